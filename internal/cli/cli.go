@@ -39,6 +39,12 @@ func Run(args []string) int {
 		return handleSettings(args[2:])
 	case "reset-password":
 		return commands.ResetPassword(args[2:])
+	case "url":
+		return commands.PrintURLs(args[2:])
+	case "diag-bundle":
+		return commands.DiagBundle(args[2:])
+	case "db-migrate":
+		return commands.DBMigrate(args[2:])
 	default:
 		// 所有其他参数传递给 serve
 		return commands.RunServe(args[1:])
@@ -58,20 +64,32 @@ func usage() string {
 	fmt.Fprintln(b, "  -b, --bind ADDR       指定绑定地址 (默认 0.0.0.0)")
 	fmt.Fprintln(b, "  -u, --user USER       初始管理员用户名")
 	fmt.Fprintln(b, "      --password PASS   初始管理员密码 (需配合 --user)")
+	fmt.Fprintln(b, "      --no-auto-admin   首次启动不自动创建 admin 账户，改为在 Web 页面创建")
+	fmt.Fprintln(b, "                        (同时指定 --user/--password 时以其创建的账户为准，本参数不起作用)")
+	fmt.Fprintln(b, "      --tls-cert FILE   HTTPS 证书文件 (需配合 --tls-key)")
+	fmt.Fprintln(b, "      --tls-key FILE    HTTPS 私钥文件")
+	fmt.Fprintln(b, "      --self-signed     未配置证书时自动生成自签名证书并启用 HTTPS")
+	fmt.Fprintln(b, "      --acme-domain D   通过 Let's Encrypt 为域名 D 自动签发证书 (需公网绑定及 80 端口)")
 	fmt.Fprintln(b, "      --debug           启用调试模式")
+	fmt.Fprintln(b, "      --quiet           不输出启动横幅 (同 --no-banner)")
+	fmt.Fprintln(b, "      --json-startup    以单行 JSON 输出启动信息 (便于 systemd/supervisord 采集)")
 	fmt.Fprintln(b, "  -h, --help            显示帮助")
 	fmt.Fprintln(b, "  -v, --version         显示版本")
 	fmt.Fprintln(b, "")
 	fmt.Fprintln(b, "辅助命令:")
 	fmt.Fprintln(b, "  doctor           诊断配置与环境")
 	fmt.Fprintln(b, "  settings         查看/设置运行模式")
-	fmt.Fprintln(b, "  reset-password   重置管理员密码")
+	fmt.Fprintln(b, "  reset-password   重置密码并解除锁定 (reset-password <用户名> [新密码]，省略密码时随机生成)")
+	fmt.Fprintln(b, "  url              输出访问地址 (--json 以 JSON 输出)")
+	fmt.Fprintln(b, "  diag-bundle      导出脱敏后的诊断包 zip，用于问题反馈 (-o 指定输出文件)")
+	fmt.Fprintln(b, "  db-migrate       查看数据库迁移状态 (status)，或回滚到指定版本 (rollback <版本号>)")
 	fmt.Fprintln(b, "")
 	fmt.Fprintln(b, "示例:")
 	fmt.Fprintln(b, "  openclawdeck                                    # 启动 Web 后台")
 	fmt.Fprintln(b, "  openclawdeck -p 9090 -b 0.0.0.0                 # 指定端口和绑定地址")
 	fmt.Fprintln(b, "  openclawdeck -u admin --password mypass123       # 启动并创建初始用户")
 	fmt.Fprintln(b, "  openclawdeck doctor                             # 诊断环境")
+	fmt.Fprintln(b, "  openclawdeck reset-password admin               # 为 admin 生成新密码")
 	return b.String()
 }
 
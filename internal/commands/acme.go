@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// acmeChallengePort HTTP-01 验证固定使用 80 端口
+const acmeChallengePort = "80"
+
+// newACMEManager 创建 Let's Encrypt 自动证书管理器，证书缓存在 cacheDir。
+// 仅允许非回环地址绑定：ACME 验证需要公网可达
+func newACMEManager(domain, email, bind, cacheDir string) (*autocert.Manager, error) {
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return nil, errors.New("未配置 ACME 域名")
+	}
+	if openclaw.IsLoopbackBind(bind) {
+		return nil, errors.New("ACME 需要公网可达的绑定地址，当前绑定为回环地址 " + bind)
+	}
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domain),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      email,
+	}, nil
+}
+
+// acmeTLSConfig 在 autocert 的 TLS 配置上包一层日志，记录证书申请进度
+func acmeTLSConfig(m *autocert.Manager) *tls.Config {
+	cfg := m.TLSConfig()
+	var (
+		mu     sync.Mutex
+		issued = map[string]bool{}
+	)
+	getCert := cfg.GetCertificate
+	cfg.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		name := hello.ServerName
+		mu.Lock()
+		first := !issued[name]
+		mu.Unlock()
+		if first && name != "" {
+			logger.Log.Info().Str("domain", name).Msg("ACME: 正在加载或申请证书")
+		}
+
+		start := time.Now()
+		cert, err := getCert(hello)
+		if err != nil {
+			logger.Log.Warn().Str("domain", name).Err(err).Msg("ACME: 获取证书失败")
+			return nil, err
+		}
+		if first && name != "" {
+			mu.Lock()
+			issued[name] = true
+			mu.Unlock()
+			ev := logger.Log.Info().Str("domain", name).Dur("elapsed", time.Since(start))
+			if cert.Leaf != nil {
+				ev = ev.Time("not_after", cert.Leaf.NotAfter)
+			}
+			ev.Msg("ACME: 证书就绪")
+		}
+		return cert, nil
+	}
+	return cfg
+}
+
+// startACMEChallengeServer 在 80 端口响应 HTTP-01 验证，其余请求重定向到 HTTPS
+func startACMEChallengeServer(m *autocert.Manager, bind string) *http.Server {
+	challenge := m.HTTPHandler(nil)
+	srv := &http.Server{
+		Addr: net.JoinHostPort(bind, acmeChallengePort),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/.well-known/acme-challenge/") {
+				logger.Log.Info().
+					Str("host", r.Host).
+					Str("remote", r.RemoteAddr).
+					Msg("ACME: 收到 HTTP-01 验证请求")
+			}
+			challenge.ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Log.Info().Str("addr", srv.Addr).Msg("ACME: HTTP-01 验证服务已启动")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Log.Warn().Err(err).Str("addr", srv.Addr).
+				Msg("ACME: HTTP-01 验证服务启动失败，只能依赖 TLS-ALPN-01（需监听 443 端口）")
+		}
+	}()
+	return srv
+}
@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/version"
+)
+
+// 启动信息输出方式
+const (
+	bannerPretty = "pretty" // 方框横幅（交互式终端默认）
+	bannerASCII  = "ascii"  // ASCII 方框横幅（控制台不支持 UTF-8 时，如旧版 Windows 代码页）
+	bannerPlain  = "plain"  // 纯文本行（非终端默认，便于日志采集）
+	bannerJSON   = "json"   // 单行 JSON（--json-startup）
+	bannerNone   = "none"   // 不输出，仅首次启动时输出生成的凭据（--quiet / --no-banner）
+)
+
+// startupInfo 启动信息，横幅与 JSON 输出共用
+type startupInfo struct {
+	Event   string   `json:"event"` // 固定为 "startup"
+	Version string   `json:"version"`
+	Bind    string   `json:"bind"`
+	Port    int      `json:"port"`
+	TLS     bool     `json:"tls"`
+	URLs    []string `json:"urls"`
+	// BindAll 绑定 0.0.0.0，局域网内任何设备均可访问
+	BindAll bool `json:"bindAll"`
+	// GeneratedAdmin 首次启动自动创建的管理员账户，需登录后立即修改
+	GeneratedAdmin *generatedAdmin `json:"generatedAdmin,omitempty"`
+	// NeedsSetup 尚无用户且未自动创建管理员（--no-auto-admin），需在 Web 页面创建
+	NeedsSetup bool   `json:"needsSetup,omitempty"`
+	Time       string `json:"time"`
+}
+
+type generatedAdmin struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// resolveBannerMode 根据命令行参数、是否为终端及终端是否支持 UTF-8 决定输出方式，flag 为空时自动选择
+func resolveBannerMode(flag string, tty, utf8 bool) string {
+	if flag != "" {
+		return flag
+	}
+	if !tty {
+		return bannerPlain
+	}
+	if !utf8 {
+		return bannerASCII
+	}
+	return bannerPretty
+}
+
+// stdoutIsTerminal 标准输出是否为交互式终端
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
+
+// newStartupInfo 汇总启动信息；绑定所有接口时列出本机所有 IPv4 地址
+func newStartupInfo(bind string, port int, useTLS bool) startupInfo {
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	info := startupInfo{
+		Event:   "startup",
+		Version: version.Version,
+		Bind:    bind,
+		Port:    port,
+		TLS:     useTLS,
+		BindAll: bind == "0.0.0.0" || bind == "",
+		Time:    time.Now().Format(time.RFC3339),
+	}
+	url := func(host string) string {
+		return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	if !info.BindAll {
+		info.URLs = []string{url(bind)}
+		return info
+	}
+	info.URLs = []string{url("localhost"), url("127.0.0.1")}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if ipnet, ok := a.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+				info.URLs = append(info.URLs, url(ipnet.IP.String()))
+			}
+		}
+	}
+	return info
+}
+
+// printStartupInfo 按 mode 输出启动信息
+func printStartupInfo(w io.Writer, mode string, info startupInfo) {
+	switch mode {
+	case bannerNone:
+		// 自动生成的密码只在这里出现一次，静默模式下也必须输出
+		if a := info.GeneratedAdmin; a != nil {
+			fmt.Fprintf(w, "first-time setup: admin account created, username=%s password=%s (change it after login)\n", a.Username, a.Password)
+		}
+	case bannerJSON:
+		json.NewEncoder(w).Encode(info)
+	case bannerPlain:
+		renderPlainStartup(w, info)
+	case bannerASCII:
+		renderBanner(w, info, true)
+	default:
+		renderBanner(w, info, false)
+	}
+}
+
+// printPublicURL 输出后台查询到的公网访问地址
+func printPublicURL(w io.Writer, mode, url string) {
+	switch mode {
+	case bannerNone:
+	case bannerJSON:
+		json.NewEncoder(w).Encode(map[string]string{"event": "public_url", "url": url})
+	case bannerPlain:
+		fmt.Fprintf(w, "public url: %s\n", url)
+	case bannerASCII:
+		fmt.Fprintf(w, "  -> Public URL: %s\n\n", url)
+	default:
+		fmt.Fprintf(w, "  ➜ 公网地址 / Public URL: %s\n\n", url)
+	}
+}
+
+// renderPlainStartup 纯文本输出，不含方框与表情字符
+func renderPlainStartup(w io.Writer, info startupInfo) {
+	fmt.Fprintf(w, "OpenClawDeck Web %s started\n", info.Version)
+	if info.BindAll {
+		fmt.Fprintln(w, "warning: bound to 0.0.0.0, accessible from any device on LAN")
+	}
+	if a := info.GeneratedAdmin; a != nil {
+		fmt.Fprintf(w, "first-time setup: admin account created, username=%s password=%s (change it after login)\n", a.Username, a.Password)
+	}
+	if info.NeedsSetup {
+		fmt.Fprintln(w, "first-time setup: no admin account yet, open the URL below to create one")
+	}
+	for _, u := range info.URLs {
+		fmt.Fprintf(w, "url: %s\n", u)
+	}
+}
+
+// bannerChars 横幅边框字符：左角、填充、右角
+type bannerChars struct {
+	top, sep, thin, bottom [3]string
+	side                   string
+}
+
+var (
+	unicodeBannerChars = bannerChars{
+		top:    [3]string{"╔", "═", "╗"},
+		sep:    [3]string{"╠", "═", "╣"},
+		thin:   [3]string{"╟", "─", "╢"},
+		bottom: [3]string{"╚", "═", "╝"},
+		side:   "║",
+	}
+	asciiBannerChars = bannerChars{
+		top:    [3]string{"+", "=", "+"},
+		sep:    [3]string{"+", "=", "+"},
+		thin:   [3]string{"+", "-", "+"},
+		bottom: [3]string{"+", "=", "+"},
+		side:   "|",
+	}
+)
+
+// renderBanner 方框横幅；ascii 为 true 时只输出 ASCII 字符（英文文案、无表情符号）
+func renderBanner(w io.Writer, info startupInfo, ascii bool) {
+	const boxWidth = 60 // 内容区域宽度（不含边框字符）
+	box := unicodeBannerChars
+	if ascii {
+		box = asciiBannerChars
+	}
+
+	// 辅助函数：生成右对齐的行
+	padLine := func(content string) string {
+		// 计算实际显示宽度（考虑中文字符占2个宽度）
+		displayWidth := 0
+		for _, r := range content {
+			if r > 127 {
+				displayWidth += 2
+			} else {
+				displayWidth++
+			}
+		}
+		padding := boxWidth - displayWidth
+		if padding < 0 {
+			padding = 0
+		}
+		return content + strings.Repeat(" ", padding)
+	}
+	// line 输出一行；ASCII 模式使用 asciiText，为空时跳过（仅中文的行）
+	line := func(text, asciiText string) {
+		if ascii {
+			if asciiText == "" {
+				return
+			}
+			text = asciiText
+		}
+		fmt.Fprintf(w, "  %s  %s%s\n", box.side, padLine(text), box.side)
+	}
+	blank := func() {
+		fmt.Fprintf(w, "  %s  %s%s\n", box.side, padLine(""), box.side)
+	}
+	// 边框与内容行等宽：内容行为 side + 2 空格 + boxWidth + side
+	border := func(c [3]string) {
+		fmt.Fprintf(w, "  %s%s%s\n", c[0], strings.Repeat(c[1], boxWidth+2), c[2])
+	}
+
+	fmt.Fprintln(w)
+	border(box.top)
+	title := fmt.Sprintf("OpenClawDeck Web %s", info.Version)
+	line(title, title)
+
+	hasWarning := false
+	// 警告1：绑定 0.0.0.0 有访问风险
+	if info.BindAll {
+		border(box.sep)
+		line("⚠️  访问风险提示 / Access Risk Warning", "[!] Access Risk Warning")
+		line("当前绑定 0.0.0.0，局域网内任何设备均可访问", "")
+		line("Binding 0.0.0.0 - accessible from any device on LAN", "Binding 0.0.0.0 - accessible from any device on LAN")
+		blank()
+		line("💡 可在 系统设置 → 账户安全 中修改绑定配置", "")
+		line("   Settings → Account Security to change binding", "    Settings -> Account Security to change binding")
+		hasWarning = true
+	}
+
+	// 首次启动：显示自动生成的凭据
+	if a := info.GeneratedAdmin; a != nil {
+		if !hasWarning {
+			border(box.sep)
+		} else {
+			border(box.thin)
+		}
+		user := fmt.Sprintf("   用户名 / Username: %s", a.Username)
+		pass := fmt.Sprintf("   密码 / Password:   %s", a.Password)
+		line("🔐 首次启动已自动创建管理员账户", "")
+		line("   First-time setup: admin account created", "[*] First-time setup: admin account created")
+		blank()
+		line(user, fmt.Sprintf("    Username: %s", a.Username))
+		line(pass, fmt.Sprintf("    Password: %s", a.Password))
+		blank()
+		line("⚠️  请登录后立即修改用户名和密码！", "")
+		line("   Please change username & password after login!", "[!] Please change username & password after login!")
+		line("   系统设置 → 账户安全 / Settings → Account Security", "    Settings -> Account Security")
+	}
+
+	// 首次启动且未自动创建管理员：提示在页面上创建
+	if info.NeedsSetup {
+		if !hasWarning {
+			border(box.sep)
+		} else {
+			border(box.thin)
+		}
+		line("🔐 尚未创建管理员账户，请打开下方地址完成初始化", "")
+		line("   No admin account yet - open a URL below to create one", "[*] No admin account yet - open a URL below to create one")
+	}
+
+	// 访问地址放在最后，方便用户复制
+	border(box.sep)
+	if info.BindAll {
+		line("可通过以下地址访问 / Access URLs:", "Access URLs:")
+		border(box.thin)
+	}
+	for _, u := range info.URLs {
+		line("➜ "+u, "-> "+u)
+	}
+	border(box.bottom)
+	fmt.Fprintln(w)
+}
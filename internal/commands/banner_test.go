@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBannerMode(t *testing.T) {
+	assert.Equal(t, bannerPretty, resolveBannerMode("", true, true))
+	assert.Equal(t, bannerASCII, resolveBannerMode("", true, false))
+	assert.Equal(t, bannerPlain, resolveBannerMode("", false, true))
+	assert.Equal(t, bannerJSON, resolveBannerMode(bannerJSON, true, false))
+	assert.Equal(t, bannerNone, resolveBannerMode(bannerNone, false, true))
+}
+
+func TestPrintStartupInfo(t *testing.T) {
+	info := newStartupInfo("192.168.1.5", 18791, true)
+	info.GeneratedAdmin = &generatedAdmin{Username: "admin", Password: "s3cret"}
+
+	var buf bytes.Buffer
+	printStartupInfo(&buf, bannerJSON, info)
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"), "JSON output is a single line")
+	var got startupInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "startup", got.Event)
+	assert.Equal(t, []string{"https://192.168.1.5:18791"}, got.URLs)
+	assert.Equal(t, []string{"http://[::1]:18791"}, newStartupInfo("::1", 18791, false).URLs)
+	assert.False(t, got.BindAll)
+	assert.Equal(t, "s3cret", got.GeneratedAdmin.Password)
+
+	buf.Reset()
+	printStartupInfo(&buf, bannerPretty, info)
+	assert.Contains(t, buf.String(), "➜ https://192.168.1.5:18791")
+	assert.Contains(t, buf.String(), "s3cret")
+
+	buf.Reset()
+	info.BindAll = true
+	printStartupInfo(&buf, bannerASCII, info)
+	for _, r := range buf.String() {
+		require.Less(t, r, rune(128), "ASCII banner contains %q", r)
+	}
+	assert.Contains(t, buf.String(), "Password: s3cret")
+	assert.Contains(t, buf.String(), "-> https://192.168.1.5:18791")
+
+	// quiet mode still reveals a freshly generated password
+	buf.Reset()
+	printStartupInfo(&buf, bannerNone, info)
+	assert.Contains(t, buf.String(), "password=s3cret")
+	buf.Reset()
+	info.GeneratedAdmin = nil
+	printStartupInfo(&buf, bannerNone, info)
+	assert.Empty(t, buf.String())
+
+	// --no-auto-admin: no credentials, point at the setup page instead
+	buf.Reset()
+	info.NeedsSetup = true
+	printStartupInfo(&buf, bannerPlain, info)
+	assert.Contains(t, buf.String(), "no admin account yet")
+	assert.NotContains(t, buf.String(), "password=")
+}
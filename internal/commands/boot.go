@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"net"
+	"net/http"
+
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/version"
+	"openclawdeck/internal/web"
+)
+
+// readyExemptPaths 未就绪时仍正常响应的 API（健康检查不访问数据库）
+var readyExemptPaths = []string{"/api/v1/health", "/api/v1/health/ready"}
+
+// healthHandler 存活探针：进程在即返回 200，附带当前是否就绪
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, map[string]interface{}{
+		"status":  "ok",
+		"ready":   web.Ready(),
+		"version": version.Version,
+	})
+}
+
+// readyHandler 就绪探针：数据库、迁移与后台服务初始化完成前返回 503
+func readyHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.Ready() {
+		w.Header().Set("Retry-After", "5")
+		web.FailErr(w, r, web.ErrNotReady)
+		return
+	}
+	web.OK(w, r, map[string]interface{}{"ready": true})
+}
+
+// bootHandler 启动期间（打开数据库、执行迁移、初始化服务）使用的处理器：
+// 健康检查与前端静态资源可用，其余 API 由 ReadyMiddleware 返回 503
+func bootHandler(static http.Handler) http.Handler {
+	router := web.NewRouter()
+	router.GET("/api/v1/health", healthHandler)
+	router.GET("/api/v1/health/ready", readyHandler)
+	router.Handle("*", "/", static.ServeHTTP)
+	return web.Chain(
+		router,
+		web.RecoveryMiddleware,
+		web.SecurityHeadersMiddleware,
+		web.RequestIDMiddleware,
+		web.ReadyMiddleware(readyExemptPaths),
+	)
+}
+
+// serveListener 在已打开的监听上提供 HTTP(S) 服务（ACME 模式下证书由 srv.TLSConfig 提供，文件参数留空）
+func serveListener(srv *http.Server, ln net.Listener, useTLS bool, certFile, keyFile string) {
+	var err error
+	if useTLS {
+		err = srv.ServeTLS(ln, certFile, keyFile)
+	} else {
+		err = srv.Serve(ln)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		logger.Log.Fatal().Err(err).Msg("服务启动失败")
+	}
+}
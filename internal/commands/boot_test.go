@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"openclawdeck/internal/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The listener is up before the database is opened: requests arriving during
+// startup get 503 (health checks excepted) until the full handler takes over.
+func TestReadyGate_ServesBeforeInit(t *testing.T) {
+	t.Cleanup(func() { web.SetReady(false) })
+	web.SetReady(false)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	static := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "index") })
+	gate := web.NewReadyGate(bootHandler(static))
+	srv := &http.Server{Handler: gate}
+	go serveListener(srv, ln, false, "", "")
+	defer srv.Close()
+
+	base := "http://" + ln.Addr().String()
+	get := func(path string) (int, string) {
+		resp, err := http.Get(base + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// startup in progress (database not open yet)
+	code, body := get("/api/v1/sessions")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "SERVICE_NOT_READY")
+	code, body = get("/api/v1/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"ready":false`)
+	code, _ = get("/api/v1/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	code, body = get("/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "index", body)
+
+	// initialization finished
+	full := web.NewRouter()
+	full.GET("/api/v1/sessions", func(w http.ResponseWriter, r *http.Request) { web.OK(w, r, []string{}) })
+	full.GET("/api/v1/health/ready", readyHandler)
+	gate.Open(web.ReadyMiddleware(readyExemptPaths)(full))
+
+	code, _ = get("/api/v1/sessions")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = get("/api/v1/health/ready")
+	assert.Equal(t, http.StatusOK, code)
+
+	// shutdown closes the gate again
+	web.SetReady(false)
+	code, _ = get("/api/v1/sessions")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
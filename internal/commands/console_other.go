@@ -0,0 +1,8 @@
+//go:build !windows
+
+package commands
+
+// ensureUTF8Console 非 Windows 终端按 UTF-8 处理
+func ensureUTF8Console() bool {
+	return true
+}
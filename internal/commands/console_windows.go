@@ -0,0 +1,25 @@
+//go:build windows
+
+package commands
+
+import "syscall"
+
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleOutputCP = kernel32.NewProc("GetConsoleOutputCP")
+	procSetConsoleOutputCP = kernel32.NewProc("SetConsoleOutputCP")
+)
+
+const codePageUTF8 = 65001
+
+// ensureUTF8Console 将控制台输出代码页切换为 UTF-8（中文 Windows 默认 936/GBK，横幅会乱码）；
+// 返回 false 表示切换失败、控制台仍非 UTF-8，应改用 ASCII 输出
+func ensureUTF8Console() bool {
+	cp, _, _ := procGetConsoleOutputCP.Call()
+	// 0 表示没有控制台（输出被重定向），按原样写 UTF-8 字节即可
+	if cp == 0 || cp == codePageUTF8 {
+		return true
+	}
+	ok, _, _ := procSetConsoleOutputCP.Call(codePageUTF8)
+	return ok != 0
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/output"
+	"openclawdeck/internal/webconfig"
+)
+
+// DBMigrate 查看数据库迁移状态或回滚迁移（无需服务运行）。
+// 迁移在服务启动时自动执行；rollback 用于降级到旧版本程序之前
+func DBMigrate(args []string) int {
+	sub := "status"
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	if sub != "status" && sub != "rollback" {
+		fmt.Fprintln(os.Stderr, "用法: openclawdeck db-migrate [status | rollback <版本号>]")
+		return 2
+	}
+	to := 0
+	if sub == "rollback" {
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "用法: openclawdeck db-migrate rollback <版本号>  (回滚到该版本，0 表示全部回滚)")
+			return 2
+		}
+		v, err := strconv.Atoi(args[1])
+		if err != nil || v < 0 {
+			fmt.Fprintf(os.Stderr, "错误: 无效的版本号 %q\n", args[1])
+			return 2
+		}
+		to = v
+	}
+
+	cfg, err := webconfig.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "配置加载失败: %v\n", err)
+		return 1
+	}
+	logger.Init(cfg.Log)
+
+	if err := database.Open(cfg.Database, false); err != nil {
+		fmt.Fprintf(os.Stderr, "数据库连接失败: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+
+	if sub == "rollback" {
+		if err := database.Rollback(to); err != nil {
+			fmt.Fprintf(os.Stderr, "回滚失败: %v\n", err)
+			return 1
+		}
+		output.Printf("数据库已回滚到版本 %d，请使用对应版本的程序启动（新版本启动时会重新执行迁移）\n", to)
+	}
+
+	states, err := database.MigrationStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取迁移状态失败: %v\n", err)
+		return 1
+	}
+	for _, st := range states {
+		status := "未执行"
+		if st.Applied {
+			status = "已执行 " + st.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		output.Printf("  %4d  %-24s %s\n", st.Version, st.Name, status)
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/output"
+	"openclawdeck/internal/webconfig"
+)
+
+// DiagBundle 导出诊断包（zip），可直接附在问题反馈中；所有密钥均已脱敏，
+// 包内文件说明见 MANIFEST.txt（diag.Contents）
+func DiagBundle(args []string) int {
+	fs := flag.NewFlagSet("diag-bundle", flag.ContinueOnError)
+	out := fs.String("o", "", "输出文件路径（默认 openclawdeck-diag-<时间>.zip）")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		output.Printf("错误: %s\n", err)
+		return 2
+	}
+	path := *out
+	if path == "" {
+		path = "openclawdeck-diag-" + time.Now().Format("20060102-150405") + ".zip"
+	}
+
+	cfg, err := webconfig.Load()
+	if err != nil {
+		output.Printf("警告: 读取 OpenClawDeck 配置失败: %s\n", err)
+	}
+	b := diag.Collect(cfg)
+	b.AddJSON("doctor.json", runDoctorChecks(openclaw.ResolveConfigPath()))
+
+	f, err := os.Create(expandPath(path))
+	if err != nil {
+		output.Printf("错误: %s\n", err)
+		return 1
+	}
+	if err := b.WriteZip(f); err != nil {
+		f.Close()
+		output.Printf("错误: 写入诊断包失败: %s\n", err)
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		output.Printf("错误: %s\n", err)
+		return 1
+	}
+	output.Printf("诊断包已导出: %s\n", path)
+	output.Println("包内密钥已脱敏，文件说明见 MANIFEST.txt，发送前仍建议检查内容。")
+	return 0
+}
@@ -1,16 +1,15 @@
 package commands
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/output"
+	"openclawdeck/internal/setup"
 )
 
 func Doctor(args []string) int {
@@ -18,6 +17,7 @@ func Doctor(args []string) int {
 	fix := fs.Bool("fix", false, "尝试安全修复")
 	fixRuntime := fs.Bool("fix-runtime", false, "修复 OpenClaw 运行时启动崩溃（networkInterfaces）")
 	rollbackRuntimeFix := fs.Bool("rollback-runtime-fix", false, "回滚 OpenClaw 运行时热修复（恢复最近备份）")
+	fixPath := fs.Bool("fix-path", false, "配合 --fix：把 npm 全局 bin 目录写入 shell 配置文件")
 	path := fs.String("path", "~/.openclaw/openclaw.json", "配置路径")
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
@@ -63,12 +63,22 @@ func Doctor(args []string) int {
 	report := runDoctorChecks(configPath)
 	output.Println(renderReport(report))
 
+	if *fix && *fixPath {
+		if changed, err := setup.AppendNpmBinToShellRC(setup.CheckNpmPrefix()); err != nil {
+			output.Printf("\nPATH 修复失败: %s\n", err)
+		} else if changed {
+			output.Println("\n已将 npm 全局 bin 目录写入 shell 配置文件，重新打开终端后生效。")
+		}
+	}
+
 	if *fix {
-		if err := runDoctorFixes(configPath, report); err != nil {
+		result, err := runDoctorFixes(configPath, report)
+		if err != nil {
 			output.Printf("\n自动修复失败: %s\n", err)
 			return 1
 		}
-		output.Println("\n自动修复完成。")
+		output.Println("\n" + renderFixResult(result))
+		output.Println("自动修复完成。")
 		report = runDoctorChecks(configPath)
 		output.Println(renderReport(report))
 	}
@@ -94,105 +104,25 @@ func runDoctorChecks(configPath string) doctorReport {
 	issues := make([]doctorIssue, 0)
 	hasErrors := false
 
-	if _, err := os.Stat(configPath); err != nil {
+	for _, ci := range openclaw.LintConfigFile(configPath) {
+		if ci.Level == openclaw.IssueError {
+			hasErrors = true
+		}
 		issues = append(issues, doctorIssue{
-			Level:      "错误",
-			Message:    "配置文件不存在: " + configPath,
-			Suggestion: "运行 `openclawdeck init` 生成最小安全配置",
+			Level:      doctorLevelLabel(ci.Level),
+			Message:    ci.Message,
+			Suggestion: ci.Suggestion,
 		})
-		hasErrors = true
-	} else {
-		data, err := os.ReadFile(configPath)
-		if err != nil {
+	}
+
+	unresolved, err := openclaw.CheckEnvRefs(configPath, openclaw.DotEnvPath())
+	if err == nil {
+		for _, v := range unresolved {
 			issues = append(issues, doctorIssue{
-				Level:      "错误",
-				Message:    "配置文件读取失败",
-				Suggestion: "检查文件权限",
+				Level:      "警告",
+				Message:    fmt.Sprintf("配置引用了未定义的环境变量 ${%s}（%s）", v.Name, strings.Join(v.Paths, ", ")),
+				Suggestion: "在 " + openclaw.DotEnvPath() + " 中添加 " + v.Name + "=...，否则网关会在首次请求时失败",
 			})
-			hasErrors = true
-		} else {
-			var raw map[string]any
-			if err := json.Unmarshal(data, &raw); err != nil {
-				issues = append(issues, doctorIssue{
-					Level:      "错误",
-					Message:    "配置 JSON 解析失败",
-					Suggestion: "修正配置格式或重新运行 `openclawdeck init`",
-				})
-				hasErrors = true
-			} else {
-				gw, _ := raw["gateway"].(map[string]any)
-				mode, _ := gw["mode"].(string)
-				bind, _ := gw["bind"].(string)
-				auth, _ := gw["auth"].(map[string]any)
-				authToken := strings.TrimSpace(asString(auth["token"]))
-				authMode := strings.TrimSpace(asString(auth["mode"]))
-				authEnabled := authMode == "token" && authToken != ""
-				if _, exists := auth["enabled"]; exists {
-					issues = append(issues, doctorIssue{
-						Level:      "警告",
-						Message:    "检测到已废弃配置项 gateway.auth.enabled",
-						Suggestion: "运行 `openclawdeck doctor --fix` 自动迁移并移除该字段",
-					})
-				}
-
-				if strings.TrimSpace(mode) == "" {
-					issues = append(issues, doctorIssue{
-						Level:      "错误",
-						Message:    "未设置 gateway.mode",
-						Suggestion: "建议设置为 `local`",
-					})
-					hasErrors = true
-				}
-				if strings.TrimSpace(bind) == "" {
-					issues = append(issues, doctorIssue{
-						Level:      "错误",
-						Message:    "未设置 gateway.bind",
-						Suggestion: "建议设置为 `loopback`",
-					})
-					hasErrors = true
-				} else if !isLoopbackBind(bind) && !authEnabled {
-					issues = append(issues, doctorIssue{
-						Level:      "警告",
-						Message:    "网关绑定非回环地址且未启用鉴权",
-						Suggestion: "设置 gateway.auth.mode=token 和 gateway.auth.token，或改为回环地址",
-					})
-				}
-				if authMode == "token" && authToken == "" {
-					issues = append(issues, doctorIssue{
-						Level:      "错误",
-						Message:    "gateway.auth.mode=token 但未设置 gateway.auth.token",
-						Suggestion: "设置 gateway.auth.token 或切换为回环地址",
-					})
-					hasErrors = true
-				}
-				if strings.TrimSpace(mode) == "remote" {
-					remote, _ := gw["remote"].(map[string]any)
-					remoteURL := strings.TrimSpace(asString(remote["url"]))
-					if remoteURL == "" {
-						issues = append(issues, doctorIssue{
-							Level:      "错误",
-							Message:    "gateway.mode=remote 但未设置 gateway.remote.url",
-							Suggestion: "设置远程网关地址（如 ws://host:18789）",
-						})
-						hasErrors = true
-					} else if !strings.HasPrefix(remoteURL, "ws://") && !strings.HasPrefix(remoteURL, "wss://") {
-						issues = append(issues, doctorIssue{
-							Level:      "警告",
-							Message:    "gateway.remote.url 不是 ws:// 或 wss:// 开头",
-							Suggestion: "请检查远程网关地址",
-						})
-					}
-					remoteToken := strings.TrimSpace(asString(remote["token"]))
-					remotePwd := strings.TrimSpace(asString(remote["password"]))
-					if remoteToken == "" && remotePwd == "" {
-						issues = append(issues, doctorIssue{
-							Level:      "警告",
-							Message:    "远程网关未配置 token/password",
-							Suggestion: "确认远程网关是否需要鉴权",
-						})
-					}
-				}
-			}
 		}
 	}
 
@@ -210,6 +140,18 @@ func runDoctorChecks(configPath string) doctorReport {
 		})
 	}
 
+	if np := setup.CheckNpmPrefix(); np != nil && !np.OnPath {
+		suggestion := "执行: " + np.FixCommand
+		if np.ShellRC != "" {
+			suggestion += "（或运行 `openclawdeck doctor --fix --fix-path` 自动写入 " + np.ShellRC + "）"
+		}
+		issues = append(issues, doctorIssue{
+			Level:      "警告",
+			Message:    "npm 全局目录 " + np.BinDir + " 不在 PATH 中，openclaw 命令可能无法找到",
+			Suggestion: suggestion,
+		})
+	}
+
 	svc := openclaw.NewService()
 	st := svc.Status()
 	if !st.Running {
@@ -229,7 +171,8 @@ func runDoctorChecks(configPath string) doctorReport {
 	return doctorReport{Issues: issues, HasErrors: hasErrors}
 }
 
-func runDoctorFixes(configPath string, report doctorReport) error {
+// runDoctorFixes 存在错误或警告时修复配置，返回实际应用的修改（无需修复时返回 nil）
+func runDoctorFixes(configPath string, report doctorReport) (*openclaw.DoctorFixResult, error) {
 	needFix := false
 	for _, issue := range report.Issues {
 		if issue.Level == "错误" || issue.Level == "警告" {
@@ -238,69 +181,38 @@ func runDoctorFixes(configPath string, report doctorReport) error {
 		}
 	}
 	if !needFix {
-		return nil
-	}
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	var raw map[string]any
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return err
+		return nil, nil
 	}
+	return openclaw.RunDoctorFixes(configPath, expandPath("~/.openclaw/env"))
+}
 
-	gw, ok := raw["gateway"].(map[string]any)
-	if !ok {
-		gw = map[string]any{}
-		raw["gateway"] = gw
-	}
-	if strings.TrimSpace(asString(gw["mode"])) == "" {
-		gw["mode"] = "local"
-	}
-	bind := strings.TrimSpace(asString(gw["bind"]))
-	if bind == "" {
-		gw["bind"] = "loopback"
-		bind = "loopback"
-	}
-	if _, ok := gw["port"]; !ok {
-		gw["port"] = 18789
+// renderFixResult 逐项列出已应用的修复
+func renderFixResult(result *openclaw.DoctorFixResult) string {
+	b := &strings.Builder{}
+	if result == nil || !result.Changed() {
+		fmt.Fprintln(b, output.Colorize("dim", "没有需要自动修复的项目。"))
+		return b.String()
 	}
-
-	auth, ok := gw["auth"].(map[string]any)
-	if !ok {
-		auth = map[string]any{}
-		gw["auth"] = auth
+	for _, c := range result.Config {
+		fmt.Fprintf(b, "%s openclaw.json: %s\n", output.Colorize("success", "[已修复]"), describeConfigChange(c))
 	}
-	delete(auth, "enabled")
-	if !isLoopbackBind(bind) {
-		if strings.TrimSpace(asString(auth["mode"])) == "" {
-			auth["mode"] = "token"
-		}
-		if strings.TrimSpace(asString(auth["token"])) == "" {
-			auth["token"] = generateToken(32)
-		}
+	if result.ConfigBackup != "" {
+		fmt.Fprintf(b, "  %s %s\n", output.Colorize("dim", "备份:"), result.ConfigBackup)
 	}
-
-	if err := backupExistingConfig(configPath); err != nil {
-		return err
+	for _, c := range result.Env {
+		fmt.Fprintf(b, "%s 环境变量: %s\n", output.Colorize("success", "[已修复]"), describeConfigChange(c))
 	}
+	return b.String()
+}
 
-	out, err := json.MarshalIndent(raw, "", "  ")
-	if err != nil {
-		return err
-	}
-	if err := os.WriteFile(configPath, append(out, '\n'), 0o600); err != nil {
-		return err
+func describeConfigChange(c openclaw.ConfigChange) string {
+	if c.Action == "remove" {
+		return "移除 " + c.Path
 	}
-
-	if changed, err := fixEnvConfig(expandPath("~/.openclaw/env")); err != nil {
-		return err
-	} else if changed {
-		output.Println("已自动修复环境变量配置。")
+	if strings.Contains(strings.ToLower(c.Path), "token") {
+		return "设置 " + c.Path
 	}
-	return nil
+	return fmt.Sprintf("设置 %s = %v", c.Path, c.New)
 }
 
 func renderReport(report doctorReport) string {
@@ -321,6 +233,18 @@ func renderReport(report doctorReport) string {
 	return b.String()
 }
 
+// doctorLevelLabel 将 openclaw.ConfigIssue 的级别转换为诊断报告中的中文标签
+func doctorLevelLabel(level string) string {
+	switch level {
+	case openclaw.IssueError:
+		return "错误"
+	case openclaw.IssueWarn:
+		return "警告"
+	default:
+		return "信息"
+	}
+}
+
 func colorDoctorLevel(level string) string {
 	switch strings.TrimSpace(level) {
 	case "错误":
@@ -334,55 +258,6 @@ func colorDoctorLevel(level string) string {
 	}
 }
 
-func isLoopbackBind(bind string) bool {
-	normalized := strings.ToLower(strings.TrimSpace(bind))
-	if normalized == "loopback" || normalized == "localhost" {
-		return true
-	}
-	if strings.HasPrefix(normalized, "127.") || normalized == "::1" {
-		return true
-	}
-	if strings.Contains(normalized, ":") {
-		host, _, found := strings.Cut(normalized, ":")
-		if !found {
-			return false
-		}
-		return host == "127.0.0.1" || host == "localhost" || host == "::1"
-	}
-	return false
-}
-
-func asString(v any) string {
-	s, _ := v.(string)
-	return s
-}
-
-func backupExistingConfig(path string) error {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return err
-	}
-	base := filepath.Base(path)
-	dirs := []string{
-		filepath.Join(expandPath("~/.openclaw"), "backups"),
-		filepath.Join(filepath.Dir(path), "backups"),
-	}
-	var lastErr error
-	for _, backupDir := range dirs {
-		if err := os.MkdirAll(backupDir, 0o755); err != nil {
-			lastErr = err
-			continue
-		}
-		backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", base, time.Now().Format("20060102-150405")))
-		if err := os.WriteFile(backupPath, data, 0o600); err == nil {
-			return nil
-		} else {
-			lastErr = err
-		}
-	}
-	return lastErr
-}
-
 func checkEnvConfig(envPath string) ([]doctorIssue, bool) {
 	issues := make([]doctorIssue, 0)
 	hasErrors := false
@@ -523,49 +398,3 @@ func requiresAPIKey(provider string) bool {
 		return false
 	}
 }
-
-func fixEnvConfig(envPath string) (bool, error) {
-	values, err := readEnvExports(envPath)
-	if err != nil {
-		return false, err
-	}
-	changed := false
-
-	platform := strings.ToLower(strings.TrimSpace(values["OPENCLAW_NOTIFY_PLATFORM"]))
-	if platform == "" {
-		if strings.TrimSpace(values["TELEGRAM_BOT_TOKEN"]) != "" || strings.TrimSpace(values["TELEGRAM_CHAT_ID"]) != "" {
-			values["OPENCLAW_NOTIFY_PLATFORM"] = "telegram"
-			changed = true
-		} else if strings.TrimSpace(values["SLACK_WEBHOOK_URL"]) != "" {
-			values["OPENCLAW_NOTIFY_PLATFORM"] = "slack"
-			changed = true
-		} else if strings.TrimSpace(values["FEISHU_WEBHOOK_URL"]) != "" {
-			values["OPENCLAW_NOTIFY_PLATFORM"] = "feishu"
-			changed = true
-		} else if strings.TrimSpace(values["OPENCLAW_NOTIFY_WEBHOOK"]) != "" {
-			values["OPENCLAW_NOTIFY_PLATFORM"] = "custom"
-			changed = true
-		}
-	}
-
-	provider := strings.ToLower(strings.TrimSpace(values["OPENCLAW_AI_PROVIDER"]))
-	if provider == "" && strings.TrimSpace(values["OPENCLAW_BASE_URL"]) != "" {
-		values["OPENCLAW_AI_PROVIDER"] = "custom"
-		changed = true
-	}
-
-	if strings.TrimSpace(values["OPENCLAW_TIMEZONE"]) == "" {
-		if tz := strings.TrimSpace(os.Getenv("TZ")); tz != "" {
-			values["OPENCLAW_TIMEZONE"] = tz
-			changed = true
-		}
-	}
-
-	if !changed {
-		return false, nil
-	}
-	if err := writeEnvExports(envPath, values); err != nil {
-		return false, err
-	}
-	return true, nil
-}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+)
+
+// PublicIPLookupSetting 设置项：启动时是否查询公网 IP（访问外部服务），默认启用；
+// 注重隐私或离线环境可关闭
+const PublicIPLookupSetting = "public_ip_lookup_enabled"
+
+func init() {
+	database.RegisterSettings(database.SettingDef{
+		Key:         PublicIPLookupSetting,
+		Type:        database.SettingBool,
+		Default:     "true",
+		Description: "look up the public IP at startup (contacts external services)",
+	})
+}
+
+// publicIPCacheTTL 公网 IP 缓存时间
+const publicIPCacheTTL = time.Hour
+
+// publicIPAPIs 依次尝试的公网 IP 查询服务
+var publicIPAPIs = []string{
+	"https://api.ipify.org",
+	"https://ifconfig.me/ip",
+	"https://icanhazip.com",
+}
+
+var publicIPCache struct {
+	mu      sync.Mutex
+	ip      string
+	fetched time.Time
+}
+
+// getPublicIP 尝试获取公网 IP 地址（带缓存）；未启用时不发出任何请求
+func getPublicIP(ctx context.Context, enabled bool) string {
+	if !enabled {
+		return ""
+	}
+	publicIPCache.mu.Lock()
+	defer publicIPCache.mu.Unlock()
+	if publicIPCache.ip != "" && time.Since(publicIPCache.fetched) < publicIPCacheTTL {
+		return publicIPCache.ip
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	for _, api := range publicIPAPIs {
+		if ip := fetchPublicIP(ctx, client, api); ip != "" {
+			publicIPCache.ip, publicIPCache.fetched = ip, time.Now()
+			return ip
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return ""
+}
+
+// fetchPublicIP 查询单个服务，响应体在返回前关闭
+func fetchPublicIP(ctx context.Context, client *http.Client, api string) string {
+	req, err := http.NewRequestWithContext(ctx, "GET", api, nil)
+	if err != nil {
+		return ""
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64))
+	ip := strings.TrimSpace(string(body))
+	// 验证是否为有效 IP
+	if net.ParseIP(ip) == nil {
+		return ""
+	}
+	return ip
+}
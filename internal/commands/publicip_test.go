@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPublicIP(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		fmt.Fprintln(w, "203.0.113.7")
+	}))
+	defer srv.Close()
+
+	origAPIs := publicIPAPIs
+	publicIPAPIs = []string{srv.URL}
+	defer func() { publicIPAPIs = origAPIs }()
+	publicIPCache.ip, publicIPCache.fetched = "", time.Time{}
+
+	// disabled: no outbound request at all
+	def, _ := database.LookupSetting(PublicIPLookupSetting)
+	assert.Equal(t, "true", def.Default)
+	assert.Empty(t, getPublicIP(context.Background(), false))
+	assert.Zero(t, hits.Load())
+
+	assert.Equal(t, "203.0.113.7", getPublicIP(context.Background(), true))
+	assert.Equal(t, "203.0.113.7", getPublicIP(context.Background(), true))
+	assert.EqualValues(t, 1, hits.Load(), "second lookup is served from cache")
+}
@@ -11,14 +11,23 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ResetPassword 直接修改数据库中的密码并解除账户锁定（无需服务运行）；
+// 未指定新密码时生成随机密码并输出
 func ResetPassword(args []string) int {
-	if len(args) < 2 {
-		fmt.Fprintln(os.Stderr, "用法: openclawdeck reset-password <用户名> <新密码>")
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "用法: openclawdeck reset-password <用户名> [新密码]")
 		return 2
 	}
 
 	username := args[0]
-	newPassword := args[1]
+	newPassword := ""
+	generated := false
+	if len(args) > 1 {
+		newPassword = args[1]
+	} else {
+		newPassword = generateRandomPassword(12)
+		generated = true
+	}
 
 	if len(newPassword) < 6 {
 		fmt.Fprintln(os.Stderr, "错误: 密码至少 6 位")
@@ -57,6 +66,11 @@ func ResetPassword(args []string) int {
 		return 1
 	}
 
-	fmt.Printf("用户 %s 的密码已重置\n", username)
+	// UpdatePassword 同时清除失败次数与锁定状态，并使该用户已签发的 token 全部失效
+	fmt.Printf("用户 %s 的密码已重置，账户锁定已解除，已登录的会话均需重新登录\n", username)
+	if generated {
+		fmt.Printf("新密码: %s\n", newPassword)
+		fmt.Println("请登录后立即修改密码")
+	}
 	return 0
 }
@@ -5,13 +5,11 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strings"
 	"syscall"
 	"time"
 
@@ -23,10 +21,10 @@ import (
 	"openclawdeck/internal/notify"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/tray"
-	"openclawdeck/internal/version"
 	"openclawdeck/internal/web"
 	"openclawdeck/internal/webconfig"
 
+	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -40,8 +38,11 @@ func RunServe(args []string) int {
 
 	// CLI arg overrides
 	portOverride := false
+	selfSigned := false
 	initUser := ""
 	initPass := ""
+	autoAdmin := true
+	bannerMode := "" // 为空时按是否为终端自动选择
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
 		case "--port", "-p":
@@ -65,9 +66,32 @@ func RunServe(args []string) int {
 				i++
 				initPass = args[i]
 			}
+		case "--tls-cert":
+			if i+1 < len(args) {
+				i++
+				cfg.Server.TLSCert = args[i]
+			}
+		case "--tls-key":
+			if i+1 < len(args) {
+				i++
+				cfg.Server.TLSKey = args[i]
+			}
+		case "--self-signed":
+			selfSigned = true
+		case "--acme-domain":
+			if i+1 < len(args) {
+				i++
+				cfg.Server.ACMEDomain = args[i]
+			}
 		case "--debug":
 			cfg.Log.Mode = "debug"
 			cfg.Log.Level = "debug"
+		case "--quiet", "--no-banner":
+			bannerMode = bannerNone
+		case "--json-startup":
+			bannerMode = bannerJSON
+		case "--no-auto-admin":
+			autoAdmin = false
 		}
 	}
 
@@ -84,12 +108,104 @@ func RunServe(args []string) int {
 	logger.Init(cfg.Log)
 	logger.Log.Info().Str("version", "0.1.0").Msg("OpenClawDeck Web 启动中...")
 
+	// Warn if binding to non-loopback
+	if cfg.Server.Bind != "127.0.0.1" && cfg.Server.Bind != "localhost" {
+		logger.Log.Warn().
+			Str("bind", cfg.Server.Bind).
+			Msg("⚠️  Web 服务绑定到非回环地址，请确保已配置防火墙规则")
+	}
+
+	// 检测端口是否被占用
+	testAddr := cfg.ListenAddr()
+	ln, err := net.Listen("tcp", testAddr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n❌ 端口 %d 已被占用，无法启动服务\n\n", cfg.Server.Port)
+		fmt.Fprintf(os.Stderr, "解决方案：\n")
+		fmt.Fprintf(os.Stderr, "  1. 关闭占用该端口的程序\n")
+		fmt.Fprintf(os.Stderr, "  2. 使用 --port 参数指定其他端口：./openclawdeck serve --port 18792\n")
+		fmt.Fprintf(os.Stderr, "     (端口号会自动保存到配置文件，下次启动无需再次指定)\n\n")
+		logger.Log.Error().Int("port", cfg.Server.Port).Err(err).Msg("端口被占用")
+		return 1
+	}
+
+	// ACME（Let's Encrypt）：显式配置域名时启用，优先于手动证书和自签名证书
+	var acmeManager *autocert.Manager
+	if cfg.Server.ACMEDomain != "" {
+		m, err := newACMEManager(cfg.Server.ACMEDomain, cfg.Server.ACMEEmail, cfg.Server.Bind, filepath.Join(webconfig.DataDir(), "acme"))
+		if err != nil {
+			logger.Log.Error().Err(err).Str("domain", cfg.Server.ACMEDomain).Msg("ACME 未启用")
+		} else {
+			acmeManager = m
+			logger.Log.Info().
+				Str("domain", cfg.Server.ACMEDomain).
+				Str("cache", filepath.Join(webconfig.DataDir(), "acme")).
+				Msg("ACME: 已启用 Let's Encrypt 自动证书，首次 HTTPS 访问时签发")
+		}
+	}
+
+	// HTTPS：证书和私钥都配置时启用；--self-signed 在未配置证书时自动生成自签名证书
+	tlsCert, tlsKey := cfg.Server.TLSCert, cfg.Server.TLSKey
+	if acmeManager != nil {
+		tlsCert, tlsKey = "", ""
+	} else if selfSigned && tlsCert == "" && tlsKey == "" {
+		certFile, keyFile, err := ensureSelfSignedCert(filepath.Join(webconfig.DataDir(), "tls"), cfg.Server.Bind)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("生成自签名证书失败，回退到 HTTP")
+		} else {
+			tlsCert, tlsKey = certFile, keyFile
+			logger.Log.Info().Str("cert", certFile).Msg("使用自签名证书（浏览器会提示证书不受信任）")
+		}
+	}
+	if (tlsCert == "") != (tlsKey == "") {
+		logger.Log.Warn().
+			Str("tls_cert", tlsCert).
+			Str("tls_key", tlsKey).
+			Msg("⚠️  TLS 证书和私钥需同时配置，当前仅配置了其一，回退到 HTTP")
+		tlsCert, tlsKey = "", ""
+	}
+	useTLS := acmeManager != nil || (tlsCert != "" && tlsKey != "")
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+
+	addr := cfg.ListenAddr()
+
+	// 先开始监听再打开数据库：初始化期间健康检查可用，其余 API 返回 503（见 bootHandler）
+	static := spaHandler()
+	gate := web.NewReadyGate(bootHandler(static))
+	srv := &http.Server{Addr: addr, Handler: gate}
+	defer srv.Close()
+	var challengeSrv *http.Server
+	if acmeManager != nil {
+		srv.TLSConfig = acmeTLSConfig(acmeManager)
+		challengeSrv = startACMEChallengeServer(acmeManager, cfg.Server.Bind)
+	}
+
+	// 信号处理（Ctrl+C / kill）
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+		logger.Log.Info().Msg("正在关闭服务...")
+		web.SetReady(false)
+		if challengeSrv != nil {
+			challengeSrv.Close()
+		}
+		srv.Close()
+	}()
+
+	go serveListener(srv, ln, useTLS, tlsCert, tlsKey)
+	logger.Log.Info().Str("addr", addr).Bool("tls", useTLS).Msg("Web 服务已启动")
+
 	// Init database
 	if err := database.Init(cfg.Database, cfg.IsDebug()); err != nil {
 		logger.Log.Fatal().Err(err).Msg("数据库初始化失败")
 		return 1
 	}
 	defer database.Close()
+	// 数据库中保存的日志级别覆盖（运行时调整并选择持久化的）
+	handlers.ApplyLogLevelSetting(database.NewSettingRepo())
 
 	// 如果指定了 --user 和 --password，创建初始管理员用户
 	if initUser != "" && initPass != "" {
@@ -147,12 +263,13 @@ func RunServe(args []string) int {
 			Str("configPath", cfg.OpenClaw.ConfigPath).
 			Bool("configPathEmpty", cfg.OpenClaw.ConfigPath == "").
 			Msg("gwToken 为空，尝试从 openclaw.json 读取 gateway auth token")
-		if t := readOpenClawGatewayToken(cfg.OpenClaw.ConfigPath); t != "" {
+		if t, src := openclaw.DiscoverGatewayToken(cfg.OpenClaw.ConfigPath); t != "" {
 			gwToken = t
-			logger.Log.Info().Int("tokenLen", len(t)).Msg("从 openclaw.json 读取到 gateway auth token")
+			logger.Log.Info().Int("tokenLen", len(t)).Str("source", src).Msg("从 openclaw.json 读取到 gateway auth token")
 		} else {
 			logger.Log.Warn().
 				Str("configPath", cfg.OpenClaw.ConfigPath).
+				Strs("searched", openclaw.TokenSearchPaths(cfg.OpenClaw.ConfigPath)).
 				Msg("未能从 openclaw.json 读取到 gateway auth token（详见上方 DEBUG 日志）")
 		}
 	}
@@ -161,7 +278,7 @@ func RunServe(args []string) int {
 	svc := openclaw.NewService()
 	svc.GatewayHost = gwHost
 	svc.GatewayPort = gwPort
-	svc.GatewayToken = gwToken
+	svc.SetGatewayToken(gwToken)
 	if svc.IsRemote() {
 		logger.Log.Info().
 			Str("host", svc.GatewayHost).
@@ -180,15 +297,42 @@ func RunServe(args []string) int {
 	gwClient.SetRestartCallback(func() error {
 		return svc.Restart()
 	})
+	// last-known-good token 持久化到 Deck 自身数据库，避免 openclaw.json 重写期间重连失败
+	{
+		settingRepo := database.NewSettingRepo()
+		gwClient.SetTokenCache(func() string {
+			v, _ := settingRepo.Get("gateway_last_good_token")
+			return v
+		}, func(token string) {
+			if err := settingRepo.Set("gateway_last_good_token", token); err != nil {
+				logger.Log.Warn().Err(err).Msg("保存 last-known-good gateway token 失败")
+			}
+		})
+	}
+	// 从数据库读取额外的 Gateway 探测端口（非标准端口部署）
+	{
+		settingRepo := database.NewSettingRepo()
+		if v, _ := settingRepo.Get(openclaw.GatewayProbePortsSetting); v != "" {
+			if ports, err := openclaw.ParseGatewayPorts(v); err == nil {
+				openclaw.SetExtraGatewayPorts(ports)
+			} else {
+				logger.Log.Warn().Err(err).Str("value", v).Msg("忽略无效的 Gateway 探测端口设置")
+			}
+		}
+	}
 	// 从数据库读取心跳自动重启设置（默认启用）
 	{
 		settingRepo := database.NewSettingRepo()
-		v, _ := settingRepo.Get("gateway_health_check_enabled")
-		// 默认启用：只有明确设为 "false" 时才禁用
-		if v != "false" {
+		if settingRepo.GetBool(openclaw.HealthCheckEnabledSetting) {
 			gwClient.SetHealthCheckEnabled(true)
 		}
 	}
+	// 从数据库读取 WebSocket 压缩设置（默认启用）
+	{
+		settingRepo := database.NewSettingRepo()
+		gwClient.SetCompression(settingRepo.GetBool(openclaw.GatewayCompressionSetting))
+	}
+	gwClient.SetKeepaliveInterval(time.Duration(cfg.OpenClaw.KeepaliveSeconds) * time.Second)
 	gwClient.Start()
 	defer gwClient.Stop()
 
@@ -209,9 +353,7 @@ func RunServe(args []string) int {
 		notifyMgr.Reload(settingRepo, gwChannels)
 	}
 	// 注入通知回调到 GWClient
-	gwClient.SetNotifyCallback(func(msg string) {
-		notifyMgr.Send(msg)
-	})
+	gwClient.SetNotifyCallback(notifyMgr.SendRisk)
 
 	// 安全引擎已禁用：当前仅审计记录，无法实际拦截 Gateway 操作
 	// secEngine := security.NewEngine(wsHub)
@@ -220,40 +362,102 @@ func RunServe(args []string) int {
 	// 	logger.Log.Error().Err(err).Msg("安全引擎初始化失败")
 	// }
 
+	// 告警规则（安全引擎禁用时的轻量替代：仅分类 + 告警，不拦截）
+	alertRules := monitor.NewAlertRuleMatcher(wsHub)
+	alertRules.SetNotifier(notifyMgr)
+	if err := alertRules.Reload(); err != nil {
+		logger.Log.Warn().Err(err).Msg("告警规则加载失败")
+	}
+
 	// GW 事件采集器（转发 Gateway 实时事件到前端 WebSocket）
 	gwCollector := monitor.NewGWCollector(gwClient, wsHub, nil, cfg.Monitor.IntervalSeconds)
+	gwCollector.SetAlertRules(alertRules)
 	go gwCollector.Start()
 	defer gwCollector.Stop()
 
+	// 网关档案 token 漂移检查（openclaw.json 中轮换 token 后提前告警/自动同步）
+	tokenDrift := monitor.NewTokenDriftWatcher(cfg.OpenClaw.ConfigPath, svc, gwClient)
+	go tokenDrift.Start()
+	defer tokenDrift.Stop()
+
+	// 数据库维护（清理过期会话、SQLite 空闲空间回收）
+	dbMaintenance := monitor.NewDBMaintenance()
+	go dbMaintenance.Start()
+	defer dbMaintenance.Stop()
+
+	// 登录失败激增告警（统计审计日志中的 login.failed）
+	loginSpike := monitor.NewLoginSpikeDetector(wsHub)
+	loginSpike.SetNotifier(notifyMgr)
+	go loginSpike.Start()
+	defer loginSpike.Stop()
+
+	// 新配对请求通知（轮询 pairing 频道，同一请求只通知一次）
+	pairingWatcher := monitor.NewPairingWatcher()
+	pairingWatcher.SetNotifier(notifyMgr)
+	go pairingWatcher.Start()
+	defer pairingWatcher.Stop()
+
+	// 技能文件完整性校验（安装时记录基线，定期比对，发现篡改时告警）
+	skillIntegrity := monitor.NewSkillIntegrity(wsHub)
+	skillIntegrity.SetNotifier(notifyMgr)
+	go skillIntegrity.Start()
+	defer skillIntegrity.Stop()
+
+	// 外发 webhook（告警 / 高风险活动以签名 JSON 转发到外部系统，如 SIEM）
+	webhookForwarder := monitor.NewWebhookForwarder()
+	go webhookForwarder.Start()
+	defer webhookForwarder.Stop()
+
+	// 维护模式（开启时暂停心跳自动重启、告警、通知与采集；按持久化设置恢复）
+	maintenance := monitor.NewMaintenance(gwClient, notifyMgr, gwCollector, wsHub)
+	maintenance.Restore()
+
+	// 空闲会话自动重置（需在设置中开启）
+	idleReset := monitor.NewIdleSessionResetter(gwClient)
+	go idleReset.Start()
+	defer idleReset.Stop()
+
 	// 本地文件扫描监控（安全引擎已禁用，传 nil；不自动启动）
 	monSvc := monitor.NewService(cfg.OpenClaw.ConfigPath, wsHub, nil, cfg.Monitor.IntervalSeconds)
 
 	// 初始化处理器
 	authHandler := handlers.NewAuthHandler(&cfg)
+	authHandler.SetNotifier(notifyMgr)
 	gatewayHandler := handlers.NewGatewayHandler(svc, wsHub)
 	gatewayHandler.SetGWClient(gwClient)
+	gatewayHandler.SetTokenDrift(tokenDrift)
 	dashboardHandler := handlers.NewDashboardHandler(svc)
+	dashboardHandler.SetGWClient(gwClient)
 	activityHandler := handlers.NewActivityHandler()
+	activityHandler.SetGWClient(gwClient)
 	monitorHandler := handlers.NewMonitorHandler()
+	monitorHandler.SetGWCollector(gwCollector)
+	monitorHandler.SetIdleResetter(idleReset)
 	// securityHandler := handlers.NewSecurityHandler(secEngine) // hidden: audit-only
+	alertRuleHandler := handlers.NewAlertRuleHandler(alertRules)
 	settingsHandler := handlers.NewSettingsHandler()
 	settingsHandler.SetGWClient(gwClient)
 	settingsHandler.SetGWService(svc)
+	settingsHandler.SetMaintenance(maintenance)
 	alertHandler := handlers.NewAlertHandler()
 	notifyHandler := handlers.NewNotifyHandler(notifyMgr)
 	notifyHandler.SetGWClient(gwClient)
 	auditHandler := handlers.NewAuditHandler()
 	configHandler := handlers.NewConfigHandler()
+	configHandler.SetService(svc)
 	backupHandler := handlers.NewBackupHandler()
 	doctorHandler := handlers.NewDoctorHandler(svc)
+	diagHandler := handlers.NewDiagHandler(&cfg, doctorHandler)
 	exportHandler := handlers.NewExportHandler()
 	userHandler := handlers.NewUserHandler()
 	skillsHandler := handlers.NewSkillsHandler()
+	skillsHandler.SetIntegrity(skillIntegrity)
 	skillTransHandler := handlers.NewSkillTranslationHandler()
 	setupWizardHandler := handlers.NewSetupWizardHandler(svc)
 	setupWizardHandler.SetGWClient(gwClient)
 	gwDiagnoseHandler := handlers.NewGatewayDiagnoseHandler(svc)
 	monConfigHandler := handlers.NewMonitorConfigHandler(monSvc, &cfg)
+	monConfigHandler.SetGWCollector(gwCollector)
 	gwLogHandler := handlers.NewGatewayLogHandler(svc, gwClient)
 	gwProfileHandler := handlers.NewGatewayProfileHandler()
 	gwProfileHandler.SetGWClient(gwClient)
@@ -261,6 +465,8 @@ func RunServe(args []string) int {
 	hostInfoHandler := handlers.NewHostInfoHandler()
 	selfUpdateHandler := handlers.NewSelfUpdateHandler()
 	serverConfigHandler := handlers.NewServerConfigHandler()
+	dbMaintenanceHandler := handlers.NewDBMaintenanceHandler()
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenance)
 	badgeHandler := handlers.NewBadgeHandler()
 
 	// 构建路由
@@ -276,6 +482,8 @@ func RunServe(args []string) int {
 	router.GET("/api/v1/auth/me", authHandler.Me)
 	router.PUT("/api/v1/auth/password", authHandler.ChangePassword)
 	router.PUT("/api/v1/auth/username", authHandler.ChangeUsername)
+	router.GET("/api/v1/auth/sessions", authHandler.ListSessions)
+	router.DELETE("/api/v1/auth/sessions/", authHandler.RevokeSession)
 
 	// 总览
 	router.GET("/api/v1/dashboard", dashboardHandler.Get)
@@ -285,7 +493,17 @@ func RunServe(args []string) int {
 	// 自更新
 	router.GET("/api/v1/self-update/info", selfUpdateHandler.Info)
 	router.GET("/api/v1/self-update/check", selfUpdateHandler.Check)
-	router.POST("/api/v1/self-update/apply", web.RequireAdmin(selfUpdateHandler.Apply))
+	router.GET("/api/v1/self-update/changelog", selfUpdateHandler.Changelog)
+	router.GET("/api/v1/self-update/openclaw-changelog", selfUpdateHandler.OpenClawChangelog)
+	router.Stream(http.MethodPost, "/api/v1/self-update/apply", web.RequireAdmin(selfUpdateHandler.Apply))
+
+	// 数据库维护
+	router.GET("/api/v1/admin/db/stats", web.RequireAdmin(dbMaintenanceHandler.Stats))
+	router.POST("/api/v1/admin/db/vacuum", web.RequireAdmin(dbMaintenanceHandler.Vacuum))
+
+	// 维护模式
+	router.GET("/api/v1/admin/maintenance", maintenanceHandler.Get)
+	router.POST("/api/v1/admin/maintenance", web.RequireAdmin(maintenanceHandler.Set))
 
 	// 服务器访问配置
 	router.GET("/api/v1/server-config", serverConfigHandler.Get)
@@ -296,14 +514,18 @@ func RunServe(args []string) int {
 	router.POST("/api/v1/gateway/start", web.RequireAdmin(gatewayHandler.Start))
 	router.POST("/api/v1/gateway/stop", web.RequireAdmin(gatewayHandler.Stop))
 	router.POST("/api/v1/gateway/restart", web.RequireAdmin(gatewayHandler.Restart))
+	router.POST("/api/v1/gateway/reload", web.RequireAdmin(gatewayHandler.Reload))
 	router.POST("/api/v1/gateway/kill", web.RequireAdmin(gatewayHandler.Kill))
 
 	// 活动流
 	router.GET("/api/v1/activities", activityHandler.List)
+	router.GET("/api/v1/activities/timeline", activityHandler.Timeline)
+	router.GET("/api/v1/activities/by-session", activityHandler.BySession)
 	router.GET("/api/v1/activities/", activityHandler.GetByID)
 
 	// 监控统计
 	router.GET("/api/v1/monitor/stats", monitorHandler.Stats)
+	router.GET("/api/v1/monitor/idle-sessions/preview", monitorHandler.IdleSessionsPreview)
 
 	// 安全策略（已禁用：仅审计，无实际拦截能力）
 	// router.GET("/api/v1/security/rules", securityHandler.ListRules)
@@ -311,11 +533,20 @@ func RunServe(args []string) int {
 	// router.PUT("/api/v1/security/rules/", securityHandler.UpdateRule)
 	// router.DELETE("/api/v1/security/rules/", securityHandler.DeleteRule)
 
+	// 告警规则（本地正则分类 + 通知，不拦截）
+	router.GET("/api/v1/alert-rules", alertRuleHandler.ListRules)
+	router.POST("/api/v1/alert-rules", web.RequireAdmin(alertRuleHandler.CreateRule))
+	router.PUT("/api/v1/alert-rules/", web.RequireAdmin(alertRuleHandler.UpdateRule))
+	router.DELETE("/api/v1/alert-rules/", web.RequireAdmin(alertRuleHandler.DeleteRule))
+
 	// 系统设置
 	router.GET("/api/v1/settings", settingsHandler.GetAll)
+	router.GET("/api/v1/settings/schema", settingsHandler.Schema)
 	router.PUT("/api/v1/settings", web.RequireAdmin(settingsHandler.Update))
 	router.GET("/api/v1/settings/gateway", settingsHandler.GetGatewayConfig)
 	router.PUT("/api/v1/settings/gateway", web.RequireAdmin(settingsHandler.UpdateGatewayConfig))
+	router.GET("/api/v1/settings/log-level", settingsHandler.GetLogLevel)
+	router.PUT("/api/v1/settings/log-level", web.RequireAdmin(settingsHandler.UpdateLogLevel))
 
 	// 告警
 	router.GET("/api/v1/alerts", alertHandler.List)
@@ -326,17 +557,27 @@ func RunServe(args []string) int {
 	router.GET("/api/v1/notify/config", notifyHandler.GetConfig)
 	router.PUT("/api/v1/notify/config", web.RequireAdmin(notifyHandler.UpdateConfig))
 	router.POST("/api/v1/notify/test", web.RequireAdmin(notifyHandler.TestSend))
+	router.POST("/api/v1/notify/test-all", web.RequireAdmin(notifyHandler.TestAll))
 
 	// 审计日志
 	router.GET("/api/v1/audit-logs", auditHandler.List)
+	router.GET("/api/v1/audit-logs/login-summary", auditHandler.LoginSummary)
 
 	// OpenClaw 配置
 	router.GET("/api/v1/config", configHandler.Get)
 	router.PUT("/api/v1/config", web.RequireAdmin(configHandler.Update))
+	router.POST("/api/v1/config/apply-change", web.RequireAdmin(configHandler.ApplyChange))
 	router.POST("/api/v1/config/generate-default", web.RequireAdmin(configHandler.GenerateDefault))
 	router.POST("/api/v1/config/set-key", web.RequireAdmin(configHandler.SetKey))
 	router.POST("/api/v1/config/unset-key", web.RequireAdmin(configHandler.UnsetKey))
 	router.GET("/api/v1/config/get-key", configHandler.GetKey)
+	router.GET("/api/v1/config/lint", configHandler.Lint)
+	router.GET("/api/v1/config/env", configHandler.GetEnv)
+	router.PUT("/api/v1/config/env", web.RequireAdmin(configHandler.UpdateEnv))
+	router.POST("/api/v1/config/migrate", web.RequireAdmin(configHandler.Migrate))
+	router.GET("/api/v1/config/export", web.RequireAdmin(configHandler.Export))
+	router.POST("/api/v1/config/import", web.RequireAdmin(configHandler.Import))
+	router.POST("/api/v1/config/compare", configHandler.Compare)
 
 	// 备份管理
 	router.GET("/api/v1/backups", backupHandler.List)
@@ -348,6 +589,8 @@ func RunServe(args []string) int {
 	// 诊断修复
 	router.GET("/api/v1/doctor", doctorHandler.Run)
 	router.POST("/api/v1/doctor/fix", doctorHandler.Fix)
+	router.GET("/api/v1/diag/bundle", web.RequireAdmin(diagHandler.Bundle))
+	router.GET("/api/v1/admin/log", web.RequireAdmin(diagHandler.LogInfo))
 
 	// 用户管理
 	router.GET("/api/v1/users", userHandler.List)
@@ -356,23 +599,32 @@ func RunServe(args []string) int {
 
 	// 技能审计
 	router.GET("/api/v1/skills", skillsHandler.List)
+	router.GET("/api/v1/skills/integrity", skillsHandler.Integrity)
+	router.POST("/api/v1/skills/integrity/verify", web.RequireAdmin(skillsHandler.VerifyIntegrity))
+	router.POST("/api/v1/skills/integrity/baseline", web.RequireAdmin(skillsHandler.Baseline))
 	router.GET("/api/v1/skills/translations", skillTransHandler.Get)
 	router.POST("/api/v1/skills/translations", skillTransHandler.Translate)
 
 	// OpenClaw 安装向导
 	router.GET("/api/v1/setup/scan", setupWizardHandler.Scan)
 	router.GET("/api/v1/setup/status", setupWizardHandler.Status)
-	router.POST("/api/v1/setup/install-deps", setupWizardHandler.InstallDeps)
-	router.POST("/api/v1/setup/install-openclaw", setupWizardHandler.InstallOpenClaw)
+	router.Stream(http.MethodPost, "/api/v1/setup/install-deps", setupWizardHandler.InstallDeps)
+	router.Stream(http.MethodPost, "/api/v1/setup/install-openclaw", setupWizardHandler.InstallOpenClaw)
 	router.POST("/api/v1/setup/configure", setupWizardHandler.Configure)
 	router.POST("/api/v1/setup/start-gateway", setupWizardHandler.StartGateway)
 	router.POST("/api/v1/setup/verify", setupWizardHandler.Verify)
-	router.POST("/api/v1/setup/auto-install", setupWizardHandler.AutoInstall)
+	router.POST("/api/v1/setup/verify-all", setupWizardHandler.VerifyAll)
+	router.Stream(http.MethodPost, "/api/v1/setup/auto-install", setupWizardHandler.AutoInstall)
+	router.Stream(http.MethodPost, "/api/v1/setup/resume", setupWizardHandler.Resume)
+	router.GET("/api/v1/setup/progress", setupWizardHandler.Progress)
+	router.GET("/api/v1/setup/history", setupWizardHandler.History)
 	router.POST("/api/v1/setup/uninstall", setupWizardHandler.Uninstall)
-	router.POST("/api/v1/setup/update-openclaw", setupWizardHandler.UpdateOpenClaw)
+	router.Stream(http.MethodPost, "/api/v1/setup/update-openclaw", setupWizardHandler.UpdateOpenClaw)
+	router.POST("/api/v1/setup/cancel-install", setupWizardHandler.CancelInstall)
 
 	// 模型/频道配置向导
 	wizardHandler := handlers.NewWizardHandler()
+	wizardHandler.SetService(svc)
 	router.POST("/api/v1/setup/test-model", wizardHandler.TestModel)
 	router.POST("/api/v1/setup/test-channel", wizardHandler.TestChannel)
 	router.POST("/api/v1/config/model-wizard", wizardHandler.SaveModel)
@@ -389,14 +641,14 @@ func RunServe(args []string) int {
 	router.POST("/api/v1/monitor/stop", monConfigHandler.StopMonitor)
 
 	// Gateway 日志
-	router.GET("/api/v1/gateway/log", gwLogHandler.GetLog)
+	router.Stream(http.MethodGet, "/api/v1/gateway/log", gwLogHandler.GetLog, handlers.IsLogFollow)
 
 	// 网关心跳健康检查
 	router.GET("/api/v1/gateway/health-check", gatewayHandler.GetHealthCheck)
 	router.PUT("/api/v1/gateway/health-check", gatewayHandler.SetHealthCheck)
 
 	// 网关诊断
-	router.POST("/api/v1/gateway/diagnose", gwDiagnoseHandler.Diagnose)
+	router.POST("/api/v1/gateway/diagnose", web.RequireAdmin(gwDiagnoseHandler.Diagnose))
 
 	// 网关配置档案（多网关管理）
 	router.GET("/api/v1/gateway/profiles", gwProfileHandler.List)
@@ -408,12 +660,15 @@ func RunServe(args []string) int {
 	// Gateway 代理 API（通过 WS JSON-RPC 连接远程 Gateway）
 	gwProxy := handlers.NewGWProxyHandler(gwClient)
 	router.GET("/api/v1/gw/status", gwProxy.Status)
+	router.GET("/api/v1/gw/rpc-stats", web.RequireAdmin(gwProxy.RPCStats))
+	router.DELETE("/api/v1/gw/rpc-stats", web.RequireAdmin(gwProxy.ResetRPCStats))
 	router.GET("/api/v1/gw/health", gwProxy.Health)
 	router.GET("/api/v1/gw/info", gwProxy.GWStatus)
 	router.GET("/api/v1/gw/sessions", gwProxy.SessionsList)
 	router.POST("/api/v1/gw/sessions/preview", gwProxy.SessionsPreview)
 	router.POST("/api/v1/gw/sessions/reset", gwProxy.SessionsReset)
 	router.POST("/api/v1/gw/sessions/delete", gwProxy.SessionsDelete)
+	router.POST("/api/v1/gw/sessions/bulk", web.RequireAdmin(gwProxy.SessionsBulk))
 	router.GET("/api/v1/gw/models", gwProxy.ModelsList)
 	router.GET("/api/v1/gw/usage/status", gwProxy.UsageStatus)
 	router.GET("/api/v1/gw/usage/cost", gwProxy.UsageCost)
@@ -421,8 +676,14 @@ func RunServe(args []string) int {
 	router.GET("/api/v1/gw/skills", gwProxy.SkillsStatus)
 	router.GET("/api/v1/gw/config", gwProxy.ConfigGet)
 	router.GET("/api/v1/gw/agents", gwProxy.AgentsList)
+	router.POST("/api/v1/gw/agents/create", web.RequireAdmin(gwProxy.AgentsCreate))
+	router.POST("/api/v1/gw/agents/update", web.RequireAdmin(gwProxy.AgentsUpdate))
+	router.POST("/api/v1/gw/agents/delete", web.RequireAdmin(gwProxy.AgentsDelete))
+	router.POST("/api/v1/gw/agents/enable", web.RequireAdmin(gwProxy.AgentsEnable))
 	router.GET("/api/v1/gw/cron", gwProxy.CronList)
 	router.GET("/api/v1/gw/cron/status", gwProxy.CronStatus)
+	router.POST("/api/v1/gw/cron/run", web.RequireAdmin(gwProxy.CronRun))
+	router.POST("/api/v1/gw/cron/toggle", web.RequireAdmin(gwProxy.CronToggle))
 	router.GET("/api/v1/gw/channels", gwProxy.ChannelsStatus)
 	router.GET("/api/v1/gw/logs/tail", gwProxy.LogsTail)
 	router.GET("/api/v1/gw/config/remote", gwProxy.ConfigGetRemote)
@@ -430,8 +691,10 @@ func RunServe(args []string) int {
 	router.POST("/api/v1/gw/config/reload", gwProxy.ConfigReload)
 	router.GET("/api/v1/gw/sessions/messages", gwProxy.SessionsPreviewMessages)
 	router.GET("/api/v1/gw/sessions/history", gwProxy.SessionsHistory)
+	router.GET("/api/v1/gw/sessions/search", gwProxy.SessionsSearch)
 	router.POST("/api/v1/gw/proxy", gwProxy.GenericProxy)
-	router.POST("/api/v1/gw/skills/install-stream", gwProxy.DepInstallStreamSSE)
+	router.Stream(http.MethodPost, "/api/v1/gw/proxy-stream", gwProxy.ProxyStream)
+	router.Stream(http.MethodPost, "/api/v1/gw/skills/install-stream", gwProxy.DepInstallStreamSSE)
 	router.POST("/api/v1/gw/skills/install-async", gwProxy.DepInstallAsync)
 	router.GET("/api/v1/gw/skills/config", gwProxy.SkillsConfigGet)
 	router.POST("/api/v1/gw/skills/configure", gwProxy.SkillsConfigure)
@@ -443,19 +706,26 @@ func RunServe(args []string) int {
 		logger.Log.Error().Err(err).Msg("内置模板种子写入失败")
 	}
 	router.GET("/api/v1/templates", templateHandler.List)
+	router.GET("/api/v1/templates/categories", templateHandler.Categories)
 	router.GET("/api/v1/templates/", templateHandler.Get)
 	router.POST("/api/v1/templates", web.RequireAdmin(templateHandler.Create))
 	router.PUT("/api/v1/templates", web.RequireAdmin(templateHandler.Update))
 	router.DELETE("/api/v1/templates/", web.RequireAdmin(templateHandler.Delete))
+	router.POST("/api/v1/templates/", web.RequireAdmin(templateHandler.Apply))
+	router.POST("/api/v1/templates/from-config", web.RequireAdmin(templateHandler.FromConfig))
 
 	// ClawHub 技能市场
 	clawHubHandler := handlers.NewClawHubHandler(gwClient)
+	clawHubHandler.SetSkillIntegrity(skillIntegrity)
 	router.GET("/api/v1/clawhub/list", clawHubHandler.List)
 	router.GET("/api/v1/clawhub/search", clawHubHandler.Search)
 	router.GET("/api/v1/clawhub/skill", clawHubHandler.SkillDetail)
+	router.GET("/api/v1/clawhub/skill/requirements", clawHubHandler.SkillRequirements)
 	router.POST("/api/v1/clawhub/install", clawHubHandler.Install)
-	router.POST("/api/v1/clawhub/install-stream", clawHubHandler.InstallStreamSSE)
+	router.Stream(http.MethodPost, "/api/v1/clawhub/install-stream", clawHubHandler.InstallStreamSSE)
+	router.POST("/api/v1/clawhub/cancel-install", clawHubHandler.CancelInstall)
 	router.POST("/api/v1/clawhub/uninstall", clawHubHandler.Uninstall)
+	router.GET("/api/v1/skills/", clawHubHandler.Readme)
 	router.POST("/api/v1/clawhub/update", clawHubHandler.Update)
 	router.GET("/api/v1/clawhub/installed", clawHubHandler.InstalledList)
 
@@ -472,24 +742,28 @@ func RunServe(args []string) int {
 
 	// 角标计数
 	router.GET("/api/v1/badges", badgeHandler.Counts)
+	router.POST("/api/v1/badges/refresh", badgeHandler.Refresh)
 
 	// WebSocket
 	router.GET("/api/v1/ws", wsHub.HandleWS(cfg.Auth.JWTSecret))
+	router.GET("/api/v1/ws/stats", web.RequireAdmin(wsHub.HandleStats))
 
-	// 健康检查
-	router.GET("/api/v1/health", func(w http.ResponseWriter, r *http.Request) {
-		web.OK(w, r, map[string]interface{}{
-			"status":  "ok",
-			"version": version.Version,
-		})
-	})
+	// 版本与功能支持（前端据此隐藏后端不支持的功能）
+	capabilitiesHandler := handlers.NewCapabilitiesHandler(&cfg)
+	router.GET("/api/v1/capabilities", capabilitiesHandler.Get)
+
+	// 健康检查：/health 为存活探针（进程在即返回 200），/health/ready 为就绪探针（未就绪返回 503）
+	router.GET("/api/v1/health", healthHandler)
+	router.GET("/api/v1/health/ready", readyHandler)
 
 	// Static files fallback (SPA)
-	router.Handle("*", "/", spaHandler())
+	router.Handle("*", "/", static)
 
 	// Middleware chain
 	// Register audit callback for auth middleware (JWT failures, forbidden access)
 	auditRepo := database.NewAuditLogRepo()
+	// 会话登记表：每次鉴权校验 token 未被注销（修改密码后旧 token 全部失效）
+	web.SetSessionCheckFunc(authHandler.CheckSession)
 	web.SetAuthAuditFunc(func(action, result, detail, ip, username string, userID uint) {
 		auditRepo.Create(&database.AuditLog{
 			UserID:   userID,
@@ -506,6 +780,7 @@ func RunServe(args []string) int {
 		"/api/v1/auth/setup",
 		"/api/v1/auth/needs-setup",
 		"/api/v1/health",
+		"/api/v1/health/ready",
 		"/api/v1/ws",
 	}
 
@@ -521,71 +796,29 @@ func RunServe(args []string) int {
 		web.SecurityHeadersMiddleware,
 		web.RequestIDMiddleware,
 		web.RequestLogMiddleware,
-		web.CORSMiddleware(cfg.Server.CORSOrigins),
+		web.ReadyMiddleware(readyExemptPaths),
+		web.StreamMiddleware(router.IsStream), // SSE 路由（router.Stream 注册）：不压缩、不限制请求体
+		web.CompressMiddleware,
+		web.CORSPolicyMiddleware(corsPolicies(cfg.Server)),
 		web.MaxBodySizeMiddleware(2<<20), // 2 MB
 		web.RateLimitMiddleware(loginLimiter, rateLimitPaths),
 		web.InputSanitizeMiddleware,
 		web.AuthMiddleware(cfg.Auth.JWTSecret, skipAuthPaths),
 	)
 
-	// Warn if binding to non-loopback
-	if cfg.Server.Bind != "127.0.0.1" && cfg.Server.Bind != "localhost" {
-		logger.Log.Warn().
-			Str("bind", cfg.Server.Bind).
-			Msg("⚠️  Web 服务绑定到非回环地址，请确保已配置防火墙规则")
-	}
-
-	// 检测端口是否被占用
-	testAddr := fmt.Sprintf("%s:%d", cfg.Server.Bind, cfg.Server.Port)
-	ln, err := net.Listen("tcp", testAddr)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ 端口 %d 已被占用，无法启动服务\n\n", cfg.Server.Port)
-		fmt.Fprintf(os.Stderr, "解决方案：\n")
-		fmt.Fprintf(os.Stderr, "  1. 关闭占用该端口的程序\n")
-		fmt.Fprintf(os.Stderr, "  2. 使用 --port 参数指定其他端口：./openclawdeck serve --port 18792\n")
-		fmt.Fprintf(os.Stderr, "     (端口号会自动保存到配置文件，下次启动无需再次指定)\n\n")
-		logger.Log.Error().Int("port", cfg.Server.Port).Err(err).Msg("端口被占用")
-		return 1
-	}
-	ln.Close()
-
-	addr := cfg.ListenAddr()
-	logger.Log.Info().Str("addr", addr).Msg("Web 服务已启动")
-
-	// 显示所有可访问的 URL
-	const boxWidth = 60 // 内容区域宽度（不含边框字符）
-
-	// 辅助函数：生成右对齐的行
-	padLine := func(content string) string {
-		// 计算实际显示宽度（考虑中文字符占2个宽度）
-		displayWidth := 0
-		for _, r := range content {
-			if r > 127 {
-				displayWidth += 2
-			} else {
-				displayWidth++
-			}
-		}
-		padding := boxWidth - displayWidth
-		if padding < 0 {
-			padding = 0
-		}
-		return content + strings.Repeat(" ", padding)
-	}
-
-	fmt.Printf("\n  ╔════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("OpenClawDeck Web %s", version.Version)))
-
-	// 检查是否需要显示安全警告
+	// 检查是否需要显示首次启动凭据
 	userRepo := database.NewUserRepo()
 	userCount, _ := userRepo.Count()
-	hasWarning := false
-	var generatedUsername, generatedPassword string
-
-	// 首次启动：自动创建默认管理员用户
-	if userCount == 0 {
-		generatedUsername = "admin"
-		generatedPassword = generateRandomPassword(8)
+	info := newStartupInfo(cfg.Server.Bind, cfg.Server.Port, useTLS)
+
+	// 首次启动：自动创建默认管理员用户；--no-auto-admin 时保持"需要初始化"状态，
+	// 由前端引导用户在首个页面创建管理员账户（auth/needs-setup 返回 true）
+	if userCount == 0 && !autoAdmin {
+		info.NeedsSetup = true
+		logger.Log.Info().Msg("首次启动：已跳过自动创建管理员（--no-auto-admin），请在 Web 页面创建管理员账户")
+	} else if userCount == 0 {
+		generatedUsername := "admin"
+		generatedPassword := generateRandomPassword(8)
 		hash, err := bcrypt.GenerateFromPassword([]byte(generatedPassword), bcrypt.DefaultCost)
 		if err == nil {
 			if err := userRepo.Create(&database.User{
@@ -596,97 +829,33 @@ func RunServe(args []string) int {
 				logger.Log.Info().Msg("首次启动：已自动创建管理员账户 admin")
 			}
 		}
+		info.GeneratedAdmin = &generatedAdmin{Username: generatedUsername, Password: generatedPassword}
 	}
 
-	// 警告1：绑定 0.0.0.0 有访问风险
-	if cfg.Server.Bind == "0.0.0.0" || cfg.Server.Bind == "" {
-		fmt.Printf("  ╠════════════════════════════════════════════════════════════╣\n")
-		fmt.Printf("  ║  %s║\n", padLine("⚠️  访问风险提示 / Access Risk Warning"))
-		fmt.Printf("  ║  %s║\n", padLine("当前绑定 0.0.0.0，局域网内任何设备均可访问"))
-		fmt.Printf("  ║  %s║\n", padLine("Binding 0.0.0.0 - accessible from any device on LAN"))
-		fmt.Printf("  ║  %s║\n", padLine(""))
-		fmt.Printf("  ║  %s║\n", padLine("💡 可在 系统设置 → 账户安全 中修改绑定配置"))
-		fmt.Printf("  ║  %s║\n", padLine("   Settings → Account Security to change binding"))
-		hasWarning = true
-	}
-
-	// 首次启动：显示自动生成的凭据
-	if generatedUsername != "" && generatedPassword != "" {
-		if !hasWarning {
-			fmt.Printf("  ╠════════════════════════════════════════════════════════════╣\n")
-		} else {
-			fmt.Printf("  ╟────────────────────────────────────────────────────────────╢\n")
-		}
-		fmt.Printf("  ║  %s║\n", padLine("🔐 首次启动已自动创建管理员账户"))
-		fmt.Printf("  ║  %s║\n", padLine("   First-time setup: admin account created"))
-		fmt.Printf("  ║  %s║\n", padLine(""))
-		fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("   用户名 / Username: %s", generatedUsername)))
-		fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("   密码 / Password:   %s", generatedPassword)))
-		fmt.Printf("  ║  %s║\n", padLine(""))
-		fmt.Printf("  ║  %s║\n", padLine("⚠️  请登录后立即修改用户名和密码！"))
-		fmt.Printf("  ║  %s║\n", padLine("   Please change username & password after login!"))
-		fmt.Printf("  ║  %s║\n", padLine("   系统设置 → 账户安全 / Settings → Account Security"))
-		hasWarning = true
-	}
-
-	// 访问地址放在最后，方便用户复制
-	if hasWarning {
-		fmt.Printf("  ╠════════════════════════════════════════════════════════════╣\n")
-	} else {
-		fmt.Printf("  ╠════════════════════════════════════════════════════════════╣\n")
-	}
-
-	if cfg.Server.Bind == "0.0.0.0" || cfg.Server.Bind == "" {
-		// 绑定所有接口，显示所有本机 IP
-		fmt.Printf("  ║  %s║\n", padLine("可通过以下地址访问 / Access URLs:"))
-		fmt.Printf("  ╟────────────────────────────────────────────────────────────╢\n")
-		fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("➜ http://localhost:%d", cfg.Server.Port)))
-		fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("➜ http://127.0.0.1:%d", cfg.Server.Port)))
-
-		// 获取所有本机 IP
-		if addrs, err := net.InterfaceAddrs(); err == nil {
-			for _, a := range addrs {
-				if ipnet, ok := a.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
-					ip := ipnet.IP.String()
-					fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("➜ http://%s:%d", ip, cfg.Server.Port)))
-				}
+	// 显示所有可访问的 URL
+	tty := stdoutIsTerminal()
+	bannerMode = resolveBannerMode(bannerMode, tty, !tty || ensureUTF8Console())
+	printStartupInfo(os.Stdout, bannerMode, info)
+
+	// 公网 IP 查询在后台进行，不阻塞启动
+	if info.BindAll && bannerMode != bannerNone {
+		go func(enabled bool) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if publicIP := getPublicIP(ctx, enabled); publicIP != "" {
+				printPublicURL(os.Stdout, bannerMode, fmt.Sprintf("%s://%s:%d", scheme, publicIP, cfg.Server.Port))
 			}
-		}
-
-		// 尝试获取公网 IP
-		if publicIP := getPublicIP(); publicIP != "" {
-			fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("➜ http://%s:%d", publicIP, cfg.Server.Port)))
-		}
-	} else {
-		// 绑定特定地址
-		fmt.Printf("  ║  %s║\n", padLine(fmt.Sprintf("➜ http://%s:%d", cfg.Server.Bind, cfg.Server.Port)))
+		}(database.NewSettingRepo().GetBool(PublicIPLookupSetting))
 	}
 
-	fmt.Printf("  ╚════════════════════════════════════════════════════════════╝\n\n")
-
-	// Graceful shutdown
-	srv := &http.Server{Addr: addr, Handler: handler}
-
-	// 信号处理（Ctrl+C / kill）
-	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		<-sigCh
-		logger.Log.Info().Msg("正在关闭服务...")
-		srv.Close()
-	}()
-
-	// 启动 HTTP 服务
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Log.Fatal().Err(err).Msg("服务启动失败")
-		}
-	}()
+	// 初始化已完成（数据库、迁移、后台服务），开始接受 API 请求
+	gate.Open(handler)
 
 	// GUI 模式：显示系统托盘图标 + 自动打开浏览器
 	if tray.HasGUI() {
-		tray.Run(addr, func() {
+		tray.Run(scheme+"://"+addr, func() {
 			logger.Log.Info().Msg("用户通过托盘菜单退出")
+			web.SetReady(false)
 			srv.Close()
 		})
 	} else {
@@ -705,138 +874,6 @@ func RunServe(args []string) int {
 	return 0
 }
 
-func serveIndex(w http.ResponseWriter, fsys fs.FS) {
-	data, err := fs.ReadFile(fsys, "index.html")
-	if err != nil {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprint(w, `<!DOCTYPE html><html><body><h1>OpenClawDeck</h1><p>index.html 未找到</p></body></html>`)
-		return
-	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	w.Write(data)
-}
-
-func spaHandler() http.HandlerFunc {
-	// 使用 embed.FS 提供静态文件，SPA 路由回退到 index.html
-	fsys, err := fs.Sub(web.StaticFS, "dist")
-	if err != nil {
-		logger.Log.Error().Err(err).Msg("无法加载前端静态资源")
-		return func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			w.WriteHeader(http.StatusOK)
-			fmt.Fprint(w, `<!DOCTYPE html><html><body><h1>OpenClawDeck</h1><p>前端资源加载失败</p></body></html>`)
-		}
-	}
-	fileServer := http.FileServer(http.FS(fsys))
-
-	return func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimPrefix(r.URL.Path, "/")
-
-		// 空路径或根路径直接返回 index.html
-		if path == "" || path == "/" {
-			serveIndex(w, fsys)
-			return
-		}
-
-		// 尝试打开文件
-		f, err := fsys.Open(path)
-		if err == nil {
-			stat, _ := f.Stat()
-			f.Close()
-			// 如果是文件（非目录），使用文件服务器
-			if stat != nil && !stat.IsDir() {
-				// 强制设置 charset=utf-8，防止 Windows 下浏览器误识别为 GBK
-				ext := strings.ToLower(filepath.Ext(path))
-				switch ext {
-				case ".html":
-					w.Header().Set("Content-Type", "text/html; charset=utf-8")
-				case ".css":
-					w.Header().Set("Content-Type", "text/css; charset=utf-8")
-				case ".js":
-					w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
-				case ".json":
-					w.Header().Set("Content-Type", "application/json; charset=utf-8")
-				}
-				fileServer.ServeHTTP(w, r)
-				return
-			}
-		}
-
-		// SPA 回退：返回 index.html
-		serveIndex(w, fsys)
-	}
-}
-
-// readOpenClawGatewayToken 从 openclaw.json 读取 gateway.auth.token
-// configPath 应指向 OpenClaw 网关配置目录（~/.openclaw）或文件（~/.openclaw/openclaw.json）
-// 注意：不要与 OpenClawDeck 数据目录（<exe>/data）混淆
-func readOpenClawGatewayToken(configPath string) string {
-	token := tryReadTokenFromPath(configPath)
-	if token != "" {
-		return token
-	}
-	// 回退：无论传入什么路径，都尝试标准路径 ~/.openclaw/openclaw.json
-	home, err := os.UserHomeDir()
-	if err != nil {
-		logger.Log.Debug().Err(err).Msg("readOpenClawGatewayToken: 无法获取用户主目录")
-		return ""
-	}
-	fallback := filepath.Join(home, ".openclaw")
-	if fallback != configPath {
-		logger.Log.Debug().Str("fallback", fallback).Msg("readOpenClawGatewayToken: 传入路径未找到 token，回退到 ~/.openclaw")
-		return tryReadTokenFromPath(fallback)
-	}
-	return ""
-}
-
-// tryReadTokenFromPath 尝试从指定路径读取 gateway.auth.token
-func tryReadTokenFromPath(configPath string) string {
-	if configPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return ""
-		}
-		configPath = filepath.Join(home, ".openclaw")
-	}
-	// configPath 可能是目录（~/.openclaw）或文件（~/.openclaw/openclaw.json）
-	info, err := os.Stat(configPath)
-	if err != nil {
-		logger.Log.Debug().Str("configPath", configPath).Err(err).Msg("tryReadTokenFromPath: 路径不存在")
-		return ""
-	}
-	if info.IsDir() {
-		configPath = filepath.Join(configPath, "openclaw.json")
-	}
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		logger.Log.Debug().Str("configPath", configPath).Err(err).Msg("tryReadTokenFromPath: 无法读取文件")
-		return ""
-	}
-	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		logger.Log.Debug().Str("configPath", configPath).Err(err).Msg("tryReadTokenFromPath: JSON 解析失败")
-		return ""
-	}
-	gw, ok := raw["gateway"].(map[string]interface{})
-	if !ok {
-		logger.Log.Debug().Str("configPath", configPath).Msg("tryReadTokenFromPath: 缺少 gateway 字段")
-		return ""
-	}
-	auth, ok := gw["auth"].(map[string]interface{})
-	if !ok {
-		logger.Log.Debug().Str("configPath", configPath).Msg("tryReadTokenFromPath: 缺少 auth 字段")
-		return ""
-	}
-	token, ok := auth["token"].(string)
-	if !ok || token == "" {
-		logger.Log.Debug().Str("configPath", configPath).Msg("tryReadTokenFromPath: token 为空")
-		return ""
-	}
-	logger.Log.Debug().Str("configPath", configPath).Int("tokenLen", len(token)).Msg("tryReadTokenFromPath: 成功读取 token")
-	return token
-}
-
 // generateRandomUsername 生成随机用户名
 func generateRandomUsername() string {
 	prefixes := []string{"user", "admin", "claw", "deck", "mgr"}
@@ -868,33 +905,19 @@ func generateRandomPassword(length int) string {
 	return string(b)
 }
 
-// getPublicIP 尝试获取公网 IP 地址
-func getPublicIP() string {
-	// 使用多个公共 API 尝试获取公网 IP
-	apis := []string{
-		"https://api.ipify.org",
-		"https://ifconfig.me/ip",
-		"https://icanhazip.com",
+// corsPolicies 全局 CORS 源（带凭据）加上按路由覆盖的策略；非法策略跳过，全局列表中的非法来源被忽略
+func corsPolicies(sc webconfig.ServerConfig) []web.CORSPolicy {
+	policies := []web.CORSPolicy{{Origins: sc.CORSOrigins, Credentials: true}}
+	if err := policies[0].Validate(); err != nil {
+		logger.Log.Warn().Err(err).Msg("⚠️  忽略非法的 CORS 来源")
 	}
-
-	client := &http.Client{Timeout: 2 * time.Second}
-
-	for _, api := range apis {
-		resp, err := client.Get(api)
-		if err != nil {
+	for _, rt := range sc.CORSRoutes {
+		p := web.CORSPolicy{PathPrefix: rt.PathPrefix, Origins: rt.Origins, Credentials: rt.Credentials}
+		if err := p.Validate(); err != nil {
+			logger.Log.Warn().Err(err).Msg("⚠️  忽略非法的 CORS 路由策略")
 			continue
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusOK {
-			body := make([]byte, 64)
-			n, _ := resp.Body.Read(body)
-			ip := strings.TrimSpace(string(body[:n]))
-			// 验证是否为有效 IP
-			if net.ParseIP(ip) != nil {
-				return ip
-			}
-		}
+		policies = append(policies, p)
 	}
-	return ""
+	return policies
 }
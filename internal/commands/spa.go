@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/web"
+)
+
+// hashedAssetPattern 匹配构建产物中带内容哈希的文件名（如 assets/index-B3x9Qk2a.js），
+// 内容变化时文件名随之变化，可长期缓存
+var hashedAssetPattern = regexp.MustCompile(`^assets/.+[-.][A-Za-z0-9_-]{8,}\.[a-z0-9]+$`)
+
+const (
+	// immutableCacheControl 带哈希的静态资源缓存一年
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	// revalidateCacheControl 其他静态文件每次用 ETag 协商
+	revalidateCacheControl = "no-cache"
+	// indexCacheControl index.html 不缓存，保证发版后立即加载新资源
+	indexCacheControl = "no-store, no-cache, must-revalidate"
+)
+
+func serveIndex(w http.ResponseWriter, fsys fs.FS) {
+	w.Header().Set("Cache-Control", indexCacheControl)
+	data, err := fs.ReadFile(fsys, "index.html")
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><h1>OpenClawDeck</h1><p>index.html 未找到</p></body></html>`)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(data)
+}
+
+func spaHandler() http.HandlerFunc {
+	// 使用 embed.FS 提供静态文件，SPA 路由回退到 index.html
+	fsys, err := fs.Sub(web.StaticFS, "dist")
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("无法加载前端静态资源")
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<!DOCTYPE html><html><body><h1>OpenClawDeck</h1><p>前端资源加载失败</p></body></html>`)
+		}
+	}
+	return newSPAHandler(fsys)
+}
+
+// newSPAHandler 提供 fsys 中的静态文件：带强 ETag（内容 SHA-256，If-None-Match 命中返回 304），
+// 哈希文件名长期缓存，其他文件每次协商；未知路径回退到不缓存的 index.html
+func newSPAHandler(fsys fs.FS) http.HandlerFunc {
+	fileServer := http.FileServer(http.FS(fsys))
+	// 嵌入文件内容不变，ETag 按路径计算一次
+	var etags sync.Map
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+
+		// 空路径或根路径直接返回 index.html
+		if p == "" || p == "index.html" {
+			serveIndex(w, fsys)
+			return
+		}
+
+		// 尝试打开文件
+		f, err := fsys.Open(p)
+		if err == nil {
+			stat, _ := f.Stat()
+			f.Close()
+			// 如果是文件（非目录），使用文件服务器
+			if stat != nil && !stat.IsDir() {
+				// 强制设置 charset=utf-8，防止 Windows 下浏览器误识别为 GBK
+				ext := strings.ToLower(filepath.Ext(p))
+				switch ext {
+				case ".html":
+					w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				case ".css":
+					w.Header().Set("Content-Type", "text/css; charset=utf-8")
+				case ".js":
+					w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+				case ".json":
+					w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				}
+				if hashedAssetPattern.MatchString(p) {
+					w.Header().Set("Cache-Control", immutableCacheControl)
+				} else {
+					w.Header().Set("Cache-Control", revalidateCacheControl)
+				}
+				// http.FileServer 根据已设置的 ETag 处理 If-None-Match / If-Match
+				if tag, ok := assetETag(&etags, fsys, p); ok {
+					w.Header().Set("ETag", tag)
+				}
+				fileServer.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		// SPA 回退：返回 index.html
+		serveIndex(w, fsys)
+	}
+}
+
+// assetETag 返回文件内容的强 ETag，结果按路径缓存
+func assetETag(cache *sync.Map, fsys fs.FS, name string) (string, bool) {
+	if v, ok := cache.Load(name); ok {
+		return v.(string), true
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	cache.Store(name, tag)
+	return tag, true
+}
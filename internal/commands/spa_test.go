@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spaTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":               {Data: []byte("<html>app</html>")},
+		"assets/index-B3x9Qk2a.js": {Data: []byte("console.log(1)")},
+		"favicon.svg":              {Data: []byte("<svg/>")},
+	}
+}
+
+func spaGet(h http.Handler, path, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSPAHandlerConditionalGet(t *testing.T) {
+	h := newSPAHandler(spaTestFS())
+
+	first := spaGet(h, "/assets/index-B3x9Qk2a.js", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, immutableCacheControl, first.Header().Get("Cache-Control"))
+	assert.Equal(t, "text/javascript; charset=utf-8", first.Header().Get("Content-Type"))
+
+	again := spaGet(h, "/assets/index-B3x9Qk2a.js", etag)
+	assert.Equal(t, http.StatusNotModified, again.Code)
+	assert.Empty(t, again.Body.String())
+	assert.Equal(t, etag, again.Header().Get("ETag"))
+
+	stale := spaGet(h, "/assets/index-B3x9Qk2a.js", `"something-else"`)
+	assert.Equal(t, http.StatusOK, stale.Code)
+	assert.Equal(t, "console.log(1)", stale.Body.String())
+
+	// unhashed files revalidate every time
+	icon := spaGet(h, "/favicon.svg", "")
+	assert.Equal(t, revalidateCacheControl, icon.Header().Get("Cache-Control"))
+	assert.Equal(t, http.StatusNotModified, spaGet(h, "/favicon.svg", icon.Header().Get("ETag")).Code)
+}
+
+func TestSPAHandlerIndexNotCached(t *testing.T) {
+	h := newSPAHandler(spaTestFS())
+	for _, path := range []string{"/", "/index.html", "/gateway/settings"} {
+		rec := spaGet(h, path, "")
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, "<html>app</html>", rec.Body.String(), path)
+		assert.Equal(t, indexCacheControl, rec.Header().Get("Cache-Control"), path)
+		assert.Empty(t, rec.Header().Get("ETag"), path)
+	}
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/output"
+	"openclawdeck/internal/webconfig"
+)
+
+// PrintURLs 读取配置并输出访问地址，供后台服务部署时查看（无需服务运行）
+func PrintURLs(args []string) int {
+	fs := flag.NewFlagSet("url", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	selfSigned := fs.Bool("self-signed", false, "服务以 --self-signed 启动（使用 https）")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		output.Printf("错误: %s\n", err)
+		return 2
+	}
+
+	cfg, err := webconfig.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "配置加载失败: %v\n", err)
+		return 1
+	}
+
+	urls := accessURLs(cfg, *selfSigned)
+	running := serverListening(cfg.Server.Bind, cfg.Server.Port)
+	if *asJSON {
+		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"urls":    urls,
+			"running": running,
+		})
+		return 0
+	}
+	for _, u := range urls {
+		fmt.Println(u)
+	}
+	if !running {
+		fmt.Fprintf(os.Stderr, "提示: 端口 %d 当前无服务监听\n", cfg.Server.Port)
+	}
+	return 0
+}
+
+// accessURLs 按配置计算访问地址：ACME 域名优先，其次为绑定地址（0.0.0.0 时列出本机所有地址）
+func accessURLs(cfg webconfig.Config, selfSigned bool) []string {
+	if cfg.Server.ACMEDomain != "" {
+		host := cfg.Server.ACMEDomain
+		if cfg.Server.Port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(cfg.Server.Port))
+		}
+		return []string{"https://" + host}
+	}
+	useTLS := selfSigned || (cfg.Server.TLSCert != "" && cfg.Server.TLSKey != "")
+	return newStartupInfo(cfg.Server.Bind, cfg.Server.Port, useTLS).URLs
+}
+
+// serverListening 绑定地址上的端口是否有服务监听
+func serverListening(bind string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(dialHost(bind), strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// dialHost 连接本机服务时使用的地址：绑定所有接口（空、0.0.0.0、::）时用回环地址，
+// 否则直接连接绑定地址（服务只在该地址上监听）
+func dialHost(bind string) string {
+	host := strings.Trim(bind, "[]")
+	switch host {
+	case "", "0.0.0.0":
+		return "127.0.0.1"
+	case "::":
+		return "::1"
+	}
+	return host
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"net"
+	"testing"
+
+	"openclawdeck/internal/webconfig"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessURLs(t *testing.T) {
+	var cfg webconfig.Config
+	cfg.Server.Bind, cfg.Server.Port = "10.0.0.2", 18791
+	assert.Equal(t, []string{"http://10.0.0.2:18791"}, accessURLs(cfg, false))
+	assert.Equal(t, []string{"https://10.0.0.2:18791"}, accessURLs(cfg, true))
+	cfg.Server.ACMEDomain = "deck.example.com"
+	assert.Equal(t, []string{"https://deck.example.com:18791"}, accessURLs(cfg, false))
+	cfg.Server.Port = 443
+	assert.Equal(t, []string{"https://deck.example.com"}, accessURLs(cfg, false))
+}
+
+func TestDialHost(t *testing.T) {
+	for bind, want := range map[string]string{
+		"":          "127.0.0.1",
+		"0.0.0.0":   "127.0.0.1",
+		"::":        "::1",
+		"[::]":      "::1",
+		"127.0.0.1": "127.0.0.1",
+		"10.0.0.2":  "10.0.0.2",
+		"[fd00::2]": "fd00::2",
+		"localhost": "localhost",
+	} {
+		assert.Equal(t, want, dialHost(bind), bind)
+	}
+}
+
+func TestServerListening_DialsBind(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.2:0")
+	if err != nil {
+		t.Skip("127.0.0.2 not available:", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+	assert.True(t, serverListening("127.0.0.2", port))
+
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	freePort := free.Addr().(*net.TCPAddr).Port
+	free.Close()
+	assert.False(t, serverListening("0.0.0.0", freePort))
+}
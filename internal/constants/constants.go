@@ -22,14 +22,17 @@ const (
 	ActionLoginFailed    = "login.failed"
 	ActionAccountLocked  = "account.locked"
 	ActionLogout         = "logout"
+	ActionSessionRevoke  = "session.revoke"
 	ActionAuthFailed     = "auth.failed"
 	ActionForbidden      = "forbidden"
 	ActionGatewayStart   = "gateway.start"
 	ActionGatewayStop    = "gateway.stop"
 	ActionGatewayRestart = "gateway.restart"
+	ActionGatewayReload  = "gateway.reload"
 	ActionKillSwitch     = "kill_switch"
 	ActionConfigUpdate   = "config.update"
 	ActionDoctorFix      = "doctor.fix"
+	ActionDiagExport     = "diag.export"
 	ActionBackupCreate   = "backup.create"
 	ActionBackupRestore  = "backup.restore"
 	ActionBackupDelete   = "backup.delete"
@@ -41,6 +44,21 @@ const (
 	ActionSelfUpdate     = "self.update"
 	ActionUserCreate     = "user.create"
 	ActionUserDelete     = "user.delete"
+	ActionCronRun        = "cron.run"
+	ActionCronToggle     = "cron.toggle"
+	ActionAgentCreate    = "agent.create"
+	ActionAgentUpdate    = "agent.update"
+	ActionAgentDelete    = "agent.delete"
+	ActionAgentToggle    = "agent.toggle"
+	ActionSessionBulk    = "session.bulk"
+	ActionDBVacuum       = "db.vacuum"
+	ActionPairingApprove = "pairing.approve"
+	ActionSkillBaseline  = "skill.baseline"
+	ActionSkillToggle    = "skill.toggle"
+	ActionMaintenance    = "maintenance"
+	ActionRuleCreate     = "rule.create"
+	ActionRuleUpdate     = "rule.update"
+	ActionRuleDelete     = "rule.delete"
 )
 
 // Activity categories
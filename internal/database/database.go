@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"openclawdeck/internal/logger"
@@ -17,7 +18,33 @@ import (
 
 var DB *gorm.DB
 
+// dbDriver / sqlitePath 记录当前连接，供维护任务（VACUUM、文件大小统计）使用
+var (
+	dbDriver   string
+	sqlitePath string
+)
+
+// Init 连接数据库并执行表结构迁移
 func Init(cfg webconfig.DatabaseConfig, debug bool) error {
+	if err := Open(cfg, debug); err != nil {
+		return err
+	}
+	if err := migrate(DB); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if err := SetSecretKeys(cfg.SecretKey, cfg.PreviousSecretKeys); err != nil {
+		return fmt.Errorf("invalid settings secret key: %w", err)
+	}
+	if err := ReencryptSecretSettings(); err != nil {
+		return fmt.Errorf("failed to encrypt secret settings: %w", err)
+	}
+
+	logger.DB.Info().Msg("数据库初始化完成")
+	return nil
+}
+
+// Open 仅连接数据库，不执行迁移（供迁移回滚等命令使用）
+func Open(cfg webconfig.DatabaseConfig, debug bool) error {
 	var dialector gorm.Dialector
 
 	switch cfg.Driver {
@@ -59,22 +86,21 @@ func Init(cfg webconfig.DatabaseConfig, debug bool) error {
 	sqlDB.SetMaxIdleConns(5)
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 
-	if err := autoMigrate(); err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	logger.DB.Info().Msg("数据库初始化完成")
+	dbDriver = cfg.Driver
+	sqlitePath = cfg.SQLitePath
 	return nil
 }
 
-func autoMigrate() error {
-	return DB.AutoMigrate(
+// autoMigrate 基线表结构：按当前模型建表/补列
+func autoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(
 		&User{},
 		&Activity{},
 		&Alert{},
 		&AuditLog{},
 		&RiskRule{},
 		&MonitorState{},
+		&MonitorSessionSnapshot{},
 		&BackupRecord{},
 		&Setting{},
 		&CredentialScan{},
@@ -83,6 +109,8 @@ func autoMigrate() error {
 		&GatewayProfile{},
 		&Template{},
 		&SkillTranslation{},
+		&InstallLog{},
+		&AuthSession{},
 	)
 }
 
@@ -96,3 +124,11 @@ func Close() error {
 	}
 	return sqlDB.Close()
 }
+
+// likeEscaper 转义 LIKE 通配符，配合 ESCAPE '\' 子句使用
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern 构造子串匹配的 LIKE 模式，用户输入中的 % 和 _ 按字面匹配
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
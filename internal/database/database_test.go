@@ -1,6 +1,7 @@
 package database
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ func setupTestDB(t *testing.T) func() {
 		&AuditLog{},
 		&RiskRule{},
 		&MonitorState{},
+		&MonitorSessionSnapshot{},
 		&BackupRecord{},
 		&Setting{},
 		&CredentialScan{},
@@ -35,6 +37,8 @@ func setupTestDB(t *testing.T) func() {
 		&GatewayProfile{},
 		&Template{},
 		&SkillTranslation{},
+		&InstallLog{},
+		&AuthSession{},
 	)
 	require.NoError(t, err, "failed to migrate test database")
 
@@ -407,6 +411,44 @@ func TestActivityRepo_List_WithFilters(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Equal(t, "high", activities[0].Risk)
+
+	// Keyword wildcards match literally
+	_, total, err = repo.List(ActivityFilter{Page: 1, PageSize: 10, Keyword: "%"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	_, total, err = repo.List(ActivityFilter{Page: 1, PageSize: 10, Keyword: "risk"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestActivityRepo_ListBySession(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewActivityRepo()
+	base := time.Now().Add(-time.Hour)
+	repo.Create(&Activity{EventID: "e1", Timestamp: base.Add(2 * time.Minute), Category: "Shell", Risk: "high", Summary: "second", Source: "test", SessionID: "s1"})
+	repo.Create(&Activity{EventID: "e2", Timestamp: base, Category: "Session", Risk: "low", Summary: "first", Source: "test", SessionID: "s1"})
+	repo.Create(&Activity{EventID: "e3", Timestamp: base.Add(time.Minute), Category: "Message", Risk: "low", Summary: "other", Source: "test", SessionID: "s2"})
+	repo.Create(&Activity{EventID: "e4", Timestamp: base.Add(3 * time.Minute), Category: "System", Risk: "low", Summary: "none", Source: "test"})
+
+	activities, total, err := repo.ListBySession("s1", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	if assert.Len(t, activities, 2) {
+		assert.Equal(t, "first", activities[0].Summary)
+		assert.Equal(t, "second", activities[1].Summary)
+	}
+
+	activities, total, err = repo.ListBySession("s1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, activities, 1)
+
+	activities, total, err = repo.ListBySession("missing", 10)
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, activities)
 }
 
 // ============== AlertRepo Tests ==============
@@ -501,6 +543,35 @@ func TestAuditLogRepo_List_WithFilters(t *testing.T) {
 	assert.Equal(t, "user", logs[0].Username)
 }
 
+func TestAuditLogRepo_GroupByIPUserAction(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAuditLogRepo()
+	repo.Create(&AuditLog{Username: "admin", Action: "login", Result: "success", IP: "10.0.0.1:5000"})
+	repo.Create(&AuditLog{Username: "admin", Action: "login.failed", Result: "failed", IP: "10.0.0.1:5001"})
+	repo.Create(&AuditLog{Username: "admin", Action: "login.failed", Result: "failed", IP: "10.0.0.1:5001"})
+	repo.Create(&AuditLog{Username: "admin", Action: "login.failed", Result: "failed", IP: "10.0.0.1"})
+	repo.Create(&AuditLog{Username: "root", Action: "login.failed", Result: "failed", IP: "10.0.0.2:6000"})
+	repo.Create(&AuditLog{Username: "admin", Action: "logout", Result: "success", IP: "10.0.0.1:5000"})
+
+	rows, err := repo.GroupByIPUserAction([]string{"login", "login.failed"}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, rows, 3)
+	for _, row := range rows {
+		assert.False(t, row.FirstSeen.IsZero(), "first seen parsed for %+v", row)
+		assert.False(t, row.LastSeen.Before(row.FirstSeen))
+		assert.NotContains(t, row.IP, ":", "rows are grouped by host")
+		if row.IP == "10.0.0.1" && row.Action == "login.failed" {
+			assert.Equal(t, int64(3), row.Count, "source ports are merged")
+		}
+	}
+
+	rows, err = repo.GroupByIPUserAction([]string{"login"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
 // ============== BackupRepo Tests ==============
 
 func TestBackupRepo_Create(t *testing.T) {
@@ -715,3 +786,288 @@ func TestRiskRuleRepo_CountEnabled(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), count) // Only r1 is enabled
 }
+
+// ============== TemplateRepo Tests ==============
+
+func TestTemplateRepo_List_Filters(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTemplateRepo()
+	repo.Create(&Template{TemplateID: "soul-a", TargetFile: "SOUL.md", Category: "persona", Tags: "soul,friendly", I18n: `{"en":{"name":"Friendly"}}`})
+	repo.Create(&Template{TemplateID: "soul-b", TargetFile: "SOUL.md", Category: "persona", Tags: "soul,coder", I18n: `{"en":{"name":"Coder"}}`})
+	repo.Create(&Template{TemplateID: "cfg-min", TargetFile: "openclaw.json", Category: "minimal", Tags: "config", I18n: `{"en":{"name":"Minimal"}}`})
+
+	all, err := repo.List(TemplateFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	byCat, err := repo.List(TemplateFilter{Category: "persona"})
+	assert.NoError(t, err)
+	assert.Len(t, byCat, 2)
+
+	byQuery, err := repo.List(TemplateFilter{Query: "CODER"})
+	assert.NoError(t, err)
+	require.Len(t, byQuery, 1)
+	assert.Equal(t, "soul-b", byQuery[0].TemplateID)
+
+	combined, err := repo.List(TemplateFilter{TargetFile: "openclaw.json", Query: "soul"})
+	assert.NoError(t, err)
+	assert.Empty(t, combined)
+
+	// LIKE wildcards in the query match literally
+	for _, q := range []string{"%", "_", "soul_"} {
+		got, err := repo.List(TemplateFilter{Query: q})
+		assert.NoError(t, err)
+		assert.Empty(t, got, q)
+	}
+}
+
+func TestContainsPattern(t *testing.T) {
+	assert.Equal(t, "%abc%", containsPattern("abc"))
+	assert.Equal(t, `%50\% off\_now\\%`, containsPattern(`50% off_now\`))
+}
+
+func TestTemplateRepo_Categories(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTemplateRepo()
+	repo.Create(&Template{TemplateID: "t1", TargetFile: "SOUL.md", Category: "persona", I18n: "{}"})
+	repo.Create(&Template{TemplateID: "t2", TargetFile: "SOUL.md", Category: "persona", I18n: "{}"})
+	repo.Create(&Template{TemplateID: "t3", TargetFile: "openclaw.json", Category: "minimal", I18n: "{}"})
+
+	cats, err := repo.Categories("")
+	assert.NoError(t, err)
+	assert.Equal(t, []TemplateCategory{{Category: "minimal", Count: 1}, {Category: "persona", Count: 2}}, cats)
+
+	cats, err = repo.Categories("SOUL.md")
+	assert.NoError(t, err)
+	assert.Equal(t, []TemplateCategory{{Category: "persona", Count: 2}}, cats)
+}
+
+func TestTemplateRepo_BackfillCategory(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTemplateRepo()
+	repo.Create(&Template{TemplateID: "t1", TargetFile: "SOUL.md", I18n: "{}"})
+	repo.Create(&Template{TemplateID: "t2", TargetFile: "SOUL.md", Category: "persona", I18n: "{}"})
+
+	n, err := repo.BackfillCategory()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	tpl, err := repo.GetByTemplateID("t1")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTemplateCategory, tpl.Category)
+}
+
+// ============== InstallLogRepo Tests ==============
+
+func TestInstallLogRepo_ListAndPrune(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewInstallLogRepo()
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.Create(&InstallLog{
+			Action:     "update",
+			Success:    i%2 == 0,
+			Version:    "1." + string(rune('0'+i)),
+			StartedAt:  base.Add(time.Duration(i) * time.Minute),
+			FinishedAt: base.Add(time.Duration(i)*time.Minute + time.Second),
+		}))
+	}
+
+	logs, err := repo.List(2)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "1.4", logs[0].Version)
+	assert.Equal(t, "1.3", logs[1].Version)
+
+	require.NoError(t, repo.Prune(3))
+	logs, err = repo.List(10)
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	assert.Equal(t, "1.2", logs[2].Version)
+}
+
+// ============== Migration Tests ==============
+
+func TestMigrate_FreshInstallMarksAllApplied(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, migrate(db))
+	assert.True(t, db.Migrator().HasColumn(&User{}, "TokenEpoch"))
+
+	states, err := migrationStatus(db, migrations)
+	require.NoError(t, err)
+	require.Len(t, states, len(migrations))
+	for _, st := range states {
+		assert.True(t, st.Applied, "migration %d", st.Version)
+	}
+
+	// second start is a no-op
+	require.NoError(t, migrate(db))
+}
+
+func TestRunMigrations_OrderAndRollback(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, DB.AutoMigrate(&SchemaMigration{}))
+
+	var calls []string
+	step := func(name string) func(tx *gorm.DB) error {
+		return func(tx *gorm.DB) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	list := []Migration{
+		{Version: 1, Name: "one", Up: step("up1"), Down: step("down1")},
+		{Version: 2, Name: "two", Up: step("up2"), Down: step("down2")},
+		{Version: 3, Name: "three", Up: step("up3")},
+	}
+
+	require.NoError(t, runMigrations(DB, list))
+	require.NoError(t, runMigrations(DB, list))
+	assert.Equal(t, []string{"up1", "up2", "up3"}, calls)
+
+	// migration 3 has no Down: rollback stops before touching anything
+	calls = nil
+	assert.Error(t, rollbackMigrations(DB, list, 0))
+	assert.Empty(t, calls)
+
+	require.NoError(t, rollbackMigrations(DB, list[:2], 0))
+	assert.Equal(t, []string{"down2", "down1"}, calls)
+	states, err := migrationStatus(DB, list)
+	require.NoError(t, err)
+	assert.False(t, states[0].Applied)
+	assert.False(t, states[1].Applied)
+	assert.True(t, states[2].Applied)
+
+	// a failing Up is not recorded
+	calls = nil
+	failing := append(list[:2:2], Migration{Version: 4, Name: "bad", Up: func(*gorm.DB) error { return assert.AnError }})
+	assert.Error(t, runMigrations(DB, failing))
+	assert.Equal(t, []string{"up1", "up2"}, calls)
+	states, err = migrationStatus(DB, failing)
+	require.NoError(t, err)
+	assert.False(t, states[2].Applied)
+
+	assert.Error(t, runMigrations(DB, []Migration{list[1], list[0]}), "unordered list is rejected")
+}
+
+// ============== Maintenance Tests ==============
+
+func TestVacuum_ReclaimsDeletedRows(t *testing.T) {
+	path := t.TempDir() + "/deck.db"
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&AuditLog{}))
+	DB, dbDriver, sqlitePath = db, "sqlite", path
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+		DB, dbDriver, sqlitePath = nil, "", ""
+	}()
+
+	detail := string(make([]byte, 4096))
+	for i := 0; i < 200; i++ {
+		require.NoError(t, db.Create(&AuditLog{Action: "test", Detail: detail}).Error)
+	}
+	require.NoError(t, db.Where("1 = 1").Delete(&AuditLog{}).Error)
+
+	st, err := Stats()
+	require.NoError(t, err)
+	assert.Greater(t, st.ReclaimableBytes, int64(0))
+
+	res, err := Vacuum()
+	require.NoError(t, err)
+	assert.Equal(t, "full", res.Mode)
+	assert.Less(t, res.AfterBytes, res.BeforeBytes)
+
+	dbDriver = "postgres"
+	_, err = Vacuum()
+	assert.ErrorIs(t, err, ErrVacuumUnsupported)
+}
+
+// ============== Secret Settings Tests ==============
+
+func TestSettingRepo_EncryptsSecretSettings(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, SetSecretKeys("master-1", nil))
+	defer SetSecretKeys("", nil)
+
+	repo := NewSettingRepo()
+	require.NoError(t, repo.Set("notify_telegram_token", "123:secret"))
+	require.NoError(t, repo.Set("language", "en"))
+
+	raw := func(key string) string {
+		var s Setting
+		require.NoError(t, DB.Where("`key` = ?", key).First(&s).Error)
+		return s.Value
+	}
+	assert.True(t, strings.HasPrefix(raw("notify_telegram_token"), encryptedPrefix))
+	assert.NotContains(t, raw("notify_telegram_token"), "123:secret")
+	assert.Equal(t, "en", raw("language"), "non-secret settings stay plaintext")
+
+	v, err := repo.Get("notify_telegram_token")
+	require.NoError(t, err)
+	assert.Equal(t, "123:secret", v)
+	all, err := repo.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, "123:secret", all["notify_telegram_token"])
+}
+
+func TestReencryptSecretSettings_MigratesAndRotates(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	defer SetSecretKeys("", nil)
+
+	// plaintext secret written before encryption existed
+	require.NoError(t, DB.Create(&Setting{Key: "gateway_token", Value: "plain-token"}).Error)
+	require.NoError(t, SetSecretKeys("old-master", nil))
+	require.NoError(t, ReencryptSecretSettings())
+	repo := NewSettingRepo()
+	v, err := repo.Get("gateway_token")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-token", v)
+
+	// rotate: new primary, old kept for decryption
+	require.NoError(t, SetSecretKeys("new-master", []string{"old-master"}))
+	require.NoError(t, ReencryptSecretSettings())
+
+	// old key no longer needed
+	require.NoError(t, SetSecretKeys("new-master", nil))
+	v, err = repo.Get("gateway_token")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-token", v)
+
+	// without the right key the value is unreadable
+	require.NoError(t, SetSecretKeys("other-master", nil))
+	_, err = repo.Get("gateway_token")
+	assert.ErrorIs(t, err, ErrSecretKeyUnavailable)
+}
+
+func TestEncryptSetting_BoundToKeyName(t *testing.T) {
+	require.NoError(t, SetSecretKeys("master-1", nil))
+	defer SetSecretKeys("", nil)
+
+	enc, err := encryptSetting("notify_slack_token", "xoxb-1")
+	require.NoError(t, err)
+	plain, err := decryptSetting("notify_slack_token", enc)
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-1", plain)
+
+	_, err = decryptSetting("notify_discord_token", enc)
+	assert.Error(t, err, "ciphertext moved to another setting must not decrypt")
+}
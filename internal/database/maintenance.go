@@ -0,0 +1,120 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrVacuumUnsupported 非 SQLite 数据库（PostgreSQL 由 autovacuum 负责回收空间）
+var ErrVacuumUnsupported = errors.New("vacuum is only supported for sqlite")
+
+// ErrVacuumBusy 已有 VACUUM 在执行，或近期写入过多
+var ErrVacuumBusy = errors.New("database is busy")
+
+// SQLite auto_vacuum 模式
+const sqliteAutoVacuumIncremental = 2
+
+// vacuumMu 保证同一时间只有一个 VACUUM
+var vacuumMu sync.Mutex
+
+// DBStats 数据库文件与可回收空间统计
+type DBStats struct {
+	Driver           string `json:"driver"`
+	Path             string `json:"path,omitempty"`
+	SizeBytes        int64  `json:"size_bytes"`
+	ReclaimableBytes int64  `json:"reclaimable_bytes"`
+	AutoVacuum       string `json:"auto_vacuum,omitempty"` // none / full / incremental
+}
+
+// VacuumResult 一次 VACUUM 的结果
+type VacuumResult struct {
+	Mode        string    `json:"mode"` // full / incremental
+	BeforeBytes int64     `json:"before_bytes"`
+	AfterBytes  int64     `json:"after_bytes"`
+	FreedBytes  int64     `json:"freed_bytes"`
+	DurationMs  int64     `json:"duration_ms"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// Stats 返回数据库文件大小及空闲页（已删除数据占用、VACUUM 可回收）大小
+func Stats() (DBStats, error) {
+	st := DBStats{Driver: dbDriver}
+	if dbDriver != "sqlite" {
+		return st, ErrVacuumUnsupported
+	}
+	st.Path = sqlitePath
+	st.SizeBytes = sqliteFileSize(sqlitePath)
+
+	var pageSize, freePages, autoVacuum int64
+	DB.Raw("PRAGMA page_size").Scan(&pageSize)
+	DB.Raw("PRAGMA freelist_count").Scan(&freePages)
+	if err := DB.Raw("PRAGMA auto_vacuum").Scan(&autoVacuum).Error; err != nil {
+		return st, err
+	}
+	st.ReclaimableBytes = pageSize * freePages
+	st.AutoVacuum = [...]string{"none", "full", "incremental"}[autoVacuum%3]
+	return st, nil
+}
+
+// RecentWrites 统计 since 之后写入活动流、审计日志和连接日志的行数，
+// 用于判断当前是否处于高频写入期
+func RecentWrites(since time.Time) int64 {
+	var total int64
+	for _, model := range []interface{}{&Activity{}, &AuditLog{}, &ConnectionLog{}} {
+		var n int64
+		DB.Model(model).Where("created_at > ?", since).Count(&n)
+		total += n
+	}
+	return total
+}
+
+// Vacuum 回收 SQLite 空闲页：auto_vacuum=incremental 时执行 PRAGMA incremental_vacuum，
+// 否则执行完整 VACUUM（重写整个文件，期间阻塞写入）。已有 VACUUM 在执行时返回 ErrVacuumBusy
+func Vacuum() (VacuumResult, error) {
+	if dbDriver != "sqlite" {
+		return VacuumResult{}, ErrVacuumUnsupported
+	}
+	if !vacuumMu.TryLock() {
+		return VacuumResult{}, ErrVacuumBusy
+	}
+	defer vacuumMu.Unlock()
+
+	start := time.Now()
+	res := VacuumResult{Mode: "full", BeforeBytes: sqliteFileSize(sqlitePath)}
+
+	var autoVacuum int
+	DB.Raw("PRAGMA auto_vacuum").Scan(&autoVacuum)
+	err := DB.Connection(func(conn *gorm.DB) error {
+		if autoVacuum == sqliteAutoVacuumIncremental {
+			res.Mode = "incremental"
+			return conn.Exec("PRAGMA incremental_vacuum").Error
+		}
+		return conn.Exec("VACUUM").Error
+	})
+	if err != nil {
+		return res, err
+	}
+	// WAL 模式下回收的页在检查点后才体现到主文件
+	DB.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+
+	res.AfterBytes = sqliteFileSize(sqlitePath)
+	res.FreedBytes = res.BeforeBytes - res.AfterBytes
+	res.DurationMs = time.Since(start).Milliseconds()
+	res.FinishedAt = time.Now().UTC()
+	return res, nil
+}
+
+// sqliteFileSize 数据库文件及 WAL 文件的总大小
+func sqliteFileSize(path string) int64 {
+	var size int64
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"openclawdeck/internal/logger"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration 已执行的迁移记录（schema_migrations 表）
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"not null" json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// Migration 一次有序的结构/数据变更。Up 与 Down 在同一事务中执行并记录版本；
+// Down 为 nil 表示不可回滚
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// MigrationState 迁移状态（供 CLI 展示）
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// migrations 按版本号递增登记，只能追加，不能修改已发布的条目。
+// 新字段/新表由 autoMigrate 基线创建即可；列重命名、删除、数据回填等
+// AutoMigrate 处理不了的变更在这里登记。Up 需幂等（已有安装可能已由基线建好）
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "user_token_epoch",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&User{}, "TokenEpoch") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&User{}, "TokenEpoch")
+		},
+		Down: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn(&User{}, "TokenEpoch") {
+				return nil
+			}
+			return tx.Migrator().DropColumn(&User{}, "TokenEpoch")
+		},
+	},
+	{
+		Version: 2,
+		Name:    "auth_sessions",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&AuthSession{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&AuthSession{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&AuthSession{})
+		},
+	},
+}
+
+// migrate 初始化表结构：全新安装直接用 autoMigrate 建到最新，并把所有迁移标记为已执行；
+// 已有安装先按顺序执行未执行的迁移（重命名等需在 AutoMigrate 补列之前完成），再跑 autoMigrate 补齐
+func migrate(db *gorm.DB) error {
+	fresh := !db.Migrator().HasTable(&User{})
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return err
+	}
+	if fresh {
+		if err := autoMigrate(db); err != nil {
+			return err
+		}
+		return markApplied(db, migrations)
+	}
+	if err := runMigrations(db, migrations); err != nil {
+		return err
+	}
+	return autoMigrate(db)
+}
+
+// runMigrations 按版本顺序执行尚未执行的迁移，每个迁移一个事务
+func runMigrations(db *gorm.DB, list []Migration) error {
+	if err := validateMigrations(list); err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range list {
+		if applied[m.Version] {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		logger.DB.Info().Int("version", m.Version).Str("name", m.Name).Msg("数据库迁移已执行")
+	}
+	return nil
+}
+
+// markApplied 记录迁移为已执行但不运行（全新安装时基线已包含其变更）
+func markApplied(db *gorm.DB, list []Migration) error {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, m := range list {
+		if applied[m.Version] {
+			continue
+		}
+		if err := db.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: now}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback 将数据库回滚到指定版本：按版本倒序执行所有大于 to 的已执行迁移的 Down。
+// 用于降级到旧版本程序之前；遇到不可回滚的迁移时停止
+func Rollback(to int) error {
+	return rollbackMigrations(DB, migrations, to)
+}
+
+func rollbackMigrations(db *gorm.DB, list []Migration, to int) error {
+	if err := validateMigrations(list); err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		m := list[i]
+		if m.Version <= to || !applied[m.Version] {
+			continue
+		}
+		if m.Down == nil {
+			return fmt.Errorf("migration %d (%s) cannot be rolled back", m.Version, m.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("rollback of migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		logger.DB.Info().Int("version", m.Version).Str("name", m.Name).Msg("数据库迁移已回滚")
+	}
+	return nil
+}
+
+// MigrationStatus 列出所有已登记的迁移及其执行状态
+func MigrationStatus() ([]MigrationState, error) {
+	return migrationStatus(DB, migrations)
+}
+
+func migrationStatus(db *gorm.DB, list []Migration) ([]MigrationState, error) {
+	var rows []SchemaMigration
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		if err := db.Order("version").Find(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+	byVersion := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		byVersion[row.Version] = row
+	}
+	states := make([]MigrationState, 0, len(list))
+	for _, m := range list {
+		st := MigrationState{Version: m.Version, Name: m.Name}
+		if row, ok := byVersion[m.Version]; ok {
+			at := row.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		states = append(states, st)
+	}
+	return states, nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int]bool, error) {
+	var versions []int
+	if err := db.Model(&SchemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
+	}
+	return applied, nil
+}
+
+// validateMigrations 版本号必须为正且严格递增
+func validateMigrations(list []Migration) error {
+	if !sort.SliceIsSorted(list, func(i, j int) bool { return list[i].Version < list[j].Version }) {
+		return fmt.Errorf("migrations are not ordered by version")
+	}
+	for i, m := range list {
+		if m.Version <= 0 || m.Up == nil {
+			return fmt.Errorf("invalid migration %d (%s)", m.Version, m.Name)
+		}
+		if i > 0 && list[i-1].Version == m.Version {
+			return fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+	}
+	return nil
+}
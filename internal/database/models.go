@@ -11,6 +11,7 @@ type User struct {
 	Role           string     `gorm:"not null;default:admin" json:"role"`
 	LockedUntil    *time.Time `json:"locked_until,omitempty"`
 	FailedAttempts int        `gorm:"default:0" json:"-"`
+	TokenEpoch     int        `gorm:"default:0" json:"-"` // 修改密码时递增，使之前签发的所有 token 失效
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 }
@@ -25,7 +26,7 @@ type Activity struct {
 	Detail      string    `gorm:"type:text" json:"detail,omitempty"`
 	Source      string    `json:"source"`
 	ActionTaken string    `json:"action_taken"`
-	SessionID   string    `json:"session_id"`
+	SessionID   string    `gorm:"index" json:"session_id"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -73,6 +74,17 @@ type MonitorState struct {
 	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
+// MonitorSessionSnapshot 持久化 GW 采集器的会话 token 快照，重启后用于继续增量检测
+type MonitorSessionSnapshot struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SessionKey   string    `gorm:"uniqueIndex;size:255" json:"session_key"`
+	InputTokens  int64     `json:"input_tokens"`
+	OutputTokens int64     `json:"output_tokens"`
+	TotalTokens  int64     `json:"total_tokens"`
+	LastActiveMs int64     `json:"last_active_ms"` // Gateway 会话 updatedAt（毫秒）
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
 type BackupRecord struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Filename  string    `json:"filename"`
@@ -146,3 +158,31 @@ type Template struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
+
+// InstallLog 安装/更新/卸载的执行记录，Log 只保留截断后的尾部输出
+type InstallLog struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Action      string    `gorm:"index" json:"action"` // install-deps / install-openclaw / auto-install / update / uninstall
+	Success     bool      `json:"success"`
+	FromVersion string    `json:"from_version"`
+	Version     string    `json:"version"` // 结束时的 OpenClaw 版本
+	Error       string    `json:"error"`
+	Log         string    `gorm:"type:text" json:"log"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `gorm:"index" json:"finished_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AuthSession 登录会话登记表：每个签发的 JWT 按 jti 记录，鉴权时校验，用于列出和注销会话
+type AuthSession struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	JTI        string     `gorm:"uniqueIndex;size:64;not null" json:"jti"`
+	UserID     uint       `gorm:"index" json:"user_id"`
+	Username   string     `json:"username"`
+	IP         string     `json:"ip"`
+	UserAgent  string     `json:"user_agent"`
+	ExpiresAt  time.Time  `gorm:"index" json:"expires_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
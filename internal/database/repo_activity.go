@@ -157,7 +157,7 @@ func (r *ActivityRepo) List(filter ActivityFilter) ([]Activity, int64, error) {
 		q = q.Where("risk = ?", filter.Risk)
 	}
 	if filter.Keyword != "" {
-		q = q.Where("summary LIKE ?", "%"+filter.Keyword+"%")
+		q = q.Where(`summary LIKE ? ESCAPE '\'`, containsPattern(filter.Keyword))
 	}
 	if filter.StartTime != "" {
 		q = q.Where("created_at >= ?", filter.StartTime)
@@ -186,6 +186,18 @@ func (r *ActivityRepo) List(filter ActivityFilter) ([]Activity, int64, error) {
 	return activities, total, err
 }
 
+// ListBySession 按时间顺序返回某个 Gateway 会话的活动（最多 limit 条，取最早的），以及总数
+func (r *ActivityRepo) ListBySession(sessionID string, limit int) ([]Activity, int64, error) {
+	var activities []Activity
+	var total int64
+	q := r.db.Model(&Activity{}).Where("session_id = ?", sessionID)
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := q.Order("timestamp asc").Order("id asc").Limit(limit).Find(&activities).Error
+	return activities, total, err
+}
+
 // GetByID 根据 ID 获取活动详情
 func (r *ActivityRepo) GetByID(id uint) (*Activity, error) {
 	var activity Activity
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// 时间线分桶粒度
+const (
+	TimelineHour = "hour"
+	TimelineDay  = "day"
+)
+
+// TimelineBucket 时间线中的一个桶：起始时间（所选时区）、总数及按风险/分类的细分
+type TimelineBucket struct {
+	Start      time.Time        `json:"start"`
+	Total      int64            `json:"total"`
+	ByRisk     map[string]int64 `json:"by_risk"`
+	ByCategory map[string]int64 `json:"by_category"`
+}
+
+// timelineRow GROUP BY 的一行：slot 为 UTC 纪元秒除以粒度
+type timelineRow struct {
+	Slot     int64
+	Risk     string
+	Category string
+	Count    int64
+}
+
+// Timeline 按小时/天统计 [since, until) 内的活动，分桶按 loc 时区的本地时间划分。
+// 数据库按 UTC 小时（或 15 分钟，用于非整点时区）聚合，再在内存中归入本地桶，
+// 因此夏令时切换与非整点时区都能正确分桶；返回连续的桶（无活动的桶计数为 0）
+func (r *ActivityRepo) Timeline(since, until time.Time, bucket string, loc *time.Location) ([]TimelineBucket, error) {
+	if bucket != TimelineHour && bucket != TimelineDay {
+		return nil, fmt.Errorf("unsupported bucket %q", bucket)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	gran := timelineGranularity(since, until, loc)
+
+	var slotExpr string
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		slotExpr = fmt.Sprintf("FLOOR(EXTRACT(EPOCH FROM created_at) / %d)::bigint", gran)
+	default:
+		slotExpr = fmt.Sprintf("CAST(strftime('%%s', created_at) AS INTEGER) / %d", gran)
+	}
+
+	var rows []timelineRow
+	err := r.db.Model(&Activity{}).
+		Select(slotExpr+" AS slot, risk, category, count(*) AS count").
+		Where("created_at >= ? AND created_at < ?", since.UTC(), until.UTC()).
+		Group("slot, risk, category").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return timelineBuckets(rows, gran, since, until, bucket, loc), nil
+}
+
+// timelineGranularity 返回数据库聚合粒度（秒）：时区偏移均为整小时时按小时，否则按 15 分钟
+func timelineGranularity(since, until time.Time, loc *time.Location) int64 {
+	for _, t := range []time.Time{since, until} {
+		if _, off := t.In(loc).Zone(); off%3600 != 0 {
+			return 900
+		}
+	}
+	return 3600
+}
+
+// timelineBucketStart 返回 t 所在本地桶的起始时间
+func timelineBucketStart(t time.Time, bucket string, loc *time.Location) time.Time {
+	lt := t.In(loc)
+	if bucket == TimelineDay {
+		return time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, loc)
+	}
+	return time.Date(lt.Year(), lt.Month(), lt.Day(), lt.Hour(), 0, 0, 0, loc)
+}
+
+// timelineNext 返回下一个桶的起始时间；夏令时回拨时重复的本地小时合并为一个桶
+func timelineNext(start time.Time, bucket string, loc *time.Location) time.Time {
+	if bucket == TimelineDay {
+		return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+	}
+	next := timelineBucketStart(start.Add(time.Hour), bucket, loc)
+	if !next.After(start) {
+		next = timelineBucketStart(start.Add(2*time.Hour), bucket, loc)
+	}
+	return next
+}
+
+// timelineBuckets 将 UTC 槽位聚合结果归入连续的本地桶
+func timelineBuckets(rows []timelineRow, gran int64, since, until time.Time, bucket string, loc *time.Location) []TimelineBucket {
+	var out []TimelineBucket
+	index := map[int64]int{}
+	for start := timelineBucketStart(since, bucket, loc); start.Before(until); start = timelineNext(start, bucket, loc) {
+		index[start.Unix()] = len(out)
+		out = append(out, TimelineBucket{
+			Start:      start,
+			ByRisk:     map[string]int64{},
+			ByCategory: map[string]int64{},
+		})
+	}
+	for _, row := range rows {
+		start := timelineBucketStart(time.Unix(row.Slot*gran, 0), bucket, loc)
+		i, ok := index[start.Unix()]
+		if !ok {
+			continue
+		}
+		b := &out[i]
+		b.Total += row.Count
+		if row.Risk != "" {
+			b.ByRisk[row.Risk] += row.Count
+		}
+		if row.Category != "" {
+			b.ByCategory[row.Category] += row.Count
+		}
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineBuckets_DayAcrossTimezone(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	since := time.Date(2026, 3, 9, 16, 0, 0, 0, time.UTC) // 2026-03-10 00:00 +08
+	until := since.Add(48 * time.Hour)
+
+	// 15:00 UTC is still 9 March in UTC but 23:00 on 10 March in Shanghai;
+	// 16:00 UTC is already 11 March there
+	rows := []timelineRow{
+		{Slot: time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC).Unix() / 3600, Risk: "high", Category: "Shell", Count: 2},
+		{Slot: time.Date(2026, 3, 10, 16, 0, 0, 0, time.UTC).Unix() / 3600, Risk: "low", Category: "File", Count: 1},
+	}
+	buckets := timelineBuckets(rows, 3600, since, until, TimelineDay, shanghai)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, "2026-03-10T00:00:00+08:00", buckets[0].Start.Format(time.RFC3339))
+	assert.Equal(t, int64(2), buckets[0].Total)
+	assert.Equal(t, int64(2), buckets[0].ByRisk["high"])
+	assert.Equal(t, int64(1), buckets[1].Total)
+	assert.Equal(t, int64(1), buckets[1].ByCategory["File"])
+}
+
+func TestTimelineBuckets_HalfHourZoneAndDST(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	since := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	until := since.Add(3 * time.Hour)
+	assert.Equal(t, int64(900), timelineGranularity(since, until, kolkata))
+
+	// 00:45 UTC = 06:15 IST, 01:15 UTC = 06:45 IST: same local hour
+	rows := []timelineRow{
+		{Slot: since.Add(45*time.Minute).Unix() / 900, Risk: "low", Count: 1},
+		{Slot: since.Add(75*time.Minute).Unix() / 900, Risk: "low", Count: 1},
+	}
+	buckets := timelineBuckets(rows, 900, since, until, TimelineHour, kolkata)
+	require.NotEmpty(t, buckets)
+	assert.Equal(t, "05:00", buckets[0].Start.Format("15:04"))
+	assert.Equal(t, "06:00", buckets[1].Start.Format("15:04"))
+	assert.Equal(t, int64(2), buckets[1].Total)
+
+	// New York falls back on 2026-11-01: 01:00 local happens twice and is one bucket
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	start := time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC) // 00:00 EDT
+	buckets = timelineBuckets(nil, 3600, start, start.Add(4*time.Hour), TimelineHour, ny)
+	var hours []string
+	for _, b := range buckets {
+		hours = append(hours, b.Start.Format("15:04"))
+	}
+	assert.Equal(t, []string{"00:00", "01:00", "02:00"}, hours)
+}
+
+func TestActivityRepo_Timeline(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewActivityRepo()
+	now := time.Now().UTC().Truncate(time.Hour)
+	for i, a := range []Activity{
+		{EventID: "e1", Risk: "high", Category: "Shell", CreatedAt: now.Add(-90 * time.Minute)},
+		{EventID: "e2", Risk: "low", Category: "File", CreatedAt: now.Add(-80 * time.Minute)},
+		{EventID: "e3", Risk: "low", Category: "File", CreatedAt: now.Add(-10 * time.Minute)},
+		{EventID: "e4", Risk: "low", Category: "File", CreatedAt: now.Add(-72 * time.Hour)},
+	} {
+		require.NoError(t, repo.Create(&a), "activity %d", i)
+	}
+
+	buckets, err := repo.Timeline(now.Add(-3*time.Hour), now, TimelineHour, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, buckets, 3)
+	assert.Equal(t, int64(0), buckets[0].Total)
+	assert.Equal(t, int64(2), buckets[1].Total)
+	assert.Equal(t, int64(1), buckets[1].ByRisk["high"])
+	assert.Equal(t, int64(1), buckets[2].Total)
+	assert.Equal(t, int64(1), buckets[2].ByCategory["File"])
+}
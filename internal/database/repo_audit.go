@@ -1,6 +1,9 @@
 package database
 
 import (
+	"net"
+	"time"
+
 	"openclawdeck/internal/logger"
 
 	"gorm.io/gorm"
@@ -60,6 +63,94 @@ func (r *AuditLogRepo) List(filter AuditFilter) ([]AuditLog, int64, error) {
 	return logs, total, err
 }
 
+// AuditGroupRow 按 IP、用户名、动作聚合的审计记录数量与时间范围
+type AuditGroupRow struct {
+	IP        string
+	Username  string
+	Action    string
+	Count     int64
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// GroupByIPUserAction 按 IP（主机，不含端口）、username、action 分组统计 since 之后的指定动作；
+// 旧版本记录的 ip 为 "host:port"，这里按主机合并，避免每个源端口各占一行
+func (r *AuditLogRepo) GroupByIPUserAction(actions []string, since time.Time) ([]AuditGroupRow, error) {
+	// MIN/MAX 在 SQLite 中以文本返回，先按字符串读取再解析
+	var raw []struct {
+		IP        string
+		Username  string
+		Action    string
+		Count     int64
+		FirstSeen string
+		LastSeen  string
+	}
+	err := r.db.Model(&AuditLog{}).
+		Select("ip, username, action, COUNT(*) AS count, MIN(created_at) AS first_seen, MAX(created_at) AS last_seen").
+		Where("action IN ? AND created_at >= ?", actions, since).
+		Group("ip, username, action").
+		Scan(&raw).Error
+	if err != nil {
+		return nil, err
+	}
+	type groupKey struct{ ip, username, action string }
+	index := map[groupKey]int{}
+	rows := make([]AuditGroupRow, 0, len(raw))
+	for _, x := range raw {
+		row := AuditGroupRow{
+			IP:        auditHost(x.IP),
+			Username:  x.Username,
+			Action:    x.Action,
+			Count:     x.Count,
+			FirstSeen: parseDBTime(x.FirstSeen),
+			LastSeen:  parseDBTime(x.LastSeen),
+		}
+		k := groupKey{row.IP, row.Username, row.Action}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(rows)
+			rows = append(rows, row)
+			continue
+		}
+		g := &rows[i]
+		g.Count += row.Count
+		if g.FirstSeen.IsZero() || (!row.FirstSeen.IsZero() && row.FirstSeen.Before(g.FirstSeen)) {
+			g.FirstSeen = row.FirstSeen
+		}
+		if row.LastSeen.After(g.LastSeen) {
+			g.LastSeen = row.LastSeen
+		}
+	}
+	return rows, nil
+}
+
+// auditHost 去掉审计 IP 中的端口
+func auditHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// dbTimeLayouts SQLite/PostgreSQL 聚合结果中时间的文本格式
+var dbTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// parseDBTime 解析文本形式的时间，无法解析时返回零值
+func parseDBTime(s string) time.Time {
+	for _, layout := range dbTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 type AuditFilter struct {
 	Page      int
 	PageSize  int
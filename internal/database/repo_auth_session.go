@@ -0,0 +1,66 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuthSessionRepo 登录会话数据仓库
+type AuthSessionRepo struct {
+	db *gorm.DB
+}
+
+func NewAuthSessionRepo() *AuthSessionRepo {
+	return &AuthSessionRepo{db: DB}
+}
+
+// Create 登记新会话
+func (r *AuthSessionRepo) Create(s *AuthSession) error {
+	return r.db.Create(s).Error
+}
+
+// FindByJTI 按 jti 查询会话
+func (r *AuthSessionRepo) FindByJTI(jti string) (*AuthSession, error) {
+	var s AuthSession
+	if err := r.db.Where("jti = ?", jti).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListActive 查询未注销且未过期的会话，userID 为 0 时查询所有用户，最近活动的在前
+func (r *AuthSessionRepo) ListActive(userID uint) ([]AuthSession, error) {
+	var list []AuthSession
+	q := r.db.Where("revoked_at IS NULL AND expires_at > ?", time.Now().UTC())
+	if userID != 0 {
+		q = q.Where("user_id = ?", userID)
+	}
+	err := q.Order("last_seen_at DESC").Find(&list).Error
+	return list, err
+}
+
+// Revoke 注销单个会话
+func (r *AuthSessionRepo) Revoke(jti string) error {
+	return r.db.Model(&AuthSession{}).Where("jti = ? AND revoked_at IS NULL", jti).
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+// RevokeUser 注销用户的所有会话，exceptJTI 非空时保留该会话
+func (r *AuthSessionRepo) RevokeUser(userID uint, exceptJTI string) error {
+	q := r.db.Model(&AuthSession{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptJTI != "" {
+		q = q.Where("jti <> ?", exceptJTI)
+	}
+	return q.Update("revoked_at", time.Now().UTC()).Error
+}
+
+// Touch 更新最近活动时间
+func (r *AuthSessionRepo) Touch(jti string, at time.Time) error {
+	return r.db.Model(&AuthSession{}).Where("jti = ?", jti).Update("last_seen_at", at).Error
+}
+
+// DeleteExpired 删除已过期的会话记录
+func (r *AuthSessionRepo) DeleteExpired() error {
+	return r.db.Where("expires_at <= ?", time.Now().UTC()).Delete(&AuthSession{}).Error
+}
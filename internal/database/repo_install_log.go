@@ -0,0 +1,38 @@
+package database
+
+import (
+	"gorm.io/gorm"
+)
+
+// InstallLogRepo 安装记录数据仓库
+type InstallLogRepo struct {
+	db *gorm.DB
+}
+
+func NewInstallLogRepo() *InstallLogRepo {
+	return &InstallLogRepo{db: DB}
+}
+
+// Create 创建安装记录
+func (r *InstallLogRepo) Create(log *InstallLog) error {
+	return r.db.Create(log).Error
+}
+
+// List 按结束时间倒序查询最近 limit 条记录
+func (r *InstallLogRepo) List(limit int) ([]InstallLog, error) {
+	var logs []InstallLog
+	err := r.db.Order("finished_at desc, id desc").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// Prune 只保留最近 keep 条记录
+func (r *InstallLogRepo) Prune(keep int) error {
+	var ids []uint
+	if err := r.db.Model(&InstallLog{}).Order("finished_at desc, id desc").Offset(keep).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Delete(&InstallLog{}, ids).Error
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MonitorSessionSnapshotRepo GW 会话快照数据仓库
+type MonitorSessionSnapshotRepo struct {
+	db *gorm.DB
+}
+
+func NewMonitorSessionSnapshotRepo() *MonitorSessionSnapshotRepo {
+	return &MonitorSessionSnapshotRepo{db: DB}
+}
+
+// ListAll 查询所有会话快照
+func (r *MonitorSessionSnapshotRepo) ListAll() ([]MonitorSessionSnapshot, error) {
+	var list []MonitorSessionSnapshot
+	err := r.db.Find(&list).Error
+	return list, err
+}
+
+// Upsert 按 session_key 写入快照（存在则更新）
+func (r *MonitorSessionSnapshotRepo) Upsert(s *MonitorSessionSnapshot) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"input_tokens", "output_tokens", "total_tokens", "last_active_ms", "updated_at"}),
+	}).Create(s).Error
+}
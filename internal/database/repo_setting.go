@@ -1,6 +1,8 @@
 package database
 
 import (
+	"openclawdeck/internal/logger"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -14,18 +16,22 @@ func NewSettingRepo() *SettingRepo {
 	return &SettingRepo{db: DB}
 }
 
-// Get 获取单个设置项
+// Get 获取单个设置项（敏感项自动解密）
 func (r *SettingRepo) Get(key string) (string, error) {
 	var setting Setting
 	err := r.db.Where("`key` = ?", key).First(&setting).Error
 	if err != nil {
 		return "", err
 	}
-	return setting.Value, nil
+	return decryptSetting(key, setting.Value)
 }
 
-// Set 设置单个配置项（存在则更新，不存在则创建）
+// Set 设置单个配置项（存在则更新，不存在则创建；敏感项加密存储）
 func (r *SettingRepo) Set(key, value string) error {
+	value, err := encryptSetting(key, value)
+	if err != nil {
+		return err
+	}
 	return r.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "key"}},
 		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
@@ -41,7 +47,11 @@ func (r *SettingRepo) GetAll() (map[string]string, error) {
 	}
 	result := make(map[string]string)
 	for _, s := range settings {
-		result[s.Key] = s.Value
+		v, err := decryptSetting(s.Key, s.Value)
+		if err != nil {
+			logger.DB.Warn().Err(err).Msg("敏感设置解密失败")
+		}
+		result[s.Key] = v
 	}
 	return result, nil
 }
@@ -50,7 +60,11 @@ func (r *SettingRepo) GetAll() (map[string]string, error) {
 func (r *SettingRepo) SetBatch(items map[string]string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		for key, value := range items {
-			err := tx.Clauses(clause.OnConflict{
+			value, err := encryptSetting(key, value)
+			if err != nil {
+				return err
+			}
+			err = tx.Clauses(clause.OnConflict{
 				Columns:   []clause.Column{{Name: "key"}},
 				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
 			}).Create(&Setting{Key: key, Value: value}).Error
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SkillHashRepo 技能文件哈希基线仓库
+type SkillHashRepo struct {
+	db *gorm.DB
+}
+
+func NewSkillHashRepo() *SkillHashRepo {
+	return &SkillHashRepo{db: DB}
+}
+
+// List 返回全部基线记录
+func (r *SkillHashRepo) List() ([]SkillHash, error) {
+	var hashes []SkillHash
+	err := r.db.Order("skill_name, file_path").Find(&hashes).Error
+	return hashes, err
+}
+
+// ReplaceSkill 以 files（相对路径 -> sha256）重建技能的基线
+func (r *SkillHashRepo) ReplaceSkill(skill string, files map[string]string) error {
+	now := time.Now().UTC()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("skill_name = ?", skill).Delete(&SkillHash{}).Error; err != nil {
+			return err
+		}
+		rows := make([]SkillHash, 0, len(files))
+		for path, sum := range files {
+			rows = append(rows, SkillHash{SkillName: skill, FilePath: path, SHA256Hash: sum, LastCheckedAt: now})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 200).Error
+	})
+}
+
+// MarkChecked 更新技能的篡改标记与检查时间
+func (r *SkillHashRepo) MarkChecked(skill string, tampered bool, at time.Time) error {
+	return r.db.Model(&SkillHash{}).Where("skill_name = ?", skill).
+		Updates(map[string]interface{}{"tampered": tampered, "last_checked_at": at}).Error
+}
+
+// DeleteSkill 删除技能的基线（卸载后调用）
+func (r *SkillHashRepo) DeleteSkill(skill string) error {
+	return r.db.Where("skill_name = ?", skill).Delete(&SkillHash{}).Error
+}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"strings"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -14,17 +16,60 @@ func NewTemplateRepo() *TemplateRepo {
 	return &TemplateRepo{db: DB}
 }
 
-// List returns all templates, optionally filtered by target file.
-func (r *TemplateRepo) List(targetFile string) ([]Template, error) {
+// DefaultTemplateCategory is assigned to templates created without a category.
+const DefaultTemplateCategory = "general"
+
+// TemplateFilter narrows a template listing. Empty fields are ignored.
+type TemplateFilter struct {
+	TargetFile string
+	Category   string
+	Query      string // case-insensitive match on template_id, tags and i18n (name/desc/content)
+}
+
+// TemplateCategory is a distinct category with its template count.
+type TemplateCategory struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// List returns all templates matching the filter.
+func (r *TemplateRepo) List(f TemplateFilter) ([]Template, error) {
 	var templates []Template
 	q := r.db.Order("built_in DESC, updated_at DESC")
-	if targetFile != "" {
-		q = q.Where("target_file = ?", targetFile)
+	if f.TargetFile != "" {
+		q = q.Where("target_file = ?", f.TargetFile)
+	}
+	if f.Category != "" {
+		q = q.Where("category = ?", f.Category)
+	}
+	if f.Query != "" {
+		like := containsPattern(strings.ToLower(f.Query))
+		q = q.Where(`LOWER(template_id) LIKE ? ESCAPE '\' OR LOWER(tags) LIKE ? ESCAPE '\' OR LOWER(i18n) LIKE ? ESCAPE '\'`, like, like, like)
 	}
 	err := q.Find(&templates).Error
 	return templates, err
 }
 
+// Categories returns the distinct template categories with counts, optionally
+// restricted to one target file.
+func (r *TemplateRepo) Categories(targetFile string) ([]TemplateCategory, error) {
+	var cats []TemplateCategory
+	q := r.db.Model(&Template{}).Select("category, COUNT(*) AS count")
+	if targetFile != "" {
+		q = q.Where("target_file = ?", targetFile)
+	}
+	err := q.Group("category").Order("category ASC").Scan(&cats).Error
+	return cats, err
+}
+
+// BackfillCategory assigns the default category to templates that have none.
+func (r *TemplateRepo) BackfillCategory() (int64, error) {
+	res := r.db.Model(&Template{}).
+		Where("category = ? OR category IS NULL", "").
+		Update("category", DefaultTemplateCategory)
+	return res.RowsAffected, res.Error
+}
+
 // GetByID returns a single template by its primary key.
 func (r *TemplateRepo) GetByID(id uint) (*Template, error) {
 	var tpl Template
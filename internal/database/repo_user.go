@@ -36,11 +36,13 @@ func (r *UserRepo) FindByID(id uint) (*User, error) {
 	return &user, nil
 }
 
+// UpdatePassword 更新密码并解除锁定；同时递增 token_epoch，使该用户之前签发的所有 token 失效
 func (r *UserRepo) UpdatePassword(id uint, hash string) error {
 	return r.db.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
 		"password_hash":   hash,
 		"failed_attempts": 0,
 		"locked_until":    nil,
+		"token_epoch":     gorm.Expr("token_epoch + 1"),
 	}).Error
 }
 
@@ -81,9 +83,12 @@ func (r *UserRepo) FirstUsername() string {
 	return user.Username
 }
 
-// UpdateUsername 更新用户名
+// UpdateUsername 更新用户名；同时递增 token_epoch，使携带旧用户名的 token 全部失效
 func (r *UserRepo) UpdateUsername(id uint, username string) error {
-	return r.db.Model(&User{}).Where("id = ?", id).Update("username", username).Error
+	return r.db.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"username":    username,
+		"token_epoch": gorm.Expr("token_epoch + 1"),
+	}).Error
 }
 
 // Delete 删除用户
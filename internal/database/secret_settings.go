@@ -0,0 +1,214 @@
+package database
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"openclawdeck/internal/logger"
+)
+
+// secretSettingKeys 需加密存储的设置项（令牌、签名密钥、含密钥的 webhook 地址）。
+// 其余设置保持明文，便于排查
+var secretSettingKeys = map[string]bool{
+	"gateway_token":            true,
+	"gateway_last_good_token":  true,
+	"notify_telegram_token":    true,
+	"notify_dingtalk_token":    true,
+	"notify_dingtalk_secret":   true,
+	"notify_lark_webhook_url":  true,
+	"notify_discord_token":     true,
+	"notify_slack_token":       true,
+	"notify_wecom_webhook_url": true,
+	"notify_webhook_url":       true,
+	"notify_webhook_headers":   true,
+	"pairing_hmac_secret":      true,
+	"translation_api_key":      true,
+	"outbound_webhook_url":     true,
+	"outbound_webhook_secret":  true,
+}
+
+// encryptedPrefix 密文格式: enc:v1:<密钥 ID>:<base64(nonce|密文)>
+const encryptedPrefix = "enc:v1:"
+
+// ErrSecretKeyUnavailable 密文对应的主密钥未配置（主密钥被更换且未保留旧密钥）
+var ErrSecretKeyUnavailable = errors.New("settings secret key unavailable")
+
+// settingsKey 由主密钥派生的 AES-256 密钥
+type settingsKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+var (
+	secretKeysMu sync.RWMutex
+	primaryKey   *settingsKey
+	fallbackKeys []*settingsKey
+)
+
+// IsSecretSetting 该设置项是否加密存储
+func IsSecretSetting(key string) bool {
+	return secretSettingKeys[key]
+}
+
+// SetSecretKeys 设置加密主密钥；previous 为轮换前的旧密钥，仅用于解密。
+// primary 为空时不加密（新写入为明文），已有密文仍可用 previous 解密
+func SetSecretKeys(primary string, previous []string) error {
+	var p *settingsKey
+	if primary != "" {
+		k, err := deriveSettingsKey(primary)
+		if err != nil {
+			return err
+		}
+		p = k
+	}
+	var prev []*settingsKey
+	for _, s := range previous {
+		if s == "" {
+			continue
+		}
+		k, err := deriveSettingsKey(s)
+		if err != nil {
+			return err
+		}
+		prev = append(prev, k)
+	}
+	secretKeysMu.Lock()
+	primaryKey, fallbackKeys = p, prev
+	secretKeysMu.Unlock()
+	return nil
+}
+
+func deriveSettingsKey(master string) (*settingsKey, error) {
+	sum := sha256.Sum256([]byte("openclawdeck/settings/v1\x00" + master))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	id := sha256.Sum256(sum[:])
+	return &settingsKey{id: hex.EncodeToString(id[:4]), aead: aead}, nil
+}
+
+// encryptSetting 加密需保护的设置值；非敏感项、空值或未配置主密钥时原样返回
+func encryptSetting(key, value string) (string, error) {
+	if !IsSecretSetting(key) || value == "" {
+		return value, nil
+	}
+	secretKeysMu.RLock()
+	k := primaryKey
+	secretKeysMu.RUnlock()
+	if k == nil {
+		return value, nil
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// 以设置项名称作为附加数据，防止密文被挪到其他设置项
+	sealed := k.aead.Seal(nonce, nonce, []byte(value), []byte(key))
+	return encryptedPrefix + k.id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSetting 解密设置值；明文（历史数据）原样返回
+func decryptSetting(key, stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+	id, payload, ok := strings.Cut(strings.TrimPrefix(stored, encryptedPrefix), ":")
+	if !ok {
+		return "", fmt.Errorf("setting %s: malformed ciphertext", key)
+	}
+	k := lookupSettingsKey(id)
+	if k == nil {
+		return "", fmt.Errorf("setting %s: %w (key id %s)", key, ErrSecretKeyUnavailable, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < k.aead.NonceSize() {
+		return "", fmt.Errorf("setting %s: malformed ciphertext", key)
+	}
+	n := k.aead.NonceSize()
+	plain, err := k.aead.Open(nil, sealed[:n], sealed[n:], []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("setting %s: decrypt failed: %w", key, err)
+	}
+	return string(plain), nil
+}
+
+func lookupSettingsKey(id string) *settingsKey {
+	secretKeysMu.RLock()
+	defer secretKeysMu.RUnlock()
+	if primaryKey != nil && primaryKey.id == id {
+		return primaryKey
+	}
+	for _, k := range fallbackKeys {
+		if k.id == id {
+			return k
+		}
+	}
+	return nil
+}
+
+// needsReencrypt 明文敏感值，或由旧密钥加密的值需要用当前主密钥重新加密
+func needsReencrypt(stored string) bool {
+	secretKeysMu.RLock()
+	k := primaryKey
+	secretKeysMu.RUnlock()
+	if k == nil || stored == "" {
+		return false
+	}
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return true
+	}
+	return !strings.HasPrefix(stored, encryptedPrefix+k.id+":")
+}
+
+// ReencryptSecretSettings 将明文存储的敏感设置（升级前的历史数据）及旧密钥加密的值
+// 用当前主密钥重新加密；启动时执行，轮换密钥后旧密钥可在一次启动后移除
+func ReencryptSecretSettings() error {
+	var rows []Setting
+	if err := DB.Where("`key` IN ?", secretSettingKeyList()).Find(&rows).Error; err != nil {
+		return err
+	}
+	count := 0
+	for _, row := range rows {
+		if !needsReencrypt(row.Value) {
+			continue
+		}
+		plain, err := decryptSetting(row.Key, row.Value)
+		if err != nil {
+			logger.DB.Warn().Err(err).Str("key", row.Key).Msg("敏感设置无法解密，跳过重新加密")
+			continue
+		}
+		enc, err := encryptSetting(row.Key, plain)
+		if err != nil {
+			return err
+		}
+		if err := DB.Model(&Setting{}).Where("`key` = ?", row.Key).Update("value", enc).Error; err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		logger.DB.Info().Int("count", count).Msg("敏感设置已加密存储")
+	}
+	return nil
+}
+
+func secretSettingKeyList() []string {
+	keys := make([]string, 0, len(secretSettingKeys))
+	for k := range secretSettingKeys {
+		keys = append(keys, k)
+	}
+	return keys
+}
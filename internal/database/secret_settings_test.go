@@ -0,0 +1,25 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptSetting_LastGoodGatewayToken(t *testing.T) {
+	if err := SetSecretKeys("test-master-key-0123456789", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer SetSecretKeys("", nil)
+
+	enc, err := encryptSetting("gateway_last_good_token", "tok-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enc, encryptedPrefix) || strings.Contains(enc, "tok-123") {
+		t.Fatalf("gateway_last_good_token stored in plaintext: %q", enc)
+	}
+	plain, err := decryptSetting("gateway_last_good_token", enc)
+	if err != nil || plain != "tok-123" {
+		t.Fatalf("decrypt = %q, %v", plain, err)
+	}
+}
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/logger"
+)
+
+// SettingType 设置项的值类型
+type SettingType string
+
+const (
+	SettingString   SettingType = "string"
+	SettingBool     SettingType = "bool"
+	SettingInt      SettingType = "int"
+	SettingDuration SettingType = "duration"
+)
+
+// SettingDef 设置项定义：类型、默认值与校验规则。
+// Duration 类型的纯数字按 Unit 解析（如 "10" 分钟），也接受 "90s"、"2h" 等写法；
+// Min/Max 对 Int 为数值、对 Duration 为纳秒，0 表示不限
+type SettingDef struct {
+	Key         string               `json:"key"`
+	Type        SettingType          `json:"type"`
+	Default     string               `json:"default"`
+	Description string               `json:"description,omitempty"`
+	Min         int64                `json:"min,omitempty"`
+	Max         int64                `json:"max,omitempty"`
+	Unit        time.Duration        `json:"-"`
+	Enum        []string             `json:"enum,omitempty"`
+	Validate    func(v string) error `json:"-"`
+}
+
+var (
+	settingDefsMu sync.RWMutex
+	settingDefs   = map[string]SettingDef{}
+)
+
+// RegisterSettings 登记设置项定义，由各功能模块在 init 中调用；重复登记同一 key 会 panic
+func RegisterSettings(defs ...SettingDef) {
+	settingDefsMu.Lock()
+	defer settingDefsMu.Unlock()
+	for _, def := range defs {
+		if _, dup := settingDefs[def.Key]; dup {
+			panic("duplicate setting definition: " + def.Key)
+		}
+		if def.Type == SettingDuration && def.Unit == 0 {
+			def.Unit = time.Second
+		}
+		if def.Default != "" {
+			if _, err := def.normalize(def.Default); err != nil {
+				panic(fmt.Sprintf("setting %s: invalid default: %v", def.Key, err))
+			}
+		}
+		settingDefs[def.Key] = def
+	}
+}
+
+// LookupSetting 返回设置项定义
+func LookupSetting(key string) (SettingDef, bool) {
+	settingDefsMu.RLock()
+	defer settingDefsMu.RUnlock()
+	def, ok := settingDefs[key]
+	return def, ok
+}
+
+// SettingDefs 返回全部已登记的设置项定义（按 key 排序）
+func SettingDefs() []SettingDef {
+	settingDefsMu.RLock()
+	defer settingDefsMu.RUnlock()
+	defs := make([]SettingDef, 0, len(settingDefs))
+	for _, def := range settingDefs {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+	return defs
+}
+
+// ValidateSetting 按定义校验并规范化设置值（如 "on" → "true"）；未登记的 key 返回错误。
+// 空值表示恢复默认，始终允许
+func ValidateSetting(key, value string) (string, error) {
+	def, ok := LookupSetting(key)
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", key)
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	return def.normalize(value)
+}
+
+func (d SettingDef) normalize(value string) (string, error) {
+	v := strings.TrimSpace(value)
+	switch d.Type {
+	case SettingBool:
+		b, err := parseSettingBool(v)
+		if err != nil {
+			return "", err
+		}
+		v = strconv.FormatBool(b)
+	case SettingInt:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		if err := d.checkRange(n, strconv.FormatInt(d.Min, 10), strconv.FormatInt(d.Max, 10)); err != nil {
+			return "", err
+		}
+		v = strconv.FormatInt(n, 10)
+	case SettingDuration:
+		dur, err := d.parseDuration(v)
+		if err != nil {
+			return "", err
+		}
+		if err := d.checkRange(int64(dur), time.Duration(d.Min).String(), time.Duration(d.Max).String()); err != nil {
+			return "", err
+		}
+	}
+	if len(d.Enum) > 0 && !containsSettingValue(d.Enum, v) {
+		return "", fmt.Errorf("must be one of %s", strings.Join(d.Enum, ", "))
+	}
+	if d.Validate != nil {
+		if err := d.Validate(v); err != nil {
+			return "", err
+		}
+	}
+	return v, nil
+}
+
+func (d SettingDef) checkRange(n int64, minText, maxText string) error {
+	if d.Min != 0 && n < d.Min {
+		return fmt.Errorf("must be at least %s", minText)
+	}
+	if d.Max != 0 && n > d.Max {
+		return fmt.Errorf("must be at most %s", maxText)
+	}
+	return nil
+}
+
+func (d SettingDef) parseDuration(v string) (time.Duration, error) {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(n) * d.Unit, nil
+	}
+	dur, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration", v)
+	}
+	return dur, nil
+}
+
+func parseSettingBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", v)
+}
+
+func containsSettingValue(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// typed 读取已登记设置项的规范化值：未设置返回默认值，存储值无效时记录警告并返回默认值
+func (r *SettingRepo) typed(key string, want SettingType) (SettingDef, string) {
+	def, ok := LookupSetting(key)
+	if !ok || def.Type != want {
+		panic(fmt.Sprintf("setting %s is not registered as %s", key, want))
+	}
+	raw, _ := r.Get(key)
+	if strings.TrimSpace(raw) == "" {
+		return def, def.Default
+	}
+	v, err := def.normalize(raw)
+	if err != nil {
+		logger.DB.Warn().Str("key", key).Str("value", raw).Err(err).Msg("设置值无效，使用默认值")
+		return def, def.Default
+	}
+	return def, v
+}
+
+// GetString 读取字符串设置（未设置时为默认值）
+func (r *SettingRepo) GetString(key string) string {
+	_, v := r.typed(key, SettingString)
+	return v
+}
+
+// GetBool 读取布尔设置（接受 true/false/1/0/yes/no/on/off）
+func (r *SettingRepo) GetBool(key string) bool {
+	_, v := r.typed(key, SettingBool)
+	return v == "true"
+}
+
+// GetInt 读取整数设置
+func (r *SettingRepo) GetInt(key string) int {
+	_, v := r.typed(key, SettingInt)
+	n, _ := strconv.Atoi(v)
+	return n
+}
+
+// GetDuration 读取时长设置
+func (r *SettingRepo) GetDuration(key string) time.Duration {
+	def, v := r.typed(key, SettingDuration)
+	if v == "" {
+		return 0
+	}
+	d, _ := def.parseDuration(v)
+	return d
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	RegisterSettings(
+		SettingDef{Key: "test_flag", Type: SettingBool, Default: "true"},
+		SettingDef{Key: "test_count", Type: SettingInt, Default: "20", Min: 1, Max: 100},
+		SettingDef{Key: "test_window", Type: SettingDuration, Unit: time.Minute, Default: "10", Min: int64(time.Minute)},
+		SettingDef{Key: "test_mode", Type: SettingString, Default: "auto", Enum: []string{"auto", "manual"}},
+	)
+}
+
+func TestValidateSetting_Coercion(t *testing.T) {
+	cases := []struct {
+		key, in, want string
+		ok            bool
+	}{
+		{"test_flag", " ON ", "true", true},
+		{"test_flag", "0", "false", true},
+		{"test_flag", "flase", "", false},
+		{"test_count", " 42 ", "42", true},
+		{"test_count", "0", "", false},
+		{"test_count", "101", "", false},
+		{"test_count", "4.5", "", false},
+		{"test_window", "15", "15", true},
+		{"test_window", "90s", "90s", true},
+		{"test_window", "30s", "", false},
+		{"test_mode", "manual", "manual", true},
+		{"test_mode", "other", "", false},
+		{"test_flag", "", "", true}, // empty resets to default
+		{"no_such_setting", "x", "", false},
+	}
+	for _, c := range cases {
+		got, err := ValidateSetting(c.key, c.in)
+		if !c.ok {
+			assert.Error(t, err, "%s=%q", c.key, c.in)
+			continue
+		}
+		require.NoError(t, err, "%s=%q", c.key, c.in)
+		assert.Equal(t, c.want, got, "%s=%q", c.key, c.in)
+	}
+}
+
+func TestSettingRepo_TypedGettersAndDefaults(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSettingRepo()
+
+	// unset: registry defaults
+	assert.True(t, repo.GetBool("test_flag"))
+	assert.Equal(t, 20, repo.GetInt("test_count"))
+	assert.Equal(t, 10*time.Minute, repo.GetDuration("test_window"))
+	assert.Equal(t, "auto", repo.GetString("test_mode"))
+
+	require.NoError(t, repo.SetBatch(map[string]string{
+		"test_flag":   "off",
+		"test_count":  "7",
+		"test_window": "2h",
+		"test_mode":   "manual",
+	}))
+	assert.False(t, repo.GetBool("test_flag"))
+	assert.Equal(t, 7, repo.GetInt("test_count"))
+	assert.Equal(t, 2*time.Hour, repo.GetDuration("test_window"))
+	assert.Equal(t, "manual", repo.GetString("test_mode"))
+
+	// invalid stored values fall back to the default instead of misbehaving
+	require.NoError(t, repo.Set("test_flag", "nope"))
+	require.NoError(t, repo.Set("test_count", "500"))
+	assert.True(t, repo.GetBool("test_flag"))
+	assert.Equal(t, 20, repo.GetInt("test_count"))
+
+	assert.Panics(t, func() { repo.GetInt("test_flag") }, "type mismatch is a programming error")
+}
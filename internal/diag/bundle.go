@@ -0,0 +1,219 @@
+package diag
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/setup"
+	"openclawdeck/internal/version"
+	"openclawdeck/internal/webconfig"
+)
+
+// logTailBytes bounds how much of the end of the deck log goes into the bundle.
+const logTailBytes = 256 << 10
+
+// Contents documents every file a bundle may contain; it is written to the
+// bundle as MANIFEST.txt so users can see what they are sharing.
+var Contents = map[string]string{
+	"version.json":     "OpenClawDeck version, build, OS/arch and Go runtime",
+	"scan.json":        "environment scan: OS, tools and versions, npm registry, OpenClaw install state",
+	"doctor.json":      "doctor check results (config lint, env refs, gateway, port, disk)",
+	"gateway.json":     "gateway runtime, running state and detected port",
+	"openclaw.json":    "OpenClaw config with secrets redacted",
+	"deck-config.json": "OpenClawDeck config with secrets (JWT secret, DSN) redacted",
+	"deck.log":         "last 256 KB of the OpenClawDeck log, secrets redacted",
+	"errors.txt":       "sections that could not be collected and why",
+}
+
+// Bundle accumulates redacted files and writes them as a zip.
+type Bundle struct {
+	files  map[string][]byte
+	errors []string
+}
+
+// NewBundle returns an empty bundle.
+func NewBundle() *Bundle {
+	return &Bundle{files: map[string][]byte{}}
+}
+
+// AddJSON marshals v, redacts secrets by key and in free text, and stores it as name.
+func (b *Bundle) AddJSON(name string, v interface{}) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		b.AddError(name, err)
+		return
+	}
+	b.AddRawJSON(name, raw)
+}
+
+// AddRawJSON redacts a JSON document and stores it as name. Invalid JSON is
+// stored as redacted text.
+func (b *Bundle) AddRawJSON(name string, raw []byte) {
+	var parsed interface{}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		b.AddText(name, string(raw))
+		return
+	}
+	out, err := json.MarshalIndent(RedactValue(parsed), "", "  ")
+	if err != nil {
+		b.AddError(name, err)
+		return
+	}
+	b.files[name] = out
+}
+
+// AddText redacts free text and stores it as name.
+func (b *Bundle) AddText(name, text string) {
+	b.files[name] = []byte(RedactText(text))
+}
+
+// AddError records a section that could not be collected.
+func (b *Bundle) AddError(name string, err error) {
+	b.errors = append(b.errors, fmt.Sprintf("%s: %s", name, RedactText(err.Error())))
+}
+
+// Files lists the stored file names, sorted.
+func (b *Bundle) Files() []string {
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteZip writes the bundle, a MANIFEST.txt and errors.txt (if any) to w.
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	write := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	if err := write("MANIFEST.txt", []byte(b.manifest())); err != nil {
+		return err
+	}
+	for _, name := range b.Files() {
+		if err := write(name, b.files[name]); err != nil {
+			return err
+		}
+	}
+	if len(b.errors) > 0 {
+		if err := write("errors.txt", []byte(strings.Join(b.errors, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (b *Bundle) manifest() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "OpenClawDeck diagnostics bundle\nCreated: %s\n\n", time.Now().Format(time.RFC3339))
+	// the key list comes from sensitiveKeys so the claim cannot drift from what is redacted
+	fmt.Fprintf(&sb, "Secrets are redacted: values under keys containing %s (case-insensitive),\n", strings.Join(sensitiveKeys, "/"))
+	sb.WriteString("bearer tokens, provider API keys, bot tokens, JWTs and URL credentials.\n\nFiles:\n")
+	names := b.Files()
+	if len(b.errors) > 0 {
+		names = append(names, "errors.txt")
+	}
+	for _, name := range names {
+		desc := Contents[name]
+		if desc == "" {
+			desc = "-"
+		}
+		fmt.Fprintf(&sb, "  %-18s %s\n", name, desc)
+	}
+	return sb.String()
+}
+
+// Collect gathers the sections shared by the CLI command and the HTTP endpoint:
+// version, environment scan, gateway status, OpenClaw and deck configs, and the
+// deck log tail. Callers add doctor.json themselves.
+func Collect(deckCfg webconfig.Config) *Bundle {
+	b := NewBundle()
+
+	b.AddJSON("version.json", map[string]string{
+		"version": version.Version,
+		"build":   version.Build,
+		"os":      runtime.GOOS,
+		"arch":    runtime.GOARCH,
+		"go":      runtime.Version(),
+	})
+
+	if report, err := setup.Scan(); err != nil {
+		b.AddError("scan.json", err)
+	} else {
+		b.AddJSON("scan.json", report)
+	}
+
+	st := openclaw.NewService().Status()
+	b.AddJSON("gateway.json", map[string]interface{}{
+		"runtime": st.Runtime,
+		"running": st.Running,
+		"detail":  st.Detail,
+		"port":    st.Port,
+	})
+
+	if path := openclaw.ResolveConfigPath(); path != "" {
+		if data, err := os.ReadFile(path); err != nil {
+			b.AddError("openclaw.json", err)
+		} else {
+			b.AddRawJSON("openclaw.json", data)
+		}
+	}
+
+	b.AddJSON("deck-config.json", deckCfg)
+
+	path := logger.FilePath()
+	if path == "" {
+		path = deckCfg.Log.FilePath
+	}
+	if path != "" {
+		if tail, err := readTail(path, logTailBytes); err != nil {
+			b.AddError("deck.log", err)
+		} else {
+			b.AddText("deck.log", tail)
+		}
+	}
+	return b
+}
+
+// readTail returns the last max bytes of a file, starting at a line boundary.
+func readTail(path string, max int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := info.Size() - max
+	if offset < 0 {
+		offset = 0
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return "", err
+	}
+	s := string(data)
+	if offset > 0 {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+	return s, nil
+}
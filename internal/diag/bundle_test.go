@@ -0,0 +1,88 @@
+package diag
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactText(t *testing.T) {
+	in := strings.Join([]string{
+		"Authorization: Bearer abc.def.ghi",
+		"token=s3cr3tvalue other=keep",
+		"key sk-ant-REDACTED",
+		"telegram 123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw",
+		"postgres://deck:hunter2@db:5432/deck",
+	}, "\n")
+	out := RedactText(in)
+	for _, secret := range []string{"abc.def.ghi", "s3cr3tvalue", "sk-ant-api03-AAAA", "AAHdqTcvCH1vGWJx", "hunter2"} {
+		assert.NotContains(t, out, secret)
+	}
+	assert.Contains(t, out, "other=keep")
+	assert.Contains(t, out, "db:5432/deck")
+}
+
+func TestRedactValue(t *testing.T) {
+	v := RedactValue(map[string]interface{}{
+		"gateway": map[string]interface{}{
+			"auth": map[string]interface{}{"token": "gw-token", "mode": "token"},
+		},
+		"channels": []interface{}{
+			map[string]interface{}{"botToken": "123", "note": "use Bearer xyz123abc"},
+		},
+		"port": float64(18789),
+	}).(map[string]interface{})
+
+	auth := v["gateway"].(map[string]interface{})["auth"].(map[string]interface{})
+	assert.Equal(t, Redacted, auth["token"])
+	assert.Equal(t, "token", auth["mode"])
+	ch := v["channels"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, Redacted, ch["botToken"])
+	assert.NotContains(t, ch["note"], "xyz123abc")
+	assert.Equal(t, float64(18789), v["port"])
+}
+
+func TestBundle_WriteZip(t *testing.T) {
+	b := NewBundle()
+	b.AddRawJSON("openclaw.json", []byte(`{"gateway":{"auth":{"password":"p4ss"}},"models":{"providers":{"custom":{"apiKey":"opaque-provider-value","api-key":"dashed-value"}}}}`))
+	b.AddText("deck.log", "request with api_key=abcdef123 done\n")
+	b.AddError("scan.json", errors.New("scan failed"))
+
+	var buf bytes.Buffer
+	require.NoError(t, b.WriteZip(&buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	require.Contains(t, files, "MANIFEST.txt")
+	assert.Contains(t, files["MANIFEST.txt"], "openclaw.json")
+	assert.Contains(t, files["MANIFEST.txt"], "errors.txt")
+	assert.NotContains(t, files["openclaw.json"], "p4ss")
+	assert.NotContains(t, files["openclaw.json"], "opaque-provider-value", "apiKey is redacted")
+	assert.NotContains(t, files["openclaw.json"], "dashed-value")
+	for _, k := range sensitiveKeys {
+		assert.Contains(t, files["MANIFEST.txt"], k, "manifest lists every redacted key")
+	}
+	assert.NotContains(t, files["deck.log"], "abcdef123")
+	assert.Contains(t, files["errors.txt"], "scan failed")
+}
+
+func TestRedactValue_SecretList(t *testing.T) {
+	v := RedactValue(map[string]interface{}{
+		"previous_secret_keys": []interface{}{"old-key-1", "old-key-2"},
+	}).(map[string]interface{})
+	assert.Equal(t, []interface{}{Redacted, Redacted}, v["previous_secret_keys"])
+}
@@ -4,14 +4,17 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
 
 // ActivityHandler manages activity events.
 type ActivityHandler struct {
 	activityRepo *database.ActivityRepo
+	gwClient     *openclaw.GWClient
 }
 
 func NewActivityHandler() *ActivityHandler {
@@ -20,6 +23,11 @@ func NewActivityHandler() *ActivityHandler {
 	}
 }
 
+// SetGWClient injects the Gateway client reference.
+func (h *ActivityHandler) SetGWClient(client *openclaw.GWClient) {
+	h.gwClient = client
+}
+
 // List returns activity events with pagination, filters, and search.
 func (h *ActivityHandler) List(w http.ResponseWriter, r *http.Request) {
 	pq := web.ParsePageQuery(r)
@@ -62,3 +70,53 @@ func (h *ActivityHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	web.OK(w, r, activity)
 }
+
+// maxTimelineDays bounds the timeline window.
+const maxTimelineDays = 90
+
+// Timeline returns activity counts per hour or day with risk and category
+// breakdowns, aggregated in the database instead of shipping raw rows.
+// Buckets follow the tz time zone (IANA name, default UTC).
+// GET /api/v1/activities/timeline?bucket=hour|day&days=7&tz=Asia/Shanghai
+func (h *ActivityHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	bucket := q.Get("bucket")
+	if bucket == "" {
+		bucket = database.TimelineHour
+	}
+	if bucket != database.TimelineHour && bucket != database.TimelineDay {
+		web.FailErr(w, r, web.ErrInvalidParam, "bucket must be hour or day")
+		return
+	}
+	days := 7
+	if v := q.Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxTimelineDays {
+			web.FailErr(w, r, web.ErrInvalidParam, "days must be 1-"+strconv.Itoa(maxTimelineDays))
+			return
+		}
+		days = n
+	}
+	loc := time.UTC
+	if tz := q.Get("tz"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			web.FailErr(w, r, web.ErrInvalidParam, "unknown time zone "+tz)
+			return
+		}
+		loc = l
+	}
+
+	until := time.Now()
+	since := until.AddDate(0, 0, -days)
+	buckets, err := h.activityRepo.Timeline(since, until, bucket, loc)
+	if err != nil {
+		web.FailErr(w, r, web.ErrDBQuery, err.Error())
+		return
+	}
+	web.OK(w, r, map[string]interface{}{
+		"bucket":   bucket,
+		"timezone": loc.String(),
+		"buckets":  buckets,
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"openclawdeck/internal/web"
+)
+
+const (
+	sessionActivitiesDefaultLimit = 500
+	sessionActivitiesMaxLimit     = 2000
+)
+
+// BySession returns the activities recorded for one gateway session, oldest
+// first, so they can be read alongside the session transcript. The gateway
+// identifies transcripts by session key rather than session id: the key is
+// taken from ?key= or looked up in sessions.list, and returned with a link
+// to /api/v1/gw/sessions/history. Activities are served from the deck
+// database even when the gateway is unreachable.
+// GET /api/v1/activities/by-session?sessionId=&key=&limit=
+func (h *ActivityHandler) BySession(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	sessionID := q.Get("sessionId")
+	if sessionID == "" {
+		web.FailErr(w, r, web.ErrInvalidParam, "sessionId is required")
+		return
+	}
+	limit := sessionActivitiesDefaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			web.FailErr(w, r, web.ErrInvalidParam, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, sessionActivitiesMaxLimit)
+	}
+
+	activities, total, err := h.activityRepo.ListBySession(sessionID, limit)
+	if err != nil {
+		web.FailErr(w, r, web.ErrAlertQueryFail)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"session_id": sessionID,
+		"activities": activities,
+		"total":      total,
+		"truncated":  int64(len(activities)) < total,
+	}
+	key := q.Get("key")
+	if key == "" && h.gwClient != nil && h.gwClient.IsConnected() {
+		if data, err := h.gwClient.Request("sessions.list", map[string]interface{}{}); err == nil {
+			key = sessionKeyFor(data, sessionID)
+		}
+	}
+	if key != "" {
+		resp["session_key"] = key
+		resp["history_url"] = "/api/v1/gw/sessions/history?key=" + url.QueryEscape(key)
+	}
+	web.OK(w, r, resp)
+}
+
+// sessionKeyFor finds the key of the session with the given id in a
+// sessions.list result; "" when it is not listed (e.g. already deleted).
+func sessionKeyFor(sessionsList json.RawMessage, sessionID string) string {
+	var result struct {
+		Sessions []struct {
+			Key       string `json:"key"`
+			SessionID string `json:"sessionId"`
+		} `json:"sessions"`
+	}
+	if json.Unmarshal(sessionsList, &result) != nil {
+		return ""
+	}
+	for _, s := range result.Sessions {
+		if s.SessionID == sessionID {
+			return s.Key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionKeyFor(t *testing.T) {
+	list := json.RawMessage(`{"sessions":[
+		{"key":"agent:main:main","sessionId":"a1"},
+		{"key":"agent:main:telegram:42","sessionId":"b2"}
+	]}`)
+	assert.Equal(t, "agent:main:telegram:42", sessionKeyFor(list, "b2"))
+	assert.Equal(t, "", sessionKeyFor(list, "gone"))
+	assert.Equal(t, "", sessionKeyFor(json.RawMessage(`[]`), "a1"))
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/web"
+)
+
+const (
+	loginSummaryDefaultDays = 30
+	loginSummaryMaxDays     = 365
+	// reverse DNS is bounded so a large summary can't stall the request
+	rdnsMaxLookups = 50
+	rdnsTimeout    = 3 * time.Second
+)
+
+// LoginUserStats are the login counts of one username from one IP.
+type LoginUserStats struct {
+	Username  string    `json:"username"`
+	Successes int64     `json:"successes"`
+	Failures  int64     `json:"failures"`
+	Locks     int64     `json:"locks"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// LoginIPSummary aggregates logins from one IP.
+type LoginIPSummary struct {
+	IP        string           `json:"ip"`
+	Hostnames []string         `json:"hostnames,omitempty"`
+	Successes int64            `json:"successes"`
+	Failures  int64            `json:"failures"`
+	Locks     int64            `json:"locks"`
+	FirstSeen time.Time        `json:"firstSeen"`
+	LastSeen  time.Time        `json:"lastSeen"`
+	Users     []LoginUserStats `json:"users"`
+}
+
+// LoginSummary groups login successes, failures and account locks by IP and
+// username, with counts and first/last seen.
+// GET /api/v1/audit-logs/login-summary?days=30&rdns=1
+func (h *AuditHandler) LoginSummary(w http.ResponseWriter, r *http.Request) {
+	days := loginSummaryDefaultDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > loginSummaryMaxDays {
+			web.FailErr(w, r, web.ErrInvalidParam, "days must be between 1 and 365")
+			return
+		}
+		days = n
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days)
+
+	rows, err := h.auditRepo.GroupByIPUserAction([]string{
+		constants.ActionLogin,
+		constants.ActionLoginFailed,
+		constants.ActionAccountLocked,
+	}, since)
+	if err != nil {
+		web.FailErr(w, r, web.ErrDBQuery)
+		return
+	}
+	summary := summarizeLogins(rows)
+	if v := r.URL.Query().Get("rdns"); v == "1" || v == "true" {
+		ctx, cancel := context.WithTimeout(r.Context(), rdnsTimeout)
+		resolveHostnames(ctx, summary)
+		cancel()
+	}
+
+	web.OK(w, r, map[string]interface{}{
+		"since": since,
+		"days":  days,
+		"ips":   summary,
+	})
+}
+
+// summarizeLogins merges grouped audit rows into per-IP summaries, most failures first.
+func summarizeLogins(rows []database.AuditGroupRow) []LoginIPSummary {
+	byIP := map[string]*LoginIPSummary{}
+	byUser := map[string]map[string]*LoginUserStats{}
+	widen := func(first, last *time.Time, rowFirst, rowLast time.Time) {
+		if first.IsZero() || (!rowFirst.IsZero() && rowFirst.Before(*first)) {
+			*first = rowFirst
+		}
+		if rowLast.After(*last) {
+			*last = rowLast
+		}
+	}
+	for _, row := range rows {
+		ip := row.IP
+		s := byIP[ip]
+		if s == nil {
+			s = &LoginIPSummary{IP: ip}
+			byIP[ip] = s
+			byUser[ip] = map[string]*LoginUserStats{}
+		}
+		u := byUser[ip][row.Username]
+		if u == nil {
+			u = &LoginUserStats{Username: row.Username}
+			byUser[ip][row.Username] = u
+		}
+		switch row.Action {
+		case constants.ActionLogin:
+			s.Successes += row.Count
+			u.Successes += row.Count
+		case constants.ActionLoginFailed:
+			s.Failures += row.Count
+			u.Failures += row.Count
+		case constants.ActionAccountLocked:
+			s.Locks += row.Count
+			u.Locks += row.Count
+		}
+		widen(&s.FirstSeen, &s.LastSeen, row.FirstSeen, row.LastSeen)
+		widen(&u.FirstSeen, &u.LastSeen, row.FirstSeen, row.LastSeen)
+	}
+
+	out := make([]LoginIPSummary, 0, len(byIP))
+	for ip, s := range byIP {
+		for _, u := range byUser[ip] {
+			s.Users = append(s.Users, *u)
+		}
+		sort.Slice(s.Users, func(i, j int) bool { return s.Users[i].LastSeen.After(s.Users[j].LastSeen) })
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Failures != out[j].Failures {
+			return out[i].Failures > out[j].Failures
+		}
+		return out[i].LastSeen.After(out[j].LastSeen)
+	})
+	return out
+}
+
+// resolveHostnames fills Hostnames via reverse DNS for the first rdnsMaxLookups IPs.
+func resolveHostnames(ctx context.Context, summary []LoginIPSummary) {
+	var wg sync.WaitGroup
+	for i := range summary {
+		if i >= rdnsMaxLookups {
+			break
+		}
+		if net.ParseIP(summary[i].IP) == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(s *LoginIPSummary) {
+			defer wg.Done()
+			names, err := net.DefaultResolver.LookupAddr(ctx, s.IP)
+			if err != nil {
+				return
+			}
+			for _, n := range names {
+				s.Hostnames = append(s.Hostnames, strings.TrimSuffix(n, "."))
+			}
+		}(&summary[i])
+	}
+	wg.Wait()
+}
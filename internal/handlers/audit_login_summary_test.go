@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeLogins(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []database.AuditGroupRow{
+		{IP: "10.0.0.1", Username: "admin", Action: constants.ActionLogin, Count: 3, FirstSeen: t0, LastSeen: t0.Add(time.Hour)},
+		{IP: "10.0.0.1", Username: "admin", Action: constants.ActionLoginFailed, Count: 1, FirstSeen: t0.Add(-time.Hour), LastSeen: t0},
+		{IP: "203.0.113.9", Username: "root", Action: constants.ActionLoginFailed, Count: 7, FirstSeen: t0, LastSeen: t0.Add(2 * time.Hour)},
+		{IP: "203.0.113.9", Username: "admin", Action: constants.ActionAccountLocked, Count: 1, FirstSeen: t0, LastSeen: t0},
+		{IP: "::1", Username: "admin", Action: constants.ActionLogin, Count: 1, FirstSeen: t0, LastSeen: t0},
+	}
+	got := summarizeLogins(rows)
+	require.Len(t, got, 3)
+
+	// most failures first
+	assert.Equal(t, "203.0.113.9", got[0].IP)
+	assert.Equal(t, int64(7), got[0].Failures)
+	assert.Equal(t, int64(1), got[0].Locks)
+	assert.Len(t, got[0].Users, 2)
+
+	assert.Equal(t, "10.0.0.1", got[1].IP)
+	assert.Equal(t, int64(3), got[1].Successes)
+	assert.Equal(t, int64(1), got[1].Failures)
+	assert.Equal(t, t0.Add(-time.Hour), got[1].FirstSeen)
+	assert.Equal(t, t0.Add(time.Hour), got[1].LastSeen)
+	require.Len(t, got[1].Users, 1)
+	assert.Equal(t, int64(3), got[1].Users[0].Successes)
+
+	assert.Equal(t, "::1", got[2].IP)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+// maxAuditChanges bounds how many changed paths go into one audit detail.
+const maxAuditChanges = 20
+
+// maxAuditValueLen bounds each value shown in an audit diff.
+const maxAuditValueLen = 60
+
+// auditMutation records a successful admin mutation for the requesting user.
+// Detail should name the affected resource and must not contain secrets; use
+// configChangeSummary / settingsChangeSummary to build redacted diffs.
+func auditMutation(r *http.Request, action, detail string) {
+	auditMutationResult(r, action, "success", detail)
+}
+
+// auditMutationResult is auditMutation with an explicit result ("success"/"failed").
+// Any mutation may change what the dashboard shows, so its cache is dropped.
+func auditMutationResult(r *http.Request, action, result, detail string) {
+	invalidateDashboard()
+	database.NewAuditLogRepo().Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   action,
+		Result:   result,
+		Detail:   diag.RedactText(detail),
+		IP:       web.ClientIP(r),
+	})
+}
+
+// configChangeSummary diffs next against prev (openclaw.DiffConfigLeaves) and
+// returns one line per changed leaf ("gateway.port: 18789 → 18790"). Values
+// under sensitive keys are never shown. Sections absent from next are
+// ignored, matching the merge done by ConfigHandler.Update.
+func configChangeSummary(prev, next map[string]interface{}) string {
+	var changes []string
+	for _, c := range openclaw.DiffConfigLeaves(prev, next) {
+		section, _, _ := strings.Cut(c.Path, ".")
+		if _, ok := next[section]; !ok {
+			continue
+		}
+		changes = append(changes, formatConfigChange(c))
+	}
+	return joinChanges(changes)
+}
+
+// settingsChangeSummary lists updated setting keys with redacted values.
+func settingsChangeSummary(items map[string]string) string {
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	changes := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if diag.IsSensitiveKey(k) {
+			changes = append(changes, k+": "+diag.Redacted)
+			continue
+		}
+		changes = append(changes, k+": "+truncateAuditValue(items[k]))
+	}
+	return joinChanges(changes)
+}
+
+func formatConfigChange(c openclaw.ConfigDiff) string {
+	switch {
+	case isSensitivePath(c.Path):
+		return c.Path + ": " + diag.Redacted
+	case c.New == nil:
+		return c.Path + ": removed"
+	case c.Old == nil:
+		return c.Path + ": added " + formatAuditValue(c.New)
+	default:
+		return c.Path + ": " + formatAuditValue(c.Old) + " → " + formatAuditValue(c.New)
+	}
+}
+
+// isSensitivePath reports whether any segment of a dotted path is a secret key.
+func isSensitivePath(path string) bool {
+	for _, seg := range strings.Split(path, ".") {
+		if isSensitiveKey(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatAuditValue renders v as JSON with nested secrets redacted. v is
+// round-tripped first because RedactKeys modifies maps in place.
+func formatAuditValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	var cp interface{}
+	if err := json.Unmarshal(data, &cp); err == nil {
+		if redacted, err := json.Marshal(diag.RedactKeys(cp)); err == nil {
+			data = redacted
+		}
+	}
+	return truncateAuditValue(string(data))
+}
+
+func truncateAuditValue(s string) string {
+	if len(s) > maxAuditValueLen {
+		return s[:maxAuditValueLen] + "…"
+	}
+	return s
+}
+
+func joinChanges(changes []string) string {
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	if len(changes) > maxAuditChanges {
+		more := len(changes) - maxAuditChanges
+		changes = append(changes[:maxAuditChanges:maxAuditChanges], fmt.Sprintf("... %d more", more))
+	}
+	return strings.Join(changes, "; ")
+}
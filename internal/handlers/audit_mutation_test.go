@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigChangeSummary(t *testing.T) {
+	prev := map[string]interface{}{
+		"gateway": map[string]interface{}{
+			"port": float64(18789),
+			"bind": "loopback",
+			"auth": map[string]interface{}{"mode": "token", "token": "old-secret"},
+		},
+		"agents": map[string]interface{}{"default": "main"},
+	}
+	next := map[string]interface{}{
+		"gateway": map[string]interface{}{
+			"port": float64(18790),
+			"auth": map[string]interface{}{"mode": "token", "token": "new-secret"},
+		},
+		"channels": map[string]interface{}{"telegram": map[string]interface{}{"botToken": "123:abc"}},
+	}
+
+	got := configChangeSummary(prev, next)
+	assert.Contains(t, got, "gateway.port: 18789 → 18790")
+	assert.Contains(t, got, "gateway.bind: removed")
+	assert.Contains(t, got, "gateway.auth.token: ***REDACTED***")
+	assert.Contains(t, got, "channels.telegram.botToken: ***REDACTED***", "added sections are reported per leaf")
+	assert.NotContains(t, got, "agents", "sections absent from the update are kept, not removed")
+	for _, secret := range []string{"old-secret", "new-secret", "123:abc"} {
+		assert.NotContains(t, got, secret)
+	}
+	// the update payload itself must not be redacted in place
+	assert.Equal(t, "new-secret", next["gateway"].(map[string]interface{})["auth"].(map[string]interface{})["token"])
+
+	assert.Equal(t, "no changes", configChangeSummary(prev, map[string]interface{}{"agents": prev["agents"]}))
+}
+
+func TestAdminMutationsAreAudited(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	t.Setenv("OPENCLAW_STATE_DIR", t.TempDir())
+
+	call := func(h http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(body))
+		req = web.SetUserInfo(req, 1, "admin", "admin")
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+
+	settings := NewSettingsHandler()
+	require.Equal(t, http.StatusOK, call(settings.Update, `{"session_idle_reset_enabled":"on","login_spike_threshold":"30","outbound_webhook_secret":"tok-123"}`).Code)
+	require.Equal(t, http.StatusOK, call(NewGatewayHandler(nil, nil).SetHealthCheck, `{"enabled":true}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(settings.Update, `{"session_idle_reset_enabled":"maybe"}`).Code)
+	require.Equal(t, http.StatusOK, call(NewConfigHandler().UpdateEnv, `{"set":{"OPENAI_API_KEY":"sk-x"}}`).Code)
+
+	logs, total, err := database.NewAuditLogRepo().List(database.AuditFilter{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+
+	details := map[string]string{}
+	for _, l := range logs {
+		assert.Equal(t, "admin", l.Username)
+		assert.Equal(t, "success", l.Result)
+		details[l.Action] += l.Detail + "\n"
+		assert.NotContains(t, l.Detail, "tok-123")
+		assert.NotContains(t, l.Detail, "sk-x")
+	}
+	assert.Contains(t, details[constants.ActionSettingsUpdate], "session_idle_reset_enabled: true")
+	assert.Contains(t, details[constants.ActionSettingsUpdate], "outbound_webhook_secret: "+diag.Redacted)
+	assert.Contains(t, details[constants.ActionSettingsUpdate], "health check")
+	assert.Contains(t, details[constants.ActionConfigUpdate], "OPENAI_API_KEY")
+}
+
+func TestConfigApplyChange_UsesRestClassification(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	body := `{"prev":{"gateway":{"port":18789,"auth":{"token":"old-tok"}}},` +
+		`"next":{"gateway":{"port":18790,"auth":{"token":"new-tok"}}}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/config/apply-change", bytes.NewBufferString(body))
+	req = web.SetUserInfo(req, 1, "admin", "admin")
+	w := httptest.NewRecorder()
+	NewConfigHandler().ApplyChange(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// no gateway service: a restart-class change is deferred, keys as in ClassifyConfigChange
+	assert.Contains(t, w.Body.String(), `"action":"deferred"`)
+	assert.Contains(t, w.Body.String(), "gateway.port")
+
+	logs, _, err := database.NewAuditLogRepo().List(database.AuditFilter{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Contains(t, logs[0].Detail, "gateway.port: 18789 → 18790")
+	assert.NotContains(t, logs[0].Detail, "new-tok")
+}
+
+func TestConfigAndGatewayMutationsAreAudited(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake openclaw CLI is a shell script")
+	}
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// a no-op openclaw CLI so set-key / unset-key / update reach the audit
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "openclaw"), []byte("#!/bin/sh\nexit 0\n"), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// remote service: lifecycle actions fail fast without touching local processes
+	svc := openclaw.NewService()
+	svc.GatewayHost = "198.51.100.7"
+	gw := NewGatewayHandler(svc, web.NewWSHub())
+	client := openclaw.NewGWClient(openclaw.GWClientConfig{Host: "127.0.0.1", Port: 1, Token: "old-tok"})
+	t.Cleanup(client.Stop)
+	settings := NewSettingsHandler()
+	settings.SetGWClient(client)
+	cfg := NewConfigHandler()
+
+	cases := []struct {
+		name   string
+		h      http.HandlerFunc
+		body   string
+		action string
+		result string
+		detail string
+	}{
+		{"config update", cfg.Update, `{"config":{"gateway":{"port":18790,"auth":{"token":"tok-123"}}}}`, constants.ActionConfigUpdate, "success", "gateway.port"},
+		{"set key", cfg.SetKey, `{"key":"channels.telegram.botToken","value":"tok-123","json":false}`, constants.ActionConfigUpdate, "success", "config set channels.telegram.botToken"},
+		{"unset key", cfg.UnsetKey, `{"key":"skills.entries.weather"}`, constants.ActionConfigUpdate, "success", "config unset skills.entries.weather"},
+		{"gateway start", gw.Start, ``, constants.ActionGatewayStart, "failed", ""},
+		{"gateway stop", gw.Stop, ``, constants.ActionGatewayStop, "failed", ""},
+		{"gateway restart", gw.Restart, ``, constants.ActionGatewayRestart, "failed", ""},
+		{"gateway kill", gw.Kill, `{"level":"term"}`, constants.ActionKillSwitch, "failed", "kill switch (level=term)"},
+		{"gateway connection", settings.UpdateGatewayConfig, `{"host":"127.0.0.1","port":1,"token":"tok-123"}`, constants.ActionSettingsUpdate, "success", "token changed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, database.DB.Where("1 = 1").Delete(&database.AuditLog{}).Error)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tc.body))
+			req = web.SetUserInfo(req, 1, "admin", "admin")
+			tc.h(httptest.NewRecorder(), req)
+
+			logs, _, err := database.NewAuditLogRepo().List(database.AuditFilter{Page: 1, PageSize: 10})
+			require.NoError(t, err)
+			require.Len(t, logs, 1)
+			assert.Equal(t, tc.action, logs[0].Action)
+			assert.Equal(t, tc.result, logs[0].Result)
+			assert.Equal(t, "admin", logs[0].Username)
+			assert.Contains(t, logs[0].Detail, tc.detail)
+			assert.NotContains(t, logs[0].Detail, "tok-123")
+		})
+	}
+}
+
+func TestConfigUpdate_RejectsUnparsableExistingConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".openclaw"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".openclaw", "openclaw.json"), []byte("{broken"), 0o600))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/config", bytes.NewBufferString(`{"config":{"agents":{}}}`))
+	w := httptest.NewRecorder()
+	NewConfigHandler().Update(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "CONFIG_READ_FAILED")
+
+	data, err := os.ReadFile(filepath.Join(home, ".openclaw", "openclaw.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{broken", string(data), "broken file is left for the user to fix")
+}
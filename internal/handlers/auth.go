@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/notify"
 	"openclawdeck/internal/web"
 	"openclawdeck/internal/webconfig"
 
@@ -19,18 +21,57 @@ const (
 	lockDuration      = 15 * time.Minute
 )
 
+// NotifyAccountLockedSetting enables a notification when an account gets locked
+// (off by default).
+const NotifyAccountLockedSetting = "notify_account_locked"
+
+func init() {
+	database.RegisterSettings(database.SettingDef{
+		Key:         NotifyAccountLockedSetting,
+		Type:        database.SettingBool,
+		Default:     "false",
+		Description: "notify when an account gets locked after failed logins",
+	})
+}
+
+// lockNotifyInterval throttles lockout notifications: at most one per interval,
+// across all accounts, so a brute-force run doesn't flood the channels.
+const lockNotifyInterval = 10 * time.Minute
+
 type AuthHandler struct {
-	userRepo  *database.UserRepo
-	auditRepo *database.AuditLogRepo
-	cfg       *webconfig.Config
+	userRepo    *database.UserRepo
+	auditRepo   *database.AuditLogRepo
+	settingRepo *database.SettingRepo
+	sessionRepo *database.AuthSessionRepo
+	notifier    *notify.Manager
+	cfg         *webconfig.Config
 }
 
 func NewAuthHandler(cfg *webconfig.Config) *AuthHandler {
 	return &AuthHandler{
-		userRepo:  database.NewUserRepo(),
-		auditRepo: database.NewAuditLogRepo(),
-		cfg:       cfg,
+		userRepo:    database.NewUserRepo(),
+		auditRepo:   database.NewAuditLogRepo(),
+		settingRepo: database.NewSettingRepo(),
+		sessionRepo: database.NewAuthSessionRepo(),
+		cfg:         cfg,
+	}
+}
+
+// SetNotifier enables lockout notifications through the given manager.
+func (h *AuthHandler) SetNotifier(mgr *notify.Manager) {
+	h.notifier = mgr
+}
+
+// notifyAccountLocked sends the lockout alert when enabled in settings.
+func (h *AuthHandler) notifyAccountLocked(username, ip string, failures int) {
+	if h.notifier == nil || !h.notifier.HasChannels() {
+		return
 	}
+	if !h.settingRepo.GetBool(NotifyAccountLockedSetting) {
+		return
+	}
+	text := fmt.Sprintf("🔒 Account %q locked for %s after %d failed logins from %s", username, lockDuration, failures, ip)
+	go h.notifier.SendThrottled(constants.ActionAccountLocked, lockNotifyInterval, "critical", text)
 }
 
 type loginRequest struct {
@@ -68,7 +109,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionLoginFailed,
 			Result:   "failed",
 			Detail:   "user not found",
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 		logger.Auth.Warn().Str("username", req.Username).Str("ip", r.RemoteAddr).Msg("login failed: user not found")
 		web.FailErr(w, r, web.ErrInvalidPassword)
@@ -83,7 +124,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionLoginFailed,
 			Result:   "failed",
 			Detail:   "account locked",
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 		logger.Auth.Warn().Str("username", req.Username).Str("ip", r.RemoteAddr).Msg("login failed: account locked")
 		web.FailErr(w, r, web.ErrAccountLocked)
@@ -99,7 +140,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionLoginFailed,
 			Result:   "failed",
 			Detail:   "wrong password",
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 		if user.FailedAttempts+1 >= maxFailedAttempts {
 			lockUntil := time.Now().UTC().Add(lockDuration)
@@ -110,9 +151,10 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 				Action:   constants.ActionAccountLocked,
 				Result:   "locked",
 				Detail:   "too many failed attempts",
-				IP:       r.RemoteAddr,
+				IP:       web.ClientIP(r),
 			})
 			logger.Auth.Warn().Str("username", req.Username).Str("ip", r.RemoteAddr).Msg("account locked")
+			h.notifyAccountLocked(user.Username, web.ClientIP(r), user.FailedAttempts+1)
 		}
 		logger.Auth.Warn().Str("username", req.Username).Str("ip", r.RemoteAddr).Msg("login failed: wrong password")
 		web.FailErr(w, r, web.ErrInvalidPassword)
@@ -122,8 +164,8 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	// Reset failed attempts
 	h.userRepo.ResetFailedAttempts(user.ID)
 
-	// Generate JWT
-	token, expiresAt, err := web.GenerateJWT(user.ID, user.Username, user.Role, h.cfg.Auth.JWTSecret, h.cfg.JWTExpireDuration())
+	// Generate JWT and register the session
+	token, expiresAt, err := h.issueSession(w, r, user)
 	if err != nil {
 		logger.Auth.Error().Err(err).Msg("JWT generation failed")
 		web.FailErr(w, r, web.ErrLoginFailed)
@@ -136,21 +178,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Username: user.Username,
 		Action:   constants.ActionLogin,
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Auth.Info().Str("username", user.Username).Str("ip", r.RemoteAddr).Msg("user logged in")
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "claw_token",
-		Value:    token,
-		Path:     "/",
-		Expires:  expiresAt,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-		// Secure:   true, // TODO: Enable in production with HTTPS
-	})
-
 	web.OK(w, r, loginResponse{
 		Token:     token,
 		ExpiresAt: expiresAt.Format(time.RFC3339),
@@ -210,7 +242,7 @@ func (h *AuthHandler) Setup(w http.ResponseWriter, r *http.Request) {
 		Username: user.Username,
 		Action:   constants.ActionSetup,
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Auth.Info().Str("username", user.Username).Msg("admin account created")
@@ -245,7 +277,7 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionPasswordChange,
 			Result:   "failed",
 			Detail:   "wrong old password",
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 		web.FailErr(w, r, web.ErrOldPasswordWrong)
 		return
@@ -259,12 +291,15 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 	h.userRepo.UpdatePassword(user.ID, string(hash))
 
+	h.logoutEverywhere(w, r, user)
+
 	h.auditRepo.Create(&database.AuditLog{
 		UserID:   user.ID,
 		Username: user.Username,
 		Action:   constants.ActionPasswordChange,
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		Detail:   "all other sessions revoked",
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Auth.Info().Str("username", user.Username).Msg("password changed")
@@ -300,7 +335,7 @@ func (h *AuthHandler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
 			Action:   "username_change",
 			Result:   "failed",
 			Detail:   "wrong password",
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 		web.FailErr(w, r, web.ErrInvalidPassword)
 		return
@@ -314,14 +349,16 @@ func (h *AuthHandler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
 
 	oldUsername := user.Username
 	h.userRepo.UpdateUsername(user.ID, req.NewUsername)
+	user.Username = req.NewUsername
+	h.logoutEverywhere(w, r, user)
 
 	h.auditRepo.Create(&database.AuditLog{
 		UserID:   user.ID,
 		Username: req.NewUsername,
 		Action:   "username_change",
 		Result:   "success",
-		Detail:   oldUsername + " -> " + req.NewUsername,
-		IP:       r.RemoteAddr,
+		Detail:   oldUsername + " -> " + req.NewUsername + ", all other sessions revoked",
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Auth.Info().Str("old", oldUsername).Str("new", req.NewUsername).Msg("username changed")
@@ -358,12 +395,15 @@ func (h *AuthHandler) NeedsSetup(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if jti := web.GetSessionID(r); jti != "" {
+		h.sessionRepo.Revoke(jti)
+	}
 	h.auditRepo.Create(&database.AuditLog{
 		UserID:   web.GetUserID(r),
 		Username: web.GetUsername(r),
 		Action:   constants.ActionLogout,
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 	http.SetCookie(w, &http.Cookie{
 		Name:     "claw_token",
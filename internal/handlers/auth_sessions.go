@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/web"
+)
+
+// sessionTouchInterval limits how often a session's last-seen time is written.
+const sessionTouchInterval = time.Minute
+
+// issueSession signs a token for user, registers it in the session registry and
+// sets the auth cookie.
+func (h *AuthHandler) issueSession(w http.ResponseWriter, r *http.Request, user *database.User) (string, time.Time, error) {
+	token, jti, expiresAt, err := web.GenerateSessionJWT(user.ID, user.Username, user.Role, user.TokenEpoch, h.cfg.Auth.JWTSecret, h.cfg.JWTExpireDuration())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now().UTC()
+	if err := h.sessionRepo.Create(&database.AuthSession{
+		JTI:        jti,
+		UserID:     user.ID,
+		Username:   user.Username,
+		IP:         web.ClientIP(r),
+		UserAgent:  r.UserAgent(),
+		ExpiresAt:  expiresAt,
+		LastSeenAt: now,
+	}); err != nil {
+		return "", time.Time{}, err
+	}
+	h.sessionRepo.DeleteExpired()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "claw_token",
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+		// Secure:   true, // TODO: Enable in production with HTTPS
+	})
+	return token, expiresAt, nil
+}
+
+// logoutEverywhere runs after a credential change that bumped the user's token
+// epoch: every existing token is already rejected by CheckSession, the sessions
+// are revoked so they leave the list, and the caller gets a fresh token.
+func (h *AuthHandler) logoutEverywhere(w http.ResponseWriter, r *http.Request, user *database.User) {
+	h.sessionRepo.RevokeUser(user.ID, "")
+	if fresh, err := h.userRepo.FindByID(user.ID); err == nil {
+		user = fresh
+	}
+	if _, _, err := h.issueSession(w, r, user); err != nil {
+		logger.Auth.Error().Err(err).Str("username", user.Username).Msg("re-issuing session after credential change failed")
+	}
+}
+
+// CheckSession verifies a validated token against the session registry: the
+// session must exist and not be revoked, and the token epoch must match the
+// user's current one. Registered via web.SetSessionCheckFunc.
+func (h *AuthHandler) CheckSession(claims *web.JWTClaims) error {
+	if claims.ID == "" {
+		return errors.New("token has no session id")
+	}
+	s, err := h.sessionRepo.FindByJTI(claims.ID)
+	if err != nil {
+		return errors.New("unknown session")
+	}
+	if s.RevokedAt != nil {
+		return errors.New("session revoked")
+	}
+	if s.UserID != claims.UserID {
+		return errors.New("session user mismatch")
+	}
+	user, err := h.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.TokenEpoch != claims.Epoch {
+		return errors.New("token issued before password change")
+	}
+	if now := time.Now().UTC(); now.Sub(s.LastSeenAt) > sessionTouchInterval {
+		h.sessionRepo.Touch(s.JTI, now)
+	}
+	return nil
+}
+
+// sessionInfo is one entry of the session list.
+type sessionInfo struct {
+	database.AuthSession
+	Current bool `json:"current"`
+}
+
+// ListSessions lists active sessions: all users' for admins, the caller's own otherwise.
+// GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := web.GetUserID(r)
+	if web.GetRole(r) == constants.RoleAdmin {
+		userID = 0
+	}
+	sessions, err := h.sessionRepo.ListActive(userID)
+	if err != nil {
+		web.FailErr(w, r, web.ErrDBQuery)
+		return
+	}
+	current := web.GetSessionID(r)
+	list := make([]sessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		list = append(list, sessionInfo{AuthSession: s, Current: s.JTI == current})
+	}
+	web.OK(w, r, list)
+}
+
+// RevokeSession revokes one session. Non-admins may only revoke their own.
+// DELETE /api/v1/auth/sessions/{jti}
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	jti := strings.TrimPrefix(r.URL.Path, "/api/v1/auth/sessions/")
+	if jti == "" || strings.Contains(jti, "/") {
+		web.FailErr(w, r, web.ErrInvalidParam)
+		return
+	}
+	s, err := h.sessionRepo.FindByJTI(jti)
+	if err != nil || (web.GetRole(r) != constants.RoleAdmin && s.UserID != web.GetUserID(r)) {
+		web.FailErr(w, r, web.ErrSessionNotFound)
+		return
+	}
+	if err := h.sessionRepo.Revoke(jti); err != nil {
+		web.FailErr(w, r, web.ErrDBQuery)
+		return
+	}
+
+	h.auditRepo.Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   constants.ActionSessionRevoke,
+		Result:   "success",
+		Detail:   "session of " + s.Username + " from " + s.IP,
+		IP:       web.ClientIP(r),
+	})
+	logger.Auth.Info().Str("by", web.GetUsername(r)).Str("user", s.Username).Msg("session revoked")
+	web.OK(w, r, map[string]string{"message": "ok"})
+}
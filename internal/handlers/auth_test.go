@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/web"
 	"openclawdeck/internal/webconfig"
 
 	"github.com/glebarez/sqlite"
@@ -31,6 +32,8 @@ func setupTestDB(t *testing.T) func() {
 	err = db.AutoMigrate(
 		&database.User{},
 		&database.AuditLog{},
+		&database.Setting{},
+		&database.AuthSession{},
 	)
 	require.NoError(t, err, "failed to migrate test database")
 
@@ -342,3 +345,125 @@ func TestLogout(t *testing.T) {
 	}
 	assert.True(t, found, "claw_token cookie should be set")
 }
+
+// ============== Session Registry Tests ==============
+
+func TestSessionRegistry(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	createTestUser(t, "admin", "password123")
+	cfg := testConfig()
+	handler := NewAuthHandler(cfg)
+
+	login := func() *web.JWTClaims {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(`{"username":"admin","password":"password123"}`))
+		w := httptest.NewRecorder()
+		handler.Login(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data struct {
+				Token string `json:"token"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		claims, err := web.ValidateJWT(resp.Data.Token, cfg.Auth.JWTSecret)
+		require.NoError(t, err)
+		return claims
+	}
+	asUser := func(req *http.Request, claims *web.JWTClaims) *http.Request {
+		req = web.SetUserInfo(req, claims.UserID, claims.Username, claims.Role)
+		return web.SetSessionID(req, claims.ID)
+	}
+
+	a, b := login(), login()
+	require.NotEmpty(t, a.ID)
+	assert.NoError(t, handler.CheckSession(a))
+	assert.NoError(t, handler.CheckSession(b))
+
+	// revoke one session
+	w := httptest.NewRecorder()
+	handler.RevokeSession(w, asUser(httptest.NewRequest(http.MethodDelete, "/api/v1/auth/sessions/"+b.ID, nil), a))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Error(t, handler.CheckSession(b))
+
+	// password change logs out every existing token
+	c := login()
+	w = httptest.NewRecorder()
+	handler.ChangePassword(w, asUser(httptest.NewRequest(http.MethodPut, "/api/v1/auth/password", bytes.NewBufferString(`{"old_password":"password123","new_password":"newpass123"}`)), a))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Error(t, handler.CheckSession(a))
+	assert.Error(t, handler.CheckSession(c))
+
+	// tokens without a registered session are rejected
+	token, _, err := web.GenerateJWT(a.UserID, "admin", "admin", cfg.Auth.JWTSecret, time.Hour)
+	require.NoError(t, err)
+	unregistered, err := web.ValidateJWT(token, cfg.Auth.JWTSecret)
+	require.NoError(t, err)
+	assert.Error(t, handler.CheckSession(unregistered))
+}
+
+func TestAuthMiddleware_RejectsTokenAfterCredentialChange(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	user := createTestUser(t, "admin", "password123")
+	cfg := testConfig()
+	handler := NewAuthHandler(cfg)
+	web.SetSessionCheckFunc(handler.CheckSession)
+	defer web.SetSessionCheckFunc(nil)
+
+	mw := web.AuthMiddleware(cfg.Auth.JWTSecret, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	call := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w
+	}
+	issue := func() (string, *web.JWTClaims) {
+		w := httptest.NewRecorder()
+		fresh, err := database.NewUserRepo().FindByID(user.ID)
+		require.NoError(t, err)
+		token, _, err := handler.issueSession(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil), fresh)
+		require.NoError(t, err)
+		claims, err := web.ValidateJWT(token, cfg.Auth.JWTSecret)
+		require.NoError(t, err)
+		return token, claims
+	}
+
+	oldToken, oldClaims := issue()
+	assert.Equal(t, http.StatusNoContent, call(oldToken).Code)
+
+	// password change: old token rejected
+	req := web.SetSessionID(web.SetUserInfo(httptest.NewRequest(http.MethodPut, "/api/v1/auth/password",
+		bytes.NewBufferString(`{"old_password":"password123","new_password":"newpass123"}`)), user.ID, "admin", "admin"), oldClaims.ID)
+	w := httptest.NewRecorder()
+	handler.ChangePassword(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := call(oldToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), "AUTH_TOKEN_REVOKED")
+
+	// username change: token issued in between is rejected too
+	midToken, midClaims := issue()
+	assert.Equal(t, http.StatusNoContent, call(midToken).Code)
+	req = web.SetSessionID(web.SetUserInfo(httptest.NewRequest(http.MethodPut, "/api/v1/auth/username",
+		bytes.NewBufferString(`{"new_username":"root","password":"newpass123"}`)), user.ID, "admin", "admin"), midClaims.ID)
+	w = httptest.NewRecorder()
+	handler.ChangeUsername(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, call(midToken).Code)
+
+	// the caller got a fresh cookie that works
+	var fresh string
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "claw_token" {
+			fresh = c.Value
+		}
+	}
+	require.NotEmpty(t, fresh)
+	assert.Equal(t, http.StatusNoContent, call(fresh).Code)
+}
@@ -13,45 +13,20 @@ import (
 
 	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
 	"openclawdeck/internal/logger"
 	"openclawdeck/internal/web"
 )
 
-// sensitiveKeys lists substrings that mark a JSON key as sensitive.
-var sensitiveKeys = []string{"token", "secret", "apikey", "api_key", "password", "dsn", "bottoken", "bot_token"}
+// isSensitiveKey reports whether a config key holds a secret.
+func isSensitiveKey(key string) bool {
+	return diag.IsSensitiveKey(key)
+}
 
-// redactSensitiveFields recursively walks a JSON structure and replaces
-// string values whose key (lowercased) contains any sensitiveKeys substring
-// with "***REDACTED***". Non-empty strings only.
+// redactSensitiveFields replaces non-empty string values under sensitive keys
+// with "***REDACTED***", in place.
 func redactSensitiveFields(v interface{}) interface{} {
-	switch val := v.(type) {
-	case map[string]interface{}:
-		for k, child := range val {
-			low := strings.ToLower(strings.ReplaceAll(k, "-", ""))
-			isSensitive := false
-			for _, sk := range sensitiveKeys {
-				if strings.Contains(low, sk) {
-					isSensitive = true
-					break
-				}
-			}
-			if isSensitive {
-				if s, ok := child.(string); ok && s != "" {
-					val[k] = "***REDACTED***"
-				}
-			} else {
-				val[k] = redactSensitiveFields(child)
-			}
-		}
-		return val
-	case []interface{}:
-		for i, item := range val {
-			val[i] = redactSensitiveFields(item)
-		}
-		return val
-	default:
-		return v
-	}
+	return diag.RedactKeys(v)
 }
 
 // BackupHandler manages backup operations.
@@ -122,7 +97,7 @@ func (h *BackupHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if err := os.WriteFile(destPath, srcData, 0o600); err != nil {
 		h.auditRepo.Create(&database.AuditLog{
 			UserID: web.GetUserID(r), Username: web.GetUsername(r),
-			Action: constants.ActionBackupCreate, Result: "failed", Detail: err.Error(), IP: r.RemoteAddr,
+			Action: constants.ActionBackupCreate, Result: "failed", Detail: err.Error(), IP: web.ClientIP(r),
 		})
 		web.FailErr(w, r, web.ErrBackupFailed, err.Error())
 		return
@@ -147,7 +122,7 @@ func (h *BackupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionBackupCreate,
 		Result:   "success",
 		Detail:   filename,
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Backup.Info().Str("file", filename).Str("trigger", req.Trigger).Msg("backup created")
@@ -208,7 +183,7 @@ func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
 	if err := os.WriteFile(destPath, backupData, 0o600); err != nil {
 		h.auditRepo.Create(&database.AuditLog{
 			UserID: web.GetUserID(r), Username: web.GetUsername(r),
-			Action: constants.ActionBackupRestore, Result: "failed", Detail: err.Error(), IP: r.RemoteAddr,
+			Action: constants.ActionBackupRestore, Result: "failed", Detail: err.Error(), IP: web.ClientIP(r),
 		})
 		web.FailErr(w, r, web.ErrBackupRestoreFail, err.Error())
 		return
@@ -220,7 +195,7 @@ func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionBackupRestore,
 		Result:   "success",
 		Detail:   record.Filename,
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Backup.Info().Str("file", record.Filename).Msg("backup restored")
@@ -254,7 +229,7 @@ func (h *BackupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.auditRepo.Create(&database.AuditLog{
 		UserID: web.GetUserID(r), Username: web.GetUsername(r),
-		Action: constants.ActionBackupDelete, Result: "success", Detail: record.Filename, IP: r.RemoteAddr,
+		Action: constants.ActionBackupDelete, Result: "success", Detail: record.Filename, IP: web.ClientIP(r),
 	})
 
 	logger.Backup.Info().Str("file", record.Filename).Msg("backup deleted")
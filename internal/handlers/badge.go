@@ -1,15 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/setup"
+	"openclawdeck/internal/updater"
 	"openclawdeck/internal/web"
 )
 
 // BadgeHandler provides desktop icon badge counts.
 type BadgeHandler struct {
-	alertRepo *database.AlertRepo
+	alertRepo  *database.AlertRepo
+	refreshing atomic.Bool
 }
 
 func NewBadgeHandler() *BadgeHandler {
@@ -19,10 +25,59 @@ func NewBadgeHandler() *BadgeHandler {
 }
 
 // Counts returns badge counts for each icon.
+// Update checks are cached; a stale cache is refreshed in the background so
+// polling never waits on GitHub or npm.
 func (h *BadgeHandler) Counts(w http.ResponseWriter, r *http.Request) {
+	h.refreshAsync()
+	web.OK(w, r, h.counts())
+}
+
+// Refresh forces the update checks to run now and returns fresh counts.
+// POST /api/v1/badges/refresh
+func (h *BadgeHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+
+	updater.CachedCheck(ctx, true)
+	setup.CachedOpenClawUpdate(true)
+	web.OK(w, r, h.counts())
+}
+
+func (h *BadgeHandler) counts() map[string]int64 {
 	unreadAlerts, _ := h.alertRepo.CountUnread()
 
-	web.OK(w, r, map[string]int64{
-		"alerts": unreadAlerts,
-	})
+	var selfUpdate, openclawUpdate int64
+	if res, _ := updater.LastCheck(); res != nil && res.Available {
+		selfUpdate = 1
+	}
+	if info := setup.LastOpenClawUpdate(); info != nil && info.UpdateAvailable {
+		openclawUpdate = 1
+	}
+
+	return map[string]int64{
+		"alerts":          unreadAlerts,
+		"settings":        selfUpdate + openclawUpdate,
+		"self_update":     selfUpdate,
+		"openclaw_update": openclawUpdate,
+	}
+}
+
+// refreshAsync refreshes stale update caches in a single background goroutine.
+func (h *BadgeHandler) refreshAsync() {
+	res, checked := updater.LastCheck()
+	oc := setup.LastOpenClawUpdate()
+	if res != nil && !res.Failed() && time.Since(checked) < updater.CheckCacheTTL &&
+		oc != nil && !oc.Failed() && time.Since(oc.CheckedAt) < setup.OpenClawUpdateTTL {
+		return
+	}
+	if !h.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer h.refreshing.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		updater.CachedCheck(ctx, false)
+		setup.CachedOpenClawUpdate(false)
+	}()
 }
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/monitor"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/version"
+	"openclawdeck/internal/web"
+	"openclawdeck/internal/webconfig"
+)
+
+// Feature flags reported by GET /api/v1/capabilities. Names are stable; new
+// features are added here, never renamed.
+const (
+	FeatureProxyStream      = "proxyStream"      // POST /api/v1/gw/proxy-stream
+	FeatureSessionsBulk     = "sessionsBulk"     // POST /api/v1/gw/sessions/bulk
+	FeatureAgentsWrite      = "agentsWrite"      // agent create/update/delete/enable
+	FeatureTemplates        = "templates"        // config templates
+	FeatureDiagBundle       = "diagBundle"       // GET /api/v1/diag/bundle
+	FeatureWSStats          = "wsStats"          // GET /api/v1/ws/stats
+	FeatureIdleSessionReset = "idleSessionReset" // idle session auto-reset (setting)
+	FeatureTwoFactor        = "twoFactor"        // not supported yet
+	FeatureGatewayWSS       = "gatewayWss"       // deck connects to the gateway over ws:// only
+	FeatureTLS              = "tls"              // deck serves HTTPS (cert, self-signed or ACME)
+	FeatureACME             = "acme"             // Let's Encrypt certificates
+)
+
+// Capabilities describes what the running deck supports.
+type Capabilities struct {
+	Version         string          `json:"version"`
+	Build           string          `json:"build"`
+	OS              string          `json:"os"`
+	Arch            string          `json:"arch"`
+	GoVersion       string          `json:"goVersion"`
+	OpenClawCompat  string          `json:"openclawCompat"`
+	GatewayProtocol ProtocolRange   `json:"gatewayProtocol"`
+	Features        map[string]bool `json:"features"`
+}
+
+// ProtocolRange is the gateway WS protocol range the deck speaks.
+type ProtocolRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// CapabilitiesHandler reports the deck version and supported features.
+type CapabilitiesHandler struct {
+	cfg         *webconfig.Config
+	settingRepo *database.SettingRepo
+}
+
+func NewCapabilitiesHandler(cfg *webconfig.Config) *CapabilitiesHandler {
+	return &CapabilitiesHandler{
+		cfg:         cfg,
+		settingRepo: database.NewSettingRepo(),
+	}
+}
+
+// Get returns the capabilities of the running deck.
+// GET /api/v1/capabilities
+func (h *CapabilitiesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	idleReset := h.settingRepo.GetBool(monitor.IdleResetEnabledSetting)
+	web.OK(w, r, buildCapabilities(h.cfg, r.TLS != nil, idleReset))
+}
+
+// buildCapabilities combines compile-time features with the runtime config.
+func buildCapabilities(cfg *webconfig.Config, servingTLS, idleReset bool) Capabilities {
+	tls := servingTLS || cfg.Server.ACMEDomain != "" || (cfg.Server.TLSCert != "" && cfg.Server.TLSKey != "")
+	return Capabilities{
+		Version:         version.Version,
+		Build:           version.Build,
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		GoVersion:       runtime.Version(),
+		OpenClawCompat:  version.OpenClawCompat,
+		GatewayProtocol: ProtocolRange{Min: openclaw.GWProtocolMin, Max: openclaw.GWProtocolMax},
+		Features: map[string]bool{
+			FeatureProxyStream:      true,
+			FeatureSessionsBulk:     true,
+			FeatureAgentsWrite:      true,
+			FeatureTemplates:        true,
+			FeatureDiagBundle:       true,
+			FeatureWSStats:          true,
+			FeatureIdleSessionReset: idleReset,
+			FeatureTwoFactor:        false,
+			FeatureGatewayWSS:       false,
+			FeatureTLS:              tls,
+			FeatureACME:             cfg.Server.ACMEDomain != "",
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"testing"
+
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/webconfig"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCapabilities(t *testing.T) {
+	cfg := webconfig.Default()
+	caps := buildCapabilities(&cfg, false, false)
+	assert.Equal(t, openclaw.GWProtocolMin, caps.GatewayProtocol.Min)
+	assert.True(t, caps.Features[FeatureProxyStream])
+	assert.False(t, caps.Features[FeatureTwoFactor])
+	assert.False(t, caps.Features[FeatureTLS])
+	assert.False(t, caps.Features[FeatureIdleSessionReset])
+
+	cfg.Server.ACMEDomain = "deck.example.com"
+	caps = buildCapabilities(&cfg, false, true)
+	assert.True(t, caps.Features[FeatureTLS])
+	assert.True(t, caps.Features[FeatureACME])
+	assert.True(t, caps.Features[FeatureIdleSessionReset])
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -15,7 +14,9 @@ import (
 	"time"
 
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/setup"
 	"openclawdeck/internal/web"
 )
 
@@ -33,6 +34,9 @@ type ClawHubHandler struct {
 	cacheMu     sync.RWMutex
 	cacheMap    map[string]*listCache
 	cacheTTL    time.Duration
+	integrity   *monitor.SkillIntegrity
+	// backoff overrides clawhubBackoff between registry retries (tests).
+	backoff func(attempt int) time.Duration
 }
 
 func NewClawHubHandler(gwClient *openclaw.GWClient) *ClawHubHandler {
@@ -47,12 +51,44 @@ func NewClawHubHandler(gwClient *openclaw.GWClient) *ClawHubHandler {
 	}
 }
 
+// SetSkillIntegrity records file hash baselines for skills installed or
+// updated through the deck.
+func (h *ClawHubHandler) SetSkillIntegrity(si *monitor.SkillIntegrity) {
+	h.integrity = si
+}
+
+// baselineSkills re-records the integrity baseline after a local install or
+// update; with no slugs every tracked skill is re-recorded.
+func (h *ClawHubHandler) baselineSkills(slugs ...string) {
+	if h.integrity == nil {
+		return
+	}
+	if len(slugs) == 0 {
+		tracked, err := h.integrity.Tracked()
+		if err != nil {
+			logger.Log.Warn().Err(err).Msg("list skill baselines failed")
+			return
+		}
+		slugs = tracked
+	}
+	for _, slug := range slugs {
+		if err := h.integrity.Baseline(slug); err != nil {
+			logger.Log.Warn().Err(err).Str("slug", slug).Msg("record skill baseline failed")
+		}
+	}
+}
+
 // isRemoteGateway checks if the connected gateway is remote.
 func (h *ClawHubHandler) isRemoteGateway() bool {
-	if h.gwClient == nil {
+	return isRemoteGWClient(h.gwClient)
+}
+
+// isRemoteGWClient reports whether the gateway client targets another host.
+func isRemoteGWClient(client *openclaw.GWClient) bool {
+	if client == nil {
 		return false
 	}
-	cfg := h.gwClient.GetConfig()
+	cfg := client.GetConfig()
 	host := strings.ToLower(strings.TrimSpace(cfg.Host))
 	if host == "" || host == "localhost" || host == "127.0.0.1" || host == "::1" {
 		return false
@@ -92,7 +128,8 @@ func (h *ClawHubHandler) remoteClawHubExec(action string, slug string, version s
 }
 
 // List lists ClawHub skills (proxied to avoid CORS, supports sort/pagination).
-// Results are cached in memory for 5 minutes to reduce upstream load.
+// Results are cached in memory for 5 minutes to reduce upstream load; transient
+// upstream failures are retried (see fetchRegistry).
 func (h *ClawHubHandler) List(w http.ResponseWriter, r *http.Request) {
 	limit := r.URL.Query().Get("limit")
 	if limit == "" {
@@ -120,23 +157,16 @@ func (h *ClawHubHandler) List(w http.ResponseWriter, r *http.Request) {
 		apiURL += "&cursor=" + url.QueryEscape(cursor)
 	}
 
-	resp, err := h.httpClient.Get(apiURL)
+	status, body, err := h.fetchRegistry(r.Context(), apiURL)
 	if err != nil {
 		logger.Log.Error().Err(err).Str("url", apiURL).Msg("ClawHub list request failed")
 		web.Fail(w, r, "CLAWHUB_LIST_FAILED", "ClawHub list failed: "+err.Error(), http.StatusBadGateway)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		web.Fail(w, r, "CLAWHUB_READ_FAILED", "failed to read response", http.StatusBadGateway)
-		return
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		logger.Log.Warn().Int("status", resp.StatusCode).Str("url", apiURL).Msg("ClawHub upstream non-200")
-		web.Fail(w, r, "CLAWHUB_UPSTREAM_ERROR", fmt.Sprintf("ClawHub returned %d", resp.StatusCode), http.StatusBadGateway)
+	if status != http.StatusOK {
+		logger.Log.Warn().Int("status", status).Str("url", apiURL).Msg("ClawHub upstream non-200")
+		web.Fail(w, r, "CLAWHUB_UPSTREAM_ERROR", fmt.Sprintf("ClawHub returned %d", status), http.StatusBadGateway)
 		return
 	}
 
@@ -181,23 +211,16 @@ func (h *ClawHubHandler) Search(w http.ResponseWriter, r *http.Request) {
 	h.cacheMu.RUnlock()
 
 	apiURL := fmt.Sprintf("%s/api/v1/search?q=%s&limit=%s", h.registryURL, url.QueryEscape(query), limit)
-	resp, err := h.httpClient.Get(apiURL)
+	status, body, err := h.fetchRegistry(r.Context(), apiURL)
 	if err != nil {
 		logger.Log.Error().Err(err).Str("url", apiURL).Msg("ClawHub search request failed")
 		web.Fail(w, r, "CLAWHUB_SEARCH_FAILED", "ClawHub search failed: "+err.Error(), http.StatusBadGateway)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		web.Fail(w, r, "CLAWHUB_READ_FAILED", "failed to read response", http.StatusBadGateway)
-		return
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		logger.Log.Warn().Int("status", resp.StatusCode).Str("url", apiURL).Msg("ClawHub search upstream non-200")
-		web.Fail(w, r, "CLAWHUB_UPSTREAM_ERROR", fmt.Sprintf("ClawHub returned %d", resp.StatusCode), http.StatusBadGateway)
+	if status != http.StatusOK {
+		logger.Log.Warn().Int("status", status).Str("url", apiURL).Msg("ClawHub search upstream non-200")
+		web.Fail(w, r, "CLAWHUB_UPSTREAM_ERROR", fmt.Sprintf("ClawHub returned %d", status), http.StatusBadGateway)
 		return
 	}
 
@@ -219,20 +242,117 @@ func (h *ClawHubHandler) SkillDetail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	apiURL := fmt.Sprintf("%s/api/v1/skills/%s", h.registryURL, url.PathEscape(slug))
-	resp, err := h.httpClient.Get(apiURL)
+	_, body, err := h.fetchRegistry(r.Context(), apiURL)
 	if err != nil {
 		web.Fail(w, r, "CLAWHUB_DETAIL_FAILED", "skill detail failed: "+err.Error(), http.StatusBadGateway)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	web.OKRaw(w, r, body)
+}
+
+// SkillRequirements checks a skill's declared runtime binaries against the
+// local environment and returns the missing ones with install commands.
+// GET /api/v1/clawhub/skill/requirements?slug=
+func (h *ClawHubHandler) SkillRequirements(w http.ResponseWriter, r *http.Request) {
+	slug := r.URL.Query().Get("slug")
+	if slug == "" {
+		web.Fail(w, r, "INVALID_PARAMS", "slug is required", http.StatusBadRequest)
+		return
+	}
+
+	apiURL := fmt.Sprintf("%s/api/v1/skills/%s", h.registryURL, url.PathEscape(slug))
+	status, body, err := h.fetchRegistry(r.Context(), apiURL)
 	if err != nil {
+		web.Fail(w, r, "CLAWHUB_DETAIL_FAILED", "skill detail failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if status != http.StatusOK {
+		web.Fail(w, r, "CLAWHUB_UPSTREAM_ERROR", fmt.Sprintf("ClawHub returned %d", status), http.StatusBadGateway)
+		return
+	}
+	var detail interface{}
+	if err := json.Unmarshal(body, &detail); err != nil {
 		web.Fail(w, r, "CLAWHUB_READ_FAILED", "failed to read response", http.StatusBadGateway)
 		return
 	}
 
-	web.OKRaw(w, r, body)
+	bins, anyBins := extractSkillBins(detail)
+	result := map[string]interface{}{
+		"slug":    slug,
+		"bins":    bins,
+		"anyBins": anyBins,
+	}
+
+	// tools are detected on this machine; a remote gateway runs skills elsewhere
+	if h.isRemoteGateway() {
+		result["checked"] = false
+		web.OK(w, r, result)
+		return
+	}
+
+	report := setup.CheckSkillRequirements(setup.DetectSkillEnv(), bins, anyBins)
+	result["checked"] = true
+	result["requirements"] = report.Requirements
+	result["missing"] = report.Missing
+	result["hardMissing"] = report.HardMissing
+	web.OK(w, r, result)
+}
+
+// extractSkillBins finds the first "requires" object carrying bins/anyBins in
+// ClawHub skill metadata. Skill frontmatter may embed metadata as a JSON
+// string, so string values that look like JSON objects are decoded too.
+func extractSkillBins(v interface{}) (bins, anyBins []string) {
+	bins, anyBins = []string{}, []string{}
+	var walk func(v interface{}) bool
+	walk = func(v interface{}) bool {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if req, ok := t["requires"].(map[string]interface{}); ok {
+				_, hasBins := req["bins"]
+				_, hasAny := req["anyBins"]
+				if hasBins || hasAny {
+					bins = append(bins, stringList(req["bins"])...)
+					anyBins = append(anyBins, stringList(req["anyBins"])...)
+					return true
+				}
+			}
+			for _, child := range t {
+				if walk(child) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, child := range t {
+				if walk(child) {
+					return true
+				}
+			}
+		case string:
+			if s := strings.TrimSpace(t); strings.HasPrefix(s, "{") {
+				var nested interface{}
+				if json.Unmarshal([]byte(s), &nested) == nil {
+					return walk(nested)
+				}
+			}
+		}
+		return false
+	}
+	walk(v)
+	return bins, anyBins
+}
+
+// stringList converts a JSON array of strings, skipping other values.
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
 // Install installs a ClawHub skill via clawhub CLI.
@@ -282,6 +402,7 @@ func (h *ClawHubHandler) Install(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.baselineSkills(params.Slug)
 	logger.Log.Info().Str("slug", params.Slug).Msg("skill installed")
 	web.OK(w, r, map[string]interface{}{
 		"slug":    params.Slug,
@@ -301,7 +422,7 @@ func (h *ClawHubHandler) Uninstall(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// safety check: slug must not contain path separators
-	if strings.ContainsAny(params.Slug, "/\\..") {
+	if !validSkillSlug(params.Slug) {
 		web.Fail(w, r, "INVALID_PARAMS", "invalid skill name", http.StatusBadRequest)
 		return
 	}
@@ -344,6 +465,11 @@ func (h *ClawHubHandler) Uninstall(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.removeLockEntry(home, params.Slug)
+	if h.integrity != nil {
+		if err := h.integrity.Forget(params.Slug); err != nil {
+			logger.Log.Warn().Err(err).Str("slug", params.Slug).Msg("remove skill baseline failed")
+		}
+	}
 
 	logger.Log.Info().Str("slug", params.Slug).Msg("skill uninstalled")
 	web.OK(w, r, map[string]interface{}{
@@ -402,6 +528,11 @@ func (h *ClawHubHandler) Update(w http.ResponseWriter, r *http.Request) {
 		web.Fail(w, r, "SKILL_UPDATE_FAILED", fmt.Sprintf("update failed: %s\n%s", err.Error(), output), http.StatusInternalServerError)
 		return
 	}
+	if params.All {
+		h.baselineSkills()
+	} else {
+		h.baselineSkills(params.Slug)
+	}
 
 	web.OK(w, r, map[string]interface{}{
 		"output":  output,
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"openclawdeck/internal/logger"
+)
+
+const (
+	// clawhubMaxAttempts bounds retries of idempotent registry GETs.
+	clawhubMaxAttempts = 3
+	// clawhubFetchBudget caps the total time spent on one registry GET,
+	// retries and backoff included.
+	clawhubFetchBudget = 30 * time.Second
+)
+
+// clawhubBackoff is the wait before retry n (0-based): 500ms, 1s, 2s, ...
+func clawhubBackoff(attempt int) time.Duration {
+	return 500 * time.Millisecond << attempt
+}
+
+// fetchRegistry GETs a ClawHub registry URL, retrying network errors,
+// timeouts and 5xx responses with exponential backoff. 4xx responses are
+// returned immediately. Retries stop when the request is cancelled or the
+// next wait would exceed clawhubFetchBudget; the last status and body are
+// returned either way.
+func (h *ClawHubHandler) fetchRegistry(ctx context.Context, apiURL string) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, clawhubFetchBudget)
+	defer cancel()
+
+	backoff := h.backoff
+	if backoff == nil {
+		backoff = clawhubBackoff
+	}
+	var (
+		status int
+		body   []byte
+		err    error
+	)
+	for attempt := 0; attempt < clawhubMaxAttempts; attempt++ {
+		if attempt > 0 {
+			wait := backoff(attempt - 1)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				break
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return status, body, errors.Join(err, ctx.Err())
+			}
+		}
+		status, body, err = h.getRegistry(ctx, apiURL)
+		if err == nil && status < 500 {
+			return status, body, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		logger.Log.Debug().Err(err).Int("status", status).Int("attempt", attempt+1).Str("url", apiURL).Msg("ClawHub request failed, retrying")
+	}
+	if err == nil && status >= 500 {
+		// a 5xx that survived every retry is still a response the caller reports
+		return status, body, nil
+	}
+	return status, body, err
+}
+
+// getRegistry performs a single GET and reads the whole body.
+func (h *ClawHubHandler) getRegistry(ctx context.Context, apiURL string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
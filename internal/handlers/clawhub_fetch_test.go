@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClawHub(t *testing.T, handler http.HandlerFunc) *ClawHubHandler {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	h := NewClawHubHandler(nil)
+	h.registryURL = srv.URL
+	h.backoff = func(int) time.Duration { return time.Millisecond }
+	return h
+}
+
+func TestClawHubListRetriesFlakyUpstream(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestClawHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"items":[{"slug":"weather"}]}`))
+	})
+
+	rec := httptest.NewRecorder()
+	h.List(rec, httptest.NewRequest(http.MethodGet, "/api/v1/clawhub/list", nil))
+
+	assert.Equal(t, int32(2), calls.Load())
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.JSONEq(t, `{"items":[{"slug":"weather"}]}`, string(resp.Data))
+}
+
+func TestClawHubFetchDoesNotRetry4xx(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestClawHub(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	})
+
+	status, _, err := h.fetchRegistry(t.Context(), h.registryURL+"/api/v1/skills/missing")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClawHubFetchGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestClawHub(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	status, _, err := h.fetchRegistry(t.Context(), h.registryURL+"/api/v1/skills")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, int32(clawhubMaxAttempts), calls.Load())
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"openclawdeck/internal/logger"
@@ -40,8 +42,18 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// local installs are killed on cancel or timeout; the client may keep
+	// reading after a disconnect, so the task is not bound to r.Context()
+	remote := h.isRemoteGateway()
+	taskID, ctx, done := runningInstalls.start(context.Background(), clawhubInstallTimeout)
+	defer done()
+	w.Header().Set(installTaskHeader, taskID)
+
+	var sseMu sync.Mutex
 	sendSSE := func(eventType string, data map[string]interface{}) {
 		payload, _ := json.Marshal(data)
+		sseMu.Lock()
+		defer sseMu.Unlock()
 		fmt.Fprintf(w, "data: %s\n\n", payload)
 		flusher.Flush()
 	}
@@ -53,7 +65,7 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 	})
 
 	// remote gateway: via JSON-RPC (non-streaming, push start/end events)
-	if h.isRemoteGateway() {
+	if remote {
 		sendSSE("log", map[string]interface{}{
 			"type":    "log",
 			"message": "remote gateway mode, waiting for install to complete...",
@@ -108,21 +120,19 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 	skillsDir := filepath.Join(home, ".openclaw", "skills")
 	os.MkdirAll(skillsDir, 0755)
 
-	cmd := exec.Command(cmdName, args...)
+	cmd := exec.CommandContext(ctx, cmdName, args...)
 	cmd.Env = append(os.Environ(), "CLAWHUB_DISABLE_TELEMETRY=1")
 	cmd.Dir = skillsDir
 
-	// merge stdout + stderr
-	stdoutPipe, err := cmd.StdoutPipe()
+	stdoutPipe, stderrPipe, err := commandPipes(cmd)
 	if err != nil {
 		sendSSE("error", map[string]interface{}{
 			"type":    "error",
-			"message": "failed to create stdout pipe: " + err.Error(),
+			"message": "failed to create output pipes: " + err.Error(),
 			"ts":      time.Now().UnixMilli(),
 		})
 		return
 	}
-	cmd.Stderr = cmd.Stdout
 
 	if err := cmd.Start(); err != nil {
 		// clawhub not in PATH, try npx
@@ -133,7 +143,7 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 				"message": "clawhub not found, trying npx ...",
 				"ts":      time.Now().UnixMilli(),
 			})
-			h.installStreamViaNpx(w, flusher, sendSSE, args, skillsDir, params.Slug)
+			h.installStreamViaNpx(ctx, sendSSE, args, skillsDir, params.Slug)
 			return
 		}
 		sendSSE("error", map[string]interface{}{
@@ -144,12 +154,13 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	h.streamOutput(stdoutPipe, sendSSE)
+	stderrTail := h.streamOutput(stdoutPipe, stderrPipe, sendSSE)
 
 	exitErr := cmd.Wait()
 	success := exitErr == nil
 
 	if success {
+		h.baselineSkills(params.Slug)
 		sendSSE("done", map[string]interface{}{
 			"type":    "done",
 			"message": "install complete",
@@ -160,8 +171,9 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 	} else {
 		sendSSE("error", map[string]interface{}{
 			"type":    "error",
-			"message": "install failed: " + exitErr.Error(),
+			"message": installFailedMessage(ctx, "install failed: "+exitErr.Error()),
 			"slug":    params.Slug,
+			"stderr":  stderrTail,
 			"ts":      time.Now().UnixMilli(),
 		})
 	}
@@ -170,13 +182,13 @@ func (h *ClawHubHandler) InstallStreamSSE(w http.ResponseWriter, r *http.Request
 }
 
 // installStreamViaNpx runs clawhub install via npx (streaming).
-func (h *ClawHubHandler) installStreamViaNpx(w http.ResponseWriter, flusher http.Flusher, sendSSE func(string, map[string]interface{}), args []string, skillsDir string, slug string) {
+func (h *ClawHubHandler) installStreamViaNpx(ctx context.Context, sendSSE func(string, map[string]interface{}), args []string, skillsDir string, slug string) {
 	npxArgs := append([]string{"clawhub"}, args...)
-	cmd := exec.Command("npx", npxArgs...)
+	cmd := exec.CommandContext(ctx, "npx", npxArgs...)
 	cmd.Env = append(os.Environ(), "CLAWHUB_DISABLE_TELEMETRY=1")
 	cmd.Dir = skillsDir
 
-	stdoutPipe, err := cmd.StdoutPipe()
+	stdoutPipe, stderrPipe, err := commandPipes(cmd)
 	if err != nil {
 		sendSSE("error", map[string]interface{}{
 			"type":    "error",
@@ -185,7 +197,6 @@ func (h *ClawHubHandler) installStreamViaNpx(w http.ResponseWriter, flusher http
 		})
 		return
 	}
-	cmd.Stderr = cmd.Stdout
 
 	if err := cmd.Start(); err != nil {
 		sendSSE("error", map[string]interface{}{
@@ -196,12 +207,13 @@ func (h *ClawHubHandler) installStreamViaNpx(w http.ResponseWriter, flusher http
 		return
 	}
 
-	h.streamOutput(stdoutPipe, sendSSE)
+	stderrTail := h.streamOutput(stdoutPipe, stderrPipe, sendSSE)
 
 	exitErr := cmd.Wait()
 	success := exitErr == nil
 
 	if success {
+		h.baselineSkills(slug)
 		sendSSE("done", map[string]interface{}{
 			"type":    "done",
 			"message": "install complete",
@@ -212,25 +224,82 @@ func (h *ClawHubHandler) installStreamViaNpx(w http.ResponseWriter, flusher http
 	} else {
 		sendSSE("error", map[string]interface{}{
 			"type":    "error",
-			"message": "install failed: " + exitErr.Error(),
+			"message": installFailedMessage(ctx, "install failed: "+exitErr.Error()),
 			"slug":    slug,
+			"stderr":  stderrTail,
 			"ts":      time.Now().UnixMilli(),
 		})
 	}
 }
 
-// streamOutput reads pipe line by line and pushes SSE events.
-func (h *ClawHubHandler) streamOutput(pipe io.Reader, sendSSE func(string, map[string]interface{})) {
-	scanner := bufio.NewScanner(pipe)
-	scanner.Buffer(make([]byte, 64*1024), 64*1024)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line = strings.TrimSpace(line); line != "" {
+// clawhubInstallTimeout bounds a local clawhub install.
+const clawhubInstallTimeout = 10 * time.Minute
+
+// CancelInstall cancels a running skill or dependency install by task ID.
+// POST /api/v1/clawhub/cancel-install
+func (h *ClawHubHandler) CancelInstall(w http.ResponseWriter, r *http.Request) {
+	cancelInstallTask(w, r)
+}
+
+// stderrTailLines is how many trailing stderr lines are attached to an error event.
+const stderrTailLines = 10
+
+// commandPipes returns separate stdout and stderr pipes for cmd.
+func commandPipes(cmd *exec.Cmd) (io.Reader, io.Reader, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// streamOutput reads both pipes line by line and pushes SSE log events tagged
+// with their stream. It returns the last few stderr lines.
+func (h *ClawHubHandler) streamOutput(stdout, stderr io.Reader, sendSSE func(string, map[string]interface{})) []string {
+	var tail []string
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		scanLines(stdout, func(line string) {
+			sendSSE("log", map[string]interface{}{
+				"type":    "log",
+				"message": line,
+				"stream":  "stdout",
+				"ts":      time.Now().UnixMilli(),
+			})
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		scanLines(stderr, func(line string) {
+			tail = append(tail, line)
+			if len(tail) > stderrTailLines {
+				tail = tail[len(tail)-stderrTailLines:]
+			}
 			sendSSE("log", map[string]interface{}{
 				"type":    "log",
 				"message": line,
+				"stream":  "stderr",
 				"ts":      time.Now().UnixMilli(),
 			})
+		})
+	}()
+	wg.Wait()
+	return tail
+}
+
+// scanLines calls fn for each non-empty trimmed line read from r.
+func scanLines(r io.Reader, fn func(line string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			fn(line)
 		}
 	}
 }
@@ -258,6 +327,10 @@ func (h *GWProxyHandler) DepInstallStreamSSE(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// not bound to r.Context(): the install keeps running if the client disconnects
+	taskID, ctx, done := runningInstalls.start(context.Background(), 5*time.Minute)
+	w.Header().Set(installTaskHeader, taskID)
+
 	sendSSE := func(eventType string, data map[string]interface{}) {
 		payload, _ := json.Marshal(data)
 		fmt.Fprintf(w, "data: %s\n\n", payload)
@@ -289,7 +362,8 @@ func (h *GWProxyHandler) DepInstallStreamSSE(w http.ResponseWriter, r *http.Requ
 	}
 	resultCh := make(chan rpcResult, 1)
 	go func() {
-		data, err := h.client.RequestWithTimeout("skills.install", rpcParams, 5*time.Minute)
+		defer done()
+		data, err := h.client.RequestWithContext(ctx, "skills.install", rpcParams)
 		resultCh <- rpcResult{data, err}
 	}()
 
@@ -304,7 +378,7 @@ waitLoop:
 			if res.err != nil {
 				sendSSE("error", map[string]interface{}{
 					"type":    "error",
-					"message": "install failed: " + res.err.Error(),
+					"message": installFailedMessage(ctx, "install failed: "+res.err.Error()),
 					"ts":      time.Now().UnixMilli(),
 				})
 				return
@@ -402,8 +476,10 @@ func (h *GWProxyHandler) DepInstallAsync(w http.ResponseWriter, r *http.Request)
 	}
 
 	// run install in background
+	taskID, ctx, done := runningInstalls.start(context.Background(), 5*time.Minute)
 	go func() {
-		data, err := h.client.RequestWithTimeout("skills.install", rpcParams, 5*time.Minute)
+		defer done()
+		data, err := h.client.RequestWithContext(ctx, "skills.install", rpcParams)
 		if err != nil {
 			logger.Log.Error().Err(err).Str("name", params.Name).Msg("background skill dep install failed")
 			return
@@ -424,5 +500,6 @@ func (h *GWProxyHandler) DepInstallAsync(w http.ResponseWriter, r *http.Request)
 		"ok":      true,
 		"message": "install submitted, poll skills.status for result",
 		"name":    params.Name,
+		"taskId":  taskID,
 	})
 }
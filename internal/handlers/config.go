@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"openclawdeck/internal/constants"
-	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
 	"openclawdeck/internal/logger"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
@@ -15,13 +19,50 @@ import (
 
 // ConfigHandler manages OpenClaw config read/write.
 type ConfigHandler struct {
-	auditRepo *database.AuditLogRepo
+	svc *openclaw.Service
 }
 
 func NewConfigHandler() *ConfigHandler {
-	return &ConfigHandler{
-		auditRepo: database.NewAuditLogRepo(),
+	return &ConfigHandler{}
+}
+
+// SetService injects the gateway service used to apply saved config.
+func (h *ConfigHandler) SetService(svc *openclaw.Service) {
+	h.svc = svc
+}
+
+// applyGatewayConfig makes a just-written openclaw.json take effect with the
+// least disruptive action (see openclaw.ClassifyConfigChange). prev is the
+// config before the write; the result is returned to the client as "apply".
+func applyGatewayConfig(svc *openclaw.Service, prev map[string]interface{}) openclaw.ConfigApply {
+	return applyGatewayConfigChange(svc, prev, readConfigSnapshot())
+}
+
+// applyGatewayConfigChange is applyGatewayConfig for a config written elsewhere
+// (e.g. over the gateway WebSocket), where both sides are known to the caller.
+func applyGatewayConfigChange(svc *openclaw.Service, prev, next map[string]interface{}) openclaw.ConfigApply {
+	invalidateDashboard()
+	if svc == nil {
+		action, keys := openclaw.ClassifyConfigChange(prev, next)
+		if action != openclaw.ApplyNone {
+			action = openclaw.ApplyDeferred
+		}
+		return openclaw.ConfigApply{Action: action, Keys: keys}
 	}
+	result := svc.ApplyConfigChange(prev, next)
+	if result.Error != "" {
+		logger.Config.Warn().Str("action", result.Action).Str("error", result.Error).Msg("failed to apply config to gateway")
+	}
+	return result
+}
+
+// readConfigSnapshot reads openclaw.json before a write (empty map if missing).
+func readConfigSnapshot() map[string]interface{} {
+	prev := make(map[string]interface{})
+	if data, err := os.ReadFile(configPath()); err == nil {
+		json.Unmarshal(data, &prev)
+	}
+	return prev
 }
 
 // configPath returns the OpenClaw config file path.
@@ -69,6 +110,93 @@ func (h *ConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Lint runs the doctor's config checks against the current openclaw.json and
+// returns the issues (deprecated keys, insecure bind, missing token, ...).
+// GET /api/v1/config/lint
+func (h *ConfigHandler) Lint(w http.ResponseWriter, r *http.Request) {
+	path := configPath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	issues := openclaw.LintConfigFile(path)
+	hasErrors := false
+	for _, issue := range issues {
+		if issue.Level == openclaw.IssueError {
+			hasErrors = true
+			break
+		}
+	}
+	web.OK(w, r, map[string]interface{}{
+		"path":       path,
+		"issues":     issues,
+		"has_errors": hasErrors,
+	})
+}
+
+// Migrate applies the doctor's safe config fixes (deprecated gateway.auth.enabled,
+// missing mode/bind/port, token auth for non-loopback binds) after backing up
+// the current file. Returns the list of changes; secret values are redacted.
+// POST /api/v1/config/migrate
+func (h *ConfigHandler) Migrate(w http.ResponseWriter, r *http.Request) {
+	path := configPath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			web.FailErr(w, r, web.ErrConfigNotFound)
+			return
+		}
+		web.FailErr(w, r, web.ErrConfigReadFailed)
+		return
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
+	}
+
+	changes := openclaw.MigrateConfig(raw)
+	if len(changes) == 0 {
+		web.OK(w, r, map[string]interface{}{"changed": false, "changes": changes})
+		return
+	}
+
+	backupPath, err := openclaw.BackupConfigFile(path)
+	if err != nil {
+		web.FailErr(w, r, web.ErrBackupFailed, err.Error())
+		return
+	}
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+		return
+	}
+	if err := openclaw.WriteFileAtomic(path, append(out, '\n')); err != nil {
+		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+		return
+	}
+
+	paths := make([]string, 0, len(changes))
+	for i := range changes {
+		paths = append(paths, changes[i].Path)
+		if isSensitiveKey(changes[i].Path[strings.LastIndex(changes[i].Path, ".")+1:]) {
+			changes[i].New = "***REDACTED***"
+		}
+	}
+	auditMutation(r, constants.ActionConfigUpdate, "config migrate: "+strings.Join(paths, ", "))
+	logger.Config.Info().Str("user", web.GetUsername(r)).Strs("changes", paths).Str("backup", backupPath).Msg("deprecated config migrated")
+
+	web.OK(w, r, map[string]interface{}{
+		"changed": true,
+		"changes": changes,
+		"backup":  backupPath,
+	})
+}
+
 // Update updates the OpenClaw config (via openclaw config set for safe writes).
 func (h *ConfigHandler) Update(w http.ResponseWriter, r *http.Request) {
 	path := configPath()
@@ -90,11 +218,28 @@ func (h *ConfigHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// diff against the current file for the audit trail (secrets redacted);
+	// an unreadable file would be silently replaced by the merge below
+	prev := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
+	}
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &prev); err != nil {
+			web.FailErr(w, r, web.ErrConfigReadFailed, "existing config is not valid JSON: "+err.Error())
+			return
+		}
+	}
+	summary := "config update: " + configChangeSummary(prev, req.Config)
+
 	// prefer openclaw CLI for safe writes
 	if openclaw.IsOpenClawInstalled() {
 		if err := openclaw.ConfigApplyFull(req.Config); err != nil {
 			logger.Config.Warn().Err(err).Msg("openclaw config set failed, falling back to direct write")
 			if writeErr := h.writeConfigDirect(path, req.Config); writeErr != nil {
+				auditMutationResult(r, constants.ActionConfigUpdate, "failed", summary+": "+writeErr.Error())
 				web.FailErr(w, r, web.ErrConfigWriteFailed, writeErr.Error())
 				return
 			}
@@ -102,22 +247,41 @@ func (h *ConfigHandler) Update(w http.ResponseWriter, r *http.Request) {
 	} else {
 		// openclaw not installed, write directly
 		if err := h.writeConfigDirect(path, req.Config); err != nil {
+			auditMutationResult(r, constants.ActionConfigUpdate, "failed", summary+": "+err.Error())
 			web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
 			return
 		}
 	}
 
-	// audit log
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   constants.ActionConfigUpdate,
-		Result:   "success",
-		IP:       r.RemoteAddr,
-	})
+	auditMutation(r, constants.ActionConfigUpdate, summary)
+
+	apply := applyGatewayConfig(h.svc, prev)
+	logger.Config.Info().Str("user", web.GetUsername(r)).Str("path", path).Str("apply", apply.Action).Msg("OpenClaw config updated")
+	web.OK(w, r, map[string]interface{}{"message": "ok", "apply": apply})
+}
+
+// ApplyChange applies a config the editor already wrote through the gateway
+// (config.set over WebSocket), using the same reload/restart classification
+// as Update. prev is the config the editor loaded before its changes.
+// POST /api/v1/config/apply-change
+func (h *ConfigHandler) ApplyChange(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Prev map[string]interface{} `json:"prev"`
+		Next map[string]interface{} `json:"next"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if req.Next == nil {
+		web.FailErr(w, r, web.ErrConfigEmpty)
+		return
+	}
 
-	logger.Config.Info().Str("user", web.GetUsername(r)).Str("path", path).Msg("OpenClaw config updated")
-	web.OK(w, r, map[string]string{"message": "ok"})
+	auditMutation(r, constants.ActionConfigUpdate, "config update via gateway: "+configChangeSummary(req.Prev, req.Next))
+	apply := applyGatewayConfigChange(h.svc, req.Prev, req.Next)
+	logger.Config.Info().Str("user", web.GetUsername(r)).Str("apply", apply.Action).Msg("OpenClaw config saved via gateway")
+	web.OK(w, r, map[string]interface{}{"message": "ok", "apply": apply})
 }
 
 // writeConfigDirect writes config file directly (fallback).
@@ -133,30 +297,11 @@ func (h *ConfigHandler) writeConfigDirect(path string, config map[string]interfa
 		existing[k] = v
 	}
 
-	// atomic write: write temp file then rename
 	data, err := json.MarshalIndent(existing, "", "  ")
 	if err != nil {
 		return err
 	}
-	data = append(data, '\n')
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return err
-	}
-
-	tmpFile := path + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
-		return err
-	}
-
-	if err := os.Rename(tmpFile, path); err != nil {
-		// Windows fallback: copy
-		os.WriteFile(path, data, 0o600)
-		os.Remove(tmpFile)
-	}
-
-	return nil
+	return openclaw.WriteFileAtomic(path, append(data, '\n'))
 }
 
 // SetKey sets a single config key.
@@ -189,19 +334,21 @@ func (h *ConfigHandler) SetKey(w http.ResponseWriter, r *http.Request) {
 		err = openclaw.ConfigSetString(req.Key, req.Value)
 	}
 
+	detail := "config set " + req.Key + " = " + truncateAuditValue(req.Value)
+	var parsed interface{}
+	if req.JSON && json.Unmarshal([]byte(req.Value), &parsed) == nil {
+		detail = "config set " + req.Key + " = " + formatAuditValue(parsed)
+	}
+	if isSensitivePath(req.Key) {
+		detail = "config set " + req.Key + " = " + diag.Redacted
+	}
 	if err != nil {
+		auditMutationResult(r, constants.ActionConfigUpdate, "failed", detail+": "+err.Error())
 		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
 		return
 	}
 
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   constants.ActionConfigUpdate,
-		Result:   "success",
-		Detail:   "config set " + req.Key,
-		IP:       r.RemoteAddr,
-	})
+	auditMutation(r, constants.ActionConfigUpdate, detail)
 
 	logger.Config.Info().Str("user", web.GetUsername(r)).Str("key", req.Key).Msg("config key updated")
 	web.OK(w, r, map[string]string{"message": "ok", "key": req.Key})
@@ -229,18 +376,12 @@ func (h *ConfigHandler) UnsetKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := openclaw.ConfigUnset(req.Key); err != nil {
+		auditMutationResult(r, constants.ActionConfigUpdate, "failed", "config unset "+req.Key+": "+err.Error())
 		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
 		return
 	}
 
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   constants.ActionConfigUpdate,
-		Result:   "success",
-		Detail:   "config unset " + req.Key,
-		IP:       r.RemoteAddr,
-	})
+	auditMutation(r, constants.ActionConfigUpdate, "config unset "+req.Key)
 
 	logger.Config.Info().Str("user", web.GetUsername(r)).Str("key", req.Key).Msg("config key removed")
 	web.OK(w, r, map[string]string{"message": "ok", "key": req.Key})
@@ -269,7 +410,9 @@ func (h *ConfigHandler) GetKey(w http.ResponseWriter, r *http.Request) {
 	web.OK(w, r, map[string]interface{}{"key": key, "value": json.RawMessage(value)})
 }
 
-// GenerateDefault generates a default config file via openclaw CLI.
+// GenerateDefault writes a minimal safe config: local mode, default port and
+// token auth. ?bind= selects the bind mode (default loopback; lan/tailnet/auto
+// for remote-ready configs). The freshly generated token is returned only once.
 // POST /api/v1/config/generate-default
 func (h *ConfigHandler) GenerateDefault(w http.ResponseWriter, r *http.Request) {
 	path := configPath()
@@ -284,26 +427,33 @@ func (h *ConfigHandler) GenerateDefault(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if !openclaw.IsOpenClawInstalled() {
-		web.FailErr(w, r, web.ErrOpenClawNotInstalled)
+	bind := strings.TrimSpace(r.URL.Query().Get("bind"))
+	if bind == "" {
+		bind = "loopback"
+	}
+	if !openclaw.IsValidGatewayBind(bind) {
+		web.FailErr(w, r, web.ErrInvalidParam, "bind must be one of "+strings.Join(openclaw.GatewayBindModes, ", "))
 		return
 	}
 
-	output, err := openclaw.InitDefaultConfig()
-	if err != nil {
+	cfg, token := openclaw.MinimalSafeConfig(bind)
+	if token == "" {
+		web.FailErr(w, r, web.ErrConfigGenFailed, "token generation failed")
+		return
+	}
+	if err := h.writeConfigDirect(path, cfg); err != nil {
 		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
 		return
 	}
 
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   constants.ActionConfigUpdate,
-		Result:   "success",
-		Detail:   "generated default config via openclaw CLI",
-		IP:       r.RemoteAddr,
-	})
+	auditMutation(r, constants.ActionConfigUpdate, "generated minimal safe config (bind="+bind+")")
 
-	logger.Config.Info().Str("user", web.GetUsername(r)).Str("path", path).Str("output", output).Msg("default config generated via CLI")
-	web.OK(w, r, map[string]string{"message": "ok", "path": path})
+	logger.Config.Info().Str("user", web.GetUsername(r)).Str("path", path).Str("bind", bind).Msg("minimal safe config generated")
+	web.OK(w, r, map[string]interface{}{
+		"message": "ok",
+		"path":    path,
+		"bind":    bind,
+		"port":    openclaw.DefaultGatewayPort,
+		"token":   token,
+	})
 }
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+// configDrift is one setting where the current config differs from the baseline.
+type configDrift struct {
+	Path        string      `json:"path"`
+	Kind        string      `json:"kind"` // missing / changed
+	Current     interface{} `json:"current,omitempty"`
+	Recommended interface{} `json:"recommended,omitempty"`
+	Severity    string      `json:"severity"`       // error / warn / info
+	Code        string      `json:"code,omitempty"` // lint issue code behind the severity
+	Hint        string      `json:"hint,omitempty"`
+}
+
+// severityRank orders differences most severe first.
+var severityRank = map[string]int{openclaw.IssueError: 0, openclaw.IssueWarn: 1, openclaw.IssueInfo: 2}
+
+// Compare diffs the current openclaw.json against a baseline: the built-in
+// minimal safe config, or a config template (template_id). Only settings the
+// baseline defines are compared; template {{VAR}} placeholders and secret
+// values match any non-empty value. Each difference carries the severity of
+// the doctor's lint issue on that path, "info" otherwise.
+// POST /api/v1/config/compare
+func (h *ConfigHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TemplateID uint   `json:"template_id"`
+		Lang       string `json:"lang"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+
+	baselineName := "default-safe"
+	var baseline map[string]interface{}
+	if req.TemplateID == 0 {
+		cfg, _ := openclaw.MinimalSafeConfig("loopback")
+		baseline = normalizeConfig(cfg)
+	} else {
+		tpl, err := database.NewTemplateRepo().GetByID(req.TemplateID)
+		if err != nil {
+			web.FailErr(w, r, web.ErrTemplateNotFound)
+			return
+		}
+		if tpl.TargetFile != configTemplateTarget {
+			web.FailErr(w, r, web.ErrTemplateInvalid, "not a config template")
+			return
+		}
+		content, ok := templateContent(tpl.I18n, req.Lang)
+		if !ok {
+			web.FailErr(w, r, web.ErrTemplateInvalid, "template has no content")
+			return
+		}
+		if err := json.Unmarshal([]byte(content), &baseline); err != nil || len(baseline) == 0 {
+			web.FailErr(w, r, web.ErrTemplateInvalid)
+			return
+		}
+		baselineName = tpl.TemplateID
+	}
+
+	current := readConfigSnapshot()
+	issues := openclaw.LintConfig(current)
+	drift := compareConfig(current, baseline, issues)
+
+	counts := map[string]int{openclaw.IssueError: 0, openclaw.IssueWarn: 0, openclaw.IssueInfo: 0}
+	for _, d := range drift {
+		counts[d.Severity]++
+	}
+	web.OK(w, r, map[string]interface{}{
+		"baseline":    baselineName,
+		"differences": drift,
+		"issues":      issues,
+		"counts":      counts,
+	})
+}
+
+// compareConfig lists the baseline leaves the current config does not match,
+// most severe first. Keys outside the baseline are ignored.
+func compareConfig(current, baseline map[string]interface{}, issues []openclaw.ConfigIssue) []configDrift {
+	drift := make([]configDrift, 0)
+	for _, d := range openclaw.DiffConfigLeaves(current, baseline) {
+		if d.New == nil {
+			continue
+		}
+		if item, ok := configDriftItem(d.Path, d.Old, d.New); ok {
+			drift = append(drift, item)
+		}
+	}
+	for i := range drift {
+		drift[i].Severity = openclaw.IssueInfo
+		for _, issue := range issues {
+			if issue.Path == "" || !pathOverlaps(drift[i].Path, issue.Path) {
+				continue
+			}
+			if severityRank[issue.Level] < severityRank[drift[i].Severity] {
+				drift[i].Severity = issue.Level
+				drift[i].Code = issue.Code
+				drift[i].Hint = issue.Suggestion
+			}
+		}
+	}
+	sort.SliceStable(drift, func(i, j int) bool {
+		return severityRank[drift[i].Severity] < severityRank[drift[j].Severity]
+	})
+	return drift
+}
+
+// configDriftItem classifies one differing leaf. Secrets and template
+// placeholders only need a value, so they are reported when missing only.
+func configDriftItem(path string, current, want interface{}) (configDrift, bool) {
+	sensitive := isSensitivePath(path)
+	placeholder := false
+	if s, ok := want.(string); ok && templateVarPattern.MatchString(s) && strings.TrimSpace(templateVarPattern.ReplaceAllString(s, "")) == "" {
+		placeholder = true
+	}
+	if current == nil || current == "" {
+		d := configDrift{Path: path, Kind: "missing", Recommended: want}
+		if sensitive && !placeholder {
+			d.Recommended = diag.Redacted
+		}
+		return d, true
+	}
+	if sensitive || placeholder {
+		return configDrift{}, false
+	}
+	return configDrift{Path: path, Kind: "changed", Current: redactedCopy(current), Recommended: want}, true
+}
+
+// pathOverlaps reports whether one dotted path equals or contains the other.
+func pathOverlaps(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// normalizeConfig round-trips cfg through JSON so numbers compare as float64
+// like a config read from disk.
+func normalizeConfig(cfg map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	if data, err := json.Marshal(cfg); err == nil {
+		json.Unmarshal(data, &out)
+	}
+	return out
+}
+
+// redactedCopy returns v with nested secrets redacted, leaving v untouched.
+func redactedCopy(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var cp interface{}
+	if json.Unmarshal(data, &cp) != nil {
+		return v
+	}
+	return diag.RedactKeys(cp)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/openclaw"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareConfig(t *testing.T) {
+	var current, baseline map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"gateway": {"mode": "local", "bind": "lan", "port": 18789, "auth": {"token": "s3cret"}},
+		"agents": {"defaults": {"model": "x"}}
+	}`), &current))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"gateway": {"mode": "local", "bind": "loopback", "port": 18789, "auth": {"mode": "token", "token": "other"}},
+		"channels": {"telegram": {"botToken": "{{TELEGRAM_TOKEN}}", "enabled": true}}
+	}`), &baseline))
+
+	drift := compareConfig(current, baseline, openclaw.LintConfig(current))
+	byPath := map[string]configDrift{}
+	for _, d := range drift {
+		byPath[d.Path] = d
+	}
+
+	// bind is flagged by the lint (non-loopback without auth) and sorts first
+	require.NotEmpty(t, drift)
+	assert.Equal(t, "gateway.bind", drift[0].Path)
+	assert.Equal(t, openclaw.IssueWarn, drift[0].Severity)
+	assert.Equal(t, "GATEWAY_BIND_INSECURE", drift[0].Code)
+	assert.Equal(t, "changed", drift[0].Kind)
+
+	assert.Equal(t, "missing", byPath["gateway.auth.mode"].Kind)
+	assert.Equal(t, openclaw.IssueInfo, byPath["gateway.auth.mode"].Severity)
+	// secrets only need a value; a missing placeholder shows the variable name
+	assert.NotContains(t, byPath, "gateway.auth.token")
+	assert.Equal(t, "{{TELEGRAM_TOKEN}}", byPath["channels.telegram.botToken"].Recommended)
+	assert.Contains(t, byPath, "channels.telegram.enabled")
+	// matching values and keys outside the baseline are not reported
+	assert.NotContains(t, byPath, "gateway.port")
+	assert.NotContains(t, byPath, "agents.defaults.model")
+	assert.Len(t, drift, 4)
+
+	delete(current["gateway"].(map[string]interface{})["auth"].(map[string]interface{}), "token")
+	found := false
+	for _, d := range compareConfig(current, baseline, nil) {
+		if d.Path == "gateway.auth.token" {
+			found = true
+			assert.Equal(t, "missing", d.Kind)
+			assert.Equal(t, diag.Redacted, d.Recommended)
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+// envKeyPattern restricts env file keys to conventional upper-case names.
+var envKeyPattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// envFilePath returns the OpenClaw env file path (<state dir>/.env).
+func envFilePath() string {
+	return openclaw.DotEnvPath()
+}
+
+// envEntry is a single KEY=value assignment in the env file.
+type envEntry struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+// parseEnvEntries returns the assignments in file order (last one wins for duplicates).
+func parseEnvEntries(content string) []envEntry {
+	index := map[string]int{}
+	var entries []envEntry
+	for _, line := range strings.Split(content, "\n") {
+		k, v, ok := openclaw.ParseEnvLine(line)
+		if !ok {
+			continue
+		}
+		if i, dup := index[k]; dup {
+			entries[i].Value = v
+			continue
+		}
+		index[k] = len(entries)
+		entries = append(entries, envEntry{Key: k, Value: v})
+	}
+	return entries
+}
+
+// formatEnvValue quotes values that would not survive a plain KEY=value line.
+func formatEnvValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\"'#$\\`") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// updateEnvContent upserts set and removes keys in remove, keeping comments,
+// blank lines and unrelated assignments untouched. Existing keys are updated in
+// place (keeping an `export ` prefix); new keys are appended in sorted order.
+func updateEnvContent(content string, set map[string]string, remove []string) string {
+	removeSet := make(map[string]bool, len(remove))
+	for _, k := range remove {
+		removeSet[k] = true
+	}
+	written := map[string]bool{}
+
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+	out := make([]string, 0, len(lines)+len(set))
+	for _, line := range lines {
+		k, _, ok := openclaw.ParseEnvLine(line)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		if removeSet[k] {
+			continue
+		}
+		v, update := set[k]
+		if !update {
+			out = append(out, line)
+			continue
+		}
+		// drop later duplicates so the updated value is the effective one
+		if written[k] {
+			continue
+		}
+		prefix := ""
+		if strings.HasPrefix(strings.TrimSpace(line), "export ") {
+			prefix = "export "
+		}
+		out = append(out, prefix+k+"="+formatEnvValue(v))
+		written[k] = true
+	}
+
+	var added []string
+	for k := range set {
+		if !written[k] && !removeSet[k] {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	for _, k := range added {
+		out = append(out, k+"="+formatEnvValue(set[k]))
+	}
+
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// updateEnvFile applies updateEnvContent to the file at path using an atomic write.
+func updateEnvFile(path string, set map[string]string, remove []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return openclaw.WriteFileAtomic(path, []byte(updateEnvContent(string(data), set, remove)))
+}
+
+// validateEnvUpdate checks key names and rejects multi-line values.
+func validateEnvUpdate(set map[string]string, remove []string) error {
+	for k, v := range set {
+		if !envKeyPattern.MatchString(k) {
+			return fmt.Errorf("invalid env key %q: must match %s", k, envKeyPattern.String())
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("value of %s must not contain line breaks", k)
+		}
+	}
+	for _, k := range remove {
+		if !envKeyPattern.MatchString(k) {
+			return fmt.Errorf("invalid env key %q: must match %s", k, envKeyPattern.String())
+		}
+	}
+	return nil
+}
+
+// GetEnv lists the assignments in the OpenClaw .env file. Values are redacted for non-admins.
+// GET /api/v1/config/env
+func (h *ConfigHandler) GetEnv(w http.ResponseWriter, r *http.Request) {
+	path := envFilePath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
+	}
+
+	entries := parseEnvEntries(string(data))
+	if entries == nil {
+		entries = []envEntry{}
+	}
+	if web.GetRole(r) != constants.RoleAdmin {
+		for i := range entries {
+			if entries[i].Value != "" {
+				entries[i].Value = "***REDACTED***"
+				entries[i].Redacted = true
+			}
+		}
+	}
+	web.OK(w, r, map[string]interface{}{
+		"path":    path,
+		"exists":  err == nil,
+		"entries": entries,
+	})
+}
+
+// UpdateEnv upserts and removes keys in the OpenClaw .env file, preserving comments
+// and unrelated lines.
+// PUT /api/v1/config/env
+func (h *ConfigHandler) UpdateEnv(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Set    map[string]string `json:"set"`
+		Remove []string          `json:"remove"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if len(req.Set) == 0 && len(req.Remove) == 0 {
+		web.FailErr(w, r, web.ErrInvalidParam)
+		return
+	}
+	if err := validateEnvUpdate(req.Set, req.Remove); err != nil {
+		web.Fail(w, r, "INVALID_PARAMS", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path := envFilePath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	if err := updateEnvFile(path, req.Set, req.Remove); err != nil {
+		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+		return
+	}
+
+	setKeys := make([]string, 0, len(req.Set))
+	for k := range req.Set {
+		setKeys = append(setKeys, k)
+	}
+	sort.Strings(setKeys)
+
+	auditMutation(r, constants.ActionConfigUpdate, fmt.Sprintf("env set %v remove %v", setKeys, req.Remove))
+
+	logger.Config.Info().Str("user", web.GetUsername(r)).Strs("set", setKeys).Strs("remove", req.Remove).Msg("OpenClaw env file updated")
+	web.OK(w, r, map[string]interface{}{"message": "ok", "set": setKeys, "removed": req.Remove})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleEnv = `# provider keys
+OPENAI_API_KEY=sk-old
+
+export ANTHROPIC_API_KEY="sk ant"
+# keep me
+UNRELATED=1
+OPENAI_API_KEY=sk-dup
+`
+
+func TestUpdateEnvContent_UpsertPreservesOtherLines(t *testing.T) {
+	got := updateEnvContent(sampleEnv, map[string]string{
+		"OPENAI_API_KEY":    "sk-new",
+		"ANTHROPIC_API_KEY": "sk-ant-2",
+		"NEW_KEY":           "has space",
+	}, nil)
+	want := `# provider keys
+OPENAI_API_KEY=sk-new
+
+export ANTHROPIC_API_KEY=sk-ant-2
+# keep me
+UNRELATED=1
+NEW_KEY="has space"
+`
+	assert.Equal(t, want, got)
+}
+
+func TestUpdateEnvContent_Remove(t *testing.T) {
+	got := updateEnvContent(sampleEnv, nil, []string{"OPENAI_API_KEY"})
+	want := `# provider keys
+
+export ANTHROPIC_API_KEY="sk ant"
+# keep me
+UNRELATED=1
+`
+	assert.Equal(t, want, got)
+
+	entries := parseEnvEntries(got)
+	require.Len(t, entries, 2)
+	assert.Equal(t, envEntry{Key: "ANTHROPIC_API_KEY", Value: "sk ant"}, entries[0])
+}
+
+func TestUpdateEnvFile_CreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".openclaw", ".env")
+	require.NoError(t, updateEnvFile(path, map[string]string{"A_KEY": "v"}, nil))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "A_KEY=v\n", string(data))
+}
+
+func TestValidateEnvUpdate(t *testing.T) {
+	assert.NoError(t, validateEnvUpdate(map[string]string{"_OK_1": "x"}, []string{"B"}))
+	assert.Error(t, validateEnvUpdate(map[string]string{"lower": "x"}, nil))
+	assert.Error(t, validateEnvUpdate(map[string]string{"1BAD": "x"}, nil))
+	assert.Error(t, validateEnvUpdate(map[string]string{"OK": "a\nb"}, nil))
+	assert.Error(t, validateEnvUpdate(nil, []string{"BAD-KEY"}))
+}
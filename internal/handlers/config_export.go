@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+// maxConfigImportSize caps the body of a config import.
+const maxConfigImportSize = 1 << 20
+
+// Export downloads the full openclaw.json as YAML (default) or JSON. Secrets
+// are redacted unless redact=false; a redacted export can be imported back
+// and the placeholders keep the current values.
+// GET /api/v1/config/export?format=yaml|json&redact=true|false
+func (h *ConfigHandler) Export(w http.ResponseWriter, r *http.Request) {
+	path := configPath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		web.FailErr(w, r, web.ErrInvalidParam, "format must be yaml or json")
+		return
+	}
+	redact := true
+	if v := r.URL.Query().Get("redact"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			web.FailErr(w, r, web.ErrInvalidParam, "redact must be true or false")
+			return
+		}
+		redact = b
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			web.FailErr(w, r, web.ErrConfigNotFound)
+			return
+		}
+		web.FailErr(w, r, web.ErrConfigReadFailed)
+		return
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
+	}
+	if cfg == nil {
+		cfg = map[string]interface{}{}
+	}
+	if redact {
+		diag.RedactValue(cfg)
+	}
+
+	var out []byte
+	contentType := "application/yaml"
+	if format == "json" {
+		contentType = "application/json"
+		out, err = json.MarshalIndent(cfg, "", "  ")
+		out = append(out, '\n')
+	} else {
+		out, err = openclaw.EncodeConfigYAML(cfg)
+	}
+	if err != nil {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
+	}
+
+	if !redact {
+		auditMutation(r, constants.ActionConfigUpdate, "config export (unredacted, "+format+")")
+	}
+	filename := "openclaw-" + time.Now().Format("20060102-150405") + "." + format
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(len(out)))
+	w.Write(out)
+}
+
+// Import replaces openclaw.json with a YAML or JSON document (detected from
+// the content). Redacted placeholders from an export keep the current values.
+// The document must pass the config lint without errors; the current file is
+// backed up before the write.
+// POST /api/v1/config/import
+func (h *ConfigHandler) Import(w http.ResponseWriter, r *http.Request) {
+	path := configPath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxConfigImportSize+1))
+	if err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if len(body) > maxConfigImportSize {
+		web.FailErr(w, r, web.ErrInvalidBody, "config document exceeds 1 MiB")
+		return
+	}
+	next, err := openclaw.ParseConfigDocument(body)
+	if err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody, err.Error())
+		return
+	}
+	if len(next) == 0 {
+		web.FailErr(w, r, web.ErrConfigEmpty)
+		return
+	}
+
+	prev := readConfigSnapshot()
+	openclaw.RestoreRedacted(next, prev, diag.Redacted)
+
+	var errs []string
+	for _, issue := range openclaw.LintConfig(next) {
+		if issue.Level == openclaw.IssueError {
+			errs = append(errs, issue.Code+" ("+issue.Path+")")
+		}
+	}
+	if len(errs) > 0 {
+		web.FailErr(w, r, web.ErrConfigInvalid, strings.Join(errs, ", "))
+		return
+	}
+
+	var backupPath string
+	if _, err := os.Stat(path); err == nil {
+		if backupPath, err = openclaw.BackupConfigFile(path); err != nil {
+			web.FailErr(w, r, web.ErrBackupFailed, err.Error())
+			return
+		}
+	}
+	out, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+		return
+	}
+	summary := "config import: " + configChangeSummary(prev, next)
+	if err := openclaw.WriteFileAtomic(path, append(out, '\n')); err != nil {
+		auditMutationResult(r, constants.ActionConfigUpdate, "failed", summary+": "+err.Error())
+		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+		return
+	}
+	auditMutation(r, constants.ActionConfigUpdate, summary)
+
+	apply := applyGatewayConfig(h.svc, prev)
+	logger.Config.Info().Str("user", web.GetUsername(r)).Str("backup", backupPath).Str("apply", apply.Action).Msg("OpenClaw config imported")
+	web.OK(w, r, map[string]interface{}{"backup": backupPath, "apply": apply})
+}
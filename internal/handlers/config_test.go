@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigLint(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".openclaw"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".openclaw", "openclaw.json"),
+		[]byte(`{"gateway":{"bind":"lan","auth":{"enabled":true}}}`), 0o600))
+
+	w := httptest.NewRecorder()
+	NewConfigHandler().Lint(w, httptest.NewRequest(http.MethodGet, "/api/v1/config/lint", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data struct {
+			HasErrors bool `json:"has_errors"`
+			Issues    []struct {
+				Level string `json:"level"`
+				Code  string `json:"code"`
+			} `json:"issues"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.HasErrors)
+	codes := make([]string, 0, len(resp.Data.Issues))
+	for _, issue := range resp.Data.Issues {
+		codes = append(codes, issue.Code)
+	}
+	assert.Equal(t, []string{"DEPRECATED_AUTH_ENABLED", "GATEWAY_MODE_MISSING", "GATEWAY_BIND_INSECURE"}, codes)
+}
+
+func TestConfigMigrate(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("OPENCLAW_STATE_DIR", filepath.Join(home, ".openclaw"))
+	path := filepath.Join(home, ".openclaw", "openclaw.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	original := `{"gateway":{"mode":"local","bind":"lan","port":18789,"auth":{"enabled":true}}}`
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o600))
+
+	migrate := func() map[string]interface{} {
+		req := web.SetUserInfo(httptest.NewRequest(http.MethodPost, "/api/v1/config/migrate", nil), 1, "admin", "admin")
+		w := httptest.NewRecorder()
+		NewConfigHandler().Migrate(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	data := migrate()
+	assert.Equal(t, true, data["changed"])
+	backup, _ := data["backup"].(string)
+	saved, err := os.ReadFile(backup)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(saved), "backup holds the pre-migration file")
+
+	var cfg map[string]interface{}
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(written, &cfg))
+	auth := cfg["gateway"].(map[string]interface{})["auth"].(map[string]interface{})
+	token, _ := auth["token"].(string)
+	require.NotEmpty(t, token)
+	assert.NotContains(t, auth, "enabled")
+	body, _ := json.Marshal(data["changes"])
+	assert.NotContains(t, string(body), token, "generated token is redacted in the response")
+
+	logs, _, err := database.NewAuditLogRepo().List(database.AuditFilter{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Contains(t, logs[0].Detail, "gateway.auth.token")
+
+	assert.Equal(t, false, migrate()["changed"], "second run is a no-op")
+}
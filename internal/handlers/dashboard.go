@@ -1,33 +1,70 @@
 package handlers
 
 import (
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
 
+// dashboardCacheTTL is how long an assembled dashboard payload is reused, so
+// rapid UI polling does not re-probe the gateway and re-count activity.
+const dashboardCacheTTL = 3 * time.Second
+
+// dashboardGeneration is bumped by every audited mutation; a cached dashboard
+// built under an older generation is discarded.
+var dashboardGeneration atomic.Uint64
+
+// invalidateDashboard drops cached dashboard payloads.
+func invalidateDashboard() {
+	dashboardGeneration.Add(1)
+}
+
 // DashboardHandler serves the dashboard overview.
 type DashboardHandler struct {
-	svc       *openclaw.Service
-	alertRepo *database.AlertRepo
-	ruleRepo  *database.RiskRuleRepo
+	svc         *openclaw.Service
+	gwClient    *openclaw.GWClient
+	alertRepo   *database.AlertRepo
+	ruleRepo    *database.RiskRuleRepo
+	profileRepo *database.GatewayProfileRepo
+
+	// build assembles a fresh payload; cacheMu also serializes rebuilds so
+	// concurrent polls wait for one build instead of stacking their own.
+	build    func() DashboardResponse
+	cacheMu  sync.Mutex
+	cached   *DashboardResponse
+	cachedAt time.Time
+	cacheGen uint64
 }
 
 func NewDashboardHandler(svc *openclaw.Service) *DashboardHandler {
-	return &DashboardHandler{
-		svc:       svc,
-		alertRepo: database.NewAlertRepo(),
-		ruleRepo:  database.NewRiskRuleRepo(),
+	h := &DashboardHandler{
+		svc:         svc,
+		alertRepo:   database.NewAlertRepo(),
+		ruleRepo:    database.NewRiskRuleRepo(),
+		profileRepo: database.NewGatewayProfileRepo(),
 	}
+	h.build = h.assemble
+	return h
+}
+
+// SetGWClient injects the Gateway client reference.
+func (h *DashboardHandler) SetGWClient(client *openclaw.GWClient) {
+	h.gwClient = client
 }
 
 // DashboardResponse is the aggregated dashboard data.
 type DashboardResponse struct {
 	Gateway        GatewayStatusResponse `json:"gateway"`
+	Connection     GatewayConnection     `json:"connection"`
 	Onboarding     OnboardingStatus      `json:"onboarding"`
 	MonitorSummary MonitorSummary        `json:"monitor_summary"`
 	RecentAlerts   []database.Alert      `json:"recent_alerts"`
@@ -35,6 +72,16 @@ type DashboardResponse struct {
 	WSClients      int                   `json:"ws_clients"`
 }
 
+// GatewayConnection describes which gateway the dashboard is currently driving.
+type GatewayConnection struct {
+	GatewayMode string `json:"gateway_mode"` // "local" | "remote"
+	Profile     string `json:"profile,omitempty"`
+	Host        string `json:"host"`
+	Port        int    `json:"port"`
+	Address     string `json:"address"`
+	WSConnected bool   `json:"ws_connected"`
+}
+
 // OnboardingStatus tracks onboarding progress.
 type OnboardingStatus struct {
 	Installed        bool `json:"installed"`
@@ -52,14 +99,39 @@ type MonitorSummary struct {
 	RiskCounts  map[string]int64 `json:"risk_counts"`
 }
 
-// Get returns aggregated dashboard data.
+// Get returns aggregated dashboard data. The payload is cached for a few
+// seconds and invalidated by mutations; ?fresh=true bypasses the cache.
+// GET /api/v1/dashboard
 func (h *DashboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, h.summary(r.URL.Query().Get("fresh") == "true"))
+}
+
+// summary returns the cached payload when it is recent and no mutation has
+// happened since, and rebuilds it otherwise.
+func (h *DashboardHandler) summary(fresh bool) DashboardResponse {
+	h.cacheMu.Lock()
+	defer h.cacheMu.Unlock()
+	gen := dashboardGeneration.Load()
+	if !fresh && h.cached != nil && h.cacheGen == gen && time.Since(h.cachedAt) < dashboardCacheTTL {
+		return *h.cached
+	}
+	resp := h.build()
+	h.cached, h.cachedAt, h.cacheGen = &resp, time.Now(), gen
+	return resp
+}
+
+// assemble gathers the dashboard data from the gateway and the database.
+func (h *DashboardHandler) assemble() DashboardResponse {
 	// gateway status
 	st := h.svc.Status()
 	gwStatus := GatewayStatusResponse{
-		Running: st.Running,
-		Runtime: string(st.Runtime),
-		Detail:  st.Detail,
+		Running:     st.Running,
+		Runtime:     string(st.Runtime),
+		Detail:      st.Detail,
+		Host:        h.svc.GatewayHost,
+		Port:        h.svc.GatewayPort,
+		Remote:      h.svc.IsRemote(),
+		Maintenance: monitor.MaintenanceActive(),
 	}
 
 	// onboarding progress
@@ -78,13 +150,42 @@ func (h *DashboardHandler) Get(w http.ResponseWriter, r *http.Request) {
 	// security score
 	securityScore := h.calcSecurityScore(st, summary)
 
-	web.OK(w, r, DashboardResponse{
+	return DashboardResponse{
 		Gateway:        gwStatus,
+		Connection:     h.detectConnection(),
 		Onboarding:     onboarding,
 		MonitorSummary: summary,
 		RecentAlerts:   recentAlerts,
 		SecurityScore:  securityScore,
-	})
+	}
+}
+
+// detectConnection reports the gateway mode, active profile, address and WS state.
+// Host/port prefer the live WS client config, which is what the dashboard is actually driving.
+func (h *DashboardHandler) detectConnection() GatewayConnection {
+	conn := GatewayConnection{
+		GatewayMode: "local",
+		Host:        h.svc.GatewayHost,
+		Port:        h.svc.GatewayPort,
+	}
+	if h.gwClient != nil {
+		cfg := h.gwClient.GetConfig()
+		if cfg.Host != "" {
+			conn.Host = cfg.Host
+		}
+		if cfg.Port > 0 {
+			conn.Port = cfg.Port
+		}
+		conn.WSConnected = h.gwClient.IsConnected()
+	}
+	if h.svc.IsRemote() {
+		conn.GatewayMode = "remote"
+	}
+	if p, err := h.profileRepo.GetActive(); err == nil && p != nil {
+		conn.Profile = p.Name
+	}
+	conn.Address = net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port))
+	return conn
 }
 
 // detectOnboarding detects onboarding progress.
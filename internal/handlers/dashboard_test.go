@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardSummary_Cache(t *testing.T) {
+	builds := 0
+	h := &DashboardHandler{}
+	h.build = func() DashboardResponse {
+		builds++
+		return DashboardResponse{SecurityScore: builds}
+	}
+
+	// rapid polling reuses one build
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 1, h.summary(false).SecurityScore)
+	}
+	assert.Equal(t, 1, builds, "10 polls should cost one gateway/DB round")
+
+	// ?fresh=true bypasses the cache
+	assert.Equal(t, 2, h.summary(true).SecurityScore)
+
+	// a mutation invalidates it
+	invalidateDashboard()
+	assert.Equal(t, 3, h.summary(false).SecurityScore)
+	assert.Equal(t, 3, h.summary(false).SecurityScore)
+	assert.Equal(t, 3, builds)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
+	"openclawdeck/internal/web"
+)
+
+// DBMaintenanceHandler exposes database size stats and on-demand vacuum.
+type DBMaintenanceHandler struct{}
+
+func NewDBMaintenanceHandler() *DBMaintenanceHandler {
+	return &DBMaintenanceHandler{}
+}
+
+// Stats returns the database file size and the space a vacuum could reclaim.
+// GET /api/v1/admin/db/stats
+func (h *DBMaintenanceHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	st, err := database.Stats()
+	if errors.Is(err, database.ErrVacuumUnsupported) {
+		web.OK(w, r, st)
+		return
+	}
+	if err != nil {
+		web.FailErr(w, r, web.ErrDBQuery, err.Error())
+		return
+	}
+	web.OK(w, r, st)
+}
+
+// Vacuum reclaims free SQLite pages and reports the before/after file size.
+// Refused with DB_BUSY while the database is taking heavy writes unless ?force=1.
+// POST /api/v1/admin/db/vacuum
+func (h *DBMaintenanceHandler) Vacuum(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("force") != "1" {
+		if n := database.RecentWrites(time.Now().Add(-monitor.BusyWriteWindow)); n > monitor.BusyWriteThreshold {
+			web.FailErr(w, r, web.ErrDBBusy, fmt.Sprintf("%d writes in the last %s", n, monitor.BusyWriteWindow))
+			return
+		}
+	}
+
+	res, err := database.Vacuum()
+	switch {
+	case errors.Is(err, database.ErrVacuumUnsupported):
+		web.FailErr(w, r, web.ErrDBVacuumUnsupported)
+		return
+	case errors.Is(err, database.ErrVacuumBusy):
+		web.FailErr(w, r, web.ErrDBBusy, "a vacuum is already running")
+		return
+	case err != nil:
+		auditMutationResult(r, constants.ActionDBVacuum, "failed", err.Error())
+		logger.DB.Error().Err(err).Msg("database vacuum failed")
+		web.FailErr(w, r, web.ErrDBVacuumFailed, err.Error())
+		return
+	}
+
+	auditMutation(r, constants.ActionDBVacuum, fmt.Sprintf("%s vacuum: %d → %d bytes (%d ms)",
+		res.Mode, res.BeforeBytes, res.AfterBytes, res.DurationMs))
+	logger.DB.Info().Str("user", web.GetUsername(r)).Int64("before", res.BeforeBytes).Int64("after", res.AfterBytes).Msg("database vacuumed")
+	web.OK(w, r, res)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/web"
+	"openclawdeck/internal/webconfig"
+)
+
+// DiagHandler exports the diagnostics bundle.
+type DiagHandler struct {
+	cfg       *webconfig.Config
+	doctor    *DoctorHandler
+	auditRepo *database.AuditLogRepo
+}
+
+func NewDiagHandler(cfg *webconfig.Config, doctor *DoctorHandler) *DiagHandler {
+	return &DiagHandler{
+		cfg:       cfg,
+		doctor:    doctor,
+		auditRepo: database.NewAuditLogRepo(),
+	}
+}
+
+// Bundle builds a zip with the environment scan, doctor checks, gateway status,
+// redacted configs and the recent deck log. See diag.Contents for the file list.
+// GET /api/v1/diag/bundle
+func (h *DiagHandler) Bundle(w http.ResponseWriter, r *http.Request) {
+	b := diag.Collect(*h.cfg)
+	b.AddJSON("doctor.json", h.doctor.diagnose())
+
+	var buf bytes.Buffer
+	if err := b.WriteZip(&buf); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to build diagnostics bundle")
+		web.Fail(w, r, "DIAG_BUNDLE_FAILED", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.auditRepo.Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   constants.ActionDiagExport,
+		Result:   "success",
+		Detail:   fmt.Sprintf("diagnostics bundle exported (%d files)", len(b.Files())),
+		IP:       web.ClientIP(r),
+	})
+
+	filename := "openclawdeck-diag-" + time.Now().Format("20060102-150405") + ".zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Write(buf.Bytes())
+}
+
+// logFileInfo describes one log file on disk.
+type logFileInfo struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// LogInfo reports where the deck is logging, the rotation settings and the
+// rotated files kept next to the active one. The diagnostics bundle includes
+// the tail of the active file.
+// GET /api/v1/admin/log
+func (h *DiagHandler) LogInfo(w http.ResponseWriter, r *http.Request) {
+	cfg := h.cfg.Log
+	path := logger.FilePath()
+	resp := map[string]interface{}{
+		"path":         path,
+		"level":        cfg.Level,
+		"mode":         cfg.Mode,
+		"stdout":       cfg.Stdout,
+		"max_size_mb":  cfg.MaxSizeMB,
+		"max_backups":  cfg.MaxBackups,
+		"max_age_days": cfg.MaxAgeDays,
+		"compress":     cfg.Compress,
+	}
+	if path == "" {
+		web.OK(w, r, resp)
+		return
+	}
+	if info, err := os.Stat(path); err == nil {
+		resp["current"] = logFileInfo{Path: path, Size: info.Size(), Modified: info.ModTime()}
+	}
+	resp["backups"] = rotatedLogs(path)
+	web.OK(w, r, resp)
+}
+
+// rotatedLogs lists the backups lumberjack keeps for path
+// (<name>-<timestamp><ext>, optionally .gz), newest first.
+func rotatedLogs(path string) []logFileInfo {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(filepath.Base(path), ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return []logFileInfo{}
+	}
+	out := []logFileInfo{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if !strings.HasSuffix(name, ext) && !strings.HasSuffix(name, ext+".gz") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, logFileInfo{Path: filepath.Join(dir, name), Size: info.Size(), Modified: info.ModTime()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Modified.After(out[j].Modified) })
+	return out
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatedLogs(t *testing.T) {
+	dir := t.TempDir()
+	active := filepath.Join(dir, "openclawdeck.log")
+	files := map[string]time.Duration{
+		"openclawdeck.log":                                0,
+		"openclawdeck-2026-10-01T10-00-00.000.log":        2 * time.Hour,
+		"openclawdeck-2026-10-01T12-00-00.000.log.gz":     time.Hour,
+		"other-2026-10-01T12-00-00.000.log":               time.Hour,
+		"openclawdeck-2026-10-01T12-00-00.000.log.backup": time.Hour,
+	}
+	now := time.Now()
+	for name, age := range files {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte("x"), 0o644))
+		require.NoError(t, os.Chtimes(p, now.Add(-age), now.Add(-age)))
+	}
+
+	got := rotatedLogs(active)
+	require.Len(t, got, 2)
+	assert.Equal(t, "openclawdeck-2026-10-01T12-00-00.000.log.gz", filepath.Base(got[0].Path))
+	assert.Equal(t, "openclawdeck-2026-10-01T10-00-00.000.log", filepath.Base(got[1].Path))
+
+	assert.Empty(t, rotatedLogs(filepath.Join(dir, "missing", "deck.log")))
+}
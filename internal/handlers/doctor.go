@@ -46,10 +46,16 @@ type DiagResult struct {
 
 // Run executes diagnostics.
 func (h *DoctorHandler) Run(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, h.diagnose())
+}
+
+// diagnose runs all checks and scores the result.
+func (h *DoctorHandler) diagnose() DiagResult {
 	var items []CheckItem
 
 	items = append(items, h.checkInstalled())
 	items = append(items, h.checkConfig())
+	items = append(items, h.checkEnvRefs())
 	items = append(items, h.checkGateway())
 	items = append(items, h.checkPIDLock())
 	items = append(items, h.checkPort())
@@ -80,16 +86,19 @@ func (h *DoctorHandler) Run(w http.ResponseWriter, r *http.Request) {
 		summary = "warnings found, review recommended"
 	}
 
-	web.OK(w, r, DiagResult{
+	return DiagResult{
 		Items:   items,
 		Summary: summary,
 		Score:   score,
-	})
+	}
 }
 
-// Fix runs automatic repairs.
+// Fix applies safe repairs and reports each applied change as a structured
+// item, grouped by target: system files, openclaw.json and the env config.
 func (h *DoctorHandler) Fix(w http.ResponseWriter, r *http.Request) {
 	var fixed []string
+	system := []openclaw.ConfigChange{}
+	var errs []string
 
 	// fix stale PID lock file
 	home, _ := os.UserHomeDir()
@@ -99,18 +108,48 @@ func (h *DoctorHandler) Fix(w http.ResponseWriter, r *http.Request) {
 		if !st.Running {
 			os.Remove(pidFile)
 			fixed = append(fixed, "removed stale PID lock file")
+			system = append(system, openclaw.ConfigChange{Path: pidFile, Action: "remove"})
 		}
 	}
 
 	// fix config file permissions (non-Windows)
+	cfgPath := configPath()
 	if runtime.GOOS != "windows" {
-		configPath := filepath.Join(home, ".openclaw", "openclaw.json")
-		if _, err := os.Stat(configPath); err == nil {
-			os.Chmod(configPath, 0o600)
+		if _, err := os.Stat(cfgPath); err == nil {
+			os.Chmod(cfgPath, 0o600)
 			fixed = append(fixed, "fixed config file permissions to 600")
+			system = append(system, openclaw.ConfigChange{Path: cfgPath, Action: "chmod", New: "600"})
+		}
+	}
+
+	// openclaw.json safe migrations (backed up before writing)
+	configChanges := []openclaw.ConfigChange{}
+	var configBackup string
+	if _, err := os.Stat(cfgPath); err == nil {
+		changes, backup, err := openclaw.FixConfigFile(cfgPath)
+		if err != nil {
+			errs = append(errs, "openclaw.json: "+err.Error())
+		} else {
+			configChanges, configBackup = changes, backup
+		}
+	}
+	for i := range configChanges {
+		fixed = append(fixed, "openclaw.json: "+configChanges[i].Action+" "+configChanges[i].Path)
+		if isSensitiveKey(configChanges[i].Path[strings.LastIndex(configChanges[i].Path, ".")+1:]) {
+			configChanges[i].New = "***REDACTED***"
 		}
 	}
 
+	// env config inference (notify platform, provider, timezone)
+	envChanges, err := openclaw.FixEnvConfig(filepath.Join(home, ".openclaw", "env"))
+	if err != nil {
+		errs = append(errs, "env: "+err.Error())
+		envChanges = []openclaw.ConfigChange{}
+	}
+	for _, c := range envChanges {
+		fixed = append(fixed, "env: "+c.Action+" "+c.Path)
+	}
+
 	// audit log
 	if len(fixed) > 0 {
 		h.auditRepo.Create(&database.AuditLog{
@@ -119,14 +158,19 @@ func (h *DoctorHandler) Fix(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionDoctorFix,
 			Result:   "success",
 			Detail:   strings.Join(fixed, "; "),
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 	}
 
-	logger.Doctor.Info().Strs("fixed", fixed).Msg("auto-fix completed")
+	logger.Doctor.Info().Strs("fixed", fixed).Strs("errors", errs).Str("backup", configBackup).Msg("auto-fix completed")
 	web.OK(w, r, map[string]interface{}{
-		"fixed":   fixed,
-		"message": "ok",
+		"fixed":        fixed,
+		"system":       system,
+		"config":       configChanges,
+		"configBackup": configBackup,
+		"env":          envChanges,
+		"errors":       errs,
+		"message":      "ok",
 	})
 }
 
@@ -151,6 +195,22 @@ func (h *DoctorHandler) checkConfig() CheckItem {
 	return CheckItem{Name: "Config File", Status: "error", Detail: "config file not found"}
 }
 
+func (h *DoctorHandler) checkEnvRefs() CheckItem {
+	unresolved, err := openclaw.CheckCurrentEnvRefs()
+	if err != nil {
+		return CheckItem{Name: "Env References", Status: "warn", Detail: "cannot check ${VAR} references: " + err.Error()}
+	}
+	if len(unresolved) == 0 {
+		return CheckItem{Name: "Env References", Status: "ok", Detail: "all ${VAR} references resolved"}
+	}
+	parts := make([]string, 0, len(unresolved))
+	for _, v := range unresolved {
+		parts = append(parts, v.Name+" ("+strings.Join(v.Paths, ", ")+")")
+	}
+	return CheckItem{Name: "Env References", Status: "warn",
+		Detail: "undefined in .env and environment: " + strings.Join(parts, "; ")}
+}
+
 func (h *DoctorHandler) checkGateway() CheckItem {
 	st := h.svc.Status()
 	if st.Running {
@@ -2,22 +2,25 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
 
 // GatewayHandler manages gateway lifecycle.
 type GatewayHandler struct {
-	svc       *openclaw.Service
-	auditRepo *database.AuditLogRepo
-	wsHub     *web.WSHub
-	gwClient  *openclaw.GWClient
+	svc      *openclaw.Service
+	wsHub    *web.WSHub
+	gwClient *openclaw.GWClient
+	drift    *monitor.TokenDriftWatcher
 }
 
 // SetGWClient injects the Gateway client reference.
@@ -25,11 +28,15 @@ func (h *GatewayHandler) SetGWClient(client *openclaw.GWClient) {
 	h.gwClient = client
 }
 
+// SetTokenDrift injects the profile/openclaw.json token drift watcher.
+func (h *GatewayHandler) SetTokenDrift(w *monitor.TokenDriftWatcher) {
+	h.drift = w
+}
+
 func NewGatewayHandler(svc *openclaw.Service, wsHub *web.WSHub) *GatewayHandler {
 	return &GatewayHandler{
-		svc:       svc,
-		auditRepo: database.NewAuditLogRepo(),
-		wsHub:     wsHub,
+		svc:   svc,
+		wsHub: wsHub,
 	}
 }
 
@@ -41,19 +48,39 @@ type GatewayStatusResponse struct {
 	Host    string `json:"host,omitempty"`
 	Port    int    `json:"port,omitempty"`
 	Remote  bool   `json:"remote"`
+	// DetectedPort is the port the gateway was actually found listening on (0 if none).
+	DetectedPort int `json:"detectedPort,omitempty"`
+	// Binding is the gateway section of the local openclaw.json (bind/port/mode/auth).
+	Binding *openclaw.GatewayBinding `json:"binding,omitempty"`
+	// TokenDrift reports whether the active profile's token differs from openclaw.json.
+	TokenDrift *monitor.TokenDriftState `json:"tokenDrift,omitempty"`
+	// Maintenance is true while maintenance mode pauses health restarts, alerts and collection.
+	Maintenance bool `json:"maintenance"`
 }
 
 // Status returns gateway running status.
 func (h *GatewayHandler) Status(w http.ResponseWriter, r *http.Request) {
 	st := h.svc.Status()
-	web.OK(w, r, GatewayStatusResponse{
-		Running: st.Running,
-		Runtime: string(st.Runtime),
-		Detail:  st.Detail,
-		Host:    h.svc.GatewayHost,
-		Port:    h.svc.GatewayPort,
-		Remote:  h.svc.IsRemote(),
-	})
+	resp := GatewayStatusResponse{
+		Running:      st.Running,
+		Runtime:      string(st.Runtime),
+		Detail:       st.Detail,
+		Host:         h.svc.GatewayHost,
+		Port:         h.svc.GatewayPort,
+		Remote:       h.svc.IsRemote(),
+		DetectedPort: st.Port,
+		Maintenance:  monitor.MaintenanceActive(),
+	}
+	if !resp.Remote {
+		if b, ok := openclaw.ReadGatewayBinding(); ok {
+			resp.Binding = &b
+		}
+	}
+	if h.drift != nil {
+		drift := h.drift.State()
+		resp.TokenDrift = &drift
+	}
+	web.OK(w, r, resp)
 }
 
 // Start starts the gateway.
@@ -119,21 +146,69 @@ func (h *GatewayHandler) Restart(w http.ResponseWriter, r *http.Request) {
 	web.OK(w, r, map[string]string{"message": "ok"})
 }
 
+// Reload hot-reloads the gateway config (config.reload) without dropping
+// sessions, falling back to a full restart when the reload is not possible.
+// POST /api/v1/gateway/reload
+func (h *GatewayHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	mode := openclaw.ApplyReload
+	err := h.svc.Reload()
+	if err != nil {
+		logger.Gateway.Warn().Err(err).Msg("config reload failed, restarting gateway")
+		mode = openclaw.ApplyRestart
+		err = h.svc.Restart()
+	}
+	if err != nil {
+		h.writeAudit(r, constants.ActionGatewayReload, "failed", mode+": "+err.Error())
+		web.FailErr(w, r, web.ErrGWStartFailed, err.Error())
+		return
+	}
+
+	h.writeAudit(r, constants.ActionGatewayReload, "success", mode)
+	if mode == openclaw.ApplyRestart {
+		h.broadcastStatus()
+	}
+	web.OK(w, r, map[string]string{"message": "ok", "mode": mode})
+}
+
 // Kill triggers the kill switch — force-stops the gateway.
+// Optional body {"level": "graceful"|"term"|"force"} (default "term") sets how far
+// to escalate: graceful stop → SIGTERM → SIGKILL. Only processes whose command
+// line contains both "openclaw" and "gateway" are signalled.
 func (h *GatewayHandler) Kill(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Level string `json:"level"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			web.FailErr(w, r, web.ErrInvalidBody)
+			return
+		}
+	}
+	level, err := openclaw.ParseKillLevel(req.Level)
+	if err != nil {
+		web.Fail(w, r, "INVALID_PARAMS", err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	logger.Gateway.Warn().
 		Str("user", web.GetUsername(r)).
 		Str("ip", r.RemoteAddr).
+		Str("level", string(level)).
 		Msg("kill switch triggered")
 
-	if err := h.svc.Stop(); err != nil {
-		h.writeAudit(r, constants.ActionKillSwitch, "failed", err.Error())
+	res, err := h.svc.Kill(level)
+	detail := fmt.Sprintf("kill switch (level=%s)", level)
+	if res != nil {
+		detail = fmt.Sprintf("kill switch (level=%s, reached=%s, pids=%v)", level, res.Reached, killPIDs(res))
+	}
+	if err != nil {
+		h.writeAudit(r, constants.ActionKillSwitch, "failed", detail+": "+err.Error())
 		logger.Gateway.Error().Err(err).Msg("kill switch failed")
 		web.FailErr(w, r, web.ErrGWStopFailed, err.Error())
 		return
 	}
 
-	h.writeAudit(r, constants.ActionKillSwitch, "success", "kill switch")
+	h.writeAudit(r, constants.ActionKillSwitch, "success", detail)
 
 	// broadcast kill switch event
 	h.wsHub.Broadcast("alert", "kill_switch", map[string]interface{}{
@@ -142,8 +217,23 @@ func (h *GatewayHandler) Kill(w http.ResponseWriter, r *http.Request) {
 	})
 	h.broadcastStatus()
 
-	logger.Gateway.Warn().Msg("kill switch executed, gateway stopped")
-	web.OK(w, r, map[string]string{"message": "ok"})
+	logger.Gateway.Warn().Ints("pids", killPIDs(res)).Str("reached", string(res.Reached)).Msg("kill switch executed, gateway stopped")
+	web.OK(w, r, map[string]interface{}{
+		"message":  "ok",
+		"level":    res.Level,
+		"reached":  res.Reached,
+		"pids":     killPIDs(res),
+		"affected": res.Affected,
+	})
+}
+
+// killPIDs lists the PIDs that were matched before the kill.
+func killPIDs(res *openclaw.KillResult) []int {
+	pids := make([]int, 0, len(res.Affected))
+	for _, p := range res.Affected {
+		pids = append(pids, p.PID)
+	}
+	return pids
 }
 
 // GetHealthCheck returns health check status.
@@ -165,7 +255,8 @@ func (h *GatewayHandler) SetHealthCheck(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if h.gwClient != nil {
+	// in maintenance mode only the setting is saved; it takes effect when maintenance ends
+	if h.gwClient != nil && !monitor.MaintenanceActive() {
 		h.gwClient.SetHealthCheckEnabled(req.Enabled)
 	}
 
@@ -176,7 +267,7 @@ func (h *GatewayHandler) SetHealthCheck(w http.ResponseWriter, r *http.Request)
 		val = "true"
 	}
 	settingRepo.SetBatch(map[string]string{
-		"gateway_health_check_enabled": val,
+		openclaw.HealthCheckEnabledSetting: val,
 	})
 
 	h.writeAudit(r, constants.ActionSettingsUpdate, "success",
@@ -188,22 +279,16 @@ func (h *GatewayHandler) SetHealthCheck(w http.ResponseWriter, r *http.Request)
 
 // writeAudit writes an audit log entry.
 func (h *GatewayHandler) writeAudit(r *http.Request, action, result, detail string) {
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   action,
-		Result:   result,
-		Detail:   detail,
-		IP:       r.RemoteAddr,
-	})
+	auditMutationResult(r, action, result, detail)
 }
 
 // broadcastStatus broadcasts gateway status via WebSocket.
 func (h *GatewayHandler) broadcastStatus() {
 	st := h.svc.Status()
 	h.wsHub.Broadcast("gateway_status", "gateway_status", GatewayStatusResponse{
-		Running: st.Running,
-		Runtime: string(st.Runtime),
-		Detail:  st.Detail,
+		Running:     st.Running,
+		Runtime:     string(st.Runtime),
+		Detail:      st.Detail,
+		Maintenance: monitor.MaintenanceActive(),
 	})
 }
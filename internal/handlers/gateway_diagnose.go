@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"openclawdeck/internal/openclaw"
@@ -17,11 +18,22 @@ func NewGatewayDiagnoseHandler(svc *openclaw.Service) *GatewayDiagnoseHandler {
 	return &GatewayDiagnoseHandler{svc: svc}
 }
 
-// Diagnose runs gateway diagnostics.
+// Diagnose runs gateway diagnostics, including a staged WS connect handshake.
+// An optional {"token": "..."} body tests a candidate token instead of the
+// configured one.
 // POST /api/v1/gateway/diagnose
 func (h *GatewayDiagnoseHandler) Diagnose(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	json.NewDecoder(r.Body).Decode(&req) // body is optional
+
 	host := h.svc.GatewayHost
 	port := h.svc.GatewayPort
-	result := openclaw.DiagnoseGateway(host, port)
+	token := req.Token
+	if token == "" {
+		token = h.svc.GatewayToken()
+	}
+	result := openclaw.DiagnoseGateway(host, port, token)
 	web.OK(w, r, result)
 }
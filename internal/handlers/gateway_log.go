@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"openclawdeck/internal/openclaw"
@@ -24,8 +30,16 @@ func NewGatewayLogHandler(svc *openclaw.Service, gwClient *openclaw.GWClient) *G
 	return &GatewayLogHandler{svc: svc, gwClient: gwClient}
 }
 
+// IsLogFollow reports whether a GetLog request asks for the SSE follow stream;
+// only those requests are registered as a stream.
+func IsLogFollow(r *http.Request) bool {
+	return r.URL.Query().Get("follow") == "true"
+}
+
 // GetLog returns the last N lines of gateway logs.
-// Remote mode uses logs.tail JSON-RPC; local mode reads the log file.
+// Remote mode uses logs.tail JSON-RPC; container runtimes use `docker/podman logs`;
+// systemd uses journalctl; otherwise the local log file is read.
+// ?follow=true streams container or journal logs via SSE.
 func (h *GatewayLogHandler) GetLog(w http.ResponseWriter, r *http.Request) {
 	lines := 200
 	if v := r.URL.Query().Get("lines"); v != "" {
@@ -34,6 +48,11 @@ func (h *GatewayLogHandler) GetLog(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if IsLogFollow(r) {
+		h.followLog(w, r, lines)
+		return
+	}
+
 	// remote mode: fetch via GWClient logs.tail, fallback to local if RPC fails
 	if h.gwClient != nil && h.gwClient.IsConnected() {
 		if h.tryRemoteLog(w, r, lines) {
@@ -41,10 +60,165 @@ func (h *GatewayLogHandler) GetLog(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// container mode: the gateway logs to the container's stdout/stderr
+	if ctr, name := h.svc.Container(); ctr != nil {
+		if h.tryContainerLog(w, r, ctr, name, lines) {
+			return
+		}
+	}
+
+	// systemd mode: logs live in the journal, not in a file
+	if h.isSystemd() {
+		h.getJournalLog(w, r, lines)
+		return
+	}
+
 	// local mode: read local log file
 	h.getLocalLog(w, r, lines)
 }
 
+// isSystemd reports whether the local gateway runs as the openclaw systemd unit.
+func (h *GatewayLogHandler) isSystemd() bool {
+	return !h.svc.IsRemote() && h.svc.DetectRuntime() == openclaw.RuntimeSystemd
+}
+
+// getJournalLog returns the last N journal lines of the openclaw unit.
+func (h *GatewayLogHandler) getJournalLog(w http.ResponseWriter, r *http.Request, lines int) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	entries, err := openclaw.ReadJournal(ctx, lines)
+	if errors.Is(err, openclaw.ErrJournalPermission) {
+		web.Fail(w, r, "LOG_PERMISSION_DENIED", err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		web.FailErr(w, r, web.ErrLogReadFailed, err.Error())
+		return
+	}
+	content := make([]string, len(entries))
+	for i, e := range entries {
+		content[i] = e.Raw
+	}
+	web.OK(w, r, map[string]interface{}{
+		"lines":      content,
+		"entries":    entries,
+		"path":       "journalctl -u openclaw",
+		"runtime":    openclaw.RuntimeSystemd,
+		"line_count": len(content),
+	})
+}
+
+// tryContainerLog fetches the last N lines via `<runtime> logs --tail N`.
+// Returns false if the command fails so the caller can fall back to log files.
+func (h *GatewayLogHandler) tryContainerLog(w http.ResponseWriter, r *http.Request, ctr openclaw.ContainerRuntime, name string, lines int) bool {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	out, err := ctr.LogsCommand(ctx, name, lines, false).CombinedOutput()
+	if err != nil {
+		return false
+	}
+	content := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(content) == 1 && content[0] == "" {
+		content = []string{}
+	}
+	web.OK(w, r, map[string]interface{}{
+		"lines":      content,
+		"path":       fmt.Sprintf("%s://%s", ctr.Name(), name),
+		"container":  name,
+		"runtime":    ctr.Name(),
+		"line_count": len(content),
+	})
+	return true
+}
+
+// followLog streams `<runtime> logs -f` or `journalctl -f` as SSE until the client disconnects.
+func (h *GatewayLogHandler) followLog(w http.ResponseWriter, r *http.Request, lines int) {
+	// bound to the request: closing the viewer kills the follow command
+	var cmd *exec.Cmd
+	start := map[string]interface{}{"type": "start"}
+	journal := false
+	if ctr, name := h.svc.Container(); ctr != nil {
+		cmd = ctr.LogsCommand(r.Context(), name, lines, true)
+		start["container"], start["runtime"] = name, ctr.Name()
+	} else if h.isSystemd() {
+		// `journalctl -f` without permission just hangs after the hint, so probe first
+		ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+		_, err := openclaw.ReadJournal(ctx, 1)
+		cancel()
+		if errors.Is(err, openclaw.ErrJournalPermission) {
+			web.Fail(w, r, "LOG_PERMISSION_DENIED", err.Error(), http.StatusForbidden)
+			return
+		}
+		cmd = openclaw.JournalCommand(r.Context(), lines, true)
+		start["runtime"] = openclaw.RuntimeSystemd
+		journal = true
+	} else {
+		web.Fail(w, r, "LOG_FOLLOW_UNSUPPORTED", "follow is only supported when the gateway runs in docker, podman or systemd", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var sseMu sync.Mutex
+	sendSSE := func(data map[string]interface{}) {
+		payload, _ := json.Marshal(data)
+		sseMu.Lock()
+		defer sseMu.Unlock()
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+		flusher.Flush()
+	}
+
+	stdout, stderr, err := commandPipes(cmd)
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		sendSSE(map[string]interface{}{"type": "error", "message": err.Error()})
+		return
+	}
+	sendSSE(start)
+
+	var wg sync.WaitGroup
+	for stream, pipe := range map[string]io.Reader{"stdout": stdout, "stderr": stderr} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scanLines(pipe, func(line string) {
+				evt := map[string]interface{}{"type": "log", "line": line, "stream": stream}
+				if journal {
+					if strings.HasPrefix(line, "-- ") {
+						return
+					}
+					if e := openclaw.ParseJournalLine(line); !e.Time.IsZero() {
+						evt["time"], evt["message"] = e.Time, e.Message
+					}
+				}
+				sendSSE(evt)
+			})
+		}()
+	}
+	wg.Wait()
+	err = cmd.Wait()
+
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		sendSSE(map[string]interface{}{"type": "error", "message": err.Error()})
+		return
+	}
+	sendSSE(map[string]interface{}{"type": "done"})
+}
+
 // tryRemoteLog attempts to fetch remote gateway logs via logs.tail JSON-RPC.
 // Returns true if successful (response written), false if failed (caller should fallback).
 func (h *GatewayLogHandler) tryRemoteLog(w http.ResponseWriter, r *http.Request, lines int) bool {
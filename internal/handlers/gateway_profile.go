@@ -84,7 +84,7 @@ func (h *GatewayProfileHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionSettingsUpdate,
 		Detail:   "created gateway profile: " + req.Name + " (" + req.Host + ":" + strconv.Itoa(req.Port) + ")",
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Config.Info().Str("name", req.Name).Str("host", req.Host).Int("port", req.Port).Msg("gateway profile created")
@@ -144,7 +144,7 @@ func (h *GatewayProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionSettingsUpdate,
 		Detail:   "updated gateway profile: " + profile.Name,
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	web.OK(w, r, profile)
@@ -181,7 +181,7 @@ func (h *GatewayProfileHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionSettingsUpdate,
 		Detail:   "deleted gateway profile: " + profile.Name,
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	web.OK(w, r, map[string]string{"message": "ok"})
@@ -215,7 +215,7 @@ func (h *GatewayProfileHandler) Activate(w http.ResponseWriter, r *http.Request)
 		Action:   constants.ActionSettingsUpdate,
 		Detail:   "activated gateway: " + profile.Name + " (" + profile.Host + ":" + strconv.Itoa(profile.Port) + ")",
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Config.Info().
@@ -229,10 +229,11 @@ func (h *GatewayProfileHandler) Activate(w http.ResponseWriter, r *http.Request)
 
 // applyProfile applies the profile to GWClient and Service.
 func (h *GatewayProfileHandler) applyProfile(p *database.GatewayProfile) {
+	invalidateDashboard()
 	if h.gwService != nil {
 		h.gwService.GatewayHost = p.Host
 		h.gwService.GatewayPort = p.Port
-		h.gwService.GatewayToken = p.Token
+		h.gwService.SetGatewayToken(p.Token)
 	}
 	if h.gwClient != nil {
 		h.gwClient.Reconnect(openclaw.GWClientConfig{
@@ -2,30 +2,62 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
+	"openclawdeck/internal/logger"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
 
 // GWProxyHandler proxies Gateway WebSocket methods as REST APIs.
 type GWProxyHandler struct {
-	client *openclaw.GWClient
+	client    *openclaw.GWClient
+	auditRepo *database.AuditLogRepo
+	history   historyCache
 }
 
 func NewGWProxyHandler(client *openclaw.GWClient) *GWProxyHandler {
-	return &GWProxyHandler{client: client}
+	return &GWProxyHandler{
+		client:    client,
+		auditRepo: database.NewAuditLogRepo(),
+	}
 }
 
 // Status returns Gateway WS client connection status.
 func (h *GWProxyHandler) Status(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, h.client.Stats())
+}
+
+// RPCStats returns per-method gateway RPC call statistics (calls, errors,
+// timeouts, latency, payload sizes) since startup or the last reset.
+// GET /api/v1/gw/rpc-stats
+func (h *GWProxyHandler) RPCStats(w http.ResponseWriter, r *http.Request) {
+	methods, since := h.client.RPCStats()
+	for i := range methods {
+		methods[i].LastError = diag.RedactText(methods[i].LastError)
+	}
 	web.OK(w, r, map[string]interface{}{
-		"connected": h.client.IsConnected(),
+		"since":   since,
+		"methods": methods,
 	})
 }
 
+// ResetRPCStats clears the gateway RPC call statistics.
+// DELETE /api/v1/gw/rpc-stats
+func (h *GWProxyHandler) ResetRPCStats(w http.ResponseWriter, r *http.Request) {
+	h.client.ResetRPCStats()
+	web.OK(w, r, map[string]string{"message": "ok"})
+}
+
 // Health returns Gateway health info.
 func (h *GWProxyHandler) Health(w http.ResponseWriter, r *http.Request) {
 	data, err := h.client.Request("health", map[string]interface{}{"probe": false})
@@ -116,6 +148,113 @@ func (h *GWProxyHandler) SessionsDelete(w http.ResponseWriter, r *http.Request)
 	web.OKRaw(w, r, data)
 }
 
+const (
+	sessionsBulkMaxKeys  = 500
+	sessionsBulkParallel = 4
+)
+
+// bulkResult is the outcome of one key in a bulk operation.
+type bulkResult struct {
+	Key   string `json:"key"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// runBulk calls fn for every key with at most parallel calls in flight. Failures
+// are recorded per key and never stop the batch; results keep the order of keys.
+func runBulk(keys []string, parallel int, fn func(key string) error) []bulkResult {
+	results := make([]bulkResult, len(keys))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = bulkResult{Key: key, OK: true}
+			if err := fn(key); err != nil {
+				results[i] = bulkResult{Key: key, Error: err.Error()}
+			}
+		}(i, key)
+	}
+	wg.Wait()
+	return results
+}
+
+// SessionsBulk resets or deletes several sessions, returning a per-key result.
+// POST /api/v1/gw/sessions/bulk
+func (h *GWProxyHandler) SessionsBulk(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Keys             []string `json:"keys"`
+		Action           string   `json:"action"` // reset | delete
+		DeleteTranscript bool     `json:"deleteTranscript"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if params.Action != "reset" && params.Action != "delete" {
+		web.Fail(w, r, "INVALID_PARAMS", "action must be reset or delete", http.StatusBadRequest)
+		return
+	}
+	seen := make(map[string]bool, len(params.Keys))
+	keys := make([]string, 0, len(params.Keys))
+	for _, k := range params.Keys {
+		if k = strings.TrimSpace(k); k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		web.Fail(w, r, "INVALID_PARAMS", "keys is required", http.StatusBadRequest)
+		return
+	}
+	if len(keys) > sessionsBulkMaxKeys {
+		web.Fail(w, r, "INVALID_PARAMS", fmt.Sprintf("at most %d keys per request", sessionsBulkMaxKeys), http.StatusBadRequest)
+		return
+	}
+
+	results := runBulk(keys, sessionsBulkParallel, func(key string) error {
+		p := map[string]interface{}{"key": key}
+		if params.Action == "delete" {
+			p["deleteTranscript"] = params.DeleteTranscript
+		}
+		_, err := h.client.Request("sessions."+params.Action, p)
+		return err
+	})
+	succeeded := 0
+	for _, res := range results {
+		if res.OK {
+			succeeded++
+		}
+	}
+
+	result := "success"
+	if succeeded < len(results) {
+		result = "partial"
+		if succeeded == 0 {
+			result = "failed"
+		}
+	}
+	h.auditRepo.Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   constants.ActionSessionBulk,
+		Result:   result,
+		Detail:   fmt.Sprintf("sessions %s: %d/%d succeeded (deleteTranscript=%t)", params.Action, succeeded, len(results), params.DeleteTranscript),
+		IP:       web.ClientIP(r),
+	})
+
+	web.OK(w, r, map[string]interface{}{
+		"action":    params.Action,
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   results,
+	})
+}
+
 // ModelsList returns model list.
 func (h *GWProxyHandler) ModelsList(w http.ResponseWriter, r *http.Request) {
 	data, err := h.client.Request("models.list", map[string]interface{}{})
@@ -245,6 +384,115 @@ func (h *GWProxyHandler) CronStatus(w http.ResponseWriter, r *http.Request) {
 	web.OKRaw(w, r, data)
 }
 
+// CronRun triggers a cron job immediately.
+// POST /api/v1/gw/cron/run
+func (h *GWProxyHandler) CronRun(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.ID == "" {
+		web.Fail(w, r, "INVALID_PARAMS", "id is required", http.StatusBadRequest)
+		return
+	}
+	if !h.cronJobExists(w, r, params.ID) {
+		return
+	}
+	data, err := h.client.Request("cron.run", map[string]interface{}{
+		"id":   params.ID,
+		"mode": "force",
+	})
+	h.auditCron(r, constants.ActionCronRun, params.ID, "run", err)
+	if err != nil {
+		web.Fail(w, r, "GW_CRON_RUN_FAILED", err.Error(), http.StatusBadGateway)
+		return
+	}
+	web.OKRaw(w, r, data)
+}
+
+// CronToggle enables or disables a cron job.
+// POST /api/v1/gw/cron/toggle
+func (h *GWProxyHandler) CronToggle(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		ID      string `json:"id"`
+		Enabled *bool  `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.ID == "" || params.Enabled == nil {
+		web.Fail(w, r, "INVALID_PARAMS", "id and enabled are required", http.StatusBadRequest)
+		return
+	}
+	if !h.cronJobExists(w, r, params.ID) {
+		return
+	}
+	data, err := h.client.Request("cron.update", map[string]interface{}{
+		"id":    params.ID,
+		"patch": map[string]interface{}{"enabled": *params.Enabled},
+	})
+	h.auditCron(r, constants.ActionCronToggle, params.ID, fmt.Sprintf("enabled=%t", *params.Enabled), err)
+	if err != nil {
+		web.Fail(w, r, "GW_CRON_UPDATE_FAILED", err.Error(), http.StatusBadGateway)
+		return
+	}
+	web.OKRaw(w, r, data)
+}
+
+// cronJobExists checks id against cron.list, writing the error response when it
+// can't be confirmed.
+func (h *GWProxyHandler) cronJobExists(w http.ResponseWriter, r *http.Request, id string) bool {
+	data, err := h.client.Request("cron.list", map[string]interface{}{
+		"includeDisabled": true,
+	})
+	if err != nil {
+		web.Fail(w, r, "GW_CRON_LIST_FAILED", err.Error(), http.StatusBadGateway)
+		return false
+	}
+	if !cronListHasJob(data, id) {
+		web.Fail(w, r, "CRON_JOB_NOT_FOUND", "cron job not found: "+id, http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// cronListHasJob reports whether a cron.list result (an array of jobs or an
+// object with a "jobs" array) contains a job with the given id.
+func cronListHasJob(data json.RawMessage, id string) bool {
+	var jobs []struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(data, &jobs) != nil {
+		var wrapper struct {
+			Jobs []struct {
+				ID string `json:"id"`
+			} `json:"jobs"`
+		}
+		if json.Unmarshal(data, &wrapper) != nil {
+			return false
+		}
+		jobs = wrapper.Jobs
+	}
+	for _, j := range jobs {
+		if j.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *GWProxyHandler) auditCron(r *http.Request, action, id, detail string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failed"
+		detail += ": " + err.Error()
+	}
+	h.auditRepo.Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   action,
+		Result:   result,
+		Detail:   fmt.Sprintf("cron job %s %s", id, detail),
+		IP:       web.ClientIP(r),
+	})
+}
+
 // ChannelsStatus returns channel status.
 func (h *GWProxyHandler) ChannelsStatus(w http.ResponseWriter, r *http.Request) {
 	data, err := h.client.Request("channels.status", map[string]interface{}{})
@@ -338,36 +586,134 @@ func (h *GWProxyHandler) SessionsPreviewMessages(w http.ResponseWriter, r *http.
 	web.OKRaw(w, r, data)
 }
 
-// SessionsHistory returns full session history.
+// SessionsHistory returns session history. Without ?limit= or ?before= the full
+// history is returned as-is; otherwise a window of up to limit messages older than
+// the before cursor (newest page when omitted) plus a cursor for the next page.
+// GET /api/v1/gw/sessions/history?key=&limit=&before=
 func (h *GWProxyHandler) SessionsHistory(w http.ResponseWriter, r *http.Request) {
-	key := r.URL.Query().Get("key")
+	q := r.URL.Query()
+	key := q.Get("key")
 	if key == "" {
 		web.Fail(w, r, "INVALID_PARAMS", "key is required", http.StatusBadRequest)
 		return
 	}
+	before := q.Get("before")
+	limit, windowed, err := parseHistoryWindow(q.Get("limit"), before)
+	if err != nil {
+		web.Fail(w, r, "INVALID_PARAMS", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// follow-up pages reuse the transcript fetched for the first page
+	data, err := h.fetchHistory(key, windowed && before != "")
+	if err != nil {
+		web.Fail(w, r, "GW_SESSIONS_HISTORY_FAILED", err.Error(), http.StatusBadGateway)
+		return
+	}
+	if !windowed {
+		web.OKRaw(w, r, data)
+		return
+	}
+
+	msgs, wrapper, ok := historyMessages(data)
+	if !ok {
+		// unknown result shape: nothing to window
+		web.OKRaw(w, r, data)
+		return
+	}
+	page := windowHistory(msgs, limit, before)
+	if wrapper == nil {
+		web.OK(w, r, page)
+		return
+	}
+	// keep the other fields of the gateway result alongside the window
+	out := make(map[string]interface{}, len(wrapper)+3)
+	for k, v := range wrapper {
+		out[k] = v
+	}
+	out["messages"] = page.Messages
+	out["total"] = page.Total
+	out["hasMore"] = page.HasMore
+	if page.NextBefore != "" {
+		out["nextBefore"] = page.NextBefore
+	}
+	web.OK(w, r, out)
+}
+
+// fetchHistory fetches the full transcript of a session, reusing one fetched in the
+// last historyCacheTTL when useCache is set. Every fetch refreshes the cache.
+func (h *GWProxyHandler) fetchHistory(key string, useCache bool) (json.RawMessage, error) {
+	if useCache {
+		if data, ok := h.history.get(key); ok {
+			return data, nil
+		}
+	}
 	data, err := h.client.RequestWithTimeout("sessions.history", map[string]interface{}{
 		"key": key,
 	}, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	h.history.put(key, data)
+	return data, nil
+}
+
+// SessionsSearch finds messages in a session containing q (case-insensitive),
+// optionally only those with the given role, and returns them with their index and
+// up to ?context= messages on each side (default 1). Matching is done here so the
+// transcript never reaches the browser.
+// GET /api/v1/gw/sessions/search?key=&q=&role=&limit=&context=
+func (h *GWProxyHandler) SessionsSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	key, query := params.Get("key"), strings.TrimSpace(params.Get("q"))
+	if key == "" || query == "" {
+		web.Fail(w, r, "INVALID_PARAMS", "key and q are required", http.StatusBadRequest)
+		return
+	}
+	limit, contextSize := searchDefaultMatches, 1
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			web.Fail(w, r, "INVALID_PARAMS", "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, searchMaxMatches)
+	}
+	if v := params.Get("context"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			web.Fail(w, r, "INVALID_PARAMS", "context must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		contextSize = min(n, searchMaxContext)
+	}
+
+	// repeated searches on the same session reuse the cached transcript
+	data, err := h.fetchHistory(key, true)
 	if err != nil {
 		web.Fail(w, r, "GW_SESSIONS_HISTORY_FAILED", err.Error(), http.StatusBadGateway)
 		return
 	}
-	web.OKRaw(w, r, data)
+	msgs, _, ok := historyMessages(data)
+	if !ok {
+		web.Fail(w, r, "GW_SESSIONS_HISTORY_FAILED", "unexpected sessions.history result", http.StatusBadGateway)
+		return
+	}
+	web.OK(w, r, searchHistory(msgs, query, params.Get("role"), limit, contextSize))
 }
 
-// SkillsConfigure configures a skill (enable/disable/env vars etc.).
-func (h *GWProxyHandler) SkillsConfigure(w http.ResponseWriter, r *http.Request) {
-	// get current config
+// fetchEditableConfig returns the gateway's current config (config.get "parsed",
+// or "config" on older gateways) for read-modify-write via config.set. The error
+// code is set when err is non-nil.
+func (h *GWProxyHandler) fetchEditableConfig() (map[string]interface{}, string, error) {
 	raw, err := h.client.Request("config.get", map[string]interface{}{})
 	if err != nil {
-		web.Fail(w, r, "GW_CONFIG_GET_FAILED", err.Error(), http.StatusBadGateway)
-		return
+		return nil, "GW_CONFIG_GET_FAILED", err
 	}
 
 	var wrapper map[string]interface{}
 	if json.Unmarshal(raw, &wrapper) != nil {
-		web.Fail(w, r, "GW_CONFIG_PARSE_FAILED", "failed to parse config response", http.StatusBadGateway)
-		return
+		return nil, "GW_CONFIG_PARSE_FAILED", fmt.Errorf("failed to parse config response")
 	}
 
 	var currentCfg map[string]interface{}
@@ -381,28 +727,26 @@ func (h *GWProxyHandler) SkillsConfigure(w http.ResponseWriter, r *http.Request)
 		}
 	}
 	if currentCfg == nil {
-		web.Fail(w, r, "GW_CONFIG_PARSE_FAILED", "failed to parse current config", http.StatusBadGateway)
-		return
+		return nil, "GW_CONFIG_PARSE_FAILED", fmt.Errorf("failed to parse current config")
 	}
+	return currentCfg, "", nil
+}
 
-	// parse request
-	var params struct {
-		SkillKey string                 `json:"skillKey"`
-		Enabled  *bool                  `json:"enabled,omitempty"`
-		ApiKey   *string                `json:"apiKey,omitempty"`
-		Env      map[string]string      `json:"env,omitempty"`
-		Config   map[string]interface{} `json:"config,omitempty"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.SkillKey == "" {
-		web.Fail(w, r, "INVALID_PARAMS", "skillKey is required", http.StatusBadRequest)
-		return
-	}
+// skillConfigureParams is the body of POST /api/v1/gw/skills/configure.
+type skillConfigureParams struct {
+	SkillKey string                 `json:"skillKey"`
+	Enabled  *bool                  `json:"enabled,omitempty"`
+	ApiKey   *string                `json:"apiKey,omitempty"`
+	Env      map[string]string      `json:"env,omitempty"`
+	Config   map[string]interface{} `json:"config,omitempty"`
+}
 
-	// update skills.entries
-	skills, _ := currentCfg["skills"].(map[string]interface{})
+// applySkillEntry updates skills.entries.<key> in cfg in place.
+func applySkillEntry(cfg map[string]interface{}, params skillConfigureParams) {
+	skills, _ := cfg["skills"].(map[string]interface{})
 	if skills == nil {
 		skills = map[string]interface{}{}
-		currentCfg["skills"] = skills
+		cfg["skills"] = skills
 	}
 	entries, _ := skills["entries"].(map[string]interface{})
 	if entries == nil {
@@ -439,15 +783,43 @@ func (h *GWProxyHandler) SkillsConfigure(w http.ResponseWriter, r *http.Request)
 		}
 	}
 	entries[params.SkillKey] = entry
+}
+
+// SkillsConfigure configures a skill (enable/disable/env vars etc.). With a
+// connected gateway the change goes through config.set + config.reload; when a
+// local gateway is down, openclaw.json is edited directly (backup + atomic
+// write) so a misbehaving skill can still be disabled.
+// POST /api/v1/gw/skills/configure
+func (h *GWProxyHandler) SkillsConfigure(w http.ResponseWriter, r *http.Request) {
+	var params skillConfigureParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.SkillKey == "" {
+		web.Fail(w, r, "INVALID_PARAMS", "skillKey is required", http.StatusBadRequest)
+		return
+	}
+
+	if !h.client.IsConnected() && !isRemoteGWClient(h.client) {
+		h.skillsConfigureLocal(w, r, params)
+		return
+	}
+
+	// get current config
+	currentCfg, code, err := h.fetchEditableConfig()
+	if err != nil {
+		web.Fail(w, r, code, err.Error(), http.StatusBadGateway)
+		return
+	}
+	applySkillEntry(currentCfg, params)
 
 	// save config
 	saveData, err := h.client.RequestWithTimeout("config.set", map[string]interface{}{
 		"config": currentCfg,
 	}, 15*time.Second)
 	if err != nil {
+		h.auditSkillToggle(r, params, "failed", "gateway: "+err.Error())
 		web.Fail(w, r, "GW_CONFIG_SET_FAILED", err.Error(), http.StatusBadGateway)
 		return
 	}
+	h.auditSkillToggle(r, params, "success", "gateway")
 
 	// hot-reload
 	h.client.RequestWithTimeout("config.reload", map[string]interface{}{}, 10*time.Second)
@@ -455,8 +827,65 @@ func (h *GWProxyHandler) SkillsConfigure(w http.ResponseWriter, r *http.Request)
 	web.OKRaw(w, r, saveData)
 }
 
+// skillsConfigureLocal applies the skill change to the local openclaw.json.
+func (h *GWProxyHandler) skillsConfigureLocal(w http.ResponseWriter, r *http.Request, params skillConfigureParams) {
+	path := openclaw.ResolveConfigPath()
+	if path == "" {
+		web.FailErr(w, r, web.ErrConfigPathError)
+		return
+	}
+	backup, err := openclaw.UpdateConfigFile(path, func(raw map[string]any) error {
+		applySkillEntry(raw, params)
+		return nil
+	})
+	if err != nil {
+		h.auditSkillToggle(r, params, "failed", "config file: "+err.Error())
+		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+		return
+	}
+	h.auditSkillToggle(r, params, "success", "config file")
+	logger.Config.Info().Str("skill", params.SkillKey).Str("backup", backup).Msg("skill config written to openclaw.json (gateway not connected)")
+	web.OK(w, r, map[string]interface{}{
+		"ok":     true,
+		"local":  true,
+		"backup": backup,
+	})
+}
+
+// auditSkillToggle records enable/disable changes; other skill settings are not audited.
+func (h *GWProxyHandler) auditSkillToggle(r *http.Request, params skillConfigureParams, result, via string) {
+	if params.Enabled == nil {
+		return
+	}
+	detail := fmt.Sprintf("skill %s enabled=%t via %s", params.SkillKey, *params.Enabled, via)
+	auditMutationResult(r, constants.ActionSkillToggle, result, detail)
+}
+
 // SkillsConfigGet returns skill config (skills.entries).
 func (h *GWProxyHandler) SkillsConfigGet(w http.ResponseWriter, r *http.Request) {
+	if !h.client.IsConnected() && !isRemoteGWClient(h.client) {
+		// local gateway down: read openclaw.json so toggles still show their state;
+		// an unreadable file is an error, not "every skill at its default"
+		var entries interface{} = map[string]interface{}{}
+		data, err := os.ReadFile(openclaw.ResolveConfigPath())
+		if err != nil && !os.IsNotExist(err) {
+			web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+			return
+		}
+		if err == nil {
+			var cfg map[string]interface{}
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				web.FailErr(w, r, web.ErrConfigReadFailed, "existing config is not valid JSON: "+err.Error())
+				return
+			}
+			if skills, ok := cfg["skills"].(map[string]interface{}); ok && skills["entries"] != nil {
+				entries = skills["entries"]
+			}
+		}
+		web.OK(w, r, map[string]interface{}{"entries": entries, "local": true})
+		return
+	}
+
 	raw, err := h.client.Request("config.get", map[string]interface{}{})
 	if err != nil {
 		web.Fail(w, r, "GW_CONFIG_GET_FAILED", err.Error(), http.StatusBadGateway)
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/web"
+)
+
+var (
+	agentIDPattern    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+	agentModelPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+/\S+$`)
+)
+
+const (
+	agentNameMaxLen   = 64
+	agentAvatarMaxLen = 512
+)
+
+// agentRequest is the body of the agent write endpoints. Fields are optional
+// except where noted per operation.
+type agentRequest struct {
+	AgentID     string  `json:"agentId,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Workspace   *string `json:"workspace,omitempty"`
+	Model       *string `json:"model,omitempty"`
+	Emoji       *string `json:"emoji,omitempty"`
+	Avatar      *string `json:"avatar,omitempty"`
+	Enabled     *bool   `json:"enabled,omitempty"`
+	DeleteFiles bool    `json:"deleteFiles,omitempty"`
+}
+
+// validate checks the fields present in the request; op is create/update/delete/enable.
+func (req *agentRequest) validate(op string) error {
+	if op == "create" {
+		if req.Name == nil {
+			return errors.New("name is required")
+		}
+	} else if !agentIDPattern.MatchString(req.AgentID) {
+		return errors.New("agentId is required and may only contain letters, digits, '-' and '_'")
+	}
+	if op == "enable" && req.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return errors.New("name must not be empty")
+		}
+		if len([]rune(name)) > agentNameMaxLen {
+			return fmt.Errorf("name must be at most %d characters", agentNameMaxLen)
+		}
+		if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+			return errors.New("name must not contain control characters")
+		}
+	}
+	if req.Workspace != nil && strings.ContainsAny(*req.Workspace, "\x00\r\n") {
+		return errors.New("workspace must be a single-line path")
+	}
+	if req.Model != nil {
+		if m := strings.TrimSpace(*req.Model); m != "" && !agentModelPattern.MatchString(m) {
+			return errors.New("model must be in provider/model form")
+		}
+	}
+	for _, v := range []*string{req.Emoji, req.Avatar} {
+		if v != nil && (len(*v) > agentAvatarMaxLen || strings.ContainsAny(*v, "\r\n")) {
+			return fmt.Errorf("emoji/avatar must be a single line of at most %d bytes", agentAvatarMaxLen)
+		}
+	}
+	return nil
+}
+
+// rpcParams builds the params of the agents.* gateway method for op.
+func (req *agentRequest) rpcParams(op string) map[string]interface{} {
+	p := map[string]interface{}{}
+	if op != "create" {
+		p["agentId"] = req.AgentID
+	}
+	// A field is sent when present in the request; on update an empty value
+	// clears it, matching the config fallback in applyAgentOp.
+	set := func(key string, v *string) {
+		if v == nil {
+			return
+		}
+		if s := strings.TrimSpace(*v); s != "" || op == "update" {
+			p[key] = s
+		}
+	}
+	switch op {
+	case "create":
+		set("name", req.Name)
+		set("workspace", req.Workspace)
+		set("emoji", req.Emoji)
+	case "update":
+		set("name", req.Name)
+		set("workspace", req.Workspace)
+		set("model", req.Model)
+		set("avatar", req.Avatar)
+		set("emoji", req.Emoji)
+	case "delete":
+		p["deleteFiles"] = req.DeleteFiles
+	}
+	return p
+}
+
+// isUnknownMethodErr reports whether the gateway rejected the method itself
+// (older gateways without the agents.* write RPCs).
+func isUnknownMethodErr(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown method") || strings.Contains(msg, "method not found")
+}
+
+// AgentsCreate creates an agent.
+// POST /api/v1/gw/agents/create
+func (h *GWProxyHandler) AgentsCreate(w http.ResponseWriter, r *http.Request) {
+	h.agentWrite(w, r, "create", constants.ActionAgentCreate)
+}
+
+// AgentsUpdate updates an agent's name, workspace, model or avatar.
+// POST /api/v1/gw/agents/update
+func (h *GWProxyHandler) AgentsUpdate(w http.ResponseWriter, r *http.Request) {
+	h.agentWrite(w, r, "update", constants.ActionAgentUpdate)
+}
+
+// AgentsDelete deletes an agent (and its files when deleteFiles is set).
+// POST /api/v1/gw/agents/delete
+func (h *GWProxyHandler) AgentsDelete(w http.ResponseWriter, r *http.Request) {
+	h.agentWrite(w, r, "delete", constants.ActionAgentDelete)
+}
+
+// AgentsEnable enables or disables an agent. The gateway has no RPC for this, so
+// agents.list[].enabled is edited in the config.
+// POST /api/v1/gw/agents/enable
+func (h *GWProxyHandler) AgentsEnable(w http.ResponseWriter, r *http.Request) {
+	h.agentWrite(w, r, "enable", constants.ActionAgentToggle)
+}
+
+// agentWrite validates the request, calls agents.<op> on the gateway and falls back
+// to editing the agents config when the gateway doesn't know the method.
+func (h *GWProxyHandler) agentWrite(w http.ResponseWriter, r *http.Request, op, action string) {
+	var req agentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if err := req.validate(op); err != nil {
+		web.Fail(w, r, "INVALID_PARAMS", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var data json.RawMessage
+	var err error
+	via := "rpc"
+	if op != "enable" {
+		data, err = h.client.RequestWithTimeout("agents."+op, req.rpcParams(op), 15*time.Second)
+	}
+	if op == "enable" || (err != nil && isUnknownMethodErr(err)) {
+		via = "config"
+		data, err = h.editAgentsConfig(op, &req)
+	}
+
+	target := req.AgentID
+	if target == "" && req.Name != nil {
+		target = strings.TrimSpace(*req.Name)
+	}
+	result, detail := "success", fmt.Sprintf("agent %s %s via %s", op, target, via)
+	if err != nil {
+		result, detail = "failed", detail+": "+err.Error()
+	}
+	h.auditRepo.Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   action,
+		Result:   result,
+		Detail:   detail,
+		IP:       web.ClientIP(r),
+	})
+
+	if err != nil {
+		var nf *agentNotFoundError
+		if errors.As(err, &nf) {
+			web.Fail(w, r, "AGENT_NOT_FOUND", err.Error(), http.StatusNotFound)
+			return
+		}
+		web.Fail(w, r, "GW_AGENTS_"+strings.ToUpper(op)+"_FAILED", err.Error(), http.StatusBadGateway)
+		return
+	}
+	web.OKRaw(w, r, data)
+}
+
+type agentNotFoundError struct{ id string }
+
+func (e *agentNotFoundError) Error() string { return "agent not found: " + e.id }
+
+// editAgentsConfig applies op to agents.list in the gateway config, then saves and
+// hot-reloads it (same pattern as SkillsConfigure).
+func (h *GWProxyHandler) editAgentsConfig(op string, req *agentRequest) (json.RawMessage, error) {
+	cfg, _, err := h.fetchEditableConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAgentOp(cfg, op, req); err != nil {
+		return nil, err
+	}
+	data, err := h.client.RequestWithTimeout("config.set", map[string]interface{}{
+		"config": cfg,
+	}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	h.client.RequestWithTimeout("config.reload", map[string]interface{}{}, 10*time.Second)
+	return data, nil
+}
+
+// applyAgentOp edits agents.list of cfg in place.
+func applyAgentOp(cfg map[string]interface{}, op string, req *agentRequest) error {
+	agents, _ := cfg["agents"].(map[string]interface{})
+	if agents == nil {
+		agents = map[string]interface{}{}
+		cfg["agents"] = agents
+	}
+	list, _ := agents["list"].([]interface{})
+
+	find := func(id string) int {
+		for i, item := range list {
+			if m, ok := item.(map[string]interface{}); ok && m["id"] == id {
+				return i
+			}
+		}
+		return -1
+	}
+	trimmed := func(v *string) (string, bool) {
+		if v == nil {
+			return "", false
+		}
+		return strings.TrimSpace(*v), true
+	}
+
+	if op == "create" {
+		name, _ := trimmed(req.Name)
+		id := agentIDFromName(name)
+		for n := 2; find(id) >= 0; n++ {
+			id = fmt.Sprintf("%s-%d", agentIDFromName(name), n)
+		}
+		entry := map[string]interface{}{"id": id, "name": name}
+		if ws, ok := trimmed(req.Workspace); ok && ws != "" {
+			entry["workspace"] = ws
+		}
+		if emoji, ok := trimmed(req.Emoji); ok && emoji != "" {
+			entry["identity"] = map[string]interface{}{"emoji": emoji}
+		}
+		agents["list"] = append(list, entry)
+		return nil
+	}
+
+	idx := find(req.AgentID)
+	if idx < 0 {
+		return &agentNotFoundError{id: req.AgentID}
+	}
+	entry := list[idx].(map[string]interface{})
+	switch op {
+	case "delete":
+		agents["list"] = append(list[:idx], list[idx+1:]...)
+	case "enable":
+		entry["enabled"] = *req.Enabled
+	case "update":
+		for key, v := range map[string]*string{"name": req.Name, "workspace": req.Workspace, "model": req.Model} {
+			if s, ok := trimmed(v); ok {
+				if s == "" {
+					delete(entry, key)
+				} else {
+					entry[key] = s
+				}
+			}
+		}
+		identity, _ := entry["identity"].(map[string]interface{})
+		for key, v := range map[string]*string{"emoji": req.Emoji, "avatar": req.Avatar} {
+			if s, ok := trimmed(v); ok {
+				if identity == nil {
+					identity = map[string]interface{}{}
+				}
+				if s == "" {
+					delete(identity, key)
+				} else {
+					identity[key] = s
+				}
+			}
+		}
+		if identity != nil {
+			entry["identity"] = identity
+		}
+	}
+	return nil
+}
+
+// agentIDFromName derives a config id ("My Agent" → "my-agent").
+func agentIDFromName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	id := strings.TrimRight(b.String(), "-")
+	if len(id) > 48 {
+		id = strings.TrimRight(id[:48], "-")
+	}
+	if id == "" {
+		id = "agent"
+	}
+	return id
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// historyDefaultLimit is the page size when only ?before= is given.
+	historyDefaultLimit = 100
+	historyMaxLimit     = 1000
+	// historyCacheTTL lets follow-up pages (?before=) reuse the transcript fetched
+	// for the first page instead of pulling the whole history again.
+	historyCacheTTL     = 30 * time.Second
+	historyCacheEntries = 8
+)
+
+// historyWindow is a page of a session transcript.
+type historyWindow struct {
+	Messages   []json.RawMessage `json:"messages"`
+	Total      int               `json:"total"`
+	HasMore    bool              `json:"hasMore"`
+	NextBefore string            `json:"nextBefore,omitempty"` // pass as ?before= to fetch older messages
+}
+
+// historyCache keeps recently fetched full transcripts by session key.
+type historyCache struct {
+	mu      sync.Mutex
+	entries map[string]historyCacheEntry
+}
+
+type historyCacheEntry struct {
+	data    json.RawMessage
+	fetched time.Time
+}
+
+func (c *historyCache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(e.fetched) > historyCacheTTL {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.data, true
+}
+
+func (c *historyCache) put(key string, data json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]historyCacheEntry{}
+	}
+	// evict expired entries, then the oldest one if still full
+	var oldest string
+	for k, e := range c.entries {
+		if time.Since(e.fetched) > historyCacheTTL {
+			delete(c.entries, k)
+		} else if oldest == "" || e.fetched.Before(c.entries[oldest].fetched) {
+			oldest = k
+		}
+	}
+	if len(c.entries) >= historyCacheEntries && oldest != "" {
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = historyCacheEntry{data: data, fetched: time.Now()}
+}
+
+// parseHistoryWindow reads ?limit= and ?before=; windowed is false when neither is set.
+func parseHistoryWindow(limitParam, before string) (limit int, windowed bool, err error) {
+	if limitParam == "" && before == "" {
+		return 0, false, nil
+	}
+	limit = historyDefaultLimit
+	if limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n <= 0 {
+			return 0, false, fmt.Errorf("limit must be a positive integer")
+		}
+		limit = min(n, historyMaxLimit)
+	}
+	return limit, true, nil
+}
+
+// historyMessages extracts the message list from a sessions.history result, which is
+// either a bare array or an object with a "messages" array. ok is false for other shapes.
+func historyMessages(data json.RawMessage) (msgs []json.RawMessage, wrapper map[string]json.RawMessage, ok bool) {
+	if json.Unmarshal(data, &msgs) == nil {
+		return msgs, nil, true
+	}
+	if json.Unmarshal(data, &wrapper) != nil {
+		return nil, nil, false
+	}
+	raw, found := wrapper["messages"]
+	if !found || json.Unmarshal(raw, &msgs) != nil {
+		return nil, nil, false
+	}
+	return msgs, wrapper, true
+}
+
+// messageCursor is the message id, or its index when the message has no id.
+func messageCursor(msg json.RawMessage, index int) string {
+	var m struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(msg, &m) == nil && len(m.ID) > 0 && string(m.ID) != "null" {
+		var s string
+		if json.Unmarshal(m.ID, &s) == nil {
+			return s
+		}
+		return string(m.ID)
+	}
+	return strconv.Itoa(index)
+}
+
+// windowHistory returns up to limit messages older than the before cursor (newest
+// page when before is empty), keeping chronological order. An unknown cursor
+// yields an empty page.
+func windowHistory(msgs []json.RawMessage, limit int, before string) historyWindow {
+	end := len(msgs)
+	if before != "" {
+		end = 0
+		for i := len(msgs) - 1; i >= 0; i-- {
+			if messageCursor(msgs[i], i) == before {
+				end = i
+				break
+			}
+		}
+	}
+	start := max(end-limit, 0)
+	w := historyWindow{
+		Messages: msgs[start:end],
+		Total:    len(msgs),
+		HasMore:  start > 0,
+	}
+	if w.Messages == nil {
+		w.Messages = []json.RawMessage{}
+	}
+	if w.HasMore {
+		w.NextBefore = messageCursor(msgs[start], start)
+	}
+	return w
+}
+
+const (
+	searchDefaultMatches = 50
+	searchMaxMatches     = 200
+	searchMaxContext     = 5
+	searchSnippetRadius  = 80
+)
+
+// historyMatch is a message matching a session search, with surrounding messages.
+type historyMatch struct {
+	Index   int               `json:"index"`
+	Cursor  string            `json:"cursor"` // usable as ?before= on the history endpoint
+	Role    string            `json:"role,omitempty"`
+	Snippet string            `json:"snippet"`
+	Message json.RawMessage   `json:"message"`
+	Before  []json.RawMessage `json:"before"`
+	After   []json.RawMessage `json:"after"`
+}
+
+// historySearchResult is the result of searchHistory.
+type historySearchResult struct {
+	Matches   []historyMatch `json:"matches"`
+	Total     int            `json:"total"` // total matches, may exceed len(Matches)
+	Truncated bool           `json:"truncated"`
+	Scanned   int            `json:"scanned"`
+}
+
+// messageRoleAndText returns the role and the searchable text of a message: every
+// string value in it (content blocks, tool names and inputs, error text) except
+// bookkeeping fields and block types.
+func messageRoleAndText(msg json.RawMessage) (role, text string) {
+	var m map[string]interface{}
+	if json.Unmarshal(msg, &m) != nil {
+		return "", string(msg)
+	}
+	role, _ = m["role"].(string)
+	var parts []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case string:
+			parts = append(parts, val)
+		case map[string]interface{}:
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				// block type markers (text, tool_use, ...) are noise in results
+				if k != "type" {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(val[k])
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		}
+	}
+	delete(m, "id")
+	delete(m, "role")
+	delete(m, "timestamp")
+	delete(m, "ts")
+	walk(m)
+	return role, strings.Join(parts, "\n")
+}
+
+// searchSnippet returns the text around the first match at byte offset pos.
+func searchSnippet(text string, pos, length int) string {
+	start := max(pos-searchSnippetRadius, 0)
+	end := min(pos+length+searchSnippetRadius, len(text))
+	// don't cut UTF-8 sequences in half
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// searchHistory finds messages containing query (case-insensitive), optionally
+// restricted to role, returning at most maxMatches with context messages on each side.
+func searchHistory(msgs []json.RawMessage, query, role string, maxMatches, context int) historySearchResult {
+	res := historySearchResult{Matches: []historyMatch{}, Scanned: len(msgs)}
+	needle := strings.ToLower(query)
+	for i, msg := range msgs {
+		msgRole, text := messageRoleAndText(msg)
+		if role != "" && !strings.EqualFold(msgRole, role) {
+			continue
+		}
+		// ToLower keeps byte offsets for ASCII; good enough for locating the snippet
+		lower := strings.ToLower(text)
+		pos := strings.Index(lower, needle)
+		if pos < 0 {
+			continue
+		}
+		res.Total++
+		if len(res.Matches) >= maxMatches {
+			res.Truncated = true
+			continue
+		}
+		if len(lower) != len(text) {
+			pos = min(pos, len(text))
+		}
+		res.Matches = append(res.Matches, historyMatch{
+			Index:   i,
+			Cursor:  messageCursor(msg, i),
+			Role:    msgRole,
+			Snippet: searchSnippet(text, pos, len(needle)),
+			Message: msg,
+			Before:  append([]json.RawMessage{}, msgs[max(i-context, 0):i]...),
+			After:   append([]json.RawMessage{}, msgs[i+1:min(i+1+context, len(msgs))]...),
+		})
+	}
+	return res
+}
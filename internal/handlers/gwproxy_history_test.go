@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowHistory(t *testing.T) {
+	msgs, wrapper, ok := historyMessages(json.RawMessage(`{"key":"s1","messages":[
+		{"id":"m1","role":"user"},{"id":"m2","role":"assistant"},{"id":"m3","role":"user"},
+		{"id":"m4","role":"assistant"},{"id":"m5","role":"user"}]}`))
+	require.True(t, ok)
+	assert.Contains(t, wrapper, "key")
+
+	ids := func(w historyWindow) []string {
+		var out []string
+		for i, m := range w.Messages {
+			out = append(out, messageCursor(m, i))
+		}
+		return out
+	}
+
+	page := windowHistory(msgs, 2, "")
+	assert.Equal(t, []string{"m4", "m5"}, ids(page))
+	assert.True(t, page.HasMore)
+	assert.Equal(t, "m4", page.NextBefore)
+	assert.Equal(t, 5, page.Total)
+
+	page = windowHistory(msgs, 2, page.NextBefore)
+	assert.Equal(t, []string{"m2", "m3"}, ids(page))
+
+	page = windowHistory(msgs, 2, page.NextBefore)
+	assert.Equal(t, []string{"m1"}, ids(page))
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextBefore)
+
+	assert.Empty(t, windowHistory(msgs, 2, "unknown").Messages)
+
+	// bare arrays without ids page by index
+	msgs, wrapper, ok = historyMessages(json.RawMessage(`[{"role":"user"},{"role":"assistant"},{"role":"user"}]`))
+	require.True(t, ok)
+	assert.Nil(t, wrapper)
+	page = windowHistory(msgs, 2, "")
+	assert.Equal(t, "1", page.NextBefore)
+	assert.Len(t, windowHistory(msgs, 2, "1").Messages, 1)
+
+	_, _, err := parseHistoryWindow("0", "")
+	assert.Error(t, err)
+	_, windowed, _ := parseHistoryWindow("", "")
+	assert.False(t, windowed)
+}
+
+func TestSearchHistory(t *testing.T) {
+	msgs, _, ok := historyMessages(json.RawMessage(`[
+		{"id":"m1","role":"user","content":"please run the build"},
+		{"id":"m2","role":"assistant","content":[{"type":"tool_use","name":"exec","input":{"command":"make build"}}]},
+		{"id":"m3","role":"tool","content":[{"type":"tool_result","content":"Error: BUILD failed"}]},
+		{"id":"m4","role":"assistant","content":"The build failed."}
+	]`))
+	require.True(t, ok)
+
+	res := searchHistory(msgs, "build", "", 10, 1)
+	assert.Equal(t, 4, res.Total)
+	assert.False(t, res.Truncated)
+	assert.Equal(t, 2, res.Matches[2].Index)
+	assert.Equal(t, "Error: BUILD failed", res.Matches[2].Snippet)
+	assert.Len(t, res.Matches[0].Before, 0)
+	assert.Len(t, res.Matches[0].After, 1)
+
+	res = searchHistory(msgs, "EXEC", "assistant", 10, 0)
+	require.Len(t, res.Matches, 1)
+	assert.Equal(t, "m2", res.Matches[0].Cursor)
+
+	res = searchHistory(msgs, "build", "", 2, 1)
+	assert.Len(t, res.Matches, 2)
+	assert.Equal(t, 4, res.Total)
+	assert.True(t, res.Truncated)
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+const (
+	streamDefaultTimeout = 5 * time.Minute
+	streamMaxTimeout     = 30 * time.Minute
+	streamKeepalive      = 15 * time.Second
+	// streamEarlyBuffer bounds the events held while waiting for the method's
+	// response, since events of the run may arrive before it.
+	streamEarlyBuffer = 1024
+	// streamEventBuffer is the subscription buffer; a browser too slow to keep
+	// up overflows it and the stream ends with an error instead of losing events.
+	streamEventBuffer = 256
+)
+
+// streamProxyRequest is the body of POST /api/v1/gw/proxy-stream.
+type streamProxyRequest struct {
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+	// CorrelationKey names the field shared by the response and the events of the
+	// run (default "runId"). When the response lacks it, params[CorrelationKey] is used.
+	CorrelationKey string `json:"correlationKey,omitempty"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
+}
+
+// streamRun tracks the events of one correlated run.
+type streamRun struct {
+	key      string
+	id       string
+	sawState bool // chat-style events (state: delta/final/...) were seen
+}
+
+// eventField returns payload[key] as a string.
+func eventField(payload json.RawMessage, key string) string {
+	var m map[string]json.RawMessage
+	if json.Unmarshal(payload, &m) != nil {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(m[key], &s) != nil {
+		return ""
+	}
+	return s
+}
+
+// matches reports whether the event belongs to the run.
+func (s *streamRun) matches(evt openclaw.GWEvent) bool {
+	return s.id != "" && eventField(evt.Payload, s.key) == s.id
+}
+
+// terminal reports whether a matching event ends the run: a final/error/aborted
+// state, or the agent lifecycle end when no chat-style events were seen (chat
+// finals arrive after the lifecycle end and carry the complete message).
+func (s *streamRun) terminal(evt openclaw.GWEvent) bool {
+	var p struct {
+		State  string `json:"state"`
+		Stream string `json:"stream"`
+		Data   struct {
+			Phase string `json:"phase"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(evt.Payload, &p) != nil {
+		return false
+	}
+	if p.State != "" {
+		s.sawState = true
+		return p.State == "final" || p.State == "error" || p.State == "aborted"
+	}
+	return !s.sawState && p.Stream == "lifecycle" && (p.Data.Phase == "end" || p.Data.Phase == "error")
+}
+
+// ProxyStream forwards a streaming method to the gateway and relays the events of
+// the resulting run to the browser as SSE until a terminal event, the timeout or
+// the client disconnecting. SSE messages are
+// {"type": "response"|"subscribed"|"event"|"done"|"error", ...}.
+// POST /api/v1/gw/proxy-stream
+func (h *GWProxyHandler) ProxyStream(w http.ResponseWriter, r *http.Request) {
+	var req streamProxyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method == "" {
+		web.Fail(w, r, "INVALID_PARAMS", "method is required", http.StatusBadRequest)
+		return
+	}
+	if !h.client.IsConnected() {
+		web.FailErr(w, r, web.ErrGWNotConnected)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	run := &streamRun{key: req.CorrelationKey}
+	if run.key == "" {
+		run.key = "runId"
+	}
+	timeout := streamDefaultTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(req.TimeoutSeconds)*time.Second, streamMaxTimeout)
+	}
+
+	// subscribe before sending so no event of the run is missed
+	sub := h.client.Subscribe(streamEventBuffer)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	var sseMu sync.Mutex
+	send := func(data map[string]interface{}) {
+		payload, _ := json.Marshal(data)
+		sseMu.Lock()
+		defer sseMu.Unlock()
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+		flusher.Flush()
+	}
+
+	type rpcResult struct {
+		data json.RawMessage
+		err  error
+	}
+	respCh := make(chan rpcResult, 1)
+	go func() {
+		reqTimeout := 30 * time.Second
+		if slowMethods[req.Method] {
+			reqTimeout = 5 * time.Minute
+		}
+		data, err := h.client.RequestWithTimeout(req.Method, req.Params, reqTimeout)
+		respCh <- rpcResult{data, err}
+	}()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+
+	// relay sends a matching event and reports whether the run is over.
+	relay := func(evt openclaw.GWEvent) bool {
+		if !run.matches(evt) {
+			return false
+		}
+		send(map[string]interface{}{"type": "event", "event": evt.Event, "payload": evt.Payload})
+		if run.terminal(evt) {
+			send(map[string]interface{}{"type": "done", "reason": "terminal", "event": evt.Event})
+			return true
+		}
+		return false
+	}
+
+	var early []openclaw.GWEvent
+	responded := false
+	for {
+		select {
+		case res := <-respCh:
+			responded = true
+			if res.err != nil {
+				send(map[string]interface{}{"type": "error", "message": res.err.Error()})
+				return
+			}
+			send(map[string]interface{}{"type": "response", "data": res.data})
+			run.id = eventField(res.data, run.key)
+			if run.id == "" {
+				if v, ok := req.Params[run.key].(string); ok {
+					run.id = v
+				}
+			}
+			if run.id == "" {
+				// nothing to correlate: plain request/response method
+				send(map[string]interface{}{"type": "done", "reason": "no-stream"})
+				return
+			}
+			send(map[string]interface{}{"type": "subscribed", "correlationKey": run.key, "id": run.id})
+			for _, evt := range early {
+				if relay(evt) {
+					return
+				}
+			}
+			early = nil
+		case evt, ok := <-sub.C:
+			if !ok {
+				if sub.Overflowed() {
+					send(map[string]interface{}{"type": "error", "message": "stream fell behind: gateway events were dropped"})
+				}
+				return
+			}
+			if !responded {
+				if len(early) >= streamEarlyBuffer {
+					send(map[string]interface{}{"type": "error", "message": "too many events before the gateway response"})
+					return
+				}
+				early = append(early, evt)
+				continue
+			}
+			if relay(evt) {
+				return
+			}
+		case <-keepalive.C:
+			if !h.client.IsConnected() {
+				send(map[string]interface{}{"type": "error", "message": "gateway disconnected"})
+				return
+			}
+			sseMu.Lock()
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+			sseMu.Unlock()
+		case <-deadline.C:
+			send(map[string]interface{}{"type": "error", "message": "stream timed out"})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"openclawdeck/internal/openclaw"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronListHasJob(t *testing.T) {
+	assert.True(t, cronListHasJob(json.RawMessage(`{"jobs":[{"id":"daily"},{"id":"hourly"}]}`), "hourly"))
+	assert.True(t, cronListHasJob(json.RawMessage(`[{"id":"daily"}]`), "daily"))
+	assert.False(t, cronListHasJob(json.RawMessage(`{"jobs":[{"id":"daily"}]}`), "weekly"))
+	assert.False(t, cronListHasJob(json.RawMessage(`"oops"`), "daily"))
+}
+
+func TestAgentRequestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	enabled := true
+
+	assert.Error(t, (&agentRequest{}).validate("create"))
+	assert.NoError(t, (&agentRequest{Name: str("Ops Bot")}).validate("create"))
+	assert.Error(t, (&agentRequest{AgentID: "../etc"}).validate("update"))
+	assert.Error(t, (&agentRequest{AgentID: "ops", Model: str("gpt-4o")}).validate("update"))
+	assert.NoError(t, (&agentRequest{AgentID: "ops", Model: str("openai/gpt-4o")}).validate("update"))
+	assert.Error(t, (&agentRequest{AgentID: "ops"}).validate("enable"))
+	assert.NoError(t, (&agentRequest{AgentID: "ops", Enabled: &enabled}).validate("enable"))
+}
+
+func TestApplyAgentOp(t *testing.T) {
+	str := func(s string) *string { return &s }
+	disabled := false
+	cfg := map[string]interface{}{
+		"agents": map[string]interface{}{"list": []interface{}{
+			map[string]interface{}{"id": "main"},
+			map[string]interface{}{"id": "ops-bot"},
+		}},
+	}
+	list := func() []interface{} { return cfg["agents"].(map[string]interface{})["list"].([]interface{}) }
+
+	require.NoError(t, applyAgentOp(cfg, "create", &agentRequest{Name: str("Ops Bot!"), Emoji: str("🤖")}))
+	created := list()[2].(map[string]interface{})
+	assert.Equal(t, "ops-bot-2", created["id"])
+	assert.Equal(t, map[string]interface{}{"emoji": "🤖"}, created["identity"])
+
+	require.NoError(t, applyAgentOp(cfg, "update", &agentRequest{AgentID: "main", Model: str("openai/gpt-4o"), Avatar: str("a.png")}))
+	main := list()[0].(map[string]interface{})
+	assert.Equal(t, "openai/gpt-4o", main["model"])
+	assert.Equal(t, map[string]interface{}{"avatar": "a.png"}, main["identity"])
+
+	require.NoError(t, applyAgentOp(cfg, "enable", &agentRequest{AgentID: "ops-bot", Enabled: &disabled}))
+	assert.Equal(t, false, list()[1].(map[string]interface{})["enabled"])
+
+	require.NoError(t, applyAgentOp(cfg, "delete", &agentRequest{AgentID: "ops-bot"}))
+	assert.Len(t, list(), 2)
+
+	var nf *agentNotFoundError
+	assert.ErrorAs(t, applyAgentOp(cfg, "delete", &agentRequest{AgentID: "nope"}), &nf)
+}
+
+func TestAgentRequestRPCParams(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	// update sends present fields, including empty ones that clear a value
+	p := (&agentRequest{AgentID: "ops", Workspace: str(""), Model: str(" openai/gpt-4o ")}).rpcParams("update")
+	assert.Equal(t, map[string]interface{}{"agentId": "ops", "workspace": "", "model": "openai/gpt-4o"}, p)
+
+	// create omits empty optional fields
+	p = (&agentRequest{Name: str("Ops"), Workspace: str(" ")}).rpcParams("create")
+	assert.Equal(t, map[string]interface{}{"name": "Ops"}, p)
+
+	assert.Error(t, (&agentRequest{AgentID: "ops", Name: str(" ")}).validate("update"))
+}
+
+func TestRunBulk(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	results := runBulk([]string{"a", "b", "c", "d", "e"}, 2, func(key string) error {
+		n := inFlight.Add(1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		if key == "c" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	require.Len(t, results, 5)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		assert.Equal(t, key, results[i].Key)
+		assert.Equal(t, key != "c", results[i].OK)
+	}
+	assert.Equal(t, "boom", results[2].Error)
+}
+
+func TestStreamRunMatchAndTerminal(t *testing.T) {
+	evt := func(name, payload string) openclaw.GWEvent {
+		return openclaw.GWEvent{Event: name, Payload: json.RawMessage(payload)}
+	}
+	run := &streamRun{key: "runId", id: "r1"}
+
+	assert.False(t, run.matches(evt("chat", `{"runId":"r2","state":"final"}`)))
+	assert.True(t, run.matches(evt("agent", `{"runId":"r1","stream":"assistant"}`)))
+
+	// chat deltas seen: the lifecycle end is not terminal, the chat final is
+	assert.False(t, run.terminal(evt("chat", `{"runId":"r1","state":"delta"}`)))
+	assert.False(t, run.terminal(evt("agent", `{"runId":"r1","stream":"lifecycle","data":{"phase":"end"}}`)))
+	assert.True(t, run.terminal(evt("chat", `{"runId":"r1","state":"final"}`)))
+
+	// agent-only runs end on the lifecycle end
+	agentRun := &streamRun{key: "runId", id: "r1"}
+	assert.False(t, agentRun.terminal(evt("agent", `{"runId":"r1","stream":"lifecycle","data":{"phase":"start"}}`)))
+	assert.True(t, agentRun.terminal(evt("agent", `{"runId":"r1","stream":"lifecycle","data":{"phase":"error"}}`)))
+}
+
+func TestSkillsConfigGet_LocalFallback(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("OPENCLAW_STATE_DIR", stateDir)
+	client := openclaw.NewGWClient(openclaw.GWClientConfig{Host: "127.0.0.1", Port: 1})
+	t.Cleanup(client.Stop)
+	h := NewGWProxyHandler(client)
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.SkillsConfigGet(w, httptest.NewRequest(http.MethodGet, "/api/v1/gw/skills/config", nil))
+		return w
+	}
+
+	w := get()
+	require.Equal(t, http.StatusOK, w.Code, "a missing config means no overrides")
+
+	path := filepath.Join(stateDir, "openclaw.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"skills":{"entries":{"weather":{"enabled":false}}}}`), 0o600))
+	w = get()
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"weather":{"enabled":false}`)
+
+	require.NoError(t, os.WriteFile(path, []byte("{broken"), 0o600))
+	w = get()
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "CONFIG_READ_FAILED")
+}
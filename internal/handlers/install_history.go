@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/setup"
+	"openclawdeck/internal/web"
+)
+
+const (
+	// installLogMaxBytes bounds the stored log; the tail is kept since that is
+	// where failures show up.
+	installLogMaxBytes = 8 << 10
+	// installHistoryKeep is the number of install records kept in the DB.
+	installHistoryKeep = 100
+)
+
+// Install history actions.
+const (
+	installActionDeps      = "install-deps"
+	installActionOpenClaw  = "install-openclaw"
+	installActionAuto      = "auto-install"
+	installActionResume    = "resume"
+	installActionUpdate    = "update"
+	installActionUninstall = "uninstall"
+)
+
+// installRecord collects an install/update/uninstall run for the history.
+type installRecord struct {
+	entry database.InstallLog
+}
+
+// beginInstallRecord starts a history record, capturing the current OpenClaw version.
+func beginInstallRecord(action string) *installRecord {
+	return &installRecord{entry: database.InstallLog{
+		Action:      action,
+		FromVersion: setup.OpenClawVersion(),
+		StartedAt:   time.Now(),
+	}}
+}
+
+// finishStream saves the record using the emitter's transcript and last error.
+func (rec *installRecord) finishStream(emitter *setup.EventEmitter, success bool) {
+	errMsg := ""
+	if !success {
+		errMsg = emitter.LastError()
+	}
+	rec.finish(success, errMsg, emitter.Transcript())
+}
+
+// finish stores the record with the version OpenClaw ended up at and prunes old ones.
+func (rec *installRecord) finish(success bool, errMsg, log string) {
+	rec.entry.Success = success
+	rec.entry.Error = errMsg
+	rec.entry.Log = truncateInstallLog(log, installLogMaxBytes)
+	rec.entry.Version = setup.OpenClawVersion()
+	rec.entry.FinishedAt = time.Now()
+
+	repo := database.NewInstallLogRepo()
+	if err := repo.Create(&rec.entry); err != nil {
+		logger.Log.Warn().Err(err).Str("action", rec.entry.Action).Msg("failed to save install history")
+		return
+	}
+	if err := repo.Prune(installHistoryKeep); err != nil {
+		logger.Log.Warn().Err(err).Msg("failed to prune install history")
+	}
+}
+
+// truncateInstallLog keeps the last max bytes of log, cut at a line boundary.
+func truncateInstallLog(log string, max int) string {
+	if len(log) <= max {
+		return log
+	}
+	tail := log[len(log)-max:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	return "...(truncated)\n" + tail
+}
+
+// History lists recent install/update/uninstall runs, newest first.
+// GET /api/v1/setup/history?limit=20
+func (h *SetupWizardHandler) History(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = min(n, installHistoryKeep)
+		}
+	}
+	logs, err := database.NewInstallLogRepo().List(limit)
+	if err != nil {
+		web.FailErr(w, r, web.ErrDBQuery)
+		return
+	}
+	web.OK(w, r, logs)
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateInstallLog(t *testing.T) {
+	assert.Equal(t, "short", truncateInstallLog("short", 16))
+
+	log := strings.Repeat("x", 10) + "\nline two\nline three"
+	got := truncateInstallLog(log, 16)
+	assert.Equal(t, "...(truncated)\nline three", got)
+}
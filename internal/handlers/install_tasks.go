@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/web"
+)
+
+// installTaskHeader carries the task ID of a streaming install so the client
+// can cancel it before the stream ends.
+const installTaskHeader = "X-Install-Task-Id"
+
+// installTasks tracks cancel funcs of running installs by task ID.
+type installTasks struct {
+	mu    sync.Mutex
+	tasks map[string]context.CancelFunc
+}
+
+var runningInstalls = &installTasks{tasks: map[string]context.CancelFunc{}}
+
+// start derives a cancellable context with the given timeout and registers it.
+// The returned done func must be called when the install finishes.
+func (t *installTasks) start(parent context.Context, timeout time.Duration) (id string, ctx context.Context, done func()) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	id = uuid.New().String()
+
+	t.mu.Lock()
+	t.tasks[id] = cancel
+	t.mu.Unlock()
+
+	return id, ctx, func() {
+		t.mu.Lock()
+		delete(t.tasks, id)
+		t.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels a running install. It reports false if the task is unknown
+// or has already finished.
+func (t *installTasks) cancel(id string) bool {
+	t.mu.Lock()
+	cancel, ok := t.tasks[id]
+	delete(t.tasks, id)
+	t.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// installCancelled reports whether ctx ended because the user cancelled the
+// install (as opposed to a timeout).
+func installCancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// installFailedMessage replaces msg when the install was cancelled or timed out.
+func installFailedMessage(ctx context.Context, msg string) string {
+	switch {
+	case installCancelled(ctx):
+		return "install cancelled"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "install timed out"
+	}
+	return msg
+}
+
+// cancelInstallTask handles POST {"taskId": "..."} for the cancel-install endpoints.
+func cancelInstallTask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TaskID string `json:"taskId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TaskID == "" {
+		web.Fail(w, r, "INVALID_PARAMS", "taskId is required", http.StatusBadRequest)
+		return
+	}
+	if !runningInstalls.cancel(req.TaskID) {
+		web.Fail(w, r, "INSTALL_TASK_NOT_FOUND", "install task not found or already finished", http.StatusNotFound)
+		return
+	}
+	logger.Log.Info().Str("task", req.TaskID).Msg("install cancelled by user")
+	web.OK(w, r, map[string]interface{}{"taskId": req.TaskID, "cancelled": true})
+}
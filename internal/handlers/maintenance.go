@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
+	"openclawdeck/internal/web"
+)
+
+// MaintenanceHandler toggles maintenance mode.
+type MaintenanceHandler struct {
+	m *monitor.Maintenance
+}
+
+func NewMaintenanceHandler(m *monitor.Maintenance) *MaintenanceHandler {
+	return &MaintenanceHandler{m: m}
+}
+
+// Get returns the current maintenance mode state.
+// GET /api/v1/admin/maintenance
+func (h *MaintenanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, h.m.State())
+}
+
+// Set turns maintenance mode on or off. While on, health-check auto-restart,
+// alerts, notifications and event collection are paused; turning it off
+// restores them from their own settings.
+// POST /api/v1/admin/maintenance
+func (h *MaintenanceHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+
+	st, err := h.m.Set(req.Enabled)
+	if err != nil {
+		auditMutationResult(r, constants.ActionMaintenance, "failed", err.Error())
+		web.FailErr(w, r, web.ErrSettingsUpdateFail, err.Error())
+		return
+	}
+	auditMutation(r, constants.ActionMaintenance, "maintenance mode: "+strconv.FormatBool(req.Enabled))
+	logger.Log.Info().Bool("enabled", req.Enabled).Str("user", web.GetUsername(r)).Msg("maintenance mode updated")
+	web.OK(w, r, st)
+}
@@ -5,12 +5,19 @@ import (
 	"time"
 
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/web"
 )
 
+// monitorStaleAfter is how long the collector may go without writing an
+// activity before stats report it as stale.
+const monitorStaleAfter = time.Hour
+
 // MonitorHandler provides monitoring statistics.
 type MonitorHandler struct {
 	activityRepo *database.ActivityRepo
+	gwCollector  *monitor.GWCollector
+	idleReset    *monitor.IdleSessionResetter
 }
 
 func NewMonitorHandler() *MonitorHandler {
@@ -29,6 +36,38 @@ type MonitorStatsResponse struct {
 	ToolCounts     map[string]int64 `json:"tool_counts"`
 	HourlyCounts   map[string]int64 `json:"hourly_counts"`
 	DailyCounts    map[string]int64 `json:"daily_counts"`
+
+	// collector freshness: stale means no activity for monitorStaleAfter
+	// (measured from collector start when nothing has been written yet)
+	LastEventAt           *time.Time `json:"last_event_at,omitempty"`
+	SecondsSinceLastEvent int64      `json:"seconds_since_last_event"`
+	Stale                 bool       `json:"stale"`
+}
+
+// SetGWCollector injects the gateway event collector for freshness stats.
+func (h *MonitorHandler) SetGWCollector(c *monitor.GWCollector) {
+	h.gwCollector = c
+}
+
+// SetIdleResetter injects the idle session resetter for the dry-run preview.
+func (h *MonitorHandler) SetIdleResetter(r *monitor.IdleSessionResetter) {
+	h.idleReset = r
+}
+
+// IdleSessionsPreview lists the sessions the idle reset would reset right now,
+// without resetting anything.
+// GET /api/v1/monitor/idle-sessions/preview
+func (h *MonitorHandler) IdleSessionsPreview(w http.ResponseWriter, r *http.Request) {
+	if h.idleReset == nil {
+		web.FailErr(w, r, web.ErrGWNotConnected)
+		return
+	}
+	preview, err := h.idleReset.Preview()
+	if err != nil {
+		web.Fail(w, r, "IDLE_PREVIEW_FAILED", err.Error(), http.StatusBadGateway)
+		return
+	}
+	web.OK(w, r, preview)
 }
 
 // Stats returns monitoring statistics.
@@ -44,7 +83,7 @@ func (h *MonitorHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	hourlyCounts, _ := h.activityRepo.CountByHour(now.Add(-48 * time.Hour))
 	dailyCounts, _ := h.activityRepo.CountByDay(now.Add(-182 * 24 * time.Hour))
 
-	web.OK(w, r, MonitorStatsResponse{
+	resp := MonitorStatsResponse{
 		TotalEvents:    total,
 		Events24h:      events24h,
 		Events1h:       events1h,
@@ -53,5 +92,22 @@ func (h *MonitorHandler) Stats(w http.ResponseWriter, r *http.Request) {
 		ToolCounts:     toolCounts,
 		HourlyCounts:   hourlyCounts,
 		DailyCounts:    dailyCounts,
-	})
+	}
+	h.fillFreshness(&resp, now)
+	web.OK(w, r, resp)
+}
+
+// fillFreshness sets last-event freshness fields from the collector.
+func (h *MonitorHandler) fillFreshness(resp *MonitorStatsResponse, now time.Time) {
+	resp.SecondsSinceLastEvent = -1
+	if h.gwCollector == nil {
+		return
+	}
+	ref := h.gwCollector.StartedAt()
+	if last := h.gwCollector.LastEventAt(); !last.IsZero() {
+		resp.LastEventAt = &last
+		resp.SecondsSinceLastEvent = int64(now.Sub(last).Seconds())
+		ref = last
+	}
+	resp.Stale = h.gwCollector.IsRunning() && !ref.IsZero() && now.Sub(ref) > monitorStaleAfter
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"openclawdeck/internal/database"
@@ -13,9 +14,10 @@ import (
 
 // MonitorConfigHandler manages monitor configuration.
 type MonitorConfigHandler struct {
-	auditRepo *database.AuditLogRepo
-	monSvc    *monitor.Service
-	cfg       *webconfig.Config
+	auditRepo   *database.AuditLogRepo
+	monSvc      *monitor.Service
+	gwCollector *monitor.GWCollector
+	cfg         *webconfig.Config
 }
 
 func NewMonitorConfigHandler(monSvc *monitor.Service, cfg *webconfig.Config) *MonitorConfigHandler {
@@ -26,6 +28,11 @@ func NewMonitorConfigHandler(monSvc *monitor.Service, cfg *webconfig.Config) *Mo
 	}
 }
 
+// SetGWCollector injects the gateway event collector so interval changes apply to it too.
+func (h *MonitorConfigHandler) SetGWCollector(c *monitor.GWCollector) {
+	h.gwCollector = c
+}
+
 // GetConfig returns monitor configuration.
 func (h *MonitorConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
 	web.OK(w, r, map[string]interface{}{
@@ -50,8 +57,13 @@ func (h *MonitorConfigHandler) UpdateConfig(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if req.IntervalSeconds != nil && *req.IntervalSeconds < monitor.MinPollIntervalSeconds {
+		web.FailErr(w, r, web.ErrInvalidParam, fmt.Sprintf("interval_seconds must be >= %d", monitor.MinPollIntervalSeconds))
+		return
+	}
+
 	changed := false
-	if req.IntervalSeconds != nil && *req.IntervalSeconds >= 5 {
+	if req.IntervalSeconds != nil {
 		h.cfg.Monitor.IntervalSeconds = *req.IntervalSeconds
 		changed = true
 	}
@@ -79,12 +91,20 @@ func (h *MonitorConfigHandler) UpdateConfig(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// hot-reload poll interval without restarting the collectors
+	if req.IntervalSeconds != nil {
+		h.monSvc.SetInterval(*req.IntervalSeconds)
+		if h.gwCollector != nil {
+			h.gwCollector.SetInterval(*req.IntervalSeconds)
+		}
+	}
+
 	h.auditRepo.Create(&database.AuditLog{
 		UserID:   web.GetUserID(r),
 		Username: web.GetUsername(r),
 		Action:   "monitor.config.update",
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Log.Info().Str("user", web.GetUsername(r)).Msg("monitor config updated")
@@ -104,7 +124,7 @@ func (h *MonitorConfigHandler) StartMonitor(w http.ResponseWriter, r *http.Reque
 		Username: web.GetUsername(r),
 		Action:   "monitor.start",
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	web.OK(w, r, map[string]string{"message": "ok"})
@@ -123,7 +143,7 @@ func (h *MonitorConfigHandler) StopMonitor(w http.ResponseWriter, r *http.Reques
 		Username: web.GetUsername(r),
 		Action:   "monitor.stop",
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	web.OK(w, r, map[string]string{"message": "ok"})
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"openclawdeck/internal/constants"
@@ -50,6 +51,11 @@ var notifySettingKeys = []string{
 	"notify_webhook_template",
 	"notify_enabled",
 	"notify_min_risk",
+	notify.AlertRoutingSetting,
+	notify.QuietHoursStartSetting,
+	notify.QuietHoursEndSetting,
+	notify.QuietHoursModeSetting,
+	NotifyAccountLockedSetting,
 }
 
 // GetConfig returns current notification configuration.
@@ -94,6 +100,18 @@ func (h *NotifyHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		web.FailErr(w, r, web.ErrInvalidParam)
 		return
 	}
+	// keys with a registered definition (routing, quiet hours) are validated
+	for k, v := range filtered {
+		if _, ok := database.LookupSetting(k); !ok {
+			continue
+		}
+		norm, err := database.ValidateSetting(k, v)
+		if err != nil {
+			web.FailErr(w, r, web.ErrSettingsInvalid, k+": "+err.Error())
+			return
+		}
+		filtered[k] = norm
+	}
 
 	if err := h.settingRepo.SetBatch(filtered); err != nil {
 		web.FailErr(w, r, web.ErrSettingsUpdateFail)
@@ -103,6 +121,7 @@ func (h *NotifyHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 	// Reload notification channels
 	gwChannels := h.fetchGWChannels()
 	h.manager.Reload(h.settingRepo, gwChannels)
+	invalidateDashboard()
 
 	h.auditRepo.Create(&database.AuditLog{
 		UserID:   web.GetUserID(r),
@@ -110,7 +129,7 @@ func (h *NotifyHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionSettingsUpdate,
 		Detail:   "notification config updated",
 		Result:   "success",
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Log.Info().Str("user", web.GetUsername(r)).Msg("notification config updated")
@@ -138,10 +157,45 @@ func (h *NotifyHandler) TestSend(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.manager.Send(req.Message)
+	// tests bypass quiet hours so the admin sees the result right away
+	h.manager.SendNow(req.Message)
 	web.OK(w, r, map[string]string{"message": "ok"})
 }
 
+// TestAll sends a test message through every configured channel separately and
+// reports per-channel success, upstream status and latency.
+// POST /api/v1/notify/test-all
+func (h *NotifyHandler) TestAll(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if req.Message == "" {
+		req.Message = "🔔 OpenClawDeck 通知测试 / Notification Test"
+	}
+
+	if !h.manager.HasChannels() {
+		web.Fail(w, r, "NO_CHANNELS", "no notification channels configured", http.StatusBadRequest)
+		return
+	}
+
+	results := h.manager.TestAll(r.Context(), req.Message)
+	failed := 0
+	for _, res := range results {
+		if !res.OK {
+			failed++
+		}
+	}
+	logger.Log.Info().Int("channels", len(results)).Int("failed", failed).Msg("notification channels tested")
+	web.OK(w, r, map[string]interface{}{
+		"results": results,
+		"failed":  failed,
+	})
+}
+
 // getAvailableChannels returns openclaw channel types that have tokens configured.
 func (h *NotifyHandler) getAvailableChannels() []map[string]interface{} {
 	var result []map[string]interface{}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+)
+
+// PairingHMACSecretSetting is the shared secret external systems use to sign
+// pairing approvals. When set, POST /api/v1/pairing/approve must carry:
+//
+//	X-Deck-Timestamp: <unix seconds>
+//	X-Deck-Signature: sha256=<hex HMAC-SHA256(secret, timestamp + "." + raw body)>
+//
+// Unsigned, mis-signed, stale or replayed requests are rejected with 401.
+const PairingHMACSecretSetting = "pairing_hmac_secret"
+
+const (
+	pairingTimestampHeader = "X-Deck-Timestamp"
+	pairingSignatureHeader = "X-Deck-Signature"
+	// pairingSignatureMaxSkew bounds how old (or far in the future) a signed request may be.
+	pairingSignatureMaxSkew = 5 * time.Minute
+	// pairingSecretMinLen keeps the shared secret out of brute-force range.
+	pairingSecretMinLen = 16
+)
+
+func init() {
+	database.RegisterSettings(database.SettingDef{
+		Key:         PairingHMACSecretSetting,
+		Type:        database.SettingString,
+		Description: "shared secret for HMAC-signed pairing approvals (empty disables signing)",
+		Validate: func(v string) error {
+			if len(v) < pairingSecretMinLen {
+				return errors.New("must be at least " + strconv.Itoa(pairingSecretMinLen) + " characters")
+			}
+			return nil
+		},
+	})
+}
+
+var (
+	errPairingUnsigned     = errors.New("missing signature headers")
+	errPairingBadTimestamp = errors.New("invalid or expired timestamp")
+	errPairingBadSignature = errors.New("signature mismatch")
+	errPairingReplayed     = errors.New("signature already used")
+)
+
+// signPairingRequest returns the X-Deck-Signature value for a body.
+func signPairingRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyPairingSignature checks the timestamp window and the HMAC in constant time.
+func verifyPairingSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || signature == "" {
+		return errPairingUnsigned
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errPairingBadTimestamp
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > pairingSignatureMaxSkew || skew < -pairingSignatureMaxSkew {
+		return errPairingBadTimestamp
+	}
+	want := signPairingRequest(secret, timestamp, body)
+	if !hmac.Equal([]byte(want), []byte(strings.ToLower(strings.TrimSpace(signature)))) {
+		return errPairingBadSignature
+	}
+	return nil
+}
+
+// pairingReplayGuard remembers accepted signatures for the skew window so a
+// captured request can't be replayed.
+type pairingReplayGuard struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+var pairingReplays = &pairingReplayGuard{seen: map[string]time.Time{}}
+
+// use records sig and reports false if it was already used within the window.
+func (g *pairingReplayGuard) use(sig string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for s, at := range g.seen {
+		if now.Sub(at) > 2*pairingSignatureMaxSkew {
+			delete(g.seen, s)
+		}
+	}
+	if _, dup := g.seen[sig]; dup {
+		return false
+	}
+	g.seen[sig] = now
+	return true
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyPairingSignature(t *testing.T) {
+	secret := "0123456789abcdef-shared"
+	now := time.Unix(1_800_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"channel":"telegram","code":"ABCD1234"}`)
+	sig := signPairingRequest(secret, ts, body)
+
+	assert.NoError(t, verifyPairingSignature(secret, ts, sig, body, now))
+	assert.NoError(t, verifyPairingSignature(secret, ts, sig, body, now.Add(4*time.Minute)))
+
+	assert.ErrorIs(t, verifyPairingSignature(secret, "", "", body, now), errPairingUnsigned)
+	assert.ErrorIs(t, verifyPairingSignature(secret, ts, sig, body, now.Add(6*time.Minute)), errPairingBadTimestamp)
+	assert.ErrorIs(t, verifyPairingSignature(secret, "soon", sig, body, now), errPairingBadTimestamp)
+	assert.ErrorIs(t, verifyPairingSignature("other-secret-value", ts, sig, body, now), errPairingBadSignature)
+	assert.ErrorIs(t, verifyPairingSignature(secret, ts, sig, []byte(`{"channel":"telegram","code":"EVIL0000"}`), now), errPairingBadSignature)
+}
+
+func TestPairingReplayGuard(t *testing.T) {
+	g := &pairingReplayGuard{seen: map[string]time.Time{}}
+	now := time.Now()
+	assert.True(t, g.use("sha256=aa", now))
+	assert.False(t, g.use("sha256=aa", now.Add(time.Minute)))
+	assert.True(t, g.use("sha256=bb", now))
+	// entries expire once the timestamp window has passed
+	assert.True(t, g.use("sha256=aa", now.Add(11*time.Minute)))
+}
@@ -3,28 +3,49 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/security"
 	"openclawdeck/internal/web"
 )
 
-// SecurityHandler manages security rules.
+// SecurityHandler manages the rules in the risk_rules table. The same CRUD
+// serves the security engine (/api/v1/security/rules) and the local alert
+// rules (/api/v1/alert-rules); only the path prefix and the reload hook differ.
 type SecurityHandler struct {
 	ruleRepo *database.RiskRuleRepo
-	engine   *security.Engine
+	prefix   string
+	reload   func() error
 }
 
 func NewSecurityHandler(engine *security.Engine) *SecurityHandler {
 	return &SecurityHandler{
 		ruleRepo: database.NewRiskRuleRepo(),
-		engine:   engine,
+		prefix:   "/api/v1/security/rules/",
+		reload:   engine.Reload,
 	}
 }
 
+// NewAlertRuleHandler serves alert rules (regex classification of gateway tool
+// events). Matching only raises the activity risk and notifies — nothing is
+// intercepted.
+func NewAlertRuleHandler(matcher *monitor.AlertRuleMatcher) *SecurityHandler {
+	h := &SecurityHandler{
+		ruleRepo: database.NewRiskRuleRepo(),
+		prefix:   "/api/v1/alert-rules/",
+	}
+	if matcher != nil {
+		h.reload = matcher.Reload
+	}
+	return h
+}
+
 // ListRules returns all risk rules.
 func (h *SecurityHandler) ListRules(w http.ResponseWriter, r *http.Request) {
 	rules, err := h.ruleRepo.ListAll()
@@ -58,6 +79,13 @@ func (h *SecurityHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
 		web.FailErr(w, r, web.ErrInvalidParam)
 		return
 	}
+	if _, err := regexp.Compile(req.Pattern); err != nil {
+		web.FailErr(w, r, web.ErrInvalidParam, "invalid pattern: "+err.Error())
+		return
+	}
+	if req.Risk == "" {
+		req.Risk = "high"
+	}
 
 	if existing, _ := h.ruleRepo.FindByRuleID(req.RuleID); existing != nil {
 		web.FailErr(w, r, web.ErrSecurityRuleExists)
@@ -80,22 +108,21 @@ func (h *SecurityHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.engine.Reload()
-
+	h.reloadRules()
+	auditMutation(r, constants.ActionRuleCreate, "rule created: "+rule.RuleID)
 	logger.Security.Info().Str("rule_id", req.RuleID).Msg("custom rule created")
 	web.OK(w, r, rule)
 }
 
 // UpdateRule updates a rule.
 func (h *SecurityHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/security/rules/")
-	id, err := strconv.ParseUint(idStr, 10, 64)
-	if err != nil || id == 0 {
+	id, ok := h.ruleID(r)
+	if !ok {
 		web.FailErr(w, r, web.ErrInvalidParam)
 		return
 	}
 
-	existing, err := h.ruleRepo.FindByID(uint(id))
+	existing, err := h.ruleRepo.FindByID(id)
 	if err != nil {
 		web.FailErr(w, r, web.ErrNotFound)
 		return
@@ -118,6 +145,10 @@ func (h *SecurityHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
 			existing.Risk = req.Risk
 		}
 		if req.Pattern != "" {
+			if _, err := regexp.Compile(req.Pattern); err != nil {
+				web.FailErr(w, r, web.ErrInvalidParam, "invalid pattern: "+err.Error())
+				return
+			}
 			existing.Pattern = req.Pattern
 		}
 		if req.Reason != "" {
@@ -134,22 +165,21 @@ func (h *SecurityHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.engine.Reload()
-
+	h.reloadRules()
+	auditMutation(r, constants.ActionRuleUpdate, "rule updated: "+existing.RuleID)
 	logger.Security.Info().Str("rule_id", existing.RuleID).Msg("rule updated")
 	web.OK(w, r, existing)
 }
 
 // DeleteRule deletes a rule (builtin rules cannot be deleted).
 func (h *SecurityHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/security/rules/")
-	id, err := strconv.ParseUint(idStr, 10, 64)
-	if err != nil || id == 0 {
+	id, ok := h.ruleID(r)
+	if !ok {
 		web.FailErr(w, r, web.ErrInvalidParam)
 		return
 	}
 
-	existing, err := h.ruleRepo.FindByID(uint(id))
+	existing, err := h.ruleRepo.FindByID(id)
 	if err != nil {
 		web.FailErr(w, r, web.ErrNotFound)
 		return
@@ -160,13 +190,31 @@ func (h *SecurityHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.ruleRepo.Delete(uint(id)); err != nil {
+	if err := h.ruleRepo.Delete(id); err != nil {
 		web.FailErr(w, r, web.ErrSecurityDeleteFail)
 		return
 	}
 
-	h.engine.Reload()
-
+	h.reloadRules()
+	auditMutation(r, constants.ActionRuleDelete, "rule deleted: "+existing.RuleID)
 	logger.Security.Info().Str("rule_id", existing.RuleID).Msg("rule deleted")
 	web.OK(w, r, map[string]string{"message": "ok"})
 }
+
+func (h *SecurityHandler) reloadRules() {
+	if h.reload == nil {
+		return
+	}
+	if err := h.reload(); err != nil {
+		logger.Security.Warn().Err(err).Msg("failed to reload rules")
+	}
+}
+
+// ruleID parses the rule id from {prefix}{id}.
+func (h *SecurityHandler) ruleID(r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, h.prefix), 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
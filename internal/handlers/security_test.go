@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityHandlerRuleID_PerPrefix(t *testing.T) {
+	alerts := NewAlertRuleHandler(nil)
+
+	id, ok := alerts.ruleID(httptest.NewRequest("PUT", "/api/v1/alert-rules/42", nil))
+	assert.True(t, ok)
+	assert.Equal(t, uint(42), id)
+
+	_, ok = alerts.ruleID(httptest.NewRequest("PUT", "/api/v1/security/rules/42", nil))
+	assert.False(t, ok, "security path is not an alert rule id")
+
+	_, ok = alerts.ruleID(httptest.NewRequest("DELETE", "/api/v1/alert-rules/0", nil))
+	assert.False(t, ok)
+}
+
+func TestSecurityHandler_RuleMutationsAreAudited(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, database.DB.AutoMigrate(&database.RiskRule{}))
+	h := NewAlertRuleHandler(nil)
+
+	call := func(fn http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+		req := web.SetUserInfo(httptest.NewRequest(method, path, bytes.NewBufferString(body)), 1, "admin", "admin")
+		w := httptest.NewRecorder()
+		fn(w, req)
+		return w
+	}
+
+	w := call(h.CreateRule, http.MethodPost, "/api/v1/alert-rules", `{"rule_id":"custom-1","pattern":"rm -rf","reason":"wipe","enabled":true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data database.RiskRule `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	path := "/api/v1/alert-rules/" + strconv.FormatUint(uint64(resp.Data.ID), 10)
+	require.Equal(t, http.StatusOK, call(h.UpdateRule, http.MethodPut, path, `{"enabled":false}`).Code)
+	require.Equal(t, http.StatusOK, call(h.DeleteRule, http.MethodDelete, path, ``).Code)
+
+	logs, _, err := database.NewAuditLogRepo().List(database.AuditFilter{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	actions := map[string]string{}
+	for _, l := range logs {
+		actions[l.Action] = l.Detail
+	}
+	assert.Equal(t, map[string]string{
+		constants.ActionRuleCreate: "rule created: custom-1",
+		constants.ActionRuleUpdate: "rule updated: custom-1",
+		constants.ActionRuleDelete: "rule deleted: custom-1",
+	}, actions)
+}
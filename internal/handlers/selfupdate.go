@@ -12,6 +12,7 @@ import (
 
 	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/setup"
 	"openclawdeck/internal/updater"
 	"openclawdeck/internal/version"
 	"openclawdeck/internal/web"
@@ -33,13 +34,13 @@ func (h *SelfUpdateHandler) Check(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	result, err := updater.CheckForUpdate(ctx)
-	if err != nil {
-		web.Fail(w, r, "UPDATE_CHECK_FAILED", err.Error(), http.StatusInternalServerError)
+	// explicit checks always hit GitHub and refresh the cache used by badge counts
+	res := updater.CachedCheck(ctx, true)
+	if res.Failed() {
+		web.FailErr(w, r, web.ErrUpdateCheckFail, res.Error)
 		return
 	}
-
-	web.OK(w, r, result)
+	web.OK(w, r, res)
 }
 
 // Apply downloads and applies the update, streaming progress via SSE.
@@ -83,7 +84,7 @@ func (h *SelfUpdateHandler) Apply(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.auditRepo.Create(&database.AuditLog{
 			UserID: web.GetUserID(r), Username: web.GetUsername(r),
-			Action: constants.ActionSelfUpdate, Result: "failed", Detail: err.Error(), IP: r.RemoteAddr,
+			Action: constants.ActionSelfUpdate, Result: "failed", Detail: err.Error(), IP: web.ClientIP(r),
 		})
 		sendSSE(updater.ApplyProgress{Stage: "error", Error: err.Error()})
 		return
@@ -91,7 +92,7 @@ func (h *SelfUpdateHandler) Apply(w http.ResponseWriter, r *http.Request) {
 
 	h.auditRepo.Create(&database.AuditLog{
 		UserID: web.GetUserID(r), Username: web.GetUsername(r),
-		Action: constants.ActionSelfUpdate, Result: "success", Detail: "update applied", IP: r.RemoteAddr,
+		Action: constants.ActionSelfUpdate, Result: "success", Detail: "update applied", IP: web.ClientIP(r),
 	})
 
 	// Send final success
@@ -104,6 +105,70 @@ func (h *SelfUpdateHandler) Apply(w http.ResponseWriter, r *http.Request) {
 	}()
 }
 
+// Changelog returns OpenClawDeck release notes between the running version and
+// the latest release (override with ?from=&to=).
+// GET /api/v1/self-update/changelog
+func (h *SelfUpdateHandler) Changelog(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+
+	from, to, ok := changelogRange(w, r)
+	if !ok {
+		return
+	}
+	if from == "" {
+		from = version.Version
+	}
+	if to == "" {
+		to = updater.CachedCheck(ctx, false).LatestVersion
+	}
+	web.OK(w, r, updater.DeckChangelog(ctx, from, to))
+}
+
+// OpenClawChangelog returns OpenClaw release notes between the installed and the
+// latest npm version (override with ?from=&to=).
+// GET /api/v1/self-update/openclaw-changelog
+func (h *SelfUpdateHandler) OpenClawChangelog(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+
+	from, to, ok := changelogRange(w, r)
+	if !ok {
+		return
+	}
+	if from == "" || to == "" {
+		info := setup.CachedOpenClawUpdate(false)
+		if from == "" {
+			from = info.CurrentVersion
+		}
+		if to == "" {
+			to = info.LatestVersion
+		}
+	}
+	if to == "" {
+		web.OK(w, r, &updater.Changelog{
+			Project:  "openclaw",
+			From:     from,
+			Releases: []updater.ReleaseNote{},
+			Error:    "latest OpenClaw version unknown",
+		})
+		return
+	}
+	web.OK(w, r, updater.OpenClawChangelog(ctx, from, to))
+}
+
+// changelogRange reads the optional ?from=&to= versions, rejecting non-semver values.
+func changelogRange(w http.ResponseWriter, r *http.Request) (from, to string, ok bool) {
+	from, to = r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	for _, v := range []string{from, to} {
+		if v != "" && !updater.ValidVersion(v) {
+			web.FailErr(w, r, web.ErrInvalidParam, "version must be semver")
+			return "", "", false
+		}
+	}
+	return from, to, true
+}
+
 // Info returns current version and build info.
 func (h *SelfUpdateHandler) Info(w http.ResponseWriter, r *http.Request) {
 	web.OK(w, r, map[string]interface{}{
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"openclawdeck/internal/constants"
 	"openclawdeck/internal/logger"
 	"openclawdeck/internal/web"
 	"openclawdeck/internal/webconfig"
@@ -18,9 +20,10 @@ func NewServerConfigHandler() *ServerConfigHandler {
 }
 
 type serverConfigPayload struct {
-	Bind        string   `json:"bind"`
-	Port        int      `json:"port"`
-	CORSOrigins []string `json:"cors_origins"`
+	Bind        string                `json:"bind"`
+	Port        int                   `json:"port"`
+	CORSOrigins []string              `json:"cors_origins"`
+	CORSRoutes  []webconfig.CORSRoute `json:"cors_routes"`
 }
 
 // Get returns the current server config.
@@ -35,6 +38,7 @@ func (h *ServerConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
 		Bind:        cfg.Server.Bind,
 		Port:        cfg.Server.Port,
 		CORSOrigins: cfg.Server.CORSOrigins,
+		CORSRoutes:  cfg.Server.CORSRoutes,
 	})
 }
 
@@ -59,6 +63,19 @@ func (h *ServerConfigHandler) Update(w http.ResponseWriter, r *http.Request) {
 		bind = "0.0.0.0"
 	}
 
+	// Reject malformed and wildcard origins
+	if err := (web.CORSPolicy{Origins: payload.CORSOrigins}).Validate(); err != nil {
+		web.Fail(w, r, "INVALID_CORS", err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, rt := range payload.CORSRoutes {
+		p := web.CORSPolicy{PathPrefix: rt.PathPrefix, Origins: rt.Origins, Credentials: rt.Credentials}
+		if err := p.Validate(); err != nil {
+			web.Fail(w, r, "INVALID_CORS", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Load current config, update server section only, then save
 	cfg, err := webconfig.Load()
 	if err != nil {
@@ -66,15 +83,22 @@ func (h *ServerConfigHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	prevBind, prevPort := cfg.Server.Bind, cfg.Server.Port
 	cfg.Server.Bind = bind
 	cfg.Server.Port = payload.Port
 	cfg.Server.CORSOrigins = payload.CORSOrigins
+	if payload.CORSRoutes != nil { // older clients omit route policies; keep them
+		cfg.Server.CORSRoutes = payload.CORSRoutes
+	}
 
 	if err := webconfig.Save(cfg); err != nil {
 		web.Fail(w, r, "SERVER_CONFIG_SAVE_ERROR", err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	auditMutation(r, constants.ActionSettingsUpdate, fmt.Sprintf("server config: %s:%d → %s:%d, cors origins %v, %d route policies",
+		prevBind, prevPort, bind, payload.Port, payload.CORSOrigins, len(cfg.Server.CORSRoutes)))
+
 	logger.Log.Info().
 		Str("bind", bind).
 		Int("port", payload.Port).
@@ -84,6 +108,7 @@ func (h *ServerConfigHandler) Update(w http.ResponseWriter, r *http.Request) {
 		"bind":         bind,
 		"port":         payload.Port,
 		"cors_origins": payload.CORSOrigins,
+		"cors_routes":  cfg.Server.CORSRoutes,
 		"restart":      true,
 	})
 }
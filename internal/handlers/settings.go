@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
@@ -15,15 +20,14 @@ import (
 // SettingsHandler manages system settings.
 type SettingsHandler struct {
 	settingRepo *database.SettingRepo
-	auditRepo   *database.AuditLogRepo
 	gwClient    *openclaw.GWClient
 	gwService   *openclaw.Service
+	maintenance *monitor.Maintenance
 }
 
 func NewSettingsHandler() *SettingsHandler {
 	return &SettingsHandler{
 		settingRepo: database.NewSettingRepo(),
-		auditRepo:   database.NewAuditLogRepo(),
 	}
 }
 
@@ -32,6 +36,12 @@ func (h *SettingsHandler) SetGWClient(client *openclaw.GWClient) {
 	h.gwClient = client
 }
 
+// SetMaintenance injects the maintenance mode switch so maintenance_mode
+// changes made here take effect immediately.
+func (h *SettingsHandler) SetMaintenance(m *monitor.Maintenance) {
+	h.maintenance = m
+}
+
 // SetGWService injects the OpenClaw service reference.
 func (h *SettingsHandler) SetGWService(svc *openclaw.Service) {
 	h.gwService = svc
@@ -44,6 +54,15 @@ func (h *SettingsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		web.FailErr(w, r, web.ErrSettingsQueryFail)
 		return
 	}
+	// internal cache of the last authenticated gateway token; never expose it
+	delete(settings, "gateway_last_good_token")
+	delete(settings, setupProgressKey)
+	// secrets (the settings encrypted at rest) are write-only; report only whether they are set
+	for key, v := range settings {
+		if v != "" && database.IsSecretSetting(key) {
+			settings[key] = diag.Redacted
+		}
+	}
 	web.OK(w, r, settings)
 }
 
@@ -60,23 +79,58 @@ func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// validate against the settings registry and store normalized values
+	var problems []string
+	for key, value := range items {
+		if database.IsSecretSetting(key) && value == diag.Redacted {
+			delete(items, key) // masked value echoed back by GetAll: keep the stored secret
+			continue
+		}
+		v, err := database.ValidateSetting(key, value)
+		if err != nil {
+			problems = append(problems, key+": "+err.Error())
+			continue
+		}
+		items[key] = v
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		web.FailErr(w, r, web.ErrSettingsInvalid, strings.Join(problems, "; "))
+		return
+	}
+
 	if err := h.settingRepo.SetBatch(items); err != nil {
+		auditMutationResult(r, constants.ActionSettingsUpdate, "failed", "settings: "+settingsChangeSummary(items))
 		web.FailErr(w, r, web.ErrSettingsUpdateFail)
 		return
 	}
+	if v, ok := items[openclaw.GatewayProbePortsSetting]; ok {
+		ports, _ := openclaw.ParseGatewayPorts(v)
+		openclaw.SetExtraGatewayPorts(ports)
+	}
+	if _, ok := items[openclaw.GatewayCompressionSetting]; ok && h.gwClient != nil {
+		// takes effect on the next (re)connect
+		h.gwClient.SetCompression(h.settingRepo.GetBool(openclaw.GatewayCompressionSetting))
+	}
+	if v, ok := items[LogLevelSetting]; ok {
+		applyLogLevel(v)
+	}
+	if _, ok := items[monitor.MaintenanceModeSetting]; ok && h.maintenance != nil {
+		h.maintenance.Apply(h.settingRepo.GetBool(monitor.MaintenanceModeSetting))
+	}
 
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   constants.ActionSettingsUpdate,
-		Result:   "success",
-		IP:       r.RemoteAddr,
-	})
+	auditMutation(r, constants.ActionSettingsUpdate, "settings: "+settingsChangeSummary(items))
 
 	logger.Config.Info().Str("user", web.GetUsername(r)).Msg("settings updated")
 	web.OK(w, r, map[string]string{"message": "ok"})
 }
 
+// Schema lists the known settings with their type, default and limits.
+// GET /api/v1/settings/schema
+func (h *SettingsHandler) Schema(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, database.SettingDefs())
+}
+
 // GetGatewayConfig returns the Gateway connection config.
 func (h *SettingsHandler) GetGatewayConfig(w http.ResponseWriter, r *http.Request) {
 	cfg := h.gwClient.GetConfig()
@@ -108,6 +162,8 @@ func (h *SettingsHandler) UpdateGatewayConfig(w http.ResponseWriter, r *http.Req
 		req.Port = 18789
 	}
 
+	prev := h.gwClient.GetConfig()
+
 	// persist to settings table
 	h.settingRepo.SetBatch(map[string]string{
 		"gateway_host":  req.Host,
@@ -119,7 +175,7 @@ func (h *SettingsHandler) UpdateGatewayConfig(w http.ResponseWriter, r *http.Req
 	if h.gwService != nil {
 		h.gwService.GatewayHost = req.Host
 		h.gwService.GatewayPort = req.Port
-		h.gwService.GatewayToken = req.Token
+		h.gwService.SetGatewayToken(req.Token)
 	}
 
 	// reconnect GWClient
@@ -130,14 +186,11 @@ func (h *SettingsHandler) UpdateGatewayConfig(w http.ResponseWriter, r *http.Req
 	}
 	h.gwClient.Reconnect(newCfg)
 
-	h.auditRepo.Create(&database.AuditLog{
-		UserID:   web.GetUserID(r),
-		Username: web.GetUsername(r),
-		Action:   constants.ActionSettingsUpdate,
-		Detail:   "gateway config updated: " + req.Host + ":" + strconv.Itoa(req.Port),
-		Result:   "success",
-		IP:       r.RemoteAddr,
-	})
+	detail := fmt.Sprintf("gateway config: %s:%d → %s:%d", prev.Host, prev.Port, req.Host, req.Port)
+	if prev.Token != req.Token {
+		detail += ", token changed"
+	}
+	auditMutation(r, constants.ActionSettingsUpdate, detail)
 
 	logger.Config.Info().
 		Str("user", web.GetUsername(r)).
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/web"
+)
+
+// LogLevelSetting overrides the log level from the deck config; empty means
+// use the config value. It is applied at startup and whenever it changes.
+const LogLevelSetting = "log_level_override"
+
+func init() {
+	database.RegisterSettings(database.SettingDef{
+		Key:         LogLevelSetting,
+		Type:        database.SettingString,
+		Enum:        logger.Levels,
+		Description: "log level override applied at startup (empty: use the config file level)",
+	})
+}
+
+// ApplyLogLevelSetting applies a stored log level override, if any.
+func ApplyLogLevelSetting(repo *database.SettingRepo) {
+	if v := repo.GetString(LogLevelSetting); v != "" {
+		if err := logger.SetLevel(v); err == nil {
+			logger.Log.Info().Str("level", v).Msg("log level override applied")
+		}
+	}
+}
+
+// applyLogLevel switches the live level to v, or back to the config level when v is empty.
+func applyLogLevel(v string) {
+	if v == "" {
+		logger.ResetLevel()
+		return
+	}
+	logger.SetLevel(v)
+}
+
+// GetLogLevel returns the current, configured and persisted log levels.
+// GET /api/v1/settings/log-level
+func (h *SettingsHandler) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	web.OK(w, r, map[string]interface{}{
+		"level":      logger.Level(),
+		"configured": logger.ConfiguredLevel(),
+		"persisted":  h.settingRepo.GetString(LogLevelSetting),
+		"levels":     logger.Levels,
+	})
+}
+
+// UpdateLogLevel changes the log level without a restart. With persist the
+// override is stored and applied again at startup; an empty level reverts to
+// the config level (and clears the stored override when persisting).
+// PUT /api/v1/settings/log-level
+func (h *SettingsHandler) UpdateLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Level   string `json:"level"`
+		Persist bool   `json:"persist"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	level, err := database.ValidateSetting(LogLevelSetting, strings.ToLower(req.Level))
+	if err != nil {
+		web.FailErr(w, r, web.ErrSettingsInvalid, "level: "+err.Error())
+		return
+	}
+
+	prev := logger.Level()
+	if req.Persist {
+		if err := h.settingRepo.Set(LogLevelSetting, level); err != nil {
+			auditMutationResult(r, constants.ActionSettingsUpdate, "failed", "log level: "+prev+" → "+level)
+			web.FailErr(w, r, web.ErrSettingsUpdateFail)
+			return
+		}
+	}
+	applyLogLevel(level)
+
+	detail := "log level: " + prev + " → " + logger.Level()
+	if req.Persist {
+		detail += " (persisted)"
+	}
+	auditMutation(r, constants.ActionSettingsUpdate, detail)
+	logger.Config.Warn().
+		Str("user", web.GetUsername(r)).
+		Str("from", prev).
+		Str("to", logger.Level()).
+		Bool("persist", req.Persist).
+		Msg("log level changed")
+
+	h.GetLogLevel(w, r)
+}
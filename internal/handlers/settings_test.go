@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/diag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsGetAll_MasksSecretSettings(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := database.NewSettingRepo()
+	require.NoError(t, repo.SetBatch(map[string]string{
+		"outbound_webhook_url":  "https://hooks.example.com/T0/secret-path",
+		"notify_telegram_token": "123:abc",
+		"notify_slack_token":    "",
+		"language":              "en",
+	}))
+
+	w := httptest.NewRecorder()
+	NewSettingsHandler().GetAll(w, httptest.NewRequest(http.MethodGet, "/api/v1/settings", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, diag.Redacted, resp.Data["outbound_webhook_url"])
+	assert.Equal(t, diag.Redacted, resp.Data["notify_telegram_token"])
+	assert.Empty(t, resp.Data["notify_slack_token"], "unset secrets stay empty")
+	assert.Equal(t, "en", resp.Data["language"])
+	assert.NotContains(t, w.Body.String(), "secret-path")
+}
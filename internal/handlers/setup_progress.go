@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/setup"
+)
+
+// setupProgressKey is the settings key holding auto-install progress.
+const setupProgressKey = "setup_install_progress"
+
+// installProgress persists which auto-install steps have completed so an
+// interrupted install can be resumed. Secrets are never stored.
+type installProgress struct {
+	Request   AutoInstallRequest `json:"request"`
+	Done      map[string]bool    `json:"completed"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+
+	repo *database.SettingRepo
+	mu   sync.Mutex
+}
+
+func newInstallProgress(repo *database.SettingRepo, req AutoInstallRequest) *installProgress {
+	req.APIKey = ""
+	req.SudoPassword = ""
+	p := &installProgress{Request: req, Done: map[string]bool{}, repo: repo}
+	p.save()
+	return p
+}
+
+// loadInstallProgress returns the saved progress, or nil if no install is pending.
+func loadInstallProgress(repo *database.SettingRepo) *installProgress {
+	raw, err := repo.Get(setupProgressKey)
+	if err != nil || raw == "" {
+		return nil
+	}
+	p := &installProgress{repo: repo}
+	if err := json.Unmarshal([]byte(raw), p); err != nil {
+		return nil
+	}
+	if p.Done == nil {
+		p.Done = map[string]bool{}
+	}
+	return p
+}
+
+// Completed implements setup.StepTracker.
+func (p *installProgress) Completed(step string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Done[step]
+}
+
+// MarkCompleted implements setup.StepTracker.
+func (p *installProgress) MarkCompleted(step string) {
+	p.mu.Lock()
+	p.Done[step] = true
+	p.mu.Unlock()
+	p.save()
+}
+
+// completedSteps lists completed steps in install order.
+func (p *installProgress) completedSteps() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var steps []string
+	for _, s := range setup.InstallSteps {
+		if p.Done[s] {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+func (p *installProgress) save() {
+	p.mu.Lock()
+	p.UpdatedAt = time.Now()
+	data, err := json.Marshal(p)
+	p.mu.Unlock()
+	if err != nil {
+		return
+	}
+	if err := p.repo.Set(setupProgressKey, string(data)); err != nil {
+		logger.Log.Warn().Err(err).Msg("failed to save install progress")
+	}
+}
+
+func (p *installProgress) clear() {
+	if err := p.repo.Delete(setupProgressKey); err != nil {
+		logger.Log.Warn().Err(err).Msg("failed to clear install progress")
+	}
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+// verify check statuses
+const (
+	verifyPass = "pass"
+	verifyFail = "fail"
+	verifySkip = "skip"
+)
+
+// VerifyCheck is a single step of the full setup verification.
+type VerifyCheck struct {
+	Name      string `json:"name"` // binary / config / gateway / model / channel
+	Status    string `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// VerifyAllResult is the per-check report returned by VerifyAll.
+type VerifyAllResult struct {
+	Checks    []VerifyCheck `json:"checks"`
+	AllPassed bool          `json:"allPassed"`
+}
+
+// VerifyAllRequest controls the full verification.
+type VerifyAllRequest struct {
+	// NoStart skips starting a stopped local gateway; the gateway check then fails.
+	NoStart bool `json:"noStart,omitempty"`
+}
+
+// verifyConfig is the parsed openclaw.json plus the .env it is resolved against.
+type verifyConfig struct {
+	raw    map[string]any
+	dotEnv map[string]string
+}
+
+// expand resolves ${VAR} references the same way the gateway does.
+func (c *verifyConfig) expand(s string) string {
+	return openclaw.ExpandEnvRefs(s, c.raw, c.dotEnv)
+}
+
+// VerifyAll runs an end-to-end check of the setup: the openclaw binary runs, the
+// config parses and lints clean, the gateway is up (starting it if needed) and
+// answers /health, the primary model probes successfully and at least one
+// configured channel validates.
+// POST /api/v1/setup/verify-all
+func (h *SetupWizardHandler) VerifyAll(w http.ResponseWriter, r *http.Request) {
+	var req VerifyAllRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	// the probe helpers on WizardHandler don't touch handler state
+	probes := &WizardHandler{}
+	res := &VerifyAllResult{}
+	run := func(name string, fn func() (string, string)) string {
+		start := time.Now()
+		status, detail := fn()
+		res.Checks = append(res.Checks, VerifyCheck{
+			Name:      name,
+			Status:    status,
+			Detail:    detail,
+			LatencyMs: time.Since(start).Milliseconds(),
+		})
+		return status
+	}
+
+	binaryOK := run("binary", verifyBinary) == verifyPass
+
+	var cfg *verifyConfig
+	run("config", func() (string, string) {
+		var status, detail string
+		cfg, status, detail = loadVerifyConfig()
+		return status, detail
+	})
+
+	run("gateway", func() (string, string) {
+		return h.verifyGateway(binaryOK, !req.NoStart)
+	})
+
+	run("model", func() (string, string) {
+		if cfg == nil {
+			return verifySkip, "config not available"
+		}
+		return verifyModel(probes, cfg)
+	})
+
+	run("channel", func() (string, string) {
+		if cfg == nil {
+			return verifySkip, "config not available"
+		}
+		return verifyChannels(probes, cfg)
+	})
+
+	res.AllPassed = true
+	for _, c := range res.Checks {
+		if c.Status != verifyPass {
+			res.AllPassed = false
+		}
+	}
+	web.OK(w, r, res)
+}
+
+// verifyBinary checks that `openclaw --version` actually runs.
+func verifyBinary() (string, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "openclaw", "--version").CombinedOutput()
+	if err != nil {
+		if len(strings.TrimSpace(string(out))) > 0 {
+			return verifyFail, fmt.Sprintf("openclaw --version failed: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return verifyFail, fmt.Sprintf("openclaw --version failed: %v", err)
+	}
+	return verifyPass, strings.TrimSpace(string(out))
+}
+
+// loadVerifyConfig parses openclaw.json and lints it; lint errors fail the check.
+func loadVerifyConfig() (*verifyConfig, string, string) {
+	path := openclaw.ResolveConfigPath()
+	if path == "" {
+		return nil, verifyFail, "config path not resolved"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, verifyFail, err.Error()
+	}
+	cfg := &verifyConfig{}
+	if err := json.Unmarshal(data, &cfg.raw); err != nil {
+		return nil, verifyFail, fmt.Sprintf("invalid JSON: %v", err)
+	}
+	if cfg.dotEnv, err = openclaw.ReadDotEnv(openclaw.DotEnvPath()); err != nil {
+		cfg.dotEnv = map[string]string{}
+	}
+
+	var errs []string
+	for _, issue := range openclaw.LintConfigFile(path) {
+		if issue.Level == openclaw.IssueError {
+			errs = append(errs, issue.Message)
+		}
+	}
+	if len(errs) > 0 {
+		// the file parsed, so later checks can still use it
+		return cfg, verifyFail, strings.Join(errs, "; ")
+	}
+	return cfg, verifyPass, path
+}
+
+// verifyGateway makes sure the gateway is running (starting a local one if allowed)
+// and that GET /health answers with a 2xx.
+func (h *SetupWizardHandler) verifyGateway(binaryOK, allowStart bool) (string, string) {
+	status := h.svc.Status()
+	if !status.Running {
+		if h.svc.IsRemote() || !allowStart {
+			return verifyFail, "gateway is not running"
+		}
+		if !binaryOK {
+			return verifyFail, "gateway is not running and openclaw cannot be started"
+		}
+		if err := h.svc.Start(); err != nil {
+			return verifyFail, fmt.Sprintf("start failed: %v", err)
+		}
+		for i := 0; i < 20; i++ {
+			time.Sleep(500 * time.Millisecond)
+			if status = h.svc.Status(); status.Running {
+				break
+			}
+		}
+		if !status.Running {
+			return verifyFail, "gateway did not come up within 10s"
+		}
+	}
+
+	port := status.Port
+	if port == 0 {
+		port = h.svc.GatewayPort
+	}
+	// the port may open a moment before the HTTP server is ready
+	var err error
+	for i := 0; i < 5; i++ {
+		if err = gatewayHealth(h.svc.GatewayHost, port); err == nil {
+			return verifyPass, fmt.Sprintf("healthy on port %d", port)
+		}
+		time.Sleep(time.Second)
+	}
+	return verifyFail, err.Error()
+}
+
+// gatewayHealth requires a 2xx from GET /health.
+func gatewayHealth(host string, port int) error {
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	url := "http://" + net.JoinHostPort(host, strconv.Itoa(port)) + "/health"
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("health check failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health check returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// primaryModel reads agents.defaults.model.primary (or the string shorthand) as provider/model.
+func primaryModel(raw map[string]any) (provider, model string) {
+	agents, _ := raw["agents"].(map[string]any)
+	defaults, _ := agents["defaults"].(map[string]any)
+	var primary string
+	switch v := defaults["model"].(type) {
+	case string:
+		primary = v
+	case map[string]any:
+		primary, _ = v["primary"].(string)
+	}
+	provider, model, _ = strings.Cut(strings.TrimSpace(primary), "/")
+	return provider, model
+}
+
+// modelProbeRequest builds the probe for the primary model, resolving the API key
+// from models.providers.<p>.apiKey or the provider's conventional env var.
+func modelProbeRequest(cfg *verifyConfig) (TestModelRequest, error) {
+	provider, model := primaryModel(cfg.raw)
+	if provider == "" || model == "" {
+		return TestModelRequest{}, fmt.Errorf("agents.defaults.model.primary is not set")
+	}
+	req := TestModelRequest{Provider: provider, Model: model}
+
+	models, _ := cfg.raw["models"].(map[string]any)
+	providers, _ := models["providers"].(map[string]any)
+	if p, ok := providers[provider].(map[string]any); ok {
+		if s, ok := p["baseUrl"].(string); ok {
+			req.BaseURL = cfg.expand(s)
+		}
+		if s, ok := p["apiKey"].(string); ok {
+			req.APIKey = cfg.expand(s)
+		}
+	}
+	if req.APIKey == "" {
+		if key := providerEnvKey(provider); key != "" {
+			req.APIKey = cfg.expand("${" + key + "}")
+		}
+	}
+	return req, nil
+}
+
+func verifyModel(probes *WizardHandler, cfg *verifyConfig) (string, string) {
+	req, err := modelProbeRequest(cfg)
+	if err != nil {
+		return verifyFail, err.Error()
+	}
+	if _, err := probes.probeModel(req); err != nil {
+		return verifyFail, fmt.Sprintf("%s/%s: %v", req.Provider, req.Model, err)
+	}
+	return verifyPass, req.Provider + "/" + req.Model
+}
+
+// channelTokens returns the enabled channels with their string settings (env refs expanded).
+func channelTokens(cfg *verifyConfig) map[string]map[string]string {
+	out := map[string]map[string]string{}
+	channels, _ := cfg.raw["channels"].(map[string]any)
+	for name, v := range channels {
+		ch, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if enabled, ok := ch["enabled"].(bool); ok && !enabled {
+			continue
+		}
+		tokens := map[string]string{}
+		for k, val := range ch {
+			if s, ok := val.(string); ok {
+				tokens[k] = cfg.expand(s)
+			}
+		}
+		out[name] = tokens
+	}
+	return out
+}
+
+// verifyChannels passes as soon as one configured channel validates. Telegram and
+// Discord tokens are checked against their APIs, other channels by token format.
+func verifyChannels(probes *WizardHandler, cfg *verifyConfig) (string, string) {
+	channels := channelTokens(cfg)
+	if len(channels) == 0 {
+		return verifyFail, "no channel configured"
+	}
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		tokens := channels[name]
+		err := probes.validateChannelTokens(name, tokens)
+		if err == nil {
+			switch name {
+			case "telegram":
+				_, err = probes.testTelegramToken(tokens["botToken"])
+			case "discord":
+				_, err = probes.testDiscordToken(tokens["token"])
+			}
+		}
+		if err == nil {
+			return verifyPass, name
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+	}
+	return verifyFail, strings.Join(failures, "; ")
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyConfigExtraction(t *testing.T) {
+	cfg := &verifyConfig{dotEnv: map[string]string{"OPENAI_API_KEY": "sk-env", "TG_TOKEN": "123:abc"}}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"agents": {"defaults": {"model": {"primary": "openrouter/anthropic/claude"}}},
+		"models": {"providers": {"openrouter": {"baseUrl": "https://openrouter.ai/api/v1", "apiKey": "${OR_KEY}"}}},
+		"channels": {
+			"telegram": {"botToken": "${TG_TOKEN}", "dmPolicy": "pairing"},
+			"discord": {"enabled": false, "token": "x"}
+		}
+	}`), &cfg.raw))
+	t.Setenv("OR_KEY", "sk-or")
+
+	req, err := modelProbeRequest(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, TestModelRequest{Provider: "openrouter", Model: "anthropic/claude", BaseURL: "https://openrouter.ai/api/v1", APIKey: "sk-or"}, req)
+
+	assert.Equal(t, map[string]map[string]string{
+		"telegram": {"botToken": "123:abc", "dmPolicy": "pairing"},
+	}, channelTokens(cfg))
+
+	// string shorthand for the model, key from the provider's conventional env var
+	cfg.raw = map[string]any{"agents": map[string]any{"defaults": map[string]any{"model": "openai/gpt-4o"}}}
+	req, err = modelProbeRequest(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, TestModelRequest{Provider: "openai", Model: "gpt-4o", APIKey: "sk-env"}, req)
+
+	_, err = modelProbeRequest(&verifyConfig{raw: map[string]any{}})
+	assert.Error(t, err)
+}
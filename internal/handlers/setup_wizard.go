@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/setup"
 	"openclawdeck/internal/web"
@@ -15,15 +17,17 @@ import (
 
 // SetupWizardHandler handles the setup wizard API.
 type SetupWizardHandler struct {
-	auditRepo *database.AuditLogRepo
-	svc       *openclaw.Service
-	gwClient  *openclaw.GWClient
+	auditRepo   *database.AuditLogRepo
+	settingRepo *database.SettingRepo
+	svc         *openclaw.Service
+	gwClient    *openclaw.GWClient
 }
 
 // NewSetupWizardHandler creates a new SetupWizardHandler.
 func NewSetupWizardHandler(svc *openclaw.Service) *SetupWizardHandler {
 	return &SetupWizardHandler{
-		svc: svc,
+		settingRepo: database.NewSettingRepo(),
+		svc:         svc,
 	}
 }
 
@@ -42,7 +46,7 @@ func (h *SetupWizardHandler) SetAuditRepo(repo *database.AuditLogRepo) {
 func (h *SetupWizardHandler) Scan(w http.ResponseWriter, r *http.Request) {
 	report, err := setup.Scan()
 	if err != nil {
-		web.Fail(w, r, "SCAN_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrScanError, err.Error())
 		return
 	}
 	web.OK(w, r, report)
@@ -50,8 +54,21 @@ func (h *SetupWizardHandler) Scan(w http.ResponseWriter, r *http.Request) {
 
 // InstallDepsRequest is the install dependencies request.
 type InstallDepsRequest struct {
-	InstallNode bool `json:"installNode"`
-	InstallGit  bool `json:"installGit"`
+	InstallNode bool   `json:"installNode"`
+	InstallGit  bool   `json:"installGit"`
+	Registry    string `json:"registry,omitempty"` // npm mirror, also selects a Node.js download mirror
+}
+
+// validRegistry normalizes an npm registry from a request body, answering 400
+// when it is not an http(s) URL. It must run before the SSE stream starts.
+func validRegistry(w http.ResponseWriter, r *http.Request, registry *string) bool {
+	normalized, err := setup.NormalizeRegistry(*registry)
+	if err != nil {
+		web.FailErr(w, r, web.ErrInvalidParam, "registry: "+err.Error())
+		return false
+	}
+	*registry = normalized
+	return true
 }
 
 // InstallDeps installs dependencies (SSE streaming).
@@ -63,46 +80,56 @@ func (h *SetupWizardHandler) InstallDeps(w http.ResponseWriter, r *http.Request)
 		req.InstallNode = true
 		req.InstallGit = true
 	}
+	if !validRegistry(w, r, &req.Registry) {
+		return
+	}
+
+	taskID, ctx, done := runningInstalls.start(r.Context(), 10*time.Minute)
+	defer done()
+	w.Header().Set(installTaskHeader, taskID)
 
 	// create SSE event emitter
 	emitter, err := setup.NewEventEmitter(w)
 	if err != nil {
-		web.Fail(w, r, "SSE_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrSSEError, err.Error())
 		return
 	}
 
+	rec, success := beginInstallRecord(installActionDeps), false
+	defer func() { rec.finishStream(emitter, success) }()
+
 	env, err := setup.Scan()
 	if err != nil {
 		emitter.EmitError("environment scan failed", map[string]string{"error": err.Error()})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Minute)
-	defer cancel()
-
 	installer := setup.NewInstaller(emitter, env)
+	installer.SetRegistry(req.Registry) // validated above
 
 	if req.InstallNode && !env.Tools["node"].Installed {
 		if err := installer.InstallNode(ctx); err != nil {
-			emitter.EmitError("Node.js install failed", map[string]string{"error": err.Error()})
+			emitter.EmitError(installFailedMessage(ctx, "Node.js install failed"), map[string]string{"error": err.Error()})
 			return
 		}
 	}
 
 	if req.InstallGit && !env.Tools["git"].Installed {
 		if err := installer.InstallGit(ctx); err != nil {
-			emitter.EmitError("Git install failed", map[string]string{"error": err.Error()})
+			emitter.EmitError(installFailedMessage(ctx, "Git install failed"), map[string]string{"error": err.Error()})
 			return
 		}
 	}
 
+	success = true
 	emitter.EmitComplete("dependency install complete", nil)
 }
 
 // InstallOpenClawRequest is the install OpenClaw request.
 type InstallOpenClawRequest struct {
-	Method  string `json:"method,omitempty"` // "installer-script" | "npm"
-	Version string `json:"version,omitempty"`
+	Method   string `json:"method,omitempty"` // "installer-script" | "npm"
+	Version  string `json:"version,omitempty"`
+	Registry string `json:"registry,omitempty"`
 }
 
 // InstallOpenClaw installs OpenClaw (SSE streaming).
@@ -110,13 +137,23 @@ type InstallOpenClawRequest struct {
 func (h *SetupWizardHandler) InstallOpenClaw(w http.ResponseWriter, r *http.Request) {
 	var req InstallOpenClawRequest
 	json.NewDecoder(r.Body).Decode(&req)
+	if !validRegistry(w, r, &req.Registry) {
+		return
+	}
+
+	taskID, ctx, done := runningInstalls.start(r.Context(), 15*time.Minute)
+	defer done()
+	w.Header().Set(installTaskHeader, taskID)
 
 	emitter, err := setup.NewEventEmitter(w)
 	if err != nil {
-		web.Fail(w, r, "SSE_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrSSEError, err.Error())
 		return
 	}
 
+	rec, success := beginInstallRecord(installActionOpenClaw), false
+	defer func() { rec.finishStream(emitter, success) }()
+
 	env, err := setup.Scan()
 	if err != nil {
 		emitter.EmitError("environment scan failed", map[string]string{"error": err.Error()})
@@ -128,16 +165,15 @@ func (h *SetupWizardHandler) InstallOpenClaw(w http.ResponseWriter, r *http.Requ
 		env.RecommendedMethod = req.Method
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Minute)
-	defer cancel()
-
 	installer := setup.NewInstaller(emitter, env)
+	installer.SetRegistry(req.Registry) // validated above
 
 	if err := installer.InstallOpenClaw(ctx); err != nil {
-		emitter.EmitError("OpenClaw install failed", map[string]string{"error": err.Error()})
+		emitter.EmitError(installFailedMessage(ctx, "OpenClaw install failed"), map[string]string{"error": err.Error()})
 		return
 	}
 
+	success = true
 	emitter.EmitComplete("OpenClaw install complete", nil)
 }
 
@@ -180,7 +216,7 @@ func (h *SetupWizardHandler) Configure(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := installer.ConfigureOpenClaw(ctx, config); err != nil {
-		web.Fail(w, r, "CONFIG_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrSetupConfigureFailed, err.Error())
 		return
 	}
 
@@ -190,8 +226,21 @@ func (h *SetupWizardHandler) Configure(w http.ResponseWriter, r *http.Request) {
 // StartGateway starts the Gateway.
 // POST /api/v1/setup/start-gateway
 func (h *SetupWizardHandler) StartGateway(w http.ResponseWriter, r *http.Request) {
+	// preflight: ${VAR} references in openclaw.json that nothing defines make the
+	// gateway start fine but fail on the first request; warn, don't block
+	unresolved, err := openclaw.CheckCurrentEnvRefs()
+	if err != nil {
+		logger.Log.Debug().Err(err).Msg("env reference preflight skipped")
+	}
+	if unresolved == nil {
+		unresolved = []openclaw.UnresolvedEnvVar{}
+	}
+	for _, v := range unresolved {
+		logger.Log.Warn().Str("var", v.Name).Strs("paths", v.Paths).Msg("config references an undefined env var")
+	}
+
 	if err := h.svc.Start(); err != nil {
-		web.Fail(w, r, "START_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrSetupStartFailed, err.Error())
 		return
 	}
 
@@ -201,8 +250,9 @@ func (h *SetupWizardHandler) StartGateway(w http.ResponseWriter, r *http.Request
 		status := h.svc.Status()
 		if status.Running {
 			web.OK(w, r, map[string]interface{}{
-				"running": true,
-				"detail":  status.Detail,
+				"running":       true,
+				"detail":        status.Detail,
+				"unresolvedEnv": unresolved,
 			})
 			return
 		}
@@ -231,6 +281,8 @@ type AutoInstallRequest struct {
 	SkipConfig        bool   `json:"skipConfig,omitempty"`
 	SkipGateway       bool   `json:"skipGateway,omitempty"`
 	SudoPassword      string `json:"sudoPassword,omitempty"`
+	OfflineTarball    string `json:"offlineTarball,omitempty"`
+	OfflineCacheDir   string `json:"offlineCacheDir,omitempty"`
 }
 
 // AutoInstall runs full automatic installation (SSE streaming).
@@ -243,12 +295,74 @@ func (h *SetupWizardHandler) AutoInstall(w http.ResponseWriter, r *http.Request)
 		req.APIKey = ""
 	}
 
+	h.runAutoInstall(w, r, req, newInstallProgress(h.settingRepo, req), installActionAuto)
+}
+
+// Resume continues an interrupted auto-install: re-scans, skips completed steps
+// and runs the rest (SSE streaming). Secrets (apiKey, sudoPassword) are not
+// persisted and must be sent again.
+// POST /api/v1/setup/resume
+func (h *SetupWizardHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	progress := loadInstallProgress(h.settingRepo)
+	if progress == nil {
+		web.FailErr(w, r, web.ErrSetupNoProgress)
+		return
+	}
+
+	var secrets struct {
+		APIKey       string `json:"apiKey"`
+		SudoPassword string `json:"sudoPassword"`
+	}
+	json.NewDecoder(r.Body).Decode(&secrets) // body is optional
+
+	req := progress.Request
+	req.APIKey = secrets.APIKey
+	req.SudoPassword = secrets.SudoPassword
+	if !req.SkipConfig && req.Provider != "" && req.APIKey == "" && !progress.Completed(setup.StepConfigure) {
+		web.FailErr(w, r, web.ErrSetupResumeNeedsKey)
+		return
+	}
+
+	h.runAutoInstall(w, r, req, progress, installActionResume)
+}
+
+// Progress returns the saved auto-install progress (null if none).
+// GET /api/v1/setup/progress
+func (h *SetupWizardHandler) Progress(w http.ResponseWriter, r *http.Request) {
+	progress := loadInstallProgress(h.settingRepo)
+	if progress == nil {
+		web.OK(w, r, nil)
+		return
+	}
+	web.OK(w, r, map[string]interface{}{
+		"request":   progress.Request,
+		"completed": progress.completedSteps(),
+		"steps":     setup.InstallSteps,
+		"updatedAt": progress.UpdatedAt,
+	})
+}
+
+func (h *SetupWizardHandler) runAutoInstall(w http.ResponseWriter, r *http.Request, req AutoInstallRequest, progress *installProgress, action string) {
+	if !validRegistry(w, r, &req.Registry) {
+		return
+	}
+	taskID, ctx, done := runningInstalls.start(r.Context(), 20*time.Minute)
+	defer done()
+	w.Header().Set(installTaskHeader, taskID)
+
 	emitter, err := setup.NewEventEmitter(w)
 	if err != nil {
-		web.Fail(w, r, "SSE_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrSSEError, err.Error())
 		return
 	}
 
+	rec, success := beginInstallRecord(action), false
+	defer func() { rec.finishStream(emitter, success) }()
+
+	if done := progress.completedSteps(); len(done) > 0 {
+		emitter.EmitLog("resuming install, completed steps: " + strings.Join(done, ", "))
+	}
+
 	emitter.EmitPhase("scan", "scanning environment...", 0)
 	env, err := setup.Scan()
 	if err != nil {
@@ -257,10 +371,8 @@ func (h *SetupWizardHandler) AutoInstall(w http.ResponseWriter, r *http.Request)
 	}
 	emitter.EmitSuccess("environment scan complete", env)
 
-	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Minute)
-	defer cancel()
-
 	installer := setup.NewInstaller(emitter, env)
+	installer.SetStepTracker(progress)
 
 	config := setup.InstallConfig{
 		Provider:          req.Provider,
@@ -275,18 +387,32 @@ func (h *SetupWizardHandler) AutoInstall(w http.ResponseWriter, r *http.Request)
 		SkipConfig:        req.SkipConfig,
 		SkipGateway:       req.SkipGateway,
 		SudoPassword:      req.SudoPassword,
+		OfflineTarball:    req.OfflineTarball,
+		OfflineCacheDir:   req.OfflineCacheDir,
 	}
 
 	_, err = installer.AutoInstall(ctx, config)
 	if err != nil {
-		// error already sent in AutoInstall
+		// error already sent in AutoInstall; progress is kept for /setup/resume
+		if installCancelled(ctx) {
+			emitter.EmitLog("install cancelled, use resume to continue")
+		}
 		return
 	}
+	success = true
+	progress.clear()
 
 	// after install, read gateway token from openclaw.json and reconnect GWClient
 	h.syncGatewayToken()
 }
 
+// CancelInstall cancels a running streaming install by the task ID returned in
+// the X-Install-Task-Id response header.
+// POST /api/v1/setup/cancel-install
+func (h *SetupWizardHandler) CancelInstall(w http.ResponseWriter, r *http.Request) {
+	cancelInstallTask(w, r)
+}
+
 // syncGatewayToken reads gateway.auth.token from openclaw.json and reconnects GWClient.
 func (h *SetupWizardHandler) syncGatewayToken() {
 	if h.gwClient == nil {
@@ -332,12 +458,27 @@ func (h *SetupWizardHandler) syncGatewayToken() {
 // UpdateOpenClaw updates OpenClaw to the latest version (SSE streaming).
 // POST /api/v1/setup/update-openclaw
 func (h *SetupWizardHandler) UpdateOpenClaw(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Registry string `json:"registry,omitempty"`
+	}
+	json.NewDecoder(r.Body).Decode(&req) // body is optional
+	if !validRegistry(w, r, &req.Registry) {
+		return
+	}
+
+	taskID, ctx, done := runningInstalls.start(r.Context(), 10*time.Minute)
+	defer done()
+	w.Header().Set(installTaskHeader, taskID)
+
 	emitter, err := setup.NewEventEmitter(w)
 	if err != nil {
-		web.Fail(w, r, "SSE_ERROR", err.Error(), http.StatusInternalServerError)
+		web.FailErr(w, r, web.ErrSSEError, err.Error())
 		return
 	}
 
+	rec, success := beginInstallRecord(installActionUpdate), false
+	defer func() { rec.finishStream(emitter, success) }()
+
 	emitter.EmitPhase("update", "Checking current version...", 0)
 
 	env, err := setup.Scan()
@@ -357,10 +498,8 @@ func (h *SetupWizardHandler) UpdateOpenClaw(w http.ResponseWriter, r *http.Reque
 	}
 	emitter.EmitLog("Current version: " + oldVersion)
 
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Minute)
-	defer cancel()
-
 	installer := setup.NewInstaller(emitter, env)
+	installer.SetRegistry(req.Registry) // validated above
 
 	// Stop gateway before update to release file locks (avoids EBUSY on Windows)
 	gwWasRunning := false
@@ -378,7 +517,7 @@ func (h *SetupWizardHandler) UpdateOpenClaw(w http.ResponseWriter, r *http.Reque
 		if gwWasRunning && h.svc != nil {
 			_ = h.svc.Start()
 		}
-		emitter.EmitError("Update failed: "+err.Error(), nil)
+		emitter.EmitError(installFailedMessage(ctx, "Update failed: "+err.Error()), nil)
 		return
 	}
 
@@ -399,6 +538,7 @@ func (h *SetupWizardHandler) UpdateOpenClaw(w http.ResponseWriter, r *http.Reque
 		_ = h.svc.Start()
 	}
 
+	success = true
 	emitter.EmitComplete("Update complete", map[string]interface{}{
 		"oldVersion": oldVersion,
 		"newVersion": newVersion,
@@ -424,8 +564,10 @@ func (h *SetupWizardHandler) Uninstall(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
 	defer cancel()
 
+	rec := beginInstallRecord(installActionUninstall)
 	output, err := openclaw.RunCLI(ctx, "uninstall", "--all", "--yes", "--non-interactive")
 	if err != nil {
+		rec.finish(false, err.Error(), output)
 		web.FailErr(w, r, web.ErrUninstallFailed, err.Error())
 		return
 	}
@@ -433,6 +575,7 @@ func (h *SetupWizardHandler) Uninstall(w http.ResponseWriter, r *http.Request) {
 	npmPkg := clawCmd
 	npmOutput, npmErr := openclaw.NpmUninstallGlobal(ctx, npmPkg)
 	if npmErr != nil {
+		rec.finish(false, npmErr.Error(), output+"\n"+npmOutput)
 		web.OK(w, r, map[string]string{
 			"message": "config cleaned, but CLI uninstall failed. Run manually: npm uninstall -g " + npmPkg,
 			"output":  output + "\n" + npmOutput,
@@ -442,6 +585,7 @@ func (h *SetupWizardHandler) Uninstall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rec.finish(true, "", output+"\n"+npmOutput)
 	web.OK(w, r, map[string]string{
 		"message": "ok",
 		"output":  output + "\n" + npmOutput,
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"openclawdeck/internal/web"
+)
+
+// skillSlugPattern is the strict skill name check used wherever a slug becomes
+// part of a file path or registry URL: no separators, no "..", no leading dot.
+var skillSlugPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+func validSkillSlug(slug string) bool {
+	return skillSlugPattern.MatchString(slug) && !strings.Contains(slug, "..")
+}
+
+// maxSkillReadmeBytes bounds SKILL.md reads from disk and from the registry.
+const maxSkillReadmeBytes = 1 << 20
+
+// SkillReadme is a skill's SKILL.md split into frontmatter and markdown body.
+type SkillReadme struct {
+	Slug        string                 `json:"slug"`
+	Source      string                 `json:"source"` // local / registry
+	Path        string                 `json:"path,omitempty"`
+	Content     string                 `json:"content"`
+	Frontmatter map[string]interface{} `json:"frontmatter"`
+	Body        string                 `json:"body"`
+}
+
+// Readme returns a skill's SKILL.md: the installed copy for local gateways, or
+// the registry copy for skills that are not installed (and for remote gateways,
+// whose skill directory is not readable from here).
+// GET /api/v1/skills/{slug}/readme
+func (h *ClawHubHandler) Readme(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/skills/")
+	slug, ok := strings.CutSuffix(rest, "/readme")
+	if !ok {
+		web.FailErr(w, r, web.ErrNotFound)
+		return
+	}
+	if !validSkillSlug(slug) {
+		web.Fail(w, r, "INVALID_PARAMS", "invalid skill name", http.StatusBadRequest)
+		return
+	}
+
+	readme := SkillReadme{Slug: slug}
+	if !h.isRemoteGateway() {
+		if path, data, err := readLocalSkillMD(slug); err == nil {
+			readme.Source, readme.Path, readme.Content = "local", path, string(data)
+		}
+	}
+	if readme.Source == "" {
+		content, status, err := h.fetchRegistrySkillMD(slug)
+		if status == http.StatusNotFound {
+			web.FailErr(w, r, web.ErrSkillNotFound)
+			return
+		}
+		if err != nil {
+			web.Fail(w, r, "CLAWHUB_UPSTREAM_ERROR", err.Error(), http.StatusBadGateway)
+			return
+		}
+		readme.Source, readme.Content = "registry", content
+	}
+
+	readme.Frontmatter, readme.Body = parseSkillMarkdown(readme.Content)
+	web.OK(w, r, readme)
+}
+
+// readLocalSkillMD reads ~/.openclaw/skills/<slug>/SKILL.md (or skill.md).
+func readLocalSkillMD(slug string) (string, []byte, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil, err
+	}
+	dir := filepath.Join(home, ".openclaw", "skills", slug)
+	var lastErr error
+	for _, name := range []string{"SKILL.md", "skill.md"} {
+		path := filepath.Join(dir, name)
+		f, err := os.Open(path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(f, maxSkillReadmeBytes))
+		f.Close()
+		return path, data, err
+	}
+	return "", nil, lastErr
+}
+
+// fetchRegistrySkillMD downloads SKILL.md of the latest published version from
+// ClawHub. The file endpoint answers with the raw text or a {"content": ...} object.
+func (h *ClawHubHandler) fetchRegistrySkillMD(slug string) (string, int, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/skills/%s/file?path=SKILL.md", h.registryURL, url.PathEscape(slug))
+	resp, err := h.httpClient.Get(apiURL)
+	if err != nil {
+		return "", 0, fmt.Errorf("fetch SKILL.md: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSkillReadmeBytes))
+	if err != nil {
+		return "", resp.StatusCode, fmt.Errorf("read SKILL.md: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", resp.StatusCode, fmt.Errorf("ClawHub returned %d", resp.StatusCode)
+	}
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		var wrapped struct {
+			Content string `json:"content"`
+		}
+		if json.Unmarshal(body, &wrapped) == nil && wrapped.Content != "" {
+			return wrapped.Content, resp.StatusCode, nil
+		}
+	}
+	return string(body), resp.StatusCode, nil
+}
+
+// parseSkillMarkdown splits a leading "---" YAML frontmatter block from the
+// markdown body. Invalid frontmatter is left in the body so nothing is lost.
+func parseSkillMarkdown(content string) (map[string]interface{}, string) {
+	front := map[string]interface{}{}
+	text := strings.TrimPrefix(content, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return front, strings.TrimSpace(text)
+	}
+	block, body, ok := strings.Cut(text[4:], "\n---")
+	if !ok {
+		return front, strings.TrimSpace(text)
+	}
+	// the closing fence must be a line of its own
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		if strings.TrimSpace(body[:nl]) != "" {
+			return front, strings.TrimSpace(text)
+		}
+		body = body[nl+1:]
+	} else if strings.TrimSpace(body) != "" {
+		return front, strings.TrimSpace(text)
+	} else {
+		body = ""
+	}
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(block)))
+	if err := dec.Decode(&front); err != nil && err != io.EOF {
+		return map[string]interface{}{}, strings.TrimSpace(text)
+	}
+	return front, strings.TrimSpace(body)
+}
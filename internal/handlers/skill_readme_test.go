@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSkillMarkdown(t *testing.T) {
+	front, body := parseSkillMarkdown("---\r\nname: weather\r\ndescription: Get the forecast\r\nmetadata:\r\n  emoji: sun\r\n---\r\n# Weather\r\n\r\nUse `curl wttr.in`.\r\n")
+	assert.Equal(t, "weather", front["name"])
+	assert.Equal(t, "Get the forecast", front["description"])
+	assert.Equal(t, map[string]interface{}{"emoji": "sun"}, front["metadata"])
+	assert.Equal(t, "# Weather\n\nUse `curl wttr.in`.", body)
+
+	front, body = parseSkillMarkdown("# No frontmatter\n")
+	assert.Empty(t, front)
+	assert.Equal(t, "# No frontmatter", body)
+
+	// unparsable frontmatter is kept in the body
+	front, body = parseSkillMarkdown("---\nname: [broken\n---\nbody\n")
+	assert.Empty(t, front)
+	assert.Contains(t, body, "name: [broken")
+}
+
+func TestValidSkillSlug(t *testing.T) {
+	for _, ok := range []string{"weather", "gog", "nano-banana-pro", "skill_v2", "a.b"} {
+		assert.True(t, validSkillSlug(ok), ok)
+	}
+	for _, bad := range []string{"", "..", "../etc", "a/b", `a\b`, ".hidden", "a..b", "x y"} {
+		assert.False(t, validSkillSlug(bad), bad)
+	}
+}
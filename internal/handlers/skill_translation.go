@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -16,19 +18,89 @@ import (
 	"openclawdeck/internal/web"
 )
 
+// Settings that choose the skill translation backend. "model" reuses the
+// primary model from openclaw.json; "libretranslate" calls the API at
+// translation_api_url; "none" keeps the original text (air-gapped setups).
+const (
+	TranslationProviderSetting = "translation_provider"
+	TranslationAPIURLSetting   = "translation_api_url"
+	TranslationAPIKeySetting   = "translation_api_key"
+)
+
+func init() {
+	database.RegisterSettings(
+		database.SettingDef{
+			Key:         TranslationProviderSetting,
+			Type:        database.SettingString,
+			Default:     translate.ProviderPublic,
+			Enum:        translate.Providers,
+			Description: "skill translation backend",
+		},
+		database.SettingDef{
+			Key:         TranslationAPIURLSetting,
+			Type:        database.SettingString,
+			Description: "LibreTranslate-compatible API base URL",
+			Validate: func(v string) error {
+				if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return errors.New("must be an http(s) URL")
+				}
+				return nil
+			},
+		},
+		database.SettingDef{
+			Key:         TranslationAPIKeySetting,
+			Type:        database.SettingString,
+			Description: "API key for the translation API (optional)",
+		},
+	)
+}
+
 // SkillTranslationHandler manages skill description translations.
 type SkillTranslationHandler struct {
-	translator *translate.Translator
-	repo       *database.SkillTranslationRepo
-	mu         sync.Mutex
-	running    map[string]bool // track in-flight translation jobs
+	translator  *translate.Translator // public engines; kept so its rate limit spans batches
+	repo        *database.SkillTranslationRepo
+	settingRepo *database.SettingRepo
+	mu          sync.Mutex
+	running     map[string]bool // track in-flight translation jobs
 }
 
 func NewSkillTranslationHandler() *SkillTranslationHandler {
 	return &SkillTranslationHandler{
-		translator: translate.New(),
-		repo:       database.NewSkillTranslationRepo(),
-		running:    make(map[string]bool),
+		translator:  translate.New(),
+		repo:        database.NewSkillTranslationRepo(),
+		settingRepo: database.NewSettingRepo(),
+		running:     make(map[string]bool),
+	}
+}
+
+// provider resolves the configured translation backend. A backend that is
+// selected but not usable (no URL, no primary model) falls back to the no-op
+// provider so skills keep their original text instead of failing.
+func (h *SkillTranslationHandler) provider() translate.Provider {
+	switch h.settingRepo.GetString(TranslationProviderSetting) {
+	case translate.ProviderNone:
+		return translate.Noop{}
+	case translate.ProviderLibre:
+		base := h.settingRepo.GetString(TranslationAPIURLSetting)
+		if base == "" {
+			logger.Log.Warn().Msg("translation_provider is libretranslate but translation_api_url is empty; translation disabled")
+			return translate.Noop{}
+		}
+		return translate.NewLibre(base, h.settingRepo.GetString(TranslationAPIKeySetting))
+	case translate.ProviderModel:
+		cfg, _, detail := loadVerifyConfig()
+		if cfg == nil {
+			logger.Log.Warn().Str("detail", detail).Msg("cannot read openclaw.json for model translation; translation disabled")
+			return translate.Noop{}
+		}
+		req, err := modelProbeRequest(cfg)
+		if err != nil {
+			logger.Log.Warn().Err(err).Msg("no primary model for translation; translation disabled")
+			return translate.Noop{}
+		}
+		return translate.NewModel(translate.ModelConfig{Provider: req.Provider, BaseURL: req.BaseURL, APIKey: req.APIKey, Model: req.Model})
+	default:
+		return h.translator
 	}
 }
 
@@ -130,6 +202,12 @@ func (h *SkillTranslationHandler) Translate(w http.ResponseWriter, r *http.Reque
 		web.OK(w, r, map[string]string{"status": "skipped", "reason": "source is english"})
 		return
 	}
+	provider := h.provider()
+	if provider.Name() == translate.ProviderNone {
+		// nothing is cached so switching to a real provider later translates everything
+		web.OK(w, r, map[string]string{"status": "skipped", "reason": "translation disabled"})
+		return
+	}
 
 	// Filter out already cached (with same source hash) and already running
 	var toTranslate []skillItem
@@ -172,12 +250,12 @@ func (h *SkillTranslationHandler) Translate(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Run translations in background
-	go h.translateBatch(req.Lang, toTranslate)
+	go h.translateBatch(provider, req.Lang, toTranslate)
 
-	web.OK(w, r, map[string]interface{}{"status": "ok", "queued": len(toTranslate)})
+	web.OK(w, r, map[string]interface{}{"status": "ok", "queued": len(toTranslate), "provider": provider.Name()})
 }
 
-func (h *SkillTranslationHandler) translateBatch(lang string, skills []skillItem) {
+func (h *SkillTranslationHandler) translateBatch(provider translate.Provider, lang string, skills []skillItem) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
@@ -187,16 +265,16 @@ func (h *SkillTranslationHandler) translateBatch(lang string, skills []skillItem
 		hash := hashText(sourceText)
 
 		// Translate name
-		translatedName, err := h.translator.Translate(ctx, sk.Name, "en", lang)
+		translatedName, err := provider.Translate(ctx, sk.Name, "en", lang)
 		if err != nil {
-			logger.Log.Warn().Err(err).Str("skill", sk.SkillKey).Msg("translate name failed")
+			logger.Log.Warn().Err(err).Str("skill", sk.SkillKey).Str("provider", provider.Name()).Msg("translate name failed")
 			translatedName = sk.Name
 		}
 
 		// Translate description
-		translatedDesc, err := h.translator.Translate(ctx, sk.Description, "en", lang)
+		translatedDesc, err := provider.Translate(ctx, sk.Description, "en", lang)
 		if err != nil {
-			logger.Log.Warn().Err(err).Str("skill", sk.SkillKey).Msg("translate description failed")
+			logger.Log.Warn().Err(err).Str("skill", sk.SkillKey).Str("provider", provider.Name()).Msg("translate description failed")
 			translatedDesc = sk.Description
 		}
 
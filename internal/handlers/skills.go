@@ -2,22 +2,86 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"openclawdeck/internal/constants"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/web"
 )
 
 // SkillsHandler manages skill auditing.
-type SkillsHandler struct{}
+type SkillsHandler struct {
+	integrity *monitor.SkillIntegrity
+}
 
 func NewSkillsHandler() *SkillsHandler {
 	return &SkillsHandler{}
 }
 
+// SetIntegrity injects the skill file integrity checker.
+func (h *SkillsHandler) SetIntegrity(si *monitor.SkillIntegrity) {
+	h.integrity = si
+}
+
+// Integrity returns the result of the last integrity check (ok / modified /
+// missing / untracked per skill) without hashing any files.
+// GET /api/v1/skills/integrity
+func (h *SkillsHandler) Integrity(w http.ResponseWriter, r *http.Request) {
+	if h.integrity == nil {
+		web.OK(w, r, []monitor.SkillIntegrityStatus{})
+		return
+	}
+	web.OK(w, r, h.integrity.Last())
+}
+
+// VerifyIntegrity re-verifies installed skills against their recorded file
+// hashes now. Newly modified skills also raise an alert.
+// POST /api/v1/skills/integrity/verify
+func (h *SkillsHandler) VerifyIntegrity(w http.ResponseWriter, r *http.Request) {
+	if h.integrity == nil {
+		web.FailErr(w, r, web.ErrSkillIntegrityFail, "integrity checker not running")
+		return
+	}
+	result, err := h.integrity.Verify()
+	if err != nil {
+		web.FailErr(w, r, web.ErrSkillIntegrityFail, err.Error())
+		return
+	}
+	web.OK(w, r, result)
+}
+
+// Baseline records the current files of a skill as trusted, accepting local
+// edits or starting to track a skill that was not installed through the deck.
+// POST /api/v1/skills/integrity/baseline
+func (h *SkillsHandler) Baseline(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Skill string `json:"skill"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Skill == "" {
+		web.FailErr(w, r, web.ErrInvalidParam)
+		return
+	}
+	if h.integrity == nil {
+		web.FailErr(w, r, web.ErrSkillIntegrityFail, "integrity checker not running")
+		return
+	}
+	if err := h.integrity.Baseline(req.Skill); err != nil {
+		if errors.Is(err, monitor.ErrSkillNotInstalled) {
+			web.FailErr(w, r, web.ErrSkillNotFound)
+			return
+		}
+		web.FailErr(w, r, web.ErrSkillIntegrityFail, err.Error())
+		return
+	}
+	auditMutation(r, constants.ActionSkillBaseline, "skill "+req.Skill)
+	web.OK(w, r, map[string]string{"skill": req.Skill, "status": monitor.SkillIntegrityOK})
+}
+
 // SkillInfo represents installed skill metadata.
 type SkillInfo struct {
 	Name        string `json:"name"`
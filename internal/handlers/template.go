@@ -2,28 +2,56 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
 
+// configTemplateTarget is the target_file of templates that merge into openclaw.json
+// instead of being written to a workspace file.
+const configTemplateTarget = "openclaw.json"
+
+// templateVarPattern matches {{VAR}} placeholders in template content.
+// ${VAR} is deliberately not used: it is OpenClaw's own env reference syntax,
+// and env references in a config must survive export and apply untouched.
+var templateVarPattern = regexp.MustCompile(`\{\{([A-Za-z_][A-Za-z0-9_]*)\}\}`)
+
 // TemplateHandler manages workspace file template CRUD.
 type TemplateHandler struct {
-	repo *database.TemplateRepo
+	repo      *database.TemplateRepo
+	auditRepo *database.AuditLogRepo
+	// merge writes a rendered config template into openclaw.json (mergeConfig; tests replace it).
+	merge func(patch map[string]interface{}) error
 }
 
 func NewTemplateHandler() *TemplateHandler {
 	return &TemplateHandler{
-		repo: database.NewTemplateRepo(),
+		repo:      database.NewTemplateRepo(),
+		auditRepo: database.NewAuditLogRepo(),
+		merge:     mergeConfig,
 	}
 }
 
-// List returns all templates, optionally filtered by ?target_file=SOUL.md
+// List returns all templates, optionally filtered by
+// ?target_file=SOUL.md&category=persona&q=keyword
 func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
-	targetFile := r.URL.Query().Get("target_file")
-	templates, err := h.repo.List(targetFile)
+	q := r.URL.Query()
+	templates, err := h.repo.List(database.TemplateFilter{
+		TargetFile: q.Get("target_file"),
+		Category:   q.Get("category"),
+		Query:      strings.TrimSpace(q.Get("q")),
+	})
 	if err != nil {
 		web.FailErr(w, r, web.ErrDBQuery)
 		return
@@ -31,6 +59,17 @@ func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
 	web.OK(w, r, templates)
 }
 
+// Categories returns the distinct template categories with counts,
+// optionally filtered by ?target_file=.
+func (h *TemplateHandler) Categories(w http.ResponseWriter, r *http.Request) {
+	cats, err := h.repo.Categories(r.URL.Query().Get("target_file"))
+	if err != nil {
+		web.FailErr(w, r, web.ErrDBQuery)
+		return
+	}
+	web.OK(w, r, cats)
+}
+
 // Get returns a single template by ID (query param ?id=).
 func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
 	idStr := r.URL.Query().Get("id")
@@ -69,6 +108,9 @@ func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
 		web.FailErr(w, r, web.ErrInvalidParam)
 		return
 	}
+	if req.Category == "" {
+		req.Category = database.DefaultTemplateCategory
+	}
 	// Validate i18n is valid JSON
 	var i18nCheck map[string]interface{}
 	if err := json.Unmarshal([]byte(req.I18n), &i18nCheck); err != nil {
@@ -184,9 +226,383 @@ func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	web.OK(w, r, map[string]string{"message": "ok"})
 }
 
+// Apply renders a template with the supplied variable values.
+// Config templates (target_file=openclaw.json) are validated and merged into the
+// OpenClaw config; workspace file templates return the rendered content.
+// POST /api/v1/templates/{id}/apply
+func (h *TemplateHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	id, ok := templateApplyID(r)
+	if !ok {
+		web.FailErr(w, r, web.ErrInvalidParam)
+		return
+	}
+	var req struct {
+		Lang   string            `json:"lang"`
+		Values map[string]string `json:"values"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	tpl, err := h.repo.GetByID(id)
+	if err != nil {
+		web.FailErr(w, r, web.ErrTemplateNotFound)
+		return
+	}
+	content, ok := templateContent(tpl.I18n, req.Lang)
+	if !ok {
+		web.FailErr(w, r, web.ErrTemplateInvalid, "template has no content")
+		return
+	}
+
+	if tpl.TargetFile != configTemplateTarget {
+		rendered, missing := renderTemplate(content, req.Values)
+		if len(missing) > 0 {
+			web.FailErr(w, r, web.ErrTemplateVarMissing, strings.Join(missing, ", "))
+			return
+		}
+		web.OK(w, r, map[string]interface{}{
+			"target_file": tpl.TargetFile,
+			"content":     rendered,
+			"merged":      false,
+		})
+		return
+	}
+
+	patch, missing, err := renderConfigTemplate(content, req.Values)
+	if len(missing) > 0 {
+		web.FailErr(w, r, web.ErrTemplateVarMissing, strings.Join(missing, ", "))
+		return
+	}
+	if err != nil || len(patch) == 0 {
+		web.FailErr(w, r, web.ErrTemplateInvalid)
+		return
+	}
+	// validated by openclaw config set (or LintConfig when the CLI is missing)
+	if err := h.merge(patch); err != nil {
+		failConfigMerge(w, r, err, web.ErrTemplateApplyFail)
+		return
+	}
+	rendered, _ := json.MarshalIndent(patch, "", "  ")
+
+	h.auditRepo.Create(&database.AuditLog{
+		UserID:   web.GetUserID(r),
+		Username: web.GetUsername(r),
+		Action:   constants.ActionConfigUpdate,
+		Result:   "success",
+		Detail:   "applied template " + tpl.TemplateID,
+		IP:       web.ClientIP(r),
+	})
+	logger.Config.Info().Str("user", web.GetUsername(r)).Str("template", tpl.TemplateID).Msg("config template applied")
+
+	web.OK(w, r, map[string]interface{}{
+		"target_file": tpl.TargetFile,
+		"content":     string(rendered) + "\n",
+		"merged":      true,
+	})
+}
+
+// FromConfig saves the current openclaw.json as a reusable config template.
+// Secret values are replaced by {{VAR}} placeholders (see extractConfigSecrets);
+// the returned required_variables must be supplied when applying it.
+// POST /api/v1/templates/from-config
+func (h *TemplateHandler) FromConfig(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TemplateID string `json:"template_id"`
+		Name       string `json:"name"`
+		Desc       string `json:"desc"`
+		Icon       string `json:"icon"`
+		Category   string `json:"category"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		web.FailErr(w, r, web.ErrInvalidParam, "name is required")
+		return
+	}
+	if req.TemplateID == "" {
+		req.TemplateID = fmt.Sprintf("config-user-%d", time.Now().Unix())
+	}
+	if req.Icon == "" {
+		req.Icon = "tune"
+	}
+	if req.Category == "" {
+		req.Category = database.DefaultTemplateCategory
+	}
+	if existing, _ := h.repo.GetByTemplateID(req.TemplateID); existing != nil {
+		web.FailErr(w, r, web.ErrTemplateExists)
+		return
+	}
+
+	data, err := os.ReadFile(configPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			web.FailErr(w, r, web.ErrConfigNotFound)
+			return
+		}
+		web.FailErr(w, r, web.ErrConfigReadFailed)
+		return
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
+	}
+
+	vars := extractConfigSecrets(cfg)
+	content, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		web.FailErr(w, r, web.ErrTemplateCreateFail, err.Error())
+		return
+	}
+	entry := map[string]string{"name": req.Name, "desc": req.Desc, "content": string(content) + "\n"}
+	i18n, _ := json.Marshal(map[string]interface{}{"zh": entry, "en": entry})
+
+	tpl := &database.Template{
+		TemplateID: req.TemplateID,
+		TargetFile: configTemplateTarget,
+		Icon:       req.Icon,
+		Category:   req.Category,
+		Tags:       "config,exported",
+		Author:     web.GetUsername(r),
+		I18n:       string(i18n),
+		Version:    1,
+	}
+	if err := h.repo.Create(tpl); err != nil {
+		web.FailErr(w, r, web.ErrTemplateCreateFail)
+		return
+	}
+
+	names := make([]string, 0, len(vars))
+	for _, v := range vars {
+		names = append(names, v.Name)
+	}
+	web.OK(w, r, map[string]interface{}{
+		"template":           tpl,
+		"required_variables": names,
+		"variables":          vars,
+	})
+}
+
+// templateSecretVar records a secret that was replaced by a placeholder.
+type templateSecretVar struct {
+	Name string `json:"name"` // placeholder name, e.g. GATEWAY_AUTH_TOKEN
+	Path string `json:"path"` // dotted config path, e.g. gateway.auth.token
+}
+
+// extractConfigSecrets replaces every non-empty secret string in cfg (keys
+// matching isSensitiveKey) with a {{VAR}} placeholder, in place.
+//
+// Placeholder names are the dotted config path upper-snake-cased
+// (channels.telegram.botToken -> CHANNELS_TELEGRAM_BOT_TOKEN). Keys are walked
+// in sorted order and collisions get a numeric suffix, so the same config
+// always yields the same placeholders. Values that already are placeholders
+// are kept and reported; OpenClaw ${ENV} references are not secrets and are
+// kept as they are.
+func extractConfigSecrets(cfg map[string]interface{}) []templateSecretVar {
+	var vars []templateSecretVar
+	used := map[string]bool{}
+	var walk func(v interface{}, path []string)
+	walk = func(v interface{}, path []string) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				child := val[k]
+				childPath := append(append([]string{}, path...), k)
+				if s, ok := child.(string); ok && s != "" && isSensitiveKey(k) {
+					if m := templateVarPattern.FindStringSubmatch(s); m != nil && m[0] == s {
+						if !used[m[1]] {
+							used[m[1]] = true
+							vars = append(vars, templateSecretVar{Name: m[1], Path: strings.Join(childPath, ".")})
+						}
+						continue
+					}
+					if openclaw.HasEnvRef(s) {
+						continue
+					}
+					name := placeholderName(childPath)
+					for i := 2; used[name]; i++ {
+						name = fmt.Sprintf("%s_%d", placeholderName(childPath), i)
+					}
+					used[name] = true
+					val[k] = "{{" + name + "}}"
+					vars = append(vars, templateSecretVar{Name: name, Path: strings.Join(childPath, ".")})
+					continue
+				}
+				walk(child, childPath)
+			}
+		case []interface{}:
+			for i, item := range val {
+				walk(item, append(append([]string{}, path...), strconv.Itoa(i)))
+			}
+		}
+	}
+	walk(cfg, nil)
+	return vars
+}
+
+// placeholderName converts a config path to an upper-snake variable name.
+func placeholderName(path []string) string {
+	var b strings.Builder
+	for i, seg := range path {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		var prev rune
+		for _, c := range seg {
+			switch {
+			case c >= 'A' && c <= 'Z':
+				if (prev >= 'a' && prev <= 'z') || (prev >= '0' && prev <= '9') {
+					b.WriteByte('_')
+				}
+				b.WriteRune(c)
+			case c >= 'a' && c <= 'z':
+				b.WriteRune(c - 'a' + 'A')
+			case c >= '0' && c <= '9':
+				b.WriteRune(c)
+			default:
+				b.WriteByte('_')
+			}
+			prev = c
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// templateContent picks the content for lang from a template's I18n JSON,
+// falling back to English and then to any available language.
+func templateContent(i18n, lang string) (string, bool) {
+	var entries map[string]struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(i18n), &entries); err != nil || len(entries) == 0 {
+		return "", false
+	}
+	for _, l := range []string{lang, "en"} {
+		if e, ok := entries[l]; ok && e.Content != "" {
+			return e.Content, true
+		}
+	}
+	langs := make([]string, 0, len(entries))
+	for l := range entries {
+		langs = append(langs, l)
+	}
+	sort.Strings(langs)
+	for _, l := range langs {
+		if entries[l].Content != "" {
+			return entries[l].Content, true
+		}
+	}
+	return "", false
+}
+
+// templateVars returns the distinct {{VAR}} names referenced in content, sorted.
+func templateVars(content string) []string {
+	seen := map[string]bool{}
+	var vars []string
+	for _, m := range templateVarPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			vars = append(vars, m[1])
+		}
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// missingTemplateVars returns the sorted names of variables in content without a value.
+func missingTemplateVars(content string, values map[string]string) []string {
+	var missing []string
+	for _, name := range templateVars(content) {
+		if v, ok := values[name]; !ok || v == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// substituteVars replaces the {{VAR}} placeholders in s with values.
+func substituteVars(s string, values map[string]string) string {
+	return templateVarPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return values[templateVarPattern.FindStringSubmatch(m)[1]]
+	})
+}
+
+// renderTemplate substitutes {{VAR}} placeholders in a text (workspace file) template.
+// Returns the rendered content and the sorted names of variables without a value.
+func renderTemplate(content string, values map[string]string) (string, []string) {
+	if missing := missingTemplateVars(content, values); len(missing) > 0 {
+		return "", missing
+	}
+	return substituteVars(content, values), nil
+}
+
+// renderConfigTemplate parses a config template and then substitutes
+// placeholders inside its string keys and values, so a value can never change
+// the structure of the document (quotes and braces stay part of the string).
+func renderConfigTemplate(content string, values map[string]string) (map[string]interface{}, []string, error) {
+	if missing := missingTemplateVars(content, values); len(missing) > 0 {
+		return nil, missing, nil
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, nil, err
+	}
+	var render func(v interface{}) interface{}
+	render = func(v interface{}) interface{} {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			out := make(map[string]interface{}, len(val))
+			for k, child := range val {
+				out[substituteVars(k, values)] = render(child)
+			}
+			return out
+		case []interface{}:
+			for i, item := range val {
+				val[i] = render(item)
+			}
+			return val
+		case string:
+			return substituteVars(val, values)
+		}
+		return v
+	}
+	return render(doc).(map[string]interface{}), nil, nil
+}
+
+// templateApplyID parses the template id from /api/v1/templates/{id}/apply.
+func templateApplyID(r *http.Request) (uint, bool) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/templates/")
+	idStr, ok := strings.CutSuffix(rest, "/apply")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
 // SeedBuiltIn inserts or updates all built-in templates from the provided list.
 // Called once at startup. Skips if the DB already has the expected number of built-in templates.
 func (h *TemplateHandler) SeedBuiltIn(templates []database.Template) error {
+	// 旧版本创建的模板可能没有分类，补齐默认分类
+	if n, err := h.repo.BackfillCategory(); err == nil && n > 0 {
+		logger.Log.Info().Int64("count", n).Msg("已为无分类模板补齐默认分类")
+	}
 	// 快速检查：如果数据库中内置模板数量与预期一致，跳过 seed
 	if count, err := h.repo.CountBuiltIn(); err == nil && count == int64(len(templates)) {
 		return nil
@@ -225,5 +641,10 @@ func BuiltInTemplates() []database.Template {
 
 		// ===== TOOLS.md =====
 		{TemplateID: "tools-notes", TargetFile: "TOOLS.md", Icon: "build", Category: "tools", Tags: "tools,notes", Author: "OpenClaw", I18n: `{"zh":{"name":"工具备注","desc":"记录工具使用的注意事项","content":"# 工具使用备注\n\n## 通用规则\n- 使用工具前先确认参数\n- 失败时尝试换一种方式\n- 记录常用的工具组合\n\n## 特殊说明\n（在这里添加你的工具使用备注）\n"},"en":{"name":"Tool Notes","desc":"Notes on tool usage","content":"# Tool Usage Notes\n\n## General Rules\n- Verify parameters before using tools\n- Try alternative approaches on failure\n- Document commonly used tool combinations\n\n## Special Notes\n(Add your tool usage notes here)\n"}}`},
+
+		// ===== openclaw.json (config templates, {{VAR}} placeholders filled on apply) =====
+		{TemplateID: "config-minimal", TargetFile: "openclaw.json", Icon: "tune", Category: "minimal", Tags: "config,minimal,local", Author: "OpenClaw", I18n: `{"zh":{"name":"最小本地配置","desc":"仅本机访问的最小安全网关配置","content":"{\n  \"gateway\": {\n    \"mode\": \"local\",\n    \"bind\": \"loopback\",\n    \"port\": 18789,\n    \"auth\": {\n      \"mode\": \"token\",\n      \"token\": \"{{GATEWAY_TOKEN}}\"\n    }\n  }\n}\n"},"en":{"name":"Minimal Local","desc":"Minimal loopback-only gateway with token auth","content":"{\n  \"gateway\": {\n    \"mode\": \"local\",\n    \"bind\": \"loopback\",\n    \"port\": 18789,\n    \"auth\": {\n      \"mode\": \"token\",\n      \"token\": \"{{GATEWAY_TOKEN}}\"\n    }\n  }\n}\n"}}`},
+		{TemplateID: "config-secure-remote", TargetFile: "openclaw.json", Icon: "lock", Category: "secure-remote", Tags: "config,remote,secure", Author: "OpenClaw", I18n: `{"zh":{"name":"安全远程访问","desc":"局域网可访问，强制 Token 鉴权","content":"{\n  \"gateway\": {\n    \"mode\": \"local\",\n    \"bind\": \"lan\",\n    \"port\": 18789,\n    \"auth\": {\n      \"mode\": \"token\",\n      \"token\": \"{{GATEWAY_TOKEN}}\"\n    }\n  }\n}\n"},"en":{"name":"Secure Remote","desc":"LAN-reachable gateway with mandatory token auth","content":"{\n  \"gateway\": {\n    \"mode\": \"local\",\n    \"bind\": \"lan\",\n    \"port\": 18789,\n    \"auth\": {\n      \"mode\": \"token\",\n      \"token\": \"{{GATEWAY_TOKEN}}\"\n    }\n  }\n}\n"}}`},
+		{TemplateID: "config-multi-channel", TargetFile: "openclaw.json", Icon: "forum", Category: "multi-channel", Tags: "config,channels,telegram,discord", Author: "OpenClaw", I18n: `{"zh":{"name":"多频道接入","desc":"同时接入 Telegram 与 Discord","content":"{\n  \"channels\": {\n    \"telegram\": {\n      \"enabled\": true,\n      \"botToken\": \"{{TELEGRAM_BOT_TOKEN}}\",\n      \"dmPolicy\": \"pairing\"\n    },\n    \"discord\": {\n      \"enabled\": true,\n      \"token\": \"{{DISCORD_BOT_TOKEN}}\",\n      \"dm\": {\n        \"enabled\": true,\n        \"policy\": \"pairing\"\n      }\n    }\n  }\n}\n"},"en":{"name":"Multi-Channel","desc":"Connect Telegram and Discord at once","content":"{\n  \"channels\": {\n    \"telegram\": {\n      \"enabled\": true,\n      \"botToken\": \"{{TELEGRAM_BOT_TOKEN}}\",\n      \"dmPolicy\": \"pairing\"\n    },\n    \"discord\": {\n      \"enabled\": true,\n      \"token\": \"{{DISCORD_BOT_TOKEN}}\",\n      \"dm\": {\n        \"enabled\": true,\n        \"policy\": \"pairing\"\n      }\n    }\n  }\n}\n"}}`},
 	}
 }
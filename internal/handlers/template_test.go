@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderConfigTemplate_ValuesStayStrings(t *testing.T) {
+	content := `{"channels":{"telegram":{"botToken":"{{BOT_TOKEN}}","name":"bot {{BOT_NAME}}"}}}`
+	values := map[string]string{
+		"BOT_TOKEN": `x"},"gateway":{"auth":{"mode":"none"}},"y":{"z":"`,
+		"BOT_NAME":  `} {`,
+	}
+
+	doc, missing, err := renderConfigTemplate(content, values)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	assert.NotContains(t, doc, "gateway", "a value must not add config keys")
+	tg := doc["channels"].(map[string]interface{})["telegram"].(map[string]interface{})
+	assert.Equal(t, values["BOT_TOKEN"], tg["botToken"])
+	assert.Equal(t, "bot } {", tg["name"])
+
+	_, missing, _ = renderConfigTemplate(content, map[string]string{"BOT_TOKEN": "t"})
+	assert.Equal(t, []string{"BOT_NAME"}, missing)
+}
+
+func TestTemplateApplyID(t *testing.T) {
+	for path, want := range map[string]uint{
+		"/api/v1/templates/12/apply": 12,
+		"/api/v1/templates/12":       0,
+		"/api/v1/templates/0/apply":  0,
+		"/api/v1/templates/x/apply":  0,
+	} {
+		id, ok := templateApplyID(httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, want, id, path)
+		assert.Equal(t, want != 0, ok, path)
+	}
+}
+
+func TestTemplateApply_ConfigTemplate(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, database.DB.AutoMigrate(&database.Template{}))
+
+	entry := map[string]string{"name": "Telegram", "content": `{"channels":{"telegram":{"botToken":"{{BOT_TOKEN}}"}}}`}
+	i18n, _ := json.Marshal(map[string]interface{}{"en": entry})
+	tpl := &database.Template{TemplateID: "tg", TargetFile: configTemplateTarget, I18n: string(i18n)}
+	require.NoError(t, database.NewTemplateRepo().Create(tpl))
+
+	var merged map[string]interface{}
+	h := NewTemplateHandler()
+	h.merge = func(patch map[string]interface{}) error {
+		merged = patch
+		return nil
+	}
+	apply := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/templates/"+strconv.FormatUint(uint64(tpl.ID), 10)+"/apply", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.Apply(w, req)
+		return w
+	}
+
+	w := apply(`{"values":{}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "BOT_TOKEN")
+	assert.Nil(t, merged, "missing variables must not reach the config")
+
+	w = apply(`{"values":{"BOT_TOKEN":"123:abc\"}"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	tg := merged["channels"].(map[string]interface{})["telegram"].(map[string]interface{})
+	assert.Equal(t, `123:abc"}`, tg["botToken"])
+}
+
+func TestTemplateApply_RefusesUnparsableConfig(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, database.DB.AutoMigrate(&database.Template{}))
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".openclaw", "openclaw.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("{broken"), 0o600))
+
+	i18n, _ := json.Marshal(map[string]interface{}{"en": map[string]string{"name": "Port", "content": `{"gateway":{"port":18790}}`}})
+	tpl := &database.Template{TemplateID: "port", TargetFile: configTemplateTarget, I18n: string(i18n)}
+	require.NoError(t, database.NewTemplateRepo().Create(tpl))
+
+	h := NewTemplateHandler()
+	h.merge = mergeConfigDirect
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/templates/"+strconv.FormatUint(uint64(tpl.ID), 10)+"/apply", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+	h.Apply(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "CONFIG_READ_FAILED")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{broken", string(data), "corrupt config is left untouched")
+}
+
+const exportSourceConfig = `{
+  "gateway": {"auth": {"mode": "token", "token": "gw-secret"}},
+  "channels": {
+    "telegram": {"botToken": "123:abc", "bot_token": "456:def"},
+    "discord": {"token": "${DISCORD_TOKEN}"},
+    "slack": {"token": "{{SLACK_TOKEN}}"}
+  },
+  "models": {"providers": [{"apiKey": "sk-1"}, {"apiKey": "sk-2"}]}
+}`
+
+func TestExtractConfigSecrets_Deterministic(t *testing.T) {
+	want := []templateSecretVar{
+		{Name: "SLACK_TOKEN", Path: "channels.slack.token"},
+		{Name: "CHANNELS_TELEGRAM_BOT_TOKEN", Path: "channels.telegram.botToken"},
+		{Name: "CHANNELS_TELEGRAM_BOT_TOKEN_2", Path: "channels.telegram.bot_token"},
+		{Name: "GATEWAY_AUTH_TOKEN", Path: "gateway.auth.token"},
+		{Name: "MODELS_PROVIDERS_0_API_KEY", Path: "models.providers.0.apiKey"},
+		{Name: "MODELS_PROVIDERS_1_API_KEY", Path: "models.providers.1.apiKey"},
+	}
+
+	var first []byte
+	for i := 0; i < 20; i++ {
+		var cfg map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(exportSourceConfig), &cfg))
+		vars := extractConfigSecrets(cfg)
+		assert.Equal(t, want, vars)
+		out, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		if first == nil {
+			first = out
+			continue
+		}
+		assert.Equal(t, string(first), string(out), "export must be stable across runs")
+	}
+
+	var cfg map[string]interface{}
+	require.NoError(t, json.Unmarshal(first, &cfg))
+	channels := cfg["channels"].(map[string]interface{})
+	assert.Equal(t, "${DISCORD_TOKEN}", channels["discord"].(map[string]interface{})["token"], "OpenClaw env references are kept")
+	assert.Equal(t, "{{SLACK_TOKEN}}", channels["slack"].(map[string]interface{})["token"])
+	assert.Equal(t, "{{GATEWAY_AUTH_TOKEN}}", cfg["gateway"].(map[string]interface{})["auth"].(map[string]interface{})["token"])
+	assert.NotContains(t, string(first), "gw-secret")
+	assert.NotContains(t, string(first), "sk-1")
+}
+
+func TestTemplateFromConfig_StableRequiredVariables(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, database.DB.AutoMigrate(&database.Template{}))
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".openclaw"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".openclaw", "openclaw.json"), []byte(exportSourceConfig), 0o600))
+
+	h := NewTemplateHandler()
+	export := func(id string) (content string, required []string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/templates/from-config", bytes.NewBufferString(`{"template_id":"`+id+`","name":"mine"}`))
+		w := httptest.NewRecorder()
+		h.FromConfig(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				Template          database.Template `json:"template"`
+				RequiredVariables []string          `json:"required_variables"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		content, ok := templateContent(resp.Data.Template.I18n, "en")
+		require.True(t, ok)
+		return content, resp.Data.RequiredVariables
+	}
+
+	content1, vars1 := export("export-a")
+	content2, vars2 := export("export-b")
+	assert.Equal(t, content1, content2)
+	assert.Equal(t, vars1, vars2)
+	// every placeholder in the stored template is listed, and nothing else
+	assert.ElementsMatch(t, templateVars(content1), vars1)
+	assert.NotContains(t, vars1, "DISCORD_TOKEN")
+}
@@ -109,7 +109,7 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionUserCreate,
 		Result:   "success",
 		Detail:   "created user: " + req.Username,
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Auth.Info().Str("username", req.Username).Str("role", req.Role).Msg("user created")
@@ -157,7 +157,7 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		Action:   constants.ActionUserDelete,
 		Result:   "success",
 		Detail:   "deleted user: " + user.Username,
-		IP:       r.RemoteAddr,
+		IP:       web.ClientIP(r),
 	})
 
 	logger.Auth.Info().Str("username", user.Username).Msg("user deleted")
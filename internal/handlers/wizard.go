@@ -4,17 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
+	"openclawdeck/internal/monitor"
 	"openclawdeck/internal/openclaw"
 	"openclawdeck/internal/web"
 )
@@ -22,6 +24,12 @@ import (
 // WizardHandler handles model/channel config wizard APIs.
 type WizardHandler struct {
 	auditRepo *database.AuditLogRepo
+	svc       *openclaw.Service
+}
+
+// SetService injects the gateway service used to apply saved config.
+func (h *WizardHandler) SetService(svc *openclaw.Service) {
+	h.svc = svc
 }
 
 func NewWizardHandler() *WizardHandler {
@@ -232,10 +240,11 @@ func (h *WizardHandler) SaveModel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	config := h.buildModelConfig(req)
+	prev := readConfigSnapshot()
 
 	// write config
-	if err := h.mergeConfig(config); err != nil {
-		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+	if err := mergeConfig(config); err != nil {
+		failConfigMerge(w, r, err, web.ErrConfigWriteFailed)
 		return
 	}
 
@@ -255,7 +264,7 @@ func (h *WizardHandler) SaveModel(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionConfigUpdate,
 			Result:   "success",
 			Detail:   fmt.Sprintf("model-wizard: %s/%s", req.Provider, req.Model),
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 	}
 
@@ -265,7 +274,7 @@ func (h *WizardHandler) SaveModel(w http.ResponseWriter, r *http.Request) {
 		Str("model", req.Model).
 		Msg("model wizard config saved")
 
-	web.OK(w, r, map[string]string{"message": "ok"})
+	web.OK(w, r, map[string]interface{}{"message": "ok", "apply": applyGatewayConfig(h.svc, prev)})
 }
 
 // buildModelConfig builds config object from wizard request.
@@ -657,9 +666,10 @@ func (h *WizardHandler) SaveChannel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	config := h.buildChannelConfig(req)
+	prev := readConfigSnapshot()
 
-	if err := h.mergeConfig(config); err != nil {
-		web.FailErr(w, r, web.ErrConfigWriteFailed, err.Error())
+	if err := mergeConfig(config); err != nil {
+		failConfigMerge(w, r, err, web.ErrConfigWriteFailed)
 		return
 	}
 
@@ -671,7 +681,7 @@ func (h *WizardHandler) SaveChannel(w http.ResponseWriter, r *http.Request) {
 			Action:   constants.ActionConfigUpdate,
 			Result:   "success",
 			Detail:   fmt.Sprintf("channel-wizard: %s (dmPolicy=%s)", req.Channel, req.DmPolicy),
-			IP:       r.RemoteAddr,
+			IP:       web.ClientIP(r),
 		})
 	}
 
@@ -681,7 +691,7 @@ func (h *WizardHandler) SaveChannel(w http.ResponseWriter, r *http.Request) {
 		Str("dmPolicy", req.DmPolicy).
 		Msg("channel wizard config saved")
 
-	web.OK(w, r, map[string]string{"message": "ok"})
+	web.OK(w, r, map[string]interface{}{"message": "ok", "apply": applyGatewayConfig(h.svc, prev)})
 }
 
 // buildChannelConfig builds channel config object from wizard request.
@@ -753,92 +763,85 @@ func (h *WizardHandler) buildChannelConfig(req ChannelWizardRequest) map[string]
 // ---------- Shared Helpers ----------
 
 // mergeConfig merges config into openclaw.json.
-func (h *WizardHandler) mergeConfig(config map[string]interface{}) error {
-	// prefer openclaw CLI for safe writes
+func mergeConfig(config map[string]interface{}) error {
+	// prefer openclaw CLI for safe writes; a rejected value is an error, not a
+	// reason to bypass its schema validation with a direct write
 	if openclaw.IsOpenClawInstalled() {
-		if err := openclaw.ConfigApplyFull(config); err != nil {
-			logger.Config.Warn().Err(err).Msg("openclaw config set failed, falling back to direct write")
-			return h.writeConfigDirect(config)
-		}
-		return nil
+		return openclaw.ConfigApplyFull(config)
+	}
+	return mergeConfigDirect(config)
+}
+
+// errConfigUnreadable is returned by mergeConfigDirect when the existing
+// openclaw.json cannot be read or parsed; merging would silently replace it.
+var errConfigUnreadable = errors.New("existing config is unreadable")
+
+// failConfigMerge writes the response for a failed mergeConfig: an unreadable
+// existing config is reported as ErrConfigReadFailed, anything else as fallback.
+func failConfigMerge(w http.ResponseWriter, r *http.Request, err error, fallback *web.AppError) {
+	if errors.Is(err, errConfigUnreadable) {
+		web.FailErr(w, r, web.ErrConfigReadFailed, err.Error())
+		return
 	}
-	return h.writeConfigDirect(config)
+	web.FailErr(w, r, fallback, err.Error())
 }
 
-// writeConfigDirect writes config file directly (fallback).
-func (h *WizardHandler) writeConfigDirect(config map[string]interface{}) error {
+// mergeConfigDirect deep-merges config into openclaw.json directly (fallback).
+func mergeConfigDirect(config map[string]interface{}) error {
 	path := configPath()
 	if path == "" {
 		return fmt.Errorf("cannot determine config file path")
 	}
 
-	// read existing config
+	// read existing config; refuse to merge over a file we cannot parse, as
+	// ConfigHandler.Update does
 	existing := make(map[string]interface{})
-	if data, err := os.ReadFile(path); err == nil {
-		json.Unmarshal(data, &existing)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %v", errConfigUnreadable, err)
+	}
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("%w: not valid JSON: %v", errConfigUnreadable, err)
+		}
+	}
+
+	// without the CLI there is no schema validation; at least refuse changes
+	// that introduce errors reported by LintConfig
+	before := map[string]bool{}
+	for _, issue := range openclaw.LintConfig(existing) {
+		before[issue.Code+" ("+issue.Path+")"] = true
 	}
 
 	// deep merge
 	deepMerge(existing, config)
 
-	// atomic write
-	data, err := json.MarshalIndent(existing, "", "  ")
-	if err != nil {
-		return err
+	var errs []string
+	for _, issue := range openclaw.LintConfig(existing) {
+		if key := issue.Code + " (" + issue.Path + ")"; issue.Level == openclaw.IssueError && !before[key] {
+			errs = append(errs, key)
+		}
 	}
-	data = append(data, '\n')
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return err
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(errs, ", "))
 	}
 
-	tmpFile := path + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+	out, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
 		return err
 	}
-
-	if err := os.Rename(tmpFile, path); err != nil {
-		os.WriteFile(path, data, 0o600)
-		os.Remove(tmpFile)
-	}
-
-	return nil
+	return openclaw.WriteFileAtomic(path, append(out, '\n'))
 }
 
 // writeEnvKey writes an API key to ~/.openclaw/.env.
 func (h *WizardHandler) writeEnvKey(key, value string) {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	path := envFilePath()
+	if path == "" {
 		return
 	}
-	envPath := filepath.Join(home, ".openclaw", ".env")
-
-	// read existing content
-	existing := ""
-	if data, err := os.ReadFile(envPath); err == nil {
-		existing = string(data)
-	}
-
-	// check if key already exists
-	lines := splitLines(existing)
-	found := false
-	for i, line := range lines {
-		if len(line) > len(key)+1 && line[:len(key)+1] == key+"=" {
-			lines[i] = key + "=" + value
-			found = true
-			break
-		}
-	}
-	if !found {
-		lines = append(lines, key+"="+value)
+	if err := updateEnvFile(path, map[string]string{key: value}, nil); err != nil {
+		logger.Config.Warn().Err(err).Str("key", key).Msg("failed to write env key")
 	}
-
-	content := joinLines(lines)
-
-	dir := filepath.Dir(envPath)
-	os.MkdirAll(dir, 0o700)
-	os.WriteFile(envPath, []byte(content), 0o600)
 }
 
 // deepMerge deep-merges src into dst.
@@ -895,50 +898,10 @@ func needsProviderConfig(provider string) bool {
 	}
 }
 
-// splitLines splits a string by newlines.
-func splitLines(s string) []string {
-	if s == "" {
-		return []string{}
-	}
-	lines := []string{}
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\n' {
-			line := s[start:i]
-			if len(line) > 0 && line[len(line)-1] == '\r' {
-				line = line[:len(line)-1]
-			}
-			lines = append(lines, line)
-			start = i + 1
-		}
-	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
-	}
-	return lines
-}
-
-// joinLines joins lines into a string.
-func joinLines(lines []string) string {
-	result := ""
-	for i, line := range lines {
-		if line == "" {
-			continue
-		}
-		if i > 0 {
-			result += "\n"
-		}
-		result += line
-	}
-	if result != "" {
-		result += "\n"
-	}
-	return result
-}
-
 // ---------- Pairing Management ----------
 
-// ListPairingRequests lists pending pairing requests for a channel.
+// ListPairingRequests lists pending pairing requests for a channel with their
+// age; requests older than the pairing TTL setting are hidden.
 // GET /api/v1/pairing/list?channel=telegram
 func (h *WizardHandler) ListPairingRequests(w http.ResponseWriter, r *http.Request) {
 	channel := r.URL.Query().Get("channel")
@@ -961,18 +924,40 @@ func (h *WizardHandler) ListPairingRequests(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	ttl := database.NewSettingRepo().GetDuration(monitor.PairingRequestTTLSetting)
+	result.Requests = openclaw.ActivePairingRequests(result.Requests, ttl, time.Now())
 
 	web.OK(w, r, result)
 }
 
-// ApprovePairingRequest approves a pairing code.
+// ApprovePairingRequest approves a pairing code. When a pairing HMAC secret is
+// configured the request must be signed (see PairingHMACSecretSetting).
 // POST /api/v1/pairing/approve
 func (h *WizardHandler) ApprovePairingRequest(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		web.FailErr(w, r, web.ErrInvalidBody)
+		return
+	}
+	if secret := database.NewSettingRepo().GetString(PairingHMACSecretSetting); secret != "" {
+		sig := r.Header.Get(pairingSignatureHeader)
+		err := verifyPairingSignature(secret, r.Header.Get(pairingTimestampHeader), sig, body, time.Now())
+		if err == nil && !pairingReplays.use(sig, time.Now()) {
+			err = errPairingReplayed
+		}
+		if err != nil {
+			logger.Log.Warn().Err(err).Str("user", web.GetUsername(r)).Str("ip", r.RemoteAddr).Msg("rejected pairing approval")
+			auditMutationResult(r, constants.ActionPairingApprove, "failed", "signature check: "+err.Error())
+			web.FailErr(w, r, web.ErrPairingSignatureInvalid, err.Error())
+			return
+		}
+	}
+
 	var req struct {
 		Channel string `json:"channel"`
 		Code    string `json:"code"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		web.FailErr(w, r, web.ErrInvalidBody)
 		return
 	}
@@ -989,9 +974,11 @@ func (h *WizardHandler) ApprovePairingRequest(w http.ResponseWriter, r *http.Req
 
 	output, err := openclaw.PairingApprove(req.Channel, req.Code)
 	if err != nil {
+		auditMutationResult(r, constants.ActionPairingApprove, "failed", "channel "+req.Channel+": "+err.Error())
 		web.Fail(w, r, "PAIRING_APPROVE_FAILED", err.Error(), http.StatusBadRequest)
 		return
 	}
+	auditMutation(r, constants.ActionPairingApprove, "channel "+req.Channel)
 
 	web.OK(w, r, map[string]string{
 		"message": output,
@@ -1,9 +1,12 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 
 	"openclawdeck/internal/webconfig"
 
@@ -28,28 +31,53 @@ var (
 	DB       zerolog.Logger
 )
 
+// configuredLevel is the level from the deck config, restored by ResetLevel.
+var configuredLevel atomic.Int32
+
+// activeFile is the log file currently written to ("" when logging only to the console).
+var activeFile atomic.Value
+
+// Init configures the loggers. Logs go to cfg.FilePath, rotated by size
+// (MaxSizeMB) and age (MaxAgeDays) keeping MaxBackups old files. Debug mode
+// also logs to the console on stderr; production mode adds JSON lines on
+// stdout when cfg.Stdout is set. If the log directory cannot be created,
+// logs fall back to stderr.
 func Init(cfg webconfig.LogConfig) {
 	level := parseLevel(cfg.Level)
 	zerolog.SetGlobalLevel(level)
+	configuredLevel.Store(int32(level))
 
-	var writer io.Writer
-
-	if cfg.Mode == "debug" {
-		writer = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"}
-	} else {
-		if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0o755); err != nil {
-			writer = os.Stderr
-		} else {
-			lj := &lumberjack.Logger{
+	var writers []io.Writer
+	file := ""
+	if cfg.FilePath != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0o755); err == nil {
+			writers = append(writers, &lumberjack.Logger{
 				Filename:   cfg.FilePath,
 				MaxSize:    cfg.MaxSizeMB,
 				MaxBackups: cfg.MaxBackups,
 				MaxAge:     cfg.MaxAgeDays,
 				Compress:   cfg.Compress,
-			}
-			writer = lj
+			})
+			file = cfg.FilePath
 		}
 	}
+	activeFile.Store(file)
+
+	if cfg.Mode == "debug" {
+		writers = append(writers, zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"})
+	} else if cfg.Stdout {
+		writers = append(writers, os.Stdout)
+	}
+
+	var writer io.Writer
+	switch len(writers) {
+	case 0:
+		writer = os.Stderr
+	case 1:
+		writer = writers[0]
+	default:
+		writer = zerolog.MultiLevelWriter(writers...)
+	}
 
 	Log = zerolog.New(writer).With().Timestamp().Caller().Logger()
 
@@ -66,6 +94,44 @@ func Init(cfg webconfig.LogConfig) {
 	DB = Log.With().Str("module", "database").Logger()
 }
 
+// FilePath returns the log file currently written to, or "" when logs only
+// go to the console.
+func FilePath() string {
+	path, _ := activeFile.Load().(string)
+	return path
+}
+
+// Levels lists the level names accepted by SetLevel, most verbose first.
+var Levels = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// SetLevel changes the level of all loggers at runtime. Unlike the config
+// file, unknown names are rejected instead of falling back to info.
+func SetLevel(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, l := range Levels {
+		if l == name {
+			zerolog.SetGlobalLevel(parseLevel(name))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log level %q (want one of %s)", name, strings.Join(Levels, ", "))
+}
+
+// ResetLevel restores the level from the deck config.
+func ResetLevel() {
+	zerolog.SetGlobalLevel(zerolog.Level(configuredLevel.Load()))
+}
+
+// Level returns the current level name.
+func Level() string {
+	return zerolog.GlobalLevel().String()
+}
+
+// ConfiguredLevel returns the level name from the deck config.
+func ConfiguredLevel() string {
+	return zerolog.Level(configuredLevel.Load()).String()
+}
+
 func parseLevel(s string) zerolog.Level {
 	switch s {
 	case "trace":
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"testing"
+
+	"openclawdeck/internal/webconfig"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLevel(t *testing.T) {
+	Init(webconfig.LogConfig{Level: "info", Mode: "debug"})
+	t.Cleanup(ResetLevel)
+
+	assert.NoError(t, SetLevel(" DEBUG "))
+	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+	assert.Equal(t, "debug", Level())
+	assert.Equal(t, "info", ConfiguredLevel())
+
+	assert.Error(t, SetLevel("verbose"))
+	assert.Equal(t, "debug", Level(), "invalid level leaves the current one")
+
+	ResetLevel()
+	assert.Equal(t, "info", Level())
+}
@@ -0,0 +1,167 @@
+package monitor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/security"
+	"openclawdeck/internal/web"
+)
+
+// AlertRuleMatcher 告警规则匹配器（安全引擎禁用时使用）
+// 仅对 Gateway 工具调用事件做正则分类与告警，不做任何拦截
+type AlertRuleMatcher struct {
+	ruleRepo  *database.RiskRuleRepo
+	alertRepo *database.AlertRepo
+	wsHub     *web.WSHub
+	notifier  security.Notifier
+	rules     []database.RiskRule
+	compiled  map[uint]*regexp.Regexp
+	mu        sync.RWMutex
+}
+
+// NewAlertRuleMatcher 创建告警规则匹配器
+func NewAlertRuleMatcher(wsHub *web.WSHub) *AlertRuleMatcher {
+	return &AlertRuleMatcher{
+		ruleRepo:  database.NewRiskRuleRepo(),
+		alertRepo: database.NewAlertRepo(),
+		wsHub:     wsHub,
+		compiled:  make(map[uint]*regexp.Regexp),
+	}
+}
+
+// SetNotifier 注入外部通知发送器
+func (m *AlertRuleMatcher) SetNotifier(n security.Notifier) {
+	m.notifier = n
+}
+
+// Reload 从 RiskRule 表重新加载启用的规则
+func (m *AlertRuleMatcher) Reload() error {
+	rules, err := m.ruleRepo.ListEnabled()
+	if err != nil {
+		return err
+	}
+	n := m.setRules(rules)
+	logger.Monitor.Info().Int("count", n).Msg("告警规则已加载")
+	return nil
+}
+
+// setRules 编译并替换规则，返回编译成功的数量。
+// 规则统一按不区分大小写匹配，原文不做转换，避免大写模式永远无法命中
+func (m *AlertRuleMatcher) setRules(rules []database.RiskRule) int {
+	compiled := make(map[uint]*regexp.Regexp)
+	for _, r := range rules {
+		if r.Pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + r.Pattern)
+		if err != nil {
+			logger.Monitor.Warn().
+				Str("rule_id", r.RuleID).
+				Str("pattern", r.Pattern).
+				Err(err).
+				Msg("告警规则正则编译失败，跳过")
+			continue
+		}
+		compiled[r.ID] = re
+	}
+
+	m.mu.Lock()
+	m.rules = rules
+	m.compiled = compiled
+	m.mu.Unlock()
+	return len(compiled)
+}
+
+// Match 返回匹配的最高风险规则（分类为空表示匹配所有分类）
+func (m *AlertRuleMatcher) Match(category, source, summary string) *database.RiskRule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var best *database.RiskRule
+	text := source + " " + summary
+	for i := range m.rules {
+		rule := &m.rules[i]
+		if rule.Category != "" && !strings.EqualFold(rule.Category, category) {
+			continue
+		}
+		re, ok := m.compiled[rule.ID]
+		if !ok || !re.MatchString(text) {
+			continue
+		}
+		if best == nil || alertRiskLevel(rule.Risk) > alertRiskLevel(best.Risk) {
+			r := *rule
+			best = &r
+		}
+	}
+	return best
+}
+
+// Fire 记录告警、推送 WebSocket 并发送外部通知（维护模式下跳过）
+func (m *AlertRuleMatcher) Fire(rule *database.RiskRule, summary, detail string) {
+	if MaintenanceActive() {
+		return
+	}
+	alert := &database.Alert{
+		AlertID: "alert_" + time.Now().UTC().Format("20060102150405") + "_" + alertRandomHex(4),
+		Risk:    rule.Risk,
+		Message: rule.Reason + "：" + summary,
+		Detail:  detail,
+	}
+	if err := m.alertRepo.Create(alert); err != nil {
+		logger.Monitor.Warn().Err(err).Str("rule_id", rule.RuleID).Msg("写入告警失败")
+	}
+	forwardAlert(alert)
+
+	m.wsHub.Broadcast("alert", "alert", map[string]interface{}{
+		"id":        alert.AlertID,
+		"risk":      alert.Risk,
+		"message":   alert.Message,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+
+	logger.Monitor.Warn().
+		Str("rule_id", rule.RuleID).
+		Str("risk", rule.Risk).
+		Str("summary", summary).
+		Msg("告警规则触发")
+
+	if m.notifier != nil {
+		go m.notifier.SendAlert(alert.Risk, alert.Message, "")
+	}
+}
+
+// maxRisk 返回两者中较高的风险等级；规则只能提升风险，不能降低
+func maxRisk(a, b string) string {
+	if alertRiskLevel(b) > alertRiskLevel(a) {
+		return b
+	}
+	return a
+}
+
+func alertRiskLevel(risk string) int {
+	switch risk {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func alertRandomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"testing"
+
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleMatcher_CaseInsensitive(t *testing.T) {
+	m := NewAlertRuleMatcher(nil)
+	m.setRules([]database.RiskRule{
+		{ID: 1, RuleID: "upper", Risk: "high", Pattern: `RM\s+-RF`},
+		{ID: 2, RuleID: "lower", Risk: "medium", Pattern: `curl .*\|\s*sh`},
+	})
+
+	rule := m.Match("Shell", "bash", "rm -rf /tmp/x")
+	require.NotNil(t, rule)
+	assert.Equal(t, "upper", rule.RuleID)
+
+	rule = m.Match("Shell", "bash", "CURL https://x.sh | SH")
+	require.NotNil(t, rule)
+	assert.Equal(t, "lower", rule.RuleID)
+}
+
+func TestAlertRuleMatcher_CategoryAndHighestRisk(t *testing.T) {
+	m := NewAlertRuleMatcher(nil)
+	m.setRules([]database.RiskRule{
+		{ID: 1, RuleID: "any-medium", Risk: "medium", Pattern: `passwd`},
+		{ID: 2, RuleID: "file-critical", Category: "File", Risk: "critical", Pattern: `/etc/passwd`},
+		{ID: 3, RuleID: "broken", Risk: "critical", Pattern: `(`},
+	})
+
+	rule := m.Match("File", "read_file", "/etc/passwd")
+	require.NotNil(t, rule)
+	assert.Equal(t, "file-critical", rule.RuleID)
+
+	rule = m.Match("Shell", "bash", "cat /etc/passwd")
+	require.NotNil(t, rule)
+	assert.Equal(t, "any-medium", rule.RuleID)
+
+	assert.Nil(t, m.Match("Shell", "bash", "ls"))
+}
+
+func TestMaxRisk(t *testing.T) {
+	assert.Equal(t, "high", maxRisk("low", "high"))
+	assert.Equal(t, "medium", maxRisk("medium", "low"))
+	assert.Equal(t, "low", maxRisk("low", ""))
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+)
+
+// DBVacuumEnabledSetting 定期 VACUUM 开关（默认开启）
+const DBVacuumEnabledSetting = "db_vacuum_enabled"
+
+const (
+	// dbMaintenanceInterval 定期检查间隔
+	dbMaintenanceInterval = 6 * time.Hour
+	// vacuumMinReclaimable 可回收空间低于该值时不做 VACUUM
+	vacuumMinReclaimable = 1 << 20
+	// vacuumReclaimableBytes / vacuumReclaimableRatio 任一满足即执行
+	vacuumReclaimableBytes = 16 << 20
+	vacuumReclaimableRatio = 0.25
+	// BusyWriteWindow / BusyWriteThreshold 窗口内写入行数超过阈值视为高频写入期，跳过 VACUUM
+	BusyWriteWindow    = 5 * time.Minute
+	BusyWriteThreshold = 500
+)
+
+// DBMaintenance 定期清理过期数据并回收 SQLite 空闲空间
+type DBMaintenance struct {
+	settingRepo *database.SettingRepo
+	sessionRepo *database.AuthSessionRepo
+	stopCh      chan struct{}
+}
+
+// NewDBMaintenance 创建数据库维护任务
+func NewDBMaintenance() *DBMaintenance {
+	return &DBMaintenance{
+		settingRepo: database.NewSettingRepo(),
+		sessionRepo: database.NewAuthSessionRepo(),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start 定期执行维护，直到 Stop
+func (m *DBMaintenance) Start() {
+	ticker := time.NewTicker(dbMaintenanceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.run()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止定期维护
+func (m *DBMaintenance) Stop() {
+	select {
+	case <-m.stopCh:
+	default:
+		close(m.stopCh)
+	}
+}
+
+func (m *DBMaintenance) run() {
+	// 过期会话始终清理，开关只控制 VACUUM
+	if err := m.sessionRepo.DeleteExpired(); err != nil {
+		logger.DB.Warn().Err(err).Msg("清理过期会话失败")
+	}
+	if !m.settingRepo.GetBool(DBVacuumEnabledSetting) {
+		return
+	}
+
+	stats, err := database.Stats()
+	if err != nil {
+		return // 非 SQLite
+	}
+	if !vacuumWorthwhile(stats) {
+		return
+	}
+	if n := database.RecentWrites(time.Now().Add(-BusyWriteWindow)); n > BusyWriteThreshold {
+		logger.DB.Info().Int64("writes", n).Msg("近期写入频繁，跳过本次 VACUUM")
+		return
+	}
+
+	res, err := database.Vacuum()
+	if err != nil {
+		logger.DB.Warn().Err(err).Msg("数据库 VACUUM 失败")
+		return
+	}
+	logger.DB.Info().
+		Str("mode", res.Mode).
+		Int64("before", res.BeforeBytes).
+		Int64("after", res.AfterBytes).
+		Int64("duration_ms", res.DurationMs).
+		Msg("数据库 VACUUM 完成")
+}
+
+// vacuumWorthwhile 可回收空间足够大（绝对值或占比）时才值得 VACUUM
+func vacuumWorthwhile(st database.DBStats) bool {
+	if st.ReclaimableBytes < vacuumMinReclaimable {
+		return false
+	}
+	if st.ReclaimableBytes >= vacuumReclaimableBytes {
+		return true
+	}
+	return st.SizeBytes > 0 && float64(st.ReclaimableBytes)/float64(st.SizeBytes) >= vacuumReclaimableRatio
+}
@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVacuumWorthwhile(t *testing.T) {
+	mb := int64(1 << 20)
+	assert.False(t, vacuumWorthwhile(database.DBStats{SizeBytes: 2 * mb, ReclaimableBytes: mb / 2}), "below minimum")
+	assert.True(t, vacuumWorthwhile(database.DBStats{SizeBytes: 4 * mb, ReclaimableBytes: 2 * mb}), "half the file is free")
+	assert.False(t, vacuumWorthwhile(database.DBStats{SizeBytes: 100 * mb, ReclaimableBytes: 10 * mb}), "small share of a large file")
+	assert.True(t, vacuumWorthwhile(database.DBStats{SizeBytes: 500 * mb, ReclaimableBytes: 20 * mb}), "large absolute amount")
+}
+
+func TestDBMaintenanceRun_DeletesExpiredSessionsWhenVacuumDisabled(t *testing.T) {
+	setupTestDB(t, &database.AuthSession{}, &database.Setting{})
+	require.NoError(t, database.NewSettingRepo().Set(DBVacuumEnabledSetting, "false"))
+	now := time.Now().UTC()
+	require.NoError(t, database.DB.Create(&database.AuthSession{JTI: "old", ExpiresAt: now.Add(-time.Hour)}).Error)
+	require.NoError(t, database.DB.Create(&database.AuthSession{JTI: "live", ExpiresAt: now.Add(time.Hour)}).Error)
+
+	NewDBMaintenance().run()
+
+	var jtis []string
+	require.NoError(t, database.DB.Model(&database.AuthSession{}).Pluck("jti", &jtis).Error)
+	assert.Equal(t, []string{"live"}, jtis)
+}
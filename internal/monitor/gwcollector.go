@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"openclawdeck/internal/constants"
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
 	"openclawdeck/internal/openclaw"
@@ -18,11 +20,18 @@ import (
 type GWCollector struct {
 	client       *openclaw.GWClient
 	activityRepo *database.ActivityRepo
+	snapshotRepo *database.MonitorSessionSnapshotRepo
 	wsHub        *web.WSHub
 	engine       *security.Engine
-	interval     time.Duration
+	alertRules   *AlertRuleMatcher
+	interval     *pollInterval
 	stopCh       chan struct{}
 	running      bool
+	paused       atomic.Bool
+
+	// 采集新鲜度（UnixNano，0 表示尚无）
+	startedAt   atomic.Int64
+	lastEventAt atomic.Int64
 
 	// 已处理的会话快照（用于增量检测）
 	lastSessions map[string]sessionSnapshot
@@ -37,40 +46,50 @@ type sessionSnapshot struct {
 
 // NewGWCollector 创建 GW 事件采集器
 func NewGWCollector(client *openclaw.GWClient, wsHub *web.WSHub, engine *security.Engine, intervalSec int) *GWCollector {
-	if intervalSec < 10 {
-		intervalSec = 30
-	}
 	return &GWCollector{
 		client:       client,
 		activityRepo: database.NewActivityRepo(),
+		snapshotRepo: database.NewMonitorSessionSnapshotRepo(),
 		wsHub:        wsHub,
 		engine:       engine,
-		interval:     time.Duration(intervalSec) * time.Second,
+		interval:     newPollInterval(intervalSec),
 		stopCh:       make(chan struct{}),
 		lastSessions: make(map[string]sessionSnapshot),
 	}
 }
 
+// SetAlertRules 注入告警规则匹配器（安全引擎未启用时用于分类与告警）
+func (c *GWCollector) SetAlertRules(m *AlertRuleMatcher) {
+	c.alertRules = m
+}
+
 // Start 启动采集循环
 func (c *GWCollector) Start() {
 	c.running = true
+	c.startedAt.Store(time.Now().UnixNano())
 	logger.Monitor.Info().
-		Dur("interval", c.interval).
+		Dur("interval", c.interval.get()).
 		Msg("GW 事件采集器已启动（通过 WebSocket 采集）")
 
 	// 注册 Gateway WS 事件回调
 	c.client.SetEventHandler(c.handleEvent)
 
+	// 恢复上次运行的会话快照，避免重启后把所有会话当作首次运行重复记录
+	c.loadSnapshots()
+
 	// 首次立即采集
 	c.poll()
 
-	ticker := time.NewTicker(c.interval)
+	ticker := time.NewTicker(c.interval.get())
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
 			c.poll()
+		case d := <-c.interval.ch:
+			ticker.Reset(d)
+			logger.Monitor.Info().Dur("interval", d).Msg("GW 采集轮询间隔已更新")
 		case <-c.stopCh:
 			c.running = false
 			logger.Monitor.Info().Msg("GW 事件采集器已停止")
@@ -79,6 +98,28 @@ func (c *GWCollector) Start() {
 	}
 }
 
+// SetInterval 运行时修改轮询间隔（秒），无需重启采集器
+func (c *GWCollector) SetInterval(sec int) time.Duration {
+	return c.interval.set(sec)
+}
+
+// LastEventAt 返回最近一次写入活动的时间（零值表示尚无）
+func (c *GWCollector) LastEventAt() time.Time {
+	return unixNanoTime(c.lastEventAt.Load())
+}
+
+// StartedAt 返回采集器启动时间（零值表示未启动）
+func (c *GWCollector) StartedAt() time.Time {
+	return unixNanoTime(c.startedAt.Load())
+}
+
+func unixNanoTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
+
 // Stop 停止采集
 func (c *GWCollector) Stop() {
 	if c.running {
@@ -87,6 +128,18 @@ func (c *GWCollector) Stop() {
 	}
 }
 
+// SetPaused 暂停/恢复采集（维护模式）；暂停期间实时事件仍转发到前端，但不轮询、不记录活动
+func (c *GWCollector) SetPaused(paused bool) {
+	if c.paused.Swap(paused) != paused {
+		logger.Monitor.Info().Bool("paused", paused).Msg("GW 事件采集器暂停状态已变更")
+	}
+}
+
+// Paused 采集是否已暂停
+func (c *GWCollector) Paused() bool {
+	return c.paused.Load()
+}
+
 // IsRunning 是否正在运行
 func (c *GWCollector) IsRunning() bool {
 	return c.running
@@ -95,7 +148,10 @@ func (c *GWCollector) IsRunning() bool {
 // handleEvent 处理 Gateway WS 推送的实时事件
 func (c *GWCollector) handleEvent(event string, payload json.RawMessage) {
 	// 转发到前端 WebSocket
-	c.wsHub.Broadcast("gw_event", event, payload)
+	c.wsHub.Broadcast(web.GWEventChannel, event, payload)
+	if c.paused.Load() {
+		return
+	}
 
 	// 解析并记录有意义的事件
 	switch {
@@ -188,6 +244,13 @@ func (c *GWCollector) handleToolEvent(event string, payload json.RawMessage) {
 			risk = result.Rule.Risk
 			actionTaken = c.engine.ProcessEvent(category, toolName, summary, string(payload), data.SessionID)
 		}
+	} else if c.alertRules != nil {
+		// 告警规则：仅提升风险等级并通知，不拦截
+		if rule := c.alertRules.Match(category, toolName, summary); rule != nil {
+			risk = maxRisk(risk, rule.Risk)
+			actionTaken = constants.ActionTakenNotify
+			c.alertRules.Fire(rule, summary, string(payload))
+		}
 	}
 
 	c.writeActivity(category, risk, summary, string(payload), toolName, actionTaken, data.SessionID)
@@ -227,52 +290,68 @@ func (c *GWCollector) handleCronEvent(event string, payload json.RawMessage) {
 
 // poll 定时轮询 Gateway 会话数据，检测变化
 func (c *GWCollector) poll() {
+	if c.paused.Load() {
+		return
+	}
 	if !c.client.IsConnected() {
 		logger.Monitor.Debug().Msg("GW 轮询跳过：未连接")
 		return
 	}
 
 	// 获取会话列表
-	data, err := c.client.Request("sessions.list", map[string]interface{}{})
+	sessions, err := fetchGWSessions(c.client)
 	if err != nil {
 		logger.Monitor.Debug().Err(err).Msg("GW 轮询会话列表失败")
 		return
 	}
 
+	logger.Monitor.Debug().Int("sessions", len(sessions)).Int("known", len(c.lastSessions)).Msg("GW 轮询会话")
+
+	newCount := c.processSessions(sessions)
+	if newCount > 0 {
+		logger.Monitor.Debug().Int("new_events", newCount).Msg("GW 轮询发现新活动")
+	}
+}
+
+// gwSession sessions.list 返回的会话条目
+type gwSession struct {
+	Key          string `json:"key"`
+	SessionID    string `json:"sessionId"`
+	DisplayName  string `json:"displayName"`
+	Model        string `json:"model"`
+	InputTokens  int64  `json:"inputTokens"`
+	OutputTokens int64  `json:"outputTokens"`
+	TotalTokens  int64  `json:"totalTokens"`
+	UpdatedAt    int64  `json:"updatedAt"`
+	LastChannel  string `json:"lastChannel"`
+	Kind         string `json:"kind"`
+}
+
+// fetchGWSessions 调用 sessions.list 获取会话快照
+func fetchGWSessions(client *openclaw.GWClient) ([]gwSession, error) {
+	data, err := client.Request("sessions.list", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
 	var result struct {
-		Sessions []struct {
-			Key          string `json:"key"`
-			SessionID    string `json:"sessionId"`
-			DisplayName  string `json:"displayName"`
-			Model        string `json:"model"`
-			InputTokens  int64  `json:"inputTokens"`
-			OutputTokens int64  `json:"outputTokens"`
-			TotalTokens  int64  `json:"totalTokens"`
-			UpdatedAt    int64  `json:"updatedAt"`
-			LastChannel  string `json:"lastChannel"`
-			Kind         string `json:"kind"`
-		} `json:"sessions"`
+		Sessions []gwSession `json:"sessions"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
-		logger.Monitor.Debug().Err(err).Msg("解析会话列表失败")
-		return
+		return nil, fmt.Errorf("解析会话列表失败: %w", err)
 	}
+	return result.Sessions, nil
+}
 
-	logger.Monitor.Debug().Int("sessions", len(result.Sessions)).Int("known", len(c.lastSessions)).Msg("GW 轮询会话")
-
+// processSessions 与上次快照比较，记录新会话 / token 增量，返回新增活动数
+func (c *GWCollector) processSessions(sessions []gwSession) int {
 	firstRun := len(c.lastSessions) == 0
 	newCount := 0
-	for _, sess := range result.Sessions {
+	for _, sess := range sessions {
 		prev, exists := c.lastSessions[sess.Key]
 
 		if !exists {
 			// 记录快照
-			c.lastSessions[sess.Key] = sessionSnapshot{
-				InputTokens:  sess.InputTokens,
-				OutputTokens: sess.OutputTokens,
-				TotalTokens:  sess.TotalTokens,
-				UpdatedAt:    sess.UpdatedAt,
-			}
+			c.saveSnapshot(sess)
 
 			// 首次运行：为每个现有会话创建一条概览记录
 			displayName := sess.DisplayName
@@ -339,17 +418,49 @@ func (c *GWCollector) poll() {
 			c.writeActivity("Message", "low", summary, string(detail), source, "allow", sess.SessionID)
 			newCount++
 
-			c.lastSessions[sess.Key] = sessionSnapshot{
-				InputTokens:  sess.InputTokens,
-				OutputTokens: sess.OutputTokens,
-				TotalTokens:  sess.TotalTokens,
-				UpdatedAt:    sess.UpdatedAt,
-			}
+			c.saveSnapshot(sess)
 		}
 	}
 
-	if newCount > 0 {
-		logger.Monitor.Debug().Int("new_events", newCount).Msg("GW 轮询发现新活动")
+	return newCount
+}
+
+// loadSnapshots 从数据库加载持久化的会话快照
+func (c *GWCollector) loadSnapshots() {
+	list, err := c.snapshotRepo.ListAll()
+	if err != nil {
+		logger.Monitor.Warn().Err(err).Msg("加载会话快照失败")
+		return
+	}
+	for _, s := range list {
+		c.lastSessions[s.SessionKey] = sessionSnapshot{
+			InputTokens:  s.InputTokens,
+			OutputTokens: s.OutputTokens,
+			TotalTokens:  s.TotalTokens,
+			UpdatedAt:    s.LastActiveMs,
+		}
+	}
+	if len(list) > 0 {
+		logger.Monitor.Info().Int("sessions", len(list)).Msg("已恢复会话快照")
+	}
+}
+
+// saveSnapshot 更新内存快照并持久化
+func (c *GWCollector) saveSnapshot(sess gwSession) {
+	c.lastSessions[sess.Key] = sessionSnapshot{
+		InputTokens:  sess.InputTokens,
+		OutputTokens: sess.OutputTokens,
+		TotalTokens:  sess.TotalTokens,
+		UpdatedAt:    sess.UpdatedAt,
+	}
+	if err := c.snapshotRepo.Upsert(&database.MonitorSessionSnapshot{
+		SessionKey:   sess.Key,
+		InputTokens:  sess.InputTokens,
+		OutputTokens: sess.OutputTokens,
+		TotalTokens:  sess.TotalTokens,
+		LastActiveMs: sess.UpdatedAt,
+	}); err != nil {
+		logger.Monitor.Debug().Err(err).Str("key", sess.Key).Msg("保存会话快照失败")
 	}
 }
 
@@ -373,6 +484,8 @@ func (c *GWCollector) writeActivity(category, risk, summary, detail, source, act
 		logger.Monitor.Warn().Str("event_id", eventID).Err(err).Msg("写入 GW 活动记录失败")
 		return
 	}
+	c.lastEventAt.Store(activity.Timestamp.UnixNano())
+	forwardActivity(activity)
 
 	// 推送到前端 WebSocket
 	c.wsHub.Broadcast("activity", "activity", map[string]interface{}{
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/testutil"
+	"openclawdeck/internal/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGWCollector_ResumeSnapshotsAfterRestart(t *testing.T) {
+	cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	hub := web.NewWSHub()
+	go hub.Run()
+	activities := database.NewActivityRepo()
+
+	sessions := []gwSession{
+		{Key: "main", Model: "gpt", InputTokens: 60, OutputTokens: 40, TotalTokens: 100, UpdatedAt: 1000},
+		{Key: "cron", Model: "gpt", InputTokens: 30, OutputTokens: 20, TotalTokens: 50, UpdatedAt: 1000},
+	}
+
+	// first run: one overview activity per session
+	first := NewGWCollector(nil, hub, nil, 30)
+	first.loadSnapshots()
+	assert.Equal(t, 2, first.processSessions(sessions))
+
+	// restart: snapshots reloaded from DB, only the changed session emits a delta
+	restarted := NewGWCollector(nil, hub, nil, 30)
+	restarted.loadSnapshots()
+	require.Len(t, restarted.lastSessions, 2)
+
+	sessions[1].OutputTokens, sessions[1].TotalTokens, sessions[1].UpdatedAt = 50, 80, 2000
+	assert.Equal(t, 1, restarted.processSessions(sessions))
+
+	total, err := activities.Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	// snapshot was updated, so another restart sees no change
+	again := NewGWCollector(nil, hub, nil, 30)
+	again.loadSnapshots()
+	assert.Equal(t, 0, again.processSessions(sessions))
+}
+
+func TestPollInterval_SetKeepsLatest(t *testing.T) {
+	p := newPollInterval(5)
+	assert.Equal(t, 10*time.Second, p.get(), "below minimum is raised to the minimum")
+	assert.Equal(t, 30*time.Second, newPollInterval(0).get(), "unset uses the default")
+
+	p.set(20)
+	p.set(45)
+	assert.Equal(t, 45*time.Second, p.get())
+	assert.Equal(t, 45*time.Second, <-p.ch)
+	select {
+	case d := <-p.ch:
+		t.Fatalf("stale interval %v left in channel", d)
+	default:
+	}
+}
@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+)
+
+// 设置项：空闲会话自动重置（默认关闭）
+const (
+	IdleResetEnabledSetting = "session_idle_reset_enabled"
+	IdleResetHoursSetting   = "session_idle_reset_hours" // 空闲多少小时后重置
+)
+
+// DefaultIdleResetHours 未配置空闲时长时的默认值
+const DefaultIdleResetHours = 72
+
+// idleResetInterval 定期检查间隔
+const idleResetInterval = 10 * time.Minute
+
+// IdleSession 超过空闲时长的会话
+type IdleSession struct {
+	Key         string    `json:"key"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Kind        string    `json:"kind,omitempty"`
+	TotalTokens int64     `json:"totalTokens"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	IdleHours   float64   `json:"idleHours"`
+}
+
+// IdleResetPreview 空闲会话预览（dry-run），不执行任何重置
+type IdleResetPreview struct {
+	Enabled    bool              `json:"enabled"`
+	IdleHours  int               `json:"idleHours"`
+	Candidates []IdleSession     `json:"candidates"`
+	LastRun    *IdleResetRunInfo `json:"lastRun,omitempty"`
+}
+
+// IdleResetRunInfo 最近一次自动重置的结果
+type IdleResetRunInfo struct {
+	At     time.Time `json:"at"`
+	Reset  []string  `json:"reset"`
+	Failed []string  `json:"failed,omitempty"`
+}
+
+// IdleSessionResetter 定期调用 sessions.list，把超过空闲时长的会话重置（不删除），
+// 需在设置中显式开启；每次读取最新设置，修改后无需重启
+type IdleSessionResetter struct {
+	client      *openclaw.GWClient
+	settingRepo *database.SettingRepo
+	stopCh      chan struct{}
+
+	mu      sync.Mutex
+	lastRun *IdleResetRunInfo
+}
+
+// NewIdleSessionResetter 创建空闲会话重置器
+func NewIdleSessionResetter(client *openclaw.GWClient) *IdleSessionResetter {
+	return &IdleSessionResetter{
+		client:      client,
+		settingRepo: database.NewSettingRepo(),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start 定期检查，直到 Stop
+func (r *IdleSessionResetter) Start() {
+	ticker := time.NewTicker(idleResetInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.run()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止定期检查
+func (r *IdleSessionResetter) Stop() {
+	select {
+	case <-r.stopCh:
+	default:
+		close(r.stopCh)
+	}
+}
+
+// settings 读取开关与空闲时长
+func (r *IdleSessionResetter) settings() (enabled bool, hours int) {
+	enabled = r.settingRepo.GetBool(IdleResetEnabledSetting)
+	hours = int(r.settingRepo.GetDuration(IdleResetHoursSetting) / time.Hour)
+	return enabled, hours
+}
+
+// Preview 返回当前会被重置的会话，不执行重置
+func (r *IdleSessionResetter) Preview() (*IdleResetPreview, error) {
+	enabled, hours := r.settings()
+	if !r.client.IsConnected() {
+		return nil, errors.New("gateway not connected")
+	}
+	sessions, err := fetchGWSessions(r.client)
+	if err != nil {
+		return nil, err
+	}
+	p := &IdleResetPreview{
+		Enabled:    enabled,
+		IdleHours:  hours,
+		Candidates: idleCandidates(sessions, time.Now(), time.Duration(hours)*time.Hour),
+	}
+	r.mu.Lock()
+	p.LastRun = r.lastRun
+	r.mu.Unlock()
+	return p, nil
+}
+
+// run 执行一次检查与重置
+func (r *IdleSessionResetter) run() {
+	enabled, hours := r.settings()
+	if !enabled || !r.client.IsConnected() {
+		return
+	}
+	sessions, err := fetchGWSessions(r.client)
+	if err != nil {
+		logger.Monitor.Debug().Err(err).Msg("空闲会话检查：获取会话列表失败")
+		return
+	}
+	candidates := idleCandidates(sessions, time.Now(), time.Duration(hours)*time.Hour)
+	if len(candidates) == 0 {
+		return
+	}
+
+	info := &IdleResetRunInfo{At: time.Now(), Reset: []string{}}
+	for _, s := range candidates {
+		if _, err := r.client.Request("sessions.reset", map[string]interface{}{"key": s.Key}); err != nil {
+			info.Failed = append(info.Failed, s.Key)
+			logger.Monitor.Warn().Err(err).Str("session", s.Key).Msg("重置空闲会话失败")
+			continue
+		}
+		info.Reset = append(info.Reset, s.Key)
+		logger.Monitor.Info().
+			Str("session", s.Key).
+			Float64("idle_hours", s.IdleHours).
+			Int64("tokens", s.TotalTokens).
+			Msg("已重置空闲会话")
+	}
+	r.mu.Lock()
+	r.lastRun = info
+	r.mu.Unlock()
+}
+
+// idleCandidates 筛选最后活动早于 now-window 的会话，最久未活动的在前；
+// 无时间戳或已为空（无 token）的会话跳过
+func idleCandidates(sessions []gwSession, now time.Time, window time.Duration) []IdleSession {
+	out := []IdleSession{}
+	cutoff := now.Add(-window)
+	for _, s := range sessions {
+		if s.Key == "" || s.UpdatedAt <= 0 || s.TotalTokens == 0 {
+			continue
+		}
+		updated := time.UnixMilli(s.UpdatedAt)
+		if !updated.Before(cutoff) {
+			continue
+		}
+		out = append(out, IdleSession{
+			Key:         s.Key,
+			DisplayName: s.DisplayName,
+			Kind:        s.Kind,
+			TotalTokens: s.TotalTokens,
+			UpdatedAt:   updated,
+			IdleHours:   float64(now.Sub(updated).Round(time.Minute)) / float64(time.Hour),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.Before(out[j].UpdatedAt) })
+	return out
+}
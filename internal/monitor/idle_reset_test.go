@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleCandidates(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) int64 { return now.Add(-d).UnixMilli() }
+	sessions := []gwSession{
+		{Key: "recent", TotalTokens: 10, UpdatedAt: ms(2 * time.Hour)},
+		{Key: "old", TotalTokens: 10, UpdatedAt: ms(80 * time.Hour)},
+		{Key: "older", TotalTokens: 10, UpdatedAt: ms(200 * time.Hour)},
+		{Key: "empty", TotalTokens: 0, UpdatedAt: ms(200 * time.Hour)},
+		{Key: "no-ts", TotalTokens: 10},
+	}
+
+	got := idleCandidates(sessions, now, 72*time.Hour)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "older", got[0].Key)
+		assert.Equal(t, "old", got[1].Key)
+		assert.InDelta(t, 80, got[1].IdleHours, 0.01)
+	}
+	assert.Empty(t, idleCandidates(sessions, now, 300*time.Hour))
+}
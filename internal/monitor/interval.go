@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// MinPollIntervalSeconds 轮询间隔下限（秒），低于该值时按下限处理；未设置（<= 0）时使用默认 30 秒
+const MinPollIntervalSeconds = 10
+
+const defaultPollIntervalSeconds = 30
+
+// pollInterval 可热更新的轮询间隔
+// set 只保留最新值（缓冲 1），循环收到后 Reset 原有 ticker，不会新建 ticker 或重复触发
+type pollInterval struct {
+	mu sync.Mutex
+	d  time.Duration
+	ch chan time.Duration
+}
+
+func newPollInterval(sec int) *pollInterval {
+	return &pollInterval{
+		d:  clampPollInterval(sec),
+		ch: make(chan time.Duration, 1),
+	}
+}
+
+func clampPollInterval(sec int) time.Duration {
+	switch {
+	case sec <= 0:
+		sec = defaultPollIntervalSeconds
+	case sec < MinPollIntervalSeconds:
+		sec = MinPollIntervalSeconds
+	}
+	return time.Duration(sec) * time.Second
+}
+
+func (p *pollInterval) get() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.d
+}
+
+func (p *pollInterval) set(sec int) time.Duration {
+	d := clampPollInterval(sec)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.d = d
+	select {
+	case <-p.ch:
+	default:
+	}
+	p.ch <- d
+	return d
+}
@@ -0,0 +1,217 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/security"
+	"openclawdeck/internal/web"
+)
+
+// 设置项：登录失败激增检测（默认开启，仅告警不拦截）
+const (
+	LoginSpikeEnabledSetting   = "login_spike_alert_enabled"
+	LoginSpikeThresholdSetting = "login_spike_threshold"        // 窗口内登录失败次数阈值
+	LoginSpikeWindowSetting    = "login_spike_window_minutes"   // 统计窗口（分钟）
+	LoginSpikeCooldownSetting  = "login_spike_cooldown_minutes" // 两次告警的最小间隔（分钟），0 表示不限
+	loginSpikeLastAlertSetting = "login_spike_last_alert_time"  // 最近一次告警时间（RFC3339），避免重复告警
+)
+
+// 默认阈值与窗口
+const (
+	DefaultLoginSpikeThreshold = 20
+	DefaultLoginSpikeWindow    = 10 * time.Minute
+	DefaultLoginSpikeCooldown  = 30 * time.Minute
+)
+
+// loginSpikeInterval 定期检查间隔
+const loginSpikeInterval = time.Minute
+
+// loginSpikeMaxIPs 告警详情中最多列出的 IP 数
+const loginSpikeMaxIPs = 10
+
+// FailedLoginIP 单个 IP 的登录失败统计
+type FailedLoginIP struct {
+	IP        string
+	Count     int64
+	Usernames []string
+}
+
+// LoginSpikeDetector 定期统计审计日志中的登录失败次数，超过阈值时产生告警并发送通知；
+// 安全引擎禁用时作为轻量的暴力破解检测
+type LoginSpikeDetector struct {
+	auditRepo   *database.AuditLogRepo
+	alertRepo   *database.AlertRepo
+	settingRepo *database.SettingRepo
+	wsHub       *web.WSHub
+	notifier    security.Notifier
+	stopCh      chan struct{}
+}
+
+// NewLoginSpikeDetector 创建登录失败激增检测器
+func NewLoginSpikeDetector(wsHub *web.WSHub) *LoginSpikeDetector {
+	return &LoginSpikeDetector{
+		auditRepo:   database.NewAuditLogRepo(),
+		alertRepo:   database.NewAlertRepo(),
+		settingRepo: database.NewSettingRepo(),
+		wsHub:       wsHub,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// SetNotifier 注入外部通知发送器
+func (d *LoginSpikeDetector) SetNotifier(n security.Notifier) {
+	d.notifier = n
+}
+
+// Start 定期检查，直到 Stop
+func (d *LoginSpikeDetector) Start() {
+	ticker := time.NewTicker(loginSpikeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.Check(time.Now().UTC())
+		case <-d.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止定期检查
+func (d *LoginSpikeDetector) Stop() {
+	select {
+	case <-d.stopCh:
+	default:
+		close(d.stopCh)
+	}
+}
+
+// settings 读取开关、阈值与窗口
+func (d *LoginSpikeDetector) settings() (enabled bool, threshold int64, window time.Duration) {
+	enabled = d.settingRepo.GetBool(LoginSpikeEnabledSetting)
+	threshold = int64(d.settingRepo.GetInt(LoginSpikeThresholdSetting))
+	window = d.settingRepo.GetDuration(LoginSpikeWindowSetting)
+	return enabled, threshold, window
+}
+
+// Check 统计窗口内（且晚于上次告警）的登录失败，超过阈值时告警，返回是否告警；
+// 距上次告警不足冷却时间时不告警，持续攻击不会每分钟重复告警
+func (d *LoginSpikeDetector) Check(now time.Time) bool {
+	enabled, threshold, window := d.settings()
+	if !enabled {
+		return false
+	}
+	since := now.Add(-window)
+	// 上次告警前的失败已告警过，不再重复计入
+	if v, _ := d.settingRepo.Get(loginSpikeLastAlertSetting); v != "" {
+		if last, err := time.Parse(time.RFC3339, v); err == nil {
+			if now.Sub(last) < d.settingRepo.GetDuration(LoginSpikeCooldownSetting) {
+				return false
+			}
+			if last.After(since) {
+				since = last
+			}
+		}
+	}
+	rows, err := d.auditRepo.GroupByIPUserAction([]string{constants.ActionLoginFailed}, since)
+	if err != nil {
+		logger.Monitor.Debug().Err(err).Msg("登录失败激增检查：查询审计日志失败")
+		return false
+	}
+	total, ips := failedLoginsByIP(rows)
+	if total < threshold {
+		return false
+	}
+
+	d.settingRepo.Set(loginSpikeLastAlertSetting, now.Format(time.RFC3339))
+	d.fire(total, window, ips)
+	return true
+}
+
+// fire 记录告警、推送 WebSocket 并发送外部通知（维护模式下跳过）
+func (d *LoginSpikeDetector) fire(total int64, window time.Duration, ips []FailedLoginIP) {
+	if MaintenanceActive() {
+		return
+	}
+	alert := &database.Alert{
+		AlertID: "alert_" + time.Now().UTC().Format("20060102150405") + "_" + alertRandomHex(4),
+		Risk:    "high",
+		Message: fmt.Sprintf("登录失败激增：%d 分钟内 %d 次失败，来自 %d 个 IP", int(window.Minutes()), total, len(ips)),
+		Detail:  loginSpikeDetail(ips),
+	}
+	if err := d.alertRepo.Create(alert); err != nil {
+		logger.Monitor.Warn().Err(err).Msg("写入告警失败")
+	}
+	forwardAlert(alert)
+	if d.wsHub != nil {
+		d.wsHub.Broadcast("alert", "alert", map[string]interface{}{
+			"id":        alert.AlertID,
+			"risk":      alert.Risk,
+			"message":   alert.Message,
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	logger.Monitor.Warn().Int64("failures", total).Int("ips", len(ips)).Str("detail", alert.Detail).Msg("检测到登录失败激增")
+	if d.notifier != nil {
+		go d.notifier.SendAlert(alert.Risk, alert.Message, alert.Detail)
+	}
+}
+
+// failedLoginsByIP 按 IP 汇总登录失败次数，次数多的在前
+func failedLoginsByIP(rows []database.AuditGroupRow) (int64, []FailedLoginIP) {
+	var total int64
+	byIP := map[string]*FailedLoginIP{}
+	for _, row := range rows {
+		ip := row.IP
+		s := byIP[ip]
+		if s == nil {
+			s = &FailedLoginIP{IP: ip}
+			byIP[ip] = s
+		}
+		s.Count += row.Count
+		total += row.Count
+		if row.Username != "" && !containsString(s.Usernames, row.Username) {
+			s.Usernames = append(s.Usernames, row.Username)
+		}
+	}
+	out := make([]FailedLoginIP, 0, len(byIP))
+	for _, s := range byIP {
+		sort.Strings(s.Usernames)
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].IP < out[j].IP
+	})
+	return total, out
+}
+
+// loginSpikeDetail 告警详情：列出失败次数最多的 IP 及尝试的用户名
+func loginSpikeDetail(ips []FailedLoginIP) string {
+	var lines []string
+	for i, s := range ips {
+		if i == loginSpikeMaxIPs {
+			lines = append(lines, fmt.Sprintf("... 以及另外 %d 个 IP", len(ips)-loginSpikeMaxIPs))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d 次（%s）", s.IP, s.Count, strings.Join(s.Usernames, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"openclawdeck/internal/constants"
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedLoginsByIP(t *testing.T) {
+	rows := []database.AuditGroupRow{
+		{IP: "10.0.0.5", Username: "admin", Count: 12},
+		{IP: "10.0.0.5", Username: "root", Count: 6},
+		{IP: "10.0.0.5", Username: "admin", Count: 1},
+		{IP: "192.168.1.9", Username: "alice", Count: 3},
+	}
+
+	total, ips := failedLoginsByIP(rows)
+	assert.Equal(t, int64(22), total)
+	if assert.Len(t, ips, 2) {
+		assert.Equal(t, "10.0.0.5", ips[0].IP)
+		assert.Equal(t, int64(19), ips[0].Count)
+		assert.Equal(t, []string{"admin", "root"}, ips[0].Usernames)
+		assert.Equal(t, "192.168.1.9", ips[1].IP)
+	}
+
+	detail := loginSpikeDetail(ips)
+	assert.True(t, strings.HasPrefix(detail, "10.0.0.5: 19"))
+	assert.Contains(t, detail, "192.168.1.9")
+
+	total, ips = failedLoginsByIP(nil)
+	assert.Zero(t, total)
+	assert.Empty(t, ips)
+}
+
+// insertFailedLogins writes n failed-login audit rows from ip at t.
+func insertFailedLogins(t *testing.T, n int, ip string, at time.Time) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, database.DB.Create(&database.AuditLog{
+			Username:  "admin",
+			Action:    constants.ActionLoginFailed,
+			Result:    "failed",
+			IP:        fmt.Sprintf("%s:%d", ip, 50000+i),
+			CreatedAt: at,
+		}).Error)
+	}
+}
+
+func countAlerts(t *testing.T) int64 {
+	t.Helper()
+	var n int64
+	require.NoError(t, database.DB.Model(&database.Alert{}).Count(&n).Error)
+	return n
+}
+
+func TestLoginSpikeCheck_SuppressionAndCooldown(t *testing.T) {
+	setupTestDB(t, &database.AuditLog{}, &database.Alert{}, &database.Setting{})
+	d := NewLoginSpikeDetector(nil)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	insertFailedLogins(t, 15, "10.0.0.5", now.Add(-5*time.Minute))
+	insertFailedLogins(t, 4, "192.168.1.9", now.Add(-3*time.Minute))
+	assert.False(t, d.Check(now), "19 failures stay under the default threshold of 20")
+
+	insertFailedLogins(t, 1, "192.168.1.9", now.Add(-time.Minute))
+	require.True(t, d.Check(now))
+	assert.Equal(t, int64(1), countAlerts(t))
+
+	// a sustained attack does not re-alert inside the cooldown
+	insertFailedLogins(t, 25, "10.0.0.5", now.Add(2*time.Minute))
+	assert.False(t, d.Check(now.Add(3*time.Minute)))
+
+	// after the cooldown, new failures inside the window alert again
+	insertFailedLogins(t, 20, "10.0.0.5", now.Add(25*time.Minute))
+	assert.True(t, d.Check(now.Add(31*time.Minute)))
+	assert.Equal(t, int64(2), countAlerts(t))
+
+	// without a cooldown, failures already alerted on are still not counted again
+	require.NoError(t, d.settingRepo.Set(LoginSpikeCooldownSetting, "0"))
+	assert.False(t, d.Check(now.Add(32*time.Minute)))
+	insertFailedLogins(t, 20, "10.0.0.6", now.Add(32*time.Minute))
+	assert.True(t, d.Check(now.Add(33*time.Minute)))
+	assert.Equal(t, int64(3), countAlerts(t))
+
+	require.NoError(t, d.settingRepo.Set(LoginSpikeEnabledSetting, "false"))
+	insertFailedLogins(t, 20, "10.0.0.7", now.Add(34*time.Minute))
+	assert.False(t, d.Check(now.Add(35*time.Minute)))
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/web"
+)
+
+// MaintenanceModeSetting 设置项：维护模式。开启期间暂停心跳自动重启、告警、通知与事件采集
+const MaintenanceModeSetting = "maintenance_mode"
+
+// maintenanceActive 维护模式是否生效；各告警触发点据此跳过
+var maintenanceActive atomic.Bool
+
+// MaintenanceActive 维护模式是否开启
+func MaintenanceActive() bool {
+	return maintenanceActive.Load()
+}
+
+// MaintenanceState 维护模式状态
+type MaintenanceState struct {
+	Enabled bool   `json:"enabled"`
+	Since   string `json:"since,omitempty"`
+}
+
+// Maintenance 维护模式开关：开启时关闭心跳自动重启、静音通知、暂停采集；
+// 关闭时按持久化设置恢复心跳自动重启，并恢复通知与采集
+type Maintenance struct {
+	mu          sync.Mutex
+	settingRepo *database.SettingRepo
+	wsHub       *web.WSHub
+	since       time.Time
+
+	health    interface{ SetHealthCheckEnabled(bool) }
+	notifier  interface{ SetMuted(bool) }
+	collector interface{ SetPaused(bool) }
+
+	// healthEnabled 返回维护模式外心跳自动重启应处的状态（测试时可替换）
+	healthEnabled func() bool
+}
+
+// NewMaintenance 创建维护模式开关；任一依赖为 nil 时跳过对应部分
+func NewMaintenance(gwClient *openclaw.GWClient, notifier interface{ SetMuted(bool) }, collector *GWCollector, wsHub *web.WSHub) *Maintenance {
+	m := &Maintenance{
+		settingRepo: database.NewSettingRepo(),
+		wsHub:       wsHub,
+	}
+	if gwClient != nil {
+		m.health = gwClient
+	}
+	if notifier != nil {
+		m.notifier = notifier
+	}
+	if collector != nil {
+		m.collector = collector
+	}
+	m.healthEnabled = func() bool {
+		return m.settingRepo.GetBool(openclaw.HealthCheckEnabledSetting)
+	}
+	return m
+}
+
+// Restore 启动时按持久化设置恢复维护模式
+func (m *Maintenance) Restore() {
+	if m.settingRepo.GetBool(MaintenanceModeSetting) {
+		m.apply(true)
+		logger.Monitor.Warn().Msg("维护模式已开启：心跳自动重启、告警、通知与采集均已暂停")
+	}
+}
+
+// Set 开启/关闭维护模式并持久化
+func (m *Maintenance) Set(enabled bool) (MaintenanceState, error) {
+	if err := m.settingRepo.Set(MaintenanceModeSetting, strconv.FormatBool(enabled)); err != nil {
+		return m.State(), err
+	}
+	return m.Apply(enabled), nil
+}
+
+// Apply 使已保存的维护模式设置生效（不持久化），并推送状态到前端
+func (m *Maintenance) Apply(enabled bool) MaintenanceState {
+	m.apply(enabled)
+	st := m.State()
+	if m.wsHub != nil {
+		m.wsHub.Broadcast("maintenance", "maintenance", st)
+	}
+	logger.Monitor.Info().Bool("enabled", enabled).Msg("维护模式已变更")
+	return st
+}
+
+// State 返回当前维护模式状态
+func (m *Maintenance) State() MaintenanceState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := MaintenanceState{Enabled: maintenanceActive.Load()}
+	if st.Enabled && !m.since.IsZero() {
+		st.Since = m.since.UTC().Format(time.RFC3339)
+	}
+	return st
+}
+
+// apply 将维护模式作用到各组件
+func (m *Maintenance) apply(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if enabled && !maintenanceActive.Load() {
+		m.since = time.Now()
+	}
+	maintenanceActive.Store(enabled)
+
+	if m.health != nil {
+		// 关闭时恢复为设置项中的心跳自动重启状态
+		m.health.SetHealthCheckEnabled(!enabled && m.healthEnabled())
+	}
+	if m.notifier != nil {
+		m.notifier.SetMuted(enabled)
+	}
+	if m.collector != nil {
+		m.collector.SetPaused(enabled)
+	}
+}
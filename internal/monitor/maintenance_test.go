@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMaintenanceTarget struct{ health, muted, paused bool }
+
+func (f *fakeMaintenanceTarget) SetHealthCheckEnabled(v bool) { f.health = v }
+func (f *fakeMaintenanceTarget) SetMuted(v bool)              { f.muted = v }
+func (f *fakeMaintenanceTarget) SetPaused(v bool)             { f.paused = v }
+
+func TestMaintenance_ApplyAndRestore(t *testing.T) {
+	defer maintenanceActive.Store(false)
+
+	target := &fakeMaintenanceTarget{health: true}
+	healthSetting := true
+	m := &Maintenance{health: target, notifier: target, collector: target}
+	m.healthEnabled = func() bool { return healthSetting }
+
+	st := m.Apply(true)
+	assert.True(t, st.Enabled)
+	assert.NotEmpty(t, st.Since)
+	assert.True(t, MaintenanceActive())
+	assert.Equal(t, fakeMaintenanceTarget{health: false, muted: true, paused: true}, *target)
+
+	// turning maintenance off restores health checks from their own setting
+	st = m.Apply(false)
+	assert.False(t, st.Enabled)
+	assert.Empty(t, st.Since)
+	assert.False(t, MaintenanceActive())
+	assert.Equal(t, fakeMaintenanceTarget{health: true}, *target)
+
+	healthSetting = false
+	m.Apply(true)
+	m.Apply(false)
+	assert.False(t, target.health)
+}
+
+func TestGWCollector_PausedSkipsRecording(t *testing.T) {
+	c := NewGWCollector(nil, nil, nil, 30)
+	c.SetPaused(true)
+	assert.True(t, c.Paused())
+	// poll would dereference the nil client if it did not return early
+	c.poll()
+	c.SetPaused(false)
+	assert.False(t, c.Paused())
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+	"openclawdeck/internal/security"
+)
+
+// 设置项：配对请求通知与过期
+const (
+	PairingNotifyEnabledSetting = "pairing_notify_enabled"
+	PairingRequestTTLSetting    = "pairing_request_ttl_minutes" // 超过该时长的配对请求视为过期并隐藏
+)
+
+// DefaultPairingRequestTTL OpenClaw 配对码默认 1 小时过期
+const DefaultPairingRequestTTL = time.Hour
+
+// pairingWatchInterval 定期检查间隔
+const pairingWatchInterval = time.Minute
+
+// PairingWatcher 定期列出各 pairing 频道的待处理配对请求，出现新请求时发送通知；
+// 同一请求只通知一次，请求消失后从记录中移除
+type PairingWatcher struct {
+	settingRepo *database.SettingRepo
+	notifier    security.Notifier
+	stopCh      chan struct{}
+
+	// channels / list 便于测试替换
+	channels func() []string
+	list     func(channel string) (*openclaw.PairingListResult, error)
+
+	mu   sync.Mutex
+	seen map[string]bool // channel + "/" + code
+}
+
+// NewPairingWatcher 创建配对请求监视器
+func NewPairingWatcher() *PairingWatcher {
+	return &PairingWatcher{
+		settingRepo: database.NewSettingRepo(),
+		stopCh:      make(chan struct{}),
+		channels:    openclaw.PairingChannels,
+		list:        openclaw.PairingList,
+		seen:        map[string]bool{},
+	}
+}
+
+// SetNotifier 注入外部通知发送器
+func (w *PairingWatcher) SetNotifier(n security.Notifier) {
+	w.notifier = n
+}
+
+// Start 定期检查，直到 Stop
+func (w *PairingWatcher) Start() {
+	ticker := time.NewTicker(pairingWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if w.settingRepo.GetBool(PairingNotifyEnabledSetting) && openclaw.IsOpenClawInstalled() {
+				w.Check(w.settingRepo.GetDuration(PairingRequestTTLSetting), time.Now())
+			}
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止定期检查
+func (w *PairingWatcher) Stop() {
+	select {
+	case <-w.stopCh:
+	default:
+		close(w.stopCh)
+	}
+}
+
+// Check 检查所有 pairing 频道，对未通知过的未过期请求发送通知，返回新请求数
+func (w *PairingWatcher) Check(ttl time.Duration, now time.Time) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current := map[string]bool{}
+	var fresh []string
+	for _, channel := range w.channels() {
+		result, err := w.list(channel)
+		if err != nil {
+			logger.Monitor.Debug().Err(err).Str("channel", channel).Msg("列出配对请求失败")
+			// 查询失败时保留该频道已通知的记录，避免恢复后重复通知
+			for key := range w.seen {
+				if strings.HasPrefix(key, channel+"/") {
+					current[key] = true
+				}
+			}
+			continue
+		}
+		for _, req := range openclaw.ActivePairingRequests(result.Requests, ttl, now) {
+			key := channel + "/" + req.Code
+			current[key] = true
+			if !w.seen[key] {
+				fresh = append(fresh, pairingRequestLine(channel, req))
+			}
+		}
+	}
+	w.seen = current
+
+	if len(fresh) > 0 {
+		logger.Monitor.Info().Int("count", len(fresh)).Msg("发现新的配对请求")
+		if w.notifier != nil {
+			go w.notifier.SendAlert("medium", fmt.Sprintf("有 %d 个新的配对请求待批准", len(fresh)), strings.Join(fresh, "\n"))
+		}
+	}
+	return len(fresh)
+}
+
+// pairingRequestLine 通知中的单行描述
+func pairingRequestLine(channel string, req openclaw.PairingRequest) string {
+	line := fmt.Sprintf("%s: %s", channel, req.Code)
+	if name := req.Meta["username"]; name != "" {
+		line += " (" + name + ")"
+	} else if req.ID != "" {
+		line += " (" + req.ID + ")"
+	}
+	return line
+}
@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"openclawdeck/internal/openclaw"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairingWatcher_Dedup(t *testing.T) {
+	now := time.Now()
+	pending := map[string][]openclaw.PairingRequest{
+		"telegram": {{Code: "AAA", CreatedAt: now.Format(time.RFC3339)}},
+	}
+	var listErr error
+	w := &PairingWatcher{
+		channels: func() []string { return []string{"telegram"} },
+		list: func(channel string) (*openclaw.PairingListResult, error) {
+			if listErr != nil {
+				return nil, listErr
+			}
+			return &openclaw.PairingListResult{Channel: channel, Requests: pending[channel]}, nil
+		},
+		seen: map[string]bool{},
+	}
+
+	assert.Equal(t, 1, w.Check(time.Hour, now))
+	assert.Equal(t, 0, w.Check(time.Hour, now), "same request is not notified twice")
+
+	// 查询失败不丢记录
+	listErr = errors.New("cli timeout")
+	assert.Equal(t, 0, w.Check(time.Hour, now))
+	listErr = nil
+	assert.Equal(t, 0, w.Check(time.Hour, now))
+
+	pending["telegram"] = append(pending["telegram"],
+		openclaw.PairingRequest{Code: "BBB", CreatedAt: now.Format(time.RFC3339)},
+		openclaw.PairingRequest{Code: "OLD", CreatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+	)
+	assert.Equal(t, 1, w.Check(time.Hour, now), "only the new unexpired request")
+}
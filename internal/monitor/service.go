@@ -15,7 +15,7 @@ type Service struct {
 	activityRepo *database.ActivityRepo
 	wsHub        *web.WSHub
 	engine       *security.Engine
-	interval     time.Duration
+	interval     *pollInterval
 	stopCh       chan struct{}
 	running      bool
 }
@@ -26,7 +26,7 @@ func NewService(openclawDir string, wsHub *web.WSHub, engine *security.Engine, i
 		activityRepo: database.NewActivityRepo(),
 		wsHub:        wsHub,
 		engine:       engine,
-		interval:     time.Duration(intervalSec) * time.Second,
+		interval:     newPollInterval(intervalSec),
 		stopCh:       make(chan struct{}),
 	}
 }
@@ -40,19 +40,22 @@ func (s *Service) IsRunning() bool {
 func (s *Service) Start() {
 	s.running = true
 	logger.Monitor.Info().
-		Dur("interval", s.interval).
+		Dur("interval", s.interval.get()).
 		Msg("监控服务已启动")
 
 	// 首次立即扫描
 	s.scan()
 
-	ticker := time.NewTicker(s.interval)
+	ticker := time.NewTicker(s.interval.get())
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
 			s.scan()
+		case d := <-s.interval.ch:
+			ticker.Reset(d)
+			logger.Monitor.Info().Dur("interval", d).Msg("监控服务轮询间隔已更新")
 		case <-s.stopCh:
 			s.running = false
 			logger.Monitor.Info().Msg("监控服务已停止")
@@ -61,6 +64,11 @@ func (s *Service) Start() {
 	}
 }
 
+// SetInterval 运行时修改扫描间隔（秒），无需重启服务
+func (s *Service) SetInterval(sec int) time.Duration {
+	return s.interval.set(sec)
+}
+
 // Stop 停止监控循环
 func (s *Service) Stop() {
 	if s.running {
@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"strconv"
+	"time"
+
+	"openclawdeck/internal/database"
+)
+
+// 本包读取的设置项定义
+func init() {
+	database.RegisterSettings(
+		database.SettingDef{
+			Key:         IdleResetEnabledSetting,
+			Type:        database.SettingBool,
+			Default:     "false",
+			Description: "reset gateway sessions that have been idle for too long",
+		},
+		database.SettingDef{
+			Key:         IdleResetHoursSetting,
+			Type:        database.SettingDuration,
+			Unit:        time.Hour,
+			Default:     strconv.Itoa(DefaultIdleResetHours),
+			Min:         int64(time.Hour),
+			Description: "idle time before a session is reset (hours)",
+		},
+		database.SettingDef{
+			Key:         LoginSpikeEnabledSetting,
+			Type:        database.SettingBool,
+			Default:     "true",
+			Description: "alert on a spike of failed logins",
+		},
+		database.SettingDef{
+			Key:         LoginSpikeThresholdSetting,
+			Type:        database.SettingInt,
+			Default:     strconv.Itoa(DefaultLoginSpikeThreshold),
+			Min:         1,
+			Description: "failed logins within the window that raise an alert",
+		},
+		database.SettingDef{
+			Key:         LoginSpikeWindowSetting,
+			Type:        database.SettingDuration,
+			Unit:        time.Minute,
+			Default:     strconv.Itoa(int(DefaultLoginSpikeWindow / time.Minute)),
+			Min:         int64(time.Minute),
+			Description: "failed-login counting window (minutes)",
+		},
+		database.SettingDef{
+			Key:         LoginSpikeCooldownSetting,
+			Type:        database.SettingDuration,
+			Unit:        time.Minute,
+			Default:     strconv.Itoa(int(DefaultLoginSpikeCooldown / time.Minute)),
+			Description: "minimum time between two failed-login spike alerts (minutes, 0 = no limit)",
+		},
+		database.SettingDef{
+			Key:         DBVacuumEnabledSetting,
+			Type:        database.SettingBool,
+			Default:     "true",
+			Description: "periodically reclaim free space in the SQLite database",
+		},
+		database.SettingDef{
+			Key:         TokenAutoSyncSetting,
+			Type:        database.SettingBool,
+			Default:     "false",
+			Description: "copy a rotated openclaw.json token into the active gateway profile",
+		},
+		database.SettingDef{
+			Key:         PairingNotifyEnabledSetting,
+			Type:        database.SettingBool,
+			Default:     "true",
+			Description: "notify when a new channel pairing request is pending",
+		},
+		database.SettingDef{
+			Key:         PairingRequestTTLSetting,
+			Type:        database.SettingDuration,
+			Unit:        time.Minute,
+			Default:     strconv.Itoa(int(DefaultPairingRequestTTL / time.Minute)),
+			Min:         int64(time.Minute),
+			Description: "age after which pending pairing requests are hidden (minutes)",
+		},
+		database.SettingDef{
+			Key:         MaintenanceModeSetting,
+			Type:        database.SettingBool,
+			Default:     "false",
+			Description: "pause health-check restarts, alerts, notifications and event collection",
+		},
+		database.SettingDef{
+			Key:         OutboundWebhookURLSetting,
+			Type:        database.SettingString,
+			Description: "POST each new alert (and optionally high-risk activity) as JSON to this URL",
+			Validate:    validWebhookURL,
+		},
+		database.SettingDef{
+			Key:         OutboundWebhookSecretSetting,
+			Type:        database.SettingString,
+			Description: "HMAC secret for the X-Deck-Signature header of outbound webhooks",
+		},
+		database.SettingDef{
+			Key:         OutboundWebhookEventsSetting,
+			Type:        database.SettingString,
+			Default:     OutboundEventAlert,
+			Description: "outbound webhook events: alert, activity (high/critical only)",
+			Validate:    validOutboundEvents,
+		},
+	)
+}
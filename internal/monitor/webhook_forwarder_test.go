@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"openclawdeck/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookForwarder_SignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	got := make(chan *http.Request, 1)
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		got <- r
+	}))
+	defer srv.Close()
+
+	f := NewWebhookForwarder()
+	f.loadConfig = func() webhookConfig {
+		return webhookConfig{URL: srv.URL, Secret: "s3cret", Alerts: true}
+	}
+	f.backoff = func(int) time.Duration { return time.Millisecond }
+	go f.Start()
+	defer f.Stop()
+
+	assert.False(t, f.Enqueue(OutboundEventActivity, map[string]string{}), "activity is not subscribed")
+	require.True(t, f.Enqueue(OutboundEventAlert, &database.Alert{AlertID: "alert_1", Risk: "high", Message: "boom"}))
+
+	select {
+	case r := <-got:
+		assert.EqualValues(t, 2, calls.Load(), "first 502 should be retried")
+		assert.Equal(t, "alert", r.Header.Get("X-Deck-Event"))
+		ts := r.Header.Get("X-Deck-Timestamp")
+		assert.Equal(t, signWebhookBody("s3cret", ts, body), r.Header.Get("X-Deck-Signature"))
+		var p struct {
+			Event string         `json:"event"`
+			Data  database.Alert `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &p))
+		assert.Equal(t, "alert_1", p.Data.AlertID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+}
+
+func TestWebhookForwarder_QueueFullDoesNotBlock(t *testing.T) {
+	f := NewWebhookForwarder()
+	f.loadConfig = func() webhookConfig { return webhookConfig{URL: "http://127.0.0.1:1", Alerts: true} }
+	// not started: the queue fills up and further events are dropped
+	for i := 0; i < outboundQueueSize; i++ {
+		require.True(t, f.Enqueue(OutboundEventAlert, i))
+	}
+	assert.False(t, f.Enqueue(OutboundEventAlert, "overflow"))
+	assert.EqualValues(t, 1, f.dropped.Load())
+}
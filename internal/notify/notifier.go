@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"openclawdeck/internal/database"
 	"openclawdeck/internal/logger"
@@ -25,12 +27,29 @@ type Manager struct {
 	mu           sync.RWMutex
 	notifier     *nfy.Notify
 	channelNames []string
+	muted        atomic.Bool
+
+	// services holds each configured channel by name for routed alerts.
+	services map[string]nfy.Notifier
+	routing  AlertRouting
+
+	// quiet hours: only critical messages go out inside the window
+	quiet        *QuietHours
+	clock        func() time.Time
+	queueMu      sync.Mutex
+	queued       []queuedMessage
+	queueDropped int
+	queueTimer   *time.Timer
+
+	throttleMu sync.Mutex
+	throttles  map[string]*throttleState
 }
 
 // NewManager creates an empty notification manager.
 func NewManager() *Manager {
 	return &Manager{
 		notifier: nfy.New(),
+		clock:    time.Now,
 	}
 }
 
@@ -43,6 +62,12 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 	// Create a fresh notifier instance (drops old services)
 	n := nfy.New()
 	var names []string
+	services := map[string]nfy.Notifier{}
+	use := func(name string, svc nfy.Notifier) {
+		n.UseServices(svc)
+		services[name] = svc
+		names = append(names, name)
+	}
 
 	// ── Telegram (via nikoksr/notify/service/telegram) ──
 	tgToken, _ := settingRepo.Get("notify_telegram_token")
@@ -62,8 +87,7 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 			// AddReceivers accepts int64 chat IDs
 			if id, err := strconv.ParseInt(strings.TrimSpace(tgChatID), 10, 64); err == nil {
 				tgSvc.AddReceivers(id)
-				n.UseServices(tgSvc)
-				names = append(names, "telegram")
+				use("telegram", tgSvc)
 			} else {
 				logger.Log.Warn().Str("chat_id", tgChatID).Msg("Telegram chat ID 格式无效")
 			}
@@ -77,16 +101,14 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 	ddSecret, _ := settingRepo.Get("notify_dingtalk_secret")
 	if ddToken != "" {
 		ddSvc := nfydd.New(&nfydd.Config{Token: ddToken, Secret: ddSecret})
-		n.UseServices(ddSvc)
-		names = append(names, "dingtalk")
+		use("dingtalk", ddSvc)
 	}
 
 	// ── Lark/飞书 (via nikoksr/notify/service/lark webhook) ──
 	larkURL, _ := settingRepo.Get("notify_lark_webhook_url")
 	if larkURL != "" {
 		larkSvc := nfylark.NewWebhookService(larkURL)
-		n.UseServices(larkSvc)
-		names = append(names, "lark")
+		use("lark", larkSvc)
 	}
 
 	// ── Discord (via nikoksr/notify/service/discord) ──
@@ -105,8 +127,7 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 		dcSvc := nfydc.New()
 		if err := dcSvc.AuthenticateWithBotToken(dcToken); err == nil {
 			dcSvc.AddReceivers(strings.TrimSpace(dcChannelID))
-			n.UseServices(dcSvc)
-			names = append(names, "discord")
+			use("discord", dcSvc)
 		} else {
 			logger.Log.Warn().Err(err).Msg("Discord 服务初始化失败")
 		}
@@ -127,8 +148,7 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 	if slackToken != "" && slackChannelID != "" {
 		slackSvc := nfyslack.New(slackToken)
 		slackSvc.AddReceivers(strings.TrimSpace(slackChannelID))
-		n.UseServices(slackSvc)
-		names = append(names, "slack")
+		use("slack", slackSvc)
 	}
 
 	// ── WeCom/企微 (via webhook, using nikoksr/notify/service/http) ──
@@ -146,8 +166,7 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 					escapeJSON(subject), escapeJSON(message))
 			},
 		})
-		n.UseServices(wecomSvc)
-		names = append(names, "wecom")
+		use("wecom", wecomSvc)
 	}
 
 	// ── Webhook (via nikoksr/notify/service/http) ──
@@ -200,31 +219,66 @@ func (m *Manager) Reload(settingRepo *database.SettingRepo, gwChannels map[strin
 			},
 		})
 
-		n.UseServices(httpSvc)
-		names = append(names, "webhook")
+		use("webhook", httpSvc)
+	}
+
+	// ── Alert routing by risk level ──
+	rawRouting, _ := settingRepo.Get(AlertRoutingSetting)
+	routing, err := ParseAlertRouting(rawRouting)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("routing", rawRouting).Msg("告警路由规则无效，告警将发送到全部渠道")
+		routing = nil
+	}
+
+	// ── Quiet hours ──
+	qStart, _ := settingRepo.Get(QuietHoursStartSetting)
+	qEnd, _ := settingRepo.Get(QuietHoursEndSetting)
+	quiet, err := ParseQuietHours(qStart, qEnd, settingRepo.GetString(QuietHoursModeSetting), quietHoursLocation())
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("免打扰时段配置无效，已忽略")
+		quiet = nil
 	}
 
 	m.notifier = n
 	m.channelNames = names
+	m.services = services
+	m.routing = routing
+	m.quiet = quiet
 
-	logger.Log.Info().Int("channels", len(names)).Strs("names", names).Msg("通知渠道已重载 (nikoksr/notify)")
+	logger.Log.Info().Int("channels", len(names)).Strs("names", names).Str("routing", routing.String()).Msg("通知渠道已重载 (nikoksr/notify)")
 }
 
-// Send dispatches a message to all configured channels.
+// SetMuted suppresses (or resumes) all outgoing notifications, e.g. during
+// maintenance mode. Muted messages are dropped, not queued.
+func (m *Manager) SetMuted(muted bool) {
+	m.muted.Store(muted)
+}
+
+// Muted reports whether notifications are currently suppressed.
+func (m *Manager) Muted() bool {
+	return m.muted.Load()
+}
+
+// Send dispatches a message to all configured channels. Outside of critical
+// alerts, messages sent during quiet hours are dropped or queued.
 func (m *Manager) Send(text string) {
-	m.mu.RLock()
-	n := m.notifier
-	m.mu.RUnlock()
+	m.deliver("", text)
+}
 
-	if n == nil {
-		return
-	}
-	if err := n.Send(context.Background(), "OpenClawDeck", text); err != nil {
-		logger.Log.Warn().Err(err).Msg("通知发送失败")
-	}
+// SendRisk dispatches text like an alert of the given risk: routing applies,
+// and "critical" messages are sent even during quiet hours.
+func (m *Manager) SendRisk(risk, text string) {
+	m.deliver(risk, text)
+}
+
+// SendNow dispatches text to all configured channels immediately, ignoring
+// muting and quiet hours. Used for explicit test messages.
+func (m *Manager) SendNow(text string) {
+	m.dispatch("", text)
 }
 
-// SendAlert formats and sends an alert notification.
+// SendAlert formats and sends an alert notification. When the alert routing
+// setting has a rule for risk, only the channels it names receive the alert.
 func (m *Manager) SendAlert(risk, message, detail string) {
 	emoji := "\u26a0\ufe0f"
 	switch risk {
@@ -241,7 +295,48 @@ func (m *Manager) SendAlert(risk, message, detail string) {
 	if detail != "" && len(detail) < 200 {
 		text += "\n" + detail
 	}
-	m.Send(text)
+	m.deliver(risk, text)
+}
+
+// deliver applies muting and quiet hours, then dispatches.
+func (m *Manager) deliver(risk, text string) {
+	if m.muted.Load() {
+		logger.Log.Debug().Msg("通知已静音（维护模式），跳过发送")
+		return
+	}
+	if m.holdForQuietHours(risk, text) {
+		return
+	}
+	m.dispatch(risk, text)
+}
+
+// dispatch sends text to the channels the alert routing picks for risk, or to
+// every channel when risk is empty or has no rule.
+func (m *Manager) dispatch(risk, text string) {
+	m.mu.RLock()
+	n := m.notifier
+	channels, routed := m.routing.Channels(risk, m.channelNames)
+	services := m.services
+	m.mu.RUnlock()
+
+	if !routed {
+		if n == nil {
+			return
+		}
+		if err := n.Send(context.Background(), "OpenClawDeck", text); err != nil {
+			logger.Log.Warn().Err(err).Msg("通知发送失败")
+		}
+		return
+	}
+	if len(channels) == 0 {
+		logger.Log.Debug().Str("risk", risk).Msg("告警路由规则已屏蔽该级别，跳过通知")
+		return
+	}
+	for _, name := range channels {
+		if err := services[name].Send(context.Background(), "OpenClawDeck", text); err != nil {
+			logger.Log.Warn().Err(err).Str("channel", name).Msg("通知发送失败")
+		}
+	}
 }
 
 // HasChannels returns true if at least one channel is configured.
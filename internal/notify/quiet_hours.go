@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/database"
+	"openclawdeck/internal/logger"
+	"openclawdeck/internal/openclaw"
+)
+
+// Quiet hours settings. Start and end are "HH:MM" in the OPENCLAW_TIMEZONE
+// time zone; the window may wrap midnight (22:00–07:00). Either empty disables
+// quiet hours.
+const (
+	QuietHoursStartSetting = "notify_quiet_hours_start"
+	QuietHoursEndSetting   = "notify_quiet_hours_end"
+	QuietHoursModeSetting  = "notify_quiet_hours_mode"
+)
+
+// Quiet hours modes for non-critical messages.
+const (
+	QuietModeDrop  = "drop"
+	QuietModeQueue = "queue"
+)
+
+// maxQueued bounds how many messages are held during quiet hours.
+const maxQueued = 50
+
+func init() {
+	validClock := func(v string) error {
+		_, err := parseClock(v)
+		return err
+	}
+	database.RegisterSettings(
+		database.SettingDef{
+			Key:         QuietHoursStartSetting,
+			Type:        database.SettingString,
+			Description: "start of notification quiet hours (HH:MM, OPENCLAW_TIMEZONE)",
+			Validate:    validClock,
+		},
+		database.SettingDef{
+			Key:         QuietHoursEndSetting,
+			Type:        database.SettingString,
+			Description: "end of notification quiet hours (HH:MM, OPENCLAW_TIMEZONE)",
+			Validate:    validClock,
+		},
+		database.SettingDef{
+			Key:         QuietHoursModeSetting,
+			Type:        database.SettingString,
+			Default:     QuietModeDrop,
+			Enum:        []string{QuietModeDrop, QuietModeQueue},
+			Description: "what to do with non-critical notifications during quiet hours",
+		},
+	)
+}
+
+// QuietHours is a daily window, in minutes after local midnight, during which
+// only critical notifications are sent.
+type QuietHours struct {
+	Start, End int // minutes after midnight
+	Loc        *time.Location
+	Queue      bool // hold messages until the window ends instead of dropping them
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ParseQuietHours builds the quiet hours window. Empty start or end, or an
+// empty window (start == end), returns nil.
+func ParseQuietHours(start, end, mode string, loc *time.Location) (*QuietHours, error) {
+	if strings.TrimSpace(start) == "" || strings.TrimSpace(end) == "" {
+		return nil, nil
+	}
+	s, err := parseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return nil, err
+	}
+	if s == e {
+		return nil, nil
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return &QuietHours{Start: s, End: e, Loc: loc, Queue: mode == QuietModeQueue}, nil
+}
+
+// Contains reports whether t falls in the window; the start is inclusive and
+// the end exclusive.
+func (q *QuietHours) Contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	lt := t.In(q.Loc)
+	min := lt.Hour()*60 + lt.Minute()
+	if q.Start < q.End {
+		return min >= q.Start && min < q.End
+	}
+	// wraps midnight, e.g. 22:00–07:00
+	return min >= q.Start || min < q.End
+}
+
+// Until returns how long after t the current window ends.
+func (q *QuietHours) Until(t time.Time) time.Duration {
+	lt := t.In(q.Loc)
+	end := time.Date(lt.Year(), lt.Month(), lt.Day(), q.End/60, q.End%60, 0, 0, q.Loc)
+	if !end.After(lt) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end.Sub(lt)
+}
+
+// queuedMessage is a message held until quiet hours end.
+type queuedMessage struct {
+	risk, text string
+}
+
+// holdForQuietHours drops or queues a non-critical message during quiet hours
+// and reports whether it was held back.
+func (m *Manager) holdForQuietHours(risk, text string) bool {
+	if risk == "critical" {
+		return false
+	}
+	m.mu.RLock()
+	q := m.quiet
+	m.mu.RUnlock()
+	now := m.clock()
+	if !q.Contains(now) {
+		return false
+	}
+	if !q.Queue {
+		logger.Log.Debug().Str("risk", risk).Msg("免打扰时段，丢弃非紧急通知")
+		return true
+	}
+
+	m.queueMu.Lock()
+	defer m.queueMu.Unlock()
+	if len(m.queued) >= maxQueued {
+		m.queueDropped++
+		return true
+	}
+	m.queued = append(m.queued, queuedMessage{risk: risk, text: text})
+	if m.queueTimer == nil {
+		m.queueTimer = time.AfterFunc(q.Until(now), m.flushQueued)
+	}
+	return true
+}
+
+// flushQueued sends the messages held during quiet hours.
+func (m *Manager) flushQueued() {
+	m.queueMu.Lock()
+	queued, dropped := m.queued, m.queueDropped
+	m.queued, m.queueDropped, m.queueTimer = nil, 0, nil
+	m.queueMu.Unlock()
+
+	if len(queued) == 0 || m.muted.Load() {
+		return
+	}
+	logger.Log.Info().Int("queued", len(queued)).Int("dropped", dropped).Msg("免打扰时段结束，发送暂存通知")
+	for _, msg := range queued {
+		m.dispatch(msg.risk, msg.text)
+	}
+	if dropped > 0 {
+		m.dispatch("", fmt.Sprintf("(%d more notifications were dropped during quiet hours)", dropped))
+	}
+}
+
+// quietHoursLocation returns the OPENCLAW_TIMEZONE location, read from the
+// process environment or the OpenClaw env file, falling back to local time.
+func quietHoursLocation() *time.Location {
+	tz := strings.TrimSpace(os.Getenv("OPENCLAW_TIMEZONE"))
+	if tz == "" {
+		if dir := openclaw.ResolveStateDir(); dir != "" {
+			if values, err := openclaw.ReadEnvFile(filepath.Join(dir, "env")); err == nil {
+				tz = strings.TrimSpace(values["OPENCLAW_TIMEZONE"])
+			}
+		}
+	}
+	if tz == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("timezone", tz).Msg("OPENCLAW_TIMEZONE 无效，免打扰时段使用本地时区")
+		return time.Local
+	}
+	return loc
+}
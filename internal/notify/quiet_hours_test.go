@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	nfy "github.com/nikoksr/notify"
+)
+
+func TestQuietHours_WrapsMidnight(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	q, err := ParseQuietHours("22:00", "07:00", QuietModeDrop, loc)
+	if err != nil || q == nil {
+		t.Fatalf("parse: %v", err)
+	}
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 10, h, m, 0, 0, loc) }
+	cases := []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(21, 59), false},
+		{at(22, 0), true},
+		{at(23, 59), true},
+		{at(0, 0), true},
+		{at(6, 59), true},
+		{at(7, 0), false},
+		{at(12, 0), false},
+		// the window is evaluated in its own zone: 14:30 UTC is 22:30 UTC+8
+		{time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC), true},
+	}
+	for _, c := range cases {
+		if got := q.Contains(c.t); got != c.want {
+			t.Errorf("Contains(%s) = %v, want %v", c.t.In(loc).Format("15:04"), got, c.want)
+		}
+	}
+	if d := q.Until(at(23, 0)); d != 8*time.Hour {
+		t.Errorf("Until(23:00) = %s, want 8h", d)
+	}
+
+	if q, _ := ParseQuietHours("", "07:00", "", loc); q != nil {
+		t.Error("empty start should disable quiet hours")
+	}
+	if _, err := ParseQuietHours("25:00", "07:00", "", loc); err == nil {
+		t.Error("invalid time should be rejected")
+	}
+}
+
+func TestSend_QuietHours(t *testing.T) {
+	tg := &recordingService{}
+	m := NewManager()
+	m.channelNames = []string{"telegram"}
+	m.services = map[string]nfy.Notifier{"telegram": tg}
+	m.routing, _ = ParseAlertRouting("critical,high,medium,low=telegram")
+	m.quiet, _ = ParseQuietHours("22:00", "07:00", QuietModeQueue, time.UTC)
+	now := time.Date(2026, 3, 10, 6, 59, 0, 0, time.UTC)
+	m.clock = func() time.Time { return now }
+
+	m.SendAlert("high", "held until morning", "")
+	m.SendAlert("critical", "gateway down", "")
+	if len(tg.sent) != 1 {
+		t.Fatalf("only the critical alert should go out during quiet hours, got %d", len(tg.sent))
+	}
+	m.queueTimer.Stop()
+	m.flushQueued()
+	if len(tg.sent) != 2 {
+		t.Fatalf("queued alert should be sent when quiet hours end, got %d", len(tg.sent))
+	}
+
+	now = now.Add(time.Minute) // 07:00, window closed
+	m.SendAlert("low", "daytime", "")
+	if len(tg.sent) != 3 {
+		t.Fatal("alerts outside quiet hours should be sent immediately")
+	}
+}
+
+func TestSendRisk_CriticalDuringQuietHours(t *testing.T) {
+	tg := &recordingService{}
+	m := NewManager()
+	m.channelNames = []string{"telegram"}
+	m.services = map[string]nfy.Notifier{"telegram": tg}
+	m.routing, _ = ParseAlertRouting("critical,high,medium,low=telegram")
+	m.quiet, _ = ParseQuietHours("22:00", "07:00", QuietModeDrop, time.UTC)
+	m.clock = func() time.Time { return time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC) }
+
+	// gateway heartbeat restart failure (GWClient notify callback) and account lockout
+	m.SendRisk("critical", "OpenClaw Gateway 心跳检测失败，自动重启也失败: port in use")
+	m.SendThrottled("account_locked", time.Minute, "critical", "Account locked")
+	m.SendRisk("medium", "OpenClaw Gateway 心跳检测失败，已自动重启成功")
+	m.Send("plain message")
+
+	if len(tg.sent) != 2 {
+		t.Fatalf("critical messages must get through quiet hours, sent %q", tg.sent)
+	}
+}
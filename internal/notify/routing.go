@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openclawdeck/internal/database"
+)
+
+// AlertRoutingSetting routes alert notifications to channels by risk level,
+// e.g. "critical,high=telegram;medium=webhook;low=none". Empty sends every
+// alert to every configured channel.
+const AlertRoutingSetting = "notify_alert_routing"
+
+func init() {
+	database.RegisterSettings(database.SettingDef{
+		Key:         AlertRoutingSetting,
+		Type:        database.SettingString,
+		Description: "route alerts by risk: critical,high=telegram;medium=webhook;low=none",
+		Validate: func(v string) error {
+			_, err := ParseAlertRouting(v)
+			return err
+		},
+	})
+}
+
+// routeNone is the channel name that suppresses a risk level.
+const routeNone = "none"
+
+// alertRisks are the risk levels a routing rule may name.
+var alertRisks = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// routableChannels are the channel names Reload can configure.
+var routableChannels = map[string]bool{
+	"telegram": true, "dingtalk": true, "lark": true, "discord": true,
+	"slack": true, "wecom": true, "webhook": true,
+}
+
+// AlertRouting maps a risk level to the channels its alerts go to. Levels
+// without a rule go to every configured channel; a level routed to "none"
+// (an empty list) is suppressed.
+type AlertRouting map[string][]string
+
+// ParseAlertRouting parses rules of the form "risk[,risk]=channel[,channel]"
+// separated by ";" or newlines.
+func ParseAlertRouting(s string) (AlertRouting, error) {
+	routing := AlertRouting{}
+	for _, rule := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == '\n' }) {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		lhs, rhs, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("rule %q: expected risk=channel", rule)
+		}
+		channels := []string{}
+		for _, ch := range splitList(rhs) {
+			if ch == routeNone {
+				continue
+			}
+			if !routableChannels[ch] {
+				return nil, fmt.Errorf("rule %q: unknown channel %q", rule, ch)
+			}
+			channels = append(channels, ch)
+		}
+		risks := splitList(lhs)
+		if len(risks) == 0 {
+			return nil, fmt.Errorf("rule %q: missing risk level", rule)
+		}
+		for _, risk := range risks {
+			if !alertRisks[risk] {
+				return nil, fmt.Errorf("rule %q: unknown risk %q", rule, risk)
+			}
+			if _, dup := routing[risk]; dup {
+				return nil, fmt.Errorf("risk %q is routed twice", risk)
+			}
+			routing[risk] = channels
+		}
+	}
+	return routing, nil
+}
+
+// Channels returns the configured channels an alert of the given risk goes
+// to, in the order of active. ok is false when no rule covers the risk.
+func (r AlertRouting) Channels(risk string, active []string) (channels []string, ok bool) {
+	routed, ok := r[strings.ToLower(risk)]
+	if !ok {
+		return active, false
+	}
+	want := map[string]bool{}
+	for _, ch := range routed {
+		want[ch] = true
+	}
+	channels = []string{}
+	for _, ch := range active {
+		if want[ch] {
+			channels = append(channels, ch)
+		}
+	}
+	return channels, true
+}
+
+// String formats the routing in the setting's syntax, one rule per risk.
+func (r AlertRouting) String() string {
+	risks := make([]string, 0, len(r))
+	for risk := range r {
+		risks = append(risks, risk)
+	}
+	sort.Strings(risks)
+	rules := make([]string, 0, len(risks))
+	for _, risk := range risks {
+		chs := strings.Join(r[risk], ",")
+		if chs == "" {
+			chs = routeNone
+		}
+		rules = append(rules, risk+"="+chs)
+	}
+	return strings.Join(rules, ";")
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
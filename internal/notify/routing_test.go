@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	nfy "github.com/nikoksr/notify"
+)
+
+type recordingService struct{ sent []string }
+
+func (s *recordingService) Send(_ context.Context, _, message string) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestParseAlertRouting(t *testing.T) {
+	r, err := ParseAlertRouting("critical, high = telegram ;medium=webhook\nlow=none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.String(); got != "critical=telegram;high=telegram;low=none;medium=webhook" {
+		t.Fatalf("unexpected routing %q", got)
+	}
+	for _, bad := range []string{"high", "urgent=telegram", "high=pager", "high=telegram;high=webhook", "=telegram"} {
+		if _, err := ParseAlertRouting(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestSendAlert_Routing(t *testing.T) {
+	tg, wh := &recordingService{}, &recordingService{}
+	m := NewManager()
+	m.channelNames = []string{"telegram", "webhook"}
+	m.services = map[string]nfy.Notifier{"telegram": tg, "webhook": wh}
+	m.routing, _ = ParseAlertRouting("critical,high=telegram;medium=webhook;low=none")
+
+	m.SendAlert("low", "noisy", "")
+	if len(tg.sent)+len(wh.sent) != 0 {
+		t.Fatal("low alerts should be suppressed")
+	}
+	m.SendAlert("medium", "disk filling up", "")
+	m.SendAlert("critical", "gateway down", "")
+	if len(wh.sent) != 1 || len(tg.sent) != 1 {
+		t.Fatalf("expected one alert per channel, got telegram=%d webhook=%d", len(tg.sent), len(wh.sent))
+	}
+
+	m.SetMuted(true)
+	m.SendAlert("critical", "muted", "")
+	if len(tg.sent) != 1 {
+		t.Fatal("muted manager should not send routed alerts")
+	}
+}
+
+type failingService struct{}
+
+func (failingService) Send(context.Context, string, string) error {
+	return errors.New("webhook: unexpected status code: 502")
+}
+
+func TestTestAll_IsolatesFailures(t *testing.T) {
+	tg := &recordingService{}
+	m := NewManager()
+	m.channelNames = []string{"webhook", "telegram"}
+	m.services = map[string]nfy.Notifier{"webhook": failingService{}, "telegram": tg}
+	m.SetMuted(true)
+
+	res := m.TestAll(context.Background(), "ping")
+	if len(res) != 2 || res[0].Channel != "webhook" || res[1].Channel != "telegram" {
+		t.Fatalf("unexpected results %+v", res)
+	}
+	if res[0].OK || res[0].Status != 502 || res[0].Error == "" {
+		t.Errorf("webhook should fail with status 502, got %+v", res[0])
+	}
+	if !res[1].OK || len(tg.sent) != 1 {
+		t.Errorf("telegram should still be tested, got %+v", res[1])
+	}
+}
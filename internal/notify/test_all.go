@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// testTimeout bounds each channel's test send.
+const testTimeout = 15 * time.Second
+
+// ChannelResult is the outcome of a test send on one channel.
+type ChannelResult struct {
+	Channel   string `json:"channel"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	Status    int    `json:"status,omitempty"` // upstream HTTP status, when the error reports one
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// TestAll sends text through every configured channel in parallel and reports
+// each channel separately; a failing channel does not stop the others. Test
+// sends ignore alert routing and muting, since they are explicit admin actions.
+func (m *Manager) TestAll(ctx context.Context, text string) []ChannelResult {
+	m.mu.RLock()
+	names := append([]string(nil), m.channelNames...)
+	services := m.services
+	m.mu.RUnlock()
+
+	results := make([]ChannelResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			res := ChannelResult{Channel: name}
+			cctx, cancel := context.WithTimeout(ctx, testTimeout)
+			defer cancel()
+			start := time.Now()
+			err := services[name].Send(cctx, "OpenClawDeck", text)
+			res.LatencyMs = time.Since(start).Milliseconds()
+			if err != nil {
+				res.Error = err.Error()
+				res.Status = statusFromError(err)
+			} else {
+				res.OK = true
+			}
+			results[i] = res
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// statusPattern finds an HTTP status code in upstream error messages such as
+// "unexpected status code: 404" or "status 401 Unauthorized".
+var statusPattern = regexp.MustCompile(`(?i)status(?:\s*code)?\s*[:=]?\s*([1-5]\d\d)\b`)
+
+// statusFromError extracts the upstream HTTP status from err, or 0.
+func statusFromError(err error) int {
+	if m := statusPattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+)
+
+// throttleState tracks one throttle key.
+type throttleState struct {
+	last       time.Time
+	suppressed int
+}
+
+// SendThrottled sends text unless a message with the same key was sent within
+// every. Suppressed messages are counted and reported with the next one that
+// goes out, so bursts (e.g. a brute-force attempt) produce one notification
+// per window instead of a flood. risk is handled as in SendRisk. Returns
+// whether the message was sent.
+func (m *Manager) SendThrottled(key string, every time.Duration, risk, text string) bool {
+	if !m.allow(key, every, time.Now(), &text) {
+		return false
+	}
+	m.deliver(risk, text)
+	return true
+}
+
+// allow applies the throttle for key at now; when allowed, the suppressed count
+// since the last message is appended to text.
+func (m *Manager) allow(key string, every time.Duration, now time.Time, text *string) bool {
+	m.throttleMu.Lock()
+	defer m.throttleMu.Unlock()
+	if m.throttles == nil {
+		m.throttles = map[string]*throttleState{}
+	}
+	st := m.throttles[key]
+	if st == nil {
+		st = &throttleState{}
+		m.throttles[key] = st
+	}
+	if !st.last.IsZero() && now.Sub(st.last) < every {
+		st.suppressed++
+		return false
+	}
+	if st.suppressed > 0 {
+		*text += fmt.Sprintf("\n(%d similar notifications suppressed in the last %s)", st.suppressed, every)
+	}
+	st.last = now
+	st.suppressed = 0
+	return true
+}
@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerAllow(t *testing.T) {
+	m := NewManager()
+	now := time.Now()
+	text := "first"
+	if !m.allow("k", time.Minute, now, &text) || text != "first" {
+		t.Fatalf("first message should pass unchanged, got %q", text)
+	}
+	for i := 0; i < 3; i++ {
+		text = "burst"
+		if m.allow("k", time.Minute, now.Add(time.Second), &text) {
+			t.Fatal("message within the window should be suppressed")
+		}
+	}
+	text = "other"
+	if !m.allow("other", time.Minute, now.Add(time.Second), &text) {
+		t.Fatal("keys are throttled independently")
+	}
+	text = "later"
+	if !m.allow("k", time.Minute, now.Add(2*time.Minute), &text) {
+		t.Fatal("message after the window should pass")
+	}
+	if !strings.Contains(text, "3 similar notifications suppressed") {
+		t.Fatalf("suppressed count missing: %q", text)
+	}
+}
@@ -7,18 +7,51 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
+
+	"openclawdeck/internal/logger"
+)
+
+var (
+	resolvedMu   sync.Mutex
+	resolvedPath string
 )
 
 // ResolveOpenClawCmd 查找可用的 openclaw 命令（优先 openclaw，其次 openclaw-cn）
+// 选中的二进制路径发生变化时记录日志，便于排查多个安装并存时实际控制的是哪一个
 func ResolveOpenClawCmd() string {
-	if _, err := exec.LookPath("openclaw"); err == nil {
-		return "openclaw"
+	openclawPath, _ := exec.LookPath("openclaw")
+	cnPath, _ := exec.LookPath("openclaw-cn")
+
+	cmd, path, other := "", "", ""
+	switch {
+	case openclawPath != "":
+		cmd, path, other = "openclaw", openclawPath, cnPath
+	case cnPath != "":
+		cmd, path = "openclaw-cn", cnPath
+	}
+	logResolvedCmd(cmd, path, other)
+	return cmd
+}
+
+// logResolvedCmd 仅在选中路径变化时输出日志，避免每次 CLI 调用都刷屏
+func logResolvedCmd(cmd, path, other string) {
+	resolvedMu.Lock()
+	defer resolvedMu.Unlock()
+	if path == resolvedPath {
+		return
+	}
+	resolvedPath = path
+	if cmd == "" {
+		logger.Log.Warn().Msg("openclaw CLI not found in PATH")
+		return
 	}
-	if _, err := exec.LookPath("openclaw-cn"); err == nil {
-		return "openclaw-cn"
+	ev := logger.Log.Info().Str("cmd", cmd).Str("path", path)
+	if other != "" {
+		ev = ev.Str("ignored", other)
 	}
-	return ""
+	ev.Msg("resolved openclaw CLI")
 }
 
 // IsOpenClawInstalled 检测 openclaw 是否已安装
@@ -135,43 +168,6 @@ func ConfigApplyFull(config map[string]interface{}) error {
 	return nil
 }
 
-// InitDefaultConfig 使用 openclaw CLI 安全地初始化默认配置
-// 优先使用 onboard --non-interactive，降级为 config set 逐键写入
-func InitDefaultConfig() (string, error) {
-	cmd := ResolveOpenClawCmd()
-	if cmd == "" {
-		return "", fmt.Errorf("openclaw 未安装，无法生成配置")
-	}
-
-	// 方案1：尝试 onboard --non-interactive
-	output, err := OnboardNonInteractive(OnboardOptions{
-		GatewayPort: 18789,
-		GatewayBind: "loopback",
-		GatewayAuth: "token",
-		SkipHealth:  true,
-		JSON:        true,
-	})
-	if err == nil {
-		return output, nil
-	}
-
-	// 方案2：降级为 config set 逐键写入
-	// 先确保配置目录和空文件存在（writeConfigFile 会自动创建）
-	pairs := map[string]string{
-		"gateway.mode": `"local"`,
-		"gateway.bind": `"loopback"`,
-		"gateway.port": "18789",
-	}
-
-	for key, value := range pairs {
-		if setErr := ConfigSet(key, value); setErr != nil {
-			return "", fmt.Errorf("config set 降级也失败: onboard 错误: %v, config set 错误: %w", err, setErr)
-		}
-	}
-
-	return "默认配置已通过 config set 生成", nil
-}
-
 // DetectOpenClawBinary 检测 openclaw 二进制文件信息
 func DetectOpenClawBinary() (cmd string, version string, installed bool) {
 	cmd = ResolveOpenClawCmd()
@@ -216,6 +212,7 @@ type PairingRequest struct {
 	CreatedAt  string            `json:"createdAt"`
 	LastSeenAt string            `json:"lastSeenAt"`
 	Meta       map[string]string `json:"meta,omitempty"`
+	AgeSeconds int64             `json:"ageSeconds,omitempty"` // 由 ActivePairingRequests 计算，CreatedAt 无法解析时为 0
 }
 
 // PairingListResult 配对列表结果
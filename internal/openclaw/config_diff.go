@@ -0,0 +1,58 @@
+package openclaw
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ConfigDiff 配置中一处叶子变化；Old 为 nil 表示新增，New 为 nil 表示删除
+type ConfigDiff struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// DiffConfig 比较两份配置，返回按路径排序的叶子变化。
+// 两侧都是对象时逐层比较，数组、标量及一侧缺失的对象整体比较
+func DiffConfig(prev, next map[string]interface{}) []ConfigDiff {
+	var out []ConfigDiff
+	diffConfigMaps("", prev, next, false, &out)
+	return out
+}
+
+// DiffConfigLeaves 与 DiffConfig 相同，但只有一侧是非空对象时也展开到叶子，
+// 每个叶子单独报告（供逐项对比基线的场景使用）
+func DiffConfigLeaves(prev, next map[string]interface{}) []ConfigDiff {
+	var out []ConfigDiff
+	diffConfigMaps("", prev, next, true, &out)
+	return out
+}
+
+func diffConfigMaps(prefix string, prev, next map[string]interface{}, expand bool, out *[]ConfigDiff) {
+	keys := make([]string, 0, len(prev)+len(next))
+	for k := range prev {
+		keys = append(keys, k)
+	}
+	for k := range next {
+		if _, ok := prev[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		pv, nv := prev[k], next[k]
+		pm, pok := pv.(map[string]interface{})
+		nm, nok := nv.(map[string]interface{})
+		if (pok && nok) || (expand && (len(pm) > 0 || len(nm) > 0)) {
+			diffConfigMaps(path, pm, nm, expand, out)
+			continue
+		}
+		if !reflect.DeepEqual(pv, nv) {
+			*out = append(*out, ConfigDiff{Path: path, Old: pv, New: nv})
+		}
+	}
+}
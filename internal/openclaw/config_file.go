@@ -0,0 +1,61 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// UpdateConfigFile 读取 openclaw.json，由 fn 原地修改后先备份再原子写回（临时文件 + rename），返回备份路径。
+// 用于网关未连接时直接修改本地配置
+func UpdateConfigFile(path string, fn func(raw map[string]any) error) (backup string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", err
+	}
+	if raw == nil {
+		raw = map[string]any{}
+	}
+	if err := fn(raw); err != nil {
+		return "", err
+	}
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if backup, err = BackupConfigFile(path); err != nil {
+		return "", err
+	}
+	return backup, WriteFileAtomic(path, append(out, '\n'))
+}
+
+// WriteFileAtomic 原子写入文件（权限 0600）：在同目录创建唯一临时文件，写入并落盘后 rename 覆盖，
+// 读者只会看到旧内容或完整的新内容；目录不存在时以 0700 创建
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	// CreateTemp 创建的文件权限为 0600，且名称唯一，并发写入互不覆盖临时文件
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
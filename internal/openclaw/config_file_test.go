@@ -0,0 +1,59 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("OPENCLAW_STATE_DIR", dir)
+	path := filepath.Join(dir, "openclaw.json")
+	orig := []byte(`{"skills":{"entries":{"weather":{"enabled":true}}}}`)
+	require.NoError(t, os.WriteFile(path, orig, 0o600))
+
+	backup, err := UpdateConfigFile(path, func(raw map[string]any) error {
+		raw["skills"].(map[string]any)["entries"].(map[string]any)["weather"].(map[string]any)["enabled"] = false
+		return nil
+	})
+	require.NoError(t, err)
+
+	saved, err := os.ReadFile(backup)
+	require.NoError(t, err)
+	assert.Equal(t, orig, saved)
+
+	var cfg map[string]any
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, false, cfg["skills"].(map[string]any)["entries"].(map[string]any)["weather"].(map[string]any)["enabled"])
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	assert.Empty(t, matches)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	path := filepath.Join(dir, "openclaw.json")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("one\n")))
+	require.NoError(t, WriteFileAtomic(path, []byte("two\n")))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "two\n", string(data))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temp files are left behind")
+}
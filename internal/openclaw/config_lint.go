@@ -0,0 +1,151 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// 配置问题级别
+const (
+	IssueError = "error"
+	IssueWarn  = "warn"
+	IssueInfo  = "info"
+)
+
+// ConfigIssue 配置检查发现的单个问题
+type ConfigIssue struct {
+	Level      string `json:"level"`          // error / warn / info
+	Code       string `json:"code"`           // 机器可读的问题代码，前端据此翻译
+	Path       string `json:"path,omitempty"` // 相关配置项，如 gateway.auth.enabled
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// LintConfigFile 读取并检查 openclaw.json，文件不存在/无法读取/解析失败也作为问题返回
+func LintConfigFile(path string) []ConfigIssue {
+	if _, err := os.Stat(path); err != nil {
+		return []ConfigIssue{{
+			Level:      IssueError,
+			Code:       "CONFIG_NOT_FOUND",
+			Message:    "配置文件不存在: " + path,
+			Suggestion: "运行 `openclawdeck init` 生成最小安全配置",
+		}}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []ConfigIssue{{
+			Level:      IssueError,
+			Code:       "CONFIG_READ_FAILED",
+			Message:    "配置文件读取失败",
+			Suggestion: "检查文件权限",
+		}}
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return []ConfigIssue{{
+			Level:      IssueError,
+			Code:       "CONFIG_PARSE_FAILED",
+			Message:    "配置 JSON 解析失败",
+			Suggestion: "修正配置格式或重新运行 `openclawdeck init`",
+		}}
+	}
+	return LintConfig(raw)
+}
+
+// LintConfig 检查已解析的配置：废弃字段、网关 mode/bind/鉴权、远程网关设置
+func LintConfig(raw map[string]any) []ConfigIssue {
+	issues := make([]ConfigIssue, 0)
+
+	gw, _ := raw["gateway"].(map[string]any)
+	mode := strings.TrimSpace(asString(gw["mode"]))
+	bind := strings.TrimSpace(asString(gw["bind"]))
+	auth, _ := gw["auth"].(map[string]any)
+	authToken := strings.TrimSpace(asString(auth["token"]))
+	authMode := strings.TrimSpace(asString(auth["mode"]))
+	authEnabled := authMode == "token" && authToken != ""
+
+	if _, exists := auth["enabled"]; exists {
+		issues = append(issues, ConfigIssue{
+			Level:      IssueWarn,
+			Code:       "DEPRECATED_AUTH_ENABLED",
+			Path:       "gateway.auth.enabled",
+			Message:    "检测到已废弃配置项 gateway.auth.enabled",
+			Suggestion: "运行 `openclawdeck doctor --fix` 自动迁移并移除该字段",
+		})
+	}
+
+	if mode == "" {
+		issues = append(issues, ConfigIssue{
+			Level:      IssueError,
+			Code:       "GATEWAY_MODE_MISSING",
+			Path:       "gateway.mode",
+			Message:    "未设置 gateway.mode",
+			Suggestion: "建议设置为 `local`",
+		})
+	}
+	if bind == "" {
+		issues = append(issues, ConfigIssue{
+			Level:      IssueError,
+			Code:       "GATEWAY_BIND_MISSING",
+			Path:       "gateway.bind",
+			Message:    "未设置 gateway.bind",
+			Suggestion: "建议设置为 `loopback`",
+		})
+	} else if !IsLoopbackBind(bind) && !authEnabled {
+		issues = append(issues, ConfigIssue{
+			Level:      IssueWarn,
+			Code:       "GATEWAY_BIND_INSECURE",
+			Path:       "gateway.bind",
+			Message:    "网关绑定非回环地址且未启用鉴权",
+			Suggestion: "设置 gateway.auth.mode=token 和 gateway.auth.token，或改为回环地址",
+		})
+	}
+	if authMode == "token" && authToken == "" {
+		issues = append(issues, ConfigIssue{
+			Level:      IssueError,
+			Code:       "GATEWAY_TOKEN_MISSING",
+			Path:       "gateway.auth.token",
+			Message:    "gateway.auth.mode=token 但未设置 gateway.auth.token",
+			Suggestion: "设置 gateway.auth.token 或切换为回环地址",
+		})
+	}
+	if mode == "remote" {
+		remote, _ := gw["remote"].(map[string]any)
+		remoteURL := strings.TrimSpace(asString(remote["url"]))
+		if remoteURL == "" {
+			issues = append(issues, ConfigIssue{
+				Level:      IssueError,
+				Code:       "REMOTE_URL_MISSING",
+				Path:       "gateway.remote.url",
+				Message:    "gateway.mode=remote 但未设置 gateway.remote.url",
+				Suggestion: "设置远程网关地址（如 ws://host:18789）",
+			})
+		} else if !strings.HasPrefix(remoteURL, "ws://") && !strings.HasPrefix(remoteURL, "wss://") {
+			issues = append(issues, ConfigIssue{
+				Level:      IssueWarn,
+				Code:       "REMOTE_URL_SCHEME",
+				Path:       "gateway.remote.url",
+				Message:    "gateway.remote.url 不是 ws:// 或 wss:// 开头",
+				Suggestion: "请检查远程网关地址",
+			})
+		}
+		remoteToken := strings.TrimSpace(asString(remote["token"]))
+		remotePwd := strings.TrimSpace(asString(remote["password"]))
+		if remoteToken == "" && remotePwd == "" {
+			issues = append(issues, ConfigIssue{
+				Level:      IssueWarn,
+				Code:       "REMOTE_AUTH_MISSING",
+				Path:       "gateway.remote",
+				Message:    "远程网关未配置 token/password",
+				Suggestion: "确认远程网关是否需要鉴权",
+			})
+		}
+	}
+	return issues
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,60 @@
+package openclaw
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintCodes(issues []ConfigIssue) []string {
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestLintConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		raw  map[string]any
+		want []string
+	}{
+		{"secure local", map[string]any{"gateway": map[string]any{"mode": "local", "bind": "loopback"}}, []string{}},
+		{"empty", map[string]any{}, []string{"GATEWAY_MODE_MISSING", "GATEWAY_BIND_MISSING"}},
+		{"deprecated auth.enabled", map[string]any{"gateway": map[string]any{
+			"mode": "local", "bind": "loopback", "auth": map[string]any{"enabled": true},
+		}}, []string{"DEPRECATED_AUTH_ENABLED"}},
+		{"lan without auth", map[string]any{"gateway": map[string]any{"mode": "local", "bind": "lan"}}, []string{"GATEWAY_BIND_INSECURE"}},
+		{"lan with token", map[string]any{"gateway": map[string]any{
+			"mode": "local", "bind": "lan", "auth": map[string]any{"mode": "token", "token": "tok"},
+		}}, []string{}},
+		{"token mode without token", map[string]any{"gateway": map[string]any{
+			"mode": "local", "bind": "loopback", "auth": map[string]any{"mode": "token"},
+		}}, []string{"GATEWAY_TOKEN_MISSING"}},
+		{"remote http url without auth", map[string]any{"gateway": map[string]any{
+			"mode": "remote", "bind": "loopback", "remote": map[string]any{"url": "http://gw.example.com"},
+		}}, []string{"REMOTE_URL_SCHEME", "REMOTE_AUTH_MISSING"}},
+		{"remote without url", map[string]any{"gateway": map[string]any{
+			"mode": "remote", "bind": "loopback", "remote": map[string]any{"token": "tok"},
+		}}, []string{"REMOTE_URL_MISSING"}},
+	} {
+		assert.Equal(t, tc.want, lintCodes(LintConfig(tc.raw)), tc.name)
+	}
+}
+
+func TestLintConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openclaw.json")
+
+	assert.Equal(t, []string{"CONFIG_NOT_FOUND"}, lintCodes(LintConfigFile(path)))
+
+	require.NoError(t, os.WriteFile(path, []byte("{broken"), 0o600))
+	assert.Equal(t, []string{"CONFIG_PARSE_FAILED"}, lintCodes(LintConfigFile(path)))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"gateway":{"mode":"local","bind":"loopback"}}`), 0o600))
+	assert.Empty(t, LintConfigFile(path))
+}
@@ -0,0 +1,97 @@
+package openclaw
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConfigChange 配置迁移/修复产生的单项修改
+type ConfigChange struct {
+	Path   string      `json:"path"`
+	Action string      `json:"action"` // set / remove
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// MigrateConfig 对已解析的配置执行安全修复（原地修改），返回实际发生的修改：
+//   - 移除废弃的 gateway.auth.enabled
+//   - 补齐 gateway.mode=local / gateway.bind=loopback / gateway.port
+//   - 非回环绑定时启用 token 鉴权并生成 token
+func MigrateConfig(raw map[string]any) []ConfigChange {
+	var changes []ConfigChange
+
+	gw, ok := raw["gateway"].(map[string]any)
+	if !ok {
+		gw = map[string]any{}
+		raw["gateway"] = gw
+	}
+	if strings.TrimSpace(asString(gw["mode"])) == "" {
+		changes = append(changes, ConfigChange{Path: "gateway.mode", Action: "set", Old: gw["mode"], New: "local"})
+		gw["mode"] = "local"
+	}
+	bind := strings.TrimSpace(asString(gw["bind"]))
+	if bind == "" {
+		changes = append(changes, ConfigChange{Path: "gateway.bind", Action: "set", Old: gw["bind"], New: "loopback"})
+		gw["bind"] = "loopback"
+		bind = "loopback"
+	}
+	if _, ok := gw["port"]; !ok {
+		changes = append(changes, ConfigChange{Path: "gateway.port", Action: "set", New: DefaultGatewayPort})
+		gw["port"] = DefaultGatewayPort
+	}
+
+	auth, ok := gw["auth"].(map[string]any)
+	if !ok {
+		auth = map[string]any{}
+	}
+	if old, exists := auth["enabled"]; exists {
+		changes = append(changes, ConfigChange{Path: "gateway.auth.enabled", Action: "remove", Old: old})
+		delete(auth, "enabled")
+	}
+	if !IsLoopbackBind(bind) {
+		if strings.TrimSpace(asString(auth["mode"])) == "" {
+			changes = append(changes, ConfigChange{Path: "gateway.auth.mode", Action: "set", Old: auth["mode"], New: "token"})
+			auth["mode"] = "token"
+		}
+		if strings.TrimSpace(asString(auth["token"])) == "" {
+			token := GenerateToken(32)
+			changes = append(changes, ConfigChange{Path: "gateway.auth.token", Action: "set", New: token})
+			auth["token"] = token
+		}
+	}
+	if len(auth) > 0 {
+		gw["auth"] = auth
+	}
+	return changes
+}
+
+// BackupConfigFile 将配置文件原样备份到 ~/.openclaw/backups（失败时尝试配置所在目录的 backups），返回备份路径
+func BackupConfigFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Base(path)
+	var dirs []string
+	if stateDir := ResolveStateDir(); stateDir != "" {
+		dirs = append(dirs, filepath.Join(stateDir, "backups"))
+	}
+	dirs = append(dirs, filepath.Join(filepath.Dir(path), "backups"))
+	var lastErr error
+	for _, backupDir := range dirs {
+		if err := os.MkdirAll(backupDir, 0o755); err != nil {
+			lastErr = err
+			continue
+		}
+		backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", base, time.Now().Format("20060102-150405")))
+		if err := os.WriteFile(backupPath, data, 0o600); err != nil {
+			lastErr = err
+			continue
+		}
+		return backupPath, nil
+	}
+	return "", lastErr
+}
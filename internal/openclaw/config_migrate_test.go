@@ -0,0 +1,63 @@
+package openclaw
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func changePaths(changes []ConfigChange) []string {
+	paths := make([]string, 0, len(changes))
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	return paths
+}
+
+func TestMigrateConfig(t *testing.T) {
+	raw := map[string]any{"gateway": map[string]any{
+		"bind": "lan",
+		"auth": map[string]any{"enabled": true},
+	}}
+	changes := MigrateConfig(raw)
+	assert.Equal(t, []string{"gateway.mode", "gateway.port", "gateway.auth.enabled", "gateway.auth.mode", "gateway.auth.token"}, changePaths(changes))
+
+	gw := raw["gateway"].(map[string]any)
+	auth := gw["auth"].(map[string]any)
+	assert.Equal(t, "local", gw["mode"])
+	assert.Equal(t, "lan", gw["bind"])
+	assert.NotContains(t, auth, "enabled")
+	assert.Equal(t, "token", auth["mode"])
+	assert.NotEmpty(t, auth["token"])
+	assert.Empty(t, LintConfig(raw), "migrated config passes lint")
+
+	// a second run has nothing left to fix
+	assert.Empty(t, MigrateConfig(raw))
+}
+
+func TestMigrateConfig_LoopbackKeepsAuthUnset(t *testing.T) {
+	raw := map[string]any{}
+	assert.Equal(t, []string{"gateway.mode", "gateway.bind", "gateway.port"}, changePaths(MigrateConfig(raw)))
+	assert.NotContains(t, raw["gateway"], "auth")
+}
+
+func TestBackupConfigFile(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("OPENCLAW_STATE_DIR", stateDir)
+	path := filepath.Join(t.TempDir(), "openclaw.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"gateway":{}}`), 0o600))
+
+	backup, err := BackupConfigFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(backup, filepath.Join(stateDir, "backups", "openclaw.json.")))
+	data, err := os.ReadFile(backup)
+	require.NoError(t, err)
+	assert.Equal(t, `{"gateway":{}}`, string(data))
+
+	_, err = BackupConfigFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package openclaw
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeConfigYAML 将配置编码为 YAML（键按字母序，2 空格缩进）
+func EncodeConfigYAML(cfg map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseConfigDocument 解析 JSON 或 YAML 配置文档（以 { 开头按 JSON 解析），
+// 结果与 json.Unmarshal 得到的类型一致（数字为 float64），保证 JSON↔YAML 往返不失真
+func ParseConfigDocument(data []byte) (map[string]any, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty config document")
+	}
+	if trimmed[0] == '{' {
+		var cfg map[string]any
+		if err := json.Unmarshal(trimmed, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return cfg, nil
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	norm, err := normalizeYAML(doc)
+	if err != nil {
+		return nil, err
+	}
+	// 经 JSON 往返，统一数字与嵌套类型
+	raw, err := json.Marshal(norm)
+	if err != nil {
+		return nil, err
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, errors.New("config document must be a mapping")
+	}
+	return cfg, nil
+}
+
+// normalizeYAML 将 yaml.v3 解出的 map[any]any 转为 map[string]any
+func normalizeYAML(v any) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			n, err := normalizeYAML(val)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = n
+		}
+		return t, nil
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			n, err := normalizeYAML(val)
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(k)] = n
+		}
+		return out, nil
+	case []any:
+		for i, val := range t {
+			n, err := normalizeYAML(val)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = n
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
+
+// RestoreRedacted 将导入配置中含 marker（导出时脱敏的占位符）的字符串恢复为当前配置中的原值；
+// 当前配置中不存在的脱敏字段被移除，避免把占位符写进配置
+func RestoreRedacted(next, current map[string]any, marker string) {
+	for k, v := range next {
+		switch t := v.(type) {
+		case string:
+			if !strings.Contains(t, marker) {
+				continue
+			}
+			if old, ok := current[k]; ok {
+				next[k] = old
+			} else {
+				delete(next, k)
+			}
+		case map[string]any:
+			cur, _ := current[k].(map[string]any)
+			RestoreRedacted(t, cur, marker)
+		case []any:
+			cur, _ := current[k].([]any)
+			for i, item := range t {
+				if s, ok := item.(string); ok && strings.Contains(s, marker) && i < len(cur) {
+					t[i] = cur[i]
+					continue
+				}
+				if m, ok := item.(map[string]any); ok {
+					var cm map[string]any
+					if i < len(cur) {
+						cm, _ = cur[i].(map[string]any)
+					}
+					RestoreRedacted(m, cm, marker)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlSampleConfig = `{
+  "gateway": {"mode": "local", "bind": "loopback", "port": 18789, "auth": {"token": "abc123"}},
+  "agents": {"defaults": {"model": {"primary": "openai/gpt-4o", "fallbacks": ["a", "b"]}, "temperature": 0.7}},
+  "channels": {"telegram": {"enabled": true, "allowFrom": ["12345", "on", "null"], "botToken": ""}},
+  "meta": {"count": 0, "empty": {}, "list": [], "nothing": null, "version": "1.10"}
+}`
+
+func TestConfigYAMLRoundTrip(t *testing.T) {
+	var orig map[string]any
+	require.NoError(t, json.Unmarshal([]byte(yamlSampleConfig), &orig))
+
+	out, err := EncodeConfigYAML(orig)
+	require.NoError(t, err)
+	back, err := ParseConfigDocument(out)
+	require.NoError(t, err)
+	assert.Equal(t, orig, back, "yaml:\n%s", out)
+
+	// 再次编码应得到相同文本
+	again, err := EncodeConfigYAML(back)
+	require.NoError(t, err)
+	assert.Equal(t, string(out), string(again))
+}
+
+func TestParseConfigDocument(t *testing.T) {
+	cfg, err := ParseConfigDocument([]byte("  " + yamlSampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, float64(18789), cfg["gateway"].(map[string]any)["port"])
+
+	cfg, err = ParseConfigDocument([]byte("gateway:\n  port: 18789\n  bind: lan\n1: one\n"))
+	require.NoError(t, err)
+	assert.Equal(t, float64(18789), cfg["gateway"].(map[string]any)["port"])
+	assert.Equal(t, "one", cfg["1"])
+
+	for _, bad := range []string{"", "- a\n- b\n", "just text", "{not json", "a: [1,"} {
+		_, err := ParseConfigDocument([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRestoreRedacted(t *testing.T) {
+	const marker = "***REDACTED***"
+	current := map[string]any{
+		"gateway":  map[string]any{"auth": map[string]any{"token": "secret"}},
+		"webhooks": []any{map[string]any{"url": "https://x?token=abc"}},
+	}
+	next := map[string]any{
+		"gateway":  map[string]any{"auth": map[string]any{"token": marker}, "port": float64(1)},
+		"webhooks": []any{map[string]any{"url": "https://x?token=" + marker}},
+		"new":      map[string]any{"apiKey": marker},
+	}
+	RestoreRedacted(next, current, marker)
+	assert.Equal(t, "secret", next["gateway"].(map[string]any)["auth"].(map[string]any)["token"])
+	assert.Equal(t, "https://x?token=abc", next["webhooks"].([]any)[0].(map[string]any)["url"])
+	assert.Empty(t, next["new"].(map[string]any))
+}
@@ -0,0 +1,104 @@
+package openclaw
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ContainerRuntime 容器运行时（docker / podman），封装查找与启停 openclaw 容器的命令
+type ContainerRuntime interface {
+	// Name 运行时名称，同时作为 Runtime 类型值
+	Name() Runtime
+	// Available 本机是否安装了该运行时的 CLI
+	Available() bool
+	// FindContainer 查找名称包含 openclaw 的容器（含已停止的），未找到返回空
+	FindContainer() string
+	Start(container string) error
+	Stop(container string) error
+	Restart(container string) error
+	// LogsCommand 构造 `logs --tail N [-f] <container>` 命令，容器 stdout/stderr 分别输出到命令的 stdout/stderr
+	LogsCommand(ctx context.Context, container string, tail int, follow bool) *exec.Cmd
+}
+
+// cliContainerRuntime 兼容 docker CLI 语法的容器运行时（podman 的 ps/start/stop/restart 与 docker 一致）
+type cliContainerRuntime struct {
+	runtime Runtime
+	bin     string
+}
+
+func (c cliContainerRuntime) Name() Runtime   { return c.runtime }
+func (c cliContainerRuntime) Available() bool { return commandExists(c.bin) }
+
+func (c cliContainerRuntime) FindContainer() string {
+	out, err := runOutput(c.bin, "ps", "-a", "--format", "{{.Names}}")
+	if err != nil {
+		return ""
+	}
+	return matchOpenClawContainer(out)
+}
+
+func (c cliContainerRuntime) Start(container string) error {
+	return runCommand(c.bin, "start", container)
+}
+
+func (c cliContainerRuntime) Stop(container string) error {
+	return runCommand(c.bin, "stop", container)
+}
+
+func (c cliContainerRuntime) Restart(container string) error {
+	return runCommand(c.bin, "restart", container)
+}
+
+func (c cliContainerRuntime) LogsCommand(ctx context.Context, container string, tail int, follow bool) *exec.Cmd {
+	args := []string{"logs", "--tail", strconv.Itoa(tail)}
+	if follow {
+		args = append(args, "-f")
+	}
+	args = append(args, container)
+	return exec.CommandContext(ctx, c.bin, args...)
+}
+
+// containerRuntimes 按优先级排列：两者都有 openclaw 容器时默认使用 Docker
+var containerRuntimes = []ContainerRuntime{
+	cliContainerRuntime{runtime: RuntimeDocker, bin: "docker"},
+	cliContainerRuntime{runtime: RuntimePodman, bin: "podman"},
+}
+
+// findContainer 依次在各容器运行时中查找 openclaw 容器，返回第一个找到的运行时与容器名
+func findContainer() (ContainerRuntime, string) {
+	for _, rt := range containerRuntimes {
+		if !rt.Available() {
+			continue
+		}
+		if name := rt.FindContainer(); name != "" {
+			return rt, name
+		}
+	}
+	return nil, ""
+}
+
+// containerRuntimeFor 返回指定 Runtime 对应的容器运行时，非容器运行时返回 nil
+func containerRuntimeFor(rt Runtime) ContainerRuntime {
+	for _, c := range containerRuntimes {
+		if c.Name() == rt {
+			return c
+		}
+	}
+	return nil
+}
+
+// matchOpenClawContainer 从 `ps --format {{.Names}}` 输出中挑出第一个名称包含 openclaw 的容器
+func matchOpenClawContainer(psOutput string) string {
+	for _, line := range strings.Split(psOutput, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.Contains(strings.ToLower(line), "openclaw") {
+			return line
+		}
+	}
+	return ""
+}
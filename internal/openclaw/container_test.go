@@ -0,0 +1,18 @@
+package openclaw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchOpenClawContainer(t *testing.T) {
+	assert.Equal(t, "my-OpenClaw-gw", matchOpenClawContainer("redis\n\n  my-OpenClaw-gw  \nopenclaw-2\n"))
+	assert.Empty(t, matchOpenClawContainer("redis\npostgres\n"))
+}
+
+func TestContainerRuntimeFor(t *testing.T) {
+	assert.Equal(t, RuntimeDocker, containerRuntimes[0].Name(), "docker must stay the default")
+	assert.Equal(t, RuntimePodman, containerRuntimeFor(RuntimePodman).Name())
+	assert.Nil(t, containerRuntimeFor(RuntimeSystemd))
+}
@@ -18,6 +18,7 @@ const (
 	DiagnosePass DiagnoseItemStatus = "pass"
 	DiagnoseFail DiagnoseItemStatus = "fail"
 	DiagnoseWarn DiagnoseItemStatus = "warn"
+	DiagnoseSkip DiagnoseItemStatus = "skip" // 前置条件不满足，未执行
 )
 
 // DiagnoseItem 单个诊断项
@@ -37,8 +38,8 @@ type DiagnoseResult struct {
 	Message string         `json:"message"`
 }
 
-// DiagnoseGateway 执行网关诊断
-func DiagnoseGateway(host string, port int) *DiagnoseResult {
+// DiagnoseGateway 执行网关诊断，token 用于 WS 握手探测（为空时读取 openclaw.json）
+func DiagnoseGateway(host string, port int, token string) *DiagnoseResult {
 	if host == "" {
 		host = "127.0.0.1"
 	}
@@ -106,6 +107,14 @@ func DiagnoseGateway(host string, port int) *DiagnoseResult {
 		overallStatus = DiagnoseWarn
 	}
 
+	// 9. WS 握手逐阶段探测（区分网络、Token、版本问题）
+	for _, item := range ProbeGatewayHandshake(host, port, token) {
+		result.Items = append(result.Items, item)
+		if item.Status == DiagnoseFail {
+			overallStatus = DiagnoseFail
+		}
+	}
+
 	result.Summary = string(overallStatus)
 	switch overallStatus {
 	case DiagnosePass:
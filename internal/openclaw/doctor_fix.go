@@ -0,0 +1,159 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DoctorFixResult doctor 自动修复结果，openclaw.json 与环境变量配置的修改分开记录
+type DoctorFixResult struct {
+	Config       []ConfigChange `json:"config"`
+	ConfigBackup string         `json:"configBackup,omitempty"` // 修改 openclaw.json 前创建的备份
+	Env          []ConfigChange `json:"env"`
+}
+
+// Changed 是否有任何修改
+func (r *DoctorFixResult) Changed() bool {
+	return len(r.Config) > 0 || len(r.Env) > 0
+}
+
+// RunDoctorFixes 依次修复 openclaw.json 与环境变量配置，返回实际应用的修改
+func RunDoctorFixes(configPath, envPath string) (*DoctorFixResult, error) {
+	result := &DoctorFixResult{Config: []ConfigChange{}, Env: []ConfigChange{}}
+
+	changes, backup, err := FixConfigFile(configPath)
+	if err != nil {
+		return result, err
+	}
+	result.Config, result.ConfigBackup = changes, backup
+
+	envChanges, err := FixEnvConfig(envPath)
+	if err != nil {
+		return result, err
+	}
+	result.Env = envChanges
+	return result, nil
+}
+
+// FixConfigFile 对 openclaw.json 执行 MigrateConfig，有修改时先备份再写回
+func FixConfigFile(path string) (changes []ConfigChange, backup string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, "", err
+	}
+
+	changes = MigrateConfig(raw)
+	if len(changes) == 0 {
+		return []ConfigChange{}, "", nil
+	}
+	if backup, err = BackupConfigFile(path); err != nil {
+		return nil, "", err
+	}
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return nil, backup, err
+	}
+	if err := WriteFileAtomic(path, append(out, '\n')); err != nil {
+		return nil, backup, err
+	}
+	return changes, backup, nil
+}
+
+// FixEnvConfig 根据已有变量推断缺失的通知平台、模型提供商与时区，返回修改的变量
+func FixEnvConfig(envPath string) ([]ConfigChange, error) {
+	values, err := ReadEnvFile(envPath)
+	if err != nil {
+		return nil, err
+	}
+	changes := []ConfigChange{}
+	set := func(key, value string) {
+		changes = append(changes, ConfigChange{Path: key, Action: "set", Old: values[key], New: value})
+		values[key] = value
+	}
+
+	platform := strings.ToLower(strings.TrimSpace(values["OPENCLAW_NOTIFY_PLATFORM"]))
+	if platform == "" {
+		if strings.TrimSpace(values["TELEGRAM_BOT_TOKEN"]) != "" || strings.TrimSpace(values["TELEGRAM_CHAT_ID"]) != "" {
+			set("OPENCLAW_NOTIFY_PLATFORM", "telegram")
+		} else if strings.TrimSpace(values["SLACK_WEBHOOK_URL"]) != "" {
+			set("OPENCLAW_NOTIFY_PLATFORM", "slack")
+		} else if strings.TrimSpace(values["FEISHU_WEBHOOK_URL"]) != "" {
+			set("OPENCLAW_NOTIFY_PLATFORM", "feishu")
+		} else if strings.TrimSpace(values["OPENCLAW_NOTIFY_WEBHOOK"]) != "" {
+			set("OPENCLAW_NOTIFY_PLATFORM", "custom")
+		}
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(values["OPENCLAW_AI_PROVIDER"]))
+	if provider == "" && strings.TrimSpace(values["OPENCLAW_BASE_URL"]) != "" {
+		set("OPENCLAW_AI_PROVIDER", "custom")
+	}
+
+	if strings.TrimSpace(values["OPENCLAW_TIMEZONE"]) == "" {
+		if tz := strings.TrimSpace(os.Getenv("TZ")); tz != "" {
+			set("OPENCLAW_TIMEZONE", tz)
+		}
+	}
+
+	if len(changes) == 0 {
+		return changes, nil
+	}
+	if err := WriteEnvFile(envPath, values); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ReadEnvFile 读取 `export KEY="value"` 格式的环境变量文件（不存在时返回空 map）
+func ReadEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	out := map[string]string{}
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "export ") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		k := strings.TrimSpace(parts[0])
+		v := strings.Trim(parts[1], "\"")
+		out[k] = v
+	}
+	return out, nil
+}
+
+// WriteEnvFile 按键名排序写回环境变量文件
+func WriteEnvFile(path string, values map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "# OpenClaw 环境变量（由 openclawdeck 生成）")
+	for _, k := range keys {
+		fmt.Fprintf(b, "export %s=\"%s\"\n", k, strings.ReplaceAll(values[k], "\"", "\\\""))
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o600)
+}
@@ -0,0 +1,201 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envRefPattern 匹配配置中的 ${VAR} 引用（$${VAR} 为转义，不算引用）
+var envRefPattern = regexp.MustCompile(`\$?\$\{([A-Z_][A-Z0-9_]*)\}`)
+
+// UnresolvedEnvVar 配置中引用但在 .env、config.env 与进程环境中都未定义（或为空）的变量
+type UnresolvedEnvVar struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"` // 引用该变量的配置路径
+}
+
+// DotEnvPath 返回 Gateway 加载的 .env 路径（状态目录下）
+func DotEnvPath() string {
+	dir := ResolveStateDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, ".env")
+}
+
+// ParseEnvLine 解析 .env 中的 `KEY=value` 或 `export KEY=value`，注释与空行返回 ok=false
+func ParseEnvLine(line string) (key, value string, ok bool) {
+	s := strings.TrimSpace(line)
+	if s == "" || strings.HasPrefix(s, "#") {
+		return "", "", false
+	}
+	s = strings.TrimPrefix(s, "export ")
+	k, v, found := strings.Cut(s, "=")
+	if !found {
+		return "", "", false
+	}
+	key = strings.TrimSpace(k)
+	if key == "" {
+		return "", "", false
+	}
+	v = strings.TrimSpace(v)
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		if v[0] == '"' {
+			if uq, err := strconv.Unquote(v); err == nil {
+				return key, uq, true
+			}
+		}
+		return key, v[1 : len(v)-1], true
+	}
+	return key, v, true
+}
+
+// ReadDotEnv 读取 .env 文件（不存在时返回空 map）
+func ReadDotEnv(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	out := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if k, v, ok := ParseEnvLine(line); ok {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+// FindEnvRefs 收集配置中所有 ${VAR} 引用，返回 变量名 → 引用路径
+func FindEnvRefs(raw map[string]any) map[string][]string {
+	refs := map[string][]string{}
+	var walk func(v any, path string)
+	walk = func(v any, path string) {
+		switch val := v.(type) {
+		case map[string]any:
+			for k, child := range val {
+				walk(child, joinConfigPath(path, k))
+			}
+		case []any:
+			for i, child := range val {
+				walk(child, fmt.Sprintf("%s[%d]", path, i))
+			}
+		case string:
+			for _, m := range envRefPattern.FindAllStringSubmatch(val, -1) {
+				if strings.HasPrefix(m[0], "$$") {
+					continue
+				}
+				refs[m[1]] = append(refs[m[1]], path)
+			}
+		}
+	}
+	walk(raw, "")
+	return refs
+}
+
+// HasEnvRef 判断 s 中是否含有 ${VAR} 引用（$${VAR} 转义不算）
+func HasEnvRef(s string) bool {
+	for _, m := range envRefPattern.FindAllString(s, -1) {
+		if !strings.HasPrefix(m, "$$") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinConfigPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// configEnvVars 配置自身 env 段定义的变量（env.KEY 或 env.vars.KEY）
+func configEnvVars(raw map[string]any) map[string]string {
+	out := map[string]string{}
+	env, ok := raw["env"].(map[string]any)
+	if !ok {
+		return out
+	}
+	collect := func(m map[string]any) {
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				out[k] = s
+			}
+		}
+	}
+	collect(env)
+	if vars, ok := env["vars"].(map[string]any); ok {
+		collect(vars)
+	}
+	return out
+}
+
+// CheckEnvRefs 检查 openclaw.json 中的 ${VAR} 引用是否都能解析（.env、config env 段、进程环境），
+// 返回未解析的变量（按名称排序）；配置文件不存在时返回 nil
+func CheckEnvRefs(configPath, dotEnvPath string) ([]UnresolvedEnvVar, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	dotEnv := map[string]string{}
+	if dotEnvPath != "" {
+		if dotEnv, err = ReadDotEnv(dotEnvPath); err != nil {
+			return nil, err
+		}
+	}
+	cfgEnv := configEnvVars(raw)
+
+	var missing []UnresolvedEnvVar
+	for name, paths := range FindEnvRefs(raw) {
+		if strings.TrimSpace(dotEnv[name]) != "" || strings.TrimSpace(cfgEnv[name]) != "" || strings.TrimSpace(os.Getenv(name)) != "" {
+			continue
+		}
+		sort.Strings(paths)
+		missing = append(missing, UnresolvedEnvVar{Name: name, Paths: paths})
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Name < missing[j].Name })
+	return missing, nil
+}
+
+// CheckCurrentEnvRefs 对当前 openclaw.json 与状态目录 .env 执行 CheckEnvRefs
+func CheckCurrentEnvRefs() ([]UnresolvedEnvVar, error) {
+	path := ResolveConfigPath()
+	if path == "" {
+		return nil, nil
+	}
+	return CheckEnvRefs(path, DotEnvPath())
+}
+
+// ExpandEnvRefs 按 .env、配置 env 段、进程环境的顺序展开 s 中的 ${VAR}（$${VAR} 还原为字面量 ${VAR}）；
+// 未定义的变量展开为空串
+func ExpandEnvRefs(s string, raw map[string]any, dotEnv map[string]string) string {
+	cfgEnv := configEnvVars(raw)
+	return envRefPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if strings.HasPrefix(m, "$$") {
+			return m[1:]
+		}
+		name := envRefPattern.FindStringSubmatch(m)[1]
+		for _, v := range []string{dotEnv[name], cfgEnv[name], os.Getenv(name)} {
+			if strings.TrimSpace(v) != "" {
+				return v
+			}
+		}
+		return ""
+	})
+}
@@ -0,0 +1,49 @@
+package openclaw
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEnvRefs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "openclaw.json")
+	dotEnv := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(cfg, []byte(`{
+		"env": {"vars": {"FROM_CONFIG": "x"}},
+		"models": {"providers": {
+			"anthropic": {"apiKey": "${ANTHROPIC_API_KEY}"},
+			"openai": {"apiKey": "${OPENAI_API_KEY}", "baseUrl": "https://${FROM_CONFIG}/v1"}
+		}},
+		"channels": {"telegram": {"botToken": "${TG_TOKEN}", "note": "literal $${NOT_A_REF}"}},
+		"list": ["${FROM_PROCESS}", "${EMPTY_IN_FILE}"]
+	}`), 0o600))
+	require.NoError(t, os.WriteFile(dotEnv, []byte("# keys\nexport OPENAI_API_KEY=\"sk-1\"\nEMPTY_IN_FILE=\n"), 0o600))
+	t.Setenv("FROM_PROCESS", "1")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("TG_TOKEN", "")
+
+	missing, err := CheckEnvRefs(cfg, dotEnv)
+	require.NoError(t, err)
+	assert.Equal(t, []UnresolvedEnvVar{
+		{Name: "ANTHROPIC_API_KEY", Paths: []string{"models.providers.anthropic.apiKey"}},
+		{Name: "EMPTY_IN_FILE", Paths: []string{"list[1]"}},
+		{Name: "TG_TOKEN", Paths: []string{"channels.telegram.botToken"}},
+	}, missing)
+
+	missing, err = CheckEnvRefs(filepath.Join(dir, "missing.json"), dotEnv)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestHasEnvRef(t *testing.T) {
+	assert.True(t, HasEnvRef("${TOKEN}"))
+	assert.True(t, HasEnvRef("Bearer ${TOKEN}"))
+	assert.False(t, HasEnvRef("$${TOKEN}"))
+	assert.False(t, HasEnvRef("{{TOKEN}}"))
+	assert.False(t, HasEnvRef("plain"))
+}
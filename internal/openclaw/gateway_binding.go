@@ -0,0 +1,70 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GatewayBinding openclaw.json 中 gateway 段的监听与鉴权配置（一次读取）
+type GatewayBinding struct {
+	Bind    string `json:"bind,omitempty"` // gateway.bind，如 loopback / lan / 0.0.0.0
+	Port    int    `json:"port,omitempty"` // gateway.port，0 表示未配置
+	Mode    string `json:"mode,omitempty"` // gateway.mode，如 local / remote
+	HasAuth bool   `json:"hasAuth"`        // 是否配置了 gateway.auth.token 或 password
+}
+
+// ReadGatewayBinding 读取当前 openclaw.json 的 gateway 配置；文件不存在或无法解析时 ok 为 false
+func ReadGatewayBinding() (b GatewayBinding, ok bool) {
+	path := ResolveConfigPath()
+	if path == "" {
+		return b, false
+	}
+	return ReadGatewayBindingFrom(path)
+}
+
+// ReadGatewayBindingFrom 从指定配置文件读取 gateway 配置
+func ReadGatewayBindingFrom(path string) (b GatewayBinding, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, false
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return b, false
+	}
+	return GatewayBindingFromConfig(raw), true
+}
+
+// GatewayBindingFromConfig 从已解析的 openclaw.json 中提取 gateway 配置，raw 可为 nil
+func GatewayBindingFromConfig(raw map[string]any) GatewayBinding {
+	var b GatewayBinding
+	gw, ok := raw["gateway"].(map[string]any)
+	if !ok {
+		return b
+	}
+	if v, ok := gw["bind"].(string); ok {
+		b.Bind = strings.TrimSpace(v)
+	}
+	if v, ok := gw["mode"].(string); ok {
+		b.Mode = strings.TrimSpace(v)
+	}
+	// port 可能写成数字或字符串
+	switch v := gw["port"].(type) {
+	case float64:
+		if v > 0 {
+			b.Port = int(v)
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n > 0 {
+			b.Port = n
+		}
+	}
+	if auth, ok := gw["auth"].(map[string]any); ok {
+		token, _ := auth["token"].(string)
+		password, _ := auth["password"].(string)
+		b.HasAuth = strings.TrimSpace(token) != "" || strings.TrimSpace(password) != ""
+	}
+	return b
+}
@@ -0,0 +1,48 @@
+package openclaw
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGatewayBindingFrom(t *testing.T) {
+	cases := []struct {
+		name string
+		json string
+		want GatewayBinding
+	}{
+		{"numeric port with token", `{"gateway":{"bind":"lan","port":18800,"mode":"local","auth":{"token":"t"}}}`,
+			GatewayBinding{Bind: "lan", Port: 18800, Mode: "local", HasAuth: true}},
+		{"string port with password", `{"gateway":{"port":" 19000 ","auth":{"mode":"password","password":"p"}}}`,
+			GatewayBinding{Port: 19000, HasAuth: true}},
+		{"blank token is no auth", `{"gateway":{"bind":" loopback ","auth":{"token":"  "}}}`,
+			GatewayBinding{Bind: "loopback"}},
+		{"invalid port ignored", `{"gateway":{"port":"abc"}}`, GatewayBinding{}},
+		{"no gateway section", `{"agents":{}}`, GatewayBinding{}},
+		{"empty gateway section", `{"gateway":{}}`, GatewayBinding{}},
+		{"gateway not an object", `{"gateway":"local"}`, GatewayBinding{}},
+		{"negative port and non-string bind", `{"gateway":{"port":-1,"bind":7}}`, GatewayBinding{}},
+		{"auth not an object", `{"gateway":{"port":18789.0,"auth":"token"}}`, GatewayBinding{Port: 18789}},
+	}
+	dir := t.TempDir()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "openclaw.json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.json), 0o600))
+			got, ok := ReadGatewayBindingFrom(path)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, ok := ReadGatewayBindingFrom(filepath.Join(dir, "missing.json"))
+	assert.False(t, ok)
+	bad := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(bad, []byte("{"), 0o600))
+	_, ok = ReadGatewayBindingFrom(bad)
+	assert.False(t, ok)
+}
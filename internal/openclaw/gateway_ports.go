@@ -0,0 +1,67 @@
+package openclaw
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GatewayProbePortsSetting 设置项：额外探测的本地 Gateway 端口（逗号分隔），用于非标准端口部署
+const GatewayProbePortsSetting = "gateway_probe_ports"
+
+var (
+	extraPortsMu      sync.RWMutex
+	extraGatewayPorts []int
+)
+
+// SetExtraGatewayPorts 设置用户配置的额外探测端口
+func SetExtraGatewayPorts(ports []int) {
+	extraPortsMu.Lock()
+	defer extraPortsMu.Unlock()
+	extraGatewayPorts = append([]int(nil), ports...)
+}
+
+// ExtraGatewayPorts 返回用户配置的额外探测端口
+func ExtraGatewayPorts() []int {
+	extraPortsMu.RLock()
+	defer extraPortsMu.RUnlock()
+	return append([]int(nil), extraGatewayPorts...)
+}
+
+// ParseGatewayPorts 解析逗号/空白分隔的端口列表，非法端口返回错误
+func ParseGatewayPorts(s string) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n'
+	})
+	ports := make([]int, 0, len(fields))
+	for _, f := range fields {
+		p, err := strconv.Atoi(f)
+		if err != nil || p < 1 || p > 65535 {
+			return nil, fmt.Errorf("无效端口: %q", f)
+		}
+		ports = append(ports, p)
+	}
+	return ports, nil
+}
+
+// GatewayCandidatePorts 返回本地 Gateway 的候选端口（去重，按优先级）：
+// 激活档案端口 → 默认端口 → OPENCLAW_GATEWAY_PORT → openclaw.json gateway.port → 用户配置的额外端口
+func GatewayCandidatePorts(activePort int) []int {
+	return portsToInts(gatewayPortsToCheck(activePort))
+}
+
+// GatewayCandidatePortsWithConfig 同 GatewayCandidatePorts，但使用调用方已读取的 gateway.port，不再读取 openclaw.json
+func GatewayCandidatePortsWithConfig(activePort, configPort int) []int {
+	return portsToInts(gatewayPortsWithConfig(configPort, activePort))
+}
+
+func portsToInts(ports []string) []int {
+	var out []int
+	for _, p := range ports {
+		if n, err := strconv.Atoi(p); err == nil && n > 0 {
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package openclaw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGatewayPorts(t *testing.T) {
+	ports, err := ParseGatewayPorts(" 18800, 19000;20000 ")
+	require.NoError(t, err)
+	assert.Equal(t, []int{18800, 19000, 20000}, ports)
+
+	ports, err = ParseGatewayPorts("")
+	require.NoError(t, err)
+	assert.Empty(t, ports)
+
+	_, err = ParseGatewayPorts("18800,abc")
+	assert.Error(t, err)
+	_, err = ParseGatewayPorts("70000")
+	assert.Error(t, err)
+}
+
+func TestGatewayCandidatePorts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("OPENCLAW_STATE_DIR", "")
+	t.Setenv("CLAWDBOT_STATE_DIR", "")
+	t.Setenv("OPENCLAW_GATEWAY_PORT", "19001")
+	SetExtraGatewayPorts([]int{19002, 18789})
+	t.Cleanup(func() { SetExtraGatewayPorts(nil) })
+
+	assert.Equal(t, []int{19000, 18789, 19001, 19002}, GatewayCandidatePorts(19000))
+	assert.Equal(t, []int{18789, 19001, 19002}, GatewayCandidatePorts(0))
+}
+
+func TestGatewayCandidatePortsWithConfig(t *testing.T) {
+	t.Setenv("OPENCLAW_GATEWAY_PORT", "")
+	SetExtraGatewayPorts(nil)
+	assert.Equal(t, []int{19000, 18789, 18800}, GatewayCandidatePortsWithConfig(19000, 18800))
+	assert.Equal(t, []int{18789}, GatewayCandidatePortsWithConfig(0, 18789))
+}
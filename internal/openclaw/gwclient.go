@@ -1,12 +1,12 @@
 package openclaw
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -44,8 +44,9 @@ type EventFrame struct {
 
 // RPCError RPC 错误
 type RPCError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Details json.RawMessage `json:"details,omitempty"`
 }
 
 // ConnectParams 连接参数
@@ -109,11 +110,30 @@ type GWClient struct {
 	closed    bool
 	stopCh    chan struct{}
 	onEvent   GWEventHandler
+	eventSubs eventSubs // 按请求订阅事件（流式代理）
 
 	// 重连
 	reconnectCount int
 	backoffMs      int
 
+	// 连接诊断
+	protocol         int    // 协商成功的协议版本
+	protocolMismatch string // 协议不匹配说明（为空表示未检测到）
+	lastConnectError string
+
+	// connect 两段式响应：收到 accepted 后等待最终响应
+	connectID         string    // 当前 connect 请求 ID
+	connectAcceptedAt time.Time // 收到 accepted ack 的时间（零值表示未收到）
+	acceptedNoFinal   int       // accepted 后未等到最终响应的次数
+
+	// 空闲保活（独立于心跳健康检查，保持 NAT/代理映射）
+	keepaliveInterval time.Duration
+	lastActivity      time.Time
+
+	// permessage-deflate 压缩（远程慢链路下减少会话历史等大消息的流量）
+	compression bool
+	traffic     *wsTraffic // 当前连接的流量统计
+
 	// 心跳健康检查
 	healthMu        sync.Mutex
 	healthEnabled   bool          // 是否启用心跳自动重启
@@ -123,8 +143,17 @@ type GWClient struct {
 	healthLastOK    time.Time     // 上次成功时间
 	healthStopCh    chan struct{}
 	healthRunning   bool
-	onRestart       func() error // 重启回调（由外部注入）
-	onNotify        func(string) // 通知回调（由外部注入）
+	onRestart       func() error            // 重启回调（由外部注入）
+	onNotify        func(risk, text string) // 通知回调（由外部注入），risk 为告警级别
+
+	// last-known-good token 缓存（由外部注入持久化），配置文件重写期间读不到 token 时回退使用
+	tokenMu       sync.Mutex
+	lastGoodToken string
+	loadToken     func() string
+	saveToken     func(string)
+
+	// RPC 调用统计
+	rpc rpcStats
 }
 
 // NewGWClient 创建 Gateway WebSocket 客户端
@@ -134,6 +163,7 @@ func NewGWClient(cfg GWClientConfig) *GWClient {
 		pending:        make(map[string]chan *ResponseFrame),
 		stopCh:         make(chan struct{}),
 		backoffMs:      1000,
+		compression:    true,
 		healthInterval: 30 * time.Second,
 		healthMaxFails: 3,
 	}
@@ -151,8 +181,8 @@ func (c *GWClient) SetRestartCallback(fn func() error) {
 	c.onRestart = fn
 }
 
-// SetNotifyCallback 设置外部通知回调
-func (c *GWClient) SetNotifyCallback(fn func(string)) {
+// SetNotifyCallback 设置外部通知回调，risk 为告警级别（critical/high/medium/low）
+func (c *GWClient) SetNotifyCallback(fn func(risk, text string)) {
 	c.healthMu.Lock()
 	defer c.healthMu.Unlock()
 	c.onNotify = fn
@@ -175,6 +205,37 @@ func (c *GWClient) SetHealthCheckEnabled(enabled bool) {
 	}
 }
 
+// SetTokenCache 设置 last-known-good token 的持久化读写回调
+func (c *GWClient) SetTokenCache(load func() string, save func(string)) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.loadToken = load
+	c.saveToken = save
+	if load != nil {
+		c.lastGoodToken = load()
+	}
+}
+
+// cachedToken 返回上次鉴权成功的 token
+func (c *GWClient) cachedToken() string {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.lastGoodToken
+}
+
+// rememberToken 鉴权成功后记录 token，仅在变化时持久化（新 token 覆盖旧缓存）
+func (c *GWClient) rememberToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if token == "" || token == c.lastGoodToken {
+		return
+	}
+	c.lastGoodToken = token
+	if c.saveToken != nil {
+		c.saveToken(token)
+	}
+}
+
 // IsHealthCheckEnabled 返回心跳健康检查是否启用
 func (c *GWClient) IsHealthCheckEnabled() bool {
 	c.healthMu.Lock()
@@ -276,17 +337,11 @@ func (c *GWClient) healthCheckLoop() {
 					notifyFn := c.onNotify
 					c.healthMu.Unlock()
 
-					if restartErr := restartFn(); restartErr != nil {
-						logger.Gateway.Error().Err(restartErr).Msg("心跳自动重启网关失败")
+					healthRestart(restartFn, func(risk, text string) {
 						if notifyFn != nil {
-							go notifyFn("\U0001f6a8 OpenClaw Gateway 心跳检测失败，自动重启也失败: " + restartErr.Error())
+							go notifyFn(risk, text)
 						}
-					} else {
-						logger.Gateway.Info().Msg("心跳自动重启网关成功")
-						if notifyFn != nil {
-							go notifyFn("\u26a0\ufe0f OpenClaw Gateway 心跳检测失败，已自动重启成功")
-						}
-					}
+					})
 					continue
 				}
 			}
@@ -295,6 +350,91 @@ func (c *GWClient) healthCheckLoop() {
 	}
 }
 
+// healthRestart 心跳失败达到阈值后重启网关并通知结果。
+// 重启失败需要人工介入，以 critical 级别通知（免打扰时段也会送达）
+func healthRestart(restartFn func() error, notify func(risk, text string)) {
+	if restartErr := restartFn(); restartErr != nil {
+		logger.Gateway.Error().Err(restartErr).Msg("心跳自动重启网关失败")
+		notify("critical", "\U0001f6a8 OpenClaw Gateway 心跳检测失败，自动重启也失败: "+restartErr.Error())
+		return
+	}
+	logger.Gateway.Info().Msg("心跳自动重启网关成功")
+	notify("medium", "\u26a0\ufe0f OpenClaw Gateway 心跳检测失败，已自动重启成功")
+}
+
+// ConnStats 连接诊断信息
+type ConnStats struct {
+	Connected        bool   `json:"connected"`
+	Host             string `json:"host"`
+	Port             int    `json:"port"`
+	ReconnectCount   int    `json:"reconnect_count"`
+	Protocol         int    `json:"protocol,omitempty"`
+	SupportedProto   string `json:"supported_protocol"`
+	ProtocolMismatch string `json:"protocol_mismatch,omitempty"`
+	LastError        string `json:"last_error,omitempty"`
+	// AuthPending connect 已被 accepted，正在等待最终鉴权结果
+	AuthPending     bool `json:"auth_pending,omitempty"`
+	AcceptedNoFinal int  `json:"accepted_without_final,omitempty"`
+}
+
+// Stats 返回连接诊断信息
+func (c *GWClient) Stats() ConnStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ConnStats{
+		Connected:        c.connected,
+		Host:             c.cfg.Host,
+		Port:             c.cfg.Port,
+		ReconnectCount:   c.reconnectCount,
+		Protocol:         c.protocol,
+		SupportedProto:   protocolRange(GWProtocolMin, GWProtocolMax),
+		ProtocolMismatch: c.protocolMismatch,
+		LastError:        c.lastConnectError,
+		AuthPending:      c.connectID != "" && !c.connectAcceptedAt.IsZero(),
+		AcceptedNoFinal:  c.acceptedNoFinal,
+	}
+}
+
+// markAccepted 记录请求收到 accepted ack；仅 connect 请求影响诊断状态
+func (c *GWClient) markAccepted(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id != "" && id == c.connectID {
+		c.connectAcceptedAt = time.Now()
+		logger.Gateway.Debug().Msg("Gateway 已接受 connect，等待最终响应")
+	}
+}
+
+// endConnectWait 结束 connect 等待，返回是否曾收到 accepted ack
+func (c *GWClient) endConnectWait(id string) (accepted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connectID != id {
+		return false
+	}
+	accepted = !c.connectAcceptedAt.IsZero()
+	c.connectID = ""
+	c.connectAcceptedAt = time.Time{}
+	return accepted
+}
+
+// recordProtocolMismatch 记录并输出协议不匹配
+func (c *GWClient) recordProtocolMismatch(hint ProtocolHint) {
+	msg := ProtocolMismatchMessage(hint)
+	c.mu.Lock()
+	changed := c.protocolMismatch != msg
+	c.protocolMismatch = msg
+	c.mu.Unlock()
+	if changed {
+		logger.Gateway.Error().
+			Int("deck_min", GWProtocolMin).
+			Int("deck_max", GWProtocolMax).
+			Int("gateway_min", hint.Min).
+			Int("gateway_max", hint.Max).
+			Msg(msg)
+	}
+}
+
 // IsConnected 是否已连接
 func (c *GWClient) IsConnected() bool {
 	c.mu.Lock()
@@ -302,9 +442,69 @@ func (c *GWClient) IsConnected() bool {
 	return c.connected
 }
 
+// SetKeepaliveInterval 设置空闲保活间隔，0 表示禁用（需在 Start 之前调用）
+func (c *GWClient) SetKeepaliveInterval(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	c.keepaliveInterval = d
+}
+
+// SetCompression 设置是否协商 permessage-deflate 压缩（下次连接生效）
+func (c *GWClient) SetCompression(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.compression = enabled
+}
+
 // Start 启动客户端（后台运行）
 func (c *GWClient) Start() {
 	go c.connectLoop()
+	c.startKeepalive()
+}
+
+// startKeepalive 启动空闲保活循环（间隔为 0 时不启动）
+func (c *GWClient) startKeepalive() {
+	c.mu.Lock()
+	interval := c.keepaliveInterval
+	stopCh := c.stopCh
+	c.mu.Unlock()
+	if interval > 0 {
+		go c.keepaliveLoop(interval, stopCh)
+	}
+}
+
+// keepaliveLoop 连接空闲超过 interval 时发送 WebSocket ping；任何出站请求都会重置空闲计时
+func (c *GWClient) keepaliveLoop(interval time.Duration, stopCh chan struct{}) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-timer.C:
+		}
+		timer.Reset(c.keepaliveTick(interval))
+	}
+}
+
+// keepaliveTick 执行一次保活检查，返回距离下次检查的等待时间
+func (c *GWClient) keepaliveTick(interval time.Duration) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected || c.conn == nil {
+		return interval
+	}
+	if idle := time.Since(c.lastActivity); idle < interval {
+		return interval - idle
+	}
+	if err := c.conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(3*time.Second)); err != nil {
+		logger.Gateway.Debug().Err(err).Msg("空闲保活 ping 失败")
+	}
+	c.lastActivity = time.Now()
+	return interval
 }
 
 // Stop 停止客户端
@@ -341,6 +541,7 @@ func (c *GWClient) Reconnect(newCfg GWClientConfig) {
 		delete(c.pending, id)
 	}
 	// 如果之前已 Stop，需要重置
+	wasClosed := c.closed
 	if c.closed {
 		c.closed = false
 		c.stopCh = make(chan struct{})
@@ -352,6 +553,9 @@ func (c *GWClient) Reconnect(newCfg GWClientConfig) {
 
 	// 启动新的连接循环
 	go c.connectLoop()
+	if wasClosed {
+		c.startKeepalive()
+	}
 }
 
 // GetConfig 获取当前配置
@@ -368,10 +572,39 @@ func (c *GWClient) Request(method string, params interface{}) (json.RawMessage,
 
 // RequestWithTimeout 带超时的 RPC 请求
 func (c *GWClient) RequestWithTimeout(method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.RequestWithContext(ctx, method, params)
+}
+
+// RequestWithContext RPC 请求，ctx 取消或超时后停止等待响应（Gateway 侧的执行不会被中断）。
+// 每次调用计入 RPCStats；debug 级别下记录方法、耗时、结果与报文大小（不记录参数内容）
+func (c *GWClient) RequestWithContext(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	start := time.Now()
+	payload, sent, err := c.roundTrip(ctx, method, params)
+	elapsed := time.Since(start)
+	timeout := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	c.rpc.record(method, elapsed, sent, len(payload), err, timeout)
+
+	ev := logger.Gateway.Debug().
+		Str("method", method).
+		Dur("duration", elapsed).
+		Int("bytes_out", sent).
+		Int("bytes_in", len(payload)).
+		Bool("ok", err == nil)
+	if err != nil {
+		ev = ev.Err(err)
+	}
+	ev.Msg("gateway RPC")
+	return payload, err
+}
+
+// roundTrip 发送请求帧并等待响应，返回响应 payload 与发送的字节数
+func (c *GWClient) roundTrip(ctx context.Context, method string, params interface{}) (json.RawMessage, int, error) {
 	c.mu.Lock()
 	if !c.connected || c.conn == nil {
 		c.mu.Unlock()
-		return nil, errors.New("gateway 未连接")
+		return nil, 0, errors.New("gateway 未连接")
 	}
 
 	id := uuid.New().String()
@@ -388,40 +621,49 @@ func (c *GWClient) RequestWithTimeout(method string, params interface{}, timeout
 	if err != nil {
 		delete(c.pending, id)
 		c.mu.Unlock()
-		return nil, fmt.Errorf("序列化请求失败: %w", err)
+		return nil, 0, fmt.Errorf("序列化请求失败: %w", err)
 	}
 
 	err = c.conn.WriteMessage(websocket.TextMessage, data)
+	if err == nil {
+		c.lastActivity = time.Now()
+		if c.traffic != nil {
+			c.traffic.payloadOut.Add(int64(len(data)))
+		}
+	}
 	c.mu.Unlock()
 
 	if err != nil {
 		c.mu.Lock()
 		delete(c.pending, id)
 		c.mu.Unlock()
-		return nil, fmt.Errorf("发送请求失败: %w", err)
+		return nil, 0, fmt.Errorf("发送请求失败: %w", err)
 	}
 
 	// 等待响应
 	select {
 	case resp := <-ch:
 		if resp == nil {
-			return nil, errors.New("连接已关闭")
+			return nil, len(data), errors.New("连接已关闭")
 		}
 		if !resp.OK {
 			msg := "未知错误"
 			if resp.Error != nil {
 				msg = resp.Error.Message
 			}
-			return nil, fmt.Errorf("gateway 错误: %s", msg)
+			return nil, len(data), fmt.Errorf("gateway 错误: %s", msg)
 		}
-		return resp.Payload, nil
-	case <-time.After(timeout):
+		return resp.Payload, len(data), nil
+	case <-ctx.Done():
 		c.mu.Lock()
 		delete(c.pending, id)
 		c.mu.Unlock()
-		return nil, fmt.Errorf("请求超时: %s", method)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, len(data), fmt.Errorf("请求超时: %s", method)
+		}
+		return nil, len(data), fmt.Errorf("请求已取消: %s", method)
 	case <-c.stopCh:
-		return nil, errors.New("客户端已停止")
+		return nil, len(data), errors.New("客户端已停止")
 	}
 }
 
@@ -462,24 +704,52 @@ func (c *GWClient) dial() error {
 		Path:   "/",
 	}
 
+	c.mu.Lock()
+	compress := c.compression
+	c.mu.Unlock()
+
+	traffic := &wsTraffic{}
+	netDialer := &net.Dialer{Timeout: 5 * time.Second}
 	dialer := websocket.Dialer{
-		HandshakeTimeout: 5 * time.Second,
+		HandshakeTimeout:  5 * time.Second,
+		EnableCompression: compress,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := netDialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &countingConn{Conn: conn, traffic: traffic}, nil
+		},
 	}
 
-	conn, _, err := dialer.Dial(u.String(), nil)
+	conn, resp, err := dialer.Dial(u.String(), nil)
 	if err != nil {
 		return fmt.Errorf("WebSocket 拨号失败: %w", err)
 	}
+	negotiated := compressionNegotiated(resp)
+	if compress {
+		logger.Gateway.Debug().Bool("negotiated", negotiated).Msg("WebSocket permessage-deflate 协商结果")
+	}
 
 	c.mu.Lock()
 	c.conn = conn
+	c.traffic = traffic
 	c.mu.Unlock()
 
+	defer func() {
+		logger.Gateway.Debug().
+			Bool("compression", negotiated).
+			Int64("payloadBytes", traffic.payloadIn.Load()+traffic.payloadOut.Load()).
+			Int64("wireBytes", traffic.wireIn.Load()+traffic.wireOut.Load()).
+			Int64("savedBytes", traffic.saved()).
+			Msg("Gateway WebSocket 连接流量统计")
+	}()
+
 	// 读取消息循环
-	return c.readLoop(conn)
+	return c.readLoop(conn, traffic)
 }
 
-func (c *GWClient) readLoop(conn *websocket.Conn) error {
+func (c *GWClient) readLoop(conn *websocket.Conn, traffic *wsTraffic) error {
 	defer func() {
 		c.mu.Lock()
 		c.connected = false
@@ -503,6 +773,7 @@ func (c *GWClient) readLoop(conn *websocket.Conn) error {
 		if err != nil {
 			return fmt.Errorf("读取消息失败: %w", err)
 		}
+		traffic.payloadIn.Add(int64(len(message)))
 
 		var raw map[string]json.RawMessage
 		if err := json.Unmarshal(message, &raw); err != nil {
@@ -522,6 +793,10 @@ func (c *GWClient) readLoop(conn *websocket.Conn) error {
 				var payload struct {
 					Nonce string `json:"nonce"`
 				}
+				// challenge 中可能携带 Gateway 支持的协议版本，提前识别版本不匹配
+				if hint, ok := parseProtocolHint(evt.Payload); ok && !hint.Overlaps() {
+					c.recordProtocolMismatch(hint)
+				}
 				if err := json.Unmarshal(evt.Payload, &payload); err == nil && payload.Nonce != "" {
 					connectNonce = payload.Nonce
 					if !connectSent {
@@ -537,10 +812,11 @@ func (c *GWClient) readLoop(conn *websocket.Conn) error {
 				continue
 			}
 
-			// 其他事件 → 回调
+			// 其他事件 → 回调与订阅者
 			if c.onEvent != nil {
 				c.onEvent(evt.Event, evt.Payload)
 			}
+			c.eventSubs.publish(GWEvent{Event: evt.Event, Payload: evt.Payload})
 			continue
 		}
 
@@ -558,6 +834,7 @@ func (c *GWClient) readLoop(conn *websocket.Conn) error {
 				}
 				if json.Unmarshal(resp.Payload, &ack) == nil && ack.Status == "accepted" {
 					// 等待最终响应
+					c.markAccepted(resp.ID)
 					continue
 				}
 			}
@@ -578,93 +855,28 @@ func (c *GWClient) readLoop(conn *websocket.Conn) error {
 }
 
 func (c *GWClient) sendConnect(conn *websocket.Conn, nonce string) {
-	params := ConnectParams{
-		MinProtocol: 3,
-		MaxProtocol: 3,
-		Client: ConnectClient{
-			ID:          "gateway-client",
-			DisplayName: "OpenClawDeck",
-			Version:     "0.2.0",
-			Platform:    "go",
-			Mode:        "backend",
-		},
-		Role:   "operator",
-		Scopes: []string{"operator.admin"},
-		Caps:   []string{},
-	}
-
 	// 如果 token 为空，尝试从 openclaw.json 自动读取
+	c.mu.Lock()
 	token := c.cfg.Token
+	c.mu.Unlock()
 	if token == "" {
 		configPath := ResolveConfigPath()
 		logger.Log.Debug().Str("configPath", configPath).Msg("GWClient token 为空，尝试从 openclaw.json 读取")
-		if t := readGatewayTokenFromConfig(); t != "" {
+		if t, src := DiscoverGatewayToken(""); t != "" {
 			token = t
 			c.mu.Lock()
 			c.cfg.Token = token
 			c.mu.Unlock()
-			logger.Log.Info().Msg("从 openclaw.json 自动读取到 gateway auth token")
+			logger.Log.Info().Str("source", src).Msg("从 openclaw.json 自动读取到 gateway auth token")
+		} else if t := c.cachedToken(); t != "" {
+			// 配置文件可能正在被重写（向导等），暂用上次鉴权成功的 token；不写回 cfg，下次重连仍优先读配置
+			token = t
+			logger.Log.Warn().Str("configPath", configPath).Msg("未能从 openclaw.json 读取到 gateway auth token，回退使用上次鉴权成功的 token")
 		} else {
 			logger.Log.Warn().Str("configPath", configPath).Msg("未能从 openclaw.json 读取到 gateway auth token，RPC 请求可能被拒绝")
 		}
 	}
-	if token != "" {
-		params.Auth = &ConnectAuth{
-			Token: token,
-		}
-	} else {
-		logger.Log.Warn().Msg("GWClient 无 auth token，将以无认证方式连接 Gateway")
-	}
-
-	// 加载或生成 device identity
-	identity, err := LoadOrCreateDeviceIdentity("")
-	if err != nil {
-		logger.Log.Error().Err(err).Msg("加载 device identity 失败")
-	} else {
-		// 构建 device auth payload
-		signedAt := time.Now().UnixMilli()
-		scopesStr := ""
-		if len(params.Scopes) > 0 {
-			scopesStr = strings.Join(params.Scopes, ",")
-		}
-
-		// 构建 payload: version|deviceId|clientId|clientMode|role|scopes|signedAtMs|token|nonce
-		payloadParts := []string{
-			"v2",
-			identity.DeviceID,
-			params.Client.ID,
-			params.Client.Mode,
-			params.Role,
-			scopesStr,
-			fmt.Sprintf("%d", signedAt),
-			token,
-			nonce,
-		}
-		payload := strings.Join(payloadParts, "|")
-
-		// 签名
-		signature, err := SignDevicePayload(identity.PrivateKeyPem, payload)
-		if err != nil {
-			logger.Log.Error().Err(err).Msg("签名 device payload 失败")
-		} else {
-			// 获取公钥的 base64url 编码
-			publicKeyBase64URL, err := PublicKeyRawBase64URLFromPem(identity.PublicKeyPem)
-			if err != nil {
-				logger.Log.Error().Err(err).Msg("编码公钥失败")
-			} else {
-				params.Device = &ConnectDevice{
-					ID:        identity.DeviceID,
-					PublicKey: publicKeyBase64URL,
-					Signature: signature,
-					SignedAt:  signedAt,
-					Nonce:     nonce,
-				}
-				logger.Log.Debug().
-					Str("deviceId", identity.DeviceID).
-					Msg("已添加 device identity 到 connect 请求")
-			}
-		}
-	}
+	params := buildConnectParams(token, nonce)
 
 	logger.Log.Debug().
 		Bool("hasToken", token != "").
@@ -678,7 +890,10 @@ func (c *GWClient) sendConnect(conn *websocket.Conn, nonce string) {
 
 	c.mu.Lock()
 	c.pending[id] = ch
+	c.connectID = id
+	c.connectAcceptedAt = time.Time{}
 	c.mu.Unlock()
+	defer c.endConnectWait(id)
 
 	frame := RequestFrame{
 		Type:   "req",
@@ -704,7 +919,15 @@ func (c *GWClient) sendConnect(conn *websocket.Conn, nonce string) {
 			c.mu.Lock()
 			c.connected = true
 			c.backoffMs = 1000
+			c.lastActivity = time.Now()
+			c.protocol = GWProtocolMax
+			if hint, ok := parseProtocolHint(resp.Payload); ok && hint.Min == hint.Max {
+				c.protocol = hint.Min
+			}
+			c.protocolMismatch = ""
+			c.lastConnectError = ""
 			c.mu.Unlock()
+			c.rememberToken(token)
 			logger.Log.Info().
 				Str("host", c.cfg.Host).
 				Int("port", c.cfg.Port).
@@ -713,40 +936,110 @@ func (c *GWClient) sendConnect(conn *websocket.Conn, nonce string) {
 			msg := "未知错误"
 			if resp != nil && resp.Error != nil {
 				msg = resp.Error.Message
+				if hint, ok := protocolHintFromError(resp.Error); ok && !hint.Overlaps() {
+					// 版本不匹配时 Gateway 返回的错误看起来像鉴权失败，明确给出原因
+					c.recordProtocolMismatch(hint)
+					msg = ProtocolMismatchMessage(hint) + " (" + msg + ")"
+				}
 			}
+			c.mu.Lock()
+			c.lastConnectError = msg
+			c.mu.Unlock()
 			logger.Log.Error().Str("error", msg).Msg("Gateway WS 连接鉴权失败")
 			conn.Close()
 		}
 	case <-time.After(10 * time.Second):
-		logger.Log.Error().Msg("Gateway WS connect 超时")
+		if c.endConnectWait(id) {
+			// 部分 Gateway 版本只回 accepted 不回最终响应，与普通超时区分开便于排查
+			msg := "Gateway 已接受 connect 但未返回最终响应（鉴权挂起）"
+			c.mu.Lock()
+			c.acceptedNoFinal++
+			c.lastConnectError = msg
+			c.mu.Unlock()
+			logger.Gateway.Error().Str("host", c.cfg.Host).Int("port", c.cfg.Port).Msg(msg)
+		} else {
+			logger.Log.Error().Msg("Gateway WS connect 超时")
+		}
 		conn.Close()
 	case <-c.stopCh:
 		return
 	}
 }
 
-// readGatewayTokenFromConfig 从 openclaw.json 读取 gateway.auth.token
-func readGatewayTokenFromConfig() string {
-	configPath := ResolveConfigPath()
-	if configPath == "" {
-		return ""
-	}
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return ""
-	}
-	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return ""
+// buildConnectParams 构建 connect 请求参数（token 鉴权 + device identity 签名）
+func buildConnectParams(token, nonce string) ConnectParams {
+	params := ConnectParams{
+		MinProtocol: GWProtocolMin,
+		MaxProtocol: GWProtocolMax,
+		Client: ConnectClient{
+			ID:          "gateway-client",
+			DisplayName: "OpenClawDeck",
+			Version:     "0.2.0",
+			Platform:    "go",
+			Mode:        "backend",
+		},
+		Role:   "operator",
+		Scopes: []string{"operator.admin"},
+		Caps:   []string{},
 	}
-	gw, ok := raw["gateway"].(map[string]interface{})
-	if !ok {
-		return ""
+
+	if token != "" {
+		params.Auth = &ConnectAuth{
+			Token: token,
+		}
+	} else {
+		logger.Log.Warn().Msg("GWClient 无 auth token，将以无认证方式连接 Gateway")
 	}
-	auth, ok := gw["auth"].(map[string]interface{})
-	if !ok {
-		return ""
+
+	// 加载或生成 device identity
+	identity, err := LoadOrCreateDeviceIdentity("")
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("加载 device identity 失败")
+	} else {
+		// 构建 device auth payload
+		signedAt := time.Now().UnixMilli()
+		scopesStr := ""
+		if len(params.Scopes) > 0 {
+			scopesStr = strings.Join(params.Scopes, ",")
+		}
+
+		// 构建 payload: version|deviceId|clientId|clientMode|role|scopes|signedAtMs|token|nonce
+		payloadParts := []string{
+			"v2",
+			identity.DeviceID,
+			params.Client.ID,
+			params.Client.Mode,
+			params.Role,
+			scopesStr,
+			fmt.Sprintf("%d", signedAt),
+			token,
+			nonce,
+		}
+		payload := strings.Join(payloadParts, "|")
+
+		// 签名
+		signature, err := SignDevicePayload(identity.PrivateKeyPem, payload)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("签名 device payload 失败")
+		} else {
+			// 获取公钥的 base64url 编码
+			publicKeyBase64URL, err := PublicKeyRawBase64URLFromPem(identity.PublicKeyPem)
+			if err != nil {
+				logger.Log.Error().Err(err).Msg("编码公钥失败")
+			} else {
+				params.Device = &ConnectDevice{
+					ID:        identity.DeviceID,
+					PublicKey: publicKeyBase64URL,
+					Signature: signature,
+					SignedAt:  signedAt,
+					Nonce:     nonce,
+				}
+				logger.Log.Debug().
+					Str("deviceId", identity.DeviceID).
+					Msg("已添加 device identity 到 connect 请求")
+			}
+		}
 	}
-	token, _ := auth["token"].(string)
-	return token
+
+	return params
 }
@@ -0,0 +1,108 @@
+package openclaw
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxRPCErrorLen 统计中保留的错误信息最大长度
+const maxRPCErrorLen = 200
+
+// RPCMethodStats 单个 RPC 方法的调用统计
+type RPCMethodStats struct {
+	Method    string    `json:"method"`
+	Calls     int64     `json:"calls"`
+	Errors    int64     `json:"errors"`
+	Timeouts  int64     `json:"timeouts"`
+	TotalMs   int64     `json:"total_ms"`
+	AvgMs     int64     `json:"avg_ms"`
+	MaxMs     int64     `json:"max_ms"`
+	LastMs    int64     `json:"last_ms"`
+	BytesOut  int64     `json:"bytes_out"`
+	BytesIn   int64     `json:"bytes_in"`
+	LastAt    time.Time `json:"last_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// rpcStats 按方法聚合的 RPC 调用统计（进程内，重启清零）
+type rpcStats struct {
+	mu      sync.Mutex
+	since   time.Time
+	methods map[string]*RPCMethodStats
+}
+
+// record 记录一次调用
+func (s *rpcStats) record(method string, d time.Duration, out, in int, err error, timeout bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.methods == nil {
+		s.methods = make(map[string]*RPCMethodStats)
+		s.since = time.Now()
+	}
+	m := s.methods[method]
+	if m == nil {
+		m = &RPCMethodStats{Method: method}
+		s.methods[method] = m
+	}
+	ms := d.Milliseconds()
+	m.Calls++
+	m.TotalMs += ms
+	m.LastMs = ms
+	if ms > m.MaxMs {
+		m.MaxMs = ms
+	}
+	m.BytesOut += int64(out)
+	m.BytesIn += int64(in)
+	m.LastAt = time.Now()
+	if err != nil {
+		m.Errors++
+		if timeout {
+			m.Timeouts++
+		}
+		msg := err.Error()
+		if len(msg) > maxRPCErrorLen {
+			msg = msg[:maxRPCErrorLen] + "…"
+		}
+		m.LastError = msg
+	}
+}
+
+// snapshot 返回统计副本，按累计耗时降序
+func (s *rpcStats) snapshot() ([]RPCMethodStats, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RPCMethodStats, 0, len(s.methods))
+	for _, m := range s.methods {
+		cp := *m
+		if cp.Calls > 0 {
+			cp.AvgMs = cp.TotalMs / cp.Calls
+		}
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalMs != out[j].TotalMs {
+			return out[i].TotalMs > out[j].TotalMs
+		}
+		return out[i].Method < out[j].Method
+	})
+	return out, s.since
+}
+
+// reset 清空统计
+func (s *rpcStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods = nil
+	s.since = time.Time{}
+}
+
+// RPCStats 返回各 RPC 方法的调用统计及统计起始时间
+func (c *GWClient) RPCStats() ([]RPCMethodStats, time.Time) {
+	return c.rpc.snapshot()
+}
+
+// ResetRPCStats 清空 RPC 调用统计
+func (c *GWClient) ResetRPCStats() {
+	c.rpc.reset()
+}
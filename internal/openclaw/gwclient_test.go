@@ -2,7 +2,9 @@ package openclaw
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -56,15 +58,24 @@ func TestGWClient_SetNotifyCallback(t *testing.T) {
 	client := NewGWClient(GWClientConfig{})
 
 	var receivedMsg string
-	callback := func(msg string) {
-		receivedMsg = msg
+	callback := func(risk, msg string) {
+		receivedMsg = risk + ": " + msg
 	}
 
 	client.SetNotifyCallback(callback)
 	assert.NotNil(t, client.onNotify)
 
-	client.onNotify("test message")
-	assert.Equal(t, "test message", receivedMsg)
+	client.onNotify("low", "test message")
+	assert.Equal(t, "low: test message", receivedMsg)
+}
+
+func TestHealthRestart_FailureIsCritical(t *testing.T) {
+	var risks []string
+	record := func(risk, text string) { risks = append(risks, risk) }
+
+	healthRestart(func() error { return errors.New("port in use") }, record)
+	healthRestart(func() error { return nil }, record)
+	assert.Equal(t, []string{"critical", "medium"}, risks)
 }
 
 func TestGWClient_HealthStatus(t *testing.T) {
@@ -140,3 +151,104 @@ func TestGWClientConfig(t *testing.T) {
 	assert.Equal(t, 18789, cfg.Port)
 	assert.Equal(t, "secret-token", cfg.Token)
 }
+
+func TestParseProtocolHint(t *testing.T) {
+	hint, ok := parseProtocolHint(json.RawMessage(`{"nonce":"n","minProtocol":4,"maxProtocol":5}`))
+	assert.True(t, ok)
+	assert.Equal(t, ProtocolHint{Min: 4, Max: 5}, hint)
+	assert.False(t, hint.Overlaps())
+
+	hint, ok = parseProtocolHint(json.RawMessage(`{"details":{"supportedProtocols":[3,4]}}`))
+	assert.True(t, ok)
+	assert.Equal(t, ProtocolHint{Min: 3, Max: 4}, hint)
+	assert.True(t, hint.Overlaps())
+
+	_, ok = parseProtocolHint(json.RawMessage(`{"nonce":"n"}`))
+	assert.False(t, ok)
+}
+
+func TestProtocolHintFromError(t *testing.T) {
+	hint, ok := protocolHintFromError(&RPCError{Message: "unsupported protocol: client 3, server requires 4"})
+	assert.True(t, ok)
+	assert.Equal(t, ProtocolHint{Min: 4, Max: 4}, hint)
+	assert.Equal(t, "protocol mismatch: deck supports 3, gateway requires 4", ProtocolMismatchMessage(hint))
+
+	_, ok = protocolHintFromError(&RPCError{Message: "invalid token"})
+	assert.False(t, ok)
+}
+
+func TestGWClient_SubscribeEndsOnOverflow(t *testing.T) {
+	c := NewGWClient(GWClientConfig{})
+	sub := c.Subscribe(1)
+	other := c.Subscribe(4)
+	c.eventSubs.publish(GWEvent{Event: "chat"})
+	c.eventSubs.publish(GWEvent{Event: "agent"}) // buffer full: must not block
+
+	evt := <-sub.C
+	assert.Equal(t, "chat", evt.Event)
+	_, ok := <-sub.C
+	assert.False(t, ok, "an overflowed subscription is closed")
+	assert.True(t, sub.Overflowed())
+	sub.Close()
+
+	// other subscribers keep receiving
+	assert.Equal(t, "chat", (<-other.C).Event)
+	assert.Equal(t, "agent", (<-other.C).Event)
+	assert.False(t, other.Overflowed())
+	other.Close()
+	other.Close()
+	_, ok = <-other.C
+	assert.False(t, ok, "channel should be closed after Close")
+	assert.False(t, other.Overflowed())
+	c.eventSubs.publish(GWEvent{Event: "chat"})
+}
+
+func TestGWClient_ConnectAcceptedPending(t *testing.T) {
+	c := NewGWClient(GWClientConfig{})
+	c.connectID = "c1"
+
+	c.markAccepted("other")
+	assert.False(t, c.Stats().AuthPending)
+
+	c.markAccepted("c1")
+	assert.True(t, c.Stats().AuthPending)
+
+	assert.True(t, c.endConnectWait("c1"))
+	assert.False(t, c.Stats().AuthPending)
+	assert.False(t, c.endConnectWait("c1"))
+}
+
+func TestGWClient_RPCStats(t *testing.T) {
+	client := NewGWClient(GWClientConfig{})
+
+	// 未连接的请求也计入统计
+	_, err := client.Request("health", map[string]interface{}{"token": "secret"})
+	assert.Error(t, err)
+
+	client.rpc.record("sessions.list", 40*time.Millisecond, 100, 2048, nil, false)
+	client.rpc.record("sessions.list", 20*time.Millisecond, 100, 1024, nil, false)
+	client.rpc.record("chat.send", 90*time.Millisecond, 50, 0, errors.New("请求超时: chat.send"), true)
+
+	stats, since := client.RPCStats()
+	assert.False(t, since.IsZero())
+	if assert.Len(t, stats, 3) {
+		// 按累计耗时降序
+		assert.Equal(t, "chat.send", stats[0].Method)
+		assert.Equal(t, int64(1), stats[0].Timeouts)
+		assert.Equal(t, "请求超时: chat.send", stats[0].LastError)
+
+		assert.Equal(t, "sessions.list", stats[1].Method)
+		assert.Equal(t, int64(2), stats[1].Calls)
+		assert.Equal(t, int64(30), stats[1].AvgMs)
+		assert.Equal(t, int64(40), stats[1].MaxMs)
+		assert.Equal(t, int64(3072), stats[1].BytesIn)
+
+		assert.Equal(t, "health", stats[2].Method)
+		assert.Equal(t, int64(1), stats[2].Errors)
+		assert.NotContains(t, stats[2].LastError, "secret")
+	}
+
+	client.ResetRPCStats()
+	stats, _ = client.RPCStats()
+	assert.Empty(t, stats)
+}
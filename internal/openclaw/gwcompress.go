@@ -0,0 +1,48 @@
+package openclaw
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// GatewayCompressionSetting 设置项：Gateway WebSocket 是否协商 permessage-deflate 压缩，
+// 默认启用；部分代理处理压缩帧有问题，设为 "false" 可关闭（下次连接生效）
+const GatewayCompressionSetting = "gateway_ws_compression"
+
+// wsTraffic 单条连接的流量统计：wire 为 TCP 实际收发字节，payload 为解压后的消息字节
+type wsTraffic struct {
+	wireIn     atomic.Int64
+	wireOut    atomic.Int64
+	payloadIn  atomic.Int64
+	payloadOut atomic.Int64
+}
+
+// saved 压缩节省的字节数（含握手与帧头开销，未压缩时可能为负）
+func (t *wsTraffic) saved() int64 {
+	return t.payloadIn.Load() + t.payloadOut.Load() - t.wireIn.Load() - t.wireOut.Load()
+}
+
+// countingConn 统计底层连接收发字节
+type countingConn struct {
+	net.Conn
+	traffic *wsTraffic
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.traffic.wireIn.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.traffic.wireOut.Add(int64(n))
+	return n, err
+}
+
+// compressionNegotiated 握手响应是否接受了 permessage-deflate
+func compressionNegotiated(resp *http.Response) bool {
+	return resp != nil && strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+}
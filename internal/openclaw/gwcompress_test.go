@@ -0,0 +1,58 @@
+package openclaw
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"openclawdeck/internal/database"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionSetting(t *testing.T) {
+	def, ok := database.LookupSetting(GatewayCompressionSetting)
+	require.True(t, ok)
+	assert.Equal(t, "true", def.Default)
+	v, err := database.ValidateSetting(GatewayCompressionSetting, " FALSE ")
+	require.NoError(t, err)
+	assert.Equal(t, "false", v)
+}
+
+func TestCompressionNegotiatedAndCounted(t *testing.T) {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	big := strings.Repeat(`{"role":"assistant","content":"hello"}`, 500)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(big))
+	}))
+	defer srv.Close()
+
+	traffic := &wsTraffic{}
+	dialer := websocket.Dialer{EnableCompression: true}
+	dialer.NetDial = func(network, addr string) (net.Conn, error) {
+		conn, err := net.Dial(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &countingConn{Conn: conn, traffic: traffic}, nil
+	}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.True(t, compressionNegotiated(resp))
+
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	traffic.payloadIn.Add(int64(len(msg)))
+	assert.Equal(t, big, string(msg))
+	assert.Greater(t, traffic.saved(), int64(len(big)/2))
+}
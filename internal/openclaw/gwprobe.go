@@ -0,0 +1,150 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// WS 握手探测各阶段超时
+const (
+	probeDialTimeout  = 3 * time.Second
+	probeFrameTimeout = 8 * time.Second
+)
+
+// ProbeGatewayHandshake 用一次性连接完整走一遍 WS 握手（TCP → 升级 → challenge → connect），
+// 逐阶段返回诊断项；某阶段失败后，后续阶段标记为跳过。token 为空时读取 openclaw.json。
+func ProbeGatewayHandshake(host string, port int, token string) []DiagnoseItem {
+	if token == "" {
+		token, _ = DiscoverGatewayToken("")
+	}
+	addr := net.JoinHostPort(host, fmt.Sprint(port))
+
+	stages := []DiagnoseItem{
+		{Name: "ws_tcp", Label: "WS 握手：TCP 连接", LabelEn: "WS Handshake: TCP Connect"},
+		{Name: "ws_upgrade", Label: "WS 握手：协议升级", LabelEn: "WS Handshake: Upgrade"},
+		{Name: "ws_challenge", Label: "WS 握手：Challenge", LabelEn: "WS Handshake: Challenge"},
+		{Name: "ws_protocol", Label: "WS 握手：协议版本", LabelEn: "WS Handshake: Protocol Version"},
+		{Name: "ws_auth", Label: "WS 握手：鉴权", LabelEn: "WS Handshake: Auth"},
+	}
+	done := 0
+	pass := func(detail string) {
+		stages[done].Status, stages[done].Detail = DiagnosePass, detail
+		done++
+	}
+	fail := func(detail, suggestion string) []DiagnoseItem {
+		stages[done].Status, stages[done].Detail, stages[done].Suggestion = DiagnoseFail, detail, suggestion
+		for i := done + 1; i < len(stages); i++ {
+			stages[i].Status, stages[i].Detail = DiagnoseSkip, "跳过：前一阶段失败"
+		}
+		return stages
+	}
+
+	// 1. TCP
+	tcp, err := net.DialTimeout("tcp", addr, probeDialTimeout)
+	if err != nil {
+		return fail(fmt.Sprintf("%s 连接失败: %v", addr, err),
+			"网络不通或 Gateway 未监听该地址：检查 Gateway 是否启动、bind 是否允许远程访问、防火墙/安全组是否放行端口")
+	}
+	tcp.Close()
+	pass(addr + " TCP 连接成功")
+
+	// 2. WebSocket 升级
+	dialer := websocket.Dialer{HandshakeTimeout: probeDialTimeout}
+	u := url.URL{Scheme: "ws", Host: addr, Path: "/"}
+	conn, resp, err := dialer.Dial(u.String(), nil)
+	if err != nil {
+		detail := fmt.Sprintf("WebSocket 升级失败: %v", err)
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			detail = fmt.Sprintf("WebSocket 升级被拒绝（HTTP %d）", resp.StatusCode)
+		}
+		return fail(detail, "端口上的服务不是 OpenClaw Gateway，或中间的反向代理未转发 WebSocket（需要 Upgrade/Connection 头）")
+	}
+	defer conn.Close()
+	pass("WebSocket 升级成功")
+
+	// 3. 等待 connect.challenge
+	conn.SetReadDeadline(time.Now().Add(probeFrameTimeout))
+	var nonce string
+	var challengePayload json.RawMessage
+	for nonce == "" {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return fail(fmt.Sprintf("未收到 connect.challenge: %v", err),
+				"Gateway 版本过旧或不是 OpenClaw Gateway，请升级 OpenClaw")
+		}
+		var evt EventFrame
+		if json.Unmarshal(msg, &evt) != nil || evt.Event != "connect.challenge" {
+			continue
+		}
+		var payload struct {
+			Nonce string `json:"nonce"`
+		}
+		if json.Unmarshal(evt.Payload, &payload) == nil {
+			nonce = payload.Nonce
+		}
+		challengePayload = evt.Payload
+		if nonce == "" {
+			return fail("connect.challenge 缺少 nonce", "Gateway 协议异常，请升级 OpenClaw")
+		}
+	}
+	pass("已收到 connect.challenge")
+
+	// 4. challenge 中携带的协议版本
+	if hint, ok := parseProtocolHint(challengePayload); ok && !hint.Overlaps() {
+		return fail(ProtocolMismatchMessage(hint), "Deck 与 Gateway 协议版本不兼容，请升级较旧的一方")
+	}
+
+	// 5. 发送 connect 并等待最终响应（跳过 accepted 中间确认）
+	id := uuid.New().String()
+	frame := RequestFrame{Type: "req", ID: id, Method: "connect", Params: buildConnectParams(token, nonce)}
+	data, _ := json.Marshal(frame)
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fail(fmt.Sprintf("发送 connect 失败: %v", err), "连接被中途关闭，检查网络或代理")
+	}
+	conn.SetReadDeadline(time.Now().Add(probeFrameTimeout))
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return fail(fmt.Sprintf("未收到 connect 响应: %v", err), "Gateway 可能因鉴权失败直接断开连接，请检查 Token")
+		}
+		var res ResponseFrame
+		if json.Unmarshal(msg, &res) != nil || res.ID != id {
+			continue
+		}
+		if res.OK {
+			var ack struct {
+				Status string `json:"status"`
+			}
+			if json.Unmarshal(res.Payload, &ack) == nil && ack.Status == "accepted" {
+				continue
+			}
+			if hint, ok := parseProtocolHint(res.Payload); ok && !hint.Overlaps() {
+				return fail(ProtocolMismatchMessage(hint), "Deck 与 Gateway 协议版本不兼容，请升级较旧的一方")
+			}
+			pass("协议版本兼容")
+			pass("connect 鉴权通过")
+			return stages
+		}
+
+		if hint, ok := protocolHintFromError(res.Error); ok && !hint.Overlaps() {
+			return fail(ProtocolMismatchMessage(hint), "Deck 与 Gateway 协议版本不兼容，请升级较旧的一方")
+		}
+		pass("协议版本兼容")
+		errMsg := "未知错误"
+		if res.Error != nil {
+			errMsg = res.Error.Message
+		}
+		suggestion := "Token 与 Gateway 的 gateway.auth.token 不一致，请在 Gateway 设置中更新 Token"
+		if token == "" {
+			suggestion = "Gateway 要求鉴权但未提供 Token，请填写 gateway.auth.token"
+		}
+		return fail("connect 被拒绝: "+errMsg, suggestion)
+	}
+}
@@ -0,0 +1,112 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGateway 模拟 Gateway 握手：发送 challenge，按 token 决定接受或拒绝 connect
+func fakeGateway(t *testing.T, wantToken string) (host string, port int) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteJSON(map[string]any{"event": "connect.challenge", "payload": map[string]any{"nonce": "n1"}})
+
+		var req struct {
+			ID     string        `json:"id"`
+			Params ConnectParams `json:"params"`
+		}
+		if conn.ReadJSON(&req) != nil {
+			return
+		}
+		token := ""
+		if req.Params.Auth != nil {
+			token = req.Params.Auth.Token
+		}
+		if token == wantToken {
+			conn.WriteJSON(map[string]any{"id": req.ID, "ok": true, "payload": map[string]any{"type": "hello-ok"}})
+			return
+		}
+		conn.WriteJSON(map[string]any{"id": req.ID, "ok": false, "error": map[string]any{"code": 401, "message": "unauthorized"}})
+	}))
+	t.Cleanup(srv.Close)
+
+	h, p, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	port, _ = strconv.Atoi(p)
+	return h, port
+}
+
+func probeStatuses(items []DiagnoseItem) map[string]DiagnoseItemStatus {
+	out := map[string]DiagnoseItemStatus{}
+	for _, it := range items {
+		out[it.Name] = it.Status
+	}
+	return out
+}
+
+func TestProbeGatewayHandshake(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	host, port := fakeGateway(t, "good")
+
+	ok := probeStatuses(ProbeGatewayHandshake(host, port, "good"))
+	assert.Equal(t, DiagnosePass, ok["ws_tcp"])
+	assert.Equal(t, DiagnosePass, ok["ws_upgrade"])
+	assert.Equal(t, DiagnosePass, ok["ws_challenge"])
+	assert.Equal(t, DiagnosePass, ok["ws_protocol"])
+	assert.Equal(t, DiagnosePass, ok["ws_auth"])
+
+	denied := probeStatuses(ProbeGatewayHandshake(host, port, "bad"))
+	assert.Equal(t, DiagnosePass, denied["ws_protocol"])
+	assert.Equal(t, DiagnoseFail, denied["ws_auth"])
+}
+
+func TestProbeGatewayHandshake_NotWebSocket(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	h, p, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(p)
+
+	items := ProbeGatewayHandshake(h, port, "x")
+	st := probeStatuses(items)
+	assert.Equal(t, DiagnosePass, st["ws_tcp"])
+	assert.Equal(t, DiagnoseFail, st["ws_upgrade"])
+	assert.Equal(t, DiagnoseSkip, st["ws_auth"])
+	assert.Contains(t, items[1].Detail, "404")
+}
+
+func TestProbeGatewayHandshake_ProtocolMismatch(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		payload, _ := json.Marshal(map[string]any{"nonce": "n1", "minProtocol": 9, "maxProtocol": 9})
+		conn.WriteJSON(map[string]any{"event": "connect.challenge", "payload": json.RawMessage(payload)})
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+	h, p, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(p)
+
+	st := probeStatuses(ProbeGatewayHandshake(h, port, "x"))
+	assert.Equal(t, DiagnosePass, st["ws_challenge"])
+	assert.Equal(t, DiagnoseFail, st["ws_protocol"])
+	assert.Equal(t, DiagnoseSkip, st["ws_auth"])
+}
@@ -0,0 +1,129 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Deck 支持的 Gateway WS 协议版本范围
+const (
+	GWProtocolMin = 3
+	GWProtocolMax = 3
+)
+
+// ProtocolHint Gateway 在 challenge / 拒绝响应中给出的协议版本信息
+type ProtocolHint struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Overlaps 判断 Gateway 支持的范围是否与 Deck 有交集
+func (h ProtocolHint) Overlaps() bool {
+	return h.Min <= GWProtocolMax && h.Max >= GWProtocolMin
+}
+
+// String 返回 "3" 或 "3-4" 形式的版本范围
+func (h ProtocolHint) String() string {
+	return protocolRange(h.Min, h.Max)
+}
+
+func protocolRange(min, max int) string {
+	if min == max {
+		return strconv.Itoa(min)
+	}
+	return fmt.Sprintf("%d-%d", min, max)
+}
+
+// ProtocolMismatchMessage 生成可读的协议不匹配说明
+func ProtocolMismatchMessage(h ProtocolHint) string {
+	return fmt.Sprintf("protocol mismatch: deck supports %s, gateway requires %s",
+		protocolRange(GWProtocolMin, GWProtocolMax), h.String())
+}
+
+// parseProtocolHint 从 payload 中提取协议版本信息，兼容
+// protocol / minProtocol / maxProtocol / supportedProtocols 字段（也会查找 details 子对象）
+func parseProtocolHint(payload json.RawMessage) (ProtocolHint, bool) {
+	if len(payload) == 0 {
+		return ProtocolHint{}, false
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return ProtocolHint{}, false
+	}
+
+	hint := ProtocolHint{}
+	intField := func(key string) int {
+		var n int
+		if v, ok := raw[key]; ok && json.Unmarshal(v, &n) == nil {
+			return n
+		}
+		return 0
+	}
+	if p := intField("protocol"); p > 0 {
+		hint.Min, hint.Max = p, p
+	}
+	if p := intField("minProtocol"); p > 0 {
+		hint.Min = p
+	}
+	if p := intField("maxProtocol"); p > 0 {
+		hint.Max = p
+	}
+	if v, ok := raw["supportedProtocols"]; ok {
+		var list []int
+		if json.Unmarshal(v, &list) == nil {
+			for _, p := range list {
+				if hint.Min == 0 || p < hint.Min {
+					hint.Min = p
+				}
+				if p > hint.Max {
+					hint.Max = p
+				}
+			}
+		}
+	}
+	if hint.Min == 0 && hint.Max == 0 {
+		if details, ok := raw["details"]; ok {
+			return parseProtocolHint(details)
+		}
+		return ProtocolHint{}, false
+	}
+	if hint.Min == 0 {
+		hint.Min = hint.Max
+	}
+	if hint.Max == 0 {
+		hint.Max = hint.Min
+	}
+	return hint, true
+}
+
+var protocolNumRe = regexp.MustCompile(`\d+`)
+
+// protocolHintFromError 从拒绝错误中推断协议版本（优先 details，其次解析错误消息中的数字）
+func protocolHintFromError(e *RPCError) (ProtocolHint, bool) {
+	if e == nil {
+		return ProtocolHint{}, false
+	}
+	if hint, ok := parseProtocolHint(e.Details); ok {
+		return hint, true
+	}
+	if !strings.Contains(strings.ToLower(e.Message), "protocol") {
+		return ProtocolHint{}, false
+	}
+	hint := ProtocolHint{}
+	for _, s := range protocolNumRe.FindAllString(e.Message, -1) {
+		n, err := strconv.Atoi(s)
+		if err != nil || (n >= GWProtocolMin && n <= GWProtocolMax) {
+			continue
+		}
+		if hint.Min == 0 || n < hint.Min {
+			hint.Min = n
+		}
+		if n > hint.Max {
+			hint.Max = n
+		}
+	}
+	return hint, hint.Min > 0
+}
@@ -0,0 +1,84 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// GWEvent Gateway 推送的事件
+type GWEvent struct {
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// eventSubs 事件订阅者集合，与 onEvent 回调并存，供按请求分流的流式代理使用
+type eventSubs struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]*GWSubscription
+}
+
+// GWSubscription 一个事件订阅；C 在 Close 或缓冲溢出后关闭
+type GWSubscription struct {
+	C <-chan GWEvent
+
+	ch         chan GWEvent
+	set        *eventSubs
+	id         int
+	overflowed bool // 受 set.mu 保护
+	once       sync.Once
+}
+
+// Subscribe 订阅 Gateway 事件（不含 connect.challenge / tick）。
+// 订阅者消费不及时（缓冲已满）时不阻塞读循环，而是结束该订阅：关闭 C 并标记溢出，
+// 由调用方通过 Overflowed 区分溢出与正常关闭，避免静默丢失事件
+func (c *GWClient) Subscribe(buffer int) *GWSubscription {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	ch := make(chan GWEvent, buffer)
+	s := &c.eventSubs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		s.subs = make(map[int]*GWSubscription)
+	}
+	sub := &GWSubscription{C: ch, ch: ch, set: s, id: s.next}
+	s.next++
+	s.subs[sub.id] = sub
+	return sub
+}
+
+// Close 取消订阅并关闭 C（可重复调用）
+func (sub *GWSubscription) Close() {
+	sub.once.Do(func() {
+		sub.set.mu.Lock()
+		defer sub.set.mu.Unlock()
+		if _, ok := sub.set.subs[sub.id]; ok {
+			delete(sub.set.subs, sub.id)
+			close(sub.ch)
+		}
+	})
+}
+
+// Overflowed 订阅是否因缓冲溢出而被结束（此后的事件已丢失）
+func (sub *GWSubscription) Overflowed() bool {
+	sub.set.mu.Lock()
+	defer sub.set.mu.Unlock()
+	return sub.overflowed
+}
+
+// publish 分发事件给所有订阅者；缓冲已满的订阅被结束并标记溢出
+func (s *eventSubs) publish(evt GWEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sub := range s.subs {
+		select {
+		case sub.ch <- evt:
+		default:
+			sub.overflowed = true
+			delete(s.subs, id)
+			close(sub.ch)
+		}
+	}
+}
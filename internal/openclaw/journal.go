@@ -0,0 +1,104 @@
+package openclaw
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemdUnit systemd 模式下 openclaw 的服务名
+const systemdUnit = "openclaw"
+
+// ErrJournalPermission 当前用户无权读取 openclaw 服务的 journal
+var ErrJournalPermission = errors.New("无权读取 systemd journal：请以 root 运行 OpenClawDeck，或将运行用户加入 systemd-journal / adm 组")
+
+// JournalEntry journalctl 输出的一行日志
+type JournalEntry struct {
+	Time    time.Time `json:"time,omitempty"`
+	Message string    `json:"message"`
+	Raw     string    `json:"raw"`
+}
+
+// JournalCommand 构造读取 openclaw 服务日志的 journalctl 命令（short-iso 格式，便于解析时间戳）
+func JournalCommand(ctx context.Context, lines int, follow bool) *exec.Cmd {
+	args := []string{"-u", systemdUnit, "-n", strconv.Itoa(lines), "--no-pager", "-o", "short-iso"}
+	if follow {
+		args = append(args, "-f")
+	}
+	return exec.CommandContext(ctx, "journalctl", args...)
+}
+
+// ReadJournal 读取最近 lines 行 openclaw 服务日志；无权限时返回 ErrJournalPermission
+func ReadJournal(ctx context.Context, lines int) ([]JournalEntry, error) {
+	out, err := JournalCommand(ctx, lines, false).CombinedOutput()
+	text := string(out)
+	entries := []JournalEntry{}
+	inHint := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		// "Hint: ..." 提示可能跨多行，后续行以空白缩进
+		if inHint && strings.HasPrefix(line, " ") {
+			continue
+		}
+		inHint = strings.HasPrefix(line, "Hint: ")
+		if line == "" || isJournalNotice(line) {
+			continue
+		}
+		entries = append(entries, ParseJournalLine(line))
+	}
+	// 有日志时不判断权限，避免日志正文中的字样被误判
+	if len(entries) == 0 && IsJournalPermissionError(text) {
+		return nil, ErrJournalPermission
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(text); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return entries, nil
+}
+
+// IsJournalPermissionError 判断 journalctl 输出是否表示权限不足
+// 非特权用户读取系统服务日志时 journalctl 往往退出码为 0，只输出提示
+func IsJournalPermissionError(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "insufficient permissions") ||
+		strings.Contains(lower, "not seeing messages from other users")
+}
+
+// isJournalNotice journalctl 自身的提示行（"-- No entries --"、"-- Boot xxx --"、"Hint: ..." 等）
+func isJournalNotice(line string) bool {
+	return strings.HasPrefix(line, "-- ") || strings.HasPrefix(line, "Hint: ") || strings.HasPrefix(line, "No journal files")
+}
+
+// ParseJournalLine 解析 short-iso 格式行：`2024-01-02T03:04:05+0800 host openclaw[123]: message`
+// 无法解析时间戳时 Message 为原始行
+func ParseJournalLine(line string) JournalEntry {
+	entry := JournalEntry{Message: line, Raw: line}
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) < 3 {
+		return entry
+	}
+	var ts time.Time
+	var err error
+	for _, layout := range []string{"2006-01-02T15:04:05-0700", time.RFC3339, "2006-01-02T15:04:05.999999-0700"} {
+		if ts, err = time.Parse(layout, fields[0]); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return entry
+	}
+	entry.Time = ts
+	// 去掉 "host ident[pid]: " 前缀
+	if i := strings.Index(fields[2], ": "); i >= 0 {
+		entry.Message = fields[2][i+2:]
+	} else {
+		entry.Message = fields[2]
+	}
+	return entry
+}
@@ -0,0 +1,29 @@
+package openclaw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJournalLine(t *testing.T) {
+	e := ParseJournalLine("2024-05-06T07:08:09+0800 box openclaw[321]: gateway listening on :18789")
+	assert.Equal(t, "gateway listening on :18789", e.Message)
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("", 8*3600)).Unix(), e.Time.Unix())
+
+	// newer systemd prints RFC 3339 offsets
+	e = ParseJournalLine("2024-05-06T07:08:09+08:00 box openclaw[321]: ready")
+	assert.Equal(t, "ready", e.Message)
+	assert.False(t, e.Time.IsZero())
+
+	e = ParseJournalLine("    at Object.<anonymous> (gateway.js:1:1)")
+	assert.True(t, e.Time.IsZero())
+	assert.Equal(t, e.Raw, e.Message)
+}
+
+func TestIsJournalPermissionError(t *testing.T) {
+	assert.True(t, IsJournalPermissionError("Hint: You are currently not seeing messages from other users and the system.\n"))
+	assert.True(t, IsJournalPermissionError("No journal files were opened due to insufficient permissions."))
+	assert.False(t, IsJournalPermissionError("-- No entries --"))
+}
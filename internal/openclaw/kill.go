@@ -0,0 +1,235 @@
+package openclaw
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// KillLevel 强制停止的最高升级级别：graceful（gateway stop / 服务管理器停止，不发信号）
+// → term（SIGTERM；Windows 为不带 /F 的 taskkill）→ force（SIGKILL；Windows 为 taskkill /F）
+type KillLevel string
+
+const (
+	KillGraceful KillLevel = "graceful"
+	KillTerm     KillLevel = "term"
+	KillForce    KillLevel = "force"
+)
+
+// ParseKillLevel 解析级别，空值默认 term
+func ParseKillLevel(s string) (KillLevel, error) {
+	switch l := KillLevel(strings.ToLower(strings.TrimSpace(s))); l {
+	case "":
+		return KillTerm, nil
+	case KillGraceful, KillTerm, KillForce:
+		return l, nil
+	default:
+		return "", fmt.Errorf("未知的 kill 级别: %q（可选 graceful / term / force）", s)
+	}
+}
+
+// GatewayProcess 匹配到的 Gateway 进程
+type GatewayProcess struct {
+	PID     int    `json:"pid"`
+	Command string `json:"command"`
+}
+
+// KillResult 强制停止结果
+type KillResult struct {
+	Level    KillLevel        `json:"level"`             // 请求的最高级别
+	Reached  KillLevel        `json:"reached"`           // 实际升级到的级别
+	Affected []GatewayProcess `json:"affected"`          // 停止前匹配到的 Gateway 进程
+	Stopped  bool             `json:"stopped"`           // 结束后已无 Gateway 进程
+	Remains  []int            `json:"remains,omitempty"` // 仍存活的 PID
+}
+
+// Kill 按级别逐级停止本地 Gateway：先正常停止（不发信号），仍有残留时依次 SIGTERM、SIGKILL。
+// 只按命令行（同时包含 openclaw 与 gateway）匹配进程，绝不按窗口标题匹配，
+// 避免误杀标题为 "OpenClawDeck" 的浏览器标签页。
+func (s *Service) Kill(level KillLevel) (*KillResult, error) {
+	if s.IsRemote() {
+		return nil, errors.New("远程网关不支持强制停止，请在远程服务器上操作")
+	}
+	res := &KillResult{Level: level, Reached: KillGraceful, Affected: []GatewayProcess{}}
+	if procs, err := FindGatewayProcesses(); err == nil {
+		res.Affected = procs
+	}
+
+	stopErr := s.stopGraceful()
+	remains := livePIDs(res.Affected)
+	for _, next := range []KillLevel{KillTerm, KillForce} {
+		if len(remains) == 0 || !levelAllows(level, next) {
+			break
+		}
+		res.Reached = next
+		for _, pid := range remains {
+			_ = signalProcess(pid, next == KillForce)
+		}
+		remains = waitPIDsGone(remains, 5, 500*time.Millisecond)
+	}
+
+	res.Remains = remains
+	res.Stopped = len(remains) == 0
+	if !res.Stopped {
+		if stopErr != nil {
+			return res, stopErr
+		}
+		return res, fmt.Errorf("仍有 %d 个 Gateway 进程未退出", len(remains))
+	}
+	// 停止前没匹配到进程时（容器/systemd 等），以 Stop 的结果为准
+	if len(res.Affected) == 0 && stopErr != nil {
+		return res, stopErr
+	}
+	return res, nil
+}
+
+// stopGraceful 只做正常停止：进程模式下仅执行 openclaw gateway stop 并等待退出，
+// 不像 Stop 那样回退到信号（Windows 上 Stop 的回退是 taskkill /F），升级交给 Kill
+func (s *Service) stopGraceful() error {
+	if s.DetectRuntime() != RuntimeProcess {
+		return s.Stop()
+	}
+	cmdName := ResolveOpenClawCmd()
+	if cmdName == "" {
+		return errors.New("未找到 openclaw 命令，无法正常停止")
+	}
+	if err := runCommand(cmdName, "gateway", "stop"); err != nil {
+		return err
+	}
+	if !waitGatewayDown(5, 700*time.Millisecond) {
+		return errors.New("停止 Gateway 超时")
+	}
+	return nil
+}
+
+func levelAllows(max, l KillLevel) bool {
+	rank := map[KillLevel]int{KillGraceful: 0, KillTerm: 1, KillForce: 2}
+	return rank[l] <= rank[max]
+}
+
+// FindGatewayProcesses 列出命令行同时包含 openclaw 与 gateway 的进程（排除 OpenClawDeck 自身）
+// Windows 查询用的 PowerShell 进程命令行本身也含这两个词，按 $PID 排除
+func FindGatewayProcesses() ([]GatewayProcess, error) {
+	var out string
+	var err error
+	if runtime.GOOS == "windows" {
+		out, err = runOutput("powershell", "-NoProfile", "-Command",
+			"Get-CimInstance Win32_Process | Where-Object { $_.ProcessId -ne $PID -and $_.CommandLine -match 'openclaw' -and $_.CommandLine -match 'gateway' } | ForEach-Object { \"$($_.ProcessId) $($_.CommandLine)\" }")
+	} else {
+		out, err = runOutput("ps", "-eo", "pid=,args=")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseGatewayProcesses(out, os.Getpid()), nil
+}
+
+// parseGatewayProcesses 解析 "PID 命令行" 格式的进程列表
+func parseGatewayProcesses(out string, selfPID int) []GatewayProcess {
+	procs := []GatewayProcess{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil || pid <= 0 || pid == selfPID {
+			continue
+		}
+		cmd := strings.TrimSpace(fields[1])
+		if isGatewayCommandLine(cmd) {
+			procs = append(procs, GatewayProcess{PID: pid, Command: cmd})
+		}
+	}
+	return procs
+}
+
+// isGatewayCommandLine 命令行同时包含 openclaw 与 gateway，且不是 OpenClawDeck 本身或 PowerShell
+// （进程查询自身、或用户在 PowerShell 中查看日志时，命令行里也会出现这两个词）
+func isGatewayCommandLine(cmd string) bool {
+	lower := strings.ToLower(cmd)
+	if strings.Contains(lower, "openclawdeck") || isShellImage(lower) {
+		return false
+	}
+	return strings.Contains(lower, "openclaw") && strings.Contains(lower, "gateway")
+}
+
+// isShellImage 命令行的可执行文件是否为 powershell / pwsh
+func isShellImage(lowerCmd string) bool {
+	exe := lowerCmd
+	if strings.HasPrefix(exe, `"`) {
+		if end := strings.Index(exe[1:], `"`); end >= 0 {
+			exe = exe[1 : end+1]
+		}
+	} else if i := strings.IndexByte(exe, ' '); i >= 0 {
+		exe = exe[:i]
+	}
+	if i := strings.LastIndexAny(exe, `\/`); i >= 0 {
+		exe = exe[i+1:]
+	}
+	exe = strings.TrimSuffix(exe, ".exe")
+	return exe == "powershell" || exe == "pwsh"
+}
+
+// signalProcess 发送 SIGTERM / SIGKILL；Windows 使用 taskkill /PID（force 时加 /F）
+func signalProcess(pid int, force bool) error {
+	if runtime.GOOS == "windows" {
+		args := []string{"/PID", strconv.Itoa(pid), "/T"}
+		if force {
+			args = append(args, "/F")
+		}
+		return runCommand("taskkill", args...)
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+	return p.Signal(sig)
+}
+
+// livePIDs 返回仍存活的 Gateway 进程 PID
+func livePIDs(procs []GatewayProcess) []int {
+	if len(procs) == 0 {
+		return nil
+	}
+	current, err := FindGatewayProcesses()
+	if err != nil {
+		return nil
+	}
+	alive := map[int]bool{}
+	for _, p := range current {
+		alive[p.PID] = true
+	}
+	var pids []int
+	for _, p := range procs {
+		if alive[p.PID] {
+			pids = append(pids, p.PID)
+		}
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+func waitPIDsGone(pids []int, maxAttempts int, interval time.Duration) []int {
+	procs := make([]GatewayProcess, len(pids))
+	for i, pid := range pids {
+		procs[i] = GatewayProcess{PID: pid}
+	}
+	for i := 0; i < maxAttempts; i++ {
+		time.Sleep(interval)
+		if pids = livePIDs(procs); len(pids) == 0 {
+			return nil
+		}
+	}
+	return pids
+}
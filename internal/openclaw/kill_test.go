@@ -0,0 +1,40 @@
+package openclaw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGatewayProcesses(t *testing.T) {
+	out := `    1 /sbin/init
+  120 node /usr/lib/node_modules/openclaw/dist/index.js gateway run --port 18789
+  121 /opt/openclawdeck/openclawdeck serve --gateway-host 127.0.0.1
+  122 openclaw-gateway
+  123 /usr/bin/chrome --title=OpenClawDeck
+  999 openclaw gateway run
+  130 powershell -NoProfile -Command Get-CimInstance Win32_Process | Where-Object { $_.CommandLine -match 'openclaw' -and $_.CommandLine -match 'gateway' }
+  131 "C:\Program Files\PowerShell\7\pwsh.exe" -Command Get-Content openclaw-gateway.log
+  132 "C:\Program Files\nodejs\node.exe" C:\Users\me\AppData\Roaming\npm\node_modules\openclaw\dist\index.js gateway
+`
+	procs := parseGatewayProcesses(out, 999)
+	require.Len(t, procs, 3)
+	assert.Equal(t, 120, procs[0].PID)
+	assert.Equal(t, 122, procs[1].PID)
+	assert.Equal(t, 132, procs[2].PID)
+}
+
+func TestParseKillLevel(t *testing.T) {
+	l, err := ParseKillLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, KillTerm, l)
+	l, err = ParseKillLevel(" FORCE ")
+	require.NoError(t, err)
+	assert.Equal(t, KillForce, l)
+	_, err = ParseKillLevel("nuke")
+	assert.Error(t, err)
+
+	assert.True(t, levelAllows(KillForce, KillTerm))
+	assert.False(t, levelAllows(KillGraceful, KillTerm))
+}
@@ -0,0 +1,72 @@
+package openclaw
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PairingChannels 返回 openclaw.json 中私信策略为 pairing 的已启用频道
+func PairingChannels() []string {
+	return pairingChannelsFromConfig(readOpenClawConfig())
+}
+
+// pairingChannelsFromConfig 支持 channels.<name>.dmPolicy 与 channels.<name>.dm.policy 两种写法
+func pairingChannelsFromConfig(cfg map[string]interface{}) []string {
+	channels, _ := cfg["channels"].(map[string]interface{})
+	var out []string
+	for name, v := range channels {
+		ch, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if enabled, ok := ch["enabled"].(bool); ok && !enabled {
+			continue
+		}
+		policy, _ := ch["dmPolicy"].(string)
+		if dm, ok := ch["dm"].(map[string]interface{}); ok && policy == "" {
+			policy, _ = dm["policy"].(string)
+		}
+		if policy == "pairing" {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ActivePairingRequests 计算每个请求的 AgeSeconds，并去掉创建时间早于 ttl 的过期请求；
+// ttl <= 0 时不过滤，CreatedAt 无法解析的请求保留
+func ActivePairingRequests(reqs []PairingRequest, ttl time.Duration, now time.Time) []PairingRequest {
+	out := make([]PairingRequest, 0, len(reqs))
+	for _, req := range reqs {
+		if created, ok := parsePairingTime(req.CreatedAt); ok {
+			age := now.Sub(created)
+			if ttl > 0 && age > ttl {
+				continue
+			}
+			if age < 0 {
+				age = 0
+			}
+			req.AgeSeconds = int64(age / time.Second)
+		}
+		out = append(out, req)
+	}
+	return out
+}
+
+// parsePairingTime 解析 CLI 输出的时间：RFC3339 或毫秒时间戳
+func parsePairingTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, true
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil && ms > 0 {
+		return time.UnixMilli(ms), true
+	}
+	return time.Time{}, false
+}
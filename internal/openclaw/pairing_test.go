@@ -0,0 +1,42 @@
+package openclaw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairingChannelsFromConfig(t *testing.T) {
+	cfg := map[string]interface{}{
+		"channels": map[string]interface{}{
+			"telegram": map[string]interface{}{"enabled": true, "dmPolicy": "pairing"},
+			"discord":  map[string]interface{}{"dm": map[string]interface{}{"policy": "pairing"}},
+			"slack":    map[string]interface{}{"dmPolicy": "open"},
+			"signal":   map[string]interface{}{"enabled": false, "dmPolicy": "pairing"},
+		},
+	}
+	assert.Equal(t, []string{"discord", "telegram"}, pairingChannelsFromConfig(cfg))
+	assert.Empty(t, pairingChannelsFromConfig(nil))
+}
+
+func TestActivePairingRequests(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	reqs := []PairingRequest{
+		{Code: "FRESH", CreatedAt: now.Add(-5 * time.Minute).Format(time.RFC3339)},
+		{Code: "OLD", CreatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+		{Code: "MS", CreatedAt: "1767268200000"}, // 11:50 UTC
+		{Code: "UNKNOWN", CreatedAt: "yesterday"},
+	}
+	got := ActivePairingRequests(reqs, time.Hour, now)
+	codes := make([]string, 0, len(got))
+	for _, r := range got {
+		codes = append(codes, r.Code)
+	}
+	assert.Equal(t, []string{"FRESH", "MS", "UNKNOWN"}, codes)
+	assert.Equal(t, int64(300), got[0].AgeSeconds)
+	assert.Equal(t, int64(600), got[1].AgeSeconds)
+	assert.Zero(t, got[2].AgeSeconds)
+
+	assert.Len(t, ActivePairingRequests(reqs, 0, now), 4)
+}
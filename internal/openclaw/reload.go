@@ -0,0 +1,117 @@
+package openclaw
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"openclawdeck/internal/logger"
+)
+
+// 配置变更的生效方式
+const (
+	ApplyNone     = "none"     // 配置无变化
+	ApplyReload   = "reload"   // config.reload 热加载，不中断会话
+	ApplyRestart  = "restart"  // 完整重启
+	ApplyDeferred = "deferred" // 网关未连接，下次启动时读取新配置
+)
+
+// ErrReloadUnavailable 网关未连接，无法热加载
+var ErrReloadUnavailable = errors.New("网关未连接，无法热加载配置")
+
+// restartConfigKeys 修改后必须重启网关才能生效的配置路径（前缀匹配，含其下所有子键）：
+//
+//	gateway.bind / gateway.port / gateway.mode  监听地址与运行模式
+//	gateway.auth / gateway.tls                  鉴权与证书在监听建立时读取
+//	plugins                                     插件只在启动时加载
+//	discovery                                   mDNS/广域发现在启动时注册
+//
+// 其余配置（channels、models、agents、skills、tools、gateway 下的其他键等）均可通过 config.reload 热加载
+var restartConfigKeys = []string{
+	"gateway.bind",
+	"gateway.port",
+	"gateway.mode",
+	"gateway.auth",
+	"gateway.tls",
+	"plugins",
+	"discovery",
+}
+
+// ConfigApply 配置变更的分类结果与实际采取的操作
+type ConfigApply struct {
+	Action string   `json:"action"`          // none / reload / restart / deferred
+	Keys   []string `json:"keys,omitempty"`  // 变化的配置路径（restart 时仅列出需要重启的路径）
+	Error  string   `json:"error,omitempty"` // 热加载/重启失败原因
+}
+
+// ClassifyConfigChange 比较新旧配置：无变化为 none，命中 restartConfigKeys 为 restart，其余为 reload。
+// 返回的 keys 为触发该分类的配置路径（已排序）
+func ClassifyConfigChange(prev, next map[string]interface{}) (string, []string) {
+	var changed []string
+	for _, c := range DiffConfig(prev, next) {
+		changed = append(changed, c.Path)
+	}
+	if len(changed) == 0 {
+		return ApplyNone, nil
+	}
+	var restart []string
+	for _, path := range changed {
+		if requiresRestart(path) {
+			restart = append(restart, path)
+		}
+	}
+	if len(restart) > 0 {
+		return ApplyRestart, restart
+	}
+	return ApplyReload, changed
+}
+
+// requiresRestart path 本身或其上级/下级命中 restartConfigKeys
+func requiresRestart(path string) bool {
+	for _, key := range restartConfigKeys {
+		if path == key || strings.HasPrefix(path, key+".") || strings.HasPrefix(key, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload 通过 WebSocket 调用 config.reload 热加载配置，不中断现有会话
+func (s *Service) Reload() error {
+	if s.gwClient == nil || !s.gwClient.IsConnected() {
+		return ErrReloadUnavailable
+	}
+	if _, err := s.gwClient.RequestWithTimeout("config.reload", map[string]interface{}{}, 15*time.Second); err != nil {
+		return fmt.Errorf("网关热加载失败: %w", err)
+	}
+	return nil
+}
+
+// ApplyConfigChange 按 ClassifyConfigChange 选择干扰最小的操作让已写入的配置生效：
+// 可热加载的优先 Reload，失败时回退到 Restart；网关未连接时不做操作（deferred）
+func (s *Service) ApplyConfigChange(prev, next map[string]interface{}) ConfigApply {
+	action, keys := ClassifyConfigChange(prev, next)
+	result := ConfigApply{Action: action, Keys: keys}
+	if action == ApplyNone {
+		return result
+	}
+	if s.gwClient == nil || !s.gwClient.IsConnected() {
+		result.Action = ApplyDeferred
+		return result
+	}
+	if action == ApplyReload {
+		err := s.Reload()
+		if err == nil {
+			return result
+		}
+		logger.Gateway.Warn().Err(err).Msg("热加载失败，改为重启网关")
+		result.Action = ApplyRestart
+	} else {
+		logger.Gateway.Info().Strs("keys", keys).Msg("配置变更需要重启网关")
+	}
+	if err := s.Restart(); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package openclaw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyConfigChange(t *testing.T) {
+	base := func() map[string]interface{} {
+		return map[string]interface{}{
+			"gateway": map[string]interface{}{
+				"port": float64(18789),
+				"bind": "loopback",
+				"auth": map[string]interface{}{"mode": "token", "token": "t1"},
+			},
+			"channels": map[string]interface{}{"telegram": map[string]interface{}{"botToken": "a"}},
+			"models":   map[string]interface{}{"providers": map[string]interface{}{}},
+		}
+	}
+
+	action, keys := ClassifyConfigChange(base(), base())
+	assert.Equal(t, ApplyNone, action)
+	assert.Empty(t, keys)
+
+	next := base()
+	next["channels"].(map[string]interface{})["telegram"].(map[string]interface{})["botToken"] = "b"
+	next["skills"] = map[string]interface{}{"entries": map[string]interface{}{"weather": map[string]interface{}{"enabled": false}}}
+	action, keys = ClassifyConfigChange(base(), next)
+	assert.Equal(t, ApplyReload, action)
+	assert.Equal(t, []string{"channels.telegram.botToken", "skills"}, keys)
+
+	next = base()
+	next["gateway"].(map[string]interface{})["port"] = float64(18790)
+	next["gateway"].(map[string]interface{})["auth"].(map[string]interface{})["token"] = "t2"
+	next["models"].(map[string]interface{})["mode"] = "merge"
+	action, keys = ClassifyConfigChange(base(), next)
+	assert.Equal(t, ApplyRestart, action)
+	assert.Equal(t, []string{"gateway.auth.token", "gateway.port"}, keys)
+
+	// other gateway keys reload; replacing the whole gateway section restarts
+	next = base()
+	next["gateway"].(map[string]interface{})["controlUi"] = map[string]interface{}{"enabled": true}
+	action, _ = ClassifyConfigChange(base(), next)
+	assert.Equal(t, ApplyReload, action)
+
+	prev := base()
+	delete(prev, "gateway")
+	action, keys = ClassifyConfigChange(prev, base())
+	assert.Equal(t, ApplyRestart, action)
+	assert.Equal(t, []string{"gateway"}, keys)
+
+	action, _ = ClassifyConfigChange(base(), map[string]interface{}{"gateway": base()["gateway"], "channels": base()["channels"], "models": base()["models"], "plugins": map[string]interface{}{"entries": map[string]interface{}{}}})
+	assert.Equal(t, ApplyRestart, action)
+}
+
+func TestDiffConfig(t *testing.T) {
+	prev := map[string]interface{}{
+		"gateway": map[string]interface{}{"port": float64(1), "bind": "loopback"},
+		"skills":  map[string]interface{}{"a": true},
+	}
+	next := map[string]interface{}{
+		"gateway":  map[string]interface{}{"port": float64(2), "mode": "local"},
+		"channels": map[string]interface{}{"x": float64(1)},
+	}
+	assert.Equal(t, []ConfigDiff{
+		{Path: "channels", Old: nil, New: map[string]interface{}{"x": float64(1)}},
+		{Path: "gateway.bind", Old: "loopback", New: nil},
+		{Path: "gateway.mode", Old: nil, New: "local"},
+		{Path: "gateway.port", Old: float64(1), New: float64(2)},
+		{Path: "skills", Old: map[string]interface{}{"a": true}, New: nil},
+	}, DiffConfig(prev, next))
+	assert.Empty(t, DiffConfig(prev, prev))
+
+	// leaves mode expands one-sided objects
+	assert.Equal(t, []ConfigDiff{
+		{Path: "channels.x", Old: nil, New: float64(1)},
+		{Path: "gateway.bind", Old: "loopback", New: nil},
+		{Path: "gateway.mode", Old: nil, New: "local"},
+		{Path: "gateway.port", Old: float64(1), New: float64(2)},
+		{Path: "skills.a", Old: true, New: nil},
+	}, DiffConfigLeaves(prev, next))
+	assert.Equal(t, []ConfigDiff{{Path: "hooks", Old: nil, New: map[string]interface{}{}}},
+		DiffConfigLeaves(nil, map[string]interface{}{"hooks": map[string]interface{}{}}))
+}
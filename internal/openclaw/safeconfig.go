@@ -0,0 +1,74 @@
+package openclaw
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// DefaultGatewayPort 网关默认端口
+const DefaultGatewayPort = 18789
+
+// GatewayBindModes OpenClaw 支持的 gateway.bind 取值
+var GatewayBindModes = []string{"loopback", "lan", "tailnet", "auto"}
+
+// GenerateToken 生成 n 字节的随机 token（十六进制编码，长度 2n）
+func GenerateToken(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// IsLoopbackBind 判断 gateway.bind 是否仅监听本机回环地址
+func IsLoopbackBind(bind string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(bind))
+	if normalized == "loopback" || normalized == "localhost" {
+		return true
+	}
+	if strings.HasPrefix(normalized, "127.") || normalized == "::1" {
+		return true
+	}
+	if strings.Contains(normalized, ":") {
+		host, _, found := strings.Cut(normalized, ":")
+		if !found {
+			return false
+		}
+		return host == "127.0.0.1" || host == "localhost" || host == "::1"
+	}
+	return false
+}
+
+// IsValidGatewayBind 判断 bind 是否为 OpenClaw 支持的绑定模式
+func IsValidGatewayBind(bind string) bool {
+	for _, b := range GatewayBindModes {
+		if bind == b {
+			return true
+		}
+	}
+	return false
+}
+
+// MinimalSafeConfig 生成最小安全配置：local 模式 + 默认端口 + token 鉴权
+// bind 为空时使用 loopback；返回配置和新生成的 token
+func MinimalSafeConfig(bind string) (map[string]interface{}, string) {
+	if strings.TrimSpace(bind) == "" {
+		bind = "loopback"
+	}
+	token := GenerateToken(32)
+	return map[string]interface{}{
+		"gateway": map[string]interface{}{
+			"mode": "local",
+			"bind": bind,
+			"port": DefaultGatewayPort,
+			"auth": map[string]interface{}{
+				"mode":  "token",
+				"token": token,
+			},
+		},
+	}, token
+}
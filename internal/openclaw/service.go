@@ -13,7 +13,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,6 +26,7 @@ type Runtime string
 const (
 	RuntimeSystemd Runtime = "systemd"
 	RuntimeDocker  Runtime = "docker"
+	RuntimePodman  Runtime = "podman"
 	RuntimeProcess Runtime = "process"
 	RuntimeUnknown Runtime = "unknown"
 )
@@ -32,14 +35,18 @@ type Status struct {
 	Runtime Runtime
 	Running bool
 	Detail  string
+	Port    int // 探测到正在监听的 Gateway 端口，0 表示未探测到
 }
 
 type Service struct {
-	dockerContainer string
-	GatewayHost     string
-	GatewayPort     int
-	GatewayToken    string
-	gwClient        *GWClient // 远程模式下通过 JSON-RPC 控制网关
+	container     ContainerRuntime // 检测到 openclaw 容器的容器运行时
+	containerName string
+	GatewayHost   string
+	GatewayPort   int
+	gwClient      *GWClient // 远程模式下通过 JSON-RPC 控制网关
+	// gatewayToken 会被 token 漂移检查等后台任务更新，读写需加锁
+	tokenMu      sync.RWMutex
+	gatewayToken string
 	// 运行时检测缓存
 	runtimeCache     Runtime
 	runtimeCacheTime time.Time
@@ -54,6 +61,20 @@ func NewService() *Service {
 	}
 }
 
+// GatewayToken 返回当前使用的网关 token
+func (s *Service) GatewayToken() string {
+	s.tokenMu.RLock()
+	defer s.tokenMu.RUnlock()
+	return s.gatewayToken
+}
+
+// SetGatewayToken 更新网关 token（档案切换、token 漂移自动同步）
+func (s *Service) SetGatewayToken(token string) {
+	s.tokenMu.Lock()
+	s.gatewayToken = token
+	s.tokenMu.Unlock()
+}
+
 // SetGWClient 注入 Gateway WebSocket 客户端（远程控制用）
 func (s *Service) SetGWClient(client *GWClient) {
 	s.gwClient = client
@@ -96,22 +117,18 @@ func (s *Service) detectRuntimeImpl() Runtime {
 		return RuntimeSystemd
 	}
 
-	hasDocker := commandExists("docker")
-	dockerName := ""
-	if hasDocker {
-		dockerName = findDockerContainer()
-	}
+	// 容器运行时：docker 优先，其次 podman，以实际存在 openclaw 容器的为准
+	ctr, containerName := findContainer()
 	logger.Gateway.Debug().
-		Bool("hasDocker", hasDocker).
-		Str("containerName", dockerName).
-		Msg("DetectRuntime: 检测 docker")
-	if dockerName != "" {
-		s.dockerContainer = dockerName
-		return RuntimeDocker
+		Str("containerName", containerName).
+		Msg("DetectRuntime: 检测 docker/podman")
+	if ctr != nil {
+		s.container, s.containerName = ctr, containerName
+		return ctr.Name()
 	}
 
 	procExists := processExists()
-	portListening := gatewayPortListening()
+	portListening := gatewayPortListening(s.GatewayPort)
 	hasOpenclawCmd := commandExists("openclaw")
 	logger.Gateway.Debug().
 		Bool("processExists", procExists).
@@ -136,15 +153,16 @@ func (s *Service) Status() Status {
 	rt := s.DetectRuntime()
 
 	// 轻量级运行状态检查（不依赖运行时类型，避免重复调用 systemctl/docker）
-	running := s.isRunning()
+	port := s.ListeningPort()
+	running := port != 0 || processExists()
 
 	// 构建详细信息
 	var detail string
 	switch rt {
 	case RuntimeSystemd:
 		detail = "服务名: openclaw"
-	case RuntimeDocker:
-		name := s.ensureContainerName()
+	case RuntimeDocker, RuntimePodman:
+		_, name := s.ensureContainer(rt)
 		if name == "" {
 			return Status{Runtime: RuntimeUnknown, Running: false, Detail: "未找到 openclaw 容器"}
 		}
@@ -159,12 +177,18 @@ func (s *Service) Status() Status {
 		detail += "（运行中）"
 	}
 
-	return Status{Runtime: rt, Running: running, Detail: detail}
+	return Status{Runtime: rt, Running: running, Detail: detail, Port: port}
 }
 
-// isRunning 轻量级运行状态检查（只检查进程/端口，不检测 systemd/docker）
-func (s *Service) isRunning() bool {
-	return processExists() || gatewayPortListening()
+// ListeningPort 返回本地正在监听的 Gateway 端口（候选端口见 GatewayCandidatePorts），未探测到返回 0
+func (s *Service) ListeningPort() int {
+	for _, p := range gatewayPortsToCheck(s.GatewayPort) {
+		if portListedBySocketTools(p) {
+			n, _ := strconv.Atoi(p)
+			return n
+		}
+	}
+	return 0
 }
 
 // remoteStatus 远程 Gateway 状态探测
@@ -202,6 +226,7 @@ func (s *Service) remoteStatus() Status {
 		Runtime: RuntimeProcess,
 		Running: true,
 		Detail:  detail,
+		Port:    port,
 	}
 }
 
@@ -210,15 +235,15 @@ func (s *Service) Start() error {
 	if s.IsRemote() {
 		return errors.New("远程网关不支持远程启动，请在远程服务器上手动启动 OpenClaw 网关")
 	}
-	switch s.DetectRuntime() {
+	switch rt := s.DetectRuntime(); rt {
 	case RuntimeSystemd:
 		return runCommand("systemctl", "start", "openclaw")
-	case RuntimeDocker:
-		name := s.ensureContainerName()
+	case RuntimeDocker, RuntimePodman:
+		ctr, name := s.ensureContainer(rt)
 		if name == "" {
 			return errors.New("未找到 openclaw 容器")
 		}
-		return runCommand("docker", "start", name)
+		return ctr.Start(name)
 	case RuntimeProcess:
 		cmdName := ResolveOpenClawCmd()
 		if cmdName == "" {
@@ -228,13 +253,12 @@ func (s *Service) Start() error {
 		// 读取配置中的端口和 bind
 		port := defaultGatewayPort
 		bind := "loopback"
-		cfgPath := ResolveConfigPath()
-		if cfgPath != "" {
-			if p := configGatewayPort(cfgPath); p != "" {
-				port = p
+		if b, ok := ReadGatewayBinding(); ok {
+			if b.Port > 0 {
+				port = strconv.Itoa(b.Port)
 			}
-			if b := configGatewayBind(cfgPath); b != "" {
-				bind = b
+			if b.Bind != "" {
+				bind = b.Bind
 			}
 		}
 
@@ -253,15 +277,15 @@ func (s *Service) Stop() error {
 	if s.IsRemote() {
 		return errors.New("远程网关不支持远程停止，请在远程服务器上手动停止 OpenClaw 网关")
 	}
-	switch s.DetectRuntime() {
+	switch rt := s.DetectRuntime(); rt {
 	case RuntimeSystemd:
 		return runCommand("systemctl", "stop", "openclaw")
-	case RuntimeDocker:
-		name := s.ensureContainerName()
+	case RuntimeDocker, RuntimePodman:
+		ctr, name := s.ensureContainer(rt)
 		if name == "" {
 			return errors.New("未找到 openclaw 容器")
 		}
-		return runCommand("docker", "stop", name)
+		return ctr.Stop(name)
 	case RuntimeProcess:
 		cmdName := ResolveOpenClawCmd()
 		if cmdName != "" {
@@ -271,16 +295,12 @@ func (s *Service) Stop() error {
 				}
 			}
 		}
-		if runtime.GOOS == "windows" {
-			// Windows: 精确终止 openclaw 相关进程
-			// 注意：不能使用 WINDOWTITLE 过滤，因为浏览器标签页标题 "OpenClawDeck" 也会匹配，导致浏览器被关闭
-			_ = runCommand("taskkill", "/F", "/IM", "openclaw.exe")
-			// 终止 node.exe 中运行的 openclaw gateway 进程
-			_ = runCommand("powershell", "-NoProfile", "-Command",
-				"Get-CimInstance Win32_Process -Filter \"Name='node.exe'\" | Where-Object { $_.CommandLine -match 'openclaw' -and $_.CommandLine -match 'gateway' } | ForEach-Object { Stop-Process -Id $_.ProcessId -Force -ErrorAction SilentlyContinue }")
-		} else {
-			_ = runCommand("pkill", "-f", "openclaw-gateway")
-			_ = runCommand("pkill", "-f", "openclaw gateway")
+		// 按命令行精确匹配 Gateway 进程后逐个终止（Unix 发 SIGTERM，Windows taskkill /F）
+		// 注意：不能按窗口标题或镜像名匹配，浏览器标签页标题 "OpenClawDeck" 也会命中，导致浏览器被关闭
+		if procs, err := FindGatewayProcesses(); err == nil {
+			for _, p := range procs {
+				_ = signalProcess(p.PID, runtime.GOOS == "windows")
+			}
 		}
 		if waitGatewayDown(5, 700*time.Millisecond) {
 			return nil
@@ -317,12 +337,12 @@ func (s *Service) Restart() error {
 	switch rt {
 	case RuntimeSystemd:
 		return runCommand("systemctl", "restart", "openclaw")
-	case RuntimeDocker:
-		name := s.ensureContainerName()
+	case RuntimeDocker, RuntimePodman:
+		ctr, name := s.ensureContainer(rt)
 		if name == "" {
 			return errors.New("未找到 openclaw 容器")
 		}
-		return runCommand("docker", "restart", name)
+		return ctr.Restart(name)
 	case RuntimeProcess:
 		if commandExists("openclaw") {
 			if err := runCommand("openclaw", "gateway", "restart"); err == nil {
@@ -372,34 +392,34 @@ func (s *Service) gwClientRestart() error {
 	return nil
 }
 
-func (s *Service) ensureContainerName() string {
-	if s.dockerContainer != "" {
-		return s.dockerContainer
+// Container 返回本地 openclaw 容器及其容器运行时；远程模式或非容器运行时返回 nil
+func (s *Service) Container() (ContainerRuntime, string) {
+	if s.IsRemote() {
+		return nil, ""
 	}
-	s.dockerContainer = findDockerContainer()
-	return s.dockerContainer
-}
-
-func systemdActive(name string) bool {
-	return runOk("systemctl", "is-active", "--quiet", name)
+	rt := s.DetectRuntime()
+	if rt != RuntimeDocker && rt != RuntimePodman {
+		return nil, ""
+	}
+	return s.ensureContainer(rt)
 }
 
-func findDockerContainer() string {
-	out, err := runOutput("docker", "ps", "-a", "--format", "{{.Names}}")
-	if err != nil {
-		return ""
+// ensureContainer 返回 openclaw 容器及其所属容器运行时（优先使用 DetectRuntime 的缓存结果）
+func (s *Service) ensureContainer(rt Runtime) (ContainerRuntime, string) {
+	if s.container != nil && s.container.Name() == rt && s.containerName != "" {
+		return s.container, s.containerName
 	}
-	lines := strings.Split(out, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		if strings.Contains(strings.ToLower(line), "openclaw") {
-			return line
+	if c := containerRuntimeFor(rt); c != nil {
+		if name := c.FindContainer(); name != "" {
+			s.container, s.containerName = c, name
+			return c, name
 		}
 	}
-	return ""
+	return nil, ""
+}
+
+func systemdActive(name string) bool {
+	return runOk("systemctl", "is-active", "--quiet", name)
 }
 
 func processExists() bool {
@@ -462,8 +482,8 @@ func processExistsUnix() bool {
 	return false
 }
 
-func gatewayPortListening() bool {
-	ports := gatewayPortsToCheck()
+func gatewayPortListening(extra ...int) bool {
+	ports := gatewayPortsToCheck(extra...)
 	for _, port := range ports {
 		if portListedBySocketTools(port) {
 			return true
@@ -472,61 +492,35 @@ func gatewayPortListening() bool {
 	return false
 }
 
-func gatewayPortsToCheck() []string {
-	ports := []string{defaultGatewayPort}
-	if p := strings.TrimSpace(os.Getenv("OPENCLAW_GATEWAY_PORT")); p != "" {
-		ports = append(ports, p)
-	}
-
-	if cfgPath := ResolveConfigPath(); cfgPath != "" {
-		if p := configGatewayPort(cfgPath); p != "" {
-			ports = append(ports, p)
-		}
+// gatewayPortsToCheck 候选端口：extra（如激活档案端口）→ 默认端口 → 环境变量 → openclaw.json → 用户配置的额外端口
+func gatewayPortsToCheck(extra ...int) []string {
+	configPort := 0
+	if b, ok := ReadGatewayBinding(); ok {
+		configPort = b.Port
 	}
-	return dedupPorts(ports)
+	return gatewayPortsWithConfig(configPort, extra...)
 }
 
-func configGatewayPort(path string) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
-	}
-	var raw map[string]any
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return ""
-	}
-	gw, ok := raw["gateway"].(map[string]any)
-	if !ok {
-		return ""
-	}
-	switch v := gw["port"].(type) {
-	case float64:
-		if v > 0 {
-			return fmt.Sprintf("%d", int(v))
+// gatewayPortsWithConfig 同 gatewayPortsToCheck，openclaw.json 的 gateway.port 由调用方提供（0 表示未配置）
+func gatewayPortsWithConfig(configPort int, extra ...int) []string {
+	var ports []string
+	for _, p := range extra {
+		if p > 0 {
+			ports = append(ports, strconv.Itoa(p))
 		}
-	case string:
-		return strings.TrimSpace(v)
-	}
-	return ""
-}
-
-func configGatewayBind(path string) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
 	}
-	var raw map[string]any
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return ""
+	ports = append(ports, defaultGatewayPort)
+	if p := strings.TrimSpace(os.Getenv("OPENCLAW_GATEWAY_PORT")); p != "" {
+		ports = append(ports, p)
 	}
-	gw, ok := raw["gateway"].(map[string]any)
-	if !ok {
-		return ""
+
+	if configPort > 0 {
+		ports = append(ports, strconv.Itoa(configPort))
 	}
-	if v, ok := gw["bind"].(string); ok {
-		return strings.TrimSpace(v)
+	for _, p := range ExtraGatewayPorts() {
+		ports = append(ports, strconv.Itoa(p))
 	}
-	return ""
+	return dedupPorts(ports)
 }
 
 // startWindowsGateway Windows 专用：启动网关子进程，stdout/stderr 重定向到日志文件，
@@ -570,7 +564,7 @@ func (s *Service) startWindowsGateway(cmdName, bind, port string) error {
 	// 等待网关端口就绪（最多 15 秒）
 	for i := 0; i < 30; i++ {
 		time.Sleep(500 * time.Millisecond)
-		if gatewayPortListening() {
+		if gatewayPortListening(s.GatewayPort) {
 			output.Debugf("网关已在端口 %s 上启动\n", port)
 			return nil
 		}
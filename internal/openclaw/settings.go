@@ -0,0 +1,35 @@
+package openclaw
+
+import (
+	"openclawdeck/internal/database"
+)
+
+// HealthCheckEnabledSetting 设置项：心跳健康检查失败时自动重启 Gateway，默认启用
+const HealthCheckEnabledSetting = "gateway_health_check_enabled"
+
+// 本包读取的设置项定义
+func init() {
+	database.RegisterSettings(
+		database.SettingDef{
+			Key:         HealthCheckEnabledSetting,
+			Type:        database.SettingBool,
+			Default:     "true",
+			Description: "restart the gateway when heartbeats fail",
+		},
+		database.SettingDef{
+			Key:         GatewayCompressionSetting,
+			Type:        database.SettingBool,
+			Default:     "true",
+			Description: "negotiate permessage-deflate with the gateway (next connect)",
+		},
+		database.SettingDef{
+			Key:         GatewayProbePortsSetting,
+			Type:        database.SettingString,
+			Description: "extra local gateway ports to probe, comma separated",
+			Validate: func(v string) error {
+				_, err := ParseGatewayPorts(v)
+				return err
+			},
+		},
+	)
+}
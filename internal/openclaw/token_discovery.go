@@ -0,0 +1,94 @@
+package openclaw
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"openclawdeck/internal/logger"
+)
+
+// dockerStateDir 官方 Docker 镜像（node 用户）挂载的状态目录
+const dockerStateDir = "/home/node/.openclaw"
+
+// TokenSearchPaths 返回查找 gateway.auth.token 的候选配置文件（按优先级去重）：
+// 显式路径 → OPENCLAW_STATE_DIR / ~/.openclaw → $OPENCLAW_HOME → $XDG_CONFIG_HOME/openclaw → Docker 挂载目录
+// explicit 可以是目录或 openclaw.json 文件，为空时忽略
+func TokenSearchPaths(explicit string) []string {
+	var candidates []string
+	if explicit = strings.TrimSpace(explicit); explicit != "" {
+		if info, err := os.Stat(explicit); err == nil && info.IsDir() {
+			explicit = filepath.Join(explicit, "openclaw.json")
+		}
+		candidates = append(candidates, explicit)
+	}
+	if p := ResolveConfigPath(); p != "" {
+		candidates = append(candidates, p)
+	}
+	if dir := strings.TrimSpace(os.Getenv("OPENCLAW_HOME")); dir != "" {
+		candidates = append(candidates,
+			filepath.Join(dir, "openclaw.json"),
+			filepath.Join(dir, ".openclaw", "openclaw.json"))
+	}
+	xdg := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME"))
+	if xdg == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			xdg = filepath.Join(home, ".config")
+		}
+	}
+	if xdg != "" {
+		candidates = append(candidates, filepath.Join(xdg, "openclaw", "openclaw.json"))
+	}
+	candidates = append(candidates, filepath.Join(dockerStateDir, "openclaw.json"))
+
+	seen := make(map[string]bool, len(candidates))
+	paths := make([]string, 0, len(candidates))
+	for _, p := range candidates {
+		p = filepath.Clean(p)
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// DiscoverGatewayToken 依次在候选位置查找 gateway.auth.token，返回第一个有效 token 及其所在文件
+func DiscoverGatewayToken(explicit string) (token, source string) {
+	for _, p := range TokenSearchPaths(explicit) {
+		if t := readTokenFromFile(p); t != "" {
+			return t, p
+		}
+	}
+	return "", ""
+}
+
+// readTokenFromFile 从 openclaw.json 读取 gateway.auth.token
+func readTokenFromFile(configPath string) string {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		logger.Log.Debug().Str("configPath", configPath).Err(err).Msg("readTokenFromFile: 无法读取文件")
+		return ""
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		logger.Log.Debug().Str("configPath", configPath).Err(err).Msg("readTokenFromFile: JSON 解析失败")
+		return ""
+	}
+	gw, ok := raw["gateway"].(map[string]interface{})
+	if !ok {
+		logger.Log.Debug().Str("configPath", configPath).Msg("readTokenFromFile: 缺少 gateway 字段")
+		return ""
+	}
+	auth, ok := gw["auth"].(map[string]interface{})
+	if !ok {
+		logger.Log.Debug().Str("configPath", configPath).Msg("readTokenFromFile: 缺少 auth 字段")
+		return ""
+	}
+	token, _ := auth["token"].(string)
+	if token == "" {
+		logger.Log.Debug().Str("configPath", configPath).Msg("readTokenFromFile: token 为空")
+	}
+	return token
+}
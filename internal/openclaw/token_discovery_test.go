@@ -0,0 +1,66 @@
+package openclaw
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTokenConfig(t *testing.T, dir, token string) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	path := filepath.Join(dir, "openclaw.json")
+	data := `{"gateway":{"auth":{"token":"` + token + `"}}}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestDiscoverGatewayToken(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	t.Setenv("OPENCLAW_STATE_DIR", "")
+	t.Setenv("CLAWDBOT_STATE_DIR", "")
+	t.Setenv("OPENCLAW_HOME", filepath.Join(root, "oc-home"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+
+	token, src := DiscoverGatewayToken("")
+	assert.Empty(t, token)
+	assert.Empty(t, src)
+
+	// XDG location is found when nothing earlier has a token
+	xdgPath := writeTokenConfig(t, filepath.Join(root, "xdg", "openclaw"), "from-xdg")
+	token, src = DiscoverGatewayToken("")
+	assert.Equal(t, "from-xdg", token)
+	assert.Equal(t, xdgPath, src)
+
+	// $OPENCLAW_HOME wins over XDG
+	writeTokenConfig(t, filepath.Join(root, "oc-home"), "from-oc-home")
+	token, _ = DiscoverGatewayToken("")
+	assert.Equal(t, "from-oc-home", token)
+
+	// an explicit directory wins over everything
+	explicitDir := filepath.Join(root, "explicit")
+	writeTokenConfig(t, explicitDir, "from-explicit")
+	token, src = DiscoverGatewayToken(explicitDir)
+	assert.Equal(t, "from-explicit", token)
+	assert.Equal(t, filepath.Join(explicitDir, "openclaw.json"), src)
+}
+
+func TestTokenSearchPaths_Dedup(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("OPENCLAW_STATE_DIR", "")
+	t.Setenv("CLAWDBOT_STATE_DIR", "")
+	t.Setenv("OPENCLAW_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	paths := TokenSearchPaths(filepath.Join(home, ".openclaw", "openclaw.json"))
+	assert.Equal(t, []string{
+		filepath.Join(home, ".openclaw", "openclaw.json"),
+		filepath.Join(home, ".config", "openclaw", "openclaw.json"),
+		filepath.Join(dockerStateDir, "openclaw.json"),
+	}, paths)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"openclawdeck/internal/openclaw"
 	"os"
 	"os/exec"
@@ -28,6 +29,14 @@ type InstallConfig struct {
 	ZerotierNetworkId string `json:"zerotierNetworkId,omitempty"` // ZeroTier Network ID
 	InstallTailscale  bool   `json:"installTailscale,omitempty"`  // 安装 Tailscale
 	SudoPassword      string `json:"sudoPassword,omitempty"`      // sudo 密码（非 root 且需要密码时）
+	// 离线安装（无法访问 npm registry 时）
+	OfflineTarball  string `json:"offlineTarball,omitempty"`  // 本地 openclaw-x.y.z.tgz 路径
+	OfflineCacheDir string `json:"offlineCacheDir,omitempty"` // 预先填充的 npm 缓存目录
+}
+
+// Offline 是否为离线安装模式
+func (c InstallConfig) Offline() bool {
+	return c.OfflineTarball != "" || c.OfflineCacheDir != ""
 }
 
 // InstallSummaryItem 安装详单条目
@@ -40,12 +49,13 @@ type InstallSummaryItem struct {
 
 // InstallResult 安装结果
 type InstallResult struct {
-	Success      bool   `json:"success"`
-	Version      string `json:"version,omitempty"`
-	ConfigPath   string `json:"configPath,omitempty"`
-	GatewayPort  int    `json:"gatewayPort,omitempty"`
-	ErrorMessage string `json:"errorMessage,omitempty"`
-	ErrorDetails string `json:"errorDetails,omitempty"`
+	Success      bool     `json:"success"`
+	Version      string   `json:"version,omitempty"`
+	ConfigPath   string   `json:"configPath,omitempty"`
+	GatewayPort  int      `json:"gatewayPort,omitempty"`
+	ErrorMessage string   `json:"errorMessage,omitempty"`
+	ErrorDetails string   `json:"errorDetails,omitempty"`
+	StderrTail   []string `json:"stderrTail,omitempty"` // 失败命令最后几行 stderr
 }
 
 // Installer 安装器
@@ -53,6 +63,8 @@ type Installer struct {
 	emitter      *EventEmitter
 	env          *EnvironmentReport
 	sudoPassword string // sudo 密码（非 root 且需要密码时使用）
+	registry     string // npm 镜像源（为空时使用 npm 自身配置）
+	tracker      StepTracker
 }
 
 // NewInstaller 创建安装器
@@ -63,6 +75,62 @@ func NewInstaller(emitter *EventEmitter, env *EnvironmentReport) *Installer {
 	}
 }
 
+// SetRegistry 设置安装过程使用的 npm 镜像源；地址无效时返回错误且不修改当前设置
+func (i *Installer) SetRegistry(registry string) error {
+	normalized, err := NormalizeRegistry(registry)
+	if err != nil {
+		return err
+	}
+	i.registry = normalized
+	return nil
+}
+
+// NormalizeRegistry 校验 npm 镜像源地址：必须是带主机名的 http(s) URL，返回规范化后的地址。
+// 空值表示使用 npm 自身配置
+func NormalizeRegistry(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("无效的镜像源地址: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("镜像源地址必须使用 http 或 https: %q", raw)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("镜像源地址缺少主机名: %q", raw)
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("镜像源地址不能包含用户信息")
+	}
+	return u.String(), nil
+}
+
+// emitRegistry 输出当前使用的 npm 源
+func (i *Installer) emitRegistry() {
+	if i.registry != "" {
+		i.emitter.EmitLog(fmt.Sprintf("npm 镜像源: %s", i.registry))
+	} else if i.env != nil && i.env.NpmRegistry != "" {
+		i.emitter.EmitLog(fmt.Sprintf("npm 镜像源: %s（npm 配置）", i.env.NpmRegistry))
+	}
+}
+
+// nodeMirrorFor 根据 npm 镜像源推断 Node.js 二进制下载镜像（fnm 使用），未知镜像返回空
+func nodeMirrorFor(registry string) string {
+	r := strings.ToLower(registry)
+	switch {
+	case strings.Contains(r, "npmmirror.com"), strings.Contains(r, "taobao.org"):
+		return "https://npmmirror.com/mirrors/node/"
+	case strings.Contains(r, "huaweicloud.com"):
+		return "https://repo.huaweicloud.com/nodejs/"
+	case strings.Contains(r, "tencent"):
+		return "https://mirrors.cloud.tencent.com/nodejs-release/"
+	}
+	return ""
+}
+
 // newSC 创建带 sudo 密码的 StreamCommand
 func (i *Installer) newSC(phase, step string) *StreamCommand {
 	if i.sudoPassword != "" {
@@ -79,6 +147,7 @@ func (i *Installer) InstallNode(ctx context.Context) error {
 	}
 
 	i.emitter.EmitStep("install", "install-node", "正在安装 Node.js...", 10)
+	i.emitRegistry()
 
 	// 策略 1: 尝试系统包管理器
 	i.emitter.EmitLog("尝试使用系统包管理器安装...")
@@ -139,6 +208,10 @@ func (i *Installer) installNodeViaFnm(ctx context.Context) error {
 		}
 		// 使用 fnm 安装 Node.js 22
 		fnmCmd := "fnm install 22 && fnm default 22 && fnm use 22"
+		if mirror := nodeMirrorFor(i.registry); mirror != "" {
+			i.emitter.EmitLog(fmt.Sprintf("Node.js 下载镜像: %s", mirror))
+			fnmCmd = fmt.Sprintf("$env:FNM_NODE_DIST_MIRROR='%s'; ", mirror) + fnmCmd
+		}
 		return sc.RunShell(ctx, fnmCmd)
 
 	case "darwin", "linux":
@@ -156,6 +229,10 @@ func (i *Installer) installNodeViaFnm(ctx context.Context) error {
 		home, _ := os.UserHomeDir()
 		fnmPath := filepath.Join(home, ".fnm")
 		fnmCmd := fmt.Sprintf("export PATH=%s:$PATH && fnm install 22 && fnm default 22 && fnm use 22", fnmPath)
+		if mirror := nodeMirrorFor(i.registry); mirror != "" {
+			i.emitter.EmitLog(fmt.Sprintf("Node.js 下载镜像: %s", mirror))
+			fnmCmd = fmt.Sprintf("export FNM_NODE_DIST_MIRROR=%s && ", mirror) + fnmCmd
+		}
 		return sc.RunShell(ctx, fnmCmd)
 
 	default:
@@ -247,6 +324,7 @@ func (i *Installer) InstallOpenClaw(ctx context.Context) error {
 	}
 
 	i.emitter.EmitStep("install", "install-openclaw", "正在安装 OpenClaw...", 30)
+	i.emitRegistry()
 
 	// 策略 1: 优先使用 npm（最可靠）
 	npmAvailable := i.env.Tools["npm"].Installed || detectTool("npm", "--version").Installed
@@ -320,9 +398,26 @@ func (i *Installer) verifyOpenClawInstalled() bool {
 // InstallOpenClawWithConfig 使用配置安装 OpenClaw（支持镜像源选择）
 func (i *Installer) InstallOpenClawWithConfig(ctx context.Context, config InstallConfig) error {
 	i.emitter.EmitStep("install", "install-openclaw", "正在安装 OpenClaw...", 30)
+	if !config.Offline() {
+		i.emitRegistry()
+	}
 
 	cmdName := "openclaw"
 
+	// 离线模式：只使用本地 tarball / npm 缓存，不回退到在线安装
+	if config.Offline() {
+		if err := i.installOffline(ctx, config); err != nil {
+			i.emitter.EmitLog(fmt.Sprintf("离线安装失败: %v", err))
+			return err
+		}
+		if detectTool(cmdName, "--version").Installed {
+			i.emitter.EmitLog("✓ OpenClaw 离线安装成功")
+		} else {
+			i.emitter.EmitLog("⚠ 离线安装完成但未检测到命令，可能需要重启")
+		}
+		return nil
+	}
+
 	// 使用 npm 全局安装（所有平台统一方案）
 	if i.env.Tools["npm"].Installed || detectTool("npm", "--version").Installed {
 		i.emitter.EmitLog("使用 npm 全局安装...")
@@ -441,31 +536,80 @@ func (i *Installer) installViaScriptWithConfig(ctx context.Context, config Insta
 
 // installViaNpm 使用 npm 安装
 func (i *Installer) installViaNpm(ctx context.Context) error {
-	return i.installViaNpmWithOptions(ctx, "openclaw", "")
+	return i.installViaNpmWithOptions(ctx, "openclaw", i.registry)
 }
 
-// installViaNpmWithOptions 使用 npm 安装（支持版本和镜像源选择）
+// installViaNpmWithOptions 使用 npm 安装（支持版本和镜像源选择）。
+// 参数以 argv 传递，不经过 shell
 func (i *Installer) installViaNpmWithOptions(ctx context.Context, version string, registry string) error {
 	sc := i.newSC("install", "install-"+version)
 
 	pkgName := version + "@latest"
 	i.emitter.EmitLog(fmt.Sprintf("安装 %s...", version))
+	if registry == "" {
+		registry = i.registry
+	}
+	registry, err := NormalizeRegistry(registry)
+	if err != nil {
+		return err
+	}
 
-	// 构建安装命令
-	cmd := "npm install -g " + pkgName
-
-	// 添加镜像源
+	args := []string{"install", "-g", pkgName}
 	if registry != "" {
-		cmd += " --registry=" + registry
+		args = append(args, "--registry="+registry)
 		i.emitter.EmitLog(fmt.Sprintf("使用镜像源: %s", registry))
 	}
 
 	// 非 root 的 Linux/macOS 需要 sudo 执行全局安装
+	return sc.RunPrivileged(ctx, "npm", args...)
+}
+
+// installOffline 从本地 tarball 或预填充的 npm 缓存安装 OpenClaw，全程 --offline 不访问 registry
+func (i *Installer) installOffline(ctx context.Context, config InstallConfig) error {
+	if !(i.env.Tools["npm"].Installed || detectTool("npm", "--version").Installed) {
+		return fmt.Errorf("离线安装需要本机已安装 Node.js 和 npm")
+	}
+
+	pkg := "openclaw"
+	if config.OfflineTarball != "" {
+		abs, err := filepath.Abs(config.OfflineTarball)
+		if err != nil {
+			return err
+		}
+		if st, err := os.Stat(abs); err != nil || st.IsDir() {
+			return fmt.Errorf("离线安装包不存在: %s", abs)
+		}
+		pkg = shellQuote(abs)
+		i.emitter.EmitLog(fmt.Sprintf("使用离线安装包: %s", abs))
+	}
+
+	cmd := "npm install -g " + pkg + " --offline --no-audit --no-fund"
+	if config.OfflineCacheDir != "" {
+		abs, err := filepath.Abs(config.OfflineCacheDir)
+		if err != nil {
+			return err
+		}
+		if st, err := os.Stat(abs); err != nil || !st.IsDir() {
+			return fmt.Errorf("npm 缓存目录不存在: %s", abs)
+		}
+		cmd += " --cache " + shellQuote(abs)
+		i.emitter.EmitLog(fmt.Sprintf("使用 npm 缓存目录: %s", abs))
+	}
+
 	if runtime.GOOS != "windows" && os.Getuid() != 0 {
 		cmd = "sudo " + cmd
 	}
 
-	return sc.RunShell(ctx, cmd)
+	i.emitter.EmitLog(fmt.Sprintf("执行: %s", cmd))
+	return i.newSC("install", "install-openclaw").RunShell(ctx, cmd)
+}
+
+// shellQuote 为 RunShell 引用路径（Unix sh / Windows PowerShell 均使用单引号）
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 // ConfigureOpenClaw 通过 onboard --non-interactive 配置 OpenClaw
@@ -950,8 +1094,13 @@ func (i *Installer) UpdateOpenClaw(ctx context.Context) error {
 	}
 
 	sc := NewStreamCommand(i.emitter, "update", "update-openclaw")
-	i.emitter.EmitLog("Running: npm update -g openclaw@latest")
-	if err := sc.Run(ctx, "npm", "install", "-g", "openclaw@latest"); err != nil {
+	args := []string{"install", "-g", "openclaw@latest"}
+	if i.registry != "" {
+		args = append(args, "--registry="+i.registry)
+	}
+	i.emitRegistry()
+	i.emitter.EmitLog("Running: npm " + strings.Join(args, " "))
+	if err := sc.Run(ctx, "npm", args...); err != nil {
 		return fmt.Errorf("npm update failed: %w", err)
 	}
 
@@ -1041,48 +1190,55 @@ func (i *Installer) InstallSkillDeps(ctx context.Context) {
 
 // installSingleSkillDep installs one skill dependency using the best available method.
 func (i *Installer) installSingleSkillDep(ctx context.Context, dep skillDep) error {
-	sc := i.newSC("skill-deps", "install-"+dep.name)
+	cmd, err := skillDepCommand(i.env, dep)
+	if err != nil {
+		return err
+	}
+	return i.newSC("skill-deps", "install-"+dep.name).RunShell(ctx, cmd)
+}
 
+// skillDepCommand returns the shell command that installs dep on this machine.
+func skillDepCommand(env *EnvironmentReport, dep skillDep) (string, error) {
 	switch runtime.GOOS {
 	case "darwin":
 		// macOS: prefer brew
-		if dep.brewFormula != "" && i.env.Tools["brew"].Installed {
-			return sc.RunShell(ctx, fmt.Sprintf("brew install %s", dep.brewFormula))
+		if dep.brewFormula != "" && env.Tools["brew"].Installed {
+			return fmt.Sprintf("brew install %s", dep.brewFormula), nil
 		}
 
 	case "linux":
-		pm := i.env.PackageManager
-		hasSudo := i.env.HasSudo
+		pm := env.PackageManager
+		hasSudo := env.HasSudo
 		// apt (Debian/Ubuntu)
 		if dep.aptPkg != "" && pm == "apt" && hasSudo {
-			return sc.RunShell(ctx, fmt.Sprintf("sudo apt-get install -y %s", dep.aptPkg))
+			return fmt.Sprintf("sudo apt-get install -y %s", dep.aptPkg), nil
 		}
 		// dnf (Fedora/RHEL 8+)
 		if dep.dnfPkg != "" && (pm == "dnf" || pm == "yum") && hasSudo {
-			return sc.RunShell(ctx, fmt.Sprintf("sudo %s install -y %s", pm, dep.dnfPkg))
+			return fmt.Sprintf("sudo %s install -y %s", pm, dep.dnfPkg), nil
 		}
 		// pacman (Arch/Manjaro)
 		if dep.pacmanPkg != "" && pm == "pacman" && hasSudo {
-			return sc.RunShell(ctx, fmt.Sprintf("sudo pacman -S --noconfirm %s", dep.pacmanPkg))
+			return fmt.Sprintf("sudo pacman -S --noconfirm %s", dep.pacmanPkg), nil
 		}
 		// Special case: uv — use official install script on any Linux
 		if dep.name == "uv" {
-			return sc.RunShell(ctx, "curl -LsSf https://astral.sh/uv/install.sh | sh")
+			return "curl -LsSf https://astral.sh/uv/install.sh | sh", nil
 		}
 
 	case "windows":
 		// Windows: prefer winget
 		if dep.wingetID != "" && detectTool("winget", "--version").Installed {
-			return sc.RunShell(ctx, fmt.Sprintf("winget install --id %s --accept-package-agreements --accept-source-agreements", dep.wingetID))
+			return fmt.Sprintf("winget install --id %s --accept-package-agreements --accept-source-agreements", dep.wingetID), nil
 		}
 	}
 
 	// Fallback: go install (for go module deps)
 	if dep.goModule != "" && detectTool("go", "version").Installed {
-		return sc.Run(ctx, "go", "install", dep.goModule)
+		return fmt.Sprintf("go install %s", dep.goModule), nil
 	}
 
-	return fmt.Errorf("no suitable install method for %s on %s", dep.label, runtime.GOOS)
+	return "", fmt.Errorf("no suitable install method for %s on %s", dep.label, runtime.GOOS)
 }
 
 // AutoInstall 一键全自动安装
@@ -1101,15 +1257,26 @@ func (i *Installer) AutoInstall(ctx context.Context, config InstallConfig) (*Ins
 		// 有密码时视为有 sudo 权限
 		i.env.HasSudo = true
 	}
+	if config.Registry != "" {
+		if err := i.SetRegistry(config.Registry); err != nil {
+			result.ErrorMessage = "npm 镜像源无效"
+			result.ErrorDetails = err.Error()
+			i.emitter.EmitError(result.ErrorMessage, result)
+			return result, err
+		}
+	}
 
 	// 阶段 1: 安装依赖
 	i.emitter.EmitPhase("install", "开始安装依赖...", 0)
 
-	// 安装 Node.js
-	if !i.env.Tools["node"].Installed {
+	// 安装 Node.js（各步骤可重复执行：已安装的组件会被跳过）
+	if i.env.Tools["node"].Installed {
+		i.skipStep(StepNode, "Node.js")
+	} else {
 		if err := i.InstallNode(ctx); err != nil {
 			result.ErrorMessage = "Node.js 安装失败"
 			result.ErrorDetails = err.Error()
+			result.StderrTail = StderrTail(err)
 			i.emitter.EmitError(result.ErrorMessage, result)
 			return result, err
 		}
@@ -1125,12 +1292,18 @@ func (i *Installer) AutoInstall(ctx context.Context, config InstallConfig) (*Ins
 			i.emitter.EmitLog("⚠️ Node.js 已安装但环境变量未生效，需要重启应用")
 		}
 	}
+	if i.env.Tools["node"].Installed {
+		i.completeStep(StepNode)
+	}
 
 	// 安装 OpenClaw（使用配置的版本和镜像源）
-	if !i.env.OpenClawInstalled {
+	if i.env.OpenClawInstalled {
+		i.skipStep(StepOpenClaw, "OpenClaw")
+	} else {
 		if err := i.InstallOpenClawWithConfig(ctx, config); err != nil {
 			result.ErrorMessage = "OpenClaw 安装失败"
 			result.ErrorDetails = err.Error()
+			result.StderrTail = StderrTail(err)
 			i.emitter.EmitError(result.ErrorMessage, result)
 			return result, err
 		}
@@ -1140,21 +1313,31 @@ func (i *Installer) AutoInstall(ctx context.Context, config InstallConfig) (*Ins
 			i.emitter.EmitLog("⚠️ OpenClaw 已安装但环境变量未生效，需要重启应用")
 		}
 	}
+	if !needsRestart {
+		i.completeStep(StepOpenClaw)
+	}
+
+	// 离线模式下跳过需要联网的可选组件
+	if config.Offline() && !needsRestart {
+		i.emitter.EmitLog("离线模式：跳过 ClawHub CLI 和技能依赖安装")
+	}
 
 	// 安装 ClawHub CLI（技能市场工具，非致命）
-	if !needsRestart {
+	if !needsRestart && !config.Offline() && !i.skipStep(StepClawHub, "ClawHub CLI") {
 		if err := i.InstallClawHub(ctx, config.Registry); err != nil {
 			i.emitter.EmitLog(fmt.Sprintf("⚠️ ClawHub CLI 安装失败: %v（跳过）", err))
 		}
+		i.completeStep(StepClawHub)
 	}
 
 	// 安装技能运行时依赖（Go, uv, ffmpeg, jq, rg — 全部非致命）
-	if !needsRestart {
+	if !needsRestart && !config.Offline() && !i.skipStep(StepSkillDeps, "技能运行时依赖") {
 		i.InstallSkillDeps(ctx)
+		i.completeStep(StepSkillDeps)
 	}
 
 	// 安装可选工具（ZeroTier / Tailscale）
-	if config.InstallZeroTier || config.InstallTailscale {
+	if (config.InstallZeroTier || config.InstallTailscale) && !i.skipStep(StepVPNTools, "内网穿透工具") {
 		i.emitter.EmitPhase("vpn-tools", "安装内网穿透工具...", 45)
 		if config.InstallZeroTier {
 			if err := i.InstallVPNTool(ctx, "zerotier"); err != nil {
@@ -1179,16 +1362,21 @@ func (i *Installer) AutoInstall(ctx context.Context, config InstallConfig) (*Ins
 				i.emitter.EmitLog(fmt.Sprintf("⚠️ Tailscale 安装失败: %v（跳过）", err))
 			}
 		}
+		i.completeStep(StepVPNTools)
 	}
 
 	// 阶段 2: 配置（可选）
 	if !config.SkipConfig {
 		i.emitter.EmitPhase("configure", "开始配置...", 50)
-		if err := i.ConfigureOpenClaw(ctx, config); err != nil {
-			result.ErrorMessage = "配置失败"
-			result.ErrorDetails = err.Error()
-			i.emitter.EmitError(result.ErrorMessage, result)
-			return result, err
+		if !i.skipStep(StepConfigure, "模型配置") {
+			if err := i.ConfigureOpenClaw(ctx, config); err != nil {
+				result.ErrorMessage = "配置失败"
+				result.ErrorDetails = err.Error()
+				result.StderrTail = StderrTail(err)
+				i.emitter.EmitError(result.ErrorMessage, result)
+				return result, err
+			}
+			i.completeStep(StepConfigure)
 		}
 	} else {
 		i.emitter.EmitLog("跳过模型配置，生成默认配置文件...")
@@ -1200,11 +1388,16 @@ func (i *Installer) AutoInstall(ctx context.Context, config InstallConfig) (*Ins
 	// 阶段 3: 启动（可选）
 	if !config.SkipGateway {
 		i.emitter.EmitPhase("start", "启动 Gateway...", 75)
-		if err := i.StartGatewayWithConfig(ctx, config); err != nil {
-			result.ErrorMessage = "Gateway 启动失败"
-			result.ErrorDetails = err.Error()
-			i.emitter.EmitError(result.ErrorMessage, result)
-			return result, err
+		// 续装时仅当 Gateway 仍在运行才跳过
+		if running, _ := checkGatewayRunning(); !running || !i.skipStep(StepGateway, "启动 Gateway") {
+			if err := i.StartGatewayWithConfig(ctx, config); err != nil {
+				result.ErrorMessage = "Gateway 启动失败"
+				result.ErrorDetails = err.Error()
+				result.StderrTail = StderrTail(err)
+				i.emitter.EmitError(result.ErrorMessage, result)
+				return result, err
+			}
+			i.completeStep(StepGateway)
 		}
 	} else {
 		i.emitter.EmitLog("跳过启动 Gateway，稍后可手动启动")
@@ -1314,15 +1507,13 @@ func (i *Installer) AutoInstall(ctx context.Context, config InstallConfig) (*Ins
 	// — 网关状态 —
 	gwMode := "local"
 	gwBind := "loopback"
-	if cfgValid {
-		if raw := readOpenClawConfigRaw(result.ConfigPath); raw != nil {
-			if gw, ok := raw["gateway"].(map[string]interface{}); ok {
-				if m, ok := gw["mode"].(string); ok {
-					gwMode = m
-				}
-				if b, ok := gw["bind"].(string); ok {
-					gwBind = b
-				}
+	if cfgValid && result.ConfigPath != "" {
+		if b, ok := openclaw.ReadGatewayBindingFrom(result.ConfigPath); ok {
+			if b.Mode != "" {
+				gwMode = b.Mode
+			}
+			if b.Bind != "" {
+				gwBind = b.Bind
 			}
 		}
 	}
@@ -0,0 +1,145 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// NpmPrefixStatus npm 全局 prefix 检查结果
+type NpmPrefixStatus struct {
+	Prefix     string `json:"prefix"`
+	BinDir     string `json:"binDir"`
+	OnPath     bool   `json:"onPath"`
+	FixCommand string `json:"fixCommand,omitempty"` // 将 bin 目录加入 PATH 的命令
+	ShellRC    string `json:"shellRc,omitempty"`    // 建议写入的 shell 配置文件
+}
+
+// CheckNpmPrefix 读取 npm config get prefix，检查全局 bin 目录是否在 PATH 中（npm 不可用时返回 nil）
+func CheckNpmPrefix() *NpmPrefixStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "npm", "config", "get", "prefix").Output()
+	if err != nil {
+		return nil
+	}
+	prefix := strings.TrimSpace(string(out))
+	if prefix == "" {
+		return nil
+	}
+
+	st := &NpmPrefixStatus{Prefix: prefix, BinDir: npmPrefixBinDir(prefix)}
+	st.OnPath = dirOnPath(st.BinDir, os.Getenv("PATH"))
+	if !st.OnPath {
+		st.ShellRC = userShellRC()
+		st.FixCommand = pathExportCommand(st.BinDir, st.ShellRC)
+	}
+	return st
+}
+
+// npmPrefixBinDir Windows 下全局命令直接位于 prefix，Unix 位于 prefix/bin
+func npmPrefixBinDir(prefix string) string {
+	if runtime.GOOS == "windows" {
+		return prefix
+	}
+	return filepath.Join(prefix, "bin")
+}
+
+// dirOnPath 判断 dir 是否在 PATH 列表中
+func dirOnPath(dir, pathEnv string) bool {
+	want := filepath.Clean(dir)
+	for _, p := range filepath.SplitList(pathEnv) {
+		if p == "" {
+			continue
+		}
+		p = filepath.Clean(p)
+		if p == want || (runtime.GOOS == "windows" && strings.EqualFold(p, want)) {
+			return true
+		}
+	}
+	return false
+}
+
+// userShellRC 根据 $SHELL 推断 shell 配置文件（Windows 返回空）
+func userShellRC() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	switch filepath.Base(os.Getenv("SHELL")) {
+	case "zsh":
+		return filepath.Join(home, ".zshrc")
+	case "bash":
+		if runtime.GOOS == "darwin" {
+			return filepath.Join(home, ".bash_profile")
+		}
+		return filepath.Join(home, ".bashrc")
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish")
+	default:
+		return filepath.Join(home, ".profile")
+	}
+}
+
+// pathExportLine 写入 shell 配置文件的 PATH 语句
+func pathExportLine(binDir, rc string) string {
+	if strings.HasSuffix(rc, "config.fish") {
+		return fmt.Sprintf("fish_add_path %q", binDir)
+	}
+	return fmt.Sprintf("export PATH=\"%s:$PATH\"", binDir)
+}
+
+// pathExportCommand 生成可直接执行的修复命令
+func pathExportCommand(binDir, rc string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf(`[Environment]::SetEnvironmentVariable("Path", [Environment]::GetEnvironmentVariable("Path", "User") + ";%s", "User")`, binDir)
+	}
+	if rc == "" {
+		return pathExportLine(binDir, rc)
+	}
+	return fmt.Sprintf("echo '%s' >> %s && source %s", pathExportLine(binDir, rc), rc, rc)
+}
+
+// AppendNpmBinToShellRC 把 npm 全局 bin 目录追加到 shell 配置文件；已存在时不重复写入
+func AppendNpmBinToShellRC(st *NpmPrefixStatus) (changed bool, err error) {
+	if st == nil || st.OnPath {
+		return false, nil
+	}
+	if st.ShellRC == "" {
+		return false, fmt.Errorf("当前系统不支持自动写入 shell 配置，请手动执行: %s", st.FixCommand)
+	}
+
+	data, err := os.ReadFile(st.ShellRC)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if strings.Contains(string(data), st.BinDir) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(st.ShellRC), 0o755); err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(st.ShellRC, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	block := "\n# Added by OpenClawDeck doctor: npm global bin\n" + pathExportLine(st.BinDir, st.ShellRC) + "\n"
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		block = "\n" + block
+	}
+	if _, err := f.WriteString(block); err != nil {
+		return false, err
+	}
+	return true, nil
+}
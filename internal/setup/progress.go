@@ -0,0 +1,44 @@
+package setup
+
+import "fmt"
+
+// 自动安装步骤（用于断点续装）
+const (
+	StepNode      = "node"
+	StepOpenClaw  = "openclaw"
+	StepClawHub   = "clawhub"
+	StepSkillDeps = "skill-deps"
+	StepVPNTools  = "vpn-tools"
+	StepConfigure = "configure"
+	StepGateway   = "gateway"
+)
+
+// InstallSteps 自动安装步骤顺序
+var InstallSteps = []string{StepNode, StepOpenClaw, StepClawHub, StepSkillDeps, StepVPNTools, StepConfigure, StepGateway}
+
+// StepTracker 记录安装步骤完成状态，由调用方持久化（页面刷新或进程崩溃后可续装）
+type StepTracker interface {
+	Completed(step string) bool
+	MarkCompleted(step string)
+}
+
+// SetStepTracker 设置步骤状态记录器（为 nil 时每次都完整执行）
+func (i *Installer) SetStepTracker(t StepTracker) {
+	i.tracker = t
+}
+
+// skipStep 步骤已完成时输出跳过日志并返回 true
+func (i *Installer) skipStep(step, label string) bool {
+	if i.tracker == nil || !i.tracker.Completed(step) {
+		return false
+	}
+	i.emitter.EmitLog(fmt.Sprintf("⏭ 跳过已完成步骤: %s", label))
+	return true
+}
+
+// completeStep 标记步骤完成
+func (i *Installer) completeStep(step string) {
+	if i.tracker != nil {
+		i.tracker.MarkCompleted(step)
+	}
+}
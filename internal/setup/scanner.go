@@ -12,6 +12,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,15 @@ type ToolInfo struct {
 	Path      string `json:"path,omitempty"`
 }
 
+// OpenClawInstall 一个已发现的 OpenClaw 安装
+type OpenClawInstall struct {
+	Name     string `json:"name"` // "openclaw" | "openclaw-cn"
+	Path     string `json:"path"`
+	RealPath string `json:"realPath,omitempty"`
+	Version  string `json:"version,omitempty"`
+	InPath   bool   `json:"inPath"`
+}
+
 // Step 安装步骤
 type Step struct {
 	Name        string `json:"name"`
@@ -59,6 +69,9 @@ type EnvironmentReport struct {
 	NpmRegistry     string `json:"npmRegistry,omitempty"`
 	RegistryLatency int    `json:"registryLatency,omitempty"` // ms
 
+	// npm 全局 prefix（bin 目录不在 PATH 时安装后找不到命令）
+	NpmPrefix *NpmPrefixStatus `json:"npmPrefix,omitempty"`
+
 	// 磁盘
 	HomeDirWritable bool    `json:"homeDirWritable"`
 	DiskFreeGB      float64 `json:"diskFreeGb,omitempty"`
@@ -70,11 +83,13 @@ type EnvironmentReport struct {
 	OpenClawCnInstalled bool   `json:"openClawCnInstalled"`
 	OpenClawCnVersion   string `json:"openClawCnVersion,omitempty"`
 	OpenClawConfigPath  string `json:"openClawConfigPath,omitempty"`
-	GatewayRunning      bool   `json:"gatewayRunning"`
-	GatewayPort         int    `json:"gatewayPort,omitempty"`
+	// 所有发现的 openclaw / openclaw-cn 安装（按真实路径去重）
+	OpenClawInstalls []OpenClawInstall `json:"openClawInstalls,omitempty"`
+	GatewayRunning   bool              `json:"gatewayRunning"`
+	GatewayPort      int               `json:"gatewayPort,omitempty"`
 
 	// 推荐安装方案
-	RecommendedMethod string   `json:"recommendedMethod"` // "installer-script" | "npm" | "docker"
+	RecommendedMethod string   `json:"recommendedMethod"` // "installer-script" | "npm" | "docker" | "offline"
 	RecommendedSteps  []Step   `json:"recommendedSteps"`
 	Warnings          []string `json:"warnings,omitempty"`
 
@@ -122,6 +137,7 @@ func Scan() (*EnvironmentReport, error) {
 	report.InternetAccess = checkInternetAccess()
 	if report.Tools["npm"].Installed {
 		report.NpmRegistry, report.RegistryLatency = detectNpmRegistry()
+		report.NpmPrefix = CheckNpmPrefix()
 	}
 
 	// 磁盘检测
@@ -137,11 +153,9 @@ func Scan() (*EnvironmentReport, error) {
 		report.OpenClawInstalled = true
 		report.OpenClawVersion = report.OpenClawCnVersion
 	}
-	report.OpenClawConfigPath = GetOpenClawConfigPath()
-	report.OpenClawConfigured = checkOpenClawConfigured(report.OpenClawConfigPath)
-	report.OpenClawConfigPath = GetOpenClawConfigPath()
-	report.OpenClawConfigured = checkOpenClawConfigured(report.OpenClawConfigPath)
-	report.GatewayRunning, report.GatewayPort = checkGatewayRunning()
+	report.OpenClawInstalls = detectOpenClawInstalls()
+	configPort := scanOpenClawConfig(report)
+	report.GatewayRunning, report.GatewayPort = checkGatewayRunningOn(openclaw.GatewayCandidatePortsWithConfig(0, configPort))
 
 	// 检查更新 (仅当已安装 OpenClaw 时)
 	if report.OpenClawInstalled {
@@ -194,15 +208,19 @@ func detectWSL() bool {
 	return strings.Contains(strings.ToLower(string(data)), "microsoft")
 }
 
-// detectDocker 检测是否在 Docker 容器中
+// detectDocker 检测是否在 Docker / Podman 容器中
 func detectDocker() bool {
 	// 检查 /.dockerenv 文件
 	if _, err := os.Stat("/.dockerenv"); err == nil {
 		return true
 	}
-	// 检查 /proc/1/cgroup 是否包含 docker
+	// Podman 容器内存在 /run/.containerenv
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		return true
+	}
+	// 检查 /proc/1/cgroup 是否包含 docker / libpod
 	data, err := os.ReadFile("/proc/1/cgroup")
-	if err == nil && strings.Contains(string(data), "docker") {
+	if err == nil && (strings.Contains(string(data), "docker") || strings.Contains(string(data), "libpod")) {
 		return true
 	}
 	return false
@@ -329,6 +347,9 @@ func detectTools() map[string]ToolInfo {
 	// Docker
 	tools["docker"] = detectTool("docker", "--version")
 
+	// Podman（rootless Linux 常用，CLI 与 docker 兼容）
+	tools["podman"] = detectTool("podman", "--version")
+
 	// Python
 	tools["python"] = detectPython()
 
@@ -601,6 +622,106 @@ func getNpmPaths() []string {
 	return paths
 }
 
+// detectOpenClawInstalls 检测所有 openclaw / openclaw-cn 安装（PATH 中的全部匹配 + 常见 npm 全局目录）
+// 按符号链接解析后的真实路径去重，以识别 "控制的到底是哪个二进制" 的混乱情况
+func detectOpenClawInstalls() []OpenClawInstall {
+	var installs []OpenClawInstall
+	seen := make(map[string]bool)
+
+	add := func(name, path string, inPath bool) {
+		if !fileExists(path) {
+			return
+		}
+		real := path
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			real = resolved
+		}
+		if seen[real] {
+			return
+		}
+		seen[real] = true
+		info := detectToolByPath(path, "--version")
+		inst := OpenClawInstall{Name: name, Path: path, Version: info.Version, InPath: inPath}
+		if real != path {
+			inst.RealPath = real
+		}
+		installs = append(installs, inst)
+	}
+
+	for _, name := range []string{"openclaw", "openclaw-cn"} {
+		for _, path := range lookPathAll(name) {
+			add(name, path, true)
+		}
+		for _, dir := range getNpmGlobalBinDirs() {
+			add(name, filepath.Join(dir, binaryName(name)), false)
+		}
+	}
+
+	return installs
+}
+
+// lookPathAll 返回 PATH 中所有名为 name 的可执行文件（exec.LookPath 只返回第一个）
+func lookPathAll(name string) []string {
+	var paths []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		candidates := []string{filepath.Join(dir, name)}
+		if runtime.GOOS == "windows" {
+			candidates = []string{filepath.Join(dir, name+".cmd"), filepath.Join(dir, name+".exe")}
+		}
+		for _, c := range candidates {
+			if p, err := exec.LookPath(c); err == nil {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// binaryName 返回平台相关的 npm 全局命令文件名
+func binaryName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".cmd"
+	}
+	return name
+}
+
+// getNpmGlobalBinDirs 获取 npm 全局命令可能所在的目录（复用 Node.js 多路径检测）
+func getNpmGlobalBinDirs() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, p := range getNodePaths() {
+		dir := filepath.Dir(p)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	if home, _ := os.UserHomeDir(); home != "" {
+		extra := []string{filepath.Join(home, ".npm-global", "bin")}
+		if runtime.GOOS == "windows" {
+			extra = []string{filepath.Join(home, "AppData", "Roaming", "npm")}
+		}
+		// nvm 下所有已安装版本各自有独立的全局 bin 目录
+		if entries, err := os.ReadDir(filepath.Join(home, ".nvm", "versions", "node")); err == nil {
+			for _, e := range entries {
+				if e.IsDir() {
+					extra = append(extra, filepath.Join(home, ".nvm", "versions", "node", e.Name(), "bin"))
+				}
+			}
+		}
+		for _, dir := range extra {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
 // detectToolByPath 通过完整路径检测工具
 func detectToolByPath(path string, versionArg string) ToolInfo {
 	info := ToolInfo{
@@ -799,19 +920,25 @@ func GetOpenClawConfigPath() string {
 	return openclaw.ResolveConfigPath()
 }
 
+// readConfigFile 读取配置文件（测试中替换以统计读取次数）
+var readConfigFile = os.ReadFile
+
+// scanOpenClawConfig 解析配置路径并只读取一次 openclaw.json，填充路径与是否已配置，
+// 返回 gateway.port（未配置为 0）供网关探测复用
+func scanOpenClawConfig(report *EnvironmentReport) int {
+	report.OpenClawConfigPath = GetOpenClawConfigPath()
+	raw := readOpenClawConfigRaw(report.OpenClawConfigPath)
+	report.OpenClawConfigured = openclawConfigured(raw)
+	return openclaw.GatewayBindingFromConfig(raw).Port
+}
+
 // checkOpenClawConfigured 检测 OpenClaw 是否已配置（有模型服务商）
 func checkOpenClawConfigured(configPath string) bool {
-	if configPath == "" {
-		return false
-	}
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return false
-	}
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return false
-	}
+	return openclawConfigured(readOpenClawConfigRaw(configPath))
+}
+
+// openclawConfigured 已解析的配置中是否有模型服务商，config 可为 nil
+func openclawConfigured(config map[string]interface{}) bool {
 	// 新 schema: models.providers 是一个非空对象
 	if models, ok := config["models"].(map[string]interface{}); ok {
 		if providers, ok := models["providers"].(map[string]interface{}); ok && len(providers) > 0 {
@@ -832,7 +959,7 @@ func readOpenClawConfigRaw(configPath string) map[string]interface{} {
 	if configPath == "" {
 		return nil
 	}
-	data, err := os.ReadFile(configPath)
+	data, err := readConfigFile(configPath)
 	if err != nil {
 		return nil
 	}
@@ -869,54 +996,22 @@ func checkConfigFileValid(configPath string) (exists bool, valid bool, detail st
 	return true, true, ""
 }
 
-// configGatewayPortFromFile 从配置文件读取 gateway.port
-func configGatewayPortFromFile(path string) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
-	}
-	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return ""
-	}
-	gw, ok := raw["gateway"].(map[string]interface{})
-	if !ok {
-		return ""
-	}
-	switch v := gw["port"].(type) {
-	case float64:
-		if v > 0 {
-			return fmt.Sprintf("%d", int(v))
-		}
-	case string:
-		return strings.TrimSpace(v)
-	}
-	return ""
-}
-
-// configGatewayBindFromFile 从配置文件读取 gateway.bind
-func configGatewayBindFromFile(path string) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
-	}
-	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return ""
-	}
-	gw, ok := raw["gateway"].(map[string]interface{})
-	if !ok {
-		return ""
-	}
-	if v, ok := gw["bind"].(string); ok {
-		return strings.TrimSpace(v)
-	}
-	return ""
-}
+// fallbackGatewayPorts 早期版本常用端口，追加在候选端口之后兜底探测
+var fallbackGatewayPorts = []int{18790, 18791}
 
 // checkGatewayRunning 检测 Gateway 是否运行（通过 HTTP 健康检查确认是真正的 OpenClaw Gateway）
+// 候选端口：默认端口、OPENCLAW_GATEWAY_PORT、openclaw.json 中的端口、设置中配置的额外端口
 func checkGatewayRunning() (running bool, port int) {
-	ports := []int{18789, 18790, 18791}
+	return checkGatewayRunningOn(openclaw.GatewayCandidatePorts(0))
+}
+
+// checkGatewayRunningOn 依次探测候选端口（追加早期版本常用端口兜底）
+func checkGatewayRunningOn(ports []int) (running bool, port int) {
+	for _, p := range fallbackGatewayPorts {
+		if !slices.Contains(ports, p) {
+			ports = append(ports, p)
+		}
+	}
 	client := &http.Client{Timeout: 2 * time.Second}
 	for _, p := range ports {
 		// 优先通过 /health 端点确认是 OpenClaw Gateway
@@ -1099,8 +1194,11 @@ func recommendInstallMethod(report *EnvironmentReport) string {
 		return ""
 	}
 
-	// 优先使用 npm，因为脚本安装包含交互式向导
+	// 优先使用 npm，因为脚本安装包含交互式向导；无法联网时只能离线安装
 	if report.Tools["node"].Installed && report.Tools["npm"].Installed {
+		if !report.InternetAccess {
+			return "offline"
+		}
 		return "npm"
 	}
 
@@ -1151,13 +1249,22 @@ func generateRecommendedSteps(report *EnvironmentReport) []Step {
 		})
 	}
 
-	// 安装 OpenClaw
-	steps = append(steps, Step{
-		Name:        "install-openclaw",
-		Description: "安装 OpenClaw",
-		Command:     getOpenClawInstallCommand(report),
-		Required:    true,
-	})
+	// 安装 OpenClaw（无法联网时建议使用离线安装包）
+	if !report.InternetAccess {
+		steps = append(steps, Step{
+			Name:        "install-openclaw-offline",
+			Description: "离线安装 OpenClaw：在可联网机器上执行 npm pack openclaw 获取安装包（或准备 npm 缓存目录），拷贝到本机后安装",
+			Command:     getOpenClawInstallCommand(&EnvironmentReport{RecommendedMethod: "offline"}),
+			Required:    true,
+		})
+	} else {
+		steps = append(steps, Step{
+			Name:        "install-openclaw",
+			Description: "安装 OpenClaw",
+			Command:     getOpenClawInstallCommand(report),
+			Required:    true,
+		})
+	}
 
 	// 配置
 	steps = append(steps, Step{
@@ -1236,6 +1343,8 @@ func getOpenClawInstallCommand(report *EnvironmentReport) string {
 		return "npm install -g openclaw@latest"
 	case "docker":
 		return "docker pull anthropic/openclaw:latest"
+	case "offline":
+		return "npm install -g ./openclaw-<version>.tgz --offline"
 	default:
 		return "npm install -g openclaw@latest"
 	}
@@ -1256,11 +1365,29 @@ func generateWarnings(report *EnvironmentReport) []string {
 		}
 	}
 
+	// 多个 OpenClaw 安装并存
+	if len(report.OpenClawInstalls) > 1 {
+		var items []string
+		for _, inst := range report.OpenClawInstalls {
+			version := inst.Version
+			if version == "" {
+				version = "unknown"
+			}
+			items = append(items, fmt.Sprintf("%s (%s)", inst.Path, version))
+		}
+		warnings = append(warnings, fmt.Sprintf("检测到 %d 个 OpenClaw 安装，可能导致控制的不是预期的版本: %s", len(report.OpenClawInstalls), strings.Join(items, "; ")))
+	}
+
 	// 权限警告
 	if report.IsRoot {
 		warnings = append(warnings, "不建议以 root 用户运行 OpenClaw")
 	}
 
+	// npm 全局 bin 目录不在 PATH 中
+	if p := report.NpmPrefix; p != nil && !p.OnPath {
+		warnings = append(warnings, fmt.Sprintf("npm 全局目录 %s 不在 PATH 中，全局安装的 openclaw 将无法直接运行。修复: %s", p.BinDir, p.FixCommand))
+	}
+
 	// 网络警告
 	if !report.InternetAccess {
 		warnings = append(warnings, "无法访问互联网，安装可能失败")
@@ -0,0 +1,40 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanOpenClawConfig_ReadsOnce(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("OPENCLAW_STATE_DIR", dir)
+	path := filepath.Join(dir, "openclaw.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"models":{"providers":{"openai":{}}},"gateway":{"port":"18800"}}`), 0o600))
+
+	reads := 0
+	orig := readConfigFile
+	readConfigFile = func(name string) ([]byte, error) {
+		reads++
+		return orig(name)
+	}
+	defer func() { readConfigFile = orig }()
+
+	report := &EnvironmentReport{}
+	port := scanOpenClawConfig(report)
+	assert.Equal(t, 1, reads)
+	assert.Equal(t, path, report.OpenClawConfigPath)
+	assert.True(t, report.OpenClawConfigured)
+	assert.Equal(t, 18800, port)
+}
+
+func TestScanOpenClawConfig_Missing(t *testing.T) {
+	t.Setenv("OPENCLAW_STATE_DIR", t.TempDir())
+	report := &EnvironmentReport{}
+	assert.Equal(t, 0, scanOpenClawConfig(report))
+	assert.False(t, report.OpenClawConfigured)
+}
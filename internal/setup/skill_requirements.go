@@ -0,0 +1,90 @@
+package setup
+
+import "runtime"
+
+// SkillRequirement 技能运行时依赖检查结果
+type SkillRequirement struct {
+	Name           string `json:"name"`
+	Label          string `json:"label,omitempty"`
+	Installed      bool   `json:"installed"`
+	Version        string `json:"version,omitempty"`
+	Hard           bool   `json:"hard"`                     // 缺失时技能无法运行
+	InstallCommand string `json:"installCommand,omitempty"` // 缺失时的安装命令（未知依赖为空）
+}
+
+// SkillRequirementsReport 技能依赖预检结果
+type SkillRequirementsReport struct {
+	Requirements []SkillRequirement `json:"requirements"`
+	Missing      []SkillRequirement `json:"missing"`
+	HardMissing  bool               `json:"hardMissing"`
+}
+
+// DetectSkillEnv 轻量环境检测（包管理器 + 工具），不做网络检查，适合技能安装前预检
+func DetectSkillEnv() *EnvironmentReport {
+	return &EnvironmentReport{
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		PackageManager: detectPackageManager(),
+		HasSudo:        detectSudo(),
+		Tools:          detectTools(),
+	}
+}
+
+// CheckSkillRequirements 对照环境检查技能声明的二进制依赖
+// bins 全部必需；anyBins 任一存在即可，全部缺失时视为必需
+func CheckSkillRequirements(env *EnvironmentReport, bins, anyBins []string) *SkillRequirementsReport {
+	report := &SkillRequirementsReport{Requirements: []SkillRequirement{}, Missing: []SkillRequirement{}}
+
+	anySatisfied := len(anyBins) == 0
+	var anyReqs []SkillRequirement
+	for _, name := range anyBins {
+		req := checkSkillBinary(env, name)
+		anySatisfied = anySatisfied || req.Installed
+		anyReqs = append(anyReqs, req)
+	}
+
+	for _, name := range bins {
+		req := checkSkillBinary(env, name)
+		req.Hard = true
+		report.add(req)
+	}
+	for _, req := range anyReqs {
+		req.Hard = !anySatisfied
+		report.add(req)
+	}
+	return report
+}
+
+func (r *SkillRequirementsReport) add(req SkillRequirement) {
+	r.Requirements = append(r.Requirements, req)
+	if req.Installed {
+		return
+	}
+	r.Missing = append(r.Missing, req)
+	if req.Hard {
+		r.HardMissing = true
+	}
+}
+
+// checkSkillBinary 优先使用扫描结果，未扫描的工具直接查找 PATH
+func checkSkillBinary(env *EnvironmentReport, name string) SkillRequirement {
+	req := SkillRequirement{Name: name}
+	if info, ok := env.Tools[name]; ok {
+		req.Installed = info.Installed
+		req.Version = info.Version
+	} else {
+		req.Installed = commandExists(name)
+	}
+
+	for _, dep := range skillDeps() {
+		if dep.name != name {
+			continue
+		}
+		req.Label = dep.label
+		if !req.Installed {
+			req.InstallCommand, _ = skillDepCommand(env, dep)
+		}
+		break
+	}
+	return req
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,14 +23,23 @@ type SetupEvent struct {
 	Step     string      `json:"step,omitempty"`     // 当前步骤
 	Message  string      `json:"message"`            // 消息内容
 	Progress int         `json:"progress,omitempty"` // 进度百分比 0-100
+	Stream   string      `json:"stream,omitempty"`   // 日志来源 "stdout" | "stderr"
 	Data     interface{} `json:"data,omitempty"`     // 附加数据
 }
 
+// maxTranscriptBytes 事件记录保留的最大字节数，超出时丢弃最早的行
+const maxTranscriptBytes = 64 << 10
+
 // EventEmitter SSE 事件发送器
 type EventEmitter struct {
 	w       http.ResponseWriter
 	flusher http.Flusher
 	mu      sync.Mutex
+
+	// 已发送事件的文本记录（用于持久化安装历史）
+	transcript     []string
+	transcriptSize int
+	lastError      string
 }
 
 // NewEventEmitter 创建事件发送器
@@ -56,6 +66,8 @@ func (e *EventEmitter) Emit(event SetupEvent) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	e.record(event)
+
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
@@ -70,6 +82,43 @@ func (e *EventEmitter) Emit(event SetupEvent) error {
 	return nil
 }
 
+// record 记录事件文本，调用方需持有 e.mu
+func (e *EventEmitter) record(event SetupEvent) {
+	line := event.Message
+	switch event.Type {
+	case "phase", "step":
+		line = "==> " + line
+	case "error":
+		e.lastError = event.Message
+		if m, ok := event.Data.(map[string]string); ok && m["error"] != "" {
+			e.lastError += ": " + m["error"]
+		}
+		line = "ERROR: " + e.lastError
+	case "progress":
+		return
+	}
+	e.transcript = append(e.transcript, line)
+	e.transcriptSize += len(line) + 1
+	for e.transcriptSize > maxTranscriptBytes && len(e.transcript) > 1 {
+		e.transcriptSize -= len(e.transcript[0]) + 1
+		e.transcript = e.transcript[1:]
+	}
+}
+
+// Transcript 返回已发送事件的文本记录（仅保留最近 maxTranscriptBytes）
+func (e *EventEmitter) Transcript() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.transcript, "\n")
+}
+
+// LastError 返回最后一次 error 事件的消息，没有错误时为空
+func (e *EventEmitter) LastError() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastError
+}
+
 // EmitPhase 发送阶段开始事件
 func (e *EventEmitter) EmitPhase(phase, message string, progress int) error {
 	return e.Emit(SetupEvent{
@@ -164,7 +213,53 @@ func NewStreamCommandWithSudo(emitter *EventEmitter, phase, step, sudoPassword s
 
 // Run 执行命令并流式输出
 func (sc *StreamCommand) Run(ctx context.Context, name string, args ...string) error {
+	return sc.run(ctx, nil, name, args...)
+}
+
+// RunPrivileged 以 argv 形式执行需要管理员权限的命令（不经过 shell）：
+// 非 Windows 且非 root 时经 sudo 执行，设置了 sudoPassword 时通过 SUDO_ASKPASS 注入密码
+func (sc *StreamCommand) RunPrivileged(ctx context.Context, name string, args ...string) error {
+	if isWindows() || os.Getuid() == 0 {
+		return sc.run(ctx, nil, name, args...)
+	}
+	if sc.sudoPassword == "" {
+		return sc.run(ctx, nil, "sudo", append([]string{name}, args...)...)
+	}
+	askpass, err := writeAskpass(sc.sudoPassword)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(askpass)
+	env := append(os.Environ(), "SUDO_ASKPASS="+askpass)
+	return sc.run(ctx, env, "sudo", append([]string{"-A", name}, args...)...)
+}
+
+// writeAskpass 创建仅当前用户可读写执行的 askpass 脚本，输出 sudo 密码
+func writeAskpass(password string) (string, error) {
+	f, err := os.CreateTemp("", "ocd-askpass-*")
+	if err != nil {
+		return "", fmt.Errorf("创建 askpass 脚本失败: %w", err)
+	}
+	escaped := strings.ReplaceAll(password, "'", "'\\''")
+	_, werr := fmt.Fprintf(f, "#!/bin/sh\nprintf '%%s\\n' '%s'\n", escaped)
+	cerr := f.Close()
+	if werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Chmod(f.Name(), 0o700)
+	}
+	if werr != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("创建 askpass 脚本失败: %w", werr)
+	}
+	return f.Name(), nil
+}
+
+// run 执行命令并流式输出；env 非空时替换子进程环境变量
+func (sc *StreamCommand) run(ctx context.Context, env []string, name string, args ...string) error {
 	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = env
 
 	// Windows 下强制子进程使用 UTF-8 输出
 	if isWindows() {
@@ -189,15 +284,16 @@ func (sc *StreamCommand) Run(ctx context.Context, name string, args ...string) e
 	// 并发读取输出
 	var wg sync.WaitGroup
 	wg.Add(2)
+	tail := &lineTail{max: stderrTailLines}
 
 	go func() {
 		defer wg.Done()
-		sc.streamOutput(stdout, "stdout")
+		sc.streamOutput(stdout, "stdout", nil)
 	}()
 
 	go func() {
 		defer wg.Done()
-		sc.streamOutput(stderr, "stderr")
+		sc.streamOutput(stderr, "stderr", tail)
 	}()
 
 	// 等待输出读取完成
@@ -205,27 +301,82 @@ func (sc *StreamCommand) Run(ctx context.Context, name string, args ...string) e
 
 	// 等待命令完成
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("命令执行失败: %w", err)
+		return &CommandError{Err: err, Stderr: tail.Lines()}
 	}
 
 	return nil
 }
 
-// streamOutput 流式读取输出
-func (sc *StreamCommand) streamOutput(r io.Reader, source string) {
+// streamOutput 流式读取输出，tail 非空时记录最近的输出行
+func (sc *StreamCommand) streamOutput(r io.Reader, source string, tail *lineTail) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
+		if tail != nil {
+			tail.Add(line)
+		}
 		sc.emitter.Emit(SetupEvent{
 			Type:    "log",
 			Phase:   sc.phase,
 			Step:    sc.step,
 			Message: line,
+			Stream:  source,
 			Data:    map[string]string{"source": source},
 		})
 	}
 }
 
+// stderrTailLines 命令失败时附带的 stderr 行数
+const stderrTailLines = 10
+
+// CommandError 命令执行失败，附带最后几行 stderr 便于排查
+type CommandError struct {
+	Err    error
+	Stderr []string
+}
+
+// Error 只返回失败原因；stderr 尾部由调用方通过 StderrTail 单独展示，避免重复
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("命令执行失败: %v", e.Err)
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// StderrTail 提取错误中附带的 stderr 尾部输出（非 CommandError 返回 nil）
+func StderrTail(err error) []string {
+	var ce *CommandError
+	if errors.As(err, &ce) {
+		return ce.Stderr
+	}
+	return nil
+}
+
+// lineTail 保留最近 max 行输出（并发安全）
+type lineTail struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+}
+
+func (t *lineTail) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+}
+
+func (t *lineTail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.lines...)
+}
+
 // RunShell 执行 shell 命令
 // 如果命令包含 sudo 且设置了 sudoPassword，自动注入密码
 func (sc *StreamCommand) RunShell(ctx context.Context, command string) error {
@@ -269,15 +420,16 @@ func (sc *StreamCommand) RunShell(ctx context.Context, command string) error {
 	// 并发读取输出
 	var wg sync.WaitGroup
 	wg.Add(2)
+	tail := &lineTail{max: stderrTailLines}
 
 	go func() {
 		defer wg.Done()
-		sc.streamOutput(stdout, "stdout")
+		sc.streamOutput(stdout, "stdout", nil)
 	}()
 
 	go func() {
 		defer wg.Done()
-		sc.streamOutput(stderr, "stderr")
+		sc.streamOutput(stderr, "stderr", tail)
 	}()
 
 	// 等待输出读取完成
@@ -285,7 +437,7 @@ func (sc *StreamCommand) RunShell(ctx context.Context, command string) error {
 
 	// 等待命令完成
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("命令执行失败: %w", err)
+		return &CommandError{Err: err, Stderr: tail.Lines()}
 	}
 
 	return nil
@@ -0,0 +1,86 @@
+package setup
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// OpenClawUpdateTTL OpenClaw 版本检查结果缓存时长（避免频繁调用 npm view）
+const OpenClawUpdateTTL = 6 * time.Hour
+
+// OpenClawUpdateInfo OpenClaw 更新检查结果
+type OpenClawUpdateInfo struct {
+	CurrentVersion  string    `json:"currentVersion"`
+	LatestVersion   string    `json:"latestVersion"`
+	UpdateAvailable bool      `json:"updateAvailable"`
+	CheckedAt       time.Time `json:"checkedAt"`
+}
+
+// openclawUpdateErrorTTL npm view 失败时的短缓存，避免离线时反复调用
+const openclawUpdateErrorTTL = 10 * time.Minute
+
+var openclawUpdateCache struct {
+	mu   sync.Mutex
+	info *OpenClawUpdateInfo
+}
+
+// openclawUpdateGroup 合并并发检查；检查期间不持有缓存锁
+var openclawUpdateGroup singleflight.Group
+
+// Failed 已安装但未能获取最新版本（npm 不可用或网络故障）
+func (i *OpenClawUpdateInfo) Failed() bool {
+	return i.CurrentVersion != "" && i.LatestVersion == ""
+}
+
+// CachedOpenClawUpdate 返回缓存的 OpenClaw 更新检查结果，过期或 force 时重新检查
+func CachedOpenClawUpdate(force bool) OpenClawUpdateInfo {
+	if cached := LastOpenClawUpdate(); !force && cached != nil {
+		ttl := OpenClawUpdateTTL
+		if cached.Failed() {
+			ttl = openclawUpdateErrorTTL
+		}
+		if time.Since(cached.CheckedAt) < ttl {
+			return *cached
+		}
+	}
+	v, _, _ := openclawUpdateGroup.Do("openclaw", func() (interface{}, error) {
+		info := checkOpenClawUpdate()
+		openclawUpdateCache.mu.Lock()
+		// 检查失败时保留仍在有效期内的成功结果
+		prev := openclawUpdateCache.info
+		if !info.Failed() || prev == nil || prev.Failed() || time.Since(prev.CheckedAt) >= OpenClawUpdateTTL {
+			openclawUpdateCache.info = &info
+		}
+		openclawUpdateCache.mu.Unlock()
+		return info, nil
+	})
+	return v.(OpenClawUpdateInfo)
+}
+
+func checkOpenClawUpdate() OpenClawUpdateInfo {
+	info := OpenClawUpdateInfo{CheckedAt: time.Now()}
+	tool := detectTool("openclaw", "--version")
+	if !tool.Installed {
+		tool = detectTool("openclaw-cn", "--version")
+	}
+	if tool.Installed {
+		info.CurrentVersion = tool.Version
+		info.LatestVersion = fetchLatestVersion()
+		// 与 Scan 保持一致：版本不同即视为有更新
+		info.UpdateAvailable = info.CurrentVersion != "" && info.LatestVersion != "" && info.CurrentVersion != info.LatestVersion
+	}
+	return info
+}
+
+// LastOpenClawUpdate 返回缓存的检查结果，不触发检查（尚未检查时返回 nil）
+func LastOpenClawUpdate() *OpenClawUpdateInfo {
+	openclawUpdateCache.mu.Lock()
+	defer openclawUpdateCache.mu.Unlock()
+	if openclawUpdateCache.info == nil {
+		return nil
+	}
+	info := *openclawUpdateCache.info
+	return &info
+}
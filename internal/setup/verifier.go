@@ -144,6 +144,11 @@ func (v *Verifier) runDoctor(ctx context.Context) *DoctorResult {
 	return result
 }
 
+// OpenClawVersion 返回 openclaw --version 的版本号，未安装或无法执行时为空
+func OpenClawVersion() string {
+	return detectTool("openclaw", "--version").Version
+}
+
 // QuickCheck 快速检查（不运行 doctor）
 func QuickCheck() *VerifyResult {
 	result := &VerifyResult{
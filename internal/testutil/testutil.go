@@ -31,6 +31,7 @@ func SetupTestDB(t *testing.T) func() {
 		&database.AuditLog{},
 		&database.RiskRule{},
 		&database.MonitorState{},
+		&database.MonitorSessionSnapshot{},
 		&database.BackupRecord{},
 		&database.Setting{},
 		&database.CredentialScan{},
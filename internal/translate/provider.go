@@ -0,0 +1,216 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by the translation_provider setting.
+const (
+	ProviderPublic = "public"         // MyMemory with Google Translate fallback
+	ProviderModel  = "model"          // the primary model configured in openclaw.json
+	ProviderLibre  = "libretranslate" // a LibreTranslate-compatible API (can be self-hosted)
+	ProviderNone   = "none"           // no translation, original text is kept
+)
+
+// Providers lists the valid provider names.
+var Providers = []string{ProviderPublic, ProviderModel, ProviderLibre, ProviderNone}
+
+// Provider translates text between languages. Implementations return the
+// original text together with any error so callers can fall back to it.
+type Provider interface {
+	Name() string
+	Translate(ctx context.Context, text, source, target string) (string, error)
+}
+
+// Noop is the offline provider: it returns the text unchanged.
+type Noop struct{}
+
+// Name implements Provider.
+func (Noop) Name() string { return ProviderNone }
+
+// Translate implements Provider.
+func (Noop) Translate(_ context.Context, text, _, _ string) (string, error) {
+	return text, nil
+}
+
+// Libre calls a LibreTranslate-compatible POST /translate endpoint.
+type Libre struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewLibre returns a LibreTranslate provider for baseURL (e.g. http://localhost:5000).
+func NewLibre(baseURL, apiKey string) *Libre {
+	return &Libre{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+// Name implements Provider.
+func (l *Libre) Name() string { return ProviderLibre }
+
+// Translate implements Provider.
+func (l *Libre) Translate(ctx context.Context, text, source, target string) (string, error) {
+	if text == "" || target == "" || target == source {
+		return text, nil
+	}
+	if source == "" {
+		source = "en"
+	}
+	payload := map[string]string{"q": text, "source": source, "target": target, "format": "text"}
+	if l.apiKey != "" {
+		payload["api_key"] = l.apiKey
+	}
+	body, _ := json.Marshal(payload)
+	respBody, err := postJSON(ctx, l.client, l.baseURL+"/translate", nil, body)
+	if err != nil {
+		return text, fmt.Errorf("libretranslate: %w", err)
+	}
+	var resp struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return text, fmt.Errorf("parse libretranslate json: %w", err)
+	}
+	if out := strings.TrimSpace(resp.TranslatedText); out != "" {
+		return out, nil
+	}
+	return text, fmt.Errorf("libretranslate empty translation")
+}
+
+// ModelConfig identifies a chat model: the provider name decides the wire
+// format (anthropic or OpenAI-compatible).
+type ModelConfig struct {
+	Provider string
+	BaseURL  string
+	APIKey   string
+	Model    string
+}
+
+// Model translates by prompting a chat model.
+type Model struct {
+	cfg    ModelConfig
+	client *http.Client
+}
+
+// NewModel returns a provider backed by the given chat model.
+func NewModel(cfg ModelConfig) *Model {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &Model{cfg: cfg, client: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// Name implements Provider.
+func (m *Model) Name() string { return ProviderModel }
+
+// Translate implements Provider.
+func (m *Model) Translate(ctx context.Context, text, source, target string) (string, error) {
+	if text == "" || target == "" || target == source {
+		return text, nil
+	}
+	if source == "" {
+		source = "en"
+	}
+	endpoint, headers, body := m.request(translationPrompt(text, source, target))
+	respBody, err := postJSON(ctx, m.client, endpoint, headers, body)
+	if err != nil {
+		return text, fmt.Errorf("model %s/%s: %w", m.cfg.Provider, m.cfg.Model, err)
+	}
+	out, err := parseModelResponse(m.cfg.Provider, respBody)
+	if err != nil {
+		return text, err
+	}
+	return out, nil
+}
+
+func translationPrompt(text, source, target string) string {
+	return fmt.Sprintf("Translate the following text from %s to %s. Keep product names, commands and code unchanged. Reply with the translation only.\n\n%s", source, target, text)
+}
+
+// request builds the chat request for the configured provider.
+func (m *Model) request(prompt string) (endpoint string, headers map[string]string, body []byte) {
+	messages := []map[string]string{{"role": "user", "content": prompt}}
+	if strings.EqualFold(m.cfg.Provider, "anthropic") {
+		base := m.cfg.BaseURL
+		if base == "" {
+			base = "https://api.anthropic.com"
+		}
+		body, _ = json.Marshal(map[string]interface{}{"model": m.cfg.Model, "max_tokens": 1024, "messages": messages})
+		return base + "/v1/messages", map[string]string{"x-api-key": m.cfg.APIKey, "anthropic-version": "2023-06-01"}, body
+	}
+	base := m.cfg.BaseURL
+	if base == "" {
+		base = "https://api.openai.com/v1"
+	}
+	headers = map[string]string{}
+	if m.cfg.APIKey != "" {
+		headers["Authorization"] = "Bearer " + m.cfg.APIKey
+	}
+	body, _ = json.Marshal(map[string]interface{}{"model": m.cfg.Model, "max_tokens": 1024, "messages": messages})
+	return base + "/chat/completions", headers, body
+}
+
+// parseModelResponse extracts the reply text from an anthropic or
+// OpenAI-compatible chat response.
+func parseModelResponse(provider string, body []byte) (string, error) {
+	var resp struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("parse %s response: %w", provider, err)
+	}
+	var out string
+	if len(resp.Choices) > 0 {
+		out = resp.Choices[0].Message.Content
+	} else {
+		for _, c := range resp.Content {
+			out += c.Text
+		}
+	}
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", fmt.Errorf("%s returned an empty translation", provider)
+	}
+	return out, nil
+}
+
+// postJSON sends body and returns the response body, failing on HTTP errors.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, truncate(string(respBody), 200))
+	}
+	return respBody, nil
+}
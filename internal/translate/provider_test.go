@@ -0,0 +1,47 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibre_Translate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "/translate", r.URL.Path)
+		assert.Equal(t, "zh", req["target"])
+		assert.Equal(t, "k1", req["api_key"])
+		w.Write([]byte(`{"translatedText":"你好"}`))
+	}))
+	defer srv.Close()
+
+	out, err := NewLibre(srv.URL+"/", "k1").Translate(context.Background(), "hello", "en", "zh")
+	require.NoError(t, err)
+	assert.Equal(t, "你好", out)
+}
+
+func TestParseModelResponse(t *testing.T) {
+	out, err := parseModelResponse("openai", []byte(`{"choices":[{"message":{"content":" 你好 \n"}}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "你好", out)
+
+	out, err = parseModelResponse("anthropic", []byte(`{"content":[{"type":"text","text":"你好"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "你好", out)
+
+	_, err = parseModelResponse("openai", []byte(`{"choices":[]}`))
+	assert.Error(t, err)
+}
+
+func TestNoop(t *testing.T) {
+	out, err := Noop{}.Translate(context.Background(), "hello", "en", "zh")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", out)
+}
@@ -14,8 +14,9 @@ import (
 	"openclawdeck/internal/logger"
 )
 
-// Translator provides text translation with dual-engine fallback:
-// MyMemory (primary, fast, China-friendly) → Google Translate (fallback).
+// Translator provides text translation through the free public web engines with
+// dual-engine fallback: MyMemory (primary, fast, China-friendly) → Google
+// Translate (fallback).
 type Translator struct {
 	client  *http.Client
 	mu      sync.Mutex
@@ -42,6 +43,9 @@ var langMap = map[string]string{
 	"it": "it", "nl": "nl", "pl": "pl", "tr": "tr",
 }
 
+// Name implements Provider.
+func (t *Translator) Name() string { return ProviderPublic }
+
 func resolveMyMemoryLang(lang string) string {
 	if mapped, ok := langMap[lang]; ok {
 		return mapped
@@ -14,10 +14,9 @@ import (
 // Run starts the system tray icon and opens the browser.
 // onReady is called after the tray is initialized.
 // This function blocks until the user quits via the tray menu.
-func Run(addr string, onQuit func()) {
+func Run(baseURL string, onQuit func()) {
 	// 0.0.0.0 不是有效的浏览器地址，替换为 127.0.0.1
-	browserAddr := strings.Replace(addr, "0.0.0.0", "127.0.0.1", 1)
-	url := fmt.Sprintf("http://%s", browserAddr)
+	url := strings.Replace(baseURL, "0.0.0.0", "127.0.0.1", 1)
 
 	systray.Run(func() {
 		systray.SetIcon(generateIcon())
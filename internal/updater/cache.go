@@ -0,0 +1,74 @@
+package updater
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// CheckCacheTTL is how long a release check result is reused before GitHub is queried again.
+	CheckCacheTTL = 6 * time.Hour
+	// checkErrorTTL keeps a failed check briefly so an unreachable GitHub isn't hammered.
+	checkErrorTTL = 10 * time.Minute
+)
+
+var checkCache struct {
+	mu      sync.Mutex
+	result  *CheckResult
+	checked time.Time
+}
+
+// checkGroup collapses concurrent checks into one GitHub request; the cache
+// mutex is never held while the request is in flight.
+var checkGroup singleflight.Group
+
+// checkForUpdate is swapped out in tests.
+var checkForUpdate = CheckForUpdate
+
+// Failed reports whether the check could not determine the latest release.
+func (r *CheckResult) Failed() bool {
+	return r == nil || r.LatestVersion == ""
+}
+
+// CachedCheck returns the last release check result, querying GitHub only when
+// the cache is older than CheckCacheTTL (checkErrorTTL after a failure) or force is set.
+func CachedCheck(ctx context.Context, force bool) *CheckResult {
+	if !force {
+		if res, checked := LastCheck(); res != nil && time.Since(checked) < cacheTTL(res) {
+			return res
+		}
+	}
+	v, _, _ := checkGroup.Do("check", func() (interface{}, error) {
+		result, err := checkForUpdate(ctx)
+		if err != nil {
+			result = &CheckResult{Error: err.Error()}
+		}
+		checkCache.mu.Lock()
+		// a failed check must not replace a good result still within its TTL
+		if !result.Failed() || checkCache.result == nil || checkCache.result.Failed() ||
+			time.Since(checkCache.checked) >= CheckCacheTTL {
+			checkCache.result = result
+			checkCache.checked = time.Now()
+		}
+		checkCache.mu.Unlock()
+		return result, nil
+	})
+	return v.(*CheckResult)
+}
+
+func cacheTTL(res *CheckResult) time.Duration {
+	if res.Failed() {
+		return checkErrorTTL
+	}
+	return CheckCacheTTL
+}
+
+// LastCheck returns the cached release check result without querying GitHub (nil if none yet).
+func LastCheck() (*CheckResult, time.Time) {
+	checkCache.mu.Lock()
+	defer checkCache.mu.Unlock()
+	return checkCache.result, checkCache.checked
+}
@@ -0,0 +1,92 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetCheckCache(t *testing.T, fn func(context.Context) (*CheckResult, error)) {
+	t.Helper()
+	checkForUpdate = fn
+	checkCache.result, checkCache.checked = nil, time.Time{}
+	t.Cleanup(func() {
+		checkForUpdate = CheckForUpdate
+		checkCache.result, checkCache.checked = nil, time.Time{}
+	})
+}
+
+func TestCachedCheck_FailureUsesShortTTL(t *testing.T) {
+	var calls atomic.Int32
+	resetCheckCache(t, func(context.Context) (*CheckResult, error) {
+		calls.Add(1)
+		return &CheckResult{Error: "GitHub API returned 503"}, nil
+	})
+
+	assert.True(t, CachedCheck(context.Background(), false).Failed())
+	CachedCheck(context.Background(), false)
+	assert.Equal(t, int32(1), calls.Load(), "failure is cached briefly")
+
+	// past checkErrorTTL but well within CheckCacheTTL: query again
+	checkCache.checked = time.Now().Add(-checkErrorTTL - time.Second)
+	CachedCheck(context.Background(), false)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCachedCheck_FailureKeepsGoodResult(t *testing.T) {
+	fail := false
+	resetCheckCache(t, func(context.Context) (*CheckResult, error) {
+		if fail {
+			return nil, errors.New("dial tcp: timeout")
+		}
+		return &CheckResult{LatestVersion: "1.2.3"}, nil
+	})
+
+	CachedCheck(context.Background(), true)
+	fail = true
+	res := CachedCheck(context.Background(), true)
+	assert.True(t, res.Failed())
+	assert.Equal(t, "dial tcp: timeout", res.Error)
+
+	last, _ := LastCheck()
+	assert.Equal(t, "1.2.3", last.LatestVersion)
+}
+
+func TestCachedCheck_ConcurrentCallersShareFetch(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	resetCheckCache(t, func(context.Context) (*CheckResult, error) {
+		calls.Add(1)
+		<-release
+		return &CheckResult{LatestVersion: "1.2.3"}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			CachedCheck(context.Background(), true)
+		}()
+	}
+	// the cache stays readable while the fetch is in flight
+	done := make(chan struct{})
+	go func() {
+		LastCheck()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LastCheck blocked by an in-flight check")
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
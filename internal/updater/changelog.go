@@ -0,0 +1,335 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"openclawdeck/internal/version"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// NpmRegistry is queried for the OpenClaw package metadata (repository URL).
+	NpmRegistry = "https://registry.npmjs.org"
+	// OpenClawPackage is the npm package name of OpenClaw.
+	OpenClawPackage = "openclaw"
+
+	// ChangelogCacheTTL is how long fetched release notes are reused.
+	ChangelogCacheTTL = 12 * time.Hour
+	// changelogErrorTTL keeps failed lookups briefly so a flaky network isn't hammered.
+	changelogErrorTTL = 10 * time.Minute
+	// maxChangelogEntries bounds the cache; from/to come from query parameters.
+	maxChangelogEntries = 32
+)
+
+// ReleaseNote is the notes of a single release.
+type ReleaseNote struct {
+	Version     string `json:"version"`
+	Name        string `json:"name,omitempty"`
+	PublishedAt string `json:"publishedAt,omitempty"`
+	Body        string `json:"body"`
+	URL         string `json:"url,omitempty"`
+}
+
+// Changelog holds the release notes for the versions in (From, To].
+type Changelog struct {
+	Project   string        `json:"project"` // "openclaw" or "openclawdeck"
+	From      string        `json:"from,omitempty"`
+	To        string        `json:"to,omitempty"`
+	Source    string        `json:"source,omitempty"` // GitHub repo the notes came from
+	Available bool          `json:"available"`
+	Releases  []ReleaseNote `json:"releases"`
+	Markdown  string        `json:"markdown"`
+	Error     string        `json:"error,omitempty"`
+	FetchedAt time.Time     `json:"fetchedAt"`
+}
+
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Draft       bool      `json:"draft"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// versionPattern accepts semver with an optional "v" prefix and pre-release/build suffix.
+var versionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// ValidVersion reports whether v is a semver string usable as a changelog bound.
+func ValidVersion(v string) bool {
+	return len(v) <= 64 && versionPattern.MatchString(v)
+}
+
+var changelogCache struct {
+	mu      sync.Mutex
+	entries map[string]*Changelog
+}
+
+// changelogGroup collapses concurrent fetches of the same range; the cache
+// mutex is never held while GitHub is queried.
+var changelogGroup singleflight.Group
+
+// cachedChangelog returns a cached changelog for key or builds one with fetch.
+func cachedChangelog(key string, fetch func() *Changelog) *Changelog {
+	changelogCache.mu.Lock()
+	c, ok := changelogCache.entries[key]
+	changelogCache.mu.Unlock()
+	if ok && time.Since(c.FetchedAt) < changelogTTL(c) {
+		return c
+	}
+
+	v, _, _ := changelogGroup.Do(key, func() (interface{}, error) {
+		c := fetch()
+		c.FetchedAt = time.Now()
+		changelogCache.mu.Lock()
+		defer changelogCache.mu.Unlock()
+		if changelogCache.entries == nil {
+			changelogCache.entries = map[string]*Changelog{}
+		}
+		if _, exists := changelogCache.entries[key]; !exists && len(changelogCache.entries) >= maxChangelogEntries {
+			evictChangelog()
+		}
+		changelogCache.entries[key] = c
+		return c, nil
+	})
+	return v.(*Changelog)
+}
+
+func changelogTTL(c *Changelog) time.Duration {
+	if c.Error != "" {
+		return changelogErrorTTL
+	}
+	return ChangelogCacheTTL
+}
+
+// evictChangelog drops expired entries, or the oldest one if none expired.
+// Caller holds changelogCache.mu.
+func evictChangelog() {
+	var oldest string
+	for k, c := range changelogCache.entries {
+		if time.Since(c.FetchedAt) >= changelogTTL(c) {
+			delete(changelogCache.entries, k)
+			continue
+		}
+		if oldest == "" || c.FetchedAt.Before(changelogCache.entries[oldest].FetchedAt) {
+			oldest = k
+		}
+	}
+	if len(changelogCache.entries) >= maxChangelogEntries {
+		delete(changelogCache.entries, oldest)
+	}
+}
+
+// invalidRange returns an error changelog (not cached) when from or to is not semver.
+func invalidRange(project, from, to string) *Changelog {
+	for _, v := range []string{from, to} {
+		if v != "" && !ValidVersion(v) {
+			return finishChangelog(&Changelog{Project: project, From: from, To: to, Error: "invalid version: " + v})
+		}
+	}
+	return nil
+}
+
+// DeckChangelog returns the OpenClawDeck release notes between from and to.
+func DeckChangelog(ctx context.Context, from, to string) *Changelog {
+	if c := invalidRange("openclawdeck", from, to); c != nil {
+		return c
+	}
+	key := "openclawdeck|" + strings.TrimPrefix(from, "v") + "|" + strings.TrimPrefix(to, "v")
+	return cachedChangelog(key, func() *Changelog {
+		c := &Changelog{Project: "openclawdeck", From: from, To: to, Source: GitHubOwner + "/" + GitHubRepo}
+		fillChangelog(ctx, c, GitHubOwner, GitHubRepo)
+		return c
+	})
+}
+
+// OpenClawChangelog returns the OpenClaw release notes between from and to. The
+// GitHub repository is taken from the npm package metadata.
+func OpenClawChangelog(ctx context.Context, from, to string) *Changelog {
+	if c := invalidRange("openclaw", from, to); c != nil {
+		return c
+	}
+	key := "openclaw|" + strings.TrimPrefix(from, "v") + "|" + strings.TrimPrefix(to, "v")
+	return cachedChangelog(key, func() *Changelog {
+		c := &Changelog{Project: "openclaw", From: from, To: to}
+		owner, repo, err := npmRepository(ctx, OpenClawPackage)
+		if err != nil {
+			c.Error = err.Error()
+			return finishChangelog(c)
+		}
+		c.Source = owner + "/" + repo
+		fillChangelog(ctx, c, owner, repo)
+		return c
+	})
+}
+
+// fillChangelog fetches GitHub releases of owner/repo into c.
+func fillChangelog(ctx context.Context, c *Changelog, owner, repo string) {
+	releases, err := fetchGitHubReleases(ctx, owner, repo)
+	if err != nil {
+		c.Error = err.Error()
+	} else {
+		c.Releases = releasesInRange(releases, c.From, c.To)
+	}
+	finishChangelog(c)
+}
+
+func finishChangelog(c *Changelog) *Changelog {
+	if c.Releases == nil {
+		c.Releases = []ReleaseNote{}
+	}
+	c.Available = len(c.Releases) > 0
+	c.Markdown = changelogMarkdown(c.Releases)
+	return c
+}
+
+// fetchGitHubReleases lists the most recent releases of owner/repo.
+func fetchGitHubReleases(ctx context.Context, owner, repo string) ([]githubRelease, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	u := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100", GitHubAPI, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "OpenClawDeck/"+version.Version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("no releases found for %s/%s", owner, repo)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("GitHub API returned %d", resp.StatusCode)
+	}
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// npmRepository resolves the GitHub owner/repo of an npm package from its registry metadata.
+func npmRepository(ctx context.Context, pkg string) (owner, repo string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", NpmRegistry+"/"+url.PathEscape(pkg), nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", "", fmt.Errorf("npm registry returned %d", resp.StatusCode)
+	}
+
+	var meta struct {
+		Repository json.RawMessage `json:"repository"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return "", "", err
+	}
+	// repository is either {"type":"git","url":"..."} or a plain string
+	var repoURL string
+	var obj struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(meta.Repository, &obj) == nil && obj.URL != "" {
+		repoURL = obj.URL
+	} else {
+		json.Unmarshal(meta.Repository, &repoURL)
+	}
+	owner, repo, ok := parseGitHubRepo(repoURL)
+	if !ok {
+		return "", "", fmt.Errorf("package %s has no GitHub repository", pkg)
+	}
+	return owner, repo, nil
+}
+
+var githubRepoPattern = regexp.MustCompile(`github\.com[/:]([\w.-]+)/([\w.-]+?)(?:\.git)?/?$`)
+
+// parseGitHubRepo extracts owner/repo from npm repository URLs such as
+// git+https://github.com/o/r.git, git@github.com:o/r.git or github:o/r.
+func parseGitHubRepo(u string) (owner, repo string, ok bool) {
+	u = strings.TrimSpace(u)
+	if rest, found := strings.CutPrefix(u, "github:"); found {
+		u = "github.com/" + rest
+	}
+	m := githubRepoPattern.FindStringSubmatch(u)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// releasesInRange keeps non-draft releases with from < version <= to, newest
+// first. An empty from keeps only to; an empty to keeps everything newer than from.
+func releasesInRange(releases []githubRelease, from, to string) []ReleaseNote {
+	var notes []ReleaseNote
+	for _, rel := range releases {
+		if rel.Draft {
+			continue
+		}
+		v := strings.TrimPrefix(rel.TagName, "v")
+		if to != "" && compareSemver(v, to) > 0 {
+			continue
+		}
+		if from != "" && compareSemver(v, from) <= 0 {
+			continue
+		}
+		if from == "" && to != "" && compareSemver(v, to) != 0 {
+			continue
+		}
+		note := ReleaseNote{Version: v, Name: rel.Name, Body: strings.TrimSpace(rel.Body), URL: rel.HTMLURL}
+		if !rel.PublishedAt.IsZero() {
+			note.PublishedAt = rel.PublishedAt.Format(time.RFC3339)
+		}
+		notes = append(notes, note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return compareSemver(notes[i].Version, notes[j].Version) > 0
+	})
+	return notes
+}
+
+// changelogMarkdown renders the notes as one markdown document, one section per release.
+func changelogMarkdown(notes []ReleaseNote) string {
+	var b strings.Builder
+	for i, n := range notes {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("## " + n.Version)
+		if len(n.PublishedAt) >= 10 {
+			b.WriteString(" (" + n.PublishedAt[:10] + ")")
+		}
+		b.WriteString("\n\n")
+		if n.Body != "" {
+			b.WriteString(n.Body)
+		} else {
+			b.WriteString("_No release notes._")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitHubRepo(t *testing.T) {
+	for _, u := range []string{
+		"git+https://github.com/openclaw/openclaw.git",
+		"git@github.com:openclaw/openclaw.git",
+		"https://github.com/openclaw/openclaw",
+		"github:openclaw/openclaw",
+	} {
+		owner, repo, ok := parseGitHubRepo(u)
+		assert.True(t, ok, u)
+		assert.Equal(t, "openclaw", owner, u)
+		assert.Equal(t, "openclaw", repo, u)
+	}
+	_, _, ok := parseGitHubRepo("https://gitlab.com/a/b.git")
+	assert.False(t, ok)
+}
+
+func TestReleasesInRange(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.4.0", Body: "four"},
+		{TagName: "v1.3.0", Body: "three"},
+		{TagName: "v1.2.1", Draft: true},
+		{TagName: "v1.2.0", Body: "two"},
+		{TagName: "v1.1.0", Body: "one"},
+	}
+
+	notes := releasesInRange(releases, "1.1.0", "1.3.0")
+	var versions []string
+	for _, n := range notes {
+		versions = append(versions, n.Version)
+	}
+	assert.Equal(t, []string{"1.3.0", "1.2.0"}, versions)
+	assert.Equal(t, "## 1.3.0\n\nthree\n\n## 1.2.0\n\ntwo", changelogMarkdown(notes))
+
+	assert.Len(t, releasesInRange(releases, "", "1.4.0"), 1)
+	assert.Empty(t, releasesInRange(releases, "1.4.0", "1.4.0"))
+}
+
+func TestValidVersion(t *testing.T) {
+	for _, v := range []string{"1.2.3", "v1.2.3", "2026.1.5", "1.0.0-beta.1", "1.0.0+build.7"} {
+		assert.True(t, ValidVersion(v), v)
+	}
+	for _, v := range []string{"", "latest", "1.2", "1.2.3; rm", "../1.2.3", "1.2.3-" + strings.Repeat("x", 80)} {
+		assert.False(t, ValidVersion(v), v)
+	}
+}
+
+func TestChangelog_InvalidRangeNotCached(t *testing.T) {
+	c := DeckChangelog(context.Background(), "nope", "1.2.3")
+	assert.Contains(t, c.Error, "invalid version")
+	changelogCache.mu.Lock()
+	defer changelogCache.mu.Unlock()
+	for k := range changelogCache.entries {
+		assert.NotContains(t, k, "nope")
+	}
+}
+
+func TestCachedChangelog_BoundedEntries(t *testing.T) {
+	changelogCache.mu.Lock()
+	changelogCache.entries = nil
+	changelogCache.mu.Unlock()
+	defer func() {
+		changelogCache.mu.Lock()
+		changelogCache.entries = nil
+		changelogCache.mu.Unlock()
+	}()
+
+	for i := 0; i < maxChangelogEntries+10; i++ {
+		cachedChangelog(fmt.Sprintf("test|%d", i), func() *Changelog { return &Changelog{} })
+	}
+	changelogCache.mu.Lock()
+	n := len(changelogCache.entries)
+	_, newest := changelogCache.entries[fmt.Sprintf("test|%d", maxChangelogEntries+9)]
+	changelogCache.mu.Unlock()
+	assert.Equal(t, maxChangelogEntries, n)
+	assert.True(t, newest)
+}
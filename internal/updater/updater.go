@@ -202,9 +202,15 @@ func ApplyUpdate(ctx context.Context, downloadURL string, progressFn func(ApplyP
 	checksum := hex.EncodeToString(hasher.Sum(nil))
 	logger.Config.Info().Str("checksum", checksum).Int64("size", downloaded).Msg("update downloaded")
 
-	// 2. Verify: try to read checksums.txt from release if available
+	// 2. Verify against the release checksums (and signature, if a public key is baked in)
 	progressFn(ApplyProgress{Stage: "verifying", Percent: 100})
 
+	if err := verifyDownload(ctx, downloadURL, checksum); err != nil {
+		logger.Config.Error().Err(err).Str("url", downloadURL).Msg("update verification failed")
+		return fmt.Errorf("verify: %w", err)
+	}
+	logger.Config.Info().Bool("signed", PublicKey != "").Msg("update verified")
+
 	// 3. Replace binary
 	progressFn(ApplyProgress{Stage: "replacing", Percent: 100})
 
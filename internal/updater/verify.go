@@ -0,0 +1,193 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"openclawdeck/internal/version"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// ChecksumsAssetName is the sha256sum-format checksums file published with each release.
+	ChecksumsAssetName = "checksums.txt"
+	// SignatureAssetName is the minisign signature of the checksums file.
+	SignatureAssetName = ChecksumsAssetName + ".minisig"
+
+	maxChecksumsSize = 1 << 20 // 1 MB
+)
+
+// PublicKey is the minisign public key (base64 line of the .pub file) used to
+// verify release signatures. Empty disables signature verification. Override via ldflags:
+//
+//	go build -ldflags "-X openclawdeck/internal/updater.PublicKey=RWQ..."
+var PublicKey = ""
+
+// siblingURL returns the URL of another asset in the same release directory.
+func siblingURL(downloadURL, name string) (string, error) {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return "", fmt.Errorf("parse download url: %w", err)
+	}
+	u.Path = path.Join(path.Dir(u.Path), name)
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+// fetchSmall downloads a small release asset (checksums / signature).
+func fetchSmall(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "OpenClawDeck/"+version.Version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxChecksumsSize))
+}
+
+// lookupChecksum finds the sha256 for assetName in a sha256sum-format file
+// ("<hex>  <name>" or "<hex> *<name>" per line).
+func lookupChecksum(data []byte, assetName string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		name := strings.TrimPrefix(fields[1], "*")
+		if path.Base(name) == assetName {
+			sum := strings.ToLower(fields[0])
+			if len(sum) != 64 {
+				return "", fmt.Errorf("malformed checksum for %s", assetName)
+			}
+			return sum, nil
+		}
+	}
+	return "", fmt.Errorf("%s not listed in %s", assetName, ChecksumsAssetName)
+}
+
+// verifyMinisign verifies a minisign signature (both the file signature and the
+// trusted-comment global signature) of msg against the base64 public key.
+func verifyMinisign(pubKey string, msg, sigFile []byte) error {
+	pk, err := decodeMinisignKey(pubKey)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(sigFile), "\r\n", "\n"), "\n")
+	if len(lines) < 4 {
+		return errors.New("malformed signature file")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 74 {
+		return errors.New("malformed signature")
+	}
+	if !bytes.Equal(sig[2:10], pk.keyID) {
+		return errors.New("signature was made with a different key")
+	}
+
+	signed := msg
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED": // prehashed
+		h := blake2b.Sum512(msg)
+		signed = h[:]
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(pk.key, signed, sig[10:]) {
+		return errors.New("signature verification failed")
+	}
+
+	trusted, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return errors.New("malformed trusted comment")
+	}
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return errors.New("malformed global signature")
+	}
+	if !ed25519.Verify(pk.key, append(append([]byte{}, sig[10:]...), trusted...), global) {
+		return errors.New("trusted comment signature verification failed")
+	}
+	return nil
+}
+
+type minisignKey struct {
+	keyID []byte
+	key   ed25519.PublicKey
+}
+
+// decodeMinisignKey accepts either the bare base64 key or the full .pub file.
+func decodeMinisignKey(s string) (*minisignKey, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[i+1:])
+	}
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(raw) != 42 || string(raw[:2]) != "Ed" {
+		return nil, errors.New("malformed minisign public key")
+	}
+	return &minisignKey{keyID: raw[2:10], key: ed25519.PublicKey(raw[10:])}, nil
+}
+
+// verifyDownload checks the downloaded asset's sha256 against the release
+// checksums file, verifying the checksums signature first when PublicKey is set.
+func verifyDownload(ctx context.Context, downloadURL, checksum string) error {
+	assetName := path.Base(downloadURL)
+	if u, err := url.Parse(downloadURL); err == nil {
+		assetName = path.Base(u.Path)
+	}
+
+	sumsURL, err := siblingURL(downloadURL, ChecksumsAssetName)
+	if err != nil {
+		return err
+	}
+	sums, err := fetchSmall(ctx, sumsURL)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w (release does not publish checksums; refusing to update)", ChecksumsAssetName, err)
+	}
+
+	if PublicKey != "" {
+		sigURL, err := siblingURL(downloadURL, SignatureAssetName)
+		if err != nil {
+			return err
+		}
+		sig, err := fetchSmall(ctx, sigURL)
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", SignatureAssetName, err)
+		}
+		if err := verifyMinisign(PublicKey, sums, sig); err != nil {
+			return fmt.Errorf("%s: %w", SignatureAssetName, err)
+		}
+	}
+
+	expected, err := lookupChecksum(sums, assetName)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(expected, checksum) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", assetName, expected, checksum)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package updater
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupChecksum(t *testing.T) {
+	sums := []byte("" +
+		"1111111111111111111111111111111111111111111111111111111111111111  openclawdeck-linux-amd64\n" +
+		"2222222222222222222222222222222222222222222222222222222222222222 *openclawdeck-windows-amd64.exe\n")
+
+	sum, err := lookupChecksum(sums, "openclawdeck-linux-amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "1111111111111111111111111111111111111111111111111111111111111111", sum)
+
+	sum, err = lookupChecksum(sums, "openclawdeck-windows-amd64.exe")
+	require.NoError(t, err)
+	assert.Equal(t, "2222222222222222222222222222222222222222222222222222222222222222", sum)
+
+	_, err = lookupChecksum(sums, "openclawdeck-darwin-arm64")
+	assert.Error(t, err)
+}
+
+func TestSiblingURL(t *testing.T) {
+	u, err := siblingURL("https://github.com/o/r/releases/download/v1.2.3/openclawdeck-linux-amd64", ChecksumsAssetName)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/o/r/releases/download/v1.2.3/checksums.txt", u)
+}
+
+// minisignFixture builds a key and a minisign-format signature for msg.
+func minisignFixture(t *testing.T, msg []byte) (pub string, sigFile []byte) {
+	pk, sk, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	pub = base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pk...))
+
+	sig := ed25519.Sign(sk, msg)
+	trusted := "timestamp:1700000000\tfile:checksums.txt"
+	global := ed25519.Sign(sk, append(append([]byte{}, sig...), trusted...))
+
+	sigFile = []byte("untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), sig...)) + "\n" +
+		"trusted comment: " + trusted + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n")
+	return pub, sigFile
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	msg := []byte("abc  openclawdeck-linux-amd64\n")
+	pub, sig := minisignFixture(t, msg)
+
+	assert.NoError(t, verifyMinisign(pub, msg, sig))
+	assert.Error(t, verifyMinisign(pub, []byte("tampered"), sig))
+
+	otherPub, _ := minisignFixture(t, msg)
+	assert.Error(t, verifyMinisign(otherPub, msg, sig))
+}
@@ -0,0 +1,82 @@
+package web
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressRequest(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sessions", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompressMiddleware_LargeJSON(t *testing.T) {
+	payload := `{"list":[` + strings.Repeat(`{"id":"session","state":"idle"},`, 100) + `{}]}`
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		OK(w, r, map[string]string{"payload": payload})
+	}))
+
+	w := compressRequest(h, "gzip, deflate, br")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Less(t, w.Body.Len(), len(payload))
+
+	zr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"success":true`)
+
+	// clients that do not accept gzip get the plain body
+	for _, ae := range []string{"", "identity", "gzip;q=0"} {
+		w = compressRequest(h, ae)
+		assert.Empty(t, w.Header().Get("Content-Encoding"), ae)
+		assert.Contains(t, w.Body.String(), `"success":true`, ae)
+	}
+}
+
+func TestCompressMiddleware_PassThrough(t *testing.T) {
+	small := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	w := compressRequest(small, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"ok":true}`, w.Body.String())
+
+	png := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(make([]byte, 4096))
+	}))
+	w = compressRequest(png, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, 4096, w.Body.Len())
+}
+
+func TestCompressMiddleware_SSENotBuffered(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: hello\n\n"))
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+		flusher.Flush()
+		// the event reached the client before the handler returned
+		assert.Equal(t, "data: hello\n\n", w.(*compressWriter).ResponseWriter.(*httptest.ResponseRecorder).Body.String())
+	}))
+	w := compressRequest(h, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, w.Flushed)
+}
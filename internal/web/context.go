@@ -14,6 +14,7 @@ const (
 	userIDKey    contextKey = "user_id"
 	usernameKey  contextKey = "username"
 	roleKey      contextKey = "role"
+	sessionKey   contextKey = "session_id"
 )
 
 func SetRequestID(r *http.Request, id string) *http.Request {
@@ -56,6 +57,19 @@ func GetRole(r *http.Request) string {
 	return ""
 }
 
+// SetSessionID stores the token ID (jti) of the authenticated session.
+func SetSessionID(r *http.Request, jti string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), sessionKey, jti))
+}
+
+// GetSessionID returns the token ID (jti) of the current session.
+func GetSessionID(r *http.Request) string {
+	if v, ok := r.Context().Value(sessionKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
 func GenerateRequestID() string {
 	b := make([]byte, 8)
 	rand.Read(b)
@@ -49,6 +49,8 @@ var (
 	ErrAccountLocked    = &AppError{"AUTH_ACCOUNT_LOCKED", "account locked, try again later", 423, nil}
 	ErrTokenExpired     = &AppError{"AUTH_TOKEN_EXPIRED", "session expired, please login again", 401, nil}
 	ErrTokenInvalid     = &AppError{"AUTH_TOKEN_INVALID", "invalid token", 400, nil}
+	ErrTokenRevoked     = &AppError{"AUTH_TOKEN_REVOKED", "session revoked, please login again", 401, nil}
+	ErrSessionNotFound  = &AppError{"AUTH_SESSION_NOT_FOUND", "session not found", 404, nil}
 	ErrEmptyCredentials = &AppError{"AUTH_EMPTY_CREDENTIALS", "username and password required", 400, nil}
 	ErrPasswordTooShort = &AppError{"AUTH_PASSWORD_TOO_SHORT", "password must be at least 6 characters", 400, nil}
 	ErrSetupDone        = &AppError{"AUTH_SETUP_DONE", "admin account already exists", 409, nil}
@@ -70,6 +72,17 @@ var (
 	ErrDBQuery       = &AppError{"DB_QUERY_FAILED", "database query failed", 500, nil}
 	ErrEncrypt       = &AppError{"ENCRYPT_FAILED", "encryption failed", 500, nil}
 	ErrPathError     = &AppError{"PATH_ERROR", "cannot determine user directory", 500, nil}
+	ErrNotReady      = &AppError{"SERVICE_NOT_READY", "service is starting, try again shortly", 503, nil}
+)
+
+// ---------------------------------------------------------------------------
+// Database maintenance
+// ---------------------------------------------------------------------------
+
+var (
+	ErrDBVacuumFailed      = &AppError{"DB_VACUUM_FAILED", "database vacuum failed", 500, nil}
+	ErrDBBusy              = &AppError{"DB_BUSY", "database is busy, try again later", 409, nil}
+	ErrDBVacuumUnsupported = &AppError{"DB_VACUUM_UNSUPPORTED", "vacuum is only supported for SQLite", 400, nil}
 )
 
 // ---------------------------------------------------------------------------
@@ -133,6 +146,7 @@ var (
 	ErrConfigWriteFailed = &AppError{"CONFIG_WRITE_FAILED", "config write failed", 500, nil}
 	ErrConfigGenFailed   = &AppError{"CONFIG_GEN_FAILED", "config generation failed", 500, nil}
 	ErrConfigEmpty       = &AppError{"CONFIG_EMPTY", "no valid config entries", 400, nil}
+	ErrConfigInvalid     = &AppError{"CONFIG_INVALID", "config failed validation", 400, nil}
 )
 
 // ---------------------------------------------------------------------------
@@ -164,8 +178,10 @@ var (
 // ---------------------------------------------------------------------------
 
 var (
-	ErrSettingsQueryFail  = &AppError{"SETTINGS_QUERY_FAILED", "settings query failed", 500, nil}
-	ErrSettingsUpdateFail = &AppError{"SETTINGS_UPDATE_FAILED", "settings update failed", 500, nil}
+	ErrSettingsQueryFail       = &AppError{"SETTINGS_QUERY_FAILED", "settings query failed", 500, nil}
+	ErrSettingsUpdateFail      = &AppError{"SETTINGS_UPDATE_FAILED", "settings update failed", 500, nil}
+	ErrSettingsInvalid         = &AppError{"SETTINGS_INVALID", "invalid setting value", 400, nil}
+	ErrPairingSignatureInvalid = &AppError{"PAIRING_SIGNATURE_INVALID", "missing or invalid pairing request signature", 401, nil}
 )
 
 // ---------------------------------------------------------------------------
@@ -179,6 +195,7 @@ var (
 	ErrSkillUpdateFail    = &AppError{"SKILL_UPDATE_FAILED", "skill update failed", 500, nil}
 	ErrSkillsReadFail     = &AppError{"SKILLS_READ_ERROR", "skills directory read failed", 500, nil}
 	ErrSkillsPathError    = &AppError{"SKILLS_PATH_ERROR", "cannot determine user directory", 500, nil}
+	ErrSkillIntegrityFail = &AppError{"SKILL_INTEGRITY_FAILED", "skill integrity check failed", 500, nil}
 )
 
 // ---------------------------------------------------------------------------
@@ -192,6 +209,17 @@ var (
 	ErrScanError            = &AppError{"SCAN_ERROR", "scan failed", 500, nil}
 )
 
+// ---------------------------------------------------------------------------
+// Setup wizard
+// ---------------------------------------------------------------------------
+
+var (
+	ErrSetupConfigureFailed = &AppError{"CONFIG_ERROR", "openclaw configuration failed", 500, nil}
+	ErrSetupStartFailed     = &AppError{"START_ERROR", "gateway start failed", 500, nil}
+	ErrSetupNoProgress      = &AppError{"SETUP_NO_PROGRESS", "no interrupted install to resume", 400, nil}
+	ErrSetupResumeNeedsKey  = &AppError{"SETUP_RESUME_NEEDS_KEY", "apiKey is required to resume configuration", 400, nil}
+)
+
 // ---------------------------------------------------------------------------
 // Monitor
 // ---------------------------------------------------------------------------
@@ -222,6 +250,14 @@ var (
 	ErrClawHubFailed = &AppError{"CLAWHUB_FAILED", "ClawHub request failed", 502, nil}
 )
 
+// ---------------------------------------------------------------------------
+// Self-update
+// ---------------------------------------------------------------------------
+
+var (
+	ErrUpdateCheckFail = &AppError{"UPDATE_CHECK_FAILED", "update check failed", 500, nil}
+)
+
 // ---------------------------------------------------------------------------
 // Templates
 // ---------------------------------------------------------------------------
@@ -233,4 +269,7 @@ var (
 	ErrTemplateUpdateFail = &AppError{"TEMPLATE_UPDATE_FAILED", "template update failed", 500, nil}
 	ErrTemplateDeleteFail = &AppError{"TEMPLATE_DELETE_FAILED", "template deletion failed", 500, nil}
 	ErrTemplateBuiltinRO  = &AppError{"TEMPLATE_BUILTIN_READONLY", "built-in templates are read-only", 403, nil}
+	ErrTemplateVarMissing = &AppError{"TEMPLATE_VAR_MISSING", "required template variables missing", 400, nil}
+	ErrTemplateInvalid    = &AppError{"TEMPLATE_INVALID", "rendered template is not a valid config object", 400, nil}
+	ErrTemplateApplyFail  = &AppError{"TEMPLATE_APPLY_FAILED", "template apply failed", 500, nil}
 )
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,24 +12,40 @@ type JWTClaims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// Epoch is the user's token epoch at issue time; bumping it (password change)
+	// invalidates every token issued before.
+	Epoch int `json:"epoch,omitempty"`
 	jwt.RegisteredClaims
 }
 
 func GenerateJWT(userID uint, username, role, secret string, expire time.Duration) (string, time.Time, error) {
-	expiresAt := time.Now().UTC().Add(expire)
+	token, _, expiresAt, err := GenerateSessionJWT(userID, username, role, 0, secret, expire)
+	return token, expiresAt, err
+}
+
+// GenerateSessionJWT issues a token with a random ID (jti) for the server-side
+// session registry and the user's current token epoch.
+func GenerateSessionJWT(userID uint, username, role string, epoch int, secret string, expire time.Duration) (token, jti string, expiresAt time.Time, err error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", time.Time{}, err
+	}
+	jti = hex.EncodeToString(b)
+	expiresAt = time.Now().UTC().Add(expire)
 	claims := JWTClaims{
 		UserID:   userID,
 		Username: username,
 		Role:     role,
+		Epoch:    epoch,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			Issuer:    "openclawdeck",
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(secret))
-	return tokenStr, expiresAt, err
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	return token, jti, expiresAt, err
 }
 
 func ValidateJWT(tokenStr, secret string) (*JWTClaims, error) {
@@ -42,3 +60,22 @@ func ValidateJWT(tokenStr, secret string) (*JWTClaims, error) {
 	}
 	return nil, jwt.ErrSignatureInvalid
 }
+
+// SessionCheckFunc verifies a validated token against the server-side session
+// registry; a non-nil error rejects the request (revoked or unknown session).
+type SessionCheckFunc func(claims *JWTClaims) error
+
+// sessionCheckFn holds the registry check set by SetSessionCheckFunc.
+var sessionCheckFn SessionCheckFunc
+
+// SetSessionCheckFunc registers the session registry check used by AuthMiddleware
+// and the WebSocket handshake.
+func SetSessionCheckFunc(fn SessionCheckFunc) { sessionCheckFn = fn }
+
+// checkSession runs the registered session check, if any.
+func checkSession(claims *JWTClaims) error {
+	if sessionCheckFn == nil {
+		return nil
+	}
+	return sessionCheckFn(claims)
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,9 @@ import (
 	"openclawdeck/internal/logger"
 )
 
+// statusWriter records the response status for the request log. Like every
+// ResponseWriter wrapper in the chain it must keep http.Flusher (SSE) and
+// http.Hijacker (WebSocket) reachable; see TestMiddlewareChain_PreservesFlusherAndHijacker.
 type statusWriter struct {
 	http.ResponseWriter
 	status int
@@ -32,11 +36,21 @@ func (w *statusWriter) Flush() {
 
 func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	if hj, ok := w.ResponseWriter.(http.Hijacker); ok {
-		return hj.Hijack()
+		conn, rw, err := hj.Hijack()
+		if err == nil {
+			// the upgraded connection never calls WriteHeader
+			w.status = http.StatusSwitchingProtocols
+		}
+		return conn, rw, err
 	}
 	return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func RecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -100,22 +114,151 @@ func RequestLogMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func CORSMiddleware(origins []string) func(http.Handler) http.Handler {
-	allowed := make(map[string]bool)
+// CORSPolicy describes which cross-origin callers may reach a set of routes.
+// Each origin is an exact scheme://host[:port], or scheme://*.domain[:port] to
+// admit any subdomain of domain. A bare "*" is not accepted.
+type CORSPolicy struct {
+	PathPrefix  string   // empty = all routes
+	Origins     []string // allowed origins (see ParseOrigin)
+	Credentials bool     // send Access-Control-Allow-Credentials to allowed origins
+}
+
+// Validate rejects origins ParseOrigin does not accept.
+func (p CORSPolicy) Validate() error {
+	for _, o := range p.Origins {
+		if _, err := ParseOrigin(o); err != nil {
+			return fmt.Errorf("cors policy %q: %w", p.PathPrefix, err)
+		}
+	}
+	return nil
+}
+
+// OriginPattern is a parsed allowed origin.
+type OriginPattern struct {
+	scheme string
+	host   string // exact host, or the domain a "*." pattern is anchored to
+	port   string
+	suffix bool // "*.host": any subdomain of host, not host itself
+}
+
+// ParseOrigin parses an allowed origin: scheme://host[:port] or
+// scheme://*.domain[:port]. Paths, queries and wildcards elsewhere are rejected.
+func ParseOrigin(origin string) (OriginPattern, error) {
+	o := strings.TrimRight(strings.TrimSpace(origin), "/")
+	scheme, rest, ok := strings.Cut(o, "://")
+	if !ok || scheme == "" || rest == "" {
+		return OriginPattern{}, fmt.Errorf("origin %q: expected scheme://host[:port]", origin)
+	}
+	if strings.ContainsAny(rest, "/?#@") {
+		return OriginPattern{}, fmt.Errorf("origin %q: must not contain a path, query or credentials", origin)
+	}
+	p := OriginPattern{scheme: strings.ToLower(scheme)}
+	if p.scheme != "http" && p.scheme != "https" {
+		return OriginPattern{}, fmt.Errorf("origin %q: scheme must be http or https", origin)
+	}
+	host := rest
+	if h, port, err := net.SplitHostPort(rest); err == nil {
+		host, p.port = h, port
+	}
+	if strings.HasPrefix(host, "*.") {
+		p.suffix = true
+		host = host[2:]
+	}
+	if host == "" || strings.Contains(host, "*") {
+		return OriginPattern{}, fmt.Errorf("origin %q: wildcards are only allowed as a leading \"*.\" label", origin)
+	}
+	p.host = strings.ToLower(host)
+	return p, nil
+}
+
+// Match reports whether a request Origin header is admitted by the pattern.
+// Scheme and port must be equal; a "*." pattern matches at a label boundary only.
+func (p OriginPattern) Match(origin string) bool {
+	scheme, rest, ok := strings.Cut(origin, "://")
+	if !ok || !strings.EqualFold(scheme, p.scheme) {
+		return false
+	}
+	host, port := rest, ""
+	if h, pt, err := net.SplitHostPort(rest); err == nil {
+		host, port = h, pt
+	}
+	if port != p.port {
+		return false
+	}
+	host = strings.ToLower(host)
+	if p.suffix {
+		return strings.HasSuffix(host, "."+p.host)
+	}
+	return host == p.host
+}
+
+// matchOrigin reports whether origin is admitted by any of the patterns.
+func matchOrigin(patterns []OriginPattern, origin string) bool {
+	for _, p := range patterns {
+		if p.Match(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOrigins parses the valid entries of origins; invalid ones are dropped
+// (settings validate them on save).
+func parseOrigins(origins []string) []OriginPattern {
+	var out []OriginPattern
 	for _, o := range origins {
-		allowed[o] = true
+		if p, err := ParseOrigin(o); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+type corsRule struct {
+	prefix      string
+	origins     []OriginPattern
+	credentials bool
+}
+
+// CORSMiddleware applies one origin list to every route; listed origins are
+// allowed with credentials.
+func CORSMiddleware(origins []string) func(http.Handler) http.Handler {
+	return CORSPolicyMiddleware([]CORSPolicy{{Origins: origins, Credentials: true}})
+}
+
+// CORSPolicyMiddleware validates the Origin header against per-route policies
+// (longest matching PathPrefix wins) and echoes allowed origins back — never "*".
+func CORSPolicyMiddleware(policies []CORSPolicy) func(http.Handler) http.Handler {
+	rules := make([]*corsRule, 0, len(policies))
+	for _, p := range policies {
+		rules = append(rules, &corsRule{prefix: p.PathPrefix, origins: parseOrigins(p.Origins), credentials: p.Credentials})
 	}
+	// longest prefix first so specific routes override the global policy
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].prefix) > len(rules[j].prefix) })
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
-			// Only allow explicitly configured origins; empty list = same-origin only
-			if origin != "" && len(allowed) > 0 && allowed[origin] {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-				w.Header().Set("Access-Control-Allow-Credentials", "true")
-				w.Header().Set("Access-Control-Max-Age", "86400")
-				w.Header().Set("Vary", "Origin")
+			if origin != "" {
+				w.Header().Add("Vary", "Origin")
+			}
+			// Only allow configured origins; no policies = same-origin only
+			if origin != "" {
+				for _, rule := range rules {
+					if !strings.HasPrefix(r.URL.Path, rule.prefix) {
+						continue
+					}
+					if matchOrigin(rule.origins, origin) {
+						w.Header().Set("Access-Control-Allow-Origin", origin)
+						w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+						w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+						if rule.credentials {
+							w.Header().Set("Access-Control-Allow-Credentials", "true")
+						}
+						w.Header().Set("Access-Control-Max-Age", "86400")
+					}
+					break
+				}
 			}
 			if r.Method == http.MethodOptions {
 				w.WriteHeader(http.StatusNoContent)
@@ -260,7 +403,7 @@ func AuthMiddleware(jwtSecret string, skipPaths []string) func(http.Handler) htt
 
 			if tokenStr == "" {
 				if authAuditFn != nil {
-					authAuditFn("auth.failed", "failed", "no token: "+path, r.RemoteAddr, "", 0)
+					authAuditFn("auth.failed", "failed", "no token: "+path, ClientIP(r), "", 0)
 				}
 				Fail(w, r, ErrUnauthorized.Code, ErrUnauthorized.Message, ErrUnauthorized.HTTPStatus)
 				return
@@ -269,13 +412,21 @@ func AuthMiddleware(jwtSecret string, skipPaths []string) func(http.Handler) htt
 			claims, err := ValidateJWT(tokenStr, jwtSecret)
 			if err != nil {
 				if authAuditFn != nil {
-					authAuditFn("auth.failed", "failed", "invalid/expired token: "+path, r.RemoteAddr, "", 0)
+					authAuditFn("auth.failed", "failed", "invalid/expired token: "+path, ClientIP(r), "", 0)
 				}
 				Fail(w, r, ErrTokenExpired.Code, ErrTokenExpired.Message, ErrTokenExpired.HTTPStatus)
 				return
 			}
+			if err := checkSession(claims); err != nil {
+				if authAuditFn != nil {
+					authAuditFn("auth.failed", "failed", "revoked session ("+err.Error()+"): "+path, ClientIP(r), claims.Username, claims.UserID)
+				}
+				FailErr(w, r, ErrTokenRevoked)
+				return
+			}
 
 			r = SetUserInfo(r, claims.UserID, claims.Username, claims.Role)
+			r = SetSessionID(r, claims.ID)
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -285,7 +436,7 @@ func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if GetRole(r) != "admin" {
 			if authAuditFn != nil {
-				authAuditFn("forbidden", "denied", "admin required: "+r.URL.Path, r.RemoteAddr, GetUsername(r), GetUserID(r))
+				authAuditFn("forbidden", "denied", "admin required: "+r.URL.Path, ClientIP(r), GetUsername(r), GetUserID(r))
 			}
 			Fail(w, r, ErrForbidden.Code, ErrForbidden.Message, ErrForbidden.HTTPStatus)
 			return
@@ -295,10 +446,12 @@ func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
 }
 
 // MaxBodySizeMiddleware limits request body size to prevent OOM from oversized payloads.
+// Only the request body is wrapped; the ResponseWriter (and its http.Flusher)
+// is passed through unchanged. Stream routes (see StreamMiddleware) are not limited.
 func MaxBodySizeMiddleware(maxBytes int64) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.Body != nil && r.ContentLength != 0 {
+			if r.Body != nil && r.ContentLength != 0 && !IsStream(r) {
 				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
 			}
 			next.ServeHTTP(w, r)
@@ -0,0 +1,169 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsRequest(h http.Handler, method, path, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_AllowedAndDenied(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := CORSMiddleware([]string{"https://app.example.com"})(ok)
+
+	w := corsRequest(h, http.MethodGet, "/api/v1/health", "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	w = corsRequest(h, http.MethodGet, "/api/v1/health", "https://evil.example.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+	w = corsRequest(h, http.MethodOptions, "/api/v1/health", "https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestCORSMiddleware_OriginPatterns(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := CORSMiddleware([]string{"*", "https://app.example.com", "https://*.corp.example", "http://localhost:3000"})(ok)
+
+	for origin, allowed := range map[string]bool{
+		"https://app.example.com":          true,
+		"https://APP.example.com":          true,
+		"https://app.example.com.evil.net": false,
+		"https://app.example.comx":         false,
+		"http://app.example.com":           false,
+		"https://app.example.com:8443":     false,
+		"https://a.corp.example":           true,
+		"https://a.b.corp.example":         true,
+		"https://corp.example":             false,
+		"https://evilcorp.example":         false,
+		"https://a.corp.example.evil.net":  false,
+		"http://localhost:3000":            true,
+		"http://localhost:3001":            false,
+		"https://other.example.com":        false,
+	} {
+		w := corsRequest(h, http.MethodGet, "/", origin)
+		if allowed {
+			assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		} else {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+	}
+}
+
+func TestCORSPolicyMiddleware_PerRoute(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := CORSPolicyMiddleware([]CORSPolicy{
+		{Origins: []string{"https://admin.example.com"}, Credentials: true},
+		{PathPrefix: "/api/v1/monitor/", Origins: []string{"https://embed.example.com"}, Credentials: true},
+	})(ok)
+
+	// route policy admits the embedding app
+	w := corsRequest(h, http.MethodGet, "/api/v1/monitor/stats", "https://embed.example.com")
+	assert.Equal(t, "https://embed.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	// but not on other routes
+	w = corsRequest(h, http.MethodGet, "/api/v1/settings", "https://embed.example.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// route policy overrides the global one
+	w = corsRequest(h, http.MethodGet, "/api/v1/monitor/stats", "https://admin.example.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSPolicy_Validate(t *testing.T) {
+	for _, o := range []string{"*", "https://*", "*.example.com", "https://a.*.example.com", "https://app.example.com/path", "ftp://example.com", "example.com"} {
+		assert.Error(t, CORSPolicy{Origins: []string{o}}.Validate(), o)
+	}
+	for _, o := range []string{"https://app.example.com", "https://app.example.com/", "http://localhost:3000", "https://*.example.com", "http://[::1]:3000"} {
+		assert.NoError(t, CORSPolicy{Origins: []string{o}, Credentials: true}.Validate(), o)
+	}
+}
+
+// hijackRecorder is a ResponseRecorder that can also be hijacked, like the
+// writer net/http hands to handlers.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	c1, c2 := net.Pipe()
+	c2.Close()
+	return c1, bufio.NewReadWriter(bufio.NewReader(c1), bufio.NewWriter(c1)), nil
+}
+
+// fullChain mirrors the middleware order used by the serve command.
+func fullChain(h http.Handler, ctx context.Context) http.Handler {
+	return Chain(h,
+		RecoveryMiddleware,
+		SecurityHeadersMiddleware,
+		RequestIDMiddleware,
+		RequestLogMiddleware,
+		ReadyMiddleware([]string{"/api/v1/health"}),
+		StreamMiddleware(func(r *http.Request) bool { return r.URL.Path == "/api/v1/stream" }),
+		CompressMiddleware,
+		CORSPolicyMiddleware([]CORSPolicy{{Origins: []string{"https://app.example.com"}, Credentials: true}}),
+		MaxBodySizeMiddleware(2<<20),
+		RateLimitMiddleware(NewRateLimiter(10, time.Minute, ctx), []string{"/api/v1/auth/login"}),
+		InputSanitizeMiddleware,
+		AuthMiddleware("secret", []string{"/api/v1/stream", "/api/v1/plain", "/api/v1/ws"}),
+	)
+}
+
+func TestMiddlewareChain_PreservesFlusherAndHijacker(t *testing.T) {
+	SetReady(true)
+	t.Cleanup(func() { SetReady(false) })
+	for _, tc := range []struct {
+		path    string
+		headers map[string]string
+	}{
+		{"/api/v1/stream", map[string]string{"Accept": "text/event-stream", "Accept-Encoding": "gzip"}},
+		// compressWriter is in the chain for ordinary routes
+		{"/api/v1/plain", map[string]string{"Accept-Encoding": "gzip"}},
+		{"/api/v1/ws", map[string]string{"Upgrade": "websocket", "Connection": "Upgrade"}},
+	} {
+		var flusher, hijacker bool
+		h := fullChain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, flusher = w.(http.Flusher)
+			var hj http.Hijacker
+			hj, hijacker = w.(http.Hijacker)
+			if tc.path == "/api/v1/ws" && hijacker {
+				conn, _, err := hj.Hijack()
+				require.NoError(t, err)
+				conn.Close()
+			}
+		}), t.Context())
+
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+		h.ServeHTTP(rec, req)
+
+		assert.True(t, flusher, "%s: http.Flusher lost in the middleware chain", tc.path)
+		assert.True(t, hijacker, "%s: http.Hijacker lost in the middleware chain", tc.path)
+		assert.Equal(t, tc.path == "/api/v1/ws", rec.hijacked, tc.path)
+	}
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ready is the readiness flag: false until startup (database, migrations,
+// services) has finished, and again once shutdown begins. Liveness is just
+// the process answering /api/v1/health.
+var ready atomic.Bool
+
+// SetReady marks the server ready (or not) to serve requests.
+func SetReady(v bool) { ready.Store(v) }
+
+// Ready reports whether the server is ready to serve requests.
+func Ready() bool { return ready.Load() }
+
+// ReadyMiddleware answers API requests with 503 SERVICE_NOT_READY (and
+// Retry-After) while the server is not ready. exemptPaths (health checks) and
+// non-API routes (static assets) are always served; neither touches the database.
+func ReadyMiddleware(exemptPaths []string) func(http.Handler) http.Handler {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, p := range exemptPaths {
+		exempt[p] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !Ready() && !exempt[r.URL.Path] && strings.HasPrefix(r.URL.Path, "/api/") {
+				w.Header().Set("Retry-After", "5")
+				FailErr(w, r, ErrNotReady)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReadyGate is the root handler of the server. The listener starts before the
+// database is opened and migrated; until Open is called the gate serves the
+// boot handler (health checks, static assets, 503 for the rest of the API).
+// Open switches to the full handler and marks the server ready.
+type ReadyGate struct {
+	h atomic.Pointer[http.Handler]
+}
+
+// NewReadyGate returns a gate serving boot until Open is called.
+func NewReadyGate(boot http.Handler) *ReadyGate {
+	g := &ReadyGate{}
+	g.h.Store(&boot)
+	return g
+}
+
+// Open serves h from now on and marks the server ready.
+func (g *ReadyGate) Open(h http.Handler) {
+	g.h.Store(&h)
+	SetReady(true)
+}
+
+func (g *ReadyGate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*g.h.Load()).ServeHTTP(w, r)
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadyMiddleware(t *testing.T) {
+	t.Cleanup(func() { SetReady(false) })
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := ReadyMiddleware([]string{"/api/v1/health"})(ok)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	SetReady(false)
+	w := get("/api/v1/sessions")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"error_code":"SERVICE_NOT_READY"`)
+	// liveness and static assets stay available
+	assert.Equal(t, http.StatusOK, get("/api/v1/health").Code)
+	assert.Equal(t, http.StatusOK, get("/assets/index.js").Code)
+
+	SetReady(true)
+	assert.Equal(t, http.StatusOK, get("/api/v1/sessions").Code)
+}
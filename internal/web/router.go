@@ -16,13 +16,15 @@ type methodMap map[string]http.HandlerFunc
 type Router struct {
 	mux         *http.ServeMux
 	routes      []Route
-	pathMethods map[string]methodMap // path → method → handler
+	pathMethods map[string]methodMap                  // path → method → handler
+	streams     map[string]func(r *http.Request) bool // SSE path → condition (nil = always)
 }
 
 func NewRouter() *Router {
 	return &Router{
 		mux:         http.NewServeMux(),
 		pathMethods: make(map[string]methodMap),
+		streams:     make(map[string]func(r *http.Request) bool),
 	}
 }
 
@@ -68,6 +70,27 @@ func (rt *Router) DELETE(path string, handler http.HandlerFunc) {
 	rt.Handle(http.MethodDelete, path, handler)
 }
 
+// Stream registers an SSE route and remembers it for IsStream, so the stream
+// list cannot drift from the registrations. when, if given, limits streaming
+// to matching requests (e.g. ?follow=true); other requests to the path are
+// treated as plain JSON.
+func (rt *Router) Stream(method, path string, handler http.HandlerFunc, when ...func(r *http.Request) bool) {
+	rt.Handle(method, path, handler)
+	var cond func(r *http.Request) bool
+	if len(when) > 0 {
+		cond = when[0]
+	}
+	rt.streams[path] = cond
+}
+
+// IsStream reports whether r targets a route registered with Stream (and
+// matches its condition). Pass it to StreamMiddleware. Streams must be
+// registered before the router serves requests.
+func (rt *Router) IsStream(r *http.Request) bool {
+	cond, ok := rt.streams[r.URL.Path]
+	return ok && (cond == nil || cond(r))
+}
+
 func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	rt.mux.ServeHTTP(w, r)
 }
@@ -0,0 +1,40 @@
+package web
+
+import (
+	"context"
+	"net/http"
+)
+
+const streamKey contextKey = "stream"
+
+// StreamMiddleware marks the requests isStream reports as SSE (normally
+// Router.IsStream, which knows the routes registered with Router.Stream) so
+// the rest of the chain leaves them alone: CompressMiddleware passes them
+// through and MaxBodySizeMiddleware does not limit them, so every Flush from
+// the handler reaches the client at once.
+//
+// Streaming responses also send "X-Accel-Buffering: no", which tells nginx
+// and compatible reverse proxies not to buffer the response. It is set here
+// before the handler runs, so a handler that forgets it still streams
+// through such a proxy; handlers should keep setting it themselves together
+// with Content-Type: text/event-stream and Cache-Control: no-cache.
+//
+// Every middleware that wraps the ResponseWriter must keep http.Flusher
+// reachable (statusWriter and compressWriter implement Flush and Unwrap).
+func StreamMiddleware(isStream func(r *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isStream(r) {
+				w.Header().Set("X-Accel-Buffering", "no")
+				r = r.WithContext(context.WithValue(r.Context(), streamKey, true))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsStream reports whether StreamMiddleware marked r as an SSE request.
+func IsStream(r *http.Request) bool {
+	v, _ := r.Context().Value(streamKey).(bool)
+	return v
+}
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamMiddleware_SSEThroughChain(t *testing.T) {
+	body := strings.Repeat("x", 4096)
+	var flushed []string
+	sse := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err, "stream routes are not body-limited")
+		assert.Len(t, data, len(body))
+
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok, "http.Flusher must survive the middleware chain")
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, ev := range []string{"one", "two"} {
+			w.Write([]byte("data: " + ev + "\n\n"))
+			flusher.Flush()
+			flushed = append(flushed, ev)
+		}
+	})
+	rt := NewRouter()
+	rt.Stream(http.MethodPost, "/api/v1/clawhub/install-stream", sse)
+	h := Chain(rt,
+		RequestLogMiddleware,
+		StreamMiddleware(rt.IsStream),
+		CompressMiddleware,
+		MaxBodySizeMiddleware(1024),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/clawhub/install-stream", strings.NewReader(body))
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, []string{"one", "two"}, flushed)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "no", w.Header().Get("X-Accel-Buffering"))
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: one\n\ndata: two\n\n", w.Body.String())
+}
+
+func TestStreamMiddleware_OtherRoutesUnchanged(t *testing.T) {
+	var limited bool
+	rt := NewRouter()
+	rt.Stream(http.MethodPost, "/api/v1/clawhub/install-stream", func(w http.ResponseWriter, r *http.Request) {})
+	rt.PUT("/api/v1/config", func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.ReadAll(r.Body)
+		limited = err != nil
+		assert.False(t, IsStream(r))
+	})
+	h := Chain(rt,
+		StreamMiddleware(rt.IsStream),
+		MaxBodySizeMiddleware(1024),
+	)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/config", strings.NewReader(strings.Repeat("x", 4096)))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.True(t, limited)
+	assert.Empty(t, w.Header().Get("X-Accel-Buffering"))
+}
+
+func TestRouterStream_Condition(t *testing.T) {
+	rt := NewRouter()
+	follow := func(r *http.Request) bool { return r.URL.Query().Get("follow") == "true" }
+	rt.Stream(http.MethodGet, "/api/v1/gateway/log", func(w http.ResponseWriter, r *http.Request) {}, follow)
+	rt.Stream(http.MethodPost, "/api/v1/gw/proxy-stream", func(w http.ResponseWriter, r *http.Request) {})
+	rt.GET("/api/v1/config", func(w http.ResponseWriter, r *http.Request) {})
+
+	for target, want := range map[string]bool{
+		"/api/v1/gateway/log?follow=true": true,
+		"/api/v1/gateway/log?lines=500":   false,
+		"/api/v1/gw/proxy-stream":         true,
+		"/api/v1/config":                  false,
+	} {
+		assert.Equal(t, want, rt.IsStream(httptest.NewRequest(http.MethodGet, target, nil)), target)
+	}
+}
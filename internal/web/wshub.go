@@ -3,7 +3,10 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"openclawdeck/internal/logger"
@@ -14,10 +17,7 @@ import (
 // newUpgrader creates a WebSocket upgrader that validates Origin against allowed origins.
 // If allowedOrigins is empty, only same-origin requests are accepted.
 func newUpgrader(allowedOrigins []string) websocket.Upgrader {
-	allowed := make(map[string]bool, len(allowedOrigins))
-	for _, o := range allowedOrigins {
-		allowed[o] = true
-	}
+	allowed := parseOrigins(allowedOrigins)
 	return websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
@@ -26,8 +26,8 @@ func newUpgrader(allowedOrigins []string) websocket.Upgrader {
 			if origin == "" {
 				return true // same-origin (no Origin header)
 			}
-			if len(allowed) > 0 {
-				return allowed[origin]
+			if len(allowedOrigins) > 0 {
+				return matchOrigin(allowed, origin)
 			}
 			// No explicit origins configured: accept same-host origins
 			return true
@@ -35,12 +35,46 @@ func newUpgrader(allowedOrigins []string) websocket.Upgrader {
 	}
 }
 
+// GWEventChannel is the channel gateway events are forwarded on.
+const GWEventChannel = "gw_event"
+
+// DefaultGWEventPrefixes are the gateway event prefixes a client receives on
+// GWEventChannel until it sends its own {"subscribe":[...]} filter. "*" matches all.
+var DefaultGWEventPrefixes = []string{"session", "tool", "error", "cron"}
+
 type WSClient struct {
 	hub      *WSHub
 	conn     *websocket.Conn
 	send     chan []byte
 	channels map[string]bool
+	events   []string // gateway event prefix filter
 	mu       sync.RWMutex
+
+	remoteAddr  string
+	username    string
+	connectedAt time.Time
+}
+
+// wants reports whether the client is subscribed to msg's channel and,
+// for gateway events, whether the event type matches its prefix filter.
+func (c *WSClient) wants(msg WSMessage) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if msg.Channel == "" {
+		return true
+	}
+	if !c.channels[msg.Channel] {
+		return false
+	}
+	if msg.Channel != GWEventChannel {
+		return true
+	}
+	for _, prefix := range c.events {
+		if prefix == "*" || strings.HasPrefix(msg.Type, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 type WSHub struct {
@@ -50,6 +84,11 @@ type WSHub struct {
 	unregister     chan *WSClient
 	mu             sync.RWMutex
 	allowedOrigins []string
+
+	// open counts upgraded connections whose read loop is still running; unlike
+	// len(clients) it also covers clients dropped as stale but not yet closed.
+	open     atomic.Int64
+	accepted atomic.Int64
 }
 
 type WSMessage struct {
@@ -99,10 +138,7 @@ func (h *WSHub) Run() {
 			var stale []*WSClient
 			h.mu.RLock()
 			for client := range h.clients {
-				client.mu.RLock()
-				subscribed := msg.Channel == "" || client.channels[msg.Channel]
-				client.mu.RUnlock()
-				if subscribed {
+				if client.wants(msg) {
 					select {
 					case client.send <- data:
 					default:
@@ -136,6 +172,61 @@ func (h *WSHub) ClientCount() int {
 	return len(h.clients)
 }
 
+// WSClientInfo describes one connected browser.
+type WSClientInfo struct {
+	RemoteAddr  string    `json:"remoteAddr"`
+	Username    string    `json:"username,omitempty"`
+	ConnectedAt time.Time `json:"connectedAt"`
+	Channels    []string  `json:"channels"`
+	Events      []string  `json:"events"` // gateway event prefix filter
+}
+
+// WSHubStats is a snapshot of the hub's connections.
+type WSHubStats struct {
+	Open       int64          `json:"open"`       // connections with a live read loop
+	Registered int            `json:"registered"` // clients receiving broadcasts
+	Accepted   int64          `json:"accepted"`   // connections accepted since start
+	Clients    []WSClientInfo `json:"clients"`
+}
+
+// Stats returns the connection counters and per-client subscriptions, oldest first.
+func (h *WSHub) Stats() WSHubStats {
+	st := WSHubStats{
+		Open:     h.open.Load(),
+		Accepted: h.accepted.Load(),
+		Clients:  []WSClientInfo{},
+	}
+	h.mu.RLock()
+	st.Registered = len(h.clients)
+	for c := range h.clients {
+		c.mu.RLock()
+		info := WSClientInfo{
+			RemoteAddr:  c.remoteAddr,
+			Username:    c.username,
+			ConnectedAt: c.connectedAt,
+			Channels:    make([]string, 0, len(c.channels)),
+			Events:      append([]string{}, c.events...),
+		}
+		for ch := range c.channels {
+			info.Channels = append(info.Channels, ch)
+		}
+		c.mu.RUnlock()
+		sort.Strings(info.Channels)
+		st.Clients = append(st.Clients, info)
+	}
+	h.mu.RUnlock()
+	sort.Slice(st.Clients, func(i, j int) bool {
+		return st.Clients[i].ConnectedAt.Before(st.Clients[j].ConnectedAt)
+	})
+	return st
+}
+
+// HandleStats serves Stats.
+// GET /api/v1/ws/stats
+func (h *WSHub) HandleStats(w http.ResponseWriter, r *http.Request) {
+	OK(w, r, h.Stats())
+}
+
 func (h *WSHub) HandleWS(jwtSecret string) http.HandlerFunc {
 	wsUpgrader := newUpgrader(h.allowedOrigins)
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -150,10 +241,15 @@ func (h *WSHub) HandleWS(jwtSecret string) http.HandlerFunc {
 			Fail(w, r, ErrUnauthorized.Code, ErrUnauthorized.Message, ErrUnauthorized.HTTPStatus)
 			return
 		}
-		if _, err := ValidateJWT(tokenStr, jwtSecret); err != nil {
+		claims, err := ValidateJWT(tokenStr, jwtSecret)
+		if err != nil {
 			Fail(w, r, ErrTokenExpired.Code, ErrTokenExpired.Message, ErrTokenExpired.HTTPStatus)
 			return
 		}
+		if err := checkSession(claims); err != nil {
+			FailErr(w, r, ErrTokenRevoked)
+			return
+		}
 
 		conn, err := wsUpgrader.Upgrade(w, r, nil)
 		if err != nil {
@@ -166,7 +262,14 @@ func (h *WSHub) HandleWS(jwtSecret string) http.HandlerFunc {
 			conn:     conn,
 			send:     make(chan []byte, 256),
 			channels: make(map[string]bool),
+			events:   append([]string(nil), DefaultGWEventPrefixes...),
+
+			remoteAddr:  r.RemoteAddr,
+			username:    claims.Username,
+			connectedAt: time.Now(),
 		}
+		h.open.Add(1)
+		h.accepted.Add(1)
 		h.register <- client
 
 		go client.writePump()
@@ -178,6 +281,7 @@ func (c *WSClient) readPump() {
 	defer func() {
 		c.hub.unregister <- c
 		c.conn.Close()
+		c.hub.open.Add(-1)
 	}()
 	c.conn.SetReadDeadline(time.Now().Add(90 * time.Second))
 	c.conn.SetPongHandler(func(string) error {
@@ -190,13 +294,18 @@ func (c *WSClient) readPump() {
 			break
 		}
 		var msg struct {
-			Action   string   `json:"action"`
-			Channel  string   `json:"channel"`
-			Channels []string `json:"channels"`
+			Action    string   `json:"action"`
+			Channel   string   `json:"channel"`
+			Channels  []string `json:"channels"`
+			Subscribe []string `json:"subscribe"`
 		}
 		if err := json.Unmarshal(message, &msg); err != nil {
 			continue
 		}
+		// {"subscribe":[...]} replaces the gateway event prefix filter and joins the gw_event channel
+		if msg.Subscribe != nil {
+			c.setEventFilter(msg.Subscribe)
+		}
 		switch msg.Action {
 		case "subscribe":
 			c.mu.Lock()
@@ -222,6 +331,19 @@ func (c *WSClient) readPump() {
 	}
 }
 
+func (c *WSClient) setEventFilter(prefixes []string) {
+	events := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		if p = strings.TrimSpace(p); p != "" {
+			events = append(events, p)
+		}
+	}
+	c.mu.Lock()
+	c.events = events
+	c.channels[GWEventChannel] = true
+	c.mu.Unlock()
+}
+
 func (c *WSClient) writePump() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer func() {
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWSClient(h *WSHub, channels ...string) *WSClient {
+	c := &WSClient{
+		hub:      h,
+		send:     make(chan []byte, 16),
+		channels: make(map[string]bool),
+		events:   append([]string(nil), DefaultGWEventPrefixes...),
+	}
+	for _, ch := range channels {
+		c.channels[ch] = true
+	}
+	h.register <- c
+	return c
+}
+
+func recvWSMessage(t *testing.T, c *WSClient) (WSMessage, bool) {
+	t.Helper()
+	select {
+	case data := <-c.send:
+		var msg WSMessage
+		require.NoError(t, json.Unmarshal(data, &msg))
+		return msg, true
+	case <-time.After(200 * time.Millisecond):
+		return WSMessage{}, false
+	}
+}
+
+func TestWSHub_GWEventFilter(t *testing.T) {
+	h := NewWSHub()
+	go h.Run()
+
+	defaults := newTestWSClient(h, GWEventChannel)
+	chatOnly := newTestWSClient(h)
+	chatOnly.setEventFilter([]string{"chat"})
+	unsubscribed := newTestWSClient(h)
+
+	h.Broadcast(GWEventChannel, "chat", map[string]string{"text": "hi"})
+	h.Broadcast(GWEventChannel, "session.updated", map[string]string{"key": "main"})
+
+	msg, ok := recvWSMessage(t, chatOnly)
+	require.True(t, ok)
+	assert.Equal(t, "chat", msg.Type)
+	_, ok = recvWSMessage(t, chatOnly)
+	assert.False(t, ok, "session event should be filtered for chat-only client")
+
+	msg, ok = recvWSMessage(t, defaults)
+	require.True(t, ok)
+	assert.Equal(t, "session.updated", msg.Type, "chat is not in the default subset")
+	_, ok = recvWSMessage(t, defaults)
+	assert.False(t, ok)
+
+	_, ok = recvWSMessage(t, unsubscribed)
+	assert.False(t, ok, "client without gw_event subscription should receive nothing")
+}
+
+func TestWSHub_Stats(t *testing.T) {
+	h := NewWSHub()
+	go h.Run()
+
+	a := &WSClient{
+		hub:         h,
+		send:        make(chan []byte, 16),
+		channels:    map[string]bool{"alert": true, GWEventChannel: true},
+		events:      append([]string(nil), DefaultGWEventPrefixes...),
+		username:    "admin",
+		connectedAt: time.Now().Add(-time.Minute),
+	}
+	h.register <- a
+	newTestWSClient(h)
+
+	require.Eventually(t, func() bool { return h.Stats().Registered == 2 }, time.Second, 10*time.Millisecond)
+	st := h.Stats()
+	require.Len(t, st.Clients, 2)
+	// oldest first; b has no connect time
+	assert.Empty(t, st.Clients[0].Channels)
+	assert.Equal(t, "admin", st.Clients[1].Username)
+	assert.Equal(t, []string{"alert", GWEventChannel}, st.Clients[1].Channels)
+	assert.Equal(t, DefaultGWEventPrefixes, st.Clients[1].Events)
+
+	h.unregister <- a
+	assert.Eventually(t, func() bool { return h.Stats().Registered == 1 }, time.Second, 10*time.Millisecond)
+}
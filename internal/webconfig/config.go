@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -13,9 +15,22 @@ import (
 )
 
 type ServerConfig struct {
-	Port        int      `json:"port"`
-	Bind        string   `json:"bind"`
-	CORSOrigins []string `json:"cors_origins"`
+	Port        int         `json:"port"`
+	Bind        string      `json:"bind"`
+	CORSOrigins []string    `json:"cors_origins"`
+	TLSCert     string      `json:"tls_cert,omitempty"`
+	TLSKey      string      `json:"tls_key,omitempty"`
+	ACMEDomain  string      `json:"acme_domain,omitempty"`
+	ACMEEmail   string      `json:"acme_email,omitempty"`
+	CORSRoutes  []CORSRoute `json:"cors_routes,omitempty"` // 按路由前缀覆盖全局 CORS（最长前缀优先）
+}
+
+// CORSRoute 单个路由前缀的 CORS 策略。Origins 必须是显式的 scheme://host[:port]，
+// 可用 "*." 匹配子域名；任何情况下都不接受单独的 "*"
+type CORSRoute struct {
+	PathPrefix  string   `json:"path_prefix"`
+	Origins     []string `json:"origins"`
+	Credentials bool     `json:"credentials"`
 }
 
 type AuthConfig struct {
@@ -27,6 +42,10 @@ type DatabaseConfig struct {
 	Driver      string `json:"driver"`
 	SQLitePath  string `json:"sqlite_path"`
 	PostgresDSN string `json:"postgres_dsn"`
+	// 敏感设置（令牌、webhook 地址）加密主密钥，未配置时自动生成；
+	// 轮换时把旧密钥移到 previous_secret_keys，启动一次后即可删除
+	SecretKey          string   `json:"secret_key"`
+	PreviousSecretKeys []string `json:"previous_secret_keys,omitempty"`
 }
 
 type LogConfig struct {
@@ -37,6 +56,8 @@ type LogConfig struct {
 	MaxBackups int    `json:"max_backups"`
 	MaxAgeDays int    `json:"max_age_days"`
 	Compress   bool   `json:"compress"`
+	// Stdout 生产模式下同时输出到标准输出（交互运行时查看；服务部署通常关闭）
+	Stdout bool `json:"stdout"`
 }
 
 type OpenClawConfig struct {
@@ -44,6 +65,8 @@ type OpenClawConfig struct {
 	GatewayHost  string `json:"gateway_host"`
 	GatewayPort  int    `json:"gateway_port"`
 	GatewayToken string `json:"gateway_token"`
+	// 空闲保活间隔（秒），0 表示禁用
+	KeepaliveSeconds int `json:"keepalive_seconds"`
 }
 
 type MonitorConfig struct {
@@ -113,10 +136,11 @@ func Default() Config {
 			Compress:   true,
 		},
 		OpenClaw: OpenClawConfig{
-			ConfigPath:   defaultOpenClawConfigDir(),
-			GatewayHost:  "127.0.0.1",
-			GatewayPort:  18789,
-			GatewayToken: "",
+			ConfigPath:       defaultOpenClawConfigDir(),
+			GatewayHost:      "127.0.0.1",
+			GatewayPort:      18789,
+			GatewayToken:     "",
+			KeepaliveSeconds: 45,
 		},
 		Monitor: MonitorConfig{
 			IntervalSeconds: 30,
@@ -137,6 +161,11 @@ func ConfigPath() string {
 	return filepath.Join(defaultDataDir(), "openclawdeck.json")
 }
 
+// DataDir 返回 OpenClawDeck 数据目录（配置文件所在目录）
+func DataDir() string {
+	return filepath.Dir(ConfigPath())
+}
+
 func Load() (Config, error) {
 	cfg := Default()
 
@@ -155,20 +184,41 @@ func Load() (Config, error) {
 	// Layer 2: environment variables override
 	applyEnvOverrides(&cfg)
 
-	// Layer 3: generate JWT secret if empty and persist it
-	if cfg.Auth.JWTSecret == "" {
-		secret, err := generateSecret(32)
-		if err != nil {
-			return cfg, err
+	// Layer 3: generate JWT secret and settings secret key if empty and persist them
+	if cfg.Auth.JWTSecret == "" || cfg.Database.SecretKey == "" {
+		if cfg.Auth.JWTSecret == "" {
+			secret, err := generateSecret(32)
+			if err != nil {
+				return cfg, err
+			}
+			cfg.Auth.JWTSecret = secret
+		}
+		generatedKey := false
+		if cfg.Database.SecretKey == "" {
+			secret, err := generateSecret(32)
+			if err != nil {
+				return cfg, err
+			}
+			cfg.Database.SecretKey = secret
+			generatedKey = true
+		}
+		// Persist so the secrets survive restarts
+		if err := saveConfig(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法保存生成的密钥到 %s: %v\n", ConfigPath(), err)
+			// A settings key that is not on disk would leave encrypted values
+			// unreadable after the next restart; keep settings in plaintext instead
+			if generatedKey {
+				cfg.Database.SecretKey = ""
+			}
 		}
-		cfg.Auth.JWTSecret = secret
-		// Persist so the secret survives restarts
-		_ = Save(cfg)
 	}
 
 	return cfg, nil
 }
 
+// saveConfig is swapped out in tests to simulate an unwritable data dir
+var saveConfig = Save
+
 func Save(cfg Config) error {
 	path := ConfigPath()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -181,8 +231,9 @@ func Save(cfg Config) error {
 	return os.WriteFile(path, append(data, '\n'), 0o600)
 }
 
+// ListenAddr 返回 host:port 监听地址（IPv6 地址自动加方括号）
 func (c *Config) ListenAddr() string {
-	return c.Server.Bind + ":" + strconv.Itoa(c.Server.Port)
+	return net.JoinHostPort(c.Server.Bind, strconv.Itoa(c.Server.Port))
 }
 
 func (c *Config) JWTExpireDuration() time.Duration {
@@ -206,6 +257,18 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("OCD_BIND"); v != "" {
 		cfg.Server.Bind = v
 	}
+	if v := os.Getenv("OCD_TLS_CERT"); v != "" {
+		cfg.Server.TLSCert = v
+	}
+	if v := os.Getenv("OCD_TLS_KEY"); v != "" {
+		cfg.Server.TLSKey = v
+	}
+	if v := os.Getenv("OCD_ACME_DOMAIN"); v != "" {
+		cfg.Server.ACMEDomain = v
+	}
+	if v := os.Getenv("OCD_ACME_EMAIL"); v != "" {
+		cfg.Server.ACMEEmail = v
+	}
 	if v := os.Getenv("OCD_DB_DRIVER"); v != "" {
 		cfg.Database.Driver = v
 	}
@@ -215,6 +278,9 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("OCD_DB_DSN"); v != "" {
 		cfg.Database.PostgresDSN = v
 	}
+	if v := os.Getenv("OCD_DB_SECRET_KEY"); v != "" {
+		cfg.Database.SecretKey = v
+	}
 	if v := os.Getenv("OCD_JWT_SECRET"); v != "" {
 		cfg.Auth.JWTSecret = v
 	}
@@ -230,6 +296,26 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("OCD_LOG_FILE"); v != "" {
 		cfg.Log.FilePath = v
 	}
+	if v := os.Getenv("OCD_LOG_MAX_SIZE_MB"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.Log.MaxSizeMB = n
+		}
+	}
+	if v := os.Getenv("OCD_LOG_MAX_BACKUPS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.Log.MaxBackups = n
+		}
+	}
+	if v := os.Getenv("OCD_LOG_MAX_AGE_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.Log.MaxAgeDays = n
+		}
+	}
+	if v := os.Getenv("OCD_LOG_STDOUT"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Log.Stdout = b
+		}
+	}
 	if v := os.Getenv("OCD_OPENCLAW_CONFIG_PATH"); v != "" {
 		cfg.OpenClaw.ConfigPath = v
 	}
@@ -244,6 +330,11 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("OCD_OPENCLAW_GATEWAY_TOKEN"); v != "" {
 		cfg.OpenClaw.GatewayToken = v
 	}
+	if v := os.Getenv("OCD_OPENCLAW_KEEPALIVE"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil && p >= 0 {
+			cfg.OpenClaw.KeepaliveSeconds = p
+		}
+	}
 	if v := os.Getenv("OCD_MONITOR_INTERVAL"); v != "" {
 		if p, err := strconv.Atoi(v); err == nil {
 			cfg.Monitor.IntervalSeconds = p
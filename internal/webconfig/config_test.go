@@ -1,6 +1,8 @@
 package webconfig
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -53,6 +55,9 @@ func TestConfig_ListenAddr(t *testing.T) {
 	}
 
 	assert.Equal(t, "127.0.0.1:8080", cfg.ListenAddr())
+
+	cfg.Server.Bind = "::1"
+	assert.Equal(t, "[::1]:8080", cfg.ListenAddr())
 }
 
 func TestConfig_ListenAddr_Default(t *testing.T) {
@@ -203,3 +208,14 @@ func TestAlertConfig(t *testing.T) {
 	assert.Contains(t, cfg.Channels, "email")
 	assert.Contains(t, cfg.Channels, "slack")
 }
+
+func TestLoad_UnsavedSecretKeyNotUsed(t *testing.T) {
+	t.Setenv("OCD_CONFIG", filepath.Join(t.TempDir(), "openclawdeck.json"))
+	saveConfig = func(Config) error { return errors.New("read-only file system") }
+	defer func() { saveConfig = Save }()
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cfg.Auth.JWTSecret)
+	assert.Empty(t, cfg.Database.SecretKey)
+}
@@ -53,7 +53,7 @@ export const ConfirmProvider: React.FC<{ children: React.ReactNode }> = ({ child
                 </span>
               </div>
               <h3 className="text-base font-bold text-slate-800 dark:text-white mb-2">{options.title}</h3>
-              <p className="text-[13px] text-slate-600 dark:text-white/70 leading-relaxed">
+              <p className="text-[13px] text-slate-600 dark:text-white/70 leading-relaxed whitespace-pre-line">
                 {options.message}
               </p>
             </div>
@@ -29,7 +29,7 @@ export function useBadgeCounts(enabled = true): Record<WindowID, number> {
     const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
     const ws = new WebSocket(`${proto}//${location.host}/api/v1/ws`);
     ws.onopen = () => {
-      ws.send(JSON.stringify({ action: 'subscribe', channels: ['alert', 'gw_event'] }));
+      ws.send(JSON.stringify({ action: 'subscribe', channels: ['alert', 'gw_event'], subscribe: ['exec.approval', 'shutdown'] }));
     };
     ws.onmessage = (evt) => {
       try {
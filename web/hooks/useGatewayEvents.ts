@@ -92,7 +92,7 @@ export function useGatewayEvents(handlers: GatewayEventHandlers) {
     const ws = new WebSocket(`${proto}//${location.host}/api/v1/ws`);
 
     ws.onopen = () => {
-      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'] }));
+      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'], subscribe: Object.keys(handlersRef.current) }));
     };
     ws.onmessage = onMessage;
 
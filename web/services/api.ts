@@ -1,5 +1,5 @@
 // OpenClawDeck API 服务层 — 对应后端所有 REST API 端点
-import { get, post, put, del, setToken, clearToken } from './request';
+import { get, post, postText, put, del, setToken, clearToken } from './request';
 
 // ==================== 鉴权 ====================
 export const authApi = {
@@ -20,6 +20,11 @@ export const authApi = {
   changeUsername: (new_username: string, password: string) =>
     put('/api/v1/auth/username', { new_username, password }),
   me: () => get<{ id: number; username: string; role: string }>('/api/v1/auth/me'),
+  sessions: () => get<Array<{
+    jti: string; user_id: number; username: string; ip: string; user_agent: string;
+    expires_at: string; last_seen_at: string; created_at: string; current: boolean;
+  }>>('/api/v1/auth/sessions'),
+  revokeSession: (jti: string) => del(`/api/v1/auth/sessions/${jti}`),
   logout: () => post('/api/v1/auth/logout').then(() => {
     // Optional: reload page to ensure state references are cleared
     window.location.reload();
@@ -33,6 +38,17 @@ export const hostInfoApi = {
 };
 
 // ==================== 自更新 ====================
+export interface Capabilities {
+  version: string; build: string; os: string; arch: string; goVersion: string;
+  openclawCompat: string;
+  gatewayProtocol: { min: number; max: number };
+  features: Record<string, boolean>;
+}
+
+export const capabilitiesApi = {
+  get: () => get<Capabilities>('/api/v1/capabilities'),
+};
+
 export const selfUpdateApi = {
   info: () => get<{ version: string; build: string; os: string; arch: string; platform: string }>('/api/v1/self-update/info'),
   check: () => get<{
@@ -40,8 +56,30 @@ export const selfUpdateApi = {
     releaseNotes?: string; publishedAt?: string;
     assetName?: string; assetSize?: number; downloadUrl?: string; error?: string;
   }>('/api/v1/self-update/check'),
+  changelog: (from?: string, to?: string) => get<ChangelogResult>(`/api/v1/self-update/changelog${changelogQuery(from, to)}`),
+  openclawChangelog: (from?: string, to?: string) => get<ChangelogResult>(`/api/v1/self-update/openclaw-changelog${changelogQuery(from, to)}`),
 };
 
+export interface ChangelogResult {
+  project: string;
+  from?: string;
+  to?: string;
+  source?: string;
+  available: boolean;
+  releases: { version: string; name?: string; publishedAt?: string; body: string; url?: string }[];
+  markdown: string;
+  error?: string;
+  fetchedAt: string;
+}
+
+function changelogQuery(from?: string, to?: string): string {
+  const params = new URLSearchParams();
+  if (from) params.set('from', from);
+  if (to) params.set('to', to);
+  const qs = params.toString();
+  return qs ? `?${qs}` : '';
+}
+
 // ==================== 服务器访问配置 ====================
 export interface ServerConfig {
   bind: string;
@@ -53,9 +91,41 @@ export const serverConfigApi = {
   update: (data: ServerConfig) => put<ServerConfig & { restart: boolean }>('/api/v1/server-config', data),
 };
 
+// ==================== 数据库维护 ====================
+export interface DBStats {
+  driver: string;
+  path?: string;
+  size_bytes: number;
+  reclaimable_bytes: number;
+  auto_vacuum?: string;
+}
+export interface VacuumResult {
+  mode: 'full' | 'incremental';
+  before_bytes: number;
+  after_bytes: number;
+  freed_bytes: number;
+  duration_ms: number;
+  finished_at: string;
+}
+export const dbApi = {
+  stats: () => get<DBStats>('/api/v1/admin/db/stats'),
+  vacuum: (force = false) => post<VacuumResult>(`/api/v1/admin/db/vacuum${force ? '?force=1' : ''}`),
+};
+
+// 维护模式：暂停心跳自动重启、告警、通知与事件采集
+export interface MaintenanceState {
+  enabled: boolean;
+  since?: string;
+}
+export const maintenanceApi = {
+  get: () => get<MaintenanceState>('/api/v1/admin/maintenance'),
+  set: (enabled: boolean) => post<MaintenanceState>('/api/v1/admin/maintenance', { enabled }),
+};
+
 // ==================== 总览 ====================
 export const dashboardApi = {
-  get: () => get<{
+  // the server caches the summary for a few seconds; fresh bypasses it
+  get: (fresh = false) => get<{
     gateway: { running: boolean; runtime: string; detail: string };
     onboarding: {
       installed: boolean; initialized: boolean; model_configured: boolean;
@@ -65,31 +135,33 @@ export const dashboardApi = {
     recent_alerts: any[];
     security_score: number;
     ws_clients: number;
-  }>('/api/v1/dashboard'),
+  }>(`/api/v1/dashboard${fresh ? '?fresh=true' : ''}`),
 };
 
 // ==================== 网关管理 ====================
 export const gatewayApi = {
-  status: () => get<{ running: boolean; runtime: string; detail: string }>('/api/v1/gateway/status'),
+  status: () => get<{ running: boolean; runtime: string; detail: string; maintenance?: boolean }>('/api/v1/gateway/status'),
   start: () => post('/api/v1/gateway/start'),
   stop: () => post('/api/v1/gateway/stop'),
   restart: () => post('/api/v1/gateway/restart'),
-  kill: () => post('/api/v1/gateway/kill'),
+  // config.reload; the server falls back to a restart when reload is not possible
+  reload: () => post<{ message: string; mode: 'reload' | 'restart' }>('/api/v1/gateway/reload'),
+  kill: (level?: 'graceful' | 'term' | 'force') => post('/api/v1/gateway/kill', level ? { level } : undefined),
   log: (lines = 200) => get<{ lines: string[] }>(`/api/v1/gateway/log?lines=${lines}`),
   getHealthCheck: () => get<{ enabled: boolean; fail_count: number; max_fails: number; last_ok: string }>('/api/v1/gateway/health-check'),
   setHealthCheck: (enabled: boolean) => put('/api/v1/gateway/health-check', { enabled }),
-  diagnose: () => post<{
+  diagnose: (token?: string) => post<{
     items: Array<{
       name: string;
       label: string;
       labelEn: string;
-      status: 'pass' | 'fail' | 'warn';
+      status: 'pass' | 'fail' | 'warn' | 'skip';
       detail: string;
       suggestion?: string;
     }>;
     summary: string;
     message: string;
-  }>('/api/v1/gateway/diagnose'),
+  }>('/api/v1/gateway/diagnose', token ? { token } : undefined),
 };
 
 // ==================== 网关配置档案（多网关管理） ====================
@@ -115,11 +187,43 @@ export const activityApi = {
       `/api/v1/activities?${qs.toString()}`
     );
   },
+  // server-side hourly/daily counts with risk and category breakdowns
+  timeline: (params?: { bucket?: 'hour' | 'day'; days?: number; tz?: string }) => {
+    const qs = new URLSearchParams();
+    if (params?.bucket) qs.set('bucket', params.bucket);
+    if (params?.days) qs.set('days', String(params.days));
+    qs.set('tz', params?.tz || Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC');
+    return get<{ bucket: string; timezone: string; buckets: TimelineBucket[] }>(
+      `/api/v1/activities/timeline?${qs.toString()}`
+    );
+  },
+  // deck activities of one gateway session; session_key/history_url link to the transcript
+  bySession: (sessionId: string, params?: { key?: string; limit?: number }) => {
+    const qs = new URLSearchParams({ sessionId });
+    if (params?.key) qs.set('key', params.key);
+    if (params?.limit) qs.set('limit', String(params.limit));
+    return get<{
+      session_id: string;
+      activities: any[];
+      total: number;
+      truncated: boolean;
+      session_key?: string;
+      history_url?: string;
+    }>(`/api/v1/activities/by-session?${qs.toString()}`);
+  },
 };
+export interface TimelineBucket {
+  start: string;
+  total: number;
+  by_risk: Record<string, number>;
+  by_category: Record<string, number>;
+}
 
 // ==================== 监控统计 ====================
 export const monitorApi = {
   stats: () => get('/api/v1/monitor/stats'),
+  wsStats: () => get('/api/v1/ws/stats'),
+  idleSessionsPreview: () => get('/api/v1/monitor/idle-sessions/preview'),
   getConfig: () => get('/api/v1/monitor/config'),
   updateConfig: (data: any) => put('/api/v1/monitor/config', data),
   start: () => post('/api/v1/monitor/start'),
@@ -135,24 +239,53 @@ export const securityApi = {
 };
 
 // ==================== 系统设置 ====================
+export interface SettingDef {
+  key: string;
+  type: 'string' | 'bool' | 'int' | 'duration';
+  default: string;
+  description?: string;
+  min?: number;
+  max?: number;
+  enum?: string[];
+}
 export const settingsApi = {
   getAll: () => get('/api/v1/settings'),
+  schema: () => get<SettingDef[]>('/api/v1/settings/schema'),
   update: (data: any) => put('/api/v1/settings', data),
   getGateway: () => get('/api/v1/settings/gateway'),
   updateGateway: (data: any) => put('/api/v1/settings/gateway', data),
+  getLogLevel: () => get<{ level: string; configured: string; persisted: string; levels: string[] }>('/api/v1/settings/log-level'),
+  setLogLevel: (level: string, persist = false) => put('/api/v1/settings/log-level', { level, persist }),
 };
 
 // ==================== 配对管理 ====================
+// 过期请求（超过 pairing_request_ttl_minutes）由后端隐藏
+export interface PairingRequest {
+  id: string;
+  code: string;
+  createdAt: string;
+  lastSeenAt: string;
+  meta?: Record<string, string>;
+  ageSeconds?: number;
+}
 export const pairingApi = {
-  list: (channel: string) => get<{ channel: string; requests: any[]; error?: string }>(`/api/v1/pairing/list?channel=${channel}`),
+  list: (channel: string) => get<{ channel: string; requests: PairingRequest[]; error?: string }>(`/api/v1/pairing/list?channel=${channel}`),
   approve: (channel: string, code: string) => post<{ message: string; status: string }>('/api/v1/pairing/approve', { channel, code }),
 };
 
 // ==================== 通知配置 ====================
+export interface NotifyChannelResult {
+  channel: string;
+  ok: boolean;
+  error?: string;
+  status?: number;
+  latency_ms: number;
+}
 export const notifyApi = {
   getConfig: () => get<any>('/api/v1/notify/config'),
   updateConfig: (data: Record<string, string>) => put('/api/v1/notify/config', data),
   testSend: (message?: string) => post('/api/v1/notify/test', { message: message || '' }),
+  testAll: (message?: string) => post<{ results: NotifyChannelResult[]; failed: number }>('/api/v1/notify/test-all', { message: message || '' }),
 };
 
 // ==================== 告警 ====================
@@ -179,16 +312,50 @@ export const auditApi = {
       `/api/v1/audit-logs?${qs.toString()}`
     );
   },
+  loginSummary: (params?: { days?: number; rdns?: boolean }) => {
+    const qs = new URLSearchParams();
+    if (params?.days) qs.set('days', String(params.days));
+    if (params?.rdns) qs.set('rdns', '1');
+    return get<{ since: string; days: number; ips: any[] }>(
+      `/api/v1/audit-logs/login-summary?${qs.toString()}`
+    );
+  },
 };
 
 // ==================== OpenClaw 配置 ====================
+// 保存后网关采取的操作：none 无变化 / reload 热加载 / restart 重启 / deferred 网关未连接，下次启动生效
+export interface ConfigApply {
+  action: 'none' | 'reload' | 'restart' | 'deferred';
+  keys?: string[];
+  error?: string;
+}
+// 与推荐基线（默认安全配置或配置模板）的差异
+export interface ConfigDrift {
+  path: string;
+  kind: 'missing' | 'changed';
+  current?: any;
+  recommended?: any;
+  severity: 'error' | 'warn' | 'info';
+  code?: string;
+  hint?: string;
+}
 export const configApi = {
   get: () => get<{ config: Record<string, any>; path: string; parsed: boolean }>('/api/v1/config'),
-  update: (config: Record<string, any>) => put('/api/v1/config', { config }),
+  update: (config: Record<string, any>) => put<{ message: string; apply: ConfigApply }>('/api/v1/config', { config }),
+  // 经网关 WebSocket 写入后，由服务端按与 update 相同的规则热加载或重启
+  applyChange: (prev: Record<string, any>, next: Record<string, any>) => post<{ message: string; apply: ConfigApply }>('/api/v1/config/apply-change', { prev, next }),
   generateDefault: () => post<{ message: string; path: string }>('/api/v1/config/generate-default'),
   setKey: (key: string, value: string, json = true) => post<{ message: string; key: string }>('/api/v1/config/set-key', { key, value, json }),
   unsetKey: (key: string) => post<{ message: string; key: string }>('/api/v1/config/unset-key', { key }),
   getKey: (key: string) => get<{ key: string; value: any }>(`/api/v1/config/get-key?key=${encodeURIComponent(key)}`),
+  getEnv: () => get<{ path: string; exists: boolean; entries: { key: string; value: string; redacted?: boolean }[] }>('/api/v1/config/env'),
+  updateEnv: (set: Record<string, string>, remove: string[] = []) => put<{ message: string; set: string[]; removed: string[] }>('/api/v1/config/env', { set, remove }),
+  // 导出为下载链接；redact=false 导出明文密钥（会记审计）
+  exportUrl: (format: 'yaml' | 'json' = 'yaml', redact = true) => `/api/v1/config/export?format=${format}${redact ? '' : '&redact=false'}`,
+  // 导入 YAML 或 JSON 文本（按内容识别），脱敏占位符保留当前值
+  import: (text: string) => postText<{ backup: string; apply: ConfigApply }>('/api/v1/config/import', text, text.trimStart().startsWith('{') ? 'application/json' : 'application/yaml'),
+  // 与默认安全配置（不传 templateId）或配置模板对比
+  compare: (templateId?: number, lang?: string) => post<{ baseline: string; differences: ConfigDrift[]; issues: any[]; counts: Record<string, number> }>('/api/v1/config/compare', { template_id: templateId || 0, lang }),
 };
 
 // ==================== 备份管理 ====================
@@ -204,6 +371,8 @@ export const backupApi = {
 export const doctorApi = {
   run: () => get('/api/v1/doctor'),
   fix: () => post('/api/v1/doctor/fix'),
+  bundleUrl: () => '/api/v1/diag/bundle',
+  logInfo: () => get('/api/v1/admin/log'),
 };
 
 // ==================== 用户管理 ====================
@@ -214,8 +383,31 @@ export const userApi = {
 };
 
 // ==================== 技能审计 ====================
+export interface SkillIntegrityStatus {
+  skill: string;
+  status: 'ok' | 'modified' | 'missing' | 'untracked';
+  files: number;
+  modified?: string[];
+  added?: string[];
+  removed?: string[];
+  checkedAt?: string;
+  error?: string;
+}
+export interface SkillReadme {
+  slug: string;
+  source: 'local' | 'registry';
+  path?: string;
+  content: string;
+  frontmatter: Record<string, any>;
+  body: string;
+}
 export const skillsApi = {
   list: () => get<any[]>('/api/v1/skills'),
+  // 最近一次校验结果；verify 立即重新校验（管理员）
+  integrity: () => get<SkillIntegrityStatus[]>('/api/v1/skills/integrity'),
+  verifyIntegrity: () => post<SkillIntegrityStatus[]>('/api/v1/skills/integrity/verify'),
+  baseline: (skill: string) => post<{ skill: string; status: string }>('/api/v1/skills/integrity/baseline', { skill }),
+  readme: (slug: string) => get<SkillReadme>(`/api/v1/skills/${encodeURIComponent(slug)}/readme`),
 };
 
 // ==================== 模板管理 ====================
@@ -225,6 +417,8 @@ export const templateApi = {
   create: (data: { template_id: string; target_file: string; icon: string; category: string; tags: string; author: string; i18n: string }) => post<any>('/api/v1/templates', data),
   update: (data: { id: number; template_id?: string; target_file?: string; icon?: string; category?: string; tags?: string; author?: string; i18n?: string }) => put<any>('/api/v1/templates', data),
   remove: (id: number) => del<any>(`/api/v1/templates/?id=${id}`),
+  apply: (id: number, values: Record<string, string>, lang?: string) =>
+    post<{ target_file: string; content: string; merged: boolean }>(`/api/v1/templates/${id}/apply`, { values, lang }),
 };
 
 // ==================== OpenClaw 安装/初始化 ====================
@@ -245,13 +439,16 @@ export const pluginApi = {
 // ==================== Gateway 代理 API ====================
 // 统一通过 GenericProxy (/api/v1/gw/proxy) 透传 JSON-RPC 到 Gateway。
 // 仅保留少量 REST 路由：status（本地连接检查）、sessionsUsage / usageCost（Go 层有额外参数/超时）、
-// skillsConfig / skillsConfigure（Go 层有复杂聚合逻辑）。
+// sessionsHistoryPage / sessionsSearch / sessionsBulk（Go 层分页、搜索与批量操作）、agents* / cronRunNow / cronToggle（管理员权限 + 审计），skillsConfig / skillsConfigure（Go 层有复杂聚合逻辑）。
 const rpc = <T = any>(method: string, params?: any): Promise<T> =>
   post<T>('/api/v1/gw/proxy', { method, params: params ?? {} });
 
 export const gwApi = {
   // --- 保留 REST（Go 层有额外逻辑） ---
   status: () => get('/api/v1/gw/status'),
+  // 各 RPC 方法的调用统计（次数、错误、耗时、报文大小），管理员
+  rpcStats: () => get<{ since: string; methods: { method: string; calls: number; errors: number; timeouts: number; total_ms: number; avg_ms: number; max_ms: number; last_ms: number; bytes_out: number; bytes_in: number; last_at: string; last_error?: string }[] }>('/api/v1/gw/rpc-stats'),
+  resetRpcStats: () => del('/api/v1/gw/rpc-stats'),
   sessionsUsage: (params?: { startDate?: string; endDate?: string; limit?: number; key?: string }) => {
     const qs = new URLSearchParams();
     if (params?.startDate) qs.set('startDate', params.startDate);
@@ -261,6 +458,28 @@ export const gwApi = {
     const q = qs.toString();
     return get(`/api/v1/gw/sessions/usage${q ? '?' + q : ''}`);
   },
+  sessionsHistoryPage: (key: string, opts?: { limit?: number; before?: string }) => {
+    const qs = new URLSearchParams({ key, limit: String(opts?.limit ?? 100) });
+    if (opts?.before) qs.set('before', opts.before);
+    return get<{ messages: any[]; total: number; hasMore: boolean; nextBefore?: string }>(`/api/v1/gw/sessions/history?${qs}`);
+  },
+  sessionsSearch: (key: string, q: string, opts?: { role?: string; limit?: number; context?: number }) => {
+    const qs = new URLSearchParams({ key, q });
+    if (opts?.role) qs.set('role', opts.role);
+    if (opts?.limit) qs.set('limit', String(opts.limit));
+    if (opts?.context != null) qs.set('context', String(opts.context));
+    return get<{ matches: { index: number; cursor: string; role?: string; snippet: string; message: any; before: any[]; after: any[] }[]; total: number; truncated: boolean; scanned: number }>(`/api/v1/gw/sessions/search?${qs}`);
+  },
+  sessionsBulk: (keys: string[], action: 'reset' | 'delete', deleteTranscript = false) =>
+    post<{ action: string; total: number; succeeded: number; failed: number; results: { key: string; ok: boolean; error?: string }[] }>(
+      '/api/v1/gw/sessions/bulk', { keys, action, deleteTranscript }),
+  agentsCreate: (agent: { name: string; workspace?: string; emoji?: string }) => post('/api/v1/gw/agents/create', agent),
+  agentsUpdate: (agentId: string, patch: { name?: string; workspace?: string; model?: string; avatar?: string; emoji?: string }) =>
+    post('/api/v1/gw/agents/update', { agentId, ...patch }),
+  agentsDelete: (agentId: string, deleteFiles = false) => post('/api/v1/gw/agents/delete', { agentId, deleteFiles }),
+  agentsEnable: (agentId: string, enabled: boolean) => post('/api/v1/gw/agents/enable', { agentId, enabled }),
+  cronRunNow: (id: string) => post('/api/v1/gw/cron/run', { id }),
+  cronToggle: (id: string, enabled: boolean) => post('/api/v1/gw/cron/toggle', { id, enabled }),
   usageCost: (params?: { startDate?: string; endDate?: string; days?: number }) => {
     const qs = new URLSearchParams();
     if (params?.startDate) qs.set('startDate', params.startDate);
@@ -427,6 +646,7 @@ export const clawHubApi = {
   },
   search: (q: string) => get<any[]>(`/api/v1/clawhub/search?q=${encodeURIComponent(q)}`),
   detail: (slug: string) => get(`/api/v1/clawhub/skill?slug=${encodeURIComponent(slug)}`),
+  requirements: (slug: string) => get<any>(`/api/v1/clawhub/skill/requirements?slug=${encodeURIComponent(slug)}`),
   install: (slug: string) => post('/api/v1/clawhub/install', { slug }),
   uninstall: (slug: string) => post('/api/v1/clawhub/uninstall', { slug }),
   update: (slug: string) => post('/api/v1/clawhub/update', { slug }),
@@ -444,6 +664,7 @@ export const exportApi = {
 // ==================== 角标计数 ====================
 export const badgeApi = {
   counts: () => get<Record<string, number>>('/api/v1/badges'),
+  refresh: () => post<Record<string, number>>('/api/v1/badges/refresh'),
 };
 
 // ==================== 健康检查 ====================
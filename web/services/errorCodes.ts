@@ -10,6 +10,8 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   AUTH_ACCOUNT_LOCKED: { zh: '账户已锁定，请稍后再试', en: 'Account locked, try again later' },
   AUTH_TOKEN_EXPIRED: { zh: '会话已过期，请重新登录', en: 'Session expired, please login again' },
   AUTH_TOKEN_INVALID: { zh: '无效的令牌', en: 'Invalid token' },
+  AUTH_TOKEN_REVOKED: { zh: '会话已被注销，请重新登录', en: 'Session revoked, please login again' },
+  AUTH_SESSION_NOT_FOUND: { zh: '会话不存在', en: 'Session not found' },
   AUTH_EMPTY_CREDENTIALS: { zh: '用户名和密码不能为空', en: 'Username and password required' },
   AUTH_PASSWORD_TOO_SHORT: { zh: '密码至少需要6位', en: 'Password must be at least 6 characters' },
   AUTH_SETUP_DONE: { zh: '管理员账号已存在', en: 'Admin account already exists' },
@@ -26,6 +28,12 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   DB_QUERY_FAILED: { zh: '数据库查询失败', en: 'Database query failed' },
   ENCRYPT_FAILED: { zh: '加密失败', en: 'Encryption failed' },
   PATH_ERROR: { zh: '无法确定用户目录', en: 'Cannot determine user directory' },
+  SERVICE_NOT_READY: { zh: '服务正在启动，请稍后再试', en: 'Service is starting, try again shortly' },
+
+  // Database maintenance
+  DB_VACUUM_FAILED: { zh: '数据库压缩失败', en: 'Database vacuum failed' },
+  DB_BUSY: { zh: '数据库繁忙，请稍后再试', en: 'Database is busy, try again later' },
+  DB_VACUUM_UNSUPPORTED: { zh: '仅 SQLite 数据库支持压缩', en: 'Vacuum is only supported for SQLite' },
 
   // User management
   USER_NOT_FOUND: { zh: '用户不存在', en: 'User not found' },
@@ -71,6 +79,7 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   CONFIG_WRITE_FAILED: { zh: '配置写入失败', en: 'Config write failed' },
   CONFIG_GEN_FAILED: { zh: '配置生成失败', en: 'Config generation failed' },
   CONFIG_EMPTY: { zh: '没有有效的配置项', en: 'No valid config entries' },
+  CONFIG_INVALID: { zh: '配置未通过校验', en: 'Config failed validation' },
 
   // Security
   SECURITY_QUERY_FAILED: { zh: '规则查询失败', en: 'Rule query failed' },
@@ -89,6 +98,8 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   // Settings
   SETTINGS_QUERY_FAILED: { zh: '设置查询失败', en: 'Settings query failed' },
   SETTINGS_UPDATE_FAILED: { zh: '设置更新失败', en: 'Settings update failed' },
+  SETTINGS_INVALID: { zh: '设置值无效', en: 'Invalid setting value' },
+  PAIRING_SIGNATURE_INVALID: { zh: '配对审批请求缺少签名或签名无效', en: 'Missing or invalid pairing request signature' },
 
   // Skills
   SKILL_NOT_FOUND: { zh: '技能不存在', en: 'Skill not found' },
@@ -97,6 +108,7 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   SKILL_UPDATE_FAILED: { zh: '技能更新失败', en: 'Skill update failed' },
   SKILLS_READ_ERROR: { zh: '技能目录读取失败', en: 'Skills directory read failed' },
   SKILLS_PATH_ERROR: { zh: '无法确定用户目录', en: 'Cannot determine user directory' },
+  SKILL_INTEGRITY_FAILED: { zh: '技能完整性校验失败', en: 'Skill integrity check failed' },
 
   // OpenClaw
   OPENCLAW_NOT_INSTALLED: { zh: 'OpenClaw 未安装', en: 'OpenClaw is not installed' },
@@ -104,6 +116,12 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   INSTALL_FAILED: { zh: '安装失败', en: 'Install failed' },
   SCAN_ERROR: { zh: '环境扫描失败', en: 'Scan failed' },
 
+  // Setup wizard
+  CONFIG_ERROR: { zh: 'OpenClaw 配置失败', en: 'OpenClaw configuration failed' },
+  START_ERROR: { zh: '网关启动失败', en: 'Gateway start failed' },
+  SETUP_NO_PROGRESS: { zh: '没有可继续的中断安装', en: 'No interrupted install to resume' },
+  SETUP_RESUME_NEEDS_KEY: { zh: '继续配置需要重新填写 API Key', en: 'API key is required to resume configuration' },
+
   // Monitor
   MONITOR_NOT_RUNNING: { zh: '监控服务未运行', en: 'Monitor service not running' },
   LOG_READ_ERROR: { zh: '日志读取失败', en: 'Log read failed' },
@@ -119,6 +137,9 @@ const errorMessages: Record<string, { zh: string; en: string }> = {
   // ClawHub
   CLAWHUB_FAILED: { zh: 'ClawHub 请求失败', en: 'ClawHub request failed' },
 
+  // Self-update
+  UPDATE_CHECK_FAILED: { zh: '检查更新失败', en: 'Update check failed' },
+
   // Router-level
   SYSTEM_METHOD_NOT_ALLOWED: { zh: '方法不允许', en: 'Method not allowed' },
 };
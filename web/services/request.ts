@@ -47,6 +47,13 @@ async function request<T = any>(
 
   // 401 → reload only if previously authenticated (or let the app handle it)
   if (res.status === 401) {
+    // signed pairing approvals fail with 401 without the session being invalid
+    if (url.includes('/pairing/approve')) {
+      const body: ApiResponse<T> | null = await res.json().catch(() => null);
+      if (body?.error_code === 'PAIRING_SIGNATURE_INVALID') {
+        throw new ApiError(body.error_code, translateApiError(body.error_code, body.message || ''), 401);
+      }
+    }
     // If we get 401, it means the cookie is invalid or missing.
     // We can just reload to force a re-login flow if needed, or throw.
     if (!url.includes('/auth/login') && !url.includes('/auth/needs-setup')) {
@@ -77,6 +84,15 @@ export function post<T = any>(url: string, body?: any): Promise<T> {
   });
 }
 
+// postText sends a raw text body (e.g. a YAML document) instead of JSON.
+export function postText<T = any>(url: string, text: string, contentType = 'text/plain'): Promise<T> {
+  return request<T>(url, {
+    method: 'POST',
+    body: text,
+    headers: { 'Content-Type': contentType },
+  });
+}
+
 export function put<T = any>(url: string, body?: any): Promise<T> {
   return request<T>(url, {
     method: 'PUT',
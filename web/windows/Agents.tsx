@@ -106,7 +106,7 @@ const Agents: React.FC<AgentsProps> = ({ language }) => {
       clearTimeout(connectTimeout);
       setGwReady(true);
       setWsConnecting(false);
-      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'] }));
+      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'], subscribe: ['chat', 'heartbeat'] }));
     };
 
     ws.onmessage = (evt) => {
@@ -319,7 +319,7 @@ const Agents: React.FC<AgentsProps> = ({ language }) => {
     if (!crudName.trim()) return;
     setCrudBusy(true); setCrudError(null);
     try {
-      await gwApi.proxy('agents.create', {
+      await gwApi.agentsCreate({
         name: crudName.trim(),
         workspace: crudWorkspace.trim() || undefined,
         emoji: crudEmoji.trim() || undefined,
@@ -336,8 +336,7 @@ const Agents: React.FC<AgentsProps> = ({ language }) => {
     if (!gwReady || crudBusy || !selectedId) return;
     setCrudBusy(true); setCrudError(null);
     try {
-      await gwApi.proxy('agents.update', {
-        agentId: selectedId,
+      await gwApi.agentsUpdate(selectedId, {
         name: crudName.trim() || undefined,
         workspace: crudWorkspace.trim() || undefined,
         model: crudModel.trim() || undefined,
@@ -355,7 +354,7 @@ const Agents: React.FC<AgentsProps> = ({ language }) => {
     if (!gwReady || crudBusy || !selectedId) return;
     setCrudBusy(true); setCrudError(null);
     try {
-      await gwApi.proxy('agents.delete', { agentId: selectedId, deleteFiles });
+      await gwApi.agentsDelete(selectedId, deleteFiles);
       setDeleteConfirm(false);
       setSelectedId(null);
       loadAgents();
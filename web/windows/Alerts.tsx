@@ -106,7 +106,7 @@ const Alerts: React.FC<AlertsProps> = ({ language }) => {
       setWsConnecting(false);
       setWsError(null);
       // Subscribe to gw_event channel to receive gateway-forwarded events
-      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'] }));
+      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'], subscribe: ['exec.approval'] }));
     };
 
     ws.onmessage = (evt) => {
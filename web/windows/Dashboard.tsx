@@ -111,13 +111,13 @@ const Dashboard: React.FC<DashboardProps> = ({ language }) => {
   // O5: Fetch-in-progress guard — prevent overlapping fetches from visibility + interval
   const fetchingRef = useRef(false);
 
-  const fetchAll = useCallback(async () => {
+  const fetchAll = useCallback(async (fresh = false) => {
     if (fetchingRef.current) return;
     fetchingRef.current = true;
     setRefreshing(true);
     const settle = (p: Promise<any>) => p.catch(() => null);
     const [dashData, gwStatusData, sessData, modelsData, skillsData, agentsData, cronData, channelsData, costData, healthData, presenceData, hostData, gwCfgData] = await Promise.all([
-      settle(dashboardApi.get()),
+      settle(dashboardApi.get(fresh)),
       settle(gwApi.status()),
       settle(gwApi.sessions()),
       settle(gwApi.models()),
@@ -240,7 +240,7 @@ const Dashboard: React.FC<DashboardProps> = ({ language }) => {
           <h1 className="text-base font-bold dark:text-white/90 text-slate-800">{d.overview}</h1>
           {lastUpdate && <p className="text-[10px] text-slate-400 dark:text-white/35 mt-0.5">{d.lastUpdate}: {lastUpdate.toLocaleTimeString()}</p>}
         </div>
-        <button onClick={fetchAll} disabled={loading} className="p-1.5 rounded-lg text-slate-400 hover:text-primary hover:bg-primary/5 transition-all disabled:opacity-40">
+        <button onClick={() => fetchAll(true)} disabled={loading} className="p-1.5 rounded-lg text-slate-400 hover:text-primary hover:bg-primary/5 transition-all disabled:opacity-40">
           <span className={`material-symbols-outlined text-[18px] ${loading ? 'animate-spin' : ''}`}>refresh</span>
         </button>
       </div>
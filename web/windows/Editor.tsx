@@ -37,8 +37,8 @@ const Editor: React.FC<EditorProps> = ({ language }) => {
         await gwApi.configSetAll(parsed);
         await gwApi.configReload().catch(() => {});
       } catch {
+        // the server applies the saved file (reload or restart) itself
         await configApi.update(parsed);
-        await gwApi.configReload().catch(() => {});
       }
     } catch {}
   }, [jsonContent]);
@@ -333,15 +333,13 @@ export const ChannelsSection: React.FC<SectionProps> = ({ config, setField, getF
     const dmPolicy = getField(['channels', chId, 'dmPolicy']) || 'pairing';
     setRestarting(true);
     try {
-      // First save the configuration
+      // Saving applies the change itself (hot reload, or restart when required)
       if (save) {
         const saved = await save();
         if (!saved) {
-          console.error('Failed to save config before restart');
+          console.error('Failed to save config');
         }
       }
-      // Then restart the gateway
-      await gatewayApi.restart();
     } catch (err) {
       console.error('Failed to finish wizard:', err);
     }
@@ -101,6 +101,8 @@ export function useConfigEditor(): UseConfigEditorReturn {
   const [loadError, setLoadError] = useState('');
   const [loadErrorCode, setLoadErrorCode] = useState('');
   const baseHashRef = useRef<string | null>(null);
+  // 最近一次加载/保存的配置，WS 保存后交给服务端判定热加载还是重启
+  const savedConfigRef = useRef<Record<string, any> | null>(null);
 
   // undo/redo history
   const historyRef = useRef<string[]>([]);
@@ -171,6 +173,7 @@ export function useConfigEditor(): UseConfigEditorReturn {
       const cfg = extractConfig(data);
       if (cfg) {
         setConfig(cfg);
+        savedConfigRef.current = deepClone(cfg);
         setDirty(false);
         setErrors([]);
         historyRef.current = [JSON.stringify(cfg)];
@@ -193,32 +196,30 @@ export function useConfigEditor(): UseConfigEditorReturn {
     setSaving(true);
     setSaveError('');
     try {
-      const raw = JSON.stringify(config, null, 2);
-      // 统一优先走 WebSocket 保存（本地/远程网关均适用）
-      if (baseHashRef.current) {
-        // 有 hash → 用 configApply（原子写入+重载）
-        const res: any = await gwApi.configApply(raw, baseHashRef.current);
-        if (res?.config) setConfig(res.config);
-        const freshData: any = await gwApi.configGet().catch(() => null);
-        if (freshData?.hash) baseHashRef.current = freshData.hash;
-      } else {
-        // 无 hash → 尝试 configSetAll + reload，失败时降级本地写入
-        try {
-          await gwApi.configSetAll(config);
-          await gwApi.configReload().catch(() => {});
-          // 刷新 hash 以便后续保存走 configApply
-          const freshData: any = await gwApi.configGet().catch(() => null);
-          if (freshData?.hash) baseHashRef.current = freshData.hash;
-        } catch {
-          // WS 不可用，降级本地写入
-          if (mode === 'local') {
-            await configApi.update(config);
-            await gwApi.configReload().catch(() => {});
-          } else {
-            throw new Error('Gateway not connected');
-          }
+      // 统一优先走 WebSocket 写入（本地/远程网关均适用）；
+      // 生效方式（热加载/重启）由服务端按与本地保存相同的规则判定
+      const conflict = new Error('Config changed on the gateway since it was loaded; reload before saving');
+      let viaGateway = false;
+      try {
+        if (baseHashRef.current) {
+          const current: any = await gwApi.configGet();
+          if (current?.hash && current.hash !== baseHashRef.current) throw conflict;
         }
+        await gwApi.configSetAll(config);
+        viaGateway = true;
+      } catch (e) {
+        if (e === conflict) throw e;
+        // WS 不可用，降级本地写入（服务端写入后自行热加载或重启）
+        if (mode !== 'local') throw new Error('Gateway not connected');
+        await configApi.update(config);
+      }
+      if (viaGateway) {
+        await configApi.applyChange(savedConfigRef.current || {}, config);
+        // 刷新 hash 供下次保存做冲突检测
+        const freshData: any = await gwApi.configGet().catch(() => null);
+        baseHashRef.current = freshData?.hash || null;
       }
+      savedConfigRef.current = deepClone(config);
       setDirty(false);
       return true;
     } catch (e: any) {
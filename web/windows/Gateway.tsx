@@ -521,6 +521,22 @@ const Gateway: React.FC<GatewayProps> = ({ language }) => {
           )}
         </div>
 
+        {/* 维护模式：心跳自动重启、告警、通知与采集已暂停 */}
+        {status?.maintenance && (
+          <div className="flex items-start gap-1.5 px-2.5 py-1.5 rounded-lg bg-mac-yellow/10 border border-mac-yellow/30 text-[11px] text-mac-yellow">
+            <span className="material-symbols-outlined text-[14px]">construction</span>
+            <span>{gw.maintenanceOn || 'Maintenance mode is on: health-check restarts, alerts, notifications and event collection are paused.'}</span>
+          </div>
+        )}
+
+        {/* 激活档案 token 与 openclaw.json 不一致 */}
+        {status?.tokenDrift?.drifted && (
+          <div className="flex items-start gap-1.5 px-2.5 py-1.5 rounded-lg bg-mac-yellow/10 border border-mac-yellow/30 text-[11px] text-mac-yellow">
+            <span className="material-symbols-outlined text-[14px]">key_off</span>
+            <span>{(gw.tokenDrift || 'Token in profile "{name}" differs from {source}; reconnects may fail to authenticate.').replace('{name}', status.tokenDrift.profileName || '').replace('{source}', status.tokenDrift.source || 'openclaw.json')}</span>
+          </div>
+        )}
+
         {/* Row 2: 操作按钮 — 单行紧凑 */}
         {(() => {
           const remote = activeProfile ? !isLocal(activeProfile.host) : false;
@@ -583,13 +599,14 @@ const Gateway: React.FC<GatewayProps> = ({ language }) => {
                     item.status === 'fail' ? 'bg-red-50 dark:bg-red-500/5' :
                     item.status === 'warn' ? 'bg-amber-50 dark:bg-amber-500/5' :
                     'bg-slate-50 dark:bg-white/[0.02]'
-                  }`}>
+                  } ${item.status === 'skip' ? 'opacity-60' : ''}`}>
                     <span className={`material-symbols-outlined text-[16px] mt-0.5 shrink-0 ${
                       item.status === 'pass' ? 'text-mac-green' :
                       item.status === 'warn' ? 'text-amber-500' :
+                      item.status === 'skip' ? 'text-slate-400' :
                       'text-mac-red'
                     }`}>
-                      {item.status === 'pass' ? 'check_circle' : item.status === 'warn' ? 'warning' : 'cancel'}
+                      {item.status === 'pass' ? 'check_circle' : item.status === 'warn' ? 'warning' : item.status === 'skip' ? 'do_not_disturb_on' : 'cancel'}
                     </span>
                     <div className="flex-1 min-w-0">
                       <div className="flex items-center gap-2">
@@ -210,7 +210,7 @@ const Sessions: React.FC<SessionsProps> = ({ language, pendingSessionKey, onSess
       setWsConnecting(false);
       setWsError(null);
       // Subscribe to gw_event channel for chat streaming events
-      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'] }));
+      ws.send(JSON.stringify({ action: 'subscribe', channels: ['gw_event'], subscribe: ['chat', 'talk.mode'] }));
     };
 
     ws.onmessage = (evt) => {
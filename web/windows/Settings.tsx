@@ -213,7 +213,7 @@ const Settings: React.FC<SettingsProps> = ({ language }) => {
     try {
       const res = await selfUpdateApi.check();
       setSelfUpdateInfo(res);
-    } catch { setSelfUpdateInfo({ available: false, error: 'Network error' }); }
+    } catch (err: any) { setSelfUpdateInfo({ available: false, error: err?.message || 'Network error' }); }
     setSelfUpdateChecking(false);
   }, []);
 
@@ -830,6 +830,35 @@ const Settings: React.FC<SettingsProps> = ({ language }) => {
                 </div>
               </div>
 
+              {/* 告警按风险等级路由 */}
+              <div>
+                <label className={labelCls}>{s.notifyAlertRouting}</label>
+                <input type="text" value={notifyCfg.notify_alert_routing || ''} onChange={e => setNf('notify_alert_routing', e.target.value)}
+                  className={`${inputCls} font-mono text-[11px]`} placeholder="critical,high=telegram;medium=webhook;low=none" />
+                <p className="text-[10px] text-slate-400 dark:text-white/20 mt-1">{s.notifyAlertRoutingHint}</p>
+              </div>
+
+              {/* 免打扰时段（OPENCLAW_TIMEZONE 时区，仅 critical 告警照常发送） */}
+              <div className="grid grid-cols-3 gap-3">
+                <div>
+                  <label className={labelCls}>{s.notifyQuietStart}</label>
+                  <input type="time" value={notifyCfg.notify_quiet_hours_start || ''} onChange={e => setNf('notify_quiet_hours_start', e.target.value)}
+                    className={inputCls} />
+                </div>
+                <div>
+                  <label className={labelCls}>{s.notifyQuietEnd}</label>
+                  <input type="time" value={notifyCfg.notify_quiet_hours_end || ''} onChange={e => setNf('notify_quiet_hours_end', e.target.value)}
+                    className={inputCls} />
+                </div>
+                <div>
+                  <label className={labelCls}>{s.notifyQuietMode}</label>
+                  <CustomSelect value={notifyCfg.notify_quiet_hours_mode || 'drop'} onChange={v => setNf('notify_quiet_hours_mode', v)}
+                    options={[{ value: 'drop', label: s.notifyQuietDrop }, { value: 'queue', label: s.notifyQuietQueue }]}
+                    className={inputCls} />
+                </div>
+              </div>
+              <p className="text-[10px] text-slate-400 dark:text-white/20 -mt-2">{s.notifyQuietHint}</p>
+
               {/* Save button at bottom */}
               <div className="flex justify-end pt-2">
                 <button onClick={handleNotifySave} disabled={notifySaving || !notifyDirty}
@@ -57,9 +57,15 @@ interface SetupEvent {
   step?: string;
   message: string;
   progress?: number;
+  stream?: 'stdout' | 'stderr';
   data?: any;
 }
 
+interface LogLine {
+  text: string;
+  stderr?: boolean;
+}
+
 const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEditor, onOpenUsageWizard }) => {
   const t = useMemo(() => getTranslation(language), [language]);
   const sw = (t as any).sw || {};
@@ -68,7 +74,8 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
   const [scanResult, setScanResult] = useState<EnvironmentReport | null>(null);
   const [isScanning, setIsScanning] = useState(false);
   const [isInstalling, setIsInstalling] = useState(false);
-  const [logs, setLogs] = useState<string[]>([]);
+  const [logs, setLogs] = useState<LogLine[]>([]);
+  const installTaskRef = useRef<string | null>(null);
   const [progress, setProgress] = useState(0);
   const [error, setError] = useState<string | null>(null);
   const [currentStep, setCurrentStep] = useState('');
@@ -163,6 +170,7 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
       if (!response.ok) {
         throw new Error(sw.installFailed || 'Install failed');
       }
+      installTaskRef.current = response.headers.get('X-Install-Task-Id');
 
       const reader = response.body?.getReader();
       if (!reader) {
@@ -186,7 +194,7 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
               const event: SetupEvent = JSON.parse(line.slice(6));
 
               if (event.type === 'log') {
-                setLogs(prev => [...prev.slice(-100), event.message]);
+                setLogs(prev => [...prev.slice(-100), { text: event.message, stderr: event.stream === 'stderr' }]);
               } else if (event.type === 'phase') {
                 setCurrentStep(event.message);
                 setProgress(event.progress || 0);
@@ -196,7 +204,9 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
               } else if (event.type === 'progress') {
                 setProgress(event.progress || 0);
               } else if (event.type === 'error') {
-                setError(event.message);
+                // 附带失败命令最后几行 stderr，便于排查
+                const tail: string[] = event.data?.stderrTail || [];
+                setError(tail.length ? `${event.message}\n${tail.join('\n')}` : event.message);
                 setIsInstalling(false);
                 return;
               } else if (event.type === 'complete') {
@@ -218,7 +228,7 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
                   setPhase('complete');
                 }
                 // 重新扫描环境以获取安装后的版本号等信息
-                setLogs(prev => [...prev, `\n🔍 ${sw.runningDiagnostics || '正在全面诊断中...'}`]);
+                setLogs(prev => [...prev, { text: `\n🔍 ${sw.runningDiagnostics || '正在全面诊断中...'}` }]);
                 scanEnvironment();
               }
             } catch { }
@@ -228,10 +238,19 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
     } catch (err: any) {
       setError(err.message || sw.installFailed);
     } finally {
+      installTaskRef.current = null;
       setIsInstalling(false);
     }
   }, [selectedRegistry, installZeroTier, zerotierNetworkId, installTailscale]);
 
+  const cancelInstall = useCallback(async () => {
+    const taskId = installTaskRef.current;
+    if (!taskId) return;
+    try {
+      await post('/api/v1/setup/cancel-install', { taskId });
+    } catch { }
+  }, []);
+
 
 
   // 网关启动轮询
@@ -267,7 +286,7 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
         if (data.running) {
           clearInterval(interval);
           // 添加明显的诊断提示
-          setLogs(prev => [...prev, `\n⏳ ${sw.runningFullDiagnostics || '正在进行全面诊断，请稍等...'}`]);
+          setLogs(prev => [...prev, { text: `\n⏳ ${sw.runningFullDiagnostics || '正在进行全面诊断，请稍等...'}` }]);
           scanEnvironment();
           setPhase('complete');
         }
@@ -313,7 +332,7 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
         {/* 错误提示 */}
         {error && (
           <div className="mb-6 p-4 bg-red-50 dark:bg-red-500/10 border border-red-200 dark:border-red-500/30 rounded-xl">
-            <p className="text-sm text-red-600 dark:text-red-400">{error}</p>
+            <p className="text-sm text-red-600 dark:text-red-400 whitespace-pre-wrap">{error}</p>
           </div>
         )}
 
@@ -788,6 +807,14 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
             <h3 className="text-sm font-bold text-slate-800 dark:text-white/80 mb-4 flex items-center gap-2">
               <span className="material-symbols-outlined text-primary animate-pulse">download</span>
               {sw.installing}
+              {isInstalling && (
+                <button
+                  onClick={cancelInstall}
+                  className="ml-auto px-2.5 py-1 rounded-lg text-[11px] font-bold text-red-500 border border-red-200 dark:border-red-500/30 hover:bg-red-50 dark:hover:bg-red-500/10 transition-colors"
+                >
+                  {sw.cancelInstall}
+                </button>
+              )}
             </h3>
 
             {/* 进度条 */}
@@ -814,7 +841,7 @@ const SetupWizard: React.FC<SetupWizardProps> = ({ language, onClose, onOpenEdit
             {/* 日志输出 */}
             <div className="h-48 overflow-y-auto bg-slate-900 dark:bg-black/50 rounded-lg p-3 font-mono text-xs text-green-400 custom-scrollbar">
               {logs.map((log, i) => (
-                <div key={i} className="whitespace-pre-wrap">{log}</div>
+                <div key={i} className={`whitespace-pre-wrap ${log.stderr ? 'text-red-400' : ''}`}>{log.text}</div>
               ))}
               {logs.length === 0 && (
                 <div className="text-slate-500">{sw.waitingOutput}</div>
@@ -4,6 +4,7 @@ import { Language } from '../types';
 import { getTranslation } from '../locales';
 import { gwApi, clawHubApi, skillTranslationApi } from '../services/api';
 import { useToast } from '../components/Toast';
+import { useConfirm } from '../components/ConfirmDialog';
 
 interface SkillsProps { language: Language; }
 
@@ -327,6 +328,7 @@ const Skills: React.FC<SkillsProps> = ({ language }) => {
   const t = useMemo(() => getTranslation(language), [language]);
   const sk = t.sk as any;
   const { toast } = useToast();
+  const { confirm } = useConfirm();
 
   const [activeTab, setActiveTab] = useState<TabId>('all');
   const [filter, setFilter] = useState<FilterId>('all');
@@ -559,8 +561,32 @@ const Skills: React.FC<SkillsProps> = ({ language }) => {
     }).catch(() => { /* fallback: ignore */ });
   }, [sk, toast]);
 
+  // 安装前预检技能运行时依赖，必需依赖缺失时提示用户（预检失败不阻塞安装）
+  const confirmMarketRequirements = useCallback(async (slug: string): Promise<boolean> => {
+    let req: any;
+    try {
+      req = await clawHubApi.requirements(slug);
+    } catch {
+      return true;
+    }
+    if (!req?.checked || !req.missing?.length) return true;
+    const lines = req.missing.map((m: any) =>
+      `• ${m.label || m.name}${m.installCommand ? `: ${m.installCommand}` : ''}`);
+    if (!req.hardMissing) {
+      toast('warning', `${sk.depsOptionalMissing}: ${req.missing.map((m: any) => m.name).join(', ')}`);
+      return true;
+    }
+    return confirm({
+      title: sk.depsMissingTitle,
+      message: `${sk.depsMissingDesc}\n${lines.join('\n')}`,
+      confirmText: sk.installAnyway,
+      danger: true,
+    });
+  }, [sk, toast, confirm]);
+
   // 一键发送市场技能安装信息给代理
   const handleSendMarketInstall = useCallback(async (item: any) => {
+    if (!(await confirmMarketRequirements(item.slug || item.name || ''))) return;
     const prompt = buildMarketInstallPrompt(item, sk);
     try {
       await gwApi.proxy('agent', { message: prompt });
@@ -568,7 +594,7 @@ const Skills: React.FC<SkillsProps> = ({ language }) => {
     } catch (err: any) {
       toast('error', (sk.sendFailed || 'Failed') + ': ' + (err?.message || ''));
     }
-  }, [sk, toast]);
+  }, [sk, toast, confirmMarketRequirements]);
 
   // 过滤技能
   const filteredSkills = useMemo(() => {